@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
 	"os"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -26,9 +27,18 @@ import (
 	"github.com/kong/gateway-operator/modules/manager"
 	"github.com/kong/gateway-operator/modules/manager/metadata"
 	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/modules/render"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == render.Command {
+		if err := render.Run(os.Args[2:], scheme.Get(), os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: failed to render resources: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	m := metadata.Metadata()
 
 	cli := cli.New(m)
@@ -0,0 +1,337 @@
+package backendtlspolicy
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kong/gateway-operator/controller/pkg/backendtls"
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/pkg/consts"
+	"github.com/kong/gateway-operator/pkg/vars"
+)
+
+// maxPolicyAncestors is the maximum number of ancestors a policy status can hold
+// as defined by the Gateway API.
+const maxPolicyAncestors = 16
+
+// Reconciler reconciles a BackendTLSPolicy object.
+// It sets the policy ancestors status for the Gateways managed by the operator
+// which route traffic to the Services targeted by the policy, and configures the
+// upstream TLS of these Services with Kong Ingress Controller annotations.
+type Reconciler struct {
+	client.Client
+	LoggingMode logging.Mode
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(_ context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1alpha3.BackendTLSPolicy{}).
+		Watches(
+			&gwtypes.HTTPRoute{},
+			handler.EnqueueRequestsFromMapFunc(r.listPoliciesForHTTPRoute),
+		).
+		Watches(
+			&gwtypes.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(r.listPoliciesForGateway),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.listPoliciesForCACertificate),
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.listPoliciesForCACertificate),
+		).
+		Watches(
+			&gatewayv1beta1.ReferenceGrant{},
+			handler.EnqueueRequestsFromMapFunc(r.listPoliciesForReferenceGrant),
+		).
+		Watches(
+			&corev1.Service{},
+			handler.EnqueueRequestsFromMapFunc(r.listPoliciesForService),
+		).
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
+
+// Reconcile moves the current state of an object to the intended state.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.GetLogger(ctx, "backendtlspolicy", r.LoggingMode)
+
+	log.Trace(logger, "reconciling BackendTLSPolicy resource")
+	var policy gatewayv1alpha3.BackendTLSPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		if k8serrors.IsNotFound(err) {
+			// The CA certificate Secrets owned by the policy are garbage collected.
+			log.Debug(logger, "BackendTLSPolicy deleted, removing the TLS configuration of its Services")
+			return ctrl.Result{}, r.ensureServicesTLS(ctx, nil, req.Namespace, req.Name, nil)
+		}
+		return ctrl.Result{}, err
+	}
+
+	gateways, err := r.listManagedGatewaysForPolicy(ctx, &policy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	oldPolicy := policy.DeepCopy()
+	// Keep the ancestors set by other controllers and drop the ones which
+	// are not relevant anymore.
+	ancestors := slices.DeleteFunc(slices.Clone(policy.Status.Ancestors), func(a gatewayv1alpha2.PolicyAncestorStatus) bool {
+		return a.ControllerName == ancestorControllerName() &&
+			!slices.ContainsFunc(gateways, func(gw gwtypes.Gateway) bool { return ancestorRefIsGateway(a.AncestorRef, &gw) })
+	})
+	var (
+		accepted       bool
+		caCertificates [][]byte
+	)
+	for i := range gateways {
+		gateway := &gateways[i]
+		res, err := backendtls.ResolveCACertificates(ctx, r.Client, &policy, gateway)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if res.Accepted() {
+			accepted = true
+			caCertificates = append(caCertificates, res.CACertificates...)
+		}
+
+		idx := slices.IndexFunc(ancestors, func(a gatewayv1alpha2.PolicyAncestorStatus) bool {
+			return a.ControllerName == ancestorControllerName() && ancestorRefIsGateway(a.AncestorRef, gateway)
+		})
+		if idx == -1 {
+			if len(ancestors) >= maxPolicyAncestors {
+				log.Debug(logger, "maximum number of BackendTLSPolicy ancestors reached, skipping Gateway", "gateway", client.ObjectKeyFromObject(gateway))
+				continue
+			}
+			ancestors = append(ancestors, gatewayv1alpha2.PolicyAncestorStatus{
+				AncestorRef:    gatewayAncestorRef(gateway),
+				ControllerName: ancestorControllerName(),
+			})
+			idx = len(ancestors) - 1
+		}
+		for _, cond := range res.Conditions(policy.Generation) {
+			meta.SetStatusCondition(&ancestors[idx].Conditions, cond)
+		}
+	}
+	policy.Status.Ancestors = ancestors
+
+	// The upstream TLS is configured on the targeted Services rather than on
+	// the DataPlanes, so that the CA certificates are only trusted, and the
+	// certificates only verified, for the Services targeted by the policy.
+	var tls *serviceTLS
+	caSecrets, err := r.ensureCACertificateSecrets(ctx, &policy, caCertificates)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if accepted {
+		tls = &serviceTLS{
+			hostname:  string(policy.Spec.Validation.Hostname),
+			caSecrets: caSecrets,
+		}
+	}
+	if err := r.ensureServicesTLS(ctx, &policy, policy.Namespace, policy.Name, tls); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if reflect.DeepEqual(oldPolicy.Status, policy.Status) {
+		log.Trace(logger, "BackendTLSPolicy status up to date")
+		return ctrl.Result{}, nil
+	}
+	if err := r.Status().Patch(ctx, &policy, client.MergeFrom(oldPolicy)); err != nil {
+		if k8serrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed patching BackendTLSPolicy status: %w", err)
+	}
+	log.Debug(logger, "BackendTLSPolicy status updated")
+
+	return ctrl.Result{}, nil
+}
+
+// listManagedGatewaysForPolicy returns the Gateways affected by the BackendTLSPolicy
+// which have a GatewayClass managed by the operator.
+func (r *Reconciler) listManagedGatewaysForPolicy(
+	ctx context.Context, policy *gatewayv1alpha3.BackendTLSPolicy,
+) ([]gwtypes.Gateway, error) {
+	gatewayNNs, err := backendtls.ListGatewaysForPolicy(ctx, r.Client, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	var gateways []gwtypes.Gateway
+	for _, nn := range gatewayNNs {
+		var gateway gwtypes.Gateway
+		if err := r.Get(ctx, nn, &gateway); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		var gatewayClass gatewayv1.GatewayClass
+		if err := r.Get(ctx, client.ObjectKey{Name: string(gateway.Spec.GatewayClassName)}, &gatewayClass); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if string(gatewayClass.Spec.ControllerName) != vars.ControllerName() {
+			continue
+		}
+		gateways = append(gateways, gateway)
+	}
+	return gateways, nil
+}
+
+// ancestorControllerName returns the controller name of the policy ancestors
+// set by the operator. It differs from the controller name of the operator's
+// GatewayClasses which is also used by the ControlPlanes of the Gateways when
+// they report the status of the policies.
+func ancestorControllerName() gatewayv1.GatewayController {
+	return gatewayv1.GatewayController(vars.ControllerName() + "/backendtlspolicy")
+}
+
+func gatewayAncestorRef(gateway *gwtypes.Gateway) gatewayv1.ParentReference {
+	return gatewayv1.ParentReference{
+		Group:     lo.ToPtr(gatewayv1.Group(gatewayv1.GroupName)),
+		Kind:      lo.ToPtr(gatewayv1.Kind("Gateway")),
+		Namespace: lo.ToPtr(gatewayv1.Namespace(gateway.Namespace)),
+		Name:      gatewayv1.ObjectName(gateway.Name),
+	}
+}
+
+func ancestorRefIsGateway(ref gatewayv1.ParentReference, gateway *gwtypes.Gateway) bool {
+	return (ref.Group == nil || *ref.Group == gatewayv1.GroupName) &&
+		(ref.Kind == nil || *ref.Kind == "Gateway") &&
+		ref.Namespace != nil && string(*ref.Namespace) == gateway.Namespace &&
+		string(ref.Name) == gateway.Name
+}
+
+// -----------------------------------------------------------------------------
+// Reconciler - Watch Map Funcs
+// -----------------------------------------------------------------------------
+
+func (r *Reconciler) listPolicies(ctx context.Context, opts ...client.ListOption) []gatewayv1alpha3.BackendTLSPolicy {
+	var policies gatewayv1alpha3.BackendTLSPolicyList
+	if err := r.List(ctx, &policies, opts...); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "failed to list BackendTLSPolicies")
+		return nil
+	}
+	return policies.Items
+}
+
+func policiesToRequests(policies []gatewayv1alpha3.BackendTLSPolicy, filter func(*gatewayv1alpha3.BackendTLSPolicy) bool) []reconcile.Request {
+	var requests []reconcile.Request
+	for i := range policies {
+		if filter(&policies[i]) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&policies[i]),
+			})
+		}
+	}
+	return requests
+}
+
+func (r *Reconciler) listPoliciesForHTTPRoute(ctx context.Context, obj client.Object) []reconcile.Request {
+	route, ok := obj.(*gwtypes.HTTPRoute)
+	if !ok {
+		return nil
+	}
+	var services []types.NamespacedName
+	for _, rule := range route.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			nn := types.NamespacedName{Namespace: route.Namespace, Name: string(backendRef.Name)}
+			if backendRef.Namespace != nil {
+				nn.Namespace = string(*backendRef.Namespace)
+			}
+			services = append(services, nn)
+		}
+	}
+	if len(services) == 0 {
+		return nil
+	}
+	// Policies of Services which have been removed from the HTTPRoute are
+	// updated when the corresponding Gateways are reconciled.
+	return policiesToRequests(r.listPolicies(ctx), func(policy *gatewayv1alpha3.BackendTLSPolicy) bool {
+		return slices.ContainsFunc(services, func(nn types.NamespacedName) bool {
+			return backendtls.PolicyTargetsService(policy, nn.Namespace, nn.Name)
+		})
+	})
+}
+
+func (r *Reconciler) listPoliciesForService(ctx context.Context, obj client.Object) []reconcile.Request {
+	requests := policiesToRequests(
+		r.listPolicies(ctx, client.InNamespace(obj.GetNamespace())),
+		func(policy *gatewayv1alpha3.BackendTLSPolicy) bool {
+			return backendtls.PolicyTargetsService(policy, obj.GetNamespace(), obj.GetName())
+		},
+	)
+	// The policy which configured the Service may not target it anymore.
+	if name, ok := obj.GetAnnotations()[consts.AnnotationBackendTLSPolicy]; ok {
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}
+		if !slices.Contains(requests, req) {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+func (r *Reconciler) listPoliciesForGateway(ctx context.Context, obj client.Object) []reconcile.Request {
+	gateway, ok := obj.(*gwtypes.Gateway)
+	if !ok {
+		return nil
+	}
+	policies, err := backendtls.ListPoliciesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		ctrllog.FromContext(ctx).Error(err, "failed to list BackendTLSPolicies for Gateway")
+		return nil
+	}
+	return policiesToRequests(policies, func(*gatewayv1alpha3.BackendTLSPolicy) bool { return true })
+}
+
+func (r *Reconciler) listPoliciesForCACertificate(ctx context.Context, obj client.Object) []reconcile.Request {
+	return policiesToRequests(
+		r.listPolicies(ctx, client.InNamespace(obj.GetNamespace())),
+		func(policy *gatewayv1alpha3.BackendTLSPolicy) bool {
+			return backendtls.PolicyReferencesCACertificate(policy, obj)
+		},
+	)
+}
+
+func (r *Reconciler) listPoliciesForReferenceGrant(ctx context.Context, obj client.Object) []reconcile.Request {
+	grant, ok := obj.(*gatewayv1beta1.ReferenceGrant)
+	if !ok {
+		return nil
+	}
+	if !slices.ContainsFunc(grant.Spec.From, func(from gatewayv1beta1.ReferenceGrantFrom) bool {
+		return from.Group == gatewayv1.GroupName && from.Kind == "Gateway"
+	}) {
+		return nil
+	}
+	return policiesToRequests(
+		r.listPolicies(ctx, client.InNamespace(grant.Namespace)),
+		func(policy *gatewayv1alpha3.BackendTLSPolicy) bool {
+			return len(policy.Spec.Validation.CACertificateRefs) > 0
+		},
+	)
+}
@@ -0,0 +1,233 @@
+package backendtlspolicy
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+
+	"github.com/kong/gateway-operator/controller/pkg/backendtls"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	"github.com/kong/gateway-operator/pkg/vars"
+	"github.com/kong/gateway-operator/test/helpers"
+)
+
+func TestReconcile(t *testing.T) {
+	gatewayClass := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+		Spec: gatewayv1.GatewayClassSpec{
+			ControllerName: gatewayv1.GatewayController(vars.ControllerName()),
+		},
+	}
+	otherGatewayClass := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec: gatewayv1.GatewayClassSpec{
+			ControllerName: "example.com/other",
+		},
+	}
+	gateway := &gwtypes.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gateway"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "kong"},
+	}
+	otherGateway := &gwtypes.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "other"},
+	}
+	route := &gwtypes.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}, {Name: "other"}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: "backend",
+						}}},
+					},
+				},
+			},
+		},
+	}
+	policy := &gatewayv1alpha3.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "policy", Generation: 2},
+		Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{
+				{LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{Kind: "Service", Name: "backend"}},
+			},
+			Validation: gatewayv1alpha3.BackendTLSPolicyValidation{
+				CACertificateRefs: []gatewayv1.LocalObjectReference{{Kind: "ConfigMap", Name: "missing"}},
+				Hostname:          "backend.example.com",
+			},
+		},
+		Status: gatewayv1alpha2.PolicyStatus{
+			Ancestors: []gatewayv1alpha2.PolicyAncestorStatus{
+				{
+					// Ancestor set by another controller has to be kept.
+					AncestorRef:    gatewayAncestorRef(otherGateway),
+					ControllerName: "example.com/other",
+				},
+				{
+					// Ancestor set by the ControlPlanes has to be kept.
+					AncestorRef:    gatewayAncestorRef(gateway),
+					ControllerName: gatewayv1.GatewayController(vars.ControllerName()),
+				},
+				{
+					// Stale ancestor set by the operator has to be dropped.
+					AncestorRef: gatewayAncestorRef(&gwtypes.Gateway{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "deleted"},
+					}),
+					ControllerName: ancestorControllerName(),
+				},
+			},
+		},
+	}
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(gatewayClass, otherGatewayClass, gateway, otherGateway, route, policy).
+		WithStatusSubresource(policy).
+		Build()
+	r := &Reconciler{Client: cl}
+
+	_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)})
+	require.NoError(t, err)
+
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(policy), policy))
+	require.Len(t, policy.Status.Ancestors, 3)
+	require.Equal(t, gatewayv1.GatewayController("example.com/other"), policy.Status.Ancestors[0].ControllerName)
+	require.Equal(t, gatewayv1.GatewayController(vars.ControllerName()), policy.Status.Ancestors[1].ControllerName)
+
+	ancestor := policy.Status.Ancestors[2]
+	require.Equal(t, ancestorControllerName(), ancestor.ControllerName)
+	require.Equal(t, gatewayv1.ObjectName("gateway"), ancestor.AncestorRef.Name)
+
+	accepted := meta.FindStatusCondition(ancestor.Conditions, string(gatewayv1alpha2.PolicyConditionAccepted))
+	require.NotNil(t, accepted)
+	require.Equal(t, metav1.ConditionFalse, accepted.Status)
+	require.Equal(t, string(backendtls.PolicyReasonNoValidCACertificate), accepted.Reason)
+	require.Equal(t, int64(2), accepted.ObservedGeneration)
+
+	resolvedRefs := meta.FindStatusCondition(ancestor.Conditions, string(backendtls.PolicyConditionResolvedRefs))
+	require.NotNil(t, resolvedRefs)
+	require.Equal(t, metav1.ConditionFalse, resolvedRefs.Status)
+	require.Equal(t, string(backendtls.PolicyReasonInvalidCACertificateRef), resolvedRefs.Reason)
+}
+
+func TestReconcileServicesTLS(t *testing.T) {
+	gatewayClass := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+		Spec: gatewayv1.GatewayClassSpec{
+			ControllerName: gatewayv1.GatewayController(vars.ControllerName()),
+		},
+	}
+	gateway := &gwtypes.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gateway"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "kong"},
+	}
+	route := &gwtypes.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: "backend",
+						}}},
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: "other",
+						}}},
+					},
+				},
+			},
+		},
+	}
+	caConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ca"},
+		Data: map[string]string{
+			consts.CACRT: helpers.CreateCA(t).CertPEM.String() + helpers.CreateCA(t).CertPEM.String(),
+		},
+	}
+	backend := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "backend",
+			Annotations: map[string]string{"example.com/user": "value"},
+		},
+	}
+	other := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"},
+	}
+	policy := &gatewayv1alpha3.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "policy", UID: "policy-uid"},
+		Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{
+				{LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{Kind: "Service", Name: "backend"}},
+			},
+			Validation: gatewayv1alpha3.BackendTLSPolicyValidation{
+				CACertificateRefs: []gatewayv1.LocalObjectReference{{Kind: "ConfigMap", Name: "ca"}},
+				Hostname:          "backend.example.com",
+			},
+		},
+	}
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(gatewayClass, gateway, route, caConfigMap, backend, other, policy).
+		WithStatusSubresource(policy).
+		Build()
+	r := &Reconciler{Client: cl}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policy)}
+
+	t.Log("the targeted Service is configured to verify its certificate with the policy's CA certificates")
+	_, err := r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+
+	var secrets corev1.SecretList
+	require.NoError(t, cl.List(t.Context(), &secrets, client.MatchingLabels{consts.KongIngressControllerCACertLabel: "true"}))
+	require.Len(t, secrets.Items, 2, "each CA certificate of the bundle is held by its own Secret")
+	var secretNames []string
+	for _, secret := range secrets.Items {
+		require.NotEmpty(t, secret.Data["cert"])
+		require.NotEmpty(t, secret.Data["id"])
+		require.Equal(t, policy.UID, secret.OwnerReferences[0].UID)
+		secretNames = append(secretNames, secret.Name)
+	}
+	slices.Sort(secretNames)
+
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(backend), backend))
+	require.Equal(t, map[string]string{
+		"example.com/user":                                   "value",
+		consts.AnnotationBackendTLSPolicy:                    "policy",
+		consts.KongIngressControllerProtocolAnnotation:       "https",
+		consts.KongIngressControllerTLSVerifyAnnotation:      "true",
+		consts.KongIngressControllerHostHeaderAnnotation:     "backend.example.com",
+		consts.KongIngressControllerCACertificatesAnnotation: strings.Join(secretNames, ","),
+	}, backend.Annotations)
+
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(other), other))
+	require.Empty(t, other.Annotations, "Services not targeted by the policy must not be configured")
+
+	t.Log("the TLS configuration is removed from the Service once the policy is deleted")
+	require.NoError(t, cl.Delete(t.Context(), policy))
+	_, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(backend), backend))
+	require.Equal(t, map[string]string{"example.com/user": "value"}, backend.Annotations)
+}
@@ -0,0 +1,198 @@
+package backendtlspolicy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/pem"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+
+	"github.com/kong/gateway-operator/controller/pkg/backendtls"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+)
+
+// caCertificateSecretPrefix is the prefix of the names of the Secrets holding
+// the CA certificates of BackendTLSPolicies.
+const caCertificateSecretPrefix = "backend-tls-ca-"
+
+// serviceAnnotations are the annotations managed by the operator on the Services
+// targeted by BackendTLSPolicies.
+var serviceAnnotations = []string{
+	consts.AnnotationBackendTLSPolicy,
+	consts.KongIngressControllerProtocolAnnotation,
+	consts.KongIngressControllerTLSVerifyAnnotation,
+	consts.KongIngressControllerCACertificatesAnnotation,
+	consts.KongIngressControllerHostHeaderAnnotation,
+}
+
+// serviceTLS is the upstream TLS configuration applied to the Services targeted
+// by a BackendTLSPolicy.
+type serviceTLS struct {
+	// hostname is used as SNI and to verify the certificate of the Services.
+	hostname string
+	// caSecrets are the names of the Secrets holding the CA certificates used
+	// to verify the certificate of the Services, empty when the system CA
+	// certificates are used.
+	caSecrets []string
+}
+
+// annotations returns the annotations configuring the Services with the
+// Kong Ingress Controller.
+func (t *serviceTLS) annotations(policyName string) map[string]string {
+	annotations := map[string]string{
+		consts.AnnotationBackendTLSPolicy:                policyName,
+		consts.KongIngressControllerProtocolAnnotation:   "https",
+		consts.KongIngressControllerTLSVerifyAnnotation:  "true",
+		consts.KongIngressControllerHostHeaderAnnotation: t.hostname,
+	}
+	if len(t.caSecrets) > 0 {
+		annotations[consts.KongIngressControllerCACertificatesAnnotation] = strings.Join(t.caSecrets, ",")
+	}
+	return annotations
+}
+
+// ensureServicesTLS configures the upstream TLS of the Services targeted by the
+// BackendTLSPolicy with the provided name. When tls is nil, e.g. because the
+// policy is deleted or not accepted, the configuration is removed from the
+// Services it was applied to.
+// Services already configured by another BackendTLSPolicy are left untouched.
+func (r *Reconciler) ensureServicesTLS(
+	ctx context.Context,
+	policy *gatewayv1alpha3.BackendTLSPolicy,
+	namespace, name string,
+	tls *serviceTLS,
+) error {
+	var services corev1.ServiceList
+	if err := r.List(ctx, &services, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed listing Services: %w", err)
+	}
+
+	for i := range services.Items {
+		service := &services.Items[i]
+		owner, managed := service.Annotations[consts.AnnotationBackendTLSPolicy]
+		if managed && owner != name {
+			continue
+		}
+		targeted := tls != nil && backendtls.PolicyTargetsService(policy, service.Namespace, service.Name)
+		if !targeted && !managed {
+			continue
+		}
+
+		old := service.DeepCopy()
+		for _, key := range serviceAnnotations {
+			delete(service.Annotations, key)
+		}
+		if targeted {
+			if service.Annotations == nil {
+				service.Annotations = map[string]string{}
+			}
+			maps.Copy(service.Annotations, tls.annotations(name))
+		}
+		if maps.Equal(old.Annotations, service.Annotations) {
+			continue
+		}
+		if err := r.Patch(ctx, service, client.MergeFrom(old)); err != nil {
+			return fmt.Errorf("failed patching Service %s: %w", client.ObjectKeyFromObject(service), err)
+		}
+	}
+	return nil
+}
+
+// ensureCACertificateSecrets ensures that a Secret owned by the BackendTLSPolicy
+// holds each of the provided PEM encoded CA certificates, so that they're loaded
+// by the ControlPlanes as Kong CA certificates. Secrets of CA certificates which
+// are not provided anymore are deleted.
+// It returns the sorted names of the Secrets.
+func (r *Reconciler) ensureCACertificateSecrets(
+	ctx context.Context,
+	policy *gatewayv1alpha3.BackendTLSPolicy,
+	caCertificates [][]byte,
+) ([]string, error) {
+	desired := make(map[string][]byte)
+	for _, data := range caCertificates {
+		for _, cert := range splitPEMCertificates(data) {
+			desired[caCertificateSecretName(policy, cert)] = cert
+		}
+	}
+
+	secrets, err := k8sutils.ListSecretsForOwner(ctx, r.Client, policy.UID,
+		client.InNamespace(policy.Namespace),
+		client.MatchingLabels{consts.KongIngressControllerCACertLabel: "true"},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing BackendTLSPolicy CA certificate Secrets: %w", err)
+	}
+	existing := make(map[string]struct{}, len(secrets))
+	for i := range secrets {
+		if _, ok := desired[secrets[i].Name]; ok {
+			existing[secrets[i].Name] = struct{}{}
+			continue
+		}
+		if err := r.Delete(ctx, &secrets[i]); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed deleting BackendTLSPolicy CA certificate Secret %s: %w", secrets[i].Name, err)
+		}
+	}
+
+	// Policies fetched from the cache have no TypeMeta set, which is used to
+	// generate the owner reference.
+	owner := policy.DeepCopy()
+	owner.SetGroupVersionKind(gatewayv1alpha3.SchemeGroupVersion.WithKind("BackendTLSPolicy"))
+	for secretName, cert := range desired {
+		if _, ok := existing[secretName]; ok {
+			continue
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: policy.Namespace,
+				Name:      secretName,
+				Labels: map[string]string{
+					consts.KongIngressControllerCACertLabel: "true",
+				},
+				Annotations: map[string]string{
+					consts.KongIngressControllerIngressClassAnnotation: consts.KongIngressControllerDefaultIngressClass,
+				},
+			},
+			Data: map[string][]byte{
+				"cert": cert,
+				"id":   []byte(uuid.NewSHA1(uuid.NameSpaceOID, []byte(secretName)).String()),
+			},
+		}
+		k8sutils.SetOwnerForObject(secret, owner)
+		if err := r.Create(ctx, secret); err != nil {
+			return nil, fmt.Errorf("failed creating BackendTLSPolicy CA certificate Secret %s: %w", secretName, err)
+		}
+	}
+
+	return slices.Sorted(maps.Keys(desired)), nil
+}
+
+// caCertificateSecretName returns the name of the Secret holding the CA
+// certificate for the BackendTLSPolicy, derived from both of them so that
+// the Secrets of different policies don't collide.
+func caCertificateSecretName(policy *gatewayv1alpha3.BackendTLSPolicy, cert []byte) string {
+	sum := sha256.Sum256(append([]byte(policy.UID), cert...))
+	return fmt.Sprintf("%s%x", caCertificateSecretPrefix, sum[:8])
+}
+
+// splitPEMCertificates splits the PEM encoded certificates bundle, as Kong CA
+// certificates hold a single certificate each.
+func splitPEMCertificates(data []byte) [][]byte {
+	var certs [][]byte
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return certs
+		}
+		certs = append(certs, pem.EncodeToMemory(block))
+	}
+}
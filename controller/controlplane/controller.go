@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/samber/lo"
 	admregv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -15,6 +16,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,8 +33,11 @@ import (
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
 	extensionserrors "github.com/kong/gateway-operator/controller/pkg/extensions/errors"
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/maintenance"
 	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/controller/pkg/versionchannel"
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
 	"github.com/kong/gateway-operator/internal/utils/index"
 	"github.com/kong/gateway-operator/internal/versions"
@@ -53,6 +58,7 @@ type Reconciler struct {
 	client.Client
 	DiscoveryClient           *CachedDiscoveryClient
 	Scheme                    *runtime.Scheme
+	eventRecorder             record.EventRecorder
 	ClusterCASecretName       string
 	ClusterCASecretNamespace  string
 	ClusterCAKeyConfig        secrets.KeyConfig
@@ -61,10 +67,16 @@ type Reconciler struct {
 	LoggingMode               logging.Mode
 	ValidateControlPlaneImage bool
 	AnonymousReportsEnabled   bool
+	// VersionChannelResolver is used to resolve the images of the version
+	// channels tracked by ControlPlanes. The shared default Resolver, listing
+	// tags from image registries, is used when nil.
+	VersionChannelResolver *versionchannel.Resolver
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("controlplane")
+
 	// for owned objects we need to check if updates to the objects resulted in the
 	// removal of an OwnerReference to the parent object, and if so we need to
 	// enqueue the parent object so that reconciliation can create a replacement.
@@ -173,6 +185,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	log.Trace(logger, "checking if ControlPlane reconciliation is paused")
+	if paused, res, err := pause.Reconcile(ctx, r.Client, r.eventRecorder, cp, cp); err != nil || paused || !res.IsZero() {
+		if paused {
+			log.Debug(logger, "ControlPlane reconciliation is paused")
+		}
+		return res, err
+	}
+
 	// controlplane is deleted, just run garbage collection for cluster wide resources.
 	if !cp.DeletionTimestamp.IsZero() {
 		// wait for termination grace period before cleaning up roles and bindings
@@ -319,22 +339,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	log.Trace(logger, "configuring ControlPlane resource")
 
-	defaultArgs := controlplane.DefaultsArgs{
-		Namespace:                   cp.Namespace,
-		ControlPlaneName:            cp.Name,
-		DataPlaneIngressServiceName: dataplaneIngressServiceName,
-		DataPlaneAdminServiceName:   dataplaneAdminServiceName,
-		AnonymousReportsEnabled:     controlplane.DeduceAnonymousReportsEnabled(r.AnonymousReportsEnabled, &cp.Spec.ControlPlaneOptions),
-	}
-	for _, owner := range cp.OwnerReferences {
-		if strings.HasPrefix(owner.APIVersion, gatewayv1.GroupName) && owner.Kind == "Gateway" {
-			defaultArgs.OwnedByGateway = owner.Name
-			continue
-		}
-	}
 	_ = controlplane.SetDefaults(
 		&cp.Spec.ControlPlaneOptions,
-		defaultArgs)
+		defaultsArgsForControlPlane(cp, dataplaneIngressServiceName, dataplaneAdminServiceName, r.AnonymousReportsEnabled),
+	)
 	stop, result, err := extensions.ApplyExtensions(ctx, r.Client, cp, r.KonnectEnabled)
 	if err != nil {
 		if extensionserrors.IsKonnectExtensionError(err) {
@@ -425,12 +433,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
 	}
 
+	// changes rolling the ControlPlane's Pods are held until its maintenance window opens
+	holdPodTemplateChanges, maintenanceWindowOpensAt, err := maintenance.HoldChanges(cp, time.Now())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not evaluate maintenance window of ControlPlane: %w", err)
+	}
+
+	log.Trace(logger, "ensuring ControlPlane version channel image")
+	desiredControlPlane, versionChannelRes, err := r.ensureVersionChannelImage(ctx, cp)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not ensure version channel image of ControlPlane: %w", err)
+	}
+	if versionChannelRes.Requeue {
+		return versionChannelRes, nil
+	}
+
 	deploymentParams := ensureDeploymentParams{
-		ControlPlane:            cp,
+		ControlPlane:            desiredControlPlane,
 		ServiceAccountName:      controlplaneServiceAccount.Name,
 		AdminMTLSCertSecretName: adminCertificate.Name,
 		EnforceConfig:           r.EnforceConfig,
 		WatchNamespaces:         validatedWatchNamespaces,
+		HoldPodTemplateChanges:  holdPodTemplateChanges,
 	}
 
 	admissionWebhookCertificateSecretName, res, err := r.ensureWebhookResources(ctx, logger, cp, r.EnforceConfig)
@@ -442,7 +466,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	deploymentParams.AdmissionWebhookCertSecretName = admissionWebhookCertificateSecretName
 
 	log.Trace(logger, "looking for existing Deployments for ControlPlane resource")
-	res, controlplaneDeployment, err := r.ensureDeployment(ctx, logger, deploymentParams)
+	res, controlplaneDeployment, podTemplateChangesHeld, err := r.ensureDeployment(ctx, logger, deploymentParams)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -462,6 +486,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 		return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
 	}
+
+	// The ControlPlane is requeued for the next version channel check, or for
+	// when its maintenance window opens if that comes first.
+	requeueRes := versionChannelRes
+
+	// The PendingMaintenance condition is patched together with the readiness below.
+	if podTemplateChangesHeld {
+		k8sutils.SetCondition(maintenance.PendingCondition(maintenanceWindowOpensAt, cp.Generation), cp)
+		// Nothing changes when the window opens so requeue to roll out the held changes.
+		requeueAfter := max(time.Until(maintenanceWindowOpensAt), time.Second)
+		if requeueRes.RequeueAfter == 0 || requeueAfter < requeueRes.RequeueAfter {
+			requeueRes = ctrl.Result{RequeueAfter: requeueAfter}
+		}
+	} else {
+		cp.Status.Conditions = lo.Reject(cp.Status.Conditions, func(c metav1.Condition, _ int) bool {
+			return c.Type == string(maintenance.ConditionType)
+		})
+	}
+
 	log.Trace(logger, "checking readiness of ControlPlane deployments")
 
 	if controlplaneDeployment.Status.Replicas == 0 || controlplaneDeployment.Status.AvailableReplicas < controlplaneDeployment.Status.Replicas {
@@ -481,7 +524,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			log.Debug(logger, "unable to patch ControlPlane status")
 			return res, nil
 		}
-		return ctrl.Result{}, nil
+		return requeueRes, nil
 	}
 
 	markAsProvisioned(cp)
@@ -498,7 +541,37 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	log.Debug(logger, "reconciliation complete for ControlPlane resource")
-	return ctrl.Result{}, nil
+	return requeueRes, nil
+}
+
+// defaultsArgsForControlPlane returns the arguments used to set the defaults
+// of the provided ControlPlane's options.
+func defaultsArgsForControlPlane(
+	cp *operatorv1beta1.ControlPlane,
+	dataplaneIngressServiceName string,
+	dataplaneAdminServiceName string,
+	anonymousReportsEnabled bool,
+) controlplane.DefaultsArgs {
+	defaultArgs := controlplane.DefaultsArgs{
+		Namespace:                   cp.Namespace,
+		ControlPlaneName:            cp.Name,
+		DataPlaneIngressServiceName: dataplaneIngressServiceName,
+		DataPlaneAdminServiceName:   dataplaneAdminServiceName,
+		AnonymousReportsEnabled:     controlplane.DeduceAnonymousReportsEnabled(anonymousReportsEnabled, &cp.Spec.ControlPlaneOptions),
+	}
+	// ControlPlanes shared by multiple Gateways reconcile all the Gateways of
+	// their GatewayClass, which only has the Gateways of the group.
+	if cp.Labels[consts.GatewaySharedInfrastructureLabel] != "" {
+		defaultArgs.SharedByGateways = true
+		return defaultArgs
+	}
+	for _, owner := range cp.OwnerReferences {
+		if strings.HasPrefix(owner.APIVersion, gatewayv1.GroupName) && owner.Kind == "Gateway" {
+			defaultArgs.OwnedByGateway = owner.Name
+			continue
+		}
+	}
+	return defaultArgs
 }
 
 // validateControlPlane validates the control plane.
@@ -17,13 +17,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/gateway-operator/controller/pkg/controlplane"
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/maintenance"
 	"github.com/kong/gateway-operator/controller/pkg/op"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/controller/pkg/versionchannel"
 	"github.com/kong/gateway-operator/internal/versions"
 	"github.com/kong/gateway-operator/pkg/clientops"
 	"github.com/kong/gateway-operator/pkg/consts"
@@ -158,16 +161,21 @@ type ensureDeploymentParams struct {
 	// as a result of the ReferenceGrant validation: if a ReferenceGrant is missing
 	// in the requested namespace, the namespace is removed from the list.
 	WatchNamespaces []string
+	// HoldPodTemplateChanges is a flag to leave the Pod template of an existing
+	// Deployment untouched, e.g. until the ControlPlane's maintenance window opens.
+	HoldPodTemplateChanges bool
 }
 
 // ensureDeployment ensures that a Deployment is created for the
 // ControlPlane resource. Deployment will remain in dormant state until
 // corresponding dataplane is set.
+// It also returns whether changes to the Pod template of the existing
+// Deployment were held as requested with params.HoldPodTemplateChanges.
 func (r *Reconciler) ensureDeployment(
 	ctx context.Context,
 	logger logr.Logger,
 	params ensureDeploymentParams,
-) (op.Result, *appsv1.Deployment, error) {
+) (op.Result, *appsv1.Deployment, bool, error) {
 	dataplaneIsSet := params.ControlPlane.Spec.DataPlane != nil && *params.ControlPlane.Spec.DataPlane != ""
 
 	deployments, err := k8sutils.ListDeploymentsForOwner(ctx,
@@ -179,15 +187,15 @@ func (r *Reconciler) ensureDeployment(
 		},
 	)
 	if err != nil {
-		return op.Noop, nil, err
+		return op.Noop, nil, false, err
 	}
 
 	count := len(deployments)
 	if count > 1 {
 		if err := k8sreduce.ReduceDeployments(ctx, r.Client, deployments); err != nil {
-			return op.Noop, nil, err
+			return op.Noop, nil, false, err
 		}
-		return op.Noop, nil, errors.New("number of deployments reduced")
+		return op.Noop, nil, false, errors.New("number of deployments reduced")
 	}
 
 	versionValidationOptions := make([]versions.VersionValidationOption, 0)
@@ -196,7 +204,7 @@ func (r *Reconciler) ensureDeployment(
 	}
 	controlplaneImage, err := controlplane.GenerateImage(&params.ControlPlane.Spec.ControlPlaneOptions, versionValidationOptions...)
 	if err != nil {
-		return op.Noop, nil, err
+		return op.Noop, nil, false, err
 	}
 	generatedDeployment, err := k8sresources.GenerateNewDeploymentForControlPlane(k8sresources.GenerateNewDeploymentForControlPlaneParams{
 		ControlPlane:                   params.ControlPlane,
@@ -207,7 +215,7 @@ func (r *Reconciler) ensureDeployment(
 		WatchNamespaces:                params.WatchNamespaces,
 	})
 	if err != nil {
-		return op.Noop, nil, err
+		return op.Noop, nil, false, err
 	}
 
 	if count == 1 {
@@ -215,23 +223,32 @@ func (r *Reconciler) ensureDeployment(
 
 		// If the enforceConfig flag is not set, we compare the spec hash of the
 		// existing Deployment with the spec hash of the desired Deployment. If
-		// the hashes match, we skip the update.
+		// the hashes and the propagated metadata match, we skip the update.
 		if !params.EnforceConfig {
 			match, err := k8sresources.SpecHashMatchesAnnotation(params.ControlPlane.Spec, existingDeployment)
 			if err != nil {
-				return op.Noop, nil, err
+				return op.Noop, nil, false, err
 			}
-			if match {
+			if match && k8sresources.PropagatedMetadataMatches(existingDeployment, generatedDeployment) {
 				log.Debug(logger, "ControlPlane Deployment spec hash matches existing Deployment, skipping update")
-				return op.Noop, existingDeployment, nil
+				return op.Noop, existingDeployment, false, nil
 			}
 			// If the spec hash does not match, we need to enforce the configuration
 			// so fall through to the update logic.
 		}
 
-		var updated bool
+		var updated, held bool
 		oldExistingDeployment := existingDeployment.DeepCopy()
 
+		if params.HoldPodTemplateChanges {
+			held = maintenance.HoldPodTemplateChanges(existingDeployment, generatedDeployment,
+				&existingDeployment.Spec.Template, &generatedDeployment.Spec.Template,
+			)
+			if held {
+				log.Debug(logger, "holding ControlPlane Deployment Pod template changes until the maintenance window opens")
+			}
+		}
+
 		// ensure that object metadata is up to date
 		updated, existingDeployment.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingDeployment.ObjectMeta, generatedDeployment.ObjectMeta)
 
@@ -266,18 +283,59 @@ func (r *Reconciler) ensureDeployment(
 			}
 		}
 
-		return patch.ApplyPatchIfNotEmpty(ctx, r.Client, logger, existingDeployment, oldExistingDeployment, updated)
+		res, deployment, err := patch.ApplyPatchIfNotEmpty(ctx, r.Client, logger, existingDeployment, oldExistingDeployment, updated)
+		return res, deployment, held, err
 	}
 
 	if !dataplaneIsSet {
 		generatedDeployment.Spec.Replicas = lo.ToPtr(int32(numReplicasWhenNoDataPlane))
 	}
 	if err := r.Create(ctx, generatedDeployment); err != nil {
-		return op.Noop, nil, fmt.Errorf("failed creating ControlPlane Deployment %s: %w", generatedDeployment.Name, err)
+		return op.Noop, nil, false, fmt.Errorf("failed creating ControlPlane Deployment %s: %w", generatedDeployment.Name, err)
 	}
 
 	log.Debug(logger, "deployment for ControlPlane created", "deployment", generatedDeployment.Name)
-	return op.Created, generatedDeployment, nil
+	return op.Created, generatedDeployment, false, nil
+}
+
+// ensureVersionChannelImage resolves the controller image of the version channel
+// tracked by the ControlPlane, if any. It returns the ControlPlane to generate
+// the Deployment from: a copy of the ControlPlane using the resolved image, so
+// that upgrades are rolled out like any other change to the ControlPlane's spec,
+// or the ControlPlane itself when it doesn't track a version channel.
+func (r *Reconciler) ensureVersionChannelImage(
+	ctx context.Context,
+	cp *operatorv1beta1.ControlPlane,
+) (*operatorv1beta1.ControlPlane, ctrl.Result, error) {
+	// The configured image determines the repository in which releases are looked up.
+	image, err := controlplane.GenerateImage(&cp.Spec.ControlPlaneOptions)
+	if err != nil {
+		return nil, ctrl.Result{}, err
+	}
+	var validators []versions.VersionValidationOption
+	if r.ValidateControlPlaneImage {
+		validators = append(validators, versions.IsControlPlaneImageVersionSupported)
+	}
+
+	var imagePullSecrets []corev1.LocalObjectReference
+	if podTemplateSpec := cp.Spec.Deployment.PodTemplateSpec; podTemplateSpec != nil {
+		imagePullSecrets = podTemplateSpec.Spec.ImagePullSecrets
+	}
+
+	resolved, res, err := versionchannel.EnsureImage(ctx, r.Client,
+		versionchannel.ResolverOrDefault(r.VersionChannelResolver), cp, image, imagePullSecrets, validators...,
+	)
+	if err != nil {
+		return nil, ctrl.Result{}, fmt.Errorf("failed resolving version channel image: %w", err)
+	}
+	if resolved == "" {
+		return cp, res, nil
+	}
+
+	cp = cp.DeepCopy()
+	container := k8sutils.GetPodContainerByName(&cp.Spec.Deployment.PodTemplateSpec.Spec, consts.ControlPlaneControllerContainerName)
+	container.Image = resolved
+	return cp, res, nil
 }
 
 func (r *Reconciler) ensureServiceAccount(
@@ -1,7 +1,9 @@
 package controlplane
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
@@ -10,12 +12,15 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"oras.land/oras-go/v2/registry/remote/credentials"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/versionchannel"
 	"github.com/kong/gateway-operator/modules/manager/scheme"
 	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
 
 	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
@@ -650,3 +655,54 @@ func TestProcessClusterRole(t *testing.T) {
 		})
 	}
 }
+
+type staticTagLister []string
+
+func (l staticTagLister) ListTags(context.Context, string, credentials.Store) ([]string, error) {
+	return l, nil
+}
+
+func TestEnsureVersionChannelImage(t *testing.T) {
+	cp := &operatorv1beta1.ControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cp",
+			Namespace: "default",
+			Annotations: map[string]string{
+				consts.AnnotationVersionChannel: "3.4.x",
+			},
+		},
+		Spec: operatorv1beta1.ControlPlaneSpec{
+			ControlPlaneOptions: operatorv1beta1.ControlPlaneOptions{
+				Deployment: operatorv1beta1.ControlPlaneDeploymentOptions{
+					PodTemplateSpec: &corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:  consts.ControlPlaneControllerContainerName,
+									Image: "kong/kubernetes-ingress-controller:3.4.0",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	r := &Reconciler{
+		Client: fakectrlruntimeclient.NewClientBuilder().
+			WithScheme(scheme.Get()).
+			WithObjects(cp).
+			WithStatusSubresource(cp).
+			Build(),
+		VersionChannelResolver: versionchannel.NewResolver(staticTagLister{"3.3.0", "3.4.0", "3.4.3", "3.5.0"}, time.Hour),
+	}
+
+	desired, res, err := r.ensureVersionChannelImage(t.Context(), cp)
+	require.NoError(t, err)
+	require.NotZero(t, res.RequeueAfter)
+	container := k8sutils.GetPodContainerByName(&desired.Spec.Deployment.PodTemplateSpec.Spec, consts.ControlPlaneControllerContainerName)
+	require.Equal(t, "kong/kubernetes-ingress-controller:3.4.3", container.Image)
+	require.Equal(t, "kong/kubernetes-ingress-controller:3.4.3", cp.Annotations[consts.AnnotationVersionChannelResolvedImage])
+	container = k8sutils.GetPodContainerByName(&cp.Spec.Deployment.PodTemplateSpec.Spec, consts.ControlPlaneControllerContainerName)
+	require.Equal(t, "kong/kubernetes-ingress-controller:3.4.0", container.Image, "the ControlPlane itself is left untouched")
+}
@@ -5,9 +5,12 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/kong/gateway-operator/controller/pkg/controlplane"
 	"github.com/kong/gateway-operator/pkg/consts"
@@ -562,6 +565,53 @@ func TestSetControlPlaneDefaults(t *testing.T) {
 	}
 }
 
+func TestSetControlPlaneDefaultsGatewayToReconcile(t *testing.T) {
+	cp := &operatorv1beta1.ControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-ns",
+			Name:      "cp",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: gatewayv1.GroupVersion.String(),
+					Kind:       "Gateway",
+					Name:       "gw",
+				},
+			},
+		},
+	}
+
+	args := defaultsArgsForControlPlane(cp, "ingress", "admin", false)
+	require.Equal(t, "gw", args.OwnedByGateway)
+	require.False(t, args.SharedByGateways)
+	require.True(t, controlplane.SetDefaults(&cp.Spec.ControlPlaneOptions, args))
+	container := k8sutils.GetPodContainerByName(&cp.Spec.Deployment.PodTemplateSpec.Spec, consts.ControlPlaneControllerContainerName)
+	require.NotNil(t, container)
+	require.Equal(t, "test-ns/gw", k8sutils.EnvValueByName(container.Env, "CONTROLLER_GATEWAY_TO_RECONCILE"))
+
+	t.Log("joining a shared infrastructure group removes the Gateway to reconcile")
+	cp.Labels = map[string]string{consts.GatewaySharedInfrastructureLabel: "group"}
+	args = defaultsArgsForControlPlane(cp, "ingress", "admin", false)
+	require.Empty(t, args.OwnedByGateway)
+	require.True(t, args.SharedByGateways)
+	require.True(t, controlplane.SetDefaults(&cp.Spec.ControlPlaneOptions, args))
+	container = k8sutils.GetPodContainerByName(&cp.Spec.Deployment.PodTemplateSpec.Spec, consts.ControlPlaneControllerContainerName)
+	require.NotNil(t, container)
+	require.False(t, lo.ContainsBy(container.Env, func(env corev1.EnvVar) bool {
+		return env.Name == "CONTROLLER_GATEWAY_TO_RECONCILE"
+	}))
+	require.Equal(t, consts.DataPlaneInitRetryDelay, k8sutils.EnvValueByName(container.Env, "CONTROLLER_KONG_ADMIN_INIT_RETRY_DELAY"))
+	require.False(t, controlplane.SetDefaults(&cp.Spec.ControlPlaneOptions, args))
+
+	t.Log("a shared ControlPlane is never restricted to a single Gateway")
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "CONTROLLER_GATEWAY_TO_RECONCILE",
+		Value: "test-ns/gw",
+	})
+	require.True(t, controlplane.SetDefaults(&cp.Spec.ControlPlaneOptions, args))
+	container = k8sutils.GetPodContainerByName(&cp.Spec.Deployment.PodTemplateSpec.Spec, consts.ControlPlaneControllerContainerName)
+	require.Empty(t, k8sutils.EnvValueByName(container.Env, "CONTROLLER_GATEWAY_TO_RECONCILE"))
+}
+
 func TestControlPlaneSpecDeepEqual(t *testing.T) {
 	testCases := []struct {
 		name            string
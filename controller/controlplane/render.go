@@ -0,0 +1,95 @@
+package controlplane
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/controlplane"
+	"github.com/kong/gateway-operator/internal/versions"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// RenderOwnedResources generates the objects that the ControlPlane controller
+// would create for the provided ControlPlane without reaching out to the API server.
+// The names of the ingress and admin Services of the ControlPlane's DataPlane
+// should be provided when the DataPlane is known, otherwise the Deployment is
+// rendered in its dormant (scaled to 0) state.
+//
+// Names of the generated objects are derived from their GenerateName and the
+// admin mTLS certificate Secret is filled with placeholder data. Objects which
+// require cluster state, like Roles for WatchNamespaces or the admission webhook
+// resources, are not rendered.
+func RenderOwnedResources(
+	cp *operatorv1beta1.ControlPlane,
+	dataplaneIngressServiceName string,
+	dataplaneAdminServiceName string,
+	anonymousReportsEnabled bool,
+	validateControlPlaneImage bool,
+) ([]client.Object, error) {
+	cp = cp.DeepCopy()
+	_ = controlplane.SetDefaults(
+		&cp.Spec.ControlPlaneOptions,
+		defaultsArgsForControlPlane(cp, dataplaneIngressServiceName, dataplaneAdminServiceName, anonymousReportsEnabled),
+	)
+	if err := validateControlPlane(cp, validateControlPlaneImage); err != nil {
+		return nil, err
+	}
+
+	serviceAccount := k8sresources.GenerateNewServiceAccountForControlPlane(cp.Namespace, cp.Name)
+	k8sutils.SetOwnerForObject(serviceAccount, cp)
+	k8sutils.SetNameFromGenerateName(serviceAccount)
+
+	controlplaneContainer := k8sutils.GetPodContainerByName(&cp.Spec.Deployment.PodTemplateSpec.Spec, consts.ControlPlaneControllerContainerName)
+	clusterRole, err := k8sresources.GenerateNewClusterRoleForControlPlane(cp.Name, controlplaneContainer.Image, validateControlPlaneImage)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating ClusterRole for ControlPlane %s: %w", cp.Name, err)
+	}
+	k8sutils.SetOwnerForObjectThroughLabels(clusterRole, cp)
+	k8sutils.SetNameFromGenerateName(clusterRole)
+
+	clusterRoleBinding := k8sresources.GenerateNewClusterRoleBindingForControlPlane(cp.Namespace, cp.Name, serviceAccount.Name, clusterRole.Name)
+	k8sutils.SetOwnerForObjectThroughLabels(clusterRoleBinding, cp)
+	k8sutils.SetNameFromGenerateName(clusterRoleBinding)
+
+	adminCertificate := k8sresources.GenerateNewTLSSecret(cp,
+		k8sresources.SecretWithLabel(consts.SecretUsedByServiceLabel, consts.ControlPlaneServiceKindAdmin),
+		k8sresources.SecretWithPlaceholderTLSData(),
+	)
+	k8sutils.SetNameFromGenerateName(adminCertificate)
+
+	versionValidationOptions := make([]versions.VersionValidationOption, 0)
+	if validateControlPlaneImage {
+		versionValidationOptions = append(versionValidationOptions, versions.IsControlPlaneImageVersionSupported)
+	}
+	controlplaneImage, err := controlplane.GenerateImage(&cp.Spec.ControlPlaneOptions, versionValidationOptions...)
+	if err != nil {
+		return nil, err
+	}
+	deployment, err := k8sresources.GenerateNewDeploymentForControlPlane(k8sresources.GenerateNewDeploymentForControlPlaneParams{
+		ControlPlane:            cp,
+		ControlPlaneImage:       controlplaneImage,
+		ServiceAccountName:      serviceAccount.Name,
+		AdminMTLSCertSecretName: adminCertificate.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed generating Deployment for ControlPlane %s: %w", cp.Name, err)
+	}
+	if cp.Spec.DataPlane == nil || *cp.Spec.DataPlane == "" {
+		deployment.Spec.Replicas = lo.ToPtr(int32(numReplicasWhenNoDataPlane))
+	}
+	k8sutils.SetNameFromGenerateName(deployment)
+
+	return []client.Object{
+		serviceAccount,
+		clusterRole,
+		clusterRoleBinding,
+		adminCertificate,
+		deployment,
+	}, nil
+}
@@ -58,6 +58,7 @@ type Manager struct {
 	pipelines                map[types.UID]MetricsScrapePipeline
 	cpNNToDpUID              map[types.NamespacedName]types.UID
 	clusterCAKeyConfig       secrets.KeyConfig
+	dataPlaneMetricsStore    *DataPlaneMetricsStore
 }
 
 // NewManager creates new MetricsScrapeManager.
@@ -80,6 +81,13 @@ func NewManager(
 	}
 }
 
+// WithDataPlaneMetricsStore configures the manager to record the metrics computed
+// from the scraped metrics of each DataPlane in the provided store.
+func (msm *Manager) WithDataPlaneMetricsStore(store *DataPlaneMetricsStore) *Manager {
+	msm.dataPlaneMetricsStore = store
+	return msm
+}
+
 // initMTLSCerts creates mTLS certs for the manager so that it can use them for
 // secure communication with DataPlane's AdminAPI endpoints.
 // When successful, it sets the certs on the manager.
@@ -366,6 +374,12 @@ func (msm *Manager) enableMetricsScraperForControlPlanesDataPlane(
 		MetricsScraper:  NewPrometheusMetricsScraper(msm.logger, &dp, httpClient, adminAPIAddressProvider),
 		MetricsEnricher: enricher,
 	}
+	if msm.dataPlaneMetricsStore != nil {
+		pipeline.MetricsEnricher = metricsConsumers{
+			msm.dataPlaneMetricsStore.ConsumerFor(&dp),
+			enricher,
+		}
+	}
 
 	if msm.Add(controlplane, pipeline) {
 		log.Debug(msm.logger, "enabled metrics scraper for ControlPlane", controlplane, "DataPlane", controlplane.Spec.DataPlane)
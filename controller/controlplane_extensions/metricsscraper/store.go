@@ -0,0 +1,226 @@
+package metricsscraper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+const (
+	// DataPlaneMetricRequestsPerSecond is the name of the DataPlane metric
+	// providing the number of requests per second handled by all of its Pods.
+	DataPlaneMetricRequestsPerSecond = "kong_requests_per_second"
+	// DataPlaneMetricActiveConnections is the name of the DataPlane metric
+	// providing the number of active connections handled by all of its Pods.
+	DataPlaneMetricActiveConnections = "kong_active_connections"
+	// DataPlaneMetricUpstreamLatencyAverageMs is the name of the DataPlane metric
+	// providing the average upstream latency (in milliseconds) of the requests
+	// proxied by all of its Pods.
+	DataPlaneMetricUpstreamLatencyAverageMs = "kong_upstream_latency_average_ms"
+)
+
+// DataPlaneMetricNames is the list of names of metrics computed for DataPlanes.
+var DataPlaneMetricNames = []string{
+	DataPlaneMetricRequestsPerSecond,
+	DataPlaneMetricActiveConnections,
+	DataPlaneMetricUpstreamLatencyAverageMs,
+}
+
+const (
+	// KongMetricNameKongNginxRequestsTotal is the name of the kong_nginx_requests_total metric.
+	KongMetricNameKongNginxRequestsTotal = "kong_nginx_requests_total"
+	// KongMetricNameKongNginxConnectionsTotal is the name of the kong_nginx_connections_total metric.
+	KongMetricNameKongNginxConnectionsTotal = "kong_nginx_connections_total"
+)
+
+// DataPlaneMetrics holds the values of the metrics computed for a DataPlane
+// from the metrics scraped from its Pods.
+type DataPlaneMetrics struct {
+	// Values maps metric names (see DataPlaneMetricNames) to their values.
+	// Metrics which could not be computed (e.g. rates before the second scrape)
+	// are not present.
+	Values map[string]float64
+	// Timestamp is the time the metrics were scraped at.
+	Timestamp time.Time
+	// Window is the time window the rate based metrics were computed over.
+	Window time.Duration
+}
+
+// endpointSample holds the cumulative values scraped from a single DataPlane
+// Admin API endpoint that are needed to compute rates.
+type endpointSample struct {
+	requests     float64
+	latencySum   float64
+	latencyCount uint64
+}
+
+// dataPlaneSample holds the samples scraped from all DataPlane's Admin API endpoints.
+type dataPlaneSample struct {
+	timestamp time.Time
+	endpoints map[adminAPIEndpointURL]endpointSample
+}
+
+// DataPlaneMetricsStore stores the latest metrics computed for DataPlanes
+// from the scraped metrics so that they can be served e.g. through the
+// Kubernetes custom metrics API.
+type DataPlaneMetricsStore struct {
+	maxAge  time.Duration
+	lock    sync.RWMutex
+	samples map[types.NamespacedName]dataPlaneSample
+	metrics map[types.NamespacedName]DataPlaneMetrics
+}
+
+// NewDataPlaneMetricsStore creates a new DataPlaneMetricsStore.
+// Metrics older than maxAge (e.g. for DataPlanes which are not scraped anymore)
+// are not returned.
+func NewDataPlaneMetricsStore(maxAge time.Duration) *DataPlaneMetricsStore {
+	return &DataPlaneMetricsStore{
+		maxAge:  maxAge,
+		samples: make(map[types.NamespacedName]dataPlaneSample),
+		metrics: make(map[types.NamespacedName]DataPlaneMetrics),
+	}
+}
+
+// Get returns the latest metrics computed for the DataPlane with the provided name.
+func (s *DataPlaneMetricsStore) Get(dataplane types.NamespacedName) (DataPlaneMetrics, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	m, ok := s.metrics[dataplane]
+	if !ok || time.Since(m.Timestamp) > s.maxAge {
+		return DataPlaneMetrics{}, false
+	}
+	return m, true
+}
+
+// ConsumerFor returns a MetricsConsumer which computes and stores the metrics
+// for the provided DataPlane.
+func (s *DataPlaneMetricsStore) ConsumerFor(dataplane *operatorv1beta1.DataPlane) MetricsConsumer {
+	return &dataPlaneMetricsStoreConsumer{
+		store:     s,
+		dataplane: client.ObjectKeyFromObject(dataplane),
+	}
+}
+
+func (s *DataPlaneMetricsStore) record(dataplane types.NamespacedName, m Metrics, now time.Time) {
+	current := dataPlaneSample{
+		timestamp: now,
+		endpoints: make(map[adminAPIEndpointURL]endpointSample, len(m.metrics)),
+	}
+	var activeConnections float64
+	for url, families := range m.metrics {
+		var sample endpointSample
+		for _, metric := range families[KongMetricNameKongNginxRequestsTotal].GetMetric() {
+			sample.requests += counterOrGaugeValue(metric)
+		}
+		for _, metric := range families[KongMetricNameKongUpstreamLatencyMs].GetMetric() {
+			sample.latencySum += metric.GetHistogram().GetSampleSum()
+			sample.latencyCount += metric.GetHistogram().GetSampleCount()
+		}
+		for _, metric := range families[KongMetricNameKongNginxConnectionsTotal].GetMetric() {
+			if labelValue(metric, "state") == "active" {
+				activeConnections += counterOrGaugeValue(metric)
+			}
+		}
+		current.endpoints[url] = sample
+	}
+
+	computed := DataPlaneMetrics{
+		Values: map[string]float64{
+			DataPlaneMetricActiveConnections: activeConnections,
+		},
+		Timestamp: now,
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if previous, ok := s.samples[dataplane]; ok && now.After(previous.timestamp) {
+		var (
+			window       = now.Sub(previous.timestamp)
+			requests     float64
+			latencySum   float64
+			latencyCount uint64
+		)
+		// Only take into account the endpoints (Pods) which were scraped in both samples.
+		for url, cur := range current.endpoints {
+			prev, ok := previous.endpoints[url]
+			if !ok {
+				continue
+			}
+			// Counters are reset when Kong restarts.
+			if cur.requests >= prev.requests {
+				requests += cur.requests - prev.requests
+			} else {
+				requests += cur.requests
+			}
+			if cur.latencyCount >= prev.latencyCount {
+				latencySum += cur.latencySum - prev.latencySum
+				latencyCount += cur.latencyCount - prev.latencyCount
+			} else {
+				latencySum += cur.latencySum
+				latencyCount += cur.latencyCount
+			}
+		}
+		computed.Window = window
+		computed.Values[DataPlaneMetricRequestsPerSecond] = requests / window.Seconds()
+		if latencyCount > 0 {
+			computed.Values[DataPlaneMetricUpstreamLatencyAverageMs] = latencySum / float64(latencyCount)
+		} else {
+			computed.Values[DataPlaneMetricUpstreamLatencyAverageMs] = 0
+		}
+	}
+
+	s.samples[dataplane] = current
+	s.metrics[dataplane] = computed
+}
+
+// dataPlaneMetricsStoreConsumer is a MetricsConsumer which computes the metrics
+// for a DataPlane and records them in a DataPlaneMetricsStore.
+type dataPlaneMetricsStoreConsumer struct {
+	store     *DataPlaneMetricsStore
+	dataplane types.NamespacedName
+}
+
+// Consume implements MetricsConsumer.
+func (c *dataPlaneMetricsStoreConsumer) Consume(_ context.Context, m Metrics) error {
+	c.store.record(c.dataplane, m, time.Now())
+	return nil
+}
+
+// metricsConsumers is a MetricsConsumer which passes the metrics to all its consumers.
+type metricsConsumers []MetricsConsumer
+
+// Consume implements MetricsConsumer.
+func (mc metricsConsumers) Consume(ctx context.Context, m Metrics) error {
+	var errs []error
+	for _, c := range mc {
+		if err := c.Consume(ctx, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func counterOrGaugeValue(m *dto.Metric) float64 {
+	if m.GetCounter() != nil {
+		return m.GetCounter().GetValue()
+	}
+	return m.GetGauge().GetValue()
+}
+
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package metricsscraper
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testMetrics(endpoints map[adminAPIEndpointURL]endpointTestValues) Metrics {
+	m := Metrics{metrics: make(metricsMap)}
+	for url, v := range endpoints {
+		m.metrics[url] = map[metricName]*dto.MetricFamily{
+			KongMetricNameKongNginxRequestsTotal: {
+				Metric: []*dto.Metric{
+					{Gauge: &dto.Gauge{Value: lo.ToPtr(v.requests)}},
+				},
+			},
+			KongMetricNameKongNginxConnectionsTotal: {
+				Metric: []*dto.Metric{
+					{
+						Label: []*dto.LabelPair{{Name: lo.ToPtr("state"), Value: lo.ToPtr("active")}},
+						Gauge: &dto.Gauge{Value: lo.ToPtr(v.activeConnections)},
+					},
+					{
+						Label: []*dto.LabelPair{{Name: lo.ToPtr("state"), Value: lo.ToPtr("accepted")}},
+						Gauge: &dto.Gauge{Value: lo.ToPtr(1000.0)},
+					},
+				},
+			},
+			KongMetricNameKongUpstreamLatencyMs: {
+				Metric: []*dto.Metric{
+					{Histogram: &dto.Histogram{SampleSum: lo.ToPtr(v.latencySum), SampleCount: lo.ToPtr(v.latencyCount)}},
+				},
+			},
+		}
+	}
+	return m
+}
+
+type endpointTestValues struct {
+	requests          float64
+	activeConnections float64
+	latencySum        float64
+	latencyCount      uint64
+}
+
+func TestDataPlaneMetricsStore(t *testing.T) {
+	var (
+		dp    = types.NamespacedName{Namespace: "default", Name: "dp"}
+		now   = time.Now()
+		store = NewDataPlaneMetricsStore(time.Minute)
+	)
+
+	_, ok := store.Get(dp)
+	require.False(t, ok, "no metrics should be returned before the first scrape")
+
+	store.record(dp, testMetrics(map[adminAPIEndpointURL]endpointTestValues{
+		"https://10.0.0.1:8444": {requests: 100, activeConnections: 3, latencySum: 1000, latencyCount: 100},
+		"https://10.0.0.2:8444": {requests: 200, activeConnections: 2, latencySum: 2000, latencyCount: 100},
+	}), now.Add(-10*time.Second))
+
+	m, ok := store.Get(dp)
+	require.True(t, ok)
+	assert.Equal(t, map[string]float64{
+		DataPlaneMetricActiveConnections: 5,
+	}, m.Values, "rates should not be computed from a single scrape")
+
+	store.record(dp, testMetrics(map[adminAPIEndpointURL]endpointTestValues{
+		"https://10.0.0.1:8444": {requests: 300, activeConnections: 4, latencySum: 4000, latencyCount: 200},
+		// Counters were reset e.g. because of a restart.
+		"https://10.0.0.2:8444": {requests: 50, activeConnections: 1, latencySum: 1000, latencyCount: 50},
+		// Endpoints not present in the previous scrape are not used for rates.
+		"https://10.0.0.3:8444": {requests: 1000, activeConnections: 1, latencySum: 100000, latencyCount: 1000},
+	}), now)
+
+	m, ok = store.Get(dp)
+	require.True(t, ok)
+	assert.Equal(t, 10*time.Second, m.Window)
+	assert.Equal(t, map[string]float64{
+		DataPlaneMetricActiveConnections:        6,
+		DataPlaneMetricRequestsPerSecond:        25,
+		DataPlaneMetricUpstreamLatencyAverageMs: 4000.0 / 150,
+	}, m.Values)
+
+	_, ok = store.Get(types.NamespacedName{Namespace: "default", Name: "other"})
+	require.False(t, ok)
+
+	staleStore := NewDataPlaneMetricsStore(time.Second)
+	staleStore.record(dp, testMetrics(nil), now.Add(-time.Minute))
+	_, ok = staleStore.Get(dp)
+	require.False(t, ok, "stale metrics should not be returned")
+}
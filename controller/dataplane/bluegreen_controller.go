@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
@@ -15,18 +16,23 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kong/gateway-operator/controller/pkg/address"
 	"github.com/kong/gateway-operator/controller/pkg/ctxinjector"
-	"github.com/kong/gateway-operator/controller/pkg/dataplane"
+	dataplanepkg "github.com/kong/gateway-operator/controller/pkg/dataplane"
+	"github.com/kong/gateway-operator/controller/pkg/deletionprotection"
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
 	extensionserrors "github.com/kong/gateway-operator/controller/pkg/extensions/errors"
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/maintenance"
 	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/controller/pkg/versionchannel"
 	"github.com/kong/gateway-operator/modules/manager/logging"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
@@ -74,6 +80,21 @@ type BlueGreenReconciler struct {
 	EnforceConfig          bool
 	ValidateDataPlaneImage bool
 	LoggingMode            logging.Mode
+
+	// ActiveConnectionsCounter is used to check whether draining DataPlane
+	// Pods still handle active connections. Kong status endpoint is used when nil.
+	ActiveConnectionsCounter ActiveConnectionsCounter
+
+	// VersionChannelResolver is used to resolve the images of the version
+	// channels tracked by DataPlanes. The shared default Resolver, listing
+	// tags from image registries, is used when nil.
+	VersionChannelResolver *versionchannel.Resolver
+
+	// DataPlaneMetrics provides the request rate scraped from DataPlanes, used
+	// to protect DataPlanes from deletion while they serve traffic.
+	DataPlaneMetrics deletionprotection.DataPlaneMetrics
+
+	eventRecorder record.EventRecorder
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -83,6 +104,7 @@ func (r *BlueGreenReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Man
 		return fmt.Errorf("incorrect delegate controller type: %T", r.DataPlaneController)
 	}
 	delegate.eventRecorder = mgr.GetEventRecorderFor("dataplane")
+	r.eventRecorder = delegate.eventRecorder
 	return DataPlaneWatchBuilder(mgr, r.KonnectEnabled).
 		Complete(r)
 }
@@ -92,7 +114,7 @@ func (r *BlueGreenReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Man
 // -----------------------------------------------------------------------------
 
 // Reconcile moves the current state of an object to the intended state.
-func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, reconcileErr error) {
 	// Calling it here ensures that evaluated values will be used for the duration of this function.
 	ctx = r.ContextInjector.InjectKeyValues(ctx)
 	var dataplane operatorv1beta1.DataPlane
@@ -102,8 +124,27 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	logger := log.GetLogger(ctx, "dataplaneBlueGreen", r.LoggingMode)
 
-	// Blue Green rollout strategy is not enabled, delegate to DataPlane controller.
-	if dataplane.Spec.Deployment.Rollout == nil || dataplane.Spec.Deployment.Rollout.Strategy.BlueGreen == nil {
+	log.Trace(logger, "checking if DataPlane reconciliation is paused")
+	if paused, res, err := pause.Reconcile(ctx, r.Client, r.eventRecorder, &dataplane, &dataplane); err != nil || paused || !res.IsZero() {
+		if paused {
+			log.Debug(logger, "DataPlane reconciliation is paused")
+		}
+		return res, err
+	}
+	defer func() { result = deletionprotection.RequeueProtected(&dataplane, result, reconcileErr) }()
+
+	log.Trace(logger, "managing DataPlane deletion protection")
+	if blocked, res, err := ensureDataPlaneDeletionProtection(ctx, r.Client, r.eventRecorder, r.DataPlaneMetrics, &dataplane); err != nil || blocked || !res.IsZero() {
+		if blocked {
+			log.Debug(logger, "DataPlane deletion blocked by deletion protection")
+		}
+		return res, err
+	}
+
+	// Blue Green rollout strategy is not enabled or not supported by the
+	// DataPlane's workload kind, delegate to DataPlane controller.
+	if dataplane.Spec.Deployment.Rollout == nil || dataplane.Spec.Deployment.Rollout.Strategy.BlueGreen == nil ||
+		dataplanepkg.IsDaemonSetWorkload(&dataplane) {
 		if err := r.prunePreviewSubresources(ctx, &dataplane); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed pruning preview DataPlane subresources: %w", err)
 		}
@@ -160,6 +201,21 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return result, nil
 	}
 
+	log.Trace(logger, "applying hybrid control plane configuration")
+	_, stop, result, err = applyDataPlaneHybridControlPlane(ctx, r.Client, logger, &dataplane,
+		types.NamespacedName{
+			Namespace: r.ClusterCASecretNamespace,
+			Name:      r.ClusterCASecretName,
+		},
+		r.ClusterCAKeyConfig,
+	)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not apply hybrid control plane configuration to DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	if stop || !result.IsZero() {
+		return result, nil
+	}
+
 	// Ensure "preview" Admin API service.
 	res, dataplaneAdminService, err := r.ensurePreviewAdminAPIService(ctx, logger, &dataplane)
 	if err != nil {
@@ -211,15 +267,50 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
+	// Resolve the image of the version channel tracked by the DataPlane, newer
+	// releases are rolled out through the "preview" Deployment.
+	log.Trace(logger, "ensuring DataPlane version channel image")
+	desiredDataPlane, versionChannelRes, err := ensureDataPlaneVersionChannelImage(ctx, r.Client,
+		versionchannel.ResolverOrDefault(r.VersionChannelResolver), &dataplane, r.DefaultImage, r.ValidateDataPlaneImage,
+	)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure version channel image for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	} else if versionChannelRes.Requeue {
+		return versionChannelRes, nil
+	}
+
+	// Changes rolling the "preview" Deployment's Pods are held until the
+	// DataPlane's maintenance window opens.
+	holdPodTemplateChanges, maintenanceWindowOpensAt, err := maintenance.HoldChanges(&dataplane, time.Now())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not evaluate maintenance window of DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+
 	// Ensure "preview" Deployment.
-	deployment, res, err := r.ensureDeploymentForDataPlane(ctx, logger, &dataplane, certSecret)
+	deployment, res, podTemplateChangesHeld, err := r.ensureDeploymentForDataPlane(ctx, logger, desiredDataPlane, certSecret, holdPodTemplateChanges)
 	if err != nil {
 		cErr := r.ensureRolledOutCondition(ctx, logger, &dataplane, metav1.ConditionFalse, kcfgdataplane.DataPlaneConditionReasonRolloutFailed, "failed to ensure preview Deployment")
 		return ctrl.Result{}, fmt.Errorf("failed to ensure Deployment for DataPlane: %w", errors.Join(cErr, err))
 	} else if res == op.Created || res == op.Updated {
 		return ctrl.Result{}, nil // dataplane deployment creation/update will trigger reconciliation
-	} else if replicas := deployment.Spec.Replicas; replicas != nil && *replicas == 0 {
-		return ctrl.Result{}, r.ensureRolledOutCondition(ctx, logger, &dataplane, metav1.ConditionFalse, kcfgdataplane.DataPlaneConditionReasonRolloutWaitingForChange, "")
+	}
+
+	log.Trace(logger, "ensuring DataPlane pending maintenance status")
+	maintenanceRes, err := ensureDataPlanePendingMaintenanceStatus(ctx, r.Client, &dataplane,
+		podTemplateChangesHeld, maintenanceWindowOpensAt,
+	)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not ensure pending maintenance status of DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	if maintenanceRes.Requeue {
+		return maintenanceRes, nil
+	}
+	// requeueRes requeues the DataPlane to check its version channel and to
+	// roll out held changes when its maintenance window opens.
+	requeueRes := earliestRequeue(versionChannelRes, maintenanceRes)
+
+	if replicas := deployment.Spec.Replicas; replicas != nil && *replicas == 0 {
+		return requeueRes, r.ensureRolledOutCondition(ctx, logger, &dataplane, metav1.ConditionFalse, kcfgdataplane.DataPlaneConditionReasonRolloutWaitingForChange, "")
 	}
 
 	// TODO: check if the preview service is available.
@@ -241,7 +332,7 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			"promotion_strategy", dataplane.Spec.Deployment.Rollout.Strategy.BlueGreen.Promotion.Strategy)
 
 		err := r.ensureRolledOutCondition(ctx, logger, &dataplane, metav1.ConditionFalse, kcfgdataplane.DataPlaneConditionReasonRolloutAwaitingPromotion, "")
-		return ctrl.Result{}, err
+		return requeueRes, err
 	}
 
 	// If we've failed to promote previously, don't set the RolledOut reason to
@@ -319,8 +410,15 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, fmt.Errorf("failed to reduce live deployments: %w", err)
 	}
 
+	log.Trace(logger, "ensuring DataPlane connection draining")
+	if res, err := ensureDataPlaneDraining(ctx, r.Client, logger, activeConnectionsCounterOrDefault(r.ActiveConnectionsCounter), &dataplane); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure connection draining: %w", err)
+	} else if !res.IsZero() {
+		return res, nil
+	}
+
 	log.Debug(logger, "BlueGreen reconciliation complete for DataPlane resource")
-	return ctrl.Result{}, nil
+	return requeueRes, nil
 }
 
 // ensureDataPlaneLiveReadyStatus ensures that the DataPlane has the Ready status
@@ -498,7 +596,8 @@ func (r *BlueGreenReconciler) ensureDeploymentForDataPlane(
 	logger logr.Logger,
 	dataplane *operatorv1beta1.DataPlane,
 	certSecret *corev1.Secret,
-) (*appsv1.Deployment, op.Result, error) {
+	holdPodTemplateChanges bool,
+) (*appsv1.Deployment, op.Result, bool, error) {
 	deploymentOpts := []k8sresources.DeploymentOpt{
 		labelSelectorFromDataPlaneRolloutStatusSelectorDeploymentOpt(dataplane),
 	}
@@ -522,7 +621,8 @@ func (r *BlueGreenReconciler) ensureDeploymentForDataPlane(
 		consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValuePreview,
 	}
 	// if the dataplane is configured with Konnect, the status/ready endpoint should be set as the readiness probe.
-	if _, konnectApplied := k8sutils.GetCondition(kcfgkonnect.KonnectExtensionAppliedType, dataplane); konnectApplied {
+	// The same applies to DataPlanes joining a self-hosted control plane.
+	if _, konnectApplied := k8sutils.GetCondition(kcfgkonnect.KonnectExtensionAppliedType, dataplane); konnectApplied || hybridControlPlaneEnabled(dataplane) {
 		deploymentOpts = append(deploymentOpts, statusReadyEndpointDeploymentOpt(dataplane))
 	}
 
@@ -532,21 +632,22 @@ func (r *BlueGreenReconciler) ensureDeploymentForDataPlane(
 		WithClusterCertificate(certSecret.Name).
 		WithOpts(deploymentOpts...).
 		WithDefaultImage(r.DefaultImage).
-		WithAdditionalLabels(deploymentLabels)
+		WithAdditionalLabels(deploymentLabels).
+		WithPodTemplateChangesHeld(holdPodTemplateChanges)
 
 	deployment, res, err := deploymentBuilder.BuildAndDeploy(ctx, dataplane, r.EnforceConfig, r.ValidateDataPlaneImage)
 	if err != nil {
-		return nil, op.Noop, fmt.Errorf("failed to ensure Deployment for DataPlane: %w", err)
+		return nil, op.Noop, false, fmt.Errorf("failed to ensure Deployment for DataPlane: %w", err)
 	}
 
 	switch res {
 	case op.Created, op.Updated:
 		log.Debug(logger, "deployment modified")
 		// requeue will be triggered by the creation or update of the owned object
-		return deployment, res, nil
+		return deployment, res, deploymentBuilder.PodTemplateChangesHeld(), nil
 	default:
 		log.Debug(logger, "no need for deployment update")
-		return deployment, op.Noop, nil
+		return deployment, op.Noop, deploymentBuilder.PodTemplateChangesHeld(), nil
 	}
 }
 
@@ -565,6 +666,8 @@ func (r *BlueGreenReconciler) resetPromoteWhenReadyAnnotation(
 }
 
 // reduceLiveDeployments reduces the number of live deployments to 1 by deleting the oldest ones.
+// When connection draining is enabled, the oldest ones are marked as draining instead
+// and are deleted by ensureDataPlaneDraining once their connections are drained.
 // It's used to reduce the number of live deployments that are not being used anymore after promotion (the old live
 // deployment gets "replaced" by the preview deployment).
 func (r *BlueGreenReconciler) reduceLiveDeployments(
@@ -596,13 +699,28 @@ func (r *BlueGreenReconciler) reduceLiveDeployments(
 	sort.Slice(deployments, func(i, j int) bool {
 		return deployments[i].CreationTimestamp.Before(&deployments[j].CreationTimestamp)
 	})
-	// Delete all but the last deployment.
+	drainingTimeout, err := connectionDrainingTimeout(dataPlane)
+	if err != nil {
+		log.Info(logger, "connection draining disabled", "reason", err.Error())
+	}
+
+	// Delete (or start draining when connection draining is enabled) all but the last deployment.
 	for _, deployment := range deployments[:len(deployments)-1] {
+		if drainingTimeout > 0 {
+			log.Debug(logger, "draining live deployment",
+				"deployment", client.ObjectKeyFromObject(&deployment),
+			)
+			if err := startDrainingDeployment(ctx, r.Client, &deployment); err != nil {
+				return err
+			}
+			continue
+		}
+
 		log.Debug(logger, "reducing live deployment",
 			"deployment", client.ObjectKeyFromObject(&deployment),
 		)
 
-		if err := dataplane.OwnedObjectPreDeleteHook(ctx, r.Client, &deployment); err != nil {
+		if err := dataplanepkg.OwnedObjectPreDeleteHook(ctx, r.Client, &deployment); err != nil {
 			return fmt.Errorf("failed executing pre delete hook: %w", err)
 		}
 		if err := r.Delete(ctx, &deployment); err != nil {
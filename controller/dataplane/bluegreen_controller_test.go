@@ -609,3 +609,70 @@ func TestEnsurePreviewIngressService(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureDeploymentForDataPlaneHoldsPodTemplateChanges(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+			UID:       "dp-uid",
+		},
+		Spec: operatorv1beta1.DataPlaneSpec{
+			DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+				Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+					Rollout: &operatorv1beta1.Rollout{
+						Strategy: operatorv1beta1.RolloutStrategy{
+							BlueGreen: &operatorv1beta1.BlueGreenStrategy{},
+						},
+					},
+					DeploymentOptions: operatorv1beta1.DeploymentOptions{
+						PodTemplateSpec: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name:  consts.DataPlaneProxyContainerName,
+										Image: "kong:3.9",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	certSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "certificate", Namespace: "default"}}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(dataplane).
+		Build()
+	r := BlueGreenReconciler{Client: cl}
+	previewImage := func() string {
+		deployments, err := k8sutils.ListDeploymentsForOwner(t.Context(), cl, dataplane.Namespace, dataplane.UID,
+			client.MatchingLabels{consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValuePreview},
+		)
+		require.NoError(t, err)
+		require.Len(t, deployments, 1)
+		return k8sutils.GetPodContainerByName(&deployments[0].Spec.Template.Spec, consts.DataPlaneProxyContainerName).Image
+	}
+
+	_, res, held, err := r.ensureDeploymentForDataPlane(t.Context(), logr.Discard(), dataplane, certSecret, true)
+	require.NoError(t, err)
+	require.Equal(t, op.Created, res, "creation is never held")
+	require.False(t, held)
+	require.Equal(t, "kong:3.9", previewImage())
+
+	t.Log("changes to the preview Deployment's Pod template are held")
+	dataplane.Spec.Deployment.PodTemplateSpec.Spec.Containers[0].Image = "kong:3.10"
+	_, _, held, err = r.ensureDeploymentForDataPlane(t.Context(), logr.Discard(), dataplane, certSecret, true)
+	require.NoError(t, err)
+	require.True(t, held)
+	require.Equal(t, "kong:3.9", previewImage())
+
+	t.Log("held changes are applied once not held anymore")
+	_, res, held, err = r.ensureDeploymentForDataPlane(t.Context(), logr.Discard(), dataplane, certSecret, false)
+	require.NoError(t, err)
+	require.Equal(t, op.Updated, res)
+	require.False(t, held)
+	require.Equal(t, "kong:3.10", previewImage())
+}
@@ -3,6 +3,7 @@ package dataplane
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
@@ -14,11 +15,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/gateway-operator/controller/pkg/ctxinjector"
+	dataplanepkg "github.com/kong/gateway-operator/controller/pkg/dataplane"
+	"github.com/kong/gateway-operator/controller/pkg/deletionprotection"
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
 	extensionserrors "github.com/kong/gateway-operator/controller/pkg/extensions/errors"
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/maintenance"
 	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/controller/pkg/versionchannel"
 	"github.com/kong/gateway-operator/modules/manager/logging"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
@@ -47,6 +53,20 @@ type Reconciler struct {
 	EnforceConfig            bool
 	LoggingMode              logging.Mode
 	ValidateDataPlaneImage   bool
+	// ActiveConnectionsCounter is used to check whether draining DataPlane
+	// Pods still handle active connections. Kong status endpoint is used when nil.
+	ActiveConnectionsCounter ActiveConnectionsCounter
+	// HybridClusteringStatusReader is used to check whether the DataPlane Pods
+	// are connected to a self-hosted Kong control plane. The control plane's
+	// Admin API is used when nil.
+	HybridClusteringStatusReader HybridClusteringStatusReader
+	// VersionChannelResolver is used to resolve the images of the version
+	// channels tracked by DataPlanes. The shared default Resolver, listing
+	// tags from image registries, is used when nil.
+	VersionChannelResolver *versionchannel.Resolver
+	// DataPlaneMetrics provides the request rate scraped from DataPlanes, used
+	// to protect DataPlanes from deletion while they serve traffic.
+	DataPlaneMetrics deletionprotection.DataPlaneMetrics
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -62,7 +82,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 // -----------------------------------------------------------------------------
 
 // Reconcile moves the current state of an object to the intended state.
-func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, reconcileErr error) {
 	// Calling it here ensures that evaluated values will be used for the duration of this function.
 	ctx = r.ContextInjector.InjectKeyValues(ctx)
 	logger := log.GetLogger(ctx, "dataplane", r.LoggingMode)
@@ -74,6 +94,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	log.Trace(logger, "checking if DataPlane reconciliation is paused")
+	if paused, res, err := pause.Reconcile(ctx, r.Client, r.eventRecorder, dataplane, dataplane); err != nil || paused || !res.IsZero() {
+		if paused {
+			log.Debug(logger, "DataPlane reconciliation is paused")
+		}
+		return res, err
+	}
+	defer func() { result = deletionprotection.RequeueProtected(dataplane, result, reconcileErr) }()
+
+	log.Trace(logger, "managing DataPlane deletion protection")
+	if blocked, res, err := ensureDataPlaneDeletionProtection(ctx, r.Client, r.eventRecorder, r.DataPlaneMetrics, dataplane); err != nil || blocked || !res.IsZero() {
+		if blocked {
+			log.Debug(logger, "DataPlane deletion blocked by deletion protection")
+		}
+		return res, err
+	}
+
 	if k8sutils.InitReady(dataplane) {
 		if patched, err := patchDataPlaneStatus(ctx, r.Client, logger, dataplane); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed initializing DataPlane Ready condition: %w", err)
@@ -99,6 +136,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return result, nil
 	}
 
+	log.Trace(logger, "applying hybrid control plane configuration")
+	hybridCfg, stop, result, err := applyDataPlaneHybridControlPlane(ctx, r.Client, logger, dataplane,
+		types.NamespacedName{
+			Namespace: r.ClusterCASecretNamespace,
+			Name:      r.ClusterCASecretName,
+		},
+		r.ClusterCAKeyConfig,
+	)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not apply hybrid control plane configuration to DataPlane %s: %w", dpNn, err)
+	}
+	if stop || !result.IsZero() {
+		return result, nil
+	}
+
 	log.Trace(logger, "exposing DataPlane deployment admin API via headless service")
 	res, dataplaneAdminService, err := ensureAdminServiceForDataPlane(ctx, r.Client, dataplane,
 		client.MatchingLabels{
@@ -188,7 +240,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	// if the dataplane is configured with Konnect, the status/ready endpoint should be set as the readiness probe.
-	if _, konnectApplied := k8sutils.GetCondition(kcfgkonnect.KonnectExtensionAppliedType, dataplane); konnectApplied {
+	// The same applies to DataPlanes joining a self-hosted control plane.
+	if _, konnectApplied := k8sutils.GetCondition(kcfgkonnect.KonnectExtensionAppliedType, dataplane); konnectApplied || hybridCfg != nil {
 		deploymentOpts = append(deploymentOpts, statusReadyEndpointDeploymentOpt(dataplane))
 	}
 
@@ -202,31 +255,110 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	deploymentOpts = append(deploymentOpts, withCustomPlugins(kpisForDeployment...))
 
+	// changes rolling the DataPlane's Pods are held until its maintenance window opens
+	holdPodTemplateChanges, maintenanceWindowOpensAt, err := maintenance.HoldChanges(dataplane, time.Now())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not evaluate maintenance window of DataPlane %s: %w", dpNn, err)
+	}
+
+	log.Trace(logger, "ensuring DataPlane version channel image")
+	desiredDataPlane, versionChannelRes, err := ensureDataPlaneVersionChannelImage(ctx, r.Client,
+		versionchannel.ResolverOrDefault(r.VersionChannelResolver), dataplane, r.DefaultImage, r.ValidateDataPlaneImage,
+	)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not ensure version channel image of DataPlane %s: %w", dpNn, err)
+	}
+	if versionChannelRes.Requeue {
+		return versionChannelRes, nil
+	}
+
 	deploymentBuilder := NewDeploymentBuilder(logger.WithName("deployment_builder"), r.Client).
 		WithBeforeCallbacks(r.Callbacks.BeforeDeployment).
 		WithAfterCallbacks(r.Callbacks.AfterDeployment).
 		WithClusterCertificate(certSecret.Name).
 		WithOpts(deploymentOpts...).
 		WithDefaultImage(r.DefaultImage).
-		WithAdditionalLabels(deploymentLabels)
+		WithAdditionalLabels(deploymentLabels).
+		WithPodTemplateChangesHeld(holdPodTemplateChanges)
 
-	deployment, res, err := deploymentBuilder.BuildAndDeploy(ctx, dataplane, r.EnforceConfig, r.ValidateDataPlaneImage)
+	workloadKind, err := dataplanepkg.WorkloadKind(dataplane)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("could not build Deployment for DataPlane %s: %w", dpNn, err)
+		return ctrl.Result{}, err
 	}
-	if res != op.Noop {
-		return ctrl.Result{}, nil
+
+	// workload is the Deployment or the DaemonSet running the DataPlane's Pods.
+	var workload client.Object
+	switch workloadKind {
+	case consts.DataPlaneWorkloadKindDaemonSet:
+		daemonSet, res, err := deploymentBuilder.BuildAndDeployDaemonSet(ctx, desiredDataPlane, r.EnforceConfig, r.ValidateDataPlaneImage)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not build DaemonSet for DataPlane %s: %w", dpNn, err)
+		}
+		if res != op.Noop {
+			return ctrl.Result{}, nil
+		}
+
+		// DaemonSets are not scaled horizontally.
+		if err := deleteHPAsForDataPlane(ctx, r.Client, dataplane); err != nil {
+			return ctrl.Result{}, err
+		}
+		workload = daemonSet
+
+	default:
+		deployment, res, err := deploymentBuilder.BuildAndDeploy(ctx, desiredDataPlane, r.EnforceConfig, r.ValidateDataPlaneImage)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not build Deployment for DataPlane %s: %w", dpNn, err)
+		}
+		if res != op.Noop {
+			return ctrl.Result{}, nil
+		}
+
+		res, _, err = ensureHPAForDataPlane(ctx, r.Client, logger, dataplane, deployment.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if res != op.Noop {
+			return ctrl.Result{}, nil
+		}
+		workload = deployment
 	}
 
-	res, _, err = ensureHPAForDataPlane(ctx, r.Client, logger, dataplane, deployment.Name)
+	log.Trace(logger, "ensuring DataPlane pending maintenance status")
+	maintenanceRes, err := ensureDataPlanePendingMaintenanceStatus(ctx, r.Client, dataplane,
+		deploymentBuilder.PodTemplateChangesHeld(), maintenanceWindowOpensAt,
+	)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, fmt.Errorf("could not ensure pending maintenance status of DataPlane %s: %w", dpNn, err)
+	}
+	if maintenanceRes.Requeue {
+		return maintenanceRes, nil
+	}
+
+	var zones *dataPlaneZones
+	if zoneAwarenessEnabled(dataplane) {
+		log.Trace(logger, "ensuring DataPlane Pods are labeled with their zones")
+		dpZones, err := ensureDataPlanePodsZones(ctx, r.Client, logger, workload)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not ensure zones of DataPlane %s Pods: %w", dpNn, err)
+		}
+		zones = &dpZones
+	}
+
+	log.Trace(logger, "ensuring DataPlane per-zone ingress services")
+	res, err = ensureZoneIngressServicesForDataPlane(ctx, logger, r.Client, dataplane, zones.zones(),
+		k8sresources.LabelSelectorFromDataPlaneStatusSelectorServiceOpt(dataplane),
+		k8sresources.ServicePortsFromDataPlaneIngressOpt(dataplane),
+		matchingLabelsToServiceOpt(additionalServiceLabels),
+	)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not ensure per-zone ingress Services for DataPlane %s: %w", dpNn, err)
 	}
 	if res != op.Noop {
+		log.Debug(logger, "per-zone ingress Services modified", "reason", res)
 		return ctrl.Result{}, nil
 	}
 
-	res, _, err = ensurePodDisruptionBudgetForDataPlane(ctx, r.Client, logger, dataplane)
+	res, _, err = ensurePodDisruptionBudgetForDataPlane(ctx, r.Client, logger, dataplane, len(zones.zones()))
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("could not ensure PodDisruptionBudget for DataPlane %s: %w", dpNn, err)
 	}
@@ -235,14 +367,41 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	if res, err := ensureDataPlaneZoneDistributionStatus(ctx, r.Client, dataplane, zones); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not ensure zone distribution status of DataPlane %s: %w", dpNn, err)
+	} else if !res.IsZero() {
+		return res, nil
+	}
+
+	var hybridRes ctrl.Result
+	if hybridCfg != nil {
+		log.Trace(logger, "ensuring DataPlane hybrid control plane connection status")
+		hybridRes, err = ensureDataPlaneHybridControlPlaneStatus(ctx, r.Client, logger,
+			hybridClusteringStatusReaderOrDefault(r.HybridClusteringStatusReader), dataplane, workload, hybridCfg,
+		)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not ensure hybrid control plane status of DataPlane %s: %w", dpNn, err)
+		}
+		if hybridRes.RequeueAfter == 0 {
+			return hybridRes, nil
+		}
+	}
+
+	log.Trace(logger, "ensuring DataPlane connection draining")
+	drainingRes, err := ensureDataPlaneDraining(ctx, r.Client, logger, activeConnectionsCounterOrDefault(r.ActiveConnectionsCounter), dataplane)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not ensure connection draining for DataPlane %s: %w", dpNn, err)
+	}
+
 	if res, err := ensureDataPlaneReadyStatus(ctx, r.Client, logger, dataplane, dataplane.Generation); err != nil {
 		return ctrl.Result{}, err
 	} else if !res.IsZero() {
-		return res, nil
+		// Keep tracking the draining progress as Pods are not watched.
+		return earliestRequeue(res, drainingRes), nil
 	}
 
 	log.Debug(logger, "reconciliation complete for DataPlane resource")
-	return ctrl.Result{}, nil
+	return earliestRequeue(hybridRes, drainingRes, maintenanceRes, versionChannelRes), nil
 }
 
 func (r *Reconciler) initSelectorInStatus(ctx context.Context, logger logr.Logger, dataplane *operatorv1beta1.DataPlane) error {
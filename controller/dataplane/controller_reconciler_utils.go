@@ -17,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/gateway-operator/controller/pkg/address"
+	dataplanepkg "github.com/kong/gateway-operator/controller/pkg/dataplane"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
@@ -63,6 +64,9 @@ func (r *Reconciler) ensureDataPlaneServiceStatus(
 // ensureDataPlaneAddressesStatus ensures that provided DataPlane's status addresses
 // are as expected and patches its status if there's a difference between the
 // current state and what's expected.
+// The addresses are taken from the ingress Service, unless the DataPlane's Pods
+// are run by a DaemonSet using the host network and the Service is not of
+// LoadBalancer type: the addresses of the Nodes running the Pods are used then.
 // It returns a boolean indicating if the patch has been triggered and an error.
 func (r *Reconciler) ensureDataPlaneAddressesStatus(
 	ctx context.Context,
@@ -70,9 +74,20 @@ func (r *Reconciler) ensureDataPlaneAddressesStatus(
 	dataplane *operatorv1beta1.DataPlane,
 	dataplaneService *corev1.Service,
 ) (bool, error) {
-	addresses, err := address.AddressesFromService(dataplaneService)
-	if err != nil {
-		return false, fmt.Errorf("failed getting addresses for service %s: %w", dataplaneService, err)
+	var (
+		addresses []operatorv1beta1.Address
+		err       error
+	)
+	if dataplanepkg.IsHostNetworkDaemonSetWorkload(dataplane) && dataplaneService.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		addresses, err = nodeAddressesForDataPlane(ctx, r.Client, dataplane)
+		if err != nil {
+			return false, err
+		}
+	} else {
+		addresses, err = address.AddressesFromService(dataplaneService)
+		if err != nil {
+			return false, fmt.Errorf("failed getting addresses for service %s: %w", dataplaneService, err)
+		}
 	}
 
 	// Compare the lengths prior to cmp.Equal() because cmp.Equal() will return
@@ -87,6 +102,45 @@ func (r *Reconciler) ensureDataPlaneAddressesStatus(
 	return false, nil
 }
 
+// nodeAddressesForDataPlane returns the addresses of the Nodes the DataPlane's
+// Pods are scheduled on.
+func nodeAddressesForDataPlane(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+) ([]operatorv1beta1.Address, error) {
+	if dataplane.Status.Selector == "" {
+		return nil, nil
+	}
+
+	var pods corev1.PodList
+	if err := cl.List(ctx, &pods,
+		client.InNamespace(dataplane.Namespace),
+		client.MatchingLabels{consts.OperatorLabelSelector: dataplane.Status.Selector},
+	); err != nil {
+		return nil, fmt.Errorf("failed listing Pods for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	nodeNames := make(map[string]struct{}, len(pods.Items))
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		nodeNames[pod.Spec.NodeName] = struct{}{}
+	}
+	if len(nodeNames) == 0 {
+		return nil, nil
+	}
+
+	var nodes corev1.NodeList
+	if err := cl.List(ctx, &nodes); err != nil {
+		return nil, fmt.Errorf("failed listing Nodes: %w", err)
+	}
+	return address.AddressesFromNodes(lo.Filter(nodes.Items, func(node corev1.Node, _ int) bool {
+		_, ok := nodeNames[node.Name]
+		return ok
+	})), nil
+}
+
 // ensureMappedConfigMapToKongPluginInstallationForDataPlane ensures that the KongPluginInstallation
 // resources referenced by the DataPlane are resolved and DataPlane is configured to use them.
 // During resolving for each DataPlane based on each instance of KongPluginInstallation
@@ -7,12 +7,14 @@ import (
 	"os"
 
 	"github.com/go-logr/logr"
+	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	dataplanepkg "github.com/kong/gateway-operator/controller/pkg/dataplane"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/internal/versions"
 	"github.com/kong/gateway-operator/pkg/consts"
@@ -118,8 +120,8 @@ func extractOutdatedDataPlaneIngressServiceAnnotations(
 
 // ensureDataPlaneReadyStatus ensures that the provided DataPlane gets an up to
 // date Ready status condition.
-// It sets the condition based on the readiness of DataPlane's Deployment and
-// its ingress Service receiving an address.
+// It sets the condition based on the readiness of DataPlane's workload (Deployment
+// or DaemonSet) and its ingress Service receiving an address.
 func ensureDataPlaneReadyStatus(
 	ctx context.Context,
 	cl client.Client,
@@ -133,14 +135,14 @@ func ensureDataPlaneReadyStatus(
 		return ctrl.Result{}, fmt.Errorf("failed getting DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
 	}
 
-	deployments, err := listDataPlaneLiveDeployments(ctx, cl, dataplane)
+	workloads, err := listDataPlaneLiveWorkloads(ctx, cl, dataplane)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed listing deployments for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+		return ctrl.Result{}, fmt.Errorf("failed listing workloads for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
 	}
 
-	switch len(deployments) {
+	switch len(workloads) {
 	case 0:
-		log.Debug(logger, "Deployment for DataPlane not present yet")
+		log.Debug(logger, "workload for DataPlane not present yet")
 
 		// Set Ready to false for dataplane as the underlying deployment is not ready.
 		k8sutils.SetCondition(
@@ -159,7 +161,7 @@ func ensureDataPlaneReadyStatus(
 		})
 		res, err := patchDataPlaneStatus(ctx, cl, logger, dataplane)
 		if err != nil {
-			return ctrl.Result{}, fmt.Errorf("failed patching status (Deployment not present) for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+			return ctrl.Result{}, fmt.Errorf("failed patching status (workload not present) for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
 		}
 		if res {
 			return ctrl.Result{}, nil
@@ -168,13 +170,13 @@ func ensureDataPlaneReadyStatus(
 	case 1: // Expect just 1.
 
 	default: // More than 1.
-		log.Info(logger, "expected only 1 Deployment for DataPlane")
+		log.Info(logger, "expected only 1 workload for DataPlane", "kind", workloads[0].kind)
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	deployment := deployments[0]
-	if _, ready := isDeploymentReady(deployment.Status); !ready {
-		log.Debug(logger, "Deployment for DataPlane not ready yet")
+	workload := workloads[0]
+	if _, ready := isDeploymentReady(workload.status); !ready {
+		log.Debug(logger, "workload for DataPlane not ready yet", "kind", workload.kind)
 
 		// Set Ready to false for dataplane as the underlying deployment is not ready.
 		k8sutils.SetCondition(
@@ -182,14 +184,14 @@ func ensureDataPlaneReadyStatus(
 				kcfgdataplane.ReadyType,
 				metav1.ConditionFalse,
 				kcfgdataplane.WaitingToBecomeReadyReason,
-				fmt.Sprintf("%s: Deployment %s is not ready yet", kcfgdataplane.WaitingToBecomeReadyMessage, deployment.Name),
+				fmt.Sprintf("%s: %s %s is not ready yet", kcfgdataplane.WaitingToBecomeReadyMessage, workload.kind, workload.name),
 				generation,
 			),
 			dataplane,
 		)
-		ensureDataPlaneReadinessStatus(dataplane, deployment.Status)
+		ensureDataPlaneReadinessStatus(dataplane, workload.status)
 		if _, err := patchDataPlaneStatus(ctx, cl, logger, dataplane); err != nil {
-			return ctrl.Result{}, fmt.Errorf("failed patching status (Deployment not ready) for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+			return ctrl.Result{}, fmt.Errorf("failed patching status (workload not ready) for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
 		}
 		return ctrl.Result{}, nil
 	}
@@ -214,7 +216,7 @@ func ensureDataPlaneReadyStatus(
 			),
 			dataplane,
 		)
-		ensureDataPlaneReadinessStatus(dataplane, deployment.Status)
+		ensureDataPlaneReadinessStatus(dataplane, workload.status)
 		_, err := patchDataPlaneStatus(ctx, cl, logger, dataplane)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed patching status (ingress Service not present) for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
@@ -243,7 +245,7 @@ func ensureDataPlaneReadyStatus(
 			),
 			dataplane,
 		)
-		ensureDataPlaneReadinessStatus(dataplane, deployment.Status)
+		ensureDataPlaneReadinessStatus(dataplane, workload.status)
 		_, err := patchDataPlaneStatus(ctx, cl, logger, dataplane)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed patching status (ingress Service not ready) for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
@@ -252,7 +254,7 @@ func ensureDataPlaneReadyStatus(
 	}
 
 	k8sutils.SetReadyWithGeneration(dataplane, generation)
-	ensureDataPlaneReadinessStatus(dataplane, deployment.Status)
+	ensureDataPlaneReadinessStatus(dataplane, workload.status)
 
 	if _, err := patchDataPlaneStatus(ctx, cl, logger, dataplane); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed patching status for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
@@ -277,6 +279,87 @@ func listDataPlaneLiveDeployments(
 	)
 }
 
+// dataPlaneWorkload describes the workload running the DataPlane's Pods.
+type dataPlaneWorkload struct {
+	kind string
+	name string
+	// status is the workload's status expressed as a Deployment status.
+	status appsv1.DeploymentStatus
+}
+
+// listDataPlaneLiveWorkloads lists the live workloads of the kind configured
+// for the DataPlane.
+func listDataPlaneLiveWorkloads(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+) ([]dataPlaneWorkload, error) {
+	if dataplanepkg.IsDaemonSetWorkload(dataplane) {
+		daemonSets, err := listDataPlaneDaemonSets(ctx, cl, dataplane, client.MatchingLabels{
+			consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValueLive,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return lo.Map(daemonSets, func(ds appsv1.DaemonSet, _ int) dataPlaneWorkload {
+			return dataPlaneWorkload{
+				kind: consts.DataPlaneWorkloadKindDaemonSet,
+				name: ds.Name,
+				status: appsv1.DeploymentStatus{
+					Replicas:          ds.Status.DesiredNumberScheduled,
+					ReadyReplicas:     ds.Status.NumberReady,
+					AvailableReplicas: ds.Status.NumberAvailable,
+				},
+			}
+		}), nil
+	}
+
+	deployments, err := listDataPlaneLiveDeployments(ctx, cl, dataplane)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(deployments, func(d appsv1.Deployment, _ int) dataPlaneWorkload {
+		return dataPlaneWorkload{
+			kind:   consts.DataPlaneWorkloadKindDeployment,
+			name:   d.Name,
+			status: d.Status,
+		}
+	}), nil
+}
+
+// listDataPlaneWorkloadPods lists the Pods of the provided DataPlane workload:
+// a Deployment or a DaemonSet.
+func listDataPlaneWorkloadPods(
+	ctx context.Context,
+	cl client.Client,
+	workload client.Object,
+) ([]corev1.Pod, error) {
+	var (
+		kind     string
+		selector *metav1.LabelSelector
+	)
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		kind, selector = consts.DataPlaneWorkloadKindDeployment, w.Spec.Selector
+	case *appsv1.DaemonSet:
+		kind, selector = consts.DataPlaneWorkloadKindDaemonSet, w.Spec.Selector
+	default:
+		return nil, fmt.Errorf("unsupported DataPlane workload type %T", workload)
+	}
+	if selector == nil {
+		return nil, nil
+	}
+
+	var pods corev1.PodList
+	if err := cl.List(ctx, &pods,
+		client.InNamespace(workload.GetNamespace()),
+		client.MatchingLabels(selector.MatchLabels),
+	); err != nil {
+		return nil, fmt.Errorf("failed listing Pods for %s %s: %w", kind, workload.GetName(), err)
+	}
+	return pods.Items, nil
+}
+
 func listDataPlaneLiveServices(
 	ctx context.Context,
 	cl client.Client,
@@ -310,3 +393,19 @@ func isDeploymentReady(deploymentStatus appsv1.DeploymentStatus) (metav1.Conditi
 		return metav1.ConditionFalse, false
 	}
 }
+
+// earliestRequeue returns the result which requeues the soonest among the
+// provided ones. Results requeuing right away take precedence over the ones
+// requeuing after a delay. It returns an empty result when none requeues.
+func earliestRequeue(results ...ctrl.Result) ctrl.Result {
+	var earliest ctrl.Result
+	for _, res := range results {
+		if res.Requeue && res.RequeueAfter == 0 {
+			return res
+		}
+		if res.RequeueAfter > 0 && (earliest.RequeueAfter == 0 || res.RequeueAfter < earliest.RequeueAfter) {
+			earliest = res
+		}
+	}
+	return earliest
+}
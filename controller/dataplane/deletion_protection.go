@@ -0,0 +1,33 @@
+package dataplane
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/deletionprotection"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// ensureDataPlaneDeletionProtection manages the deletion protection of the
+// provided DataPlane, blocking its deletion while it serves traffic.
+// DataPlanes managed by Gateways are protected through their Gateways so that
+// their deletion, e.g. when their Gateway's deletion is confirmed, is never blocked.
+func ensureDataPlaneDeletionProtection(
+	ctx context.Context,
+	cl client.Client,
+	recorder record.EventRecorder,
+	metrics deletionprotection.DataPlaneMetrics,
+	dataplane *operatorv1beta1.DataPlane,
+) (bool, ctrl.Result, error) {
+	return deletionprotection.Reconcile(ctx, cl, recorder, "DataPlane", dataplane, dataplane, func() (deletionprotection.Blockers, error) {
+		var blockers deletionprotection.Blockers
+		if !deletionprotection.IsManagedByGateway(dataplane) {
+			blockers.AddDataPlaneTraffic(metrics, dataplane)
+		}
+		return blockers, nil
+	})
+}
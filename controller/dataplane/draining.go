@@ -0,0 +1,371 @@
+package dataplane
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/dataplane"
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+const (
+	// DataPlaneConditionTypeDraining is the type of the DataPlane condition which
+	// is set when DataPlane's Pods or Deployments are being drained of active connections.
+	// It is removed once draining is done.
+	DataPlaneConditionTypeDraining kcfgconsts.ConditionType = "Draining"
+
+	// DataPlaneConditionReasonDrainingInProgress is the reason used with the
+	// Draining condition when draining is in progress.
+	DataPlaneConditionReasonDrainingInProgress kcfgconsts.ConditionReason = "DrainingInProgress"
+	// DataPlaneConditionReasonActiveConnectionsUnavailable is the reason used with
+	// the Draining condition when the active connections of some draining Pods
+	// cannot be read. Such Pods are considered as still draining until the
+	// draining timeout passes.
+	DataPlaneConditionReasonActiveConnectionsUnavailable kcfgconsts.ConditionReason = "ActiveConnectionsUnavailable"
+)
+
+const (
+	// drainingRequeueInterval is the interval in which the draining progress is checked.
+	drainingRequeueInterval = 5 * time.Second
+
+	// drainingEndpointsPropagationDelay is the time terminating Pods wait before
+	// initiating the graceful shutdown to let the removal of the Pod from the
+	// Service endpoints propagate, so that no new connections are routed to it.
+	drainingEndpointsPropagationDelay = 5 * time.Second
+
+	// drainingTerminationGracePeriodMargin is added to the termination grace period
+	// of draining Pods on top of the draining timeout and the endpoints propagation delay.
+	drainingTerminationGracePeriodMargin = 5 * time.Second
+)
+
+// connectionDrainingTimeout returns the connection draining timeout configured
+// for the DataPlane through consts.AnnotationDataPlaneConnectionDrainingTimeout.
+// It returns 0 when connection draining is not enabled.
+func connectionDrainingTimeout(dataplane *operatorv1beta1.DataPlane) (time.Duration, error) {
+	v, ok := dataplane.Annotations[consts.AnnotationDataPlaneConnectionDrainingTimeout]
+	if !ok {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q of %s annotation: %w", v, consts.AnnotationDataPlaneConnectionDrainingTimeout, err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("invalid value %q of %s annotation: must not be negative", v, consts.AnnotationDataPlaneConnectionDrainingTimeout)
+	}
+	return timeout, nil
+}
+
+// connectionDrainingDeploymentOpt configures DataPlane's Pods to wait for
+// the endpoints removal to propagate and then gracefully shut down Kong,
+// waiting up to the provided timeout for the active connections to be closed.
+func connectionDrainingDeploymentOpt(timeout time.Duration) k8sresources.DeploymentOpt {
+	return func(d *appsv1.Deployment) {
+		container := k8sutils.GetPodContainerByName(&d.Spec.Template.Spec, consts.DataPlaneProxyContainerName)
+		if container == nil {
+			return
+		}
+		container.Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{
+					Command: []string{
+						"/bin/sh",
+						"-c",
+						fmt.Sprintf("kong quit --wait=%d --timeout=%d",
+							int64(drainingEndpointsPropagationDelay.Seconds()),
+							int64(timeout.Seconds()),
+						),
+					},
+				},
+			},
+		}
+		d.Spec.Template.Spec.TerminationGracePeriodSeconds = lo.ToPtr(
+			int64((drainingEndpointsPropagationDelay + timeout + drainingTerminationGracePeriodMargin).Seconds()),
+		)
+	}
+}
+
+// ActiveConnectionsCounter returns the number of active connections handled by a DataPlane Pod.
+type ActiveConnectionsCounter interface {
+	ActiveConnections(ctx context.Context, pod *corev1.Pod) (int64, error)
+}
+
+// statusEndpointActiveConnectionsCounter is an ActiveConnectionsCounter which
+// uses the Kong status endpoint exposed by DataPlane Pods.
+type statusEndpointActiveConnectionsCounter struct {
+	httpClient *http.Client
+}
+
+func newStatusEndpointActiveConnectionsCounter() statusEndpointActiveConnectionsCounter {
+	return statusEndpointActiveConnectionsCounter{
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// activeConnectionsCounterOrDefault returns the provided ActiveConnectionsCounter
+// or the one using Kong status endpoint when nil.
+func activeConnectionsCounterOrDefault(c ActiveConnectionsCounter) ActiveConnectionsCounter {
+	if c == nil {
+		return newStatusEndpointActiveConnectionsCounter()
+	}
+	return c
+}
+
+// ActiveConnections returns the number of active connections of the provided Pod
+// as reported by the Kong status endpoint.
+func (c statusEndpointActiveConnectionsCounter) ActiveConnections(ctx context.Context, pod *corev1.Pod) (int64, error) {
+	if pod.Status.PodIP == "" {
+		return 0, nil
+	}
+	url := fmt.Sprintf("http://%s%s",
+		net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(consts.DataPlaneStatusPort)),
+		consts.DataPlaneStatusEndpoint,
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed getting status of Pod %s: %w", pod.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed getting status of Pod %s: unexpected status code %d", pod.Name, resp.StatusCode)
+	}
+
+	var status struct {
+		Server struct {
+			ConnectionsActive int64 `json:"connections_active"`
+		} `json:"server"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return 0, fmt.Errorf("failed decoding status of Pod %s: %w", pod.Name, err)
+	}
+	// Do not count the connection used for this very request.
+	return max(status.Server.ConnectionsActive-1, 0), nil
+}
+
+// startDrainingDeployment marks the provided live DataPlane Deployment as draining.
+// The ingress Services no longer select its Pods (they select the Pods of the
+// Deployment that replaced it) so the Pods are only left with the already
+// established connections.
+func startDrainingDeployment(ctx context.Context, cl client.Client, deployment *appsv1.Deployment) error {
+	old := deployment.DeepCopy()
+	deployment.Labels[consts.DataPlaneDeploymentStateLabel] = consts.DataPlaneStateLabelValueDraining
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[consts.AnnotationDataPlaneDrainingStartedAt] = time.Now().UTC().Format(time.RFC3339)
+	if err := cl.Patch(ctx, deployment, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed marking Deployment %s as draining: %w", deployment.Name, err)
+	}
+	return nil
+}
+
+// ensureDataPlaneDraining deletes the DataPlane's draining Deployments once their
+// Pods have no active connections left or the draining timeout has passed.
+// Pods whose active connections cannot be read are considered as still draining.
+// When the timeout has passed, the Pods are deleted with a grace period only
+// covering Kong's shutdown so that their preStop hook doesn't wait for the
+// timeout once more.
+// The draining progress (including the terminating Pods) is reflected in
+// the DataPlane's Draining status condition.
+func ensureDataPlaneDraining(
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	counter ActiveConnectionsCounter,
+	dp *operatorv1beta1.DataPlane,
+) (ctrl.Result, error) {
+	timeout, err := connectionDrainingTimeout(dp)
+	if err != nil {
+		log.Info(logger, "connection draining disabled", "reason", err.Error())
+	}
+
+	deployments, err := k8sutils.ListDeploymentsForOwner(ctx, cl, dp.Namespace, dp.UID,
+		client.MatchingLabels{
+			"app":                                dp.Name,
+			consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValueDraining,
+		},
+	)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed listing draining Deployments: %w", err)
+	}
+
+	var (
+		drainingDeployments int
+		activeConnections   int64
+		unavailablePods     int
+		unavailableErr      error
+	)
+	for i := range deployments {
+		deployment := &deployments[i]
+		conns, err := countActiveConnectionsForDeployment(ctx, cl, logger, counter, deployment)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		startedAt, err := time.Parse(time.RFC3339, deployment.Annotations[consts.AnnotationDataPlaneDrainingStartedAt])
+		if err != nil {
+			startedAt = deployment.CreationTimestamp.Time
+		}
+		drained := conns.active == 0 && len(conns.unavailable) == 0
+		if !drained && time.Now().Before(startedAt.Add(timeout)) {
+			log.Debug(logger, "waiting for draining Deployment's connections to be closed",
+				"deployment", deployment.Name, "active_connections", conns.active, "unavailable_pods", len(conns.unavailable),
+			)
+			drainingDeployments++
+			activeConnections += conns.active
+			unavailablePods += len(conns.unavailable)
+			if len(conns.unavailable) > 0 {
+				unavailableErr = conns.unavailable[len(conns.unavailable)-1]
+			}
+			continue
+		}
+
+		if !drained {
+			// The draining timeout has already been waited for.
+			for j := range conns.pods {
+				if err := cl.Delete(ctx, &conns.pods[j],
+					client.GracePeriodSeconds(int64(drainingTerminationGracePeriodMargin.Seconds())),
+				); client.IgnoreNotFound(err) != nil {
+					return ctrl.Result{}, fmt.Errorf("failed deleting Pod %s of draining Deployment %s: %w", conns.pods[j].Name, deployment.Name, err)
+				}
+			}
+		}
+
+		log.Debug(logger, "deleting drained Deployment", "deployment", deployment.Name,
+			"active_connections", conns.active, "unavailable_pods", len(conns.unavailable),
+		)
+		if err := dataplane.OwnedObjectPreDeleteHook(ctx, cl, deployment); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed executing pre delete hook: %w", err)
+		}
+		if err := cl.Delete(ctx, deployment); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed deleting drained Deployment %s: %w", deployment.Name, err)
+		}
+	}
+
+	var terminatingPods int
+	if timeout > 0 {
+		var pods corev1.PodList
+		if err := cl.List(ctx, &pods,
+			client.InNamespace(dp.Namespace),
+			client.MatchingLabelsSelector{Selector: dataPlanePodsSelector(dp.Name)},
+		); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed listing Pods: %w", err)
+		}
+		terminatingPods = lo.CountBy(pods.Items, func(p corev1.Pod) bool {
+			return !p.DeletionTimestamp.IsZero()
+		})
+	}
+
+	if drainingDeployments == 0 && terminatingPods == 0 {
+		if !k8sutils.HasCondition(DataPlaneConditionTypeDraining, dp) {
+			return ctrl.Result{}, nil
+		}
+		old := dp.DeepCopy()
+		dp.Status.Conditions = lo.Reject(dp.Status.Conditions, func(c metav1.Condition, _ int) bool {
+			return c.Type == string(DataPlaneConditionTypeDraining)
+		})
+		if err := cl.Status().Patch(ctx, dp, client.MergeFrom(old)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed removing %s condition: %w", DataPlaneConditionTypeDraining, err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	reason := DataPlaneConditionReasonDrainingInProgress
+	message := fmt.Sprintf("Draining %d Deployment(s) with %d active connection(s), %d Pod(s) terminating",
+		drainingDeployments, activeConnections, terminatingPods,
+	)
+	if unavailablePods > 0 {
+		reason = DataPlaneConditionReasonActiveConnectionsUnavailable
+		message += fmt.Sprintf(", active connections of %d Pod(s) unavailable: %v", unavailablePods, unavailableErr)
+	}
+	res, err := patch.StatusWithCondition(ctx, cl, dp,
+		DataPlaneConditionTypeDraining,
+		metav1.ConditionTrue,
+		reason,
+		message,
+	)
+	if err != nil || !res.IsZero() {
+		return res, err
+	}
+	// Terminating Pods trigger a reconciliation (see DataPlaneWatchBuilder)
+	// but the active connections are only polled so requeue to track them.
+	return ctrl.Result{RequeueAfter: drainingRequeueInterval}, nil
+}
+
+// dataPlanePodsSelector returns the label selector matching the Pods of
+// the DataPlane's Deployments: these carry the DataPlane's name in the "app"
+// label and the operator's selector label.
+func dataPlanePodsSelector(dataplaneName string) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{"app": dataplaneName}).
+		Add(*lo.Must(labels.NewRequirement(consts.OperatorLabelSelector, selection.Exists, nil)))
+}
+
+// drainingConnections holds the active connections of a draining Deployment's Pods.
+type drainingConnections struct {
+	// pods are the Pods of the Deployment.
+	pods []corev1.Pod
+	// active is the number of active connections of the Pods which could be queried.
+	active int64
+	// unavailable holds the errors of the Pods whose active connections cannot be read.
+	unavailable []error
+}
+
+// countActiveConnectionsForDeployment returns the active connections handled
+// by the Pods of the provided Deployment.
+func countActiveConnectionsForDeployment(
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	counter ActiveConnectionsCounter,
+	deployment *appsv1.Deployment,
+) (drainingConnections, error) {
+	if deployment.Spec.Selector == nil {
+		return drainingConnections{}, nil
+	}
+	var pods corev1.PodList
+	if err := cl.List(ctx, &pods,
+		client.InNamespace(deployment.Namespace),
+		client.MatchingLabels(deployment.Spec.Selector.MatchLabels),
+	); err != nil {
+		return drainingConnections{}, fmt.Errorf("failed listing Pods for Deployment %s: %w", deployment.Name, err)
+	}
+
+	conns := drainingConnections{pods: pods.Items}
+	for i := range pods.Items {
+		active, err := counter.ActiveConnections(ctx, &pods.Items[i])
+		if err != nil {
+			log.Debug(logger, "failed getting active connections of Pod", "pod", pods.Items[i].Name, "error", err.Error())
+			conns.unavailable = append(conns.unavailable, err)
+			continue
+		}
+		conns.active += active
+	}
+	return conns, nil
+}
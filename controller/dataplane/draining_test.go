@@ -0,0 +1,295 @@
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// fakeActiveConnectionsCounter returns the active connections of Pods by name.
+// Negative values make it fail.
+type fakeActiveConnectionsCounter map[string]int64
+
+func (c fakeActiveConnectionsCounter) ActiveConnections(_ context.Context, pod *corev1.Pod) (int64, error) {
+	if c[pod.Name] < 0 {
+		return 0, errors.New("connection refused")
+	}
+	return c[pod.Name], nil
+}
+
+func TestConnectionDrainingTimeout(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    time.Duration
+		expectedErr bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "valid timeout",
+			annotations: map[string]string{consts.AnnotationDataPlaneConnectionDrainingTimeout: "2m"},
+			expected:    2 * time.Minute,
+		},
+		{
+			name:        "invalid timeout",
+			annotations: map[string]string{consts.AnnotationDataPlaneConnectionDrainingTimeout: "2 minutes"},
+			expectedErr: true,
+		},
+		{
+			name:        "negative timeout",
+			annotations: map[string]string{consts.AnnotationDataPlaneConnectionDrainingTimeout: "-1s"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			timeout, err := connectionDrainingTimeout(&operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+			})
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, timeout)
+		})
+	}
+}
+
+func TestConnectionDrainingDeploymentOpt(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: consts.DataPlaneProxyContainerName},
+					},
+				},
+			},
+		},
+	}
+	connectionDrainingDeploymentOpt(time.Minute)(deployment)
+
+	container := deployment.Spec.Template.Spec.Containers[0]
+	require.NotNil(t, container.Lifecycle)
+	require.NotNil(t, container.Lifecycle.PreStop)
+	assert.Equal(t, []string{"/bin/sh", "-c", "kong quit --wait=5 --timeout=60"}, container.Lifecycle.PreStop.Exec.Command)
+	assert.Equal(t, lo.ToPtr(int64(70)), deployment.Spec.Template.Spec.TerminationGracePeriodSeconds)
+}
+
+func TestEnsureDataPlaneDraining(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+			Kind:       "DataPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+			UID:       "dp-uid",
+			Annotations: map[string]string{
+				consts.AnnotationDataPlaneConnectionDrainingTimeout: "1m",
+			},
+		},
+	}
+	drainingDeployment := func(name string, startedAt time.Time) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					"app":                                "dp",
+					consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValueDraining,
+				},
+				Annotations: map[string]string{
+					consts.AnnotationDataPlaneDrainingStartedAt: startedAt.UTC().Format(time.RFC3339),
+				},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "dp", consts.OperatorLabelSelector: name},
+				},
+			},
+		}
+		k8sutils.SetOwnerForObject(d, dataplane)
+		return d
+	}
+	pod := func(name, selector string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "dp", consts.OperatorLabelSelector: selector},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                string
+		objects             []client.Object
+		connections         fakeActiveConnectionsCounter
+		expectedDeleted     []string
+		expectedDeletedPods []string
+		expectedRemaining   []string
+		expectedRequeue     bool
+		expectedCondReason  string
+		expectedCondMessage string
+	}{
+		{
+			name: "nothing to drain",
+		},
+		{
+			name: "drained Deployment is deleted",
+			objects: []client.Object{
+				drainingDeployment("old", time.Now()),
+				pod("old-pod", "old"),
+			},
+			connections:     fakeActiveConnectionsCounter{"old-pod": 0},
+			expectedDeleted: []string{"old"},
+		},
+		{
+			name: "Deployment with active connections is kept",
+			objects: []client.Object{
+				drainingDeployment("old", time.Now()),
+				pod("old-pod-1", "old"),
+				pod("old-pod-2", "old"),
+			},
+			connections:         fakeActiveConnectionsCounter{"old-pod-1": 2, "old-pod-2": 1},
+			expectedRemaining:   []string{"old"},
+			expectedRequeue:     true,
+			expectedCondMessage: "Draining 1 Deployment(s) with 3 active connection(s), 0 Pod(s) terminating",
+		},
+		{
+			name: "Deployment with active connections past the timeout is deleted",
+			objects: []client.Object{
+				drainingDeployment("old", time.Now().Add(-2*time.Minute)),
+				pod("old-pod", "old"),
+			},
+			connections:         fakeActiveConnectionsCounter{"old-pod": 5},
+			expectedDeleted:     []string{"old"},
+			expectedDeletedPods: []string{"old-pod"},
+		},
+		{
+			name: "Deployment with unavailable active connections is kept",
+			objects: []client.Object{
+				drainingDeployment("old", time.Now()),
+				pod("old-pod-1", "old"),
+				pod("old-pod-2", "old"),
+			},
+			connections:         fakeActiveConnectionsCounter{"old-pod-1": -1, "old-pod-2": 0},
+			expectedRemaining:   []string{"old"},
+			expectedRequeue:     true,
+			expectedCondReason:  string(DataPlaneConditionReasonActiveConnectionsUnavailable),
+			expectedCondMessage: "Draining 1 Deployment(s) with 0 active connection(s), 0 Pod(s) terminating, active connections of 1 Pod(s) unavailable: connection refused",
+		},
+		{
+			name: "Deployment with unavailable active connections past the timeout is deleted",
+			objects: []client.Object{
+				drainingDeployment("old", time.Now().Add(-2*time.Minute)),
+				pod("old-pod", "old"),
+			},
+			connections:         fakeActiveConnectionsCounter{"old-pod": -1},
+			expectedDeleted:     []string{"old"},
+			expectedDeletedPods: []string{"old-pod"},
+		},
+		{
+			name: "terminating Pods are reported",
+			objects: []client.Object{
+				func() *corev1.Pod {
+					p := pod("terminating", "live")
+					p.DeletionTimestamp = lo.ToPtr(metav1.Now())
+					p.Finalizers = []string{"test"}
+					return p
+				}(),
+			},
+			expectedRequeue:     true,
+			expectedCondMessage: "Draining 0 Deployment(s) with 0 active connection(s), 1 Pod(s) terminating",
+		},
+		{
+			name: "terminating Pods not belonging to the DataPlane are ignored",
+			objects: []client.Object{
+				func() *corev1.Pod {
+					p := pod("terminating", "live")
+					delete(p.Labels, consts.OperatorLabelSelector)
+					p.DeletionTimestamp = lo.ToPtr(metav1.Now())
+					p.Finalizers = []string{"test"}
+					return p
+				}(),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			dp := dataplane.DeepCopy()
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(append(tc.objects, dp)...).
+				WithStatusSubresource(dp).
+				Build()
+
+			res, err := ensureDataPlaneDraining(ctx, cl, logr.Discard(), tc.connections, dp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRequeue, res.RequeueAfter > 0)
+
+			for _, name := range tc.expectedDeleted {
+				err := cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &appsv1.Deployment{})
+				assert.True(t, k8serrors.IsNotFound(err), "Deployment %s should be deleted", name)
+			}
+			for _, name := range tc.expectedDeletedPods {
+				err := cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &corev1.Pod{})
+				assert.True(t, k8serrors.IsNotFound(err), "Pod %s should be deleted", name)
+			}
+			for _, name := range tc.expectedRemaining {
+				require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &appsv1.Deployment{}))
+			}
+
+			require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(dp), dp))
+			cond, ok := k8sutils.GetCondition(DataPlaneConditionTypeDraining, dp)
+			if tc.expectedCondMessage == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+			assert.Equal(t, lo.Ternary(tc.expectedCondReason != "", tc.expectedCondReason, string(DataPlaneConditionReasonDrainingInProgress)), cond.Reason)
+			assert.Equal(t, tc.expectedCondMessage, cond.Message)
+
+			// Once draining is done the condition is removed.
+			for _, obj := range tc.objects {
+				require.NoError(t, client.IgnoreNotFound(cl.Delete(ctx, obj)))
+				if p, ok := obj.(*corev1.Pod); ok && len(p.Finalizers) > 0 {
+					old := p.DeepCopy()
+					p.Finalizers = nil
+					require.NoError(t, client.IgnoreNotFound(cl.Patch(ctx, p, client.MergeFrom(old))))
+				}
+			}
+			_, err = ensureDataPlaneDraining(ctx, cl, logr.Discard(), tc.connections, dp)
+			require.NoError(t, err)
+			require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(dp), dp))
+			assert.False(t, k8sutils.HasCondition(DataPlaneConditionTypeDraining, dp))
+		})
+	}
+}
@@ -0,0 +1,595 @@
+package dataplane
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/internal/utils/config"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+	kcfgkonnect "github.com/kong/kubernetes-configuration/api/konnect"
+)
+
+const (
+	// DataPlaneConditionTypeHybridControlPlaneConnected is the type of the DataPlane
+	// condition which reports whether the DataPlane Pods are connected to the
+	// self-hosted Kong control plane configured through the
+	// consts.AnnotationDataPlaneHybridControlPlane annotation.
+	// It is set only when the annotation is set on the DataPlane.
+	DataPlaneConditionTypeHybridControlPlaneConnected kcfgconsts.ConditionType = "HybridControlPlaneConnected"
+
+	// DataPlaneConditionReasonHybridControlPlaneConnected is the reason used with the
+	// HybridControlPlaneConnected condition when all the DataPlane Pods are connected
+	// to the control plane.
+	DataPlaneConditionReasonHybridControlPlaneConnected kcfgconsts.ConditionReason = "Connected"
+	// DataPlaneConditionReasonHybridControlPlaneNotConnected is the reason used with the
+	// HybridControlPlaneConnected condition when some of the DataPlane Pods are not
+	// connected to the control plane.
+	DataPlaneConditionReasonHybridControlPlaneNotConnected kcfgconsts.ConditionReason = "NotConnected"
+	// DataPlaneConditionReasonHybridControlPlaneInvalidConfiguration is the reason used with
+	// the HybridControlPlaneConnected condition when the control plane configuration is invalid.
+	DataPlaneConditionReasonHybridControlPlaneInvalidConfiguration kcfgconsts.ConditionReason = "InvalidConfiguration"
+	// DataPlaneConditionReasonHybridControlPlaneClusteringStatusUnavailable is the reason
+	// used with the HybridControlPlaneConnected condition when the connection status
+	// of the DataPlane Pods cannot be retrieved from the control plane's Admin API.
+	DataPlaneConditionReasonHybridControlPlaneClusteringStatusUnavailable kcfgconsts.ConditionReason = "ClusteringStatusUnavailable"
+)
+
+// Keys of the ConfigMap referenced through consts.AnnotationDataPlaneHybridControlPlane.
+const (
+	// hybridConfigKeyControlPlane is the host:port of the control plane's cluster listener. Required.
+	hybridConfigKeyControlPlane = consts.DataPlaneHybridConfigKeyControlPlane
+	// hybridConfigKeyServerName is the SNI used when connecting to the control plane.
+	hybridConfigKeyServerName = "cluster_server_name"
+	// hybridConfigKeyTelemetryEndpoint is the host:port of the control plane's telemetry listener.
+	hybridConfigKeyTelemetryEndpoint = consts.DataPlaneHybridConfigKeyTelemetryEndpoint
+	// hybridConfigKeyTelemetryServerName is the SNI used when connecting to the telemetry endpoint.
+	hybridConfigKeyTelemetryServerName = "cluster_telemetry_server_name"
+	// hybridConfigKeyMTLS is the cluster mTLS mode: "shared" (default) or "pki".
+	hybridConfigKeyMTLS = "cluster_mtls"
+	// hybridConfigKeyCertSecret is the name of the Secret holding the cluster
+	// certificate (tls.crt and tls.key). It's required in the "shared" mode.
+	// In the "pki" mode a certificate is issued for each DataPlane by the
+	// operator's cluster CA when it's not set.
+	hybridConfigKeyCertSecret = "cluster_cert_secret"
+	// hybridConfigKeyCACert is the PEM encoded CA certificate used to verify the
+	// control plane's certificate in the "pki" mode and its Admin API's certificate.
+	hybridConfigKeyCACert = "cluster_ca_cert"
+	// hybridConfigKeyAdminAPIURL is the URL of the control plane's Admin API used to
+	// retrieve the connection status of the DataPlane Pods.
+	hybridConfigKeyAdminAPIURL = "admin_api_url"
+	// hybridConfigKeyAdminTokenSecret is the name of the Secret holding the token
+	// (under the "token" key) sent to the control plane's Admin API.
+	hybridConfigKeyAdminTokenSecret = "admin_token_secret"
+)
+
+const (
+	hybridClusterMTLSShared = "shared"
+	hybridClusterMTLSPKI    = "pki"
+
+	// hybridAdminTokenSecretKey is the key of the admin token in the Secret
+	// referenced through the admin_token_secret key.
+	hybridAdminTokenSecretKey = "token"
+
+	// hybridDataPlaneCertPurpose is the consts.CertPurposeLabel value of the
+	// certificates issued for DataPlanes connecting to a control plane in the "pki" mode.
+	hybridDataPlaneCertPurpose = "hybrid-dataplane-cluster-cert"
+
+	// hybridStatusRequeueInterval is the interval in which the connection status
+	// of the DataPlane Pods is checked.
+	hybridStatusRequeueInterval = 30 * time.Second
+
+	// hybridDataPlaneLastSeenThreshold is the time since the last ping received
+	// by the control plane after which a DataPlane Pod is considered disconnected.
+	hybridDataPlaneLastSeenThreshold = 90 * time.Second
+)
+
+// errInvalidHybridControlPlaneConfiguration is returned when the hybrid control
+// plane configuration of a DataPlane is invalid.
+var errInvalidHybridControlPlaneConfiguration = errors.New("invalid hybrid control plane configuration")
+
+// HybridAdminAPIConfig is the configuration used to connect to the Admin API
+// of a self-hosted Kong control plane.
+type HybridAdminAPIConfig struct {
+	// URL is the Admin API's URL.
+	URL string
+	// Token is sent in the Kong-Admin-Token header when not empty.
+	Token string
+	// CACert is the PEM encoded CA certificate used to verify the Admin API's
+	// certificate. System roots are used when empty.
+	CACert []byte
+}
+
+// hybridControlPlaneConfig is the hybrid control plane configuration of a DataPlane.
+type hybridControlPlaneConfig struct {
+	configMapName string
+	cluster       config.HybridClusterConfig
+	// certSecretName is the name of the Secret holding the cluster certificate,
+	// empty when it has to be issued by the operator.
+	certSecretName string
+	// caCert is the PEM encoded CA certificate set in the ConfigMap.
+	caCert string
+	// adminAPI is nil when the Admin API URL is not configured.
+	adminAPI *HybridAdminAPIConfig
+}
+
+// hybridControlPlaneEnabled returns true when the DataPlane is configured to join
+// a self-hosted Kong control plane.
+func hybridControlPlaneEnabled(dataplane *operatorv1beta1.DataPlane) bool {
+	return dataplane.Annotations[consts.AnnotationDataPlaneHybridControlPlane] != ""
+}
+
+// getHybridControlPlaneConfig reads the hybrid control plane configuration
+// from the ConfigMap referenced by the DataPlane.
+// errInvalidHybridControlPlaneConfiguration is returned when the configuration
+// or the objects it references are missing or invalid.
+func getHybridControlPlaneConfig(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+) (*hybridControlPlaneConfig, error) {
+	cmName := dataplane.Annotations[consts.AnnotationDataPlaneHybridControlPlane]
+	var cm corev1.ConfigMap
+	if err := cl.Get(ctx, types.NamespacedName{Namespace: dataplane.Namespace, Name: cmName}, &cm); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return nil, fmt.Errorf("%w: ConfigMap %s not found", errInvalidHybridControlPlaneConfiguration, cmName)
+		}
+		return nil, fmt.Errorf("failed getting ConfigMap %s: %w", cmName, err)
+	}
+
+	cfg := &hybridControlPlaneConfig{
+		configMapName: cmName,
+		cluster: config.HybridClusterConfig{
+			ControlPlane:        cm.Data[hybridConfigKeyControlPlane],
+			ServerName:          cm.Data[hybridConfigKeyServerName],
+			TelemetryEndpoint:   cm.Data[hybridConfigKeyTelemetryEndpoint],
+			TelemetryServerName: cm.Data[hybridConfigKeyTelemetryServerName],
+			MTLS:                cm.Data[hybridConfigKeyMTLS],
+		},
+		certSecretName: cm.Data[hybridConfigKeyCertSecret],
+		caCert:         cm.Data[hybridConfigKeyCACert],
+	}
+	if cfg.cluster.ControlPlane == "" {
+		return nil, fmt.Errorf("%w: %s is required in ConfigMap %s",
+			errInvalidHybridControlPlaneConfiguration, hybridConfigKeyControlPlane, cmName,
+		)
+	}
+	switch cfg.cluster.MTLS {
+	case "":
+		cfg.cluster.MTLS = hybridClusterMTLSShared
+		fallthrough
+	case hybridClusterMTLSShared:
+		if cfg.certSecretName == "" {
+			return nil, fmt.Errorf("%w: %s is required in ConfigMap %s in the %q cluster mTLS mode",
+				errInvalidHybridControlPlaneConfiguration, hybridConfigKeyCertSecret, cmName, hybridClusterMTLSShared,
+			)
+		}
+	case hybridClusterMTLSPKI:
+	default:
+		return nil, fmt.Errorf("%w: invalid %s %q in ConfigMap %s, supported values: %s, %s",
+			errInvalidHybridControlPlaneConfiguration, hybridConfigKeyMTLS, cfg.cluster.MTLS, cmName,
+			hybridClusterMTLSShared, hybridClusterMTLSPKI,
+		)
+	}
+	if cfg.certSecretName != "" {
+		secret, err := getHybridControlPlaneSecret(ctx, cl, dataplane.Namespace, cfg.certSecretName)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range []string{consts.TLSCRT, consts.TLSKey} {
+			if len(secret.Data[key]) == 0 {
+				return nil, fmt.Errorf("%w: Secret %s has no %s key",
+					errInvalidHybridControlPlaneConfiguration, cfg.certSecretName, key,
+				)
+			}
+		}
+	}
+
+	if adminURL := cm.Data[hybridConfigKeyAdminAPIURL]; adminURL != "" {
+		if u, err := url.Parse(adminURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("%w: invalid %s %q in ConfigMap %s",
+				errInvalidHybridControlPlaneConfiguration, hybridConfigKeyAdminAPIURL, adminURL, cmName,
+			)
+		}
+		cfg.adminAPI = &HybridAdminAPIConfig{
+			URL:    adminURL,
+			CACert: []byte(cfg.caCert),
+		}
+		if tokenSecretName := cm.Data[hybridConfigKeyAdminTokenSecret]; tokenSecretName != "" {
+			secret, err := getHybridControlPlaneSecret(ctx, cl, dataplane.Namespace, tokenSecretName)
+			if err != nil {
+				return nil, err
+			}
+			token, ok := secret.Data[hybridAdminTokenSecretKey]
+			if !ok {
+				return nil, fmt.Errorf("%w: Secret %s has no %s key",
+					errInvalidHybridControlPlaneConfiguration, tokenSecretName, hybridAdminTokenSecretKey,
+				)
+			}
+			cfg.adminAPI.Token = string(token)
+		}
+	}
+
+	return cfg, nil
+}
+
+func getHybridControlPlaneSecret(ctx context.Context, cl client.Client, namespace, name string) (*corev1.Secret, error) {
+	var secret corev1.Secret
+	if err := cl.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &secret); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return nil, fmt.Errorf("%w: Secret %s not found", errInvalidHybridControlPlaneConfiguration, name)
+		}
+		return nil, fmt.Errorf("failed getting Secret %s: %w", name, err)
+	}
+	return &secret, nil
+}
+
+// applyDataPlaneHybridControlPlane configures the DataPlane's PodTemplateSpec
+// (in memory) to join the self-hosted Kong control plane configured through
+// the consts.AnnotationDataPlaneHybridControlPlane annotation, issuing the
+// DataPlane's cluster certificate when needed.
+//
+// It returns nil configuration when the annotation is not set, in which case
+// the HybridControlPlaneConnected condition is removed. When stop is true the
+// reconciliation should be stopped and the provided result returned.
+func applyDataPlaneHybridControlPlane(
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	dataplane *operatorv1beta1.DataPlane,
+	clusterCASecretNN types.NamespacedName,
+	keyConfig secrets.KeyConfig,
+) (cfg *hybridControlPlaneConfig, stop bool, res ctrl.Result, err error) {
+	if !hybridControlPlaneEnabled(dataplane) {
+		if !k8sutils.HasCondition(DataPlaneConditionTypeHybridControlPlaneConnected, dataplane) {
+			return nil, false, ctrl.Result{}, nil
+		}
+		old := dataplane.DeepCopy()
+		dataplane.Status.Conditions = lo.Reject(dataplane.Status.Conditions, func(c metav1.Condition, _ int) bool {
+			return c.Type == string(DataPlaneConditionTypeHybridControlPlaneConnected)
+		})
+		if err := cl.Status().Patch(ctx, dataplane, client.MergeFrom(old)); err != nil {
+			return nil, true, ctrl.Result{}, fmt.Errorf("failed removing %s condition: %w", DataPlaneConditionTypeHybridControlPlaneConnected, err)
+		}
+		return nil, false, ctrl.Result{}, nil
+	}
+
+	if _, konnectApplied := k8sutils.GetCondition(kcfgkonnect.KonnectExtensionAppliedType, dataplane); konnectApplied {
+		err = fmt.Errorf("%w: %s annotation cannot be used along with a KonnectExtension",
+			errInvalidHybridControlPlaneConfiguration, consts.AnnotationDataPlaneHybridControlPlane,
+		)
+	} else {
+		cfg, err = getHybridControlPlaneConfig(ctx, cl, dataplane)
+	}
+	if err != nil {
+		if !errors.Is(err, errInvalidHybridControlPlaneConfiguration) {
+			return nil, true, ctrl.Result{}, err
+		}
+		log.Debug(logger, "invalid hybrid control plane configuration", "error", err)
+		res, err := patch.StatusWithCondition(ctx, cl, dataplane,
+			DataPlaneConditionTypeHybridControlPlaneConnected,
+			metav1.ConditionFalse,
+			DataPlaneConditionReasonHybridControlPlaneInvalidConfiguration,
+			err.Error(),
+		)
+		if err != nil || !res.IsZero() {
+			return nil, true, res, err
+		}
+		// Referenced Secrets are not watched, check them again later.
+		return nil, true, ctrl.Result{RequeueAfter: hybridStatusRequeueInterval}, nil
+	}
+
+	certSecretName := cfg.certSecretName
+	if certSecretName == "" {
+		certRes, certSecret, err := secrets.EnsureCertificate(ctx,
+			dataplane,
+			fmt.Sprintf("%s.%s", dataplane.Name, dataplane.Namespace),
+			clusterCASecretNN,
+			[]certificatesv1.KeyUsage{
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageClientAuth,
+			},
+			keyConfig,
+			cl,
+			client.MatchingLabels{
+				consts.CertPurposeLabel: hybridDataPlaneCertPurpose,
+			},
+		)
+		if err != nil {
+			return nil, true, ctrl.Result{}, fmt.Errorf("failed ensuring hybrid cluster certificate: %w", err)
+		}
+		if certRes != op.Noop {
+			log.Debug(logger, "hybrid cluster certificate created/updated")
+			return nil, true, ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
+		}
+		certSecretName = certSecret.Name
+	}
+
+	if dataplane.Spec.Deployment.PodTemplateSpec == nil {
+		dataplane.Spec.Deployment.PodTemplateSpec = &corev1.PodTemplateSpec{}
+	}
+	d := k8sresources.Deployment(appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: *dataplane.Spec.Deployment.PodTemplateSpec,
+		},
+	})
+	if container := k8sutils.GetPodContainerByName(&d.Spec.Template.Spec, consts.DataPlaneProxyContainerName); container == nil {
+		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{
+			Name: consts.DataPlaneProxyContainerName,
+		})
+	}
+
+	d.WithVolume(corev1.Volume{
+		Name: consts.KongClusterCertVolume,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  certSecretName,
+				DefaultMode: lo.ToPtr(int32(420)),
+			},
+		},
+	})
+	d.WithVolumeMount(corev1.VolumeMount{
+		Name:      consts.KongClusterCertVolume,
+		MountPath: consts.KongClusterCertVolumeMountPath,
+		ReadOnly:  true,
+	}, consts.DataPlaneProxyContainerName)
+
+	switch {
+	case cfg.caCert != "":
+		d.WithVolume(corev1.Volume{
+			Name: consts.KongClusterCACertVolume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: cfg.configMapName},
+					Items: []corev1.KeyToPath{
+						{Key: hybridConfigKeyCACert, Path: consts.CACRT},
+					},
+					DefaultMode: lo.ToPtr(int32(420)),
+				},
+			},
+		})
+		d.WithVolumeMount(corev1.VolumeMount{
+			Name:      consts.KongClusterCACertVolume,
+			MountPath: consts.KongClusterCACertVolumeMountPath,
+			ReadOnly:  true,
+		}, consts.DataPlaneProxyContainerName)
+		cfg.cluster.CACertPath = consts.KongClusterCACertVolumeMountPath + "/" + consts.CACRT
+	case cfg.cluster.MTLS == hybridClusterMTLSPKI && cfg.certSecretName == "":
+		// The issued certificate's Secret holds the operator's cluster CA
+		// certificate which is expected to sign the control plane's certificate.
+		cfg.cluster.CACertPath = consts.KongClusterCertVolumeMountPath + "/" + consts.CACRT
+	}
+
+	config.FillContainerEnvs(nil, &d.Spec.Template, consts.DataPlaneProxyContainerName,
+		config.EnvVarMapToSlice(config.KongInHybridDefaults(cfg.cluster)),
+	)
+	dataplane.Spec.Deployment.PodTemplateSpec = &d.Spec.Template
+
+	return cfg, false, ctrl.Result{}, nil
+}
+
+// HybridClusteringStatusReader returns the time of the last ping received by
+// a self-hosted Kong control plane from each of its data planes, by hostname.
+type HybridClusteringStatusReader interface {
+	DataPlanesLastSeen(ctx context.Context, adminAPI HybridAdminAPIConfig) (map[string]time.Time, error)
+}
+
+// adminAPIHybridClusteringStatusReader is a HybridClusteringStatusReader which
+// uses the /clustering/data-planes endpoint of the control plane's Admin API.
+// It keeps one HTTP client per Admin API so that connections are reused across
+// reads. Clients are replaced when the CA certificate they trust changes.
+type adminAPIHybridClusteringStatusReader struct {
+	lock    sync.Mutex
+	clients map[string]adminAPIHTTPClient
+}
+
+// adminAPIHTTPClient is an HTTP client trusting caCert.
+type adminAPIHTTPClient struct {
+	caCert []byte
+	client *http.Client
+}
+
+// defaultHybridClusteringStatusReader is shared by the DataPlane reconcilers
+// which are not provided a HybridClusteringStatusReader.
+var defaultHybridClusteringStatusReader = &adminAPIHybridClusteringStatusReader{}
+
+// hybridClusteringStatusReaderOrDefault returns the provided HybridClusteringStatusReader
+// or the one using the control plane's Admin API when nil.
+func hybridClusteringStatusReaderOrDefault(r HybridClusteringStatusReader) HybridClusteringStatusReader {
+	if r == nil {
+		return defaultHybridClusteringStatusReader
+	}
+	return r
+}
+
+// httpClient returns the HTTP client for the provided Admin API.
+func (r *adminAPIHybridClusteringStatusReader) httpClient(adminAPI HybridAdminAPIConfig) (*http.Client, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	cached, ok := r.clients[adminAPI.URL]
+	if ok && bytes.Equal(cached.caCert, adminAPI.CACert) {
+		return cached.client, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(adminAPI.CACert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(adminAPI.CACert) {
+			return nil, errors.New("failed parsing CA certificate")
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	httpClient := &http.Client{
+		Transport: transport,
+		Timeout:   5 * time.Second,
+	}
+
+	if ok {
+		cached.client.CloseIdleConnections()
+	}
+	if r.clients == nil {
+		r.clients = make(map[string]adminAPIHTTPClient)
+	}
+	r.clients[adminAPI.URL] = adminAPIHTTPClient{
+		caCert: bytes.Clone(adminAPI.CACert),
+		client: httpClient,
+	}
+	return httpClient, nil
+}
+
+// DataPlanesLastSeen returns the time of the last ping received by the control
+// plane from each of its data planes as reported by its Admin API.
+func (r *adminAPIHybridClusteringStatusReader) DataPlanesLastSeen(
+	ctx context.Context, adminAPI HybridAdminAPIConfig,
+) (map[string]time.Time, error) {
+	httpClient, err := r.httpClient(adminAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := url.Parse(adminAPI.URL)
+	if err != nil {
+		return nil, err
+	}
+	next := base.JoinPath("clustering", "data-planes")
+	lastSeen := make(map[string]time.Time)
+	for next != nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if adminAPI.Token != "" {
+			req.Header.Set("Kong-Admin-Token", adminAPI.Token)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed listing data planes: %w", err)
+		}
+		var body struct {
+			Data []struct {
+				Hostname string `json:"hostname"`
+				LastSeen int64  `json:"last_seen"`
+			} `json:"data"`
+			Next *string `json:"next"`
+		}
+		err = func() error {
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("failed listing data planes: unexpected status code %d", resp.StatusCode)
+			}
+			return json.NewDecoder(resp.Body).Decode(&body)
+		}()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dp := range body.Data {
+			seen := time.Unix(dp.LastSeen, 0)
+			if seen.After(lastSeen[dp.Hostname]) {
+				lastSeen[dp.Hostname] = seen
+			}
+		}
+
+		next = nil
+		if body.Next != nil && *body.Next != "" {
+			// Kong returns the path of the next page, e.g. /clustering/data-planes?offset=...
+			if next, err = base.Parse(*body.Next); err != nil {
+				return nil, fmt.Errorf("failed parsing next page %q: %w", *body.Next, err)
+			}
+		}
+	}
+	return lastSeen, nil
+}
+
+// ensureDataPlaneHybridControlPlaneStatus reports whether the DataPlane Pods
+// are connected to the self-hosted Kong control plane in the DataPlane's
+// HybridControlPlaneConnected status condition. The Pods are matched with the
+// control plane's data planes by their hostname.
+// The returned result requeues the DataPlane to keep the status up to date.
+func ensureDataPlaneHybridControlPlaneStatus(
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	reader HybridClusteringStatusReader,
+	dataplane *operatorv1beta1.DataPlane,
+	workload client.Object,
+	cfg *hybridControlPlaneConfig,
+) (ctrl.Result, error) {
+	status, reason, msg := metav1.ConditionUnknown,
+		DataPlaneConditionReasonHybridControlPlaneClusteringStatusUnavailable,
+		fmt.Sprintf("%s is not set in ConfigMap %s", hybridConfigKeyAdminAPIURL, cfg.configMapName)
+
+	if cfg.adminAPI != nil {
+		lastSeen, err := reader.DataPlanesLastSeen(ctx, *cfg.adminAPI)
+		if err != nil {
+			log.Debug(logger, "failed getting hybrid control plane clustering status", "error", err)
+			msg = fmt.Sprintf("failed getting clustering status from %s: %v", cfg.adminAPI.URL, err)
+		} else {
+			pods, err := listDataPlaneWorkloadPods(ctx, cl, workload)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			var total, connected int
+			for _, pod := range pods {
+				if !pod.DeletionTimestamp.IsZero() {
+					continue
+				}
+				total++
+				// Pods using the host network report their Node's hostname.
+				hostname := pod.Name
+				if pod.Spec.HostNetwork {
+					hostname = pod.Spec.NodeName
+				}
+				if seen, ok := lastSeen[hostname]; ok && time.Since(seen) <= hybridDataPlaneLastSeenThreshold {
+					connected++
+				}
+			}
+			msg = fmt.Sprintf("%d/%d DataPlane Pods connected to the control plane", connected, total)
+			if total > 0 && connected == total {
+				status, reason = metav1.ConditionTrue, DataPlaneConditionReasonHybridControlPlaneConnected
+			} else {
+				status, reason = metav1.ConditionFalse, DataPlaneConditionReasonHybridControlPlaneNotConnected
+			}
+		}
+	}
+
+	res, err := patch.StatusWithCondition(ctx, cl, dataplane,
+		DataPlaneConditionTypeHybridControlPlaneConnected, status, reason, msg,
+	)
+	if err != nil || !res.IsZero() {
+		return res, err
+	}
+	return ctrl.Result{RequeueAfter: hybridStatusRequeueInterval}, nil
+}
@@ -0,0 +1,453 @@
+package dataplane
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	"github.com/kong/gateway-operator/test/helpers"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+	kcfgkonnect "github.com/kong/kubernetes-configuration/api/konnect"
+)
+
+func TestApplyDataPlaneHybridControlPlane(t *testing.T) {
+	ca := helpers.CreateCA(t)
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca",
+			Namespace: "kong-system",
+		},
+		Data: map[string][]byte{
+			"tls.crt": ca.CertPEM.Bytes(),
+			"tls.key": ca.KeyPEM.Bytes(),
+		},
+	}
+	clusterCertSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-cert",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"tls.crt": []byte("cert"),
+			"tls.key": []byte("key"),
+		},
+	}
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "admin-token",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"token": []byte("secret-token"),
+		},
+	}
+	configMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kong-cp",
+				Namespace: "default",
+			},
+			Data: data,
+		}
+	}
+	dataplane := &operatorv1beta1.DataPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+			Kind:       "DataPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+			UID:       "dp-uid",
+			Annotations: map[string]string{
+				consts.AnnotationDataPlaneHybridControlPlane: "kong-cp",
+			},
+		},
+	}
+	proxyEnv := func(t *testing.T, dp *operatorv1beta1.DataPlane) map[string]string {
+		t.Helper()
+		require.NotNil(t, dp.Spec.Deployment.PodTemplateSpec)
+		container := k8sutils.GetPodContainerByName(&dp.Spec.Deployment.PodTemplateSpec.Spec, consts.DataPlaneProxyContainerName)
+		require.NotNil(t, container)
+		env := make(map[string]string, len(container.Env))
+		for _, e := range container.Env {
+			env[e.Name] = e.Value
+		}
+		return env
+	}
+	volume := func(dp *operatorv1beta1.DataPlane, name string) *corev1.Volume {
+		for i, v := range dp.Spec.Deployment.PodTemplateSpec.Spec.Volumes {
+			if v.Name == name {
+				return &dp.Spec.Deployment.PodTemplateSpec.Spec.Volumes[i]
+			}
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		name       string
+		dataplane  func() *operatorv1beta1.DataPlane
+		objects    []client.Object
+		assertions func(t *testing.T, cl client.Client, dp *operatorv1beta1.DataPlane, cfg *hybridControlPlaneConfig)
+	}{
+		{
+			name: "shared mode",
+			objects: []client.Object{
+				configMap(map[string]string{
+					"cluster_control_plane":      "kong-cp.kong.svc:8005",
+					"cluster_telemetry_endpoint": "kong-cp.kong.svc:8006",
+					"cluster_cert_secret":        "cluster-cert",
+					"admin_api_url":              "https://kong-cp.kong.svc:8444",
+					"admin_token_secret":         "admin-token",
+				}),
+				clusterCertSecret,
+				tokenSecret,
+			},
+			assertions: func(t *testing.T, _ client.Client, dp *operatorv1beta1.DataPlane, cfg *hybridControlPlaneConfig) {
+				require.NotNil(t, cfg)
+				env := proxyEnv(t, dp)
+				assert.Equal(t, "data_plane", env["KONG_ROLE"])
+				assert.Equal(t, "shared", env["KONG_CLUSTER_MTLS"])
+				assert.Equal(t, "kong-cp.kong.svc:8005", env["KONG_CLUSTER_CONTROL_PLANE"])
+				assert.Equal(t, "kong-cp.kong.svc:8006", env["KONG_CLUSTER_TELEMETRY_ENDPOINT"])
+				assert.Equal(t, "/etc/secrets/kong-cluster-cert/tls.crt", env["KONG_CLUSTER_CERT"])
+				assert.NotContains(t, env, "KONG_CLUSTER_CA_CERT")
+
+				v := volume(dp, consts.KongClusterCertVolume)
+				require.NotNil(t, v)
+				assert.Equal(t, "cluster-cert", v.Secret.SecretName)
+
+				require.NotNil(t, cfg.adminAPI)
+				assert.Equal(t, "https://kong-cp.kong.svc:8444", cfg.adminAPI.URL)
+				assert.Equal(t, "secret-token", cfg.adminAPI.Token)
+			},
+		},
+		{
+			name: "pki mode with an issued certificate and CA from the ConfigMap",
+			objects: []client.Object{
+				configMap(map[string]string{
+					"cluster_control_plane": "kong-cp.kong.svc:8005",
+					"cluster_server_name":   "kong-cp.example.com",
+					"cluster_mtls":          "pki",
+					"cluster_ca_cert":       string(ca.CertPEM.Bytes()),
+				}),
+			},
+			assertions: func(t *testing.T, cl client.Client, dp *operatorv1beta1.DataPlane, cfg *hybridControlPlaneConfig) {
+				require.NotNil(t, cfg)
+				var certSecrets corev1.SecretList
+				require.NoError(t, cl.List(t.Context(), &certSecrets,
+					client.InNamespace("default"),
+					client.MatchingLabels{consts.CertPurposeLabel: hybridDataPlaneCertPurpose},
+				))
+				require.Len(t, certSecrets.Items, 1)
+
+				env := proxyEnv(t, dp)
+				assert.Equal(t, "pki", env["KONG_CLUSTER_MTLS"])
+				assert.Equal(t, "kong-cp.example.com", env["KONG_CLUSTER_SERVER_NAME"])
+				assert.Equal(t, "/etc/secrets/kong-cluster-ca-cert/ca.crt", env["KONG_CLUSTER_CA_CERT"])
+
+				v := volume(dp, consts.KongClusterCertVolume)
+				require.NotNil(t, v)
+				assert.Equal(t, certSecrets.Items[0].Name, v.Secret.SecretName)
+				v = volume(dp, consts.KongClusterCACertVolume)
+				require.NotNil(t, v)
+				assert.Equal(t, "kong-cp", v.ConfigMap.Name)
+				assert.Nil(t, cfg.adminAPI)
+			},
+		},
+		{
+			name: "missing ConfigMap",
+			assertions: func(t *testing.T, _ client.Client, dp *operatorv1beta1.DataPlane, cfg *hybridControlPlaneConfig) {
+				assert.Nil(t, cfg)
+				cond, ok := k8sutils.GetCondition(DataPlaneConditionTypeHybridControlPlaneConnected, dp)
+				require.True(t, ok)
+				assert.Equal(t, metav1.ConditionFalse, cond.Status)
+				assert.EqualValues(t, DataPlaneConditionReasonHybridControlPlaneInvalidConfiguration, cond.Reason)
+			},
+		},
+		{
+			name: "shared mode without a certificate",
+			objects: []client.Object{
+				configMap(map[string]string{
+					"cluster_control_plane": "kong-cp.kong.svc:8005",
+				}),
+			},
+			assertions: func(t *testing.T, _ client.Client, dp *operatorv1beta1.DataPlane, cfg *hybridControlPlaneConfig) {
+				assert.Nil(t, cfg)
+				cond, ok := k8sutils.GetCondition(DataPlaneConditionTypeHybridControlPlaneConnected, dp)
+				require.True(t, ok)
+				assert.EqualValues(t, DataPlaneConditionReasonHybridControlPlaneInvalidConfiguration, cond.Reason)
+				assert.Contains(t, cond.Message, "cluster_cert_secret is required")
+			},
+		},
+		{
+			name: "combined with a KonnectExtension",
+			dataplane: func() *operatorv1beta1.DataPlane {
+				dp := dataplane.DeepCopy()
+				k8sutils.SetCondition(k8sutils.NewCondition(kcfgkonnect.KonnectExtensionAppliedType, metav1.ConditionTrue, "Applied", ""), dp)
+				return dp
+			},
+			objects: []client.Object{
+				configMap(map[string]string{
+					"cluster_control_plane": "kong-cp.kong.svc:8005",
+					"cluster_cert_secret":   "cluster-cert",
+				}),
+				clusterCertSecret,
+			},
+			assertions: func(t *testing.T, _ client.Client, dp *operatorv1beta1.DataPlane, cfg *hybridControlPlaneConfig) {
+				assert.Nil(t, cfg)
+				cond, ok := k8sutils.GetCondition(DataPlaneConditionTypeHybridControlPlaneConnected, dp)
+				require.True(t, ok)
+				assert.EqualValues(t, DataPlaneConditionReasonHybridControlPlaneInvalidConfiguration, cond.Reason)
+			},
+		},
+		{
+			name: "condition is removed when the annotation is not set",
+			dataplane: func() *operatorv1beta1.DataPlane {
+				dp := dataplane.DeepCopy()
+				dp.Annotations = nil
+				k8sutils.SetCondition(k8sutils.NewCondition(DataPlaneConditionTypeHybridControlPlaneConnected, metav1.ConditionTrue, "Connected", ""), dp)
+				return dp
+			},
+			assertions: func(t *testing.T, _ client.Client, dp *operatorv1beta1.DataPlane, cfg *hybridControlPlaneConfig) {
+				assert.Nil(t, cfg)
+				assert.False(t, k8sutils.HasCondition(DataPlaneConditionTypeHybridControlPlaneConnected, dp))
+				assert.Nil(t, dp.Spec.Deployment.PodTemplateSpec)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dp := dataplane.DeepCopy()
+			if tc.dataplane != nil {
+				dp = tc.dataplane()
+			}
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(append(tc.objects, dp, caSecret)...).
+				WithStatusSubresource(dp).
+				Build()
+
+			var (
+				cfg  *hybridControlPlaneConfig
+				stop bool
+				err  error
+			)
+			// The certificate is issued in the first pass when needed.
+			for range 2 {
+				cfg, stop, _, err = applyDataPlaneHybridControlPlane(t.Context(), cl, logr.Discard(), dp,
+					client.ObjectKeyFromObject(caSecret), secrets.KeyConfig{Type: x509.ECDSA},
+				)
+				require.NoError(t, err)
+				if !stop {
+					break
+				}
+			}
+			assert.Equal(t, cfg == nil && hybridControlPlaneEnabled(dp), stop)
+			require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dp), &operatorv1beta1.DataPlane{}))
+			tc.assertions(t, cl, dp, cfg)
+		})
+	}
+}
+
+type fakeHybridClusteringStatusReader struct {
+	lastSeen map[string]time.Time
+	err      error
+}
+
+func (r fakeHybridClusteringStatusReader) DataPlanesLastSeen(context.Context, HybridAdminAPIConfig) (map[string]time.Time, error) {
+	return r.lastSeen, r.err
+}
+
+func TestEnsureDataPlaneHybridControlPlaneStatus(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+			Kind:       "DataPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp-deployment",
+			Namespace: "default",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "dp"},
+			},
+		},
+	}
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "dp"},
+			},
+		}
+	}
+	adminAPI := &HybridAdminAPIConfig{URL: "https://kong-cp.kong.svc:8444"}
+
+	testCases := []struct {
+		name            string
+		cfg             *hybridControlPlaneConfig
+		reader          fakeHybridClusteringStatusReader
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "all Pods connected",
+			cfg:  &hybridControlPlaneConfig{adminAPI: adminAPI},
+			reader: fakeHybridClusteringStatusReader{
+				lastSeen: map[string]time.Time{"pod-1": time.Now(), "pod-2": time.Now().Add(-time.Minute)},
+			},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  string(DataPlaneConditionReasonHybridControlPlaneConnected),
+			expectedMessage: "2/2 DataPlane Pods connected to the control plane",
+		},
+		{
+			name: "Pod not seen recently",
+			cfg:  &hybridControlPlaneConfig{adminAPI: adminAPI},
+			reader: fakeHybridClusteringStatusReader{
+				lastSeen: map[string]time.Time{"pod-1": time.Now(), "pod-2": time.Now().Add(-5 * time.Minute)},
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  string(DataPlaneConditionReasonHybridControlPlaneNotConnected),
+			expectedMessage: "1/2 DataPlane Pods connected to the control plane",
+		},
+		{
+			name: "clustering status cannot be retrieved",
+			cfg:  &hybridControlPlaneConfig{adminAPI: adminAPI},
+			reader: fakeHybridClusteringStatusReader{
+				err: errors.New("connection refused"),
+			},
+			expectedStatus:  metav1.ConditionUnknown,
+			expectedReason:  string(DataPlaneConditionReasonHybridControlPlaneClusteringStatusUnavailable),
+			expectedMessage: "failed getting clustering status from https://kong-cp.kong.svc:8444: connection refused",
+		},
+		{
+			name:            "Admin API not configured",
+			cfg:             &hybridControlPlaneConfig{configMapName: "kong-cp"},
+			expectedStatus:  metav1.ConditionUnknown,
+			expectedReason:  string(DataPlaneConditionReasonHybridControlPlaneClusteringStatusUnavailable),
+			expectedMessage: "admin_api_url is not set in ConfigMap kong-cp",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dp := dataplane.DeepCopy()
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(dp, pod("pod-1"), pod("pod-2")).
+				WithStatusSubresource(dp).
+				Build()
+
+			_, err := ensureDataPlaneHybridControlPlaneStatus(t.Context(), cl, logr.Discard(), tc.reader, dp, deployment, tc.cfg)
+			require.NoError(t, err)
+			res, err := ensureDataPlaneHybridControlPlaneStatus(t.Context(), cl, logr.Discard(), tc.reader, dp, deployment, tc.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, hybridStatusRequeueInterval, res.RequeueAfter, "status should be checked periodically")
+
+			require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dp), dp))
+			cond, ok := k8sutils.GetCondition(DataPlaneConditionTypeHybridControlPlaneConnected, dp)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedStatus, cond.Status)
+			assert.Equal(t, tc.expectedReason, cond.Reason)
+			assert.Equal(t, tc.expectedMessage, cond.Message)
+		})
+	}
+}
+
+func TestAdminAPIHybridClusteringStatusReader(t *testing.T) {
+	lastSeen := time.Now().Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Kong-Admin-Token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("offset") {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{
+					{"hostname": "pod-1", "last_seen": lastSeen.Unix()},
+				},
+				"next": "/prefix/clustering/data-planes?offset=page-2",
+			})
+		case "page-2":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{
+					{"hostname": "pod-2", "last_seen": lastSeen.Add(-time.Minute).Unix()},
+				},
+				"next": nil,
+			})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	reader := hybridClusteringStatusReaderOrDefault(nil)
+	seen, err := reader.DataPlanesLastSeen(t.Context(), HybridAdminAPIConfig{
+		URL:   server.URL + "/prefix",
+		Token: "token",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{
+		"pod-1": lastSeen,
+		"pod-2": lastSeen.Add(-time.Minute),
+	}, seen)
+
+	_, err = reader.DataPlanesLastSeen(t.Context(), HybridAdminAPIConfig{URL: server.URL})
+	require.Error(t, err)
+}
+
+func TestAdminAPIHybridClusteringStatusReaderReusesHTTPClients(t *testing.T) {
+	r := &adminAPIHybridClusteringStatusReader{}
+	adminAPI := HybridAdminAPIConfig{URL: "https://cp.example.com:8444"}
+
+	c1, err := r.httpClient(adminAPI)
+	require.NoError(t, err)
+	c2, err := r.httpClient(adminAPI)
+	require.NoError(t, err)
+	assert.Same(t, c1, c2, "the client should be reused for the same Admin API")
+
+	other, err := r.httpClient(HybridAdminAPIConfig{URL: "https://other.example.com:8444"})
+	require.NoError(t, err)
+	assert.NotSame(t, c1, other, "each Admin API should have its own client")
+
+	_, err = r.httpClient(HybridAdminAPIConfig{URL: adminAPI.URL, CACert: []byte("not a certificate")})
+	require.Error(t, err)
+
+	adminAPI.CACert = helpers.CreateCA(t).CertPEM.Bytes()
+	c3, err := r.httpClient(adminAPI)
+	require.NoError(t, err)
+	assert.NotSame(t, c1, c3, "the client should be replaced when the CA certificate changes")
+	c4, err := r.httpClient(adminAPI)
+	require.NoError(t, err)
+	assert.Same(t, c3, c4)
+}
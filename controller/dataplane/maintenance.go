@@ -0,0 +1,51 @@
+package dataplane
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/maintenance"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// ensureDataPlanePendingMaintenanceStatus sets the DataPlane's PendingMaintenance
+// condition while changes to its Pod template are held until its maintenance
+// window opens at nextOpen, and removes it once nothing is held anymore.
+// While changes are held, the DataPlane is requeued for when the window opens.
+func ensureDataPlanePendingMaintenanceStatus(
+	ctx context.Context,
+	cl client.Client,
+	dp *operatorv1beta1.DataPlane,
+	held bool,
+	nextOpen time.Time,
+) (ctrl.Result, error) {
+	if !held {
+		if !k8sutils.HasCondition(maintenance.ConditionType, dp) {
+			return ctrl.Result{}, nil
+		}
+		old := dp.DeepCopy()
+		dp.Status.Conditions = lo.Reject(dp.Status.Conditions, func(c metav1.Condition, _ int) bool {
+			return c.Type == string(maintenance.ConditionType)
+		})
+		if err := cl.Status().Patch(ctx, dp, client.MergeFrom(old)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed removing %s condition: %w", maintenance.ConditionType, err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	res, _, err := patch.StatusWithConditions(ctx, cl, dp, maintenance.PendingCondition(nextOpen, dp.Generation))
+	if err != nil || !res.IsZero() {
+		return res, err
+	}
+	// Nothing changes when the window opens so requeue to roll out the held changes.
+	return ctrl.Result{RequeueAfter: max(time.Until(nextOpen), time.Second)}, nil
+}
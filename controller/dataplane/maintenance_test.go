@@ -0,0 +1,131 @@
+package dataplane
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/controller/pkg/maintenance"
+	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestDeploymentBuilderHoldPodTemplateChanges(t *testing.T) {
+	const (
+		validateDataPlaneImage = false
+		enforceConfig          = false
+	)
+
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+			UID:       "dp-uid",
+		},
+		Spec: operatorv1beta1.DataPlaneSpec{
+			DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+				Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+					DeploymentOptions: operatorv1beta1.DeploymentOptions{
+						PodTemplateSpec: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name:  consts.DataPlaneProxyContainerName,
+										Image: "kong:3.9",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(dataplane).
+		Build()
+	newBuilder := func(hold bool) *DeploymentBuilder {
+		return NewDeploymentBuilder(logr.Discard(), cl).
+			WithClusterCertificate("certificate").
+			WithPodTemplateChangesHeld(hold)
+	}
+	proxyImage := func() string {
+		deployments, err := k8sutils.ListDeploymentsForOwner(t.Context(), cl, dataplane.Namespace, dataplane.UID)
+		require.NoError(t, err)
+		require.Len(t, deployments, 1)
+		return k8sutils.GetPodContainerByName(&deployments[0].Spec.Template.Spec, consts.DataPlaneProxyContainerName).Image
+	}
+
+	builder := newBuilder(true)
+	_, res, err := builder.BuildAndDeploy(t.Context(), dataplane, enforceConfig, validateDataPlaneImage)
+	require.NoError(t, err)
+	require.Equal(t, op.Created, res, "creation is never held")
+	require.False(t, builder.PodTemplateChangesHeld())
+	require.Equal(t, "kong:3.9", proxyImage())
+
+	dataplane.Spec.Deployment.PodTemplateSpec.Spec.Containers[0].Image = "kong:3.10"
+
+	t.Run("Pod template changes are held", func(t *testing.T) {
+		builder := newBuilder(true)
+		for range 2 {
+			_, _, err := builder.BuildAndDeploy(t.Context(), dataplane, enforceConfig, validateDataPlaneImage)
+			require.NoError(t, err)
+			require.True(t, builder.PodTemplateChangesHeld())
+			require.Equal(t, "kong:3.9", proxyImage())
+		}
+	})
+
+	t.Run("held Pod template changes are applied once not held anymore", func(t *testing.T) {
+		builder := newBuilder(false)
+		_, res, err := builder.BuildAndDeploy(t.Context(), dataplane, enforceConfig, validateDataPlaneImage)
+		require.NoError(t, err)
+		require.Equal(t, op.Updated, res)
+		require.False(t, builder.PodTemplateChangesHeld())
+		require.Equal(t, "kong:3.10", proxyImage())
+	})
+}
+
+func TestEnsureDataPlanePendingMaintenanceStatus(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "dp",
+			Namespace:  "default",
+			Generation: 2,
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(dataplane).
+		WithStatusSubresource(dataplane).
+		Build()
+	nextOpen := time.Now().Add(time.Hour)
+
+	res, err := ensureDataPlanePendingMaintenanceStatus(t.Context(), cl, dataplane, true, nextOpen)
+	require.NoError(t, err)
+	require.InDelta(t, time.Hour, res.RequeueAfter, float64(time.Minute))
+
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), dataplane))
+	condition, ok := k8sutils.GetCondition(maintenance.ConditionType, dataplane)
+	require.True(t, ok)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Equal(t, string(maintenance.ReasonWaitingForMaintenanceWindow), condition.Reason)
+	require.Equal(t, int64(2), condition.ObservedGeneration)
+
+	res, err = ensureDataPlanePendingMaintenanceStatus(t.Context(), cl, dataplane, false, time.Time{})
+	require.NoError(t, err)
+	require.True(t, res.IsZero())
+
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), dataplane))
+	require.False(t, k8sutils.HasCondition(maintenance.ConditionType, dataplane))
+}
@@ -0,0 +1,267 @@
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dataplanepkg "github.com/kong/gateway-operator/controller/pkg/dataplane"
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/maintenance"
+	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sreduce "github.com/kong/gateway-operator/pkg/utils/kubernetes/reduce"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// BuildAndDeployDaemonSet builds and deploys a DataPlane DaemonSet, or reduces
+// DaemonSets if there are more than one. The DaemonSet is generated from the same
+// Deployment as BuildAndDeploy would deploy, including the callbacks and the user
+// PodTemplateSpec patches, so that both workload kinds run the same Pods.
+// Live Deployments left over from a previous workload kind are deleted once the
+// DaemonSet reports ready Pods, so that the DataPlane keeps serving traffic while
+// switching workload kinds.
+// It returns the DaemonSet if it created or updated one, or nil if it needed to
+// reduce.
+func (d *DeploymentBuilder) BuildAndDeployDaemonSet(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+	enforceConfig bool,
+	validateDataPlaneImage bool,
+) (*appsv1.DaemonSet, op.Result, error) {
+	d.podTemplateChangesHeld = false
+	if err := d.runBeforeCallbacks(ctx, dataplane); err != nil {
+		return nil, op.Noop, err
+	}
+
+	// if there is more than one DaemonSet, delete the extras
+	reduced, existingDaemonSet, err := listOrReduceDataPlaneDaemonSets(ctx, d.client, dataplane, d.additionalLabels)
+	if err != nil {
+		return nil, op.Noop, fmt.Errorf("failed listing existing DaemonSets: %w", err)
+	}
+	if reduced {
+		return nil, op.Noop, nil
+	}
+
+	desiredDeployment, err := d.generateDesiredDeployment(ctx, dataplane, validateDataPlaneImage)
+	if err != nil {
+		return nil, op.Noop, err
+	}
+	desiredDaemonSet := k8sresources.GenerateDaemonSetFromDeployment(desiredDeployment.Unwrap())
+
+	// push the complete DaemonSet to Kubernetes
+	res, daemonSet, held, err := reconcileDataPlaneDaemonSet(ctx, d.client, d.logger, enforceConfig,
+		d.holdPodTemplateChanges, dataplane, existingDaemonSet, desiredDaemonSet)
+	if err != nil {
+		return nil, op.Noop, err
+	}
+	d.podTemplateChangesHeld = held
+
+	// the DataPlane's Pods can be run by a single kind of workload at a time
+	if daemonSet.Status.NumberReady > 0 {
+		if err := deleteDataPlaneDeployments(ctx, d.client, dataplane, d.additionalLabels); err != nil {
+			return nil, op.Noop, err
+		}
+	}
+
+	return daemonSet, res, nil
+}
+
+// listOrReduceDataPlaneDaemonSets lists existing DataPlane DaemonSets. If only one is present, it returns it. If
+// multiple are present, it reduces them to one and notifies the caller it reduced, so that the caller can try its
+// operation again once there's only a single DaemonSet to work with.
+func listOrReduceDataPlaneDaemonSets(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+	additionalLabels client.MatchingLabels,
+) (reduced bool, daemonSet *appsv1.DaemonSet, err error) {
+	daemonSets, err := listDataPlaneDaemonSets(ctx, cl, dataplane, additionalLabels)
+	if err != nil {
+		return false, nil, err
+	}
+
+	count := len(daemonSets)
+	if count > 1 {
+		if err := k8sreduce.ReduceDaemonSets(ctx, cl, daemonSets, dataplanepkg.OwnedObjectPreDeleteHook); err != nil {
+			return false, nil, err
+		}
+		return true, nil, errors.New("number of daemonsets reduced")
+	}
+	if count == 0 {
+		return false, nil, nil
+	}
+
+	return false, &daemonSets[0], nil
+}
+
+func listDataPlaneDaemonSets(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+	additionalLabels client.MatchingLabels,
+) ([]appsv1.DaemonSet, error) {
+	matchingLabels := k8sresources.GetManagedLabelForOwner(dataplane)
+	for k, v := range additionalLabels {
+		matchingLabels[k] = v
+	}
+
+	daemonSets, err := k8sutils.ListDaemonSetsForOwner(
+		ctx,
+		cl,
+		dataplane.Namespace,
+		dataplane.UID,
+		matchingLabels,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing DaemonSets for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	return daemonSets, nil
+}
+
+// deleteDataPlaneDaemonSets deletes the DataPlane DaemonSets matching the
+// provided labels.
+func deleteDataPlaneDaemonSets(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+	additionalLabels client.MatchingLabels,
+) error {
+	daemonSets, err := listDataPlaneDaemonSets(ctx, cl, dataplane, additionalLabels)
+	if err != nil {
+		return err
+	}
+	for i := range daemonSets {
+		if err := deleteDataPlaneOwnedObject(ctx, cl, &daemonSets[i]); err != nil {
+			return fmt.Errorf("failed deleting DaemonSet %s: %w", daemonSets[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// deleteDataPlaneDeployments deletes the DataPlane Deployments matching the
+// provided labels.
+func deleteDataPlaneDeployments(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+	additionalLabels client.MatchingLabels,
+) error {
+	matchingLabels := k8sresources.GetManagedLabelForOwner(dataplane)
+	for k, v := range additionalLabels {
+		matchingLabels[k] = v
+	}
+
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		cl,
+		dataplane.Namespace,
+		dataplane.UID,
+		matchingLabels,
+	)
+	if err != nil {
+		return fmt.Errorf("failed listing Deployments for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	for i := range deployments {
+		if err := deleteDataPlaneOwnedObject(ctx, cl, &deployments[i]); err != nil {
+			return fmt.Errorf("failed deleting Deployment %s: %w", deployments[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// deleteDataPlaneOwnedObject removes the finalizer protecting the provided
+// DataPlane owned object and deletes it.
+func deleteDataPlaneOwnedObject(ctx context.Context, cl client.Client, obj client.Object) error {
+	if err := dataplanepkg.OwnedObjectPreDeleteHook(ctx, cl, obj); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	return client.IgnoreNotFound(cl.Delete(ctx, obj))
+}
+
+// reconcileDataPlaneDaemonSet takes any existing DataPlane DaemonSet and a desired DataPlane DaemonSet and
+// reconciles the existing state to the desired state by either updating an existing DaemonSet, creating a new one,
+// or doing nothing.
+// When holdPodTemplateChanges is set, the Pod template of an existing DaemonSet is left untouched and the returned
+// held flag reports whether it differs from the desired one.
+func reconcileDataPlaneDaemonSet(
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	enforceConfig bool,
+	holdPodTemplateChanges bool,
+	dataplane *operatorv1beta1.DataPlane,
+	existing *appsv1.DaemonSet,
+	desired *appsv1.DaemonSet,
+) (res op.Result, daemonSet *appsv1.DaemonSet, held bool, err error) {
+	if existing != nil {
+
+		// If the enforceConfig flag is not set, we compare the spec hash of the
+		// existing DaemonSet with the spec hash of the desired DaemonSet. If
+		// the hashes and the propagated metadata match, we skip the update.
+		if !enforceConfig {
+			match, err := k8sresources.SpecHashMatchesAnnotation(dataplane.Spec, existing)
+			if err != nil {
+				return op.Noop, nil, false, err
+			}
+			if match && k8sresources.PropagatedMetadataMatches(existing, desired) {
+				log.Debug(logger, "DataPlane DaemonSet spec hash matches existing DaemonSet, skipping update")
+				return op.Noop, existing, false, nil
+			}
+		}
+
+		var updated bool
+		original := existing.DeepCopy()
+
+		k8sresources.SetDefaultsPodTemplateSpec(&desired.Spec.Template)
+
+		if holdPodTemplateChanges {
+			held = maintenance.HoldPodTemplateChanges(existing, desired, &existing.Spec.Template, &desired.Spec.Template)
+			if held {
+				log.Debug(logger, "holding DataPlane DaemonSet Pod template changes until the maintenance window opens")
+			}
+		}
+
+		// ensure that object metadata is up to date
+		updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, desired.ObjectMeta)
+
+		opts := []cmp.Option{
+			cmp.Comparer(k8sresources.ResourceRequirementsEqual),
+		}
+
+		// ensure that PodTemplateSpec is up to date
+		if !cmp.Equal(existing.Spec.Template, desired.Spec.Template, opts...) {
+			existing.Spec.Template = desired.Spec.Template
+			updated = true
+		}
+
+		// ensure that update strategy is up to date
+		if !cmp.Equal(existing.Spec.UpdateStrategy, desired.Spec.UpdateStrategy) {
+			existing.Spec.UpdateStrategy = desired.Spec.UpdateStrategy
+			updated = true
+		}
+
+		if updated {
+			diff := cmp.Diff(original.Spec.Template, desired.Spec.Template, opts...)
+			log.Trace(logger, "DataPlane DaemonSet diff detected", "diff", diff)
+		}
+
+		res, daemonSet, err := patch.ApplyPatchIfNotEmpty(ctx, cl, logger, existing, original, updated)
+		return res, daemonSet, held, err
+	}
+
+	if err = cl.Create(ctx, desired); err != nil {
+		return op.Noop, nil, false, fmt.Errorf("failed creating DaemonSet for DataPlane %s: %w", dataplane.Name, err)
+	}
+
+	log.Debug(logger, "daemonset for DataPlane created", "daemonset", desired.Name)
+	return op.Created, desired, false, nil
+}
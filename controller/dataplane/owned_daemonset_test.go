@@ -0,0 +1,227 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestDeploymentBuilderBuildAndDeployDaemonSet(t *testing.T) {
+	const (
+		validateDataPlaneImage = true
+		enforceConfig          = false
+	)
+	liveLabels := client.MatchingLabels{
+		consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValueLive,
+	}
+
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "edge",
+			Namespace: "default",
+			UID:       "edge-uid",
+			Annotations: map[string]string{
+				consts.AnnotationDataPlaneWorkloadKind: consts.DataPlaneWorkloadKindDaemonSet,
+			},
+		},
+		Spec: operatorv1beta1.DataPlaneSpec{
+			DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+				Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+					DeploymentOptions: operatorv1beta1.DeploymentOptions{
+						PodTemplateSpec: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								HostNetwork: true,
+								Containers: []corev1.Container{
+									{
+										Name:  consts.DataPlaneProxyContainerName,
+										Image: consts.DefaultDataPlaneImage,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// A live Deployment left over from the Deployment workload kind.
+	leftoverDeployment, err := k8sresources.GenerateNewDeploymentForDataPlane(dataplane, consts.DefaultDataPlaneImage,
+		matchingLabelsToDeploymentOpt(liveLabels),
+	)
+	require.NoError(t, err)
+	leftoverDeployment.Name = "leftover"
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(dataplane, leftoverDeployment.Unwrap()).
+		Build()
+
+	var afterCallbackRun bool
+	afterCallbacks := CreateCallbackManager()
+	require.NoError(t, afterCallbacks.Register(func(_ context.Context, _ *operatorv1beta1.DataPlane, _ client.Client, subject any) error {
+		deployment, ok := subject.(*k8sresources.Deployment)
+		require.True(t, ok)
+		afterCallbackRun = true
+		deployment.Spec.Template.Annotations = map[string]string{"callback": "applied"}
+		return nil
+	}, "test"))
+
+	builder := NewDeploymentBuilder(logr.Discard(), cl).
+		WithAfterCallbacks(afterCallbacks).
+		WithClusterCertificate("certificate").
+		WithAdditionalLabels(liveLabels)
+
+	daemonSet, res, err := builder.BuildAndDeployDaemonSet(t.Context(), dataplane, enforceConfig, validateDataPlaneImage)
+	require.NoError(t, err)
+	require.Equal(t, op.Created, res)
+	require.True(t, afterCallbackRun)
+	require.True(t, k8sutils.IsOwnedByRefUID(daemonSet, dataplane.UID))
+	require.Equal(t, consts.DataPlaneStateLabelValueLive, daemonSet.Labels[consts.DataPlaneDeploymentStateLabel])
+	require.Equal(t, "applied", daemonSet.Spec.Template.Annotations["callback"])
+	require.True(t, daemonSet.Spec.Template.Spec.HostNetwork)
+	require.Equal(t, corev1.DNSClusterFirstWithHostNet, daemonSet.Spec.Template.Spec.DNSPolicy)
+
+	deployments, err := k8sutils.ListDeploymentsForOwner(t.Context(), cl, dataplane.Namespace, dataplane.UID)
+	require.NoError(t, err)
+	require.Len(t, deployments, 1, "leftover Deployment should be kept until the DaemonSet has ready Pods")
+
+	t.Run("unchanged DaemonSet is not updated", func(t *testing.T) {
+		_, res, err := builder.BuildAndDeployDaemonSet(t.Context(), dataplane, enforceConfig, validateDataPlaneImage)
+		require.NoError(t, err)
+		require.Equal(t, op.Noop, res)
+	})
+
+	t.Run("leftover Deployment is deleted once the DaemonSet has ready Pods", func(t *testing.T) {
+		daemonSet.Status.NumberReady = 1
+		require.NoError(t, cl.Status().Update(t.Context(), daemonSet))
+
+		_, res, err := builder.BuildAndDeployDaemonSet(t.Context(), dataplane, enforceConfig, validateDataPlaneImage)
+		require.NoError(t, err)
+		require.Equal(t, op.Noop, res)
+
+		deployments, err := k8sutils.ListDeploymentsForOwner(t.Context(), cl, dataplane.Namespace, dataplane.UID)
+		require.NoError(t, err)
+		require.Empty(t, deployments, "leftover Deployment should be deleted")
+	})
+
+	t.Run("Deployment workload kind deletes the DaemonSet", func(t *testing.T) {
+		deployment, res, err := builder.BuildAndDeploy(t.Context(), dataplane, enforceConfig, validateDataPlaneImage)
+		require.NoError(t, err)
+		require.Equal(t, op.Created, res)
+		require.NotNil(t, deployment)
+
+		daemonSets, err := k8sutils.ListDaemonSetsForOwner(t.Context(), cl, dataplane.Namespace, dataplane.UID)
+		require.NoError(t, err)
+		require.Empty(t, daemonSets)
+	})
+}
+
+func TestNodeAddressesForDataPlane(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Name: "edge", Namespace: "default"},
+		Status:     operatorv1beta1.DataPlaneStatus{Selector: "selector"},
+	}
+	pod := func(name, nodeName, selector string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{consts.OperatorLabelSelector: selector},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+	node := func(name, internalIP string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: internalIP}},
+			},
+		}
+	}
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			pod("scheduled", "node-a", "selector"),
+			pod("pending", "", "selector"),
+			pod("other-dataplane", "node-c", "other"),
+			node("node-a", "10.0.0.1"),
+			node("node-b", "10.0.0.2"),
+			node("node-c", "10.0.0.3"),
+		).
+		Build()
+
+	addresses, err := nodeAddressesForDataPlane(t.Context(), cl, dataplane)
+	require.NoError(t, err)
+	require.Len(t, addresses, 1)
+	require.Equal(t, "10.0.0.1", addresses[0].Value)
+	require.Equal(t, operatorv1beta1.PrivateIPAddressSourceType, addresses[0].SourceType)
+}
+
+func TestListDataPlaneLiveWorkloads(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "edge",
+			Namespace: "default",
+			UID:       "edge-uid",
+			Annotations: map[string]string{
+				consts.AnnotationDataPlaneWorkloadKind: consts.DataPlaneWorkloadKindDaemonSet,
+			},
+		},
+	}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "edge-ds",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":                                dataplane.Name,
+				consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+				consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValueLive,
+			},
+		},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 3,
+			NumberReady:            2,
+			NumberAvailable:        1,
+		},
+	}
+	k8sutils.SetOwnerForObject(daemonSet, dataplane)
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(dataplane, daemonSet).
+		Build()
+
+	workloads, err := listDataPlaneLiveWorkloads(t.Context(), cl, dataplane)
+	require.NoError(t, err)
+	require.Equal(t, []dataPlaneWorkload{
+		{
+			kind: consts.DataPlaneWorkloadKindDaemonSet,
+			name: "edge-ds",
+			status: appsv1.DeploymentStatus{
+				Replicas:          3,
+				ReadyReplicas:     2,
+				AvailableReplicas: 1,
+			},
+		},
+	}, workloads)
+	_, ready := isDeploymentReady(workloads[0].status)
+	require.False(t, ready)
+}
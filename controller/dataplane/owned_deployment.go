@@ -15,6 +15,7 @@ import (
 
 	dataplanepkg "github.com/kong/gateway-operator/controller/pkg/dataplane"
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/maintenance"
 	"github.com/kong/gateway-operator/controller/pkg/op"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
 	"github.com/kong/gateway-operator/internal/utils/config"
@@ -37,6 +38,8 @@ type DeploymentBuilder struct {
 	additionalLabels       client.MatchingLabels
 	defaultImage           string
 	opts                   []k8sresources.DeploymentOpt
+	holdPodTemplateChanges bool
+	podTemplateChangesHeld bool
 }
 
 // NewDeploymentBuilder creates a DeploymentBuilder.
@@ -83,6 +86,20 @@ func (d *DeploymentBuilder) WithOpts(opts ...k8sresources.DeploymentOpt) *Deploy
 	return d
 }
 
+// WithPodTemplateChangesHeld configures whether changes to the Pod template of
+// an existing workload are held, e.g. until the DataPlane's maintenance window opens.
+// Other changes, e.g. to the workload's metadata or replicas, are still applied.
+func (d *DeploymentBuilder) WithPodTemplateChangesHeld(hold bool) *DeploymentBuilder {
+	d.holdPodTemplateChanges = hold
+	return d
+}
+
+// PodTemplateChangesHeld returns true if the last BuildAndDeploy or BuildAndDeployDaemonSet
+// call held changes to the Pod template of the existing workload.
+func (d *DeploymentBuilder) PodTemplateChangesHeld() bool {
+	return d.podTemplateChangesHeld
+}
+
 // BuildAndDeploy builds and deploys a DataPlane Deployment, or reduces Deployments if there are more than one. It
 // returns the Deployment if it created or updated one, or nil if it needed to reduce or did not need to update an
 // existing Deployment.
@@ -92,14 +109,14 @@ func (d *DeploymentBuilder) BuildAndDeploy(
 	enforceConfig bool,
 	validateDataPlaneImage bool,
 ) (*appsv1.Deployment, op.Result, error) {
-	// run any preparatory callbacks
-	beforeDeploymentCallbacks := NewCallbackRunner(d.client)
-	cbErrors := beforeDeploymentCallbacks.For(dataplane).Runs(d.beforeCallbacks).Do(ctx, nil)
-	if len(cbErrors) > 0 {
-		for _, err := range cbErrors {
-			d.logger.Error(err, "callback failed")
-		}
-		return nil, op.Noop, fmt.Errorf("before generation callbacks failed")
+	d.podTemplateChangesHeld = false
+	if err := d.runBeforeCallbacks(ctx, dataplane); err != nil {
+		return nil, op.Noop, err
+	}
+
+	// the DataPlane's Pods can be run by a single kind of workload at a time
+	if err := deleteDataPlaneDaemonSets(ctx, d.client, dataplane, d.additionalLabels); err != nil {
+		return nil, op.Noop, err
 	}
 
 	// if there is more than one Deployment, delete the extras
@@ -111,10 +128,47 @@ func (d *DeploymentBuilder) BuildAndDeploy(
 		return nil, op.Noop, nil
 	}
 
+	desiredDeployment, err := d.generateDesiredDeployment(ctx, dataplane, validateDataPlaneImage)
+	if err != nil {
+		return nil, op.Noop, err
+	}
+
+	// push the complete Deployment to Kubernetes
+	res, deployment, held, err := reconcileDataPlaneDeployment(ctx, d.client, d.logger, enforceConfig,
+		d.holdPodTemplateChanges, dataplane, existingDeployment, desiredDeployment.Unwrap())
+	if err != nil {
+		return nil, op.Noop, err
+	}
+	d.podTemplateChangesHeld = held
+	return deployment, res, nil
+}
+
+// runBeforeCallbacks runs the callbacks which have to run before the DataPlane
+// Deployment is generated.
+func (d *DeploymentBuilder) runBeforeCallbacks(ctx context.Context, dataplane *operatorv1beta1.DataPlane) error {
+	beforeDeploymentCallbacks := NewCallbackRunner(d.client)
+	cbErrors := beforeDeploymentCallbacks.For(dataplane).Runs(d.beforeCallbacks).Do(ctx, nil)
+	if len(cbErrors) > 0 {
+		for _, err := range cbErrors {
+			d.logger.Error(err, "callback failed")
+		}
+		return fmt.Errorf("before generation callbacks failed")
+	}
+	return nil
+}
+
+// generateDesiredDeployment generates the complete DataPlane Deployment: the
+// generated Deployment with the cluster certificate, patched by the after
+// generation callbacks and the user PodTemplateSpec patches.
+func (d *DeploymentBuilder) generateDesiredDeployment(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+	validateDataPlaneImage bool,
+) (*k8sresources.Deployment, error) {
 	// generate the initial Deployment struct
 	desiredDeployment, err := generateDataPlaneDeployment(validateDataPlaneImage, dataplane, d.defaultImage, d.additionalLabels, d.opts...)
 	if err != nil {
-		return nil, op.Noop, fmt.Errorf("could not generate Deployment: %w", err)
+		return nil, fmt.Errorf("could not generate Deployment: %w", err)
 	}
 
 	// Add the cluster certificate to the generated Deployment
@@ -122,39 +176,16 @@ func (d *DeploymentBuilder) BuildAndDeploy(
 
 	// run any callbacks that patch the initial Deployment struct
 	afterDeploymentCallbacks := NewCallbackRunner(d.client)
-	cbErrors = afterDeploymentCallbacks.For(dataplane).Runs(d.afterCallbacks).
+	cbErrors := afterDeploymentCallbacks.For(dataplane).Runs(d.afterCallbacks).
 		Modifies(reflect.TypeFor[k8sresources.Deployment]()).Do(ctx, desiredDeployment)
 	if len(cbErrors) > 0 {
 		for _, err := range cbErrors {
 			d.logger.Error(err, "callback failed")
 		}
-		return nil, op.Noop, fmt.Errorf("after generation callbacks failed")
-	}
-
-	// TODO https://github.com/Kong/gateway-operator/issues/128
-	// This is a a workaround to avoid patches clobbering the wrong EnvVar. We want to find an improved patch mechanism
-	// that doesn't clobber EnvVars (and other array fields) it shouldn't.
-	existingEnvVars := desiredDeployment.Spec.Template.Spec.Containers[0].Env
-	desiredDeployment.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{}
-	// apply user patches and set any default environment variables that aren't already set
-	desiredDeployment, err = applyDeploymentUserPatchesForDataPlane(dataplane, desiredDeployment)
-	if err != nil {
-		return nil, op.Noop, err
+		return nil, fmt.Errorf("after generation callbacks failed")
 	}
-	// apply default envvars and restore the hacked-out ones
-	desiredDeployment = applyEnvForDataPlane(existingEnvVars, desiredDeployment, config.KongDefaults)
 
-	if err := k8sresources.AnnotateObjWithHash(desiredDeployment.Unwrap(), dataplane.Spec); err != nil {
-		return nil, op.Noop, err
-	}
-
-	// push the complete Deployment to Kubernetes
-	res, deployment, err := reconcileDataPlaneDeployment(ctx, d.client, d.logger, enforceConfig,
-		dataplane, existingDeployment, desiredDeployment.Unwrap())
-	if err != nil {
-		return nil, op.Noop, err
-	}
-	return deployment, res, nil
+	return finalizeDataPlaneDeployment(dataplane, desiredDeployment)
 }
 
 // generateDataPlaneDeployment generates the base Deployment for a DataPlane. It determines the image to use and
@@ -170,6 +201,22 @@ func generateDataPlaneDeployment(
 		opts = append(opts, matchingLabelsToDeploymentOpt(additionalDeploymentLabels))
 	}
 
+	drainingTimeout, err := connectionDrainingTimeout(dataplane)
+	if err != nil {
+		return nil, err
+	}
+	if drainingTimeout > 0 {
+		opts = append(opts, connectionDrainingDeploymentOpt(drainingTimeout))
+	}
+
+	topologyKeys, whenUnsatisfiable, err := dataPlaneTopologySpread(dataplane)
+	if err != nil {
+		return nil, err
+	}
+	if len(topologyKeys) > 0 {
+		opts = append(opts, topologySpreadDeploymentOpt(topologyKeys, whenUnsatisfiable))
+	}
+
 	versionValidationOptions := make([]versions.VersionValidationOption, 0)
 	if validateDataPlaneImage {
 		versionValidationOptions = append(versionValidationOptions, versions.IsDataPlaneImageVersionSupported)
@@ -186,6 +233,32 @@ func generateDataPlaneDeployment(
 	return generatedDeployment, nil
 }
 
+// finalizeDataPlaneDeployment applies the user PodTemplateSpec patches and the
+// default environment variables to the generated Deployment and annotates it
+// with the hash of the DataPlane spec.
+func finalizeDataPlaneDeployment(
+	dataplane *operatorv1beta1.DataPlane,
+	deployment *k8sresources.Deployment,
+) (*k8sresources.Deployment, error) {
+	// TODO https://github.com/Kong/gateway-operator/issues/128
+	// This is a a workaround to avoid patches clobbering the wrong EnvVar. We want to find an improved patch mechanism
+	// that doesn't clobber EnvVars (and other array fields) it shouldn't.
+	existingEnvVars := deployment.Spec.Template.Spec.Containers[0].Env
+	deployment.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{}
+	// apply user patches and set any default environment variables that aren't already set
+	deployment, err := applyDeploymentUserPatchesForDataPlane(dataplane, deployment)
+	if err != nil {
+		return nil, err
+	}
+	// apply default envvars and restore the hacked-out ones
+	deployment = applyEnvForDataPlane(existingEnvVars, deployment, config.KongDefaults)
+
+	if err := k8sresources.AnnotateObjWithHash(deployment.Unwrap(), dataplane.Spec); err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
 // applyDeploymentUserPatchesForDataPlane applies user PodTemplateSpec patches and fills in defaults
 // for any previously unset environment variables.
 func applyDeploymentUserPatchesForDataPlane(
@@ -274,28 +347,31 @@ func listOrReduceDataPlaneDeployments(
 // reconcileDataPlaneDeployment takes any existing DataPlane Deployment and a desired DataPlane Deployment and
 // reconciles the existing state to the desired state by either updating an existing Deployment, creating a new one,
 // or doing nothing.
+// When holdPodTemplateChanges is set, the Pod template of an existing Deployment is left untouched and the returned
+// held flag reports whether it differs from the desired one.
 func reconcileDataPlaneDeployment(
 	ctx context.Context,
 	cl client.Client,
 	logger logr.Logger,
 	enforceConfig bool,
+	holdPodTemplateChanges bool,
 	dataplane *operatorv1beta1.DataPlane,
 	existing *appsv1.Deployment,
 	desired *appsv1.Deployment,
-) (res op.Result, deploy *appsv1.Deployment, err error) {
+) (res op.Result, deploy *appsv1.Deployment, held bool, err error) {
 	if existing != nil {
 
 		// If the enforceConfig flag is not set, we compare the spec hash of the
 		// existing Deployment with the spec hash of the desired Deployment. If
-		// the hashes match, we skip the update.
+		// the hashes and the propagated metadata match, we skip the update.
 		if !enforceConfig {
 			match, err := k8sresources.SpecHashMatchesAnnotation(dataplane.Spec, existing)
 			if err != nil {
-				return op.Noop, nil, err
+				return op.Noop, nil, false, err
 			}
-			if match {
+			if match && k8sresources.PropagatedMetadataMatches(existing, desired) {
 				log.Debug(logger, "DataPlane Deployment spec hash matches existing Deployment, skipping update")
-				return op.Noop, existing, nil
+				return op.Noop, existing, false, nil
 			}
 			// If the spec hash does not match, we need to enforce the configuration
 			// so fall through to the update logic.
@@ -306,6 +382,13 @@ func reconcileDataPlaneDeployment(
 
 		k8sresources.SetDefaultsPodTemplateSpec(&desired.Spec.Template)
 
+		if holdPodTemplateChanges {
+			held = maintenance.HoldPodTemplateChanges(existing, desired, &existing.Spec.Template, &desired.Spec.Template)
+			if held {
+				log.Debug(logger, "holding DataPlane Deployment Pod template changes until the maintenance window opens")
+			}
+		}
+
 		// ensure that object metadata is up to date
 		updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, desired.ObjectMeta)
 
@@ -349,13 +432,14 @@ func reconcileDataPlaneDeployment(
 			log.Trace(logger, "DataPlane Deployment diff detected", "diff", diff)
 		}
 
-		return patch.ApplyPatchIfNotEmpty(ctx, cl, logger, existing, original, updated)
+		res, deploy, err := patch.ApplyPatchIfNotEmpty(ctx, cl, logger, existing, original, updated)
+		return res, deploy, held, err
 	}
 
 	if err = cl.Create(ctx, desired); err != nil {
-		return op.Noop, nil, fmt.Errorf("failed creating Deployment for DataPlane %s: %w", dataplane.Name, err)
+		return op.Noop, nil, false, fmt.Errorf("failed creating Deployment for DataPlane %s: %w", dataplane.Name, err)
 	}
 
 	log.Debug(logger, "deployment for DataPlane created", "deployment", desired.Name)
-	return op.Created, desired, nil
+	return op.Created, desired, false, nil
 }
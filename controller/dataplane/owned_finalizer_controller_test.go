@@ -44,14 +44,17 @@ func TestRequestsForDataPlaneOwnedObjects(t *testing.T) {
 	const otherNs = "other-namespace"
 	cl := fake.NewFakeClient(
 		&appsv1.Deployment{ObjectMeta: ownedObjMeta("deployment")},
+		&appsv1.DaemonSet{ObjectMeta: ownedObjMeta("daemonset")},
 		&corev1.Service{ObjectMeta: ownedObjMeta("service")},
 		&corev1.Secret{ObjectMeta: ownedObjMeta("secret")},
 
 		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "not-owned-deployment", Namespace: ownerDp.Namespace}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "not-owned-daemonset", Namespace: ownerDp.Namespace}},
 		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "not-owned-service", Namespace: ownerDp.Namespace}},
 		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "not-owned-secret", Namespace: ownerDp.Namespace}},
 
 		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "not-owned-diff-ns-deployment", Namespace: otherNs}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "not-owned-diff-ns-daemonset", Namespace: otherNs}},
 		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "not-owned-diff-ns-service", Namespace: otherNs}},
 		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "not-owned-diff-ns-secret", Namespace: otherNs}},
 	)
@@ -84,4 +87,10 @@ func TestRequestsForDataPlaneOwnedObjects(t *testing.T) {
 		require.Len(t, requests, 1)
 		require.Equal(t, expectedRequest("deployment"), requests[0])
 	})
+	t.Run("daemonsets", func(t *testing.T) {
+		require.Empty(t, requestsForDataPlaneOwnedObjects[appsv1.DaemonSet](cl)(ctx, nonOwnerDp))
+		requests := requestsForDataPlaneOwnedObjects[appsv1.DaemonSet](cl)(ctx, ownerDp)
+		require.Len(t, requests, 1)
+		require.Equal(t, expectedRequest("daemonset"), requests[0])
+	})
 }
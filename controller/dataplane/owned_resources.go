@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
@@ -53,6 +54,29 @@ func ensureDataPlaneCertificate(
 	)
 }
 
+// deleteHPAsForDataPlane deletes all the HorizontalPodAutoscalers owned by
+// the provided DataPlane.
+func deleteHPAsForDataPlane(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+) error {
+	hpas, err := k8sutils.ListHPAsForOwner(
+		ctx,
+		cl,
+		dataplane.Namespace,
+		dataplane.UID,
+		k8sresources.GetManagedLabelForOwner(dataplane),
+	)
+	if err != nil {
+		return fmt.Errorf("failed listing HPAs for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	if err := k8sreduce.ReduceHPAs(ctx, cl, hpas, k8sreduce.FilterNone); err != nil {
+		return fmt.Errorf("failed reducing HPAs for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	return nil
+}
+
 func ensureHPAForDataPlane(
 	ctx context.Context,
 	cl client.Client,
@@ -115,11 +139,17 @@ func ensureHPAForDataPlane(
 	return op.Created, nil, nil
 }
 
+// ensurePodDisruptionBudgetForDataPlane ensures the PodDisruptionBudget defined
+// in the DataPlane's spec exists. When it's not defined and zone-aware
+// PodDisruptionBudget is enabled for the DataPlane, a PodDisruptionBudget
+// allowing to disrupt the share of replicas of a single zone out of the provided
+// number of zones is ensured instead.
 func ensurePodDisruptionBudgetForDataPlane(
 	ctx context.Context,
 	cl client.Client,
 	log logr.Logger,
 	dataplane *operatorv1beta1.DataPlane,
+	zones int,
 ) (res op.Result, pdb *policyv1.PodDisruptionBudget, err error) {
 	dpNn := client.ObjectKeyFromObject(dataplane)
 	matchingLabels := k8sresources.GetManagedLabelForOwner(dataplane)
@@ -128,7 +158,7 @@ func ensurePodDisruptionBudgetForDataPlane(
 		return op.Noop, nil, fmt.Errorf("failed listing PodDisruptionBudgets for DataPlane %s: %w", dpNn, err)
 	}
 
-	if dataplane.Spec.Resources.PodDisruptionBudget == nil {
+	if dataplane.Spec.Resources.PodDisruptionBudget == nil && !zoneAwarePodDisruptionBudgetEnabled(dataplane) {
 		if err := k8sreduce.ReducePodDisruptionBudgets(ctx, cl, pdbs, k8sreduce.FilterNone); err != nil {
 			return op.Noop, nil, fmt.Errorf("failed reducing PodDisruptionBudgets for DataPlane %s: %w", dpNn, err)
 		}
@@ -142,9 +172,14 @@ func ensurePodDisruptionBudgetForDataPlane(
 		return op.Noop, nil, nil
 	}
 
-	generatedPDB, err := k8sresources.GeneratePodDisruptionBudgetForDataPlane(dataplane)
-	if err != nil {
-		return op.Noop, nil, fmt.Errorf("failed generating PodDisruptionBudget for DataPlane %s: %w", dpNn, err)
+	var generatedPDB *policyv1.PodDisruptionBudget
+	if dataplane.Spec.Resources.PodDisruptionBudget != nil {
+		generatedPDB, err = k8sresources.GeneratePodDisruptionBudgetForDataPlane(dataplane)
+		if err != nil {
+			return op.Noop, nil, fmt.Errorf("failed generating PodDisruptionBudget for DataPlane %s: %w", dpNn, err)
+		}
+	} else {
+		generatedPDB = k8sresources.GenerateZoneAwarePodDisruptionBudgetForDataPlane(dataplane, zones)
 	}
 
 	if len(pdbs) == 1 {
@@ -368,6 +403,18 @@ func ensureIngressServiceForDataPlane(
 			existingService.Spec.Ports = generatedService.Spec.Ports
 			updated = true
 		}
+		if !cmp.Equal(existingService.Spec.TrafficDistribution, generatedService.Spec.TrafficDistribution) {
+			existingService.Spec.TrafficDistribution = generatedService.Spec.TrafficDistribution
+			updated = true
+		}
+		if existingService.Spec.LoadBalancerIP != generatedService.Spec.LoadBalancerIP {
+			existingService.Spec.LoadBalancerIP = generatedService.Spec.LoadBalancerIP
+			updated = true
+		}
+		if !slices.Equal(existingService.Spec.ExternalIPs, generatedService.Spec.ExternalIPs) {
+			existingService.Spec.ExternalIPs = generatedService.Spec.ExternalIPs
+			updated = true
+		}
 
 		if updated {
 			res, existingService, err := patch.ApplyPatchIfNotEmpty(ctx, cl, logger, existingService, old, updated)
@@ -0,0 +1,104 @@
+package dataplane
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// RenderOwnedResources generates the objects that the DataPlane controller
+// would create for the provided DataPlane without reaching out to the API server.
+// It returns the names of the ingress and admin Services alongside the objects
+// so that a ControlPlane pointing at the DataPlane can be rendered as well.
+//
+// Names of the generated objects are derived from their GenerateName and the
+// certificate Secret is filled with placeholder data as the certificate is
+// signed with the cluster CA at runtime. Objects that depend on the cluster
+// state (e.g. KongPluginInstallation ConfigMaps or Konnect extensions) are not
+// rendered.
+func RenderOwnedResources(
+	dataplane *operatorv1beta1.DataPlane,
+	defaultImage string,
+	validateDataPlaneImage bool,
+) (ingressServiceName string, adminServiceName string, objs []client.Object, err error) {
+	dataplane = dataplane.DeepCopy()
+	// The selector is a random UUID generated by the controller, use a stable
+	// value instead so that the rendered output is reproducible.
+	if dataplane.Status.Selector == "" {
+		dataplane.Status.Selector = dataplane.Name
+	}
+	liveServiceLabels := client.MatchingLabels{
+		consts.DataPlaneServiceStateLabel: consts.DataPlaneStateLabelValueLive,
+	}
+
+	adminService, err := k8sresources.GenerateNewAdminServiceForDataPlane(dataplane,
+		k8sresources.LabelSelectorFromDataPlaneStatusSelectorServiceOpt(dataplane),
+		matchingLabelsToServiceOpt(liveServiceLabels),
+	)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed generating admin Service for DataPlane %s: %w", dataplane.Name, err)
+	}
+	k8sutils.SetNameFromGenerateName(adminService)
+
+	ingressService, err := k8sresources.GenerateNewIngressServiceForDataPlane(dataplane,
+		k8sresources.LabelSelectorFromDataPlaneStatusSelectorServiceOpt(dataplane),
+		k8sresources.ServicePortsFromDataPlaneIngressOpt(dataplane),
+		matchingLabelsToServiceOpt(liveServiceLabels),
+	)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed generating ingress Service for DataPlane %s: %w", dataplane.Name, err)
+	}
+	addAnnotationsForDataPlaneIngressService(ingressService, *dataplane)
+	k8sutils.SetOwnerForObject(ingressService, dataplane)
+	k8sutils.SetNameFromGenerateName(ingressService)
+
+	certSecret := k8sresources.GenerateNewTLSSecret(dataplane,
+		k8sresources.SecretWithLabel(consts.ServiceSecretLabel, adminService.Name),
+		k8sresources.SecretWithPlaceholderTLSData(),
+	)
+	k8sutils.SetNameFromGenerateName(certSecret)
+
+	deployment, err := generateDataPlaneDeployment(validateDataPlaneImage, dataplane, defaultImage,
+		client.MatchingLabels{
+			consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValueLive,
+		},
+		labelSelectorFromDataPlaneStatusSelectorDeploymentOpt(dataplane),
+	)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("could not generate Deployment for DataPlane %s: %w", dataplane.Name, err)
+	}
+	deployment = setClusterCertVars(deployment, certSecret.Name)
+	deployment, err = finalizeDataPlaneDeployment(dataplane, deployment)
+	if err != nil {
+		return "", "", nil, err
+	}
+	k8sutils.SetNameFromGenerateName(deployment)
+
+	objs = []client.Object{adminService, ingressService, certSecret, deployment.Unwrap()}
+
+	if scaling := dataplane.Spec.Deployment.Scaling; scaling != nil && scaling.HorizontalScaling != nil {
+		hpa, err := k8sresources.GenerateHPAForDataPlane(dataplane, deployment.Name)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("failed generating HPA for DataPlane %s: %w", dataplane.Name, err)
+		}
+		k8sutils.SetNameFromGenerateName(hpa)
+		objs = append(objs, hpa)
+	}
+
+	if dataplane.Spec.Resources.PodDisruptionBudget != nil {
+		pdb, err := k8sresources.GeneratePodDisruptionBudgetForDataPlane(dataplane)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("failed generating PodDisruptionBudget for DataPlane %s: %w", dataplane.Name, err)
+		}
+		k8sutils.SetNameFromGenerateName(pdb)
+		objs = append(objs, pdb)
+	}
+
+	return ingressService.Name, adminService.Name, objs, nil
+}
@@ -0,0 +1,73 @@
+package dataplane
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/versionchannel"
+	"github.com/kong/gateway-operator/internal/versions"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// ensureDataPlaneVersionChannelImage resolves the proxy image of the version
+// channel tracked by the DataPlane, if any. It returns the DataPlane to generate
+// the workload from: a copy of the DataPlane using the resolved image, so that
+// upgrades are rolled out like any other change to the DataPlane's spec, or
+// the DataPlane itself when it doesn't track a version channel.
+func ensureDataPlaneVersionChannelImage(
+	ctx context.Context,
+	cl client.Client,
+	resolver *versionchannel.Resolver,
+	dataplane *operatorv1beta1.DataPlane,
+	defaultImage string,
+	validateDataPlaneImage bool,
+) (*operatorv1beta1.DataPlane, ctrl.Result, error) {
+	// The configured image determines the repository in which releases are looked up.
+	image, err := generateDataPlaneImage(dataplane, defaultImage)
+	if err != nil {
+		return nil, ctrl.Result{}, err
+	}
+	var validators []versions.VersionValidationOption
+	if validateDataPlaneImage {
+		validators = append(validators, versions.IsDataPlaneImageVersionSupported)
+	}
+
+	var imagePullSecrets []corev1.LocalObjectReference
+	if podTemplateSpec := dataplane.Spec.Deployment.PodTemplateSpec; podTemplateSpec != nil {
+		imagePullSecrets = podTemplateSpec.Spec.ImagePullSecrets
+	}
+
+	resolved, res, err := versionchannel.EnsureImage(ctx, cl, resolver, dataplane, image, imagePullSecrets, validators...)
+	if err != nil {
+		return nil, ctrl.Result{}, fmt.Errorf("failed resolving version channel image: %w", err)
+	}
+	if resolved == "" {
+		return dataplane, res, nil
+	}
+	return withProxyImage(dataplane, resolved), res, nil
+}
+
+// withProxyImage returns a copy of the DataPlane using the provided proxy image.
+func withProxyImage(dataplane *operatorv1beta1.DataPlane, image string) *operatorv1beta1.DataPlane {
+	dataplane = dataplane.DeepCopy()
+	if dataplane.Spec.Deployment.PodTemplateSpec == nil {
+		dataplane.Spec.Deployment.PodTemplateSpec = &corev1.PodTemplateSpec{}
+	}
+	podSpec := &dataplane.Spec.Deployment.PodTemplateSpec.Spec
+	if container := k8sutils.GetPodContainerByName(podSpec, consts.DataPlaneProxyContainerName); container != nil {
+		container.Image = image
+	} else {
+		podSpec.Containers = append(podSpec.Containers, corev1.Container{
+			Name:  consts.DataPlaneProxyContainerName,
+			Image: image,
+		})
+	}
+	return dataplane
+}
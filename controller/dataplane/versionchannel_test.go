@@ -0,0 +1,113 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/controller/pkg/versionchannel"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+type staticTagLister []string
+
+func (l staticTagLister) ListTags(context.Context, string, credentials.Store) ([]string, error) {
+	return l, nil
+}
+
+func TestEnsureDataPlaneVersionChannelImage(t *testing.T) {
+	resolver := versionchannel.NewResolver(staticTagLister{"3.8.0", "3.9.0", "3.9.1", "latest"}, time.Hour)
+	proxyImage := func(dp *operatorv1beta1.DataPlane) string {
+		return k8sutils.GetPodContainerByName(&dp.Spec.Deployment.PodTemplateSpec.Spec, consts.DataPlaneProxyContainerName).Image
+	}
+
+	testCases := []struct {
+		name          string
+		dataplane     *operatorv1beta1.DataPlane
+		expectedImage string
+	}{
+		{
+			name: "no version channel",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "dp", Namespace: "default"},
+				Spec: operatorv1beta1.DataPlaneSpec{
+					DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+						Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+							DeploymentOptions: operatorv1beta1.DeploymentOptions{
+								PodTemplateSpec: &corev1.PodTemplateSpec{
+									Spec: corev1.PodSpec{
+										Containers: []corev1.Container{
+											{Name: consts.DataPlaneProxyContainerName, Image: "kong:3.9.0"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedImage: "kong:3.9.0",
+		},
+		{
+			name: "pinned image is upgraded",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "dp",
+					Namespace:   "default",
+					Annotations: map[string]string{consts.AnnotationVersionChannel: "3.9.x"},
+				},
+				Spec: operatorv1beta1.DataPlaneSpec{
+					DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+						Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+							DeploymentOptions: operatorv1beta1.DeploymentOptions{
+								PodTemplateSpec: &corev1.PodTemplateSpec{
+									Spec: corev1.PodSpec{
+										Containers: []corev1.Container{
+											{Name: consts.DataPlaneProxyContainerName, Image: "kong:3.9.0"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedImage: "kong:3.9.1",
+		},
+		{
+			name: "default image is upgraded",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "dp",
+					Namespace:   "default",
+					Annotations: map[string]string{consts.AnnotationVersionChannel: "3.x"},
+				},
+			},
+			expectedImage: "kong:3.9.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(tc.dataplane).
+				WithStatusSubresource(tc.dataplane).
+				Build()
+
+			desired, _, err := ensureDataPlaneVersionChannelImage(t.Context(), cl, resolver, tc.dataplane, "kong:3.8.0", false)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedImage, proxyImage(desired))
+		})
+	}
+}
@@ -8,11 +8,15 @@ import (
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -36,6 +40,8 @@ func DataPlaneWatchBuilder(mgr ctrl.Manager, konnectEnabled bool) *builder.Build
 		Owns(&corev1.Service{}).
 		// Watch for changes in Deployments created by the dataplane controller.
 		Owns(&appsv1.Deployment{}).
+		// Watch for changes in DaemonSets created by the dataplane controller.
+		Owns(&appsv1.DaemonSet{}).
 		// Watch for changes in HPA created by the dataplane controller.
 		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
 		// Watch for changes in PodDisruptionBudgets created by the dataplane controller.
@@ -50,6 +56,24 @@ func DataPlaneWatchBuilder(mgr ctrl.Manager, konnectEnabled bool) *builder.Build
 				&corev1.ConfigMap{},
 				handler.TypedEnqueueRequestsFromMapFunc(listDataPlanesReferencingKongPluginInstallation(mgr.GetClient())),
 			),
+		).
+		// Watch for changes in ConfigMaps holding the configuration of self-hosted
+		// control planes referenced by DataPlanes.
+		WatchesRawSource(
+			source.Kind(
+				mgr.GetCache(),
+				&corev1.ConfigMap{},
+				handler.TypedEnqueueRequestsFromMapFunc(listDataPlanesReferencingHybridControlPlaneConfigMap(mgr.GetClient())),
+			),
+		).
+		// Watch for terminating DataPlane Pods to track the connection draining progress.
+		WatchesRawSource(
+			source.Kind(
+				mgr.GetCache(),
+				&corev1.Pod{},
+				handler.TypedEnqueueRequestsFromMapFunc(dataPlaneForPod),
+				terminatingPodPredicate,
+			),
 		)
 
 	if konnectEnabled {
@@ -94,3 +118,62 @@ func listDataPlanesReferencingKongPluginInstallation(
 		})
 	}
 }
+
+func listDataPlanesReferencingHybridControlPlaneConfigMap(
+	c client.Client,
+) handler.TypedMapFunc[*corev1.ConfigMap, reconcile.Request] {
+	return func(
+		ctx context.Context, cm *corev1.ConfigMap,
+	) []reconcile.Request {
+		logger := ctrllog.FromContext(ctx)
+
+		var dataPlaneList operatorv1beta1.DataPlaneList
+		if err := c.List(ctx, &dataPlaneList,
+			client.InNamespace(cm.Namespace),
+			client.MatchingFields{
+				index.HybridControlPlaneConfigMapIndex: cm.Name,
+			},
+		); err != nil {
+			logger.Error(err, "Failed to list DataPlanes in watch", "ConfigMap", client.ObjectKeyFromObject(cm))
+			return nil
+		}
+		return lo.Map(dataPlaneList.Items, func(dp operatorv1beta1.DataPlane, _ int) reconcile.Request {
+			return reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&dp),
+			}
+		})
+	}
+}
+
+// terminatingPodPredicate filters Pod events down to Pods being terminated
+// or deleted.
+var terminatingPodPredicate = predicate.TypedFuncs[*corev1.Pod]{
+	CreateFunc: func(event.TypedCreateEvent[*corev1.Pod]) bool {
+		return false
+	},
+	UpdateFunc: func(e event.TypedUpdateEvent[*corev1.Pod]) bool {
+		return !e.ObjectNew.DeletionTimestamp.IsZero()
+	},
+	DeleteFunc: func(event.TypedDeleteEvent[*corev1.Pod]) bool {
+		return true
+	},
+	GenericFunc: func(event.TypedGenericEvent[*corev1.Pod]) bool {
+		return false
+	},
+}
+
+// dataPlaneForPod maps a DataPlane Pod to its DataPlane.
+func dataPlaneForPod(_ context.Context, pod *corev1.Pod) []reconcile.Request {
+	name := pod.Labels["app"]
+	if name == "" || !dataPlanePodsSelector(name).Matches(labels.Set(pod.Labels)) {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Namespace: pod.Namespace,
+				Name:      name,
+			},
+		},
+	}
+}
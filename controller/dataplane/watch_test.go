@@ -0,0 +1,51 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+func TestDataPlaneForPod(t *testing.T) {
+	testCases := []struct {
+		name     string
+		labels   map[string]string
+		expected []reconcile.Request
+	}{
+		{
+			name:   "DataPlane Pod",
+			labels: map[string]string{"app": "dp", consts.OperatorLabelSelector: "selector"},
+			expected: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "default", Name: "dp"}},
+			},
+		},
+		{
+			name:   "Pod without the selector label",
+			labels: map[string]string{"app": "dp"},
+		},
+		{
+			name:   "Pod without the app label",
+			labels: map[string]string{consts.OperatorLabelSelector: "selector"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod",
+					Namespace: "default",
+					Labels:    tc.labels,
+				},
+			}
+			assert.Equal(t, tc.expected, dataPlaneForPod(context.Background(), pod))
+		})
+	}
+}
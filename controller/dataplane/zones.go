@@ -0,0 +1,394 @@
+package dataplane
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/dataplane"
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+const (
+	// DataPlaneConditionTypeZoneDistribution is the type of the DataPlane condition
+	// which reports the number of DataPlane replicas running in each zone.
+	// It is set only when zone awareness is enabled for the DataPlane.
+	DataPlaneConditionTypeZoneDistribution kcfgconsts.ConditionType = "ZoneDistribution"
+
+	// DataPlaneConditionReasonReplicasPerZone is the reason used with the
+	// ZoneDistribution condition.
+	DataPlaneConditionReasonReplicasPerZone kcfgconsts.ConditionReason = "ReplicasPerZone"
+)
+
+const (
+	// topologySpreadZone and topologySpreadNode are the topology domains
+	// which can be used in consts.AnnotationDataPlaneTopologySpread.
+	topologySpreadZone = "zone"
+	topologySpreadNode = "node"
+)
+
+// topologySpreadKeys maps the topology domains which can be used in
+// consts.AnnotationDataPlaneTopologySpread to the Node labels identifying them.
+var topologySpreadKeys = map[string]string{
+	topologySpreadZone: corev1.LabelTopologyZone,
+	topologySpreadNode: corev1.LabelHostname,
+}
+
+// dataPlaneTopologySpread returns the topology keys and the unsatisfiable
+// constraint action configured for the DataPlane through the
+// consts.AnnotationDataPlaneTopologySpread and
+// consts.AnnotationDataPlaneTopologySpreadWhenUnsatisfiable annotations.
+func dataPlaneTopologySpread(
+	dataplane *operatorv1beta1.DataPlane,
+) (topologyKeys []string, whenUnsatisfiable corev1.UnsatisfiableConstraintAction, err error) {
+	v, ok := dataplane.Annotations[consts.AnnotationDataPlaneTopologySpread]
+	if !ok || v == "" {
+		return nil, "", nil
+	}
+	for _, domain := range strings.Split(v, ",") {
+		key, ok := topologySpreadKeys[strings.TrimSpace(domain)]
+		if !ok {
+			return nil, "", fmt.Errorf("invalid %s annotation value %q: unknown topology domain %q, supported domains: %s, %s",
+				consts.AnnotationDataPlaneTopologySpread, v, domain, topologySpreadZone, topologySpreadNode,
+			)
+		}
+		if !lo.Contains(topologyKeys, key) {
+			topologyKeys = append(topologyKeys, key)
+		}
+	}
+
+	whenUnsatisfiable = corev1.UnsatisfiableConstraintAction(
+		dataplane.Annotations[consts.AnnotationDataPlaneTopologySpreadWhenUnsatisfiable],
+	)
+	switch whenUnsatisfiable {
+	case "":
+		whenUnsatisfiable = corev1.ScheduleAnyway
+	case corev1.ScheduleAnyway, corev1.DoNotSchedule:
+	default:
+		return nil, "", fmt.Errorf("invalid %s annotation value %q, supported values: %s, %s",
+			consts.AnnotationDataPlaneTopologySpreadWhenUnsatisfiable, whenUnsatisfiable, corev1.ScheduleAnyway, corev1.DoNotSchedule,
+		)
+	}
+	return topologyKeys, whenUnsatisfiable, nil
+}
+
+// topologySpreadDeploymentOpt returns a DeploymentOpt which spreads the
+// Deployment's Pods across the provided topology keys.
+// Constraints defined in the DataPlane's PodTemplateSpec for the same topology
+// keys take precedence as they're applied on top of the generated Deployment.
+func topologySpreadDeploymentOpt(
+	topologyKeys []string,
+	whenUnsatisfiable corev1.UnsatisfiableConstraintAction,
+) k8sresources.DeploymentOpt {
+	return func(d *appsv1.Deployment) {
+		podSpec := &d.Spec.Template.Spec
+		for _, key := range topologyKeys {
+			if lo.ContainsBy(podSpec.TopologySpreadConstraints, func(c corev1.TopologySpreadConstraint) bool {
+				return c.TopologyKey == key
+			}) {
+				continue
+			}
+			podSpec.TopologySpreadConstraints = append(podSpec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+				MaxSkew:           1,
+				TopologyKey:       key,
+				WhenUnsatisfiable: whenUnsatisfiable,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: maps.Clone(d.Spec.Selector.MatchLabels),
+				},
+				// Only Pods of the same revision are taken into account so
+				// that the Pods being replaced during a rollout don't skew it.
+				MatchLabelKeys: []string{appsv1.DefaultDeploymentUniqueLabelKey},
+			})
+		}
+	}
+}
+
+// zoneAwarePodDisruptionBudgetEnabled returns true when the DataPlane
+// enables zone-aware PodDisruptionBudget.
+func zoneAwarePodDisruptionBudgetEnabled(dataplane *operatorv1beta1.DataPlane) bool {
+	return dataplane.Annotations[consts.AnnotationDataPlaneZoneAwarePodDisruptionBudget] == "true"
+}
+
+// perZoneIngressServicesEnabled returns true when the DataPlane
+// enables per-zone ingress Services.
+func perZoneIngressServicesEnabled(dataplane *operatorv1beta1.DataPlane) bool {
+	return dataplane.Annotations[consts.AnnotationDataPlanePerZoneIngressServices] == "true"
+}
+
+// zoneAwarenessEnabled returns true when any of the zone-aware features is
+// enabled for the DataPlane. In that case the DataPlane Pods are labeled with
+// their zone and the number of replicas per zone is reported in DataPlane's status.
+func zoneAwarenessEnabled(dataplane *operatorv1beta1.DataPlane) bool {
+	topologyKeys, _, _ := dataPlaneTopologySpread(dataplane)
+	return lo.Contains(topologyKeys, corev1.LabelTopologyZone) ||
+		zoneAwarePodDisruptionBudgetEnabled(dataplane) ||
+		perZoneIngressServicesEnabled(dataplane)
+}
+
+// zoneReplicas holds the number of DataPlane replicas running in a zone.
+type zoneReplicas struct {
+	Replicas      int32
+	ReadyReplicas int32
+}
+
+// dataPlaneZones holds the zones of the cluster and the number of DataPlane
+// replicas running in each of them.
+type dataPlaneZones struct {
+	// Zones is the sorted list of zones of the cluster's Nodes.
+	Zones []string
+	// Replicas is the number of DataPlane replicas per zone.
+	Replicas map[string]zoneReplicas
+}
+
+// zones returns the zones of the cluster or nil when zones is nil.
+func (z *dataPlaneZones) zones() []string {
+	if z == nil {
+		return nil
+	}
+	return z.Zones
+}
+
+// ensureDataPlanePodsZones labels the Pods of the provided DataPlane workload
+// (Deployment or DaemonSet) with the zone of the Node they are running on
+// (consts.DataPlaneZoneLabel) and returns the zones of the cluster along with
+// the number of replicas in each zone.
+// Pods which are not scheduled yet are labeled once scheduled, when the
+// workload's status changes.
+func ensureDataPlanePodsZones(
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	workload client.Object,
+) (dataPlaneZones, error) {
+	var nodes corev1.NodeList
+	if err := cl.List(ctx, &nodes); err != nil {
+		return dataPlaneZones{}, fmt.Errorf("failed listing Nodes: %w", err)
+	}
+	nodeZones := make(map[string]string, len(nodes.Items))
+	for _, node := range nodes.Items {
+		if zone := node.Labels[corev1.LabelTopologyZone]; zone != "" {
+			nodeZones[node.Name] = zone
+		}
+	}
+
+	zones := dataPlaneZones{
+		Zones:    lo.Uniq(lo.Values(nodeZones)),
+		Replicas: make(map[string]zoneReplicas),
+	}
+	slices.Sort(zones.Zones)
+
+	pods, err := listDataPlaneWorkloadPods(ctx, cl, workload)
+	if err != nil {
+		return dataPlaneZones{}, err
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		zone, ok := nodeZones[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+
+		if pod.Labels[consts.DataPlaneZoneLabel] != zone {
+			old := pod.DeepCopy()
+			if pod.Labels == nil {
+				pod.Labels = make(map[string]string)
+			}
+			pod.Labels[consts.DataPlaneZoneLabel] = zone
+			if err := cl.Patch(ctx, pod, client.MergeFrom(old)); client.IgnoreNotFound(err) != nil {
+				return dataPlaneZones{}, fmt.Errorf("failed labeling Pod %s with its zone: %w", pod.Name, err)
+			}
+			log.Trace(logger, "labeled DataPlane Pod with its zone", "pod", pod.Name, "zone", zone)
+		}
+
+		r := zones.Replicas[zone]
+		r.Replicas++
+		if k8sutils.IsPodReady(pod) {
+			r.ReadyReplicas++
+		}
+		zones.Replicas[zone] = r
+	}
+
+	return zones, nil
+}
+
+// ensureZoneIngressServicesForDataPlane ensures that an ingress Service exists
+// for each of the provided zones when per-zone ingress Services are enabled for
+// the DataPlane, and that no other per-zone ingress Services exist.
+func ensureZoneIngressServicesForDataPlane(
+	ctx context.Context,
+	logger logr.Logger,
+	cl client.Client,
+	dp *operatorv1beta1.DataPlane,
+	zones []string,
+	opts ...k8sresources.ServiceOpt,
+) (op.Result, error) {
+	matchingLabels := k8sresources.GetManagedLabelForOwner(dp)
+	matchingLabels[consts.DataPlaneServiceTypeLabel] = string(consts.DataPlaneZoneIngressServiceLabelValue)
+	services, err := k8sutils.ListServicesForOwner(ctx, cl, dp.Namespace, dp.UID, matchingLabels)
+	if err != nil {
+		return op.Noop, fmt.Errorf("failed listing per-zone ingress Services for DataPlane %s/%s: %w", dp.Namespace, dp.Name, err)
+	}
+	if !perZoneIngressServicesEnabled(dp) {
+		zones = nil
+	}
+
+	// Delete the Services for zones which are not present anymore and the duplicates.
+	existing := make(map[string]*corev1.Service, len(services))
+	var deleted bool
+	for i := range services {
+		svc := &services[i]
+		zone := svc.Labels[consts.DataPlaneZoneLabel]
+		if _, duplicate := existing[zone]; !duplicate && lo.Contains(zones, zone) {
+			existing[zone] = svc
+			continue
+		}
+		if err := dataplane.OwnedObjectPreDeleteHook(ctx, cl, svc); err != nil {
+			return op.Noop, fmt.Errorf("failed executing pre delete hook: %w", err)
+		}
+		if err := cl.Delete(ctx, svc); client.IgnoreNotFound(err) != nil {
+			return op.Noop, fmt.Errorf("failed deleting per-zone ingress Service %s: %w", svc.Name, err)
+		}
+		log.Debug(logger, "deleted per-zone ingress Service", "service", svc.Name, "zone", zone)
+		deleted = true
+	}
+	if deleted {
+		return op.Deleted, nil
+	}
+
+	res := op.Noop
+	for _, zone := range zones {
+		generated, err := k8sresources.GenerateNewZoneIngressServiceForDataPlane(dp, zone, opts...)
+		if err != nil {
+			return op.Noop, err
+		}
+		addAnnotationsForDataPlaneIngressService(generated, *dp)
+
+		svc, ok := existing[zone]
+		if !ok {
+			if err := cl.Create(ctx, generated); err != nil {
+				return op.Noop, fmt.Errorf("failed creating per-zone ingress Service for zone %s: %w", zone, err)
+			}
+			log.Debug(logger, "created per-zone ingress Service", "service", generated.Name, "zone", zone)
+			res = op.Created
+			continue
+		}
+
+		old := svc.DeepCopy()
+		updated, meta := k8sutils.EnsureObjectMetaIsUpdated(svc.ObjectMeta, generated.ObjectMeta,
+			func(existingMeta metav1.ObjectMeta, generatedMeta metav1.ObjectMeta) (bool, metav1.ObjectMeta) {
+				metaToUpdate, updatedAnnotations, err := ensureDataPlaneIngressServiceAnnotationsUpdated(
+					dp, existingMeta.Annotations, generatedMeta.Annotations,
+				)
+				if err != nil {
+					logger.Error(err, "failed to update annotations of existing per-zone ingress service for dataplane",
+						"service", svc.Name,
+					)
+					return true, existingMeta
+				}
+				existingMeta.Annotations = updatedAnnotations
+				return metaToUpdate, existingMeta
+			})
+		svc.ObjectMeta = meta
+		if svc.Spec.Type != generated.Spec.Type {
+			svc.Spec.Type = generated.Spec.Type
+			updated = true
+		}
+		if svc.Spec.ExternalTrafficPolicy != generated.Spec.ExternalTrafficPolicy && generated.Spec.ExternalTrafficPolicy != "" {
+			svc.Spec.ExternalTrafficPolicy = generated.Spec.ExternalTrafficPolicy
+			updated = true
+		}
+		if !cmp.Equal(svc.Spec.Selector, generated.Spec.Selector) {
+			svc.Spec.Selector = generated.Spec.Selector
+			updated = true
+		}
+		if !comparePorts(svc.Spec.Ports, generated.Spec.Ports, dp) {
+			svc.Spec.Ports = generated.Spec.Ports
+			updated = true
+		}
+		if !cmp.Equal(svc.Spec.TrafficDistribution, generated.Spec.TrafficDistribution) {
+			svc.Spec.TrafficDistribution = generated.Spec.TrafficDistribution
+			updated = true
+		}
+
+		patchRes, _, err := patch.ApplyPatchIfNotEmpty(ctx, cl, logger, svc, old, updated)
+		if err != nil {
+			return op.Noop, fmt.Errorf("failed updating per-zone ingress Service %s: %w", svc.Name, err)
+		}
+		if patchRes != op.Noop && res == op.Noop {
+			res = patchRes
+		}
+	}
+	return res, nil
+}
+
+// ensureDataPlaneZoneDistributionStatus reports the number of DataPlane
+// replicas per zone in the DataPlane's ZoneDistribution status condition.
+// The condition is removed when zone awareness is not enabled for the DataPlane.
+func ensureDataPlaneZoneDistributionStatus(
+	ctx context.Context,
+	cl client.Client,
+	dp *operatorv1beta1.DataPlane,
+	zones *dataPlaneZones,
+) (ctrl.Result, error) {
+	if zones == nil {
+		if !k8sutils.HasCondition(DataPlaneConditionTypeZoneDistribution, dp) {
+			return ctrl.Result{}, nil
+		}
+		old := dp.DeepCopy()
+		dp.Status.Conditions = lo.Reject(dp.Status.Conditions, func(c metav1.Condition, _ int) bool {
+			return c.Type == string(DataPlaneConditionTypeZoneDistribution)
+		})
+		if err := cl.Status().Patch(ctx, dp, client.MergeFrom(old)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed removing %s condition: %w", DataPlaneConditionTypeZoneDistribution, err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// The condition is True as it's informational and should not
+	// affect the DataPlane's readiness.
+	return patch.StatusWithCondition(ctx, cl, dp,
+		DataPlaneConditionTypeZoneDistribution,
+		metav1.ConditionTrue,
+		DataPlaneConditionReasonReplicasPerZone,
+		zoneDistributionMessage(zones),
+	)
+}
+
+// zoneDistributionMessage returns the message of the ZoneDistribution condition
+// listing the ready and total replicas in each zone of the cluster,
+// e.g. "zone-a: 2/2, zone-b: 1/2, zone-c: 0/0 (ready/total replicas)".
+func zoneDistributionMessage(zones *dataPlaneZones) string {
+	allZones := lo.Uniq(append(slices.Clone(zones.Zones), lo.Keys(zones.Replicas)...))
+	if len(allZones) == 0 {
+		return "No zones found in the cluster"
+	}
+	slices.Sort(allZones)
+	entries := lo.Map(allZones, func(zone string, _ int) string {
+		r := zones.Replicas[zone]
+		return fmt.Sprintf("%s: %d/%d", zone, r.ReadyReplicas, r.Replicas)
+	})
+	return strings.Join(entries, ", ") + " (ready/total replicas)"
+}
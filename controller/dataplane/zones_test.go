@@ -0,0 +1,275 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestDataPlaneTopologySpread(t *testing.T) {
+	testCases := []struct {
+		name                      string
+		annotations               map[string]string
+		expectedTopologyKeys      []string
+		expectedWhenUnsatisfiable corev1.UnsatisfiableConstraintAction
+		expectedErr               bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name: "zone",
+			annotations: map[string]string{
+				consts.AnnotationDataPlaneTopologySpread: "zone",
+			},
+			expectedTopologyKeys:      []string{"topology.kubernetes.io/zone"},
+			expectedWhenUnsatisfiable: corev1.ScheduleAnyway,
+		},
+		{
+			name: "zone and node with DoNotSchedule",
+			annotations: map[string]string{
+				consts.AnnotationDataPlaneTopologySpread:                  "zone, node,zone",
+				consts.AnnotationDataPlaneTopologySpreadWhenUnsatisfiable: "DoNotSchedule",
+			},
+			expectedTopologyKeys:      []string{"topology.kubernetes.io/zone", "kubernetes.io/hostname"},
+			expectedWhenUnsatisfiable: corev1.DoNotSchedule,
+		},
+		{
+			name: "unknown domain",
+			annotations: map[string]string{
+				consts.AnnotationDataPlaneTopologySpread: "region",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid when unsatisfiable",
+			annotations: map[string]string{
+				consts.AnnotationDataPlaneTopologySpread:                  "node",
+				consts.AnnotationDataPlaneTopologySpreadWhenUnsatisfiable: "Sometimes",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dp := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+			}
+			topologyKeys, whenUnsatisfiable, err := dataPlaneTopologySpread(dp)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTopologyKeys, topologyKeys)
+			assert.Equal(t, tc.expectedWhenUnsatisfiable, whenUnsatisfiable)
+		})
+	}
+}
+
+func TestTopologySpreadDeploymentOpt(t *testing.T) {
+	d := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":                        "dp",
+					consts.OperatorLabelSelector: "selector",
+				},
+			},
+		},
+	}
+
+	opt := topologySpreadDeploymentOpt([]string{corev1.LabelTopologyZone, corev1.LabelHostname}, corev1.DoNotSchedule)
+	opt(d)
+	// Applying the opt again does not duplicate the constraints.
+	opt(d)
+
+	expectedSelector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"app":                        "dp",
+			consts.OperatorLabelSelector: "selector",
+		},
+	}
+	assert.Equal(t, []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     expectedSelector,
+			MatchLabelKeys:    []string{"pod-template-hash"},
+		},
+		{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelHostname,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     expectedSelector,
+			MatchLabelKeys:    []string{"pod-template-hash"},
+		},
+	}, d.Spec.Template.Spec.TopologySpreadConstraints)
+}
+
+func TestEnsureDataPlanePodsZones(t *testing.T) {
+	node := func(name, zone string) *corev1.Node {
+		n := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if zone != "" {
+			n.Labels = map[string]string{corev1.LabelTopologyZone: zone}
+		}
+		return n
+	}
+	pod := func(name, nodeName string, ready bool, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    labels,
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: lo.Ternary(ready, corev1.ConditionTrue, corev1.ConditionFalse)},
+				},
+			},
+		}
+	}
+	dpLabels := map[string]string{"app": "dp", consts.OperatorLabelSelector: "selector"}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "dp-deployment", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: dpLabels},
+		},
+	}
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			node("node-a-1", "zone-a"),
+			node("node-a-2", "zone-a"),
+			node("node-b-1", "zone-b"),
+			node("node-c-1", "zone-c"),
+			node("node-no-zone", ""),
+			pod("pod-1", "node-a-1", true, dpLabels),
+			pod("pod-2", "node-a-2", false, dpLabels),
+			pod("pod-3", "node-b-1", true, map[string]string{
+				"app": "dp", consts.OperatorLabelSelector: "selector", consts.DataPlaneZoneLabel: "outdated",
+			}),
+			pod("pod-unscheduled", "", false, dpLabels),
+			pod("pod-no-zone", "node-no-zone", true, dpLabels),
+			pod("pod-other", "node-c-1", true, map[string]string{"app": "other"}),
+		).
+		Build()
+
+	zones, err := ensureDataPlanePodsZones(context.Background(), cl, logr.Discard(), deployment)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zone-a", "zone-b", "zone-c"}, zones.Zones)
+	assert.Equal(t, map[string]zoneReplicas{
+		"zone-a": {Replicas: 2, ReadyReplicas: 1},
+		"zone-b": {Replicas: 1, ReadyReplicas: 1},
+	}, zones.Replicas)
+	assert.Equal(t, "zone-a: 1/2, zone-b: 1/1, zone-c: 0/0 (ready/total replicas)", zoneDistributionMessage(&zones))
+
+	expectedZoneLabels := map[string]string{
+		"pod-1":           "zone-a",
+		"pod-2":           "zone-a",
+		"pod-3":           "zone-b",
+		"pod-unscheduled": "",
+		"pod-no-zone":     "",
+		"pod-other":       "",
+	}
+	for name, zone := range expectedZoneLabels {
+		var p corev1.Pod
+		require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, &p))
+		assert.Equal(t, zone, p.Labels[consts.DataPlaneZoneLabel], "unexpected zone label of Pod %s", name)
+	}
+}
+
+func TestEnsureZoneIngressServicesForDataPlane(t *testing.T) {
+	dp := &operatorv1beta1.DataPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+			Kind:       "DataPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+			UID:       "1234",
+			Annotations: map[string]string{
+				consts.AnnotationDataPlanePerZoneIngressServices:            "true",
+				consts.AnnotationDataPlaneIngressServiceTrafficDistribution: "PreferClose",
+			},
+		},
+		Status: operatorv1beta1.DataPlaneStatus{
+			Selector: "selector",
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		Build()
+	ctx := context.Background()
+
+	listZoneServices := func(t *testing.T) map[string]corev1.Service {
+		services, err := k8sutils.ListServicesForOwner(ctx, cl, dp.Namespace, dp.UID, client.MatchingLabels{
+			consts.DataPlaneServiceTypeLabel: string(consts.DataPlaneZoneIngressServiceLabelValue),
+		})
+		require.NoError(t, err)
+		return lo.SliceToMap(services, func(s corev1.Service) (string, corev1.Service) {
+			return s.Labels[consts.DataPlaneZoneLabel], s
+		})
+	}
+	ensure := func(t *testing.T, zones []string) op.Result {
+		res, err := ensureZoneIngressServicesForDataPlane(ctx, logr.Discard(), cl, dp, zones,
+			k8sresources.LabelSelectorFromDataPlaneStatusSelectorServiceOpt(dp),
+		)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Log("creating Services for all zones")
+	require.Equal(t, op.Created, ensure(t, []string{"zone-a", "zone-b"}))
+	services := listZoneServices(t)
+	require.Len(t, services, 2)
+	svc := services["zone-a"]
+	assert.Equal(t, map[string]string{
+		"app":                        "dp",
+		consts.OperatorLabelSelector: "selector",
+		consts.DataPlaneZoneLabel:    "zone-a",
+	}, svc.Spec.Selector)
+	assert.Equal(t, lo.ToPtr(corev1.ServiceTrafficDistributionPreferClose), svc.Spec.TrafficDistribution)
+
+	t.Log("nothing changes when Services are up to date")
+	require.Equal(t, op.Noop, ensure(t, []string{"zone-a", "zone-b"}))
+
+	t.Log("updating Services when DataPlane changes")
+	delete(dp.Annotations, consts.AnnotationDataPlaneIngressServiceTrafficDistribution)
+	require.Equal(t, op.Updated, ensure(t, []string{"zone-a", "zone-b"}))
+	services = listZoneServices(t)
+	assert.Nil(t, services["zone-a"].Spec.TrafficDistribution)
+
+	t.Log("deleting Services of zones which are gone")
+	require.Equal(t, op.Deleted, ensure(t, []string{"zone-b"}))
+	services = listZoneServices(t)
+	require.Len(t, services, 1)
+	require.Contains(t, services, "zone-b")
+
+	t.Log("deleting all Services when per-zone ingress Services are disabled")
+	delete(dp.Annotations, consts.AnnotationDataPlanePerZoneIngressServices)
+	require.Equal(t, op.Deleted, ensure(t, []string{"zone-b"}))
+	require.Empty(t, listZoneServices(t))
+}
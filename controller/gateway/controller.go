@@ -11,9 +11,13 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,13 +26,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	controlplanecontroller "github.com/kong/gateway-operator/controller/pkg/controlplane"
+	"github.com/kong/gateway-operator/controller/pkg/deletionprotection"
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/op"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/secrets/ref"
 	"github.com/kong/gateway-operator/controller/pkg/watch"
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
@@ -58,10 +65,18 @@ import (
 type Reconciler struct {
 	client.Client
 	Scheme                  *runtime.Scheme
+	eventRecorder           record.EventRecorder
 	DefaultDataPlaneImage   string
 	KonnectEnabled          bool
 	AnonymousReportsEnabled bool
 	LoggingMode             logging.Mode
+	// DataPlaneMetrics provides the request rate scraped from DataPlanes, used
+	// to protect Gateways from deletion while their DataPlanes serve traffic.
+	DataPlaneMetrics deletionprotection.DataPlaneMetrics
+
+	// egressDependencies caches what the NetworkPolicy egress rules of the
+	// DataPlanes with restricted egress depend on, for the watches.
+	egressDependencies *egressDependenciesCache
 }
 
 // provisionDataPlaneFailRequeueAfter is the time duration after which we retry provisioning
@@ -70,15 +85,31 @@ const provisionDataPlaneFailRetryAfter = 5 * time.Second
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("gateway")
+	r.egressDependencies = &egressDependenciesCache{}
+
 	builder := ctrl.NewControllerManagedBy(mgr).
 		// watch Gateway objects, filtering out any Gateways which are not configured with
 		// a supported GatewayClass controller name.
 		For(&gwtypes.Gateway{},
 			builder.WithPredicates(predicate.NewPredicateFuncs(r.gatewayHasMatchingGatewayClass))).
-		// watch for changes in dataplanes created by the gateway controller
-		Owns(&operatorv1beta1.DataPlane{}).
+		// watch for changes in dataplanes created by the gateway controller.
+		// Dataplanes shared by multiple Gateways have no controller owner,
+		// hence all the owning Gateways are enqueued.
+		Watches(
+			&operatorv1beta1.DataPlane{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &gwtypes.Gateway{})).
 		// watch for changes in controlplanes created by the gateway controller
-		Owns(&operatorv1beta1.ControlPlane{}).
+		Watches(
+			&operatorv1beta1.ControlPlane{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &gwtypes.Gateway{})).
+		// watch for changes in Gateways sharing dataplanes with other Gateways so
+		// that conflicts between their listeners are reflected in all of them and
+		// for changes in Gateways of the same GatewayClass which determine whether
+		// the infrastructure can be shared.
+		Watches(
+			&gwtypes.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysSharingInfrastructure)).
 		// watch for changes in networkpolicies created by the gateway controller
 		Owns(&networkingv1.NetworkPolicy{}).
 		// watch for updates to GatewayConfigurations, if any configuration targets a
@@ -109,7 +140,50 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		// This is required to properly support Gateway's listeners.allowedRoutes.namespaces.selector.
 		Watches(
 			&corev1.Namespace{},
-			handler.EnqueueRequestsFromMapFunc(r.listManagedGatewaysInNamespace))
+			handler.EnqueueRequestsFromMapFunc(r.listManagedGatewaysInNamespace)).
+		// watch the routes, Services, EndpointSlices, ReferenceGrants and hybrid
+		// control plane ConfigMaps which the egress rules of the NetworkPolicies of
+		// DataPlanes with restricted egress depend on. Gateways sharing a DataPlane
+		// are all enqueued as their NetworkPolicies include each other's backends.
+		// The dependencies are cached when generating the NetworkPolicies, so
+		// events are only mapped to Gateways when egress restriction is in use.
+		Watches(
+			&gatewayv1.HTTPRoute{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForEgressObject)).
+		Watches(
+			&corev1.Service{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForEgressObject)).
+		Watches(
+			&discoveryv1.EndpointSlice{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForEgressObject)).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForEgressObject)).
+		Watches(
+			&gatewayv1beta1.ReferenceGrant{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForEgressObject))
+
+	// GRPCRoutes, TLSRoutes, TCPRoutes and UDPRoutes are only watched when
+	// their CRDs are installed, as some are part of the Gateway API
+	// experimental channel.
+	checker := k8sutils.CRDChecker{Client: mgr.GetClient()}
+	for _, route := range []struct {
+		obj client.Object
+		gvr schema.GroupVersionResource
+	}{
+		{obj: &gatewayv1.GRPCRoute{}, gvr: gatewayv1.SchemeGroupVersion.WithResource("grpcroutes")},
+		{obj: &gatewayv1alpha2.TLSRoute{}, gvr: gatewayv1alpha2.SchemeGroupVersion.WithResource("tlsroutes")},
+		{obj: &gatewayv1alpha2.TCPRoute{}, gvr: gatewayv1alpha2.SchemeGroupVersion.WithResource("tcproutes")},
+		{obj: &gatewayv1alpha2.UDPRoute{}, gvr: gatewayv1alpha2.SchemeGroupVersion.WithResource("udproutes")},
+	} {
+		ok, err := checker.CRDExists(route.gvr)
+		if err != nil {
+			return err
+		}
+		if ok {
+			builder.Watches(route.obj, handler.EnqueueRequestsFromMapFunc(r.listGatewaysForEgressObject))
+		}
+	}
 
 	if r.KonnectEnabled {
 		// Watch for changes in KonnectExtension objects that are referenced by GatewayConfigurations used by Gateways objects.
@@ -126,14 +200,29 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 }
 
 // Reconcile moves the current state of an object to the intended state.
-func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, reconcileErr error) {
 	logger := log.GetLogger(ctx, "gateway", r.LoggingMode)
 
 	log.Trace(logger, "reconciling gateway resource")
 	var gateway gwtypes.Gateway
 	if err := r.Get(ctx, req.NamespacedName, &gateway); err != nil {
+		if k8serrors.IsNotFound(err) {
+			r.egressDependencies.deleteForGateway(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	defer func() { result = deletionprotection.RequeueProtected(&gateway, result, reconcileErr) }()
+
+	log.Trace(logger, "managing deletion protection for gateway resource")
+	if blocked, res, err := deletionprotection.Reconcile(ctx, r.Client, r.eventRecorder, "Gateway", &gateway,
+		gatewayConditionsAndListenersAware(&gateway),
+		func() (deletionprotection.Blockers, error) { return r.deletionBlockers(ctx, &gateway) },
+	); err != nil || blocked || !res.IsZero() {
+		if blocked {
+			log.Debug(logger, "gateway deletion blocked by deletion protection")
+		}
+		return res, err
+	}
 
 	log.Trace(logger, "managing cleanup for gateway resource")
 	if shouldReturnEarly, result, err := r.cleanup(ctx, logger, &gateway); err != nil || !result.IsZero() {
@@ -163,6 +252,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	log.Trace(logger, "checking if gateway reconciliation is paused")
+	if paused, res, err := pause.Reconcile(ctx, r.Client, r.eventRecorder, &gateway, gatewayConditionsAndListenersAware(&gateway)); err != nil || paused || !res.IsZero() {
+		if paused {
+			log.Debug(logger, "gateway reconciliation is paused")
+		}
+		return res, err
+	}
+
 	log.Trace(logger, "managing the gateway resource finalizers")
 	cpFinalizerSet := controllerutil.AddFinalizer(&gateway, string(GatewayFinalizerCleanupControlPlanes))
 	dpFinalizerSet := controllerutil.AddFinalizer(&gateway, string(GatewayFinalizerCleanupDataPlanes))
@@ -193,6 +290,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	log.Trace(logger, "resource is supported that it gets marked as accepted")
 	gwConditionAware.initListenersStatus()
 	gwConditionAware.setConflicted()
+	shared, err := r.getSharedInfrastructure(ctx, gwc.GatewayClass, &gateway)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	gwConditionAware.setSharedInfrastructureConflicted(shared)
 	if err = gwConditionAware.setAcceptedAndAttachedRoutes(ctx, r.Client); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -222,7 +324,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	log.Trace(logger, "determining configuration")
-	gatewayConfig, err := r.getOrCreateGatewayConfiguration(ctx, gwc.GatewayClass)
+	gatewayConfig, err := r.getOrCreateGatewayConfiguration(ctx, gwc.GatewayClass, &gateway)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// During a staged rollout the Gateway is provisioned with the revision of
+	// the GatewayConfiguration it's pinned to rather than its current spec.
+	gatewayConfig, err = gatewayConfigForGatewayRevision(ctx, r.Client, gatewayConfig, &gateway)
+	if errors.Is(err, errGatewayConfigRevisionNotFound) {
+		log.Debug(logger, "GatewayConfiguration revision of the gateway not found, requeueing", "error", err)
+		if r.eventRecorder != nil {
+			r.eventRecorder.Event(&gateway, corev1.EventTypeWarning, "GatewayConfigurationRevisionNotFound", err.Error())
+		}
+		return ctrl.Result{RequeueAfter: stagedRolloutCheckInterval}, nil
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -230,7 +345,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// Provision dataplane creates a dataplane and adds the DataPlaneReady=True
 	// condition to the Gateway status if the dataplane is ready. If not ready
 	// the status DataPlaneReady=False will be set instead.
-	dataplane, provisionErr := r.provisionDataPlane(ctx, logger, &gateway, gatewayConfig)
+	dataplane, provisionErr := r.provisionDataPlane(ctx, logger, &gateway, gatewayConfig, shared)
+	// Conflicts updating the owners of shared DataPlanes are retried right away.
+	if k8serrors.IsConflict(provisionErr) {
+		log.Debug(logger, "conflict updating the owners of the dataplane, requeueing")
+		return ctrl.Result{Requeue: true}, nil
+	}
 
 	// Set the DataPlaneReady Condition to False. This happens only if:
 	// * the new status is false and there was no DataPlaneReady condition in the old gateway, or
@@ -351,7 +471,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	// Provision controlplane creates a controlplane and adds the ControlPlaneReady condition to the Gateway status
 	// if the controlplane is ready, the ControlPlaneReady status is set to true, otherwise false.
-	controlplane := r.provisionControlPlane(ctx, logger, gwc.GatewayClass, &gateway, gatewayConfig, dataplane, ingressServices[0], adminServices[0])
+	controlplane := r.provisionControlPlane(ctx, logger, gwc.GatewayClass, &gateway, gatewayConfig, shared, dataplane, ingressServices[0], adminServices[0])
 	// Set the ControlPlaneReady Condition to False. This happens only if:
 	// * the new status is false and there was no ControlPlaneReady condition in the gateway
 	// * the new status is false and the previous status was true
@@ -398,16 +518,34 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	log.Trace(logger, "ensuring DataPlane connectivity for Gateway")
+	var (
+		addressesReason  gatewayv1.GatewayConditionReason
+		addressesMessage string
+	)
 	gateway.Status.Addresses, err = r.getGatewayAddresses(ctx, dataplane)
-	if err == nil {
-		k8sutils.SetCondition(k8sutils.NewConditionWithGeneration(kcfggateway.GatewayServiceType, metav1.ConditionTrue, kcfgdataplane.ResourceReadyReason, "", gateway.Generation),
-			gatewayConditionsAndListenersAware(&gateway))
-	} else {
+	if err == nil && len(gateway.Spec.Addresses) > 0 {
+		requestedAddresses := gatewayStaticAddresses(&gateway, dataPlaneIngressServiceType(&dataplane.Spec.DataPlaneOptions))
+		gateway.Status.Addresses, addressesReason, addressesMessage = gatewayStatusAddressesForStaticAddresses(requestedAddresses, gateway.Status.Addresses)
+	}
+	switch {
+	case err != nil:
 		k8sutils.SetCondition(k8sutils.NewConditionWithGeneration(kcfggateway.GatewayServiceType, metav1.ConditionFalse, kcfggateway.GatewayReasonServiceError, err.Error(), gateway.Generation),
 			gatewayConditionsAndListenersAware(&gateway))
+	case addressesReason != "":
+		k8sutils.SetCondition(k8sutils.NewConditionWithGeneration(kcfggateway.GatewayServiceType, metav1.ConditionFalse, kcfgconsts.ConditionReason(addressesReason), addressesMessage, gateway.Generation),
+			gatewayConditionsAndListenersAware(&gateway))
+	default:
+		k8sutils.SetCondition(k8sutils.NewConditionWithGeneration(kcfggateway.GatewayServiceType, metav1.ConditionTrue, kcfgdataplane.ResourceReadyReason, "", gateway.Generation),
+			gatewayConditionsAndListenersAware(&gateway))
 	}
 
 	gwConditionAware.setProgrammed()
+	if addressesReason != "" {
+		// Static addresses which are not assigned or usable are reported with
+		// the dedicated Programmed condition reasons.
+		k8sutils.SetCondition(k8sutils.NewConditionWithGeneration(kcfgconsts.ConditionType(gatewayv1.GatewayConditionProgrammed), metav1.ConditionFalse, kcfgconsts.ConditionReason(addressesReason), addressesMessage, gateway.Generation),
+			gwConditionAware)
+	}
 	res, err := patch.ApplyStatusPatchIfNotEmpty(ctx, r.Client, logger, &gateway, oldGateway)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -429,16 +567,13 @@ func (r *Reconciler) provisionDataPlane(
 	logger logr.Logger,
 	gateway *gwtypes.Gateway,
 	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	shared *sharedInfrastructure,
 ) (*operatorv1beta1.DataPlane, error) {
 	logger = logger.WithName("dataplaneProvisioning")
 
 	r.setDataPlaneGatewayConfigDefaults(gatewayConfig)
 	log.Trace(logger, "looking for associated dataplanes")
-	dataplanes, err := gatewayutils.ListDataPlanesForGateway(
-		ctx,
-		r.Client,
-		gateway,
-	)
+	dataplanes, err := r.listDataPlanesForGateway(ctx, gateway, shared)
 	if err != nil {
 		errWrap := fmt.Errorf("failed listing associated dataplanes - error: %w", err)
 		k8sutils.SetCondition(
@@ -462,7 +597,7 @@ func (r *Reconciler) provisionDataPlane(
 		return nil, err
 	}
 	if count == 0 {
-		dataplane, err := r.createDataPlane(ctx, gateway, gatewayConfig)
+		dataplane, err := r.createDataPlane(ctx, gateway, gatewayConfig, shared)
 		if err != nil {
 			errWrap := fmt.Errorf("dataplane creation failed - error: %w", err)
 			k8sutils.SetCondition(
@@ -489,7 +624,7 @@ func (r *Reconciler) provisionDataPlane(
 	}
 	// Don't require setting defaults for DataPlane when using Gateway CRD.
 	setDataPlaneOptionsDefaults(expectedDataPlaneOptions, r.DefaultDataPlaneImage)
-	err = setDataPlaneIngressServicePorts(expectedDataPlaneOptions, gateway.Spec.Listeners)
+	err = setDataPlaneIngressServicePorts(expectedDataPlaneOptions, shared.listeners(gateway))
 	if err != nil {
 		errWrap := fmt.Errorf("dataplane creation failed - error: %w", err)
 		k8sutils.SetCondition(
@@ -499,12 +634,24 @@ func (r *Reconciler) provisionDataPlane(
 		return nil, errWrap
 	}
 
+	infraGateway := shared.infrastructureGateway(gateway)
+	requestedAddresses := gatewayStaticAddresses(infraGateway, dataPlaneIngressServiceType(expectedDataPlaneOptions))
+	setDataPlaneIngressServiceHostnames(expectedDataPlaneOptions, requestedAddresses.Hostnames)
+
 	expectedDataPlaneOptions.Extensions = extensions.MergeExtensions(gatewayConfig.Spec.Extensions, expectedDataPlaneOptions.Extensions)
 
-	if !dataplaneSpecDeepEqual(&dataplane.Spec.DataPlaneOptions, expectedDataPlaneOptions) {
+	oldDataPlane := dataplane.DeepCopy()
+	infraLabels, infraAnnotations := infrastructureMetadata(infraGateway)
+	metadataChanged := k8sresources.SetPropagatedMetadata(dataplane, infraLabels, infraAnnotations)
+	metadataChanged = setDataPlaneStaticIPs(dataplane, requestedAddresses.IPs) || metadataChanged
+	metadataChanged = setDataPlaneAnnotations(dataplane, gatewayConfig) || metadataChanged
+	gatewayutils.LabelObjectAsGatewayManaged(dataplane)
+
+	if specChanged := !dataplaneSpecDeepEqual(&dataplane.Spec.DataPlaneOptions, expectedDataPlaneOptions); specChanged || metadataChanged {
 		log.Trace(logger, "dataplane config is out of date")
-		oldDataPlane := dataplane.DeepCopy()
-		dataplane.Spec.DataPlaneOptions = *expectedDataPlaneOptions
+		if specChanged {
+			dataplane.Spec.DataPlaneOptions = *expectedDataPlaneOptions
+		}
 
 		if err = r.Patch(ctx, dataplane, client.MergeFrom(oldDataPlane)); err != nil {
 			k8sutils.SetCondition(
@@ -542,6 +689,7 @@ func (r *Reconciler) provisionControlPlane(
 	gatewayClass *gatewayv1.GatewayClass,
 	gateway *gwtypes.Gateway,
 	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	shared *sharedInfrastructure,
 	dataplane *operatorv1beta1.DataPlane,
 	ingressService corev1.Service,
 	adminService corev1.Service,
@@ -549,7 +697,7 @@ func (r *Reconciler) provisionControlPlane(
 	logger = logger.WithName("controlplaneProvisioning")
 
 	log.Trace(logger, "looking for associated controlplanes")
-	controlplanes, err := gatewayutils.ListControlPlanesForGateway(ctx, r.Client, gateway)
+	controlplanes, err := r.listControlPlanesForGateway(ctx, gateway, shared)
 	if err != nil {
 		log.Debug(logger, fmt.Sprintf("failed listing associated controlplanes - error: %v", err))
 		k8sutils.SetCondition(
@@ -564,8 +712,8 @@ func (r *Reconciler) provisionControlPlane(
 	count := len(controlplanes)
 	switch {
 	case count == 0:
-		r.setControlPlaneGatewayConfigDefaults(gateway, gatewayConfig, dataplane.Name, ingressService.Name, adminService.Name, "")
-		err := r.createControlPlane(ctx, gatewayClass, gateway, gatewayConfig, dataplane.Name)
+		r.setControlPlaneGatewayConfigDefaults(gateway, gatewayConfig, shared, dataplane.Name, ingressService.Name, adminService.Name, "")
+		err := r.createControlPlane(ctx, gatewayClass, gateway, gatewayConfig, dataplane.Name, shared)
 		if err != nil {
 			log.Debug(logger, fmt.Sprintf("controlplane creation failed - error: %v", err))
 			k8sutils.SetCondition(
@@ -591,7 +739,7 @@ func (r *Reconciler) provisionControlPlane(
 
 	// If we continue, there is only one controlplane.
 	controlPlane = controlplanes[0].DeepCopy()
-	r.setControlPlaneGatewayConfigDefaults(gateway, gatewayConfig, dataplane.Name, ingressService.Name, adminService.Name, controlPlane.Name)
+	r.setControlPlaneGatewayConfigDefaults(gateway, gatewayConfig, shared, dataplane.Name, ingressService.Name, adminService.Name, controlPlane.Name)
 
 	log.Trace(logger, "ensuring controlplane config is up to date")
 	// compare deployment option of controlplane with controlplane deployment option of gatewayconfiguration.
@@ -605,10 +753,17 @@ func (r *Reconciler) provisionControlPlane(
 
 	expectedControlPlaneOptions.Extensions = extensions.MergeExtensions(gatewayConfig.Spec.Extensions, expectedControlPlaneOptions.Extensions)
 
-	if !controlplanecontroller.SpecDeepEqual(&controlPlane.Spec.ControlPlaneOptions, expectedControlPlaneOptions) {
+	controlplaneOld := controlPlane.DeepCopy()
+	infraLabels, infraAnnotations := infrastructureMetadata(shared.infrastructureGateway(gateway))
+	metadataChanged := k8sresources.SetPropagatedMetadata(controlPlane, infraLabels, infraAnnotations)
+	metadataChanged = setControlPlaneAnnotations(controlPlane, gatewayConfig) || metadataChanged
+	gatewayutils.LabelObjectAsGatewayManaged(controlPlane)
+
+	if specChanged := !controlplanecontroller.SpecDeepEqual(&controlPlane.Spec.ControlPlaneOptions, expectedControlPlaneOptions); specChanged || metadataChanged {
 		log.Trace(logger, "controlplane config is out of date")
-		controlplaneOld := controlPlane.DeepCopy()
-		controlPlane.Spec.ControlPlaneOptions = *expectedControlPlaneOptions
+		if specChanged {
+			controlPlane.Spec.ControlPlaneOptions = *expectedControlPlaneOptions
+		}
 		if err := r.Patch(ctx, controlPlane, client.MergeFrom(controlplaneOld)); err != nil {
 			k8sutils.SetCondition(
 				createControlPlaneCondition(metav1.ConditionFalse, kcfgdataplane.UnableToProvisionReason, err.Error(), gateway.Generation),
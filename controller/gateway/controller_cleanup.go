@@ -67,6 +67,10 @@ func (r *Reconciler) cleanup(
 	}
 	if len(controlplanes) > 0 {
 		deletions, err := r.ensureOwnedControlPlanesDeleted(ctx, gateway)
+		if k8serrors.IsConflict(err) {
+			// Shared controlplanes were changed by other Gateways in the meantime.
+			return true, ctrl.Result{Requeue: true}, nil
+		}
 		if err != nil {
 			return false, ctrl.Result{}, err
 		}
@@ -97,6 +101,10 @@ func (r *Reconciler) cleanup(
 
 	if len(dataplanes) > 0 {
 		deletions, err := r.ensureOwnedDataPlanesDeleted(ctx, gateway)
+		if k8serrors.IsConflict(err) {
+			// Shared dataplanes were changed by other Gateways in the meantime.
+			return true, ctrl.Result{Requeue: true}, nil
+		}
 		if err != nil {
 			return false, ctrl.Result{}, err
 		}
@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
+	dataplanepkg "github.com/kong/gateway-operator/controller/pkg/dataplane"
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
 	"github.com/kong/gateway-operator/controller/pkg/secrets/ref"
@@ -46,6 +47,25 @@ import (
 func (r *Reconciler) createDataPlane(ctx context.Context,
 	gateway *gwtypes.Gateway,
 	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	shared *sharedInfrastructure,
+) (*operatorv1beta1.DataPlane, error) {
+	dataplane, err := r.generateDataPlane(gateway, gatewayConfig, shared)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, dataplane); err != nil {
+		return nil, err
+	}
+	return dataplane, nil
+}
+
+// generateDataPlane generates the DataPlane for the provided Gateway, using
+// the provided GatewayConfiguration. When the infrastructure is shared, the
+// DataPlane serves the listeners of all the Gateways sharing it.
+func (r *Reconciler) generateDataPlane(
+	gateway *gwtypes.Gateway,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	shared *sharedInfrastructure,
 ) (*operatorv1beta1.DataPlane, error) {
 	dataplane := &operatorv1beta1.DataPlane{
 		ObjectMeta: metav1.ObjectMeta{
@@ -57,18 +77,21 @@ func (r *Reconciler) createDataPlane(ctx context.Context,
 		dataplane.Spec.DataPlaneOptions = *gatewayConfigDataPlaneOptionsToDataPlaneOptions(gatewayConfig.Namespace, *gatewayConfig.Spec.DataPlaneOptions)
 	}
 	setDataPlaneOptionsDefaults(&dataplane.Spec.DataPlaneOptions, r.DefaultDataPlaneImage)
-	if err := setDataPlaneIngressServicePorts(&dataplane.Spec.DataPlaneOptions, gateway.Spec.Listeners); err != nil {
+	if err := setDataPlaneIngressServicePorts(&dataplane.Spec.DataPlaneOptions, shared.listeners(gateway)); err != nil {
 		return nil, err
 	}
+	infraGateway := shared.infrastructureGateway(gateway)
+	requestedAddresses := gatewayStaticAddresses(infraGateway, dataPlaneIngressServiceType(&dataplane.Spec.DataPlaneOptions))
+	setDataPlaneIngressServiceHostnames(&dataplane.Spec.DataPlaneOptions, requestedAddresses.Hostnames)
+	setDataPlaneStaticIPs(dataplane, requestedAddresses.IPs)
+	setDataPlaneAnnotations(dataplane, gatewayConfig)
 
 	dataplane.Spec.Extensions = extensions.MergeExtensions(gatewayConfig.Spec.Extensions, dataplane.Spec.Extensions)
 
-	k8sutils.SetOwnerForObject(dataplane, gateway)
+	infraLabels, infraAnnotations := infrastructureMetadata(infraGateway)
+	k8sresources.SetPropagatedMetadata(dataplane, infraLabels, infraAnnotations)
+	setInfrastructureOwner(dataplane, gateway, gatewayConfig, shared)
 	gatewayutils.LabelObjectAsGatewayManaged(dataplane)
-	err := r.Create(ctx, dataplane)
-	if err != nil {
-		return nil, err
-	}
 	return dataplane, nil
 }
 
@@ -78,7 +101,20 @@ func (r *Reconciler) createControlPlane(
 	gateway *gwtypes.Gateway,
 	gatewayConfig *operatorv1beta1.GatewayConfiguration,
 	dataplaneName string,
+	shared *sharedInfrastructure,
 ) error {
+	return r.Create(ctx, generateControlPlane(gatewayClass, gateway, gatewayConfig, dataplaneName, shared))
+}
+
+// generateControlPlane generates the ControlPlane for the provided Gateway,
+// using the provided GatewayConfiguration.
+func generateControlPlane(
+	gatewayClass *gatewayv1.GatewayClass,
+	gateway *gwtypes.Gateway,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	dataplaneName string,
+	shared *sharedInfrastructure,
+) *operatorv1beta1.ControlPlane {
 	controlplane := &operatorv1beta1.ControlPlane{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:    gateway.Namespace,
@@ -98,9 +134,12 @@ func (r *Reconciler) createControlPlane(
 	controlplane.Spec.Extensions = extensions.MergeExtensions(gatewayConfig.Spec.Extensions, controlplane.Spec.Extensions)
 
 	setControlPlaneOptionsDefaults(&controlplane.Spec.ControlPlaneOptions)
-	k8sutils.SetOwnerForObject(controlplane, gateway)
+	infraLabels, infraAnnotations := infrastructureMetadata(shared.infrastructureGateway(gateway))
+	k8sresources.SetPropagatedMetadata(controlplane, infraLabels, infraAnnotations)
+	setControlPlaneAnnotations(controlplane, gatewayConfig)
+	setInfrastructureOwner(controlplane, gateway, gatewayConfig, shared)
 	gatewayutils.LabelObjectAsGatewayManaged(controlplane)
-	return r.Create(ctx, controlplane)
+	return controlplane
 }
 
 func (r *Reconciler) getGatewayAddresses(
@@ -131,9 +170,26 @@ func (r *Reconciler) getGatewayAddresses(
 	if count == 0 {
 		return []gwtypes.GatewayStatusAddress{}, fmt.Errorf("no Services found for DataPlane %s/%s", dataplane.Namespace, dataplane.Name)
 	}
+	// DataPlanes running a host network DaemonSet without a LoadBalancer Service
+	// are reachable on the addresses of their Nodes, reported in their status.
+	if dataplanepkg.IsHostNetworkDaemonSetWorkload(dataplane) && services[0].Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return gatewayAddressesFromDataPlaneNodes(dataplane)
+	}
 	return gatewayAddressesFromService(services[0])
 }
 
+func gatewayAddressesFromDataPlaneNodes(dataplane *operatorv1beta1.DataPlane) ([]gwtypes.GatewayStatusAddress, error) {
+	if len(dataplane.Status.Addresses) == 0 {
+		return []gwtypes.GatewayStatusAddress{}, fmt.Errorf("DataPlane %s/%s doesn't have Node addresses yet, not ready", dataplane.Namespace, dataplane.Name)
+	}
+	return lo.Map(dataplane.Status.Addresses, func(addr operatorv1beta1.Address, _ int) gwtypes.GatewayStatusAddress {
+		return gwtypes.GatewayStatusAddress{
+			Value: addr.Value,
+			Type:  lo.ToPtr(gatewayv1.IPAddressType),
+		}
+	}), nil
+}
+
 func gatewayConfigDataPlaneOptionsToDataPlaneOptions(
 	gatewayConfigNamespace string,
 	opts operatorv1beta1.GatewayConfigDataPlaneOptions,
@@ -206,13 +262,33 @@ func gatewayAddressesFromService(svc corev1.Service) ([]gwtypes.GatewayStatusAdd
 			Value: svc.Spec.ClusterIP,
 			Type:  lo.ToPtr(gatewayv1.IPAddressType),
 		})
+		for _, externalIP := range svc.Spec.ExternalIPs {
+			addresses = append(addresses, gwtypes.GatewayStatusAddress{
+				Value: externalIP,
+				Type:  lo.ToPtr(gatewayv1.IPAddressType),
+			})
+		}
 	}
 
 	return addresses, nil
 }
 
-func (r *Reconciler) getOrCreateGatewayConfiguration(ctx context.Context, gatewayClass *gatewayv1.GatewayClass) (*operatorv1beta1.GatewayConfiguration, error) {
-	gatewayConfig, err := r.getGatewayConfigForGatewayClass(ctx, gatewayClass)
+func (r *Reconciler) getOrCreateGatewayConfiguration(
+	ctx context.Context,
+	gatewayClass *gatewayv1.GatewayClass,
+	gateway *gwtypes.Gateway,
+) (*operatorv1beta1.GatewayConfiguration, error) {
+	// The GatewayConfiguration referenced by the Gateway's infrastructure
+	// takes precedence over the one referenced by its GatewayClass.
+	gatewayConfig, err := getGatewayConfigForGateway(ctx, r.Client, gateway)
+	if err == nil {
+		return gatewayConfig, nil
+	}
+	if !errors.Is(err, operatorerrors.ErrObjectMissingParametersRef) {
+		return nil, err
+	}
+
+	gatewayConfig, err = r.getGatewayConfigForGatewayClass(ctx, gatewayClass)
 	if err != nil {
 		if errors.Is(err, operatorerrors.ErrObjectMissingParametersRef) {
 			return new(operatorv1beta1.GatewayConfiguration), nil
@@ -260,6 +336,66 @@ func (r *Reconciler) getGatewayConfigForGatewayClass(ctx context.Context, gatewa
 	}, gatewayConfig)
 }
 
+// getGatewayConfigForGateway returns the GatewayConfiguration referenced by the
+// Gateway's spec.infrastructure.parametersRef. The reference is local, hence the
+// GatewayConfiguration is looked up in the Gateway's namespace.
+func getGatewayConfigForGateway(
+	ctx context.Context,
+	cl client.Client,
+	gateway *gwtypes.Gateway,
+) (*operatorv1beta1.GatewayConfiguration, error) {
+	if gateway.Spec.Infrastructure == nil || gateway.Spec.Infrastructure.ParametersRef == nil {
+		return nil, fmt.Errorf("%w, gateway = %s", operatorerrors.ErrObjectMissingParametersRef, client.ObjectKeyFromObject(gateway))
+	}
+
+	parametersRef := gateway.Spec.Infrastructure.ParametersRef
+	if string(parametersRef.Group) != operatorv1beta1.SchemeGroupVersion.Group ||
+		string(parametersRef.Kind) != "GatewayConfiguration" {
+		msg := fmt.Sprintf("controller only supports %s %s resources for Gateway infrastructure parametersRef",
+			operatorv1beta1.SchemeGroupVersion.Group, "GatewayConfiguration")
+		return nil, &k8serrors.StatusError{
+			ErrStatus: metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    http.StatusBadRequest,
+				Reason:  metav1.StatusReasonInvalid,
+				Message: msg,
+				Details: &metav1.StatusDetails{
+					Kind: string(parametersRef.Kind),
+					Causes: []metav1.StatusCause{{
+						Type:    metav1.CauseTypeFieldValueNotSupported,
+						Message: msg,
+					}},
+				},
+			},
+		}
+	}
+
+	gatewayConfig := new(operatorv1beta1.GatewayConfiguration)
+	return gatewayConfig, cl.Get(ctx, client.ObjectKey{
+		Namespace: gateway.Namespace,
+		Name:      parametersRef.Name,
+	}, gatewayConfig)
+}
+
+// infrastructureMetadata returns the labels and annotations set in the Gateway's
+// spec.infrastructure which should be propagated to the resources generated for it.
+func infrastructureMetadata(gateway *gwtypes.Gateway) (map[string]string, map[string]string) {
+	if gateway.Spec.Infrastructure == nil {
+		return nil, nil
+	}
+	var (
+		infraLabels      = make(map[string]string, len(gateway.Spec.Infrastructure.Labels))
+		infraAnnotations = make(map[string]string, len(gateway.Spec.Infrastructure.Annotations))
+	)
+	for k, v := range gateway.Spec.Infrastructure.Labels {
+		infraLabels[string(k)] = string(v)
+	}
+	for k, v := range gateway.Spec.Infrastructure.Annotations {
+		infraAnnotations[string(k)] = string(v)
+	}
+	return infraLabels, infraAnnotations
+}
+
 func (r *Reconciler) ensureDataPlaneHasNetworkPolicy(
 	ctx context.Context,
 	gateway *gwtypes.Gateway,
@@ -279,10 +415,34 @@ func (r *Reconciler) ensureDataPlaneHasNetworkPolicy(
 		return false, errors.New("number of networkPolicies reduced")
 	}
 
-	generatedPolicy, err := generateDataPlaneNetworkPolicy(gateway.Namespace, dataplane, controlplane)
+	var backends []networkingv1.NetworkPolicyEgressRule
+	if dataPlaneEgressRestricted(dataplane) {
+		gateways, err := r.gatewaysUsingDataPlane(ctx, dataplane)
+		if err != nil {
+			return false, err
+		}
+		if !lo.ContainsBy(gateways, func(gw gwtypes.Gateway) bool { return gw.UID == gateway.UID }) {
+			gateways = append(gateways, *gateway)
+		}
+		getter := clientEgressObjectsGetter{cl: r.Client}
+		egressBackends, err := getDataPlaneEgressBackends(ctx, getter, gateways, dataplane)
+		if err != nil {
+			return false, fmt.Errorf("failed getting DataPlane %s backends: %w", dataplane.Name, err)
+		}
+		r.egressDependencies.set(newDataPlaneEgressDependencies(gateways, dataplane, egressBackends))
+		if backends, err = backendsEgressRules(ctx, getter, dataplane, egressBackends); err != nil {
+			return false, fmt.Errorf("failed generating network policy egress rules for DataPlane %s backends: %w", dataplane.Name, err)
+		}
+	} else {
+		r.egressDependencies.delete(client.ObjectKeyFromObject(dataplane))
+	}
+
+	generatedPolicy, err := generateDataPlaneNetworkPolicy(gateway.Namespace, dataplane, controlplane, backends)
 	if err != nil {
 		return false, fmt.Errorf("failed generating network policy for DataPlane %s: %w", dataplane.Name, err)
 	}
+	infraLabels, infraAnnotations := infrastructureMetadata(gateway)
+	k8sresources.SetPropagatedMetadata(generatedPolicy, infraLabels, infraAnnotations)
 	k8sutils.SetOwnerForObject(generatedPolicy, gateway)
 	gatewayutils.LabelObjectAsGatewayManaged(generatedPolicy)
 
@@ -310,6 +470,7 @@ func generateDataPlaneNetworkPolicy(
 	namespace string,
 	dataplane *operatorv1beta1.DataPlane,
 	controlplane *operatorv1beta1.ControlPlane,
+	backends []networkingv1.NetworkPolicyEgressRule,
 ) (*networkingv1.NetworkPolicy, error) {
 	var (
 		protocolTCP     = corev1.ProtocolTCP
@@ -382,6 +543,25 @@ func generateDataPlaneNetworkPolicy(
 		},
 	}
 
+	var err error
+	if allowProxyIngress.From, err = proxyIngressPeers(dataplane); err != nil {
+		return nil, err
+	}
+	if allowMetricsIngress.From, err = metricsIngressPeers(dataplane); err != nil {
+		return nil, err
+	}
+
+	policyTypes := []networkingv1.PolicyType{
+		networkingv1.PolicyTypeIngress,
+	}
+	var egress []networkingv1.NetworkPolicyEgressRule
+	if dataPlaneEgressRestricted(dataplane) {
+		policyTypes = append(policyTypes, networkingv1.PolicyTypeEgress)
+		if egress, err = dataPlaneEgressRules(dataplane, backends); err != nil {
+			return nil, err
+		}
+	}
+
 	return &networkingv1.NetworkPolicy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:    namespace,
@@ -393,14 +573,13 @@ func generateDataPlaneNetworkPolicy(
 					"app": dataplane.Name,
 				},
 			},
-			PolicyTypes: []networkingv1.PolicyType{
-				networkingv1.PolicyTypeIngress,
-			},
+			PolicyTypes: policyTypes,
 			Ingress: []networkingv1.NetworkPolicyIngressRule{
 				limitAdminAPIIngress,
 				allowProxyIngress,
 				allowMetricsIngress,
 			},
+			Egress: egress,
 		},
 	}, nil
 }
@@ -423,8 +602,7 @@ func (r *Reconciler) ensureOwnedControlPlanesDeleted(ctx context.Context, gatewa
 		if !controlplanes[i].DeletionTimestamp.IsZero() {
 			continue
 		}
-		err = r.Delete(ctx, &controlplanes[i])
-		if client.IgnoreNotFound(err) != nil {
+		if err := r.deleteOrReleaseOwnedObject(ctx, &controlplanes[i], gateway); err != nil {
 			errs = append(errs, err)
 			continue
 		}
@@ -447,8 +625,7 @@ func (r *Reconciler) ensureOwnedDataPlanesDeleted(ctx context.Context, gateway *
 		errs    []error
 	)
 	for i := range dataplanes {
-		err = r.Delete(ctx, &dataplanes[i])
-		if client.IgnoreNotFound(err) != nil {
+		if err := r.deleteOrReleaseOwnedObject(ctx, &dataplanes[i], gateway); err != nil {
 			errs = append(errs, err)
 			continue
 		}
@@ -636,6 +813,35 @@ func (g *gatewayConditionsAndListenersAwareT) setAcceptedAndAttachedRoutes(ctx c
 	}
 
 	k8sutils.SetAcceptedConditionOnGateway(g)
+	g.setUnsupportedAddress()
+	return g.setInvalidParameters(ctx, c)
+}
+
+// setInvalidParameters marks the Gateway as not accepted with the InvalidParameters
+// reason when its spec.infrastructure.parametersRef doesn't reference an existing
+// GatewayConfiguration.
+func (g *gatewayConditionsAndListenersAwareT) setInvalidParameters(ctx context.Context, c client.Client) error {
+	var message string
+	_, err := getGatewayConfigForGateway(ctx, c, g.Gateway)
+	switch {
+	case err == nil, errors.Is(err, operatorerrors.ErrObjectMissingParametersRef):
+		return nil
+	case k8serrors.IsNotFound(err):
+		message = "The referenced GatewayConfiguration does not exist"
+	case k8serrors.IsInvalid(err):
+		message = err.Error()
+	default:
+		return fmt.Errorf("failed to get GatewayConfiguration for Gateway %s: %w", client.ObjectKeyFromObject(g), err)
+	}
+
+	k8sutils.SetCondition(metav1.Condition{
+		Type:               string(gatewayv1.GatewayConditionAccepted),
+		Status:             metav1.ConditionFalse,
+		Reason:             string(gatewayv1.GatewayReasonInvalidParameters),
+		Message:            message,
+		ObservedGeneration: g.Generation,
+		LastTransitionTime: metav1.Now(),
+	}, g)
 	return nil
 }
 
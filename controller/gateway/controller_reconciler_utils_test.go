@@ -100,6 +100,27 @@ func TestGatewayAddressesFromService(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "ClusterIP Service with external IPs",
+			svc: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type:        "ClusterIP",
+					ClusterIP:   "198.51.100.1",
+					ExternalIPs: []string{"203.0.113.10"},
+				},
+			},
+			addresses: []gwtypes.GatewayStatusAddress{
+				{
+					Value: "198.51.100.1",
+					Type:  lo.ToPtr(gatewayv1.IPAddressType),
+				},
+				{
+					Value: "203.0.113.10",
+					Type:  lo.ToPtr(gatewayv1.IPAddressType),
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "ClusterIP Service without ClusterIP",
 			svc: corev1.Service{
@@ -253,6 +274,74 @@ func TestGatewayAddressesFromService(t *testing.T) {
 	}
 }
 
+func TestGetGatewayAddressesFromDataPlaneNodes(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "edge",
+			Namespace: "default",
+			UID:       "edge-uid",
+			Annotations: map[string]string{
+				consts.AnnotationDataPlaneWorkloadKind: consts.DataPlaneWorkloadKindDaemonSet,
+			},
+		},
+		Spec: operatorv1beta1.DataPlaneSpec{
+			DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+				Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+					DeploymentOptions: operatorv1beta1.DeploymentOptions{
+						PodTemplateSpec: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{HostNetwork: true},
+						},
+					},
+				},
+			},
+		},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "edge-ingress",
+			Namespace: "default",
+			Labels: map[string]string{
+				consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+				consts.DataPlaneServiceTypeLabel:     string(consts.DataPlaneIngressServiceLabelValue),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: "198.51.100.1",
+		},
+	}
+	k8sutils.SetOwnerForObject(service, dataplane)
+
+	r := &Reconciler{
+		Client: fakectrlruntimeclient.NewClientBuilder().
+			WithScheme(scheme.Get()).
+			WithObjects(service).
+			Build(),
+	}
+
+	_, err := r.getGatewayAddresses(t.Context(), dataplane)
+	require.Error(t, err, "DataPlane without Node addresses is not ready")
+
+	dataplane.Status.Addresses = []operatorv1beta1.Address{
+		{
+			Type:       lo.ToPtr(operatorv1beta1.IPAddressType),
+			Value:      "203.0.113.1",
+			SourceType: operatorv1beta1.PublicIPAddressSourceType,
+		},
+		{
+			Type:       lo.ToPtr(operatorv1beta1.IPAddressType),
+			Value:      "10.0.0.1",
+			SourceType: operatorv1beta1.PrivateIPAddressSourceType,
+		},
+	}
+	addresses, err := r.getGatewayAddresses(t.Context(), dataplane)
+	require.NoError(t, err)
+	require.Equal(t, []gwtypes.GatewayStatusAddress{
+		{Value: "203.0.113.1", Type: lo.ToPtr(gatewayv1.IPAddressType)},
+		{Value: "10.0.0.1", Type: lo.ToPtr(gatewayv1.IPAddressType)},
+	}, addresses)
+}
+
 func TestSetAcceptedOnGateway(t *testing.T) {
 	testCases := []struct {
 		name                      string
@@ -1759,3 +1848,138 @@ func TestCountAttachedRoutesForGatewayListener(t *testing.T) {
 		})
 	}
 }
+
+func TestSetInvalidParameters(t *testing.T) {
+	gatewayConfig := &operatorv1beta1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway-config",
+			Namespace: "default",
+		},
+	}
+
+	testCases := []struct {
+		name                      string
+		infrastructure            *gatewayv1.GatewayInfrastructure
+		expectedAcceptedCondition *metav1.Condition
+	}{
+		{
+			name: "no infrastructure",
+		},
+		{
+			name: "no parametersRef",
+			infrastructure: &gatewayv1.GatewayInfrastructure{
+				Labels: map[gatewayv1.LabelKey]gatewayv1.LabelValue{"team": "a"},
+			},
+		},
+		{
+			name: "existing GatewayConfiguration",
+			infrastructure: &gatewayv1.GatewayInfrastructure{
+				ParametersRef: &gatewayv1.LocalParametersReference{
+					Group: gatewayv1.Group(operatorv1beta1.SchemeGroupVersion.Group),
+					Kind:  "GatewayConfiguration",
+					Name:  "gateway-config",
+				},
+			},
+		},
+		{
+			name: "missing GatewayConfiguration",
+			infrastructure: &gatewayv1.GatewayInfrastructure{
+				ParametersRef: &gatewayv1.LocalParametersReference{
+					Group: gatewayv1.Group(operatorv1beta1.SchemeGroupVersion.Group),
+					Kind:  "GatewayConfiguration",
+					Name:  "missing",
+				},
+			},
+			expectedAcceptedCondition: &metav1.Condition{
+				Type:               string(gatewayv1.GatewayConditionAccepted),
+				Status:             metav1.ConditionFalse,
+				Reason:             string(gatewayv1.GatewayReasonInvalidParameters),
+				Message:            "The referenced GatewayConfiguration does not exist",
+				ObservedGeneration: 1,
+			},
+		},
+		{
+			name: "unsupported kind",
+			infrastructure: &gatewayv1.GatewayInfrastructure{
+				ParametersRef: &gatewayv1.LocalParametersReference{
+					Group: "",
+					Kind:  "ConfigMap",
+					Name:  "gateway-config",
+				},
+			},
+			expectedAcceptedCondition: &metav1.Condition{
+				Type:               string(gatewayv1.GatewayConditionAccepted),
+				Status:             metav1.ConditionFalse,
+				Reason:             string(gatewayv1.GatewayReasonInvalidParameters),
+				Message:            "controller only supports gateway-operator.konghq.com GatewayConfiguration resources for Gateway infrastructure parametersRef",
+				ObservedGeneration: 1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(gatewayConfig).
+				Build()
+
+			gateway := gatewayConditionsAndListenersAware(&gwtypes.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 1,
+				},
+				Spec: gatewayv1.GatewaySpec{
+					Infrastructure: tc.infrastructure,
+				},
+			})
+			require.NoError(t, gateway.setInvalidParameters(t.Context(), cl))
+
+			acceptedCondition, found := k8sutils.GetCondition(kcfgconsts.ConditionType(gatewayv1.GatewayConditionAccepted), gateway)
+			if tc.expectedAcceptedCondition == nil {
+				require.False(t, found)
+				return
+			}
+			require.True(t, found)
+			tc.expectedAcceptedCondition.LastTransitionTime = acceptedCondition.LastTransitionTime
+			require.Equal(t, *tc.expectedAcceptedCondition, acceptedCondition)
+		})
+	}
+}
+
+func TestGenerateDataPlaneAndControlPlanePropagateInfrastructureMetadata(t *testing.T) {
+	gateway := &gwtypes.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			UID:       "uid",
+		},
+		Spec: gatewayv1.GatewaySpec{
+			Infrastructure: &gatewayv1.GatewayInfrastructure{
+				Labels:      map[gatewayv1.LabelKey]gatewayv1.LabelValue{"team": "a"},
+				Annotations: map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{"cost-center": "42"},
+			},
+		},
+	}
+	gatewayClass := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kong",
+		},
+	}
+	gatewayConfig := &operatorv1beta1.GatewayConfiguration{}
+
+	r := &Reconciler{DefaultDataPlaneImage: consts.DefaultDataPlaneImage}
+	dataplane, err := r.generateDataPlane(gateway, gatewayConfig, nil)
+	require.NoError(t, err)
+	controlplane := generateControlPlane(gatewayClass, gateway, gatewayConfig, "dataplane", nil)
+
+	for _, obj := range []client.Object{dataplane, controlplane} {
+		assert.Equal(t, "a", obj.GetLabels()["team"])
+		assert.Equal(t, consts.GatewayManagedLabelValue, obj.GetLabels()[consts.GatewayOperatorManagedByLabel])
+		assert.Equal(t, "42", obj.GetAnnotations()["cost-center"])
+		assert.JSONEq(t, `{"team":"a"}`, obj.GetAnnotations()[consts.AnnotationPropagatedLabels])
+		assert.JSONEq(t, `{"cost-center":"42"}`, obj.GetAnnotations()[consts.AnnotationPropagatedAnnotations])
+	}
+}
@@ -155,16 +155,37 @@ func (r *Reconciler) listGatewaysForGatewayConfig(ctx context.Context, obj clien
 		return nil
 	}
 
-	gatewayClassList := new(gatewayv1.GatewayClassList)
-	if err := r.List(ctx, gatewayClassList); err != nil {
-		ctrllog.FromContext(ctx).Error(
-			fmt.Errorf("unexpected error occurred while listing GatewayClass resources"),
-			"failed to run map funcs",
-			"error", err.Error(),
-		)
+	gateways, err := listGatewaysUsingGatewayConfig(ctx, r.Client, gatewayConfig)
+	if err != nil {
+		logger.Error(err, "failed to run map funcs")
 		return nil
 	}
 
+	recs := make([]reconcile.Request, 0, len(gateways))
+	for _, gateway := range gateways {
+		recs = append(recs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: gateway.Namespace,
+				Name:      gateway.Name,
+			},
+		})
+	}
+	return recs
+}
+
+// listGatewaysUsingGatewayConfig lists the Gateways which use the provided
+// GatewayConfiguration, either through their GatewayClass or through their
+// spec.infrastructure.parametersRef.
+func listGatewaysUsingGatewayConfig(
+	ctx context.Context,
+	cl client.Client,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) ([]gwtypes.Gateway, error) {
+	gatewayClassList := new(gatewayv1.GatewayClassList)
+	if err := cl.List(ctx, gatewayClassList); err != nil {
+		return nil, fmt.Errorf("unexpected error occurred while listing GatewayClass resources: %w", err)
+	}
+
 	matchingGatewayClasses := make(map[string]struct{})
 	for _, gatewayClass := range gatewayClassList.Items {
 		if gatewayClass.Spec.ParametersRef != nil &&
@@ -176,27 +197,34 @@ func (r *Reconciler) listGatewaysForGatewayConfig(ctx context.Context, obj clien
 	}
 
 	gatewayList := new(gatewayv1.GatewayList)
-	if err := r.List(ctx, gatewayList); err != nil {
-		ctrllog.FromContext(ctx).Error(
-			fmt.Errorf("unexpected error occurred while listing Gateway resources"),
-			"failed to run map funcs",
-			"error", err.Error(),
-		)
-		return nil
+	if err := cl.List(ctx, gatewayList); err != nil {
+		return nil, fmt.Errorf("unexpected error occurred while listing Gateway resources: %w", err)
 	}
 
-	var recs []reconcile.Request
+	var gateways []gwtypes.Gateway
 	for _, gateway := range gatewayList.Items {
-		if _, ok := matchingGatewayClasses[string(gateway.Spec.GatewayClassName)]; ok {
-			recs = append(recs, reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Namespace: gateway.Namespace,
-					Name:      gateway.Name,
-				},
-			})
+		if _, ok := matchingGatewayClasses[string(gateway.Spec.GatewayClassName)]; ok ||
+			gatewayInfrastructureReferencesGatewayConfig(&gateway, gatewayConfig) {
+			gateways = append(gateways, gateway)
 		}
 	}
-	return recs
+	return gateways, nil
+}
+
+// gatewayInfrastructureReferencesGatewayConfig returns true if the Gateway's
+// spec.infrastructure.parametersRef references the provided GatewayConfiguration.
+func gatewayInfrastructureReferencesGatewayConfig(
+	gateway *gatewayv1.Gateway,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) bool {
+	if gateway.Spec.Infrastructure == nil || gateway.Spec.Infrastructure.ParametersRef == nil {
+		return false
+	}
+	parametersRef := gateway.Spec.Infrastructure.ParametersRef
+	return gateway.Namespace == gatewayConfig.Namespace &&
+		string(parametersRef.Group) == operatorv1beta1.SchemeGroupVersion.Group &&
+		string(parametersRef.Kind) == "GatewayConfiguration" &&
+		parametersRef.Name == gatewayConfig.Name
 }
 
 // listReferenceGrantsForGateway is a watch predicate which finds all Gateways mentioned in a From clause for a
@@ -316,6 +344,15 @@ func (r *Reconciler) listGatewaysAttachedByHTTPRoute(ctx context.Context, obj cl
 	return recs
 }
 
+// listGatewaysForEgressObject is a watch map func which enqueues the Gateways
+// using DataPlanes with restricted egress whose NetworkPolicy egress rules
+// depend on the changed route, Service, EndpointSlice, ConfigMap or ReferenceGrant.
+func (r *Reconciler) listGatewaysForEgressObject(_ context.Context, obj client.Object) []reconcile.Request {
+	return lo.Map(r.egressDependencies.gatewaysDependingOn(obj), func(nn types.NamespacedName, _ int) reconcile.Request {
+		return reconcile.Request{NamespacedName: nn}
+	})
+}
+
 // -----------------------------------------------------------------------------
 // GatewayReconciler - Config Defaults
 // -----------------------------------------------------------------------------
@@ -328,6 +365,7 @@ func (r *Reconciler) setDataPlaneGatewayConfigDefaults(gatewayConfig *operatorv1
 
 func (r *Reconciler) setControlPlaneGatewayConfigDefaults(gateway *gwtypes.Gateway,
 	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	shared *sharedInfrastructure,
 	dataplaneName,
 	dataplaneIngressServiceName,
 	dataplaneAdminServiceName,
@@ -363,6 +401,13 @@ func (r *Reconciler) setControlPlaneGatewayConfigDefaults(gateway *gwtypes.Gatew
 		controlPlanePodTemplateSpec.Spec.Containers = append(controlPlanePodTemplateSpec.Spec.Containers, *container)
 	}
 
+	// A ControlPlane shared by multiple Gateways reconciles all the Gateways
+	// of its GatewayClass instead of only the Gateway owning it.
+	ownedByGateway := gateway.Name
+	if shared != nil {
+		ownedByGateway = ""
+	}
+
 	// an actual ControlPlane will have ObjectMeta populated with ownership information. this includes a stand-in to
 	// satisfy the signature
 	_ = controlplane.SetDefaults(gatewayConfig.Spec.ControlPlaneOptions,
@@ -370,7 +415,8 @@ func (r *Reconciler) setControlPlaneGatewayConfigDefaults(gateway *gwtypes.Gatew
 			Namespace:                   gateway.Namespace,
 			DataPlaneIngressServiceName: dataplaneIngressServiceName,
 			DataPlaneAdminServiceName:   dataplaneAdminServiceName,
-			OwnedByGateway:              gateway.Name,
+			OwnedByGateway:              ownedByGateway,
+			SharedByGateways:            shared != nil,
 			ControlPlaneName:            controlPlaneName,
 			AnonymousReportsEnabled:     controlplane.DeduceAnonymousReportsEnabled(r.AnonymousReportsEnabled, gatewayConfig.Spec.ControlPlaneOptions),
 		},
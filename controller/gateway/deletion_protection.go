@@ -0,0 +1,40 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kong/gateway-operator/controller/pkg/deletionprotection"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
+)
+
+// deletionBlockers returns the reasons why the provided Gateway, when protected
+// from deletion, must not be deleted: routes attached to its listeners and
+// DataPlanes serving traffic. Attached routes are counted rather than read from
+// the Gateway's status as the status is not updated anymore once it's being deleted.
+func (r *Reconciler) deletionBlockers(ctx context.Context, gateway *gwtypes.Gateway) (deletionprotection.Blockers, error) {
+	var (
+		blockers       deletionprotection.Blockers
+		attachedRoutes int32
+	)
+	for _, listener := range gateway.Spec.Listeners {
+		count, err := countAttachedRoutesForGatewayListener(ctx, gateway, listener, r.Client)
+		if err != nil {
+			return deletionprotection.Blockers{}, err
+		}
+		attachedRoutes += count
+	}
+	if attachedRoutes > 0 {
+		blockers.Reasons = append(blockers.Reasons, fmt.Sprintf("%d routes are attached to its listeners", attachedRoutes))
+	}
+
+	dataplanes, err := gatewayutils.ListDataPlanesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return deletionprotection.Blockers{}, err
+	}
+	for i := range dataplanes {
+		blockers.AddDataPlaneTraffic(r.DataPlaneMetrics, &dataplanes[i])
+	}
+	return blockers, nil
+}
@@ -0,0 +1,88 @@
+package gateway
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/gateway-operator/controller/pkg/maintenance"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+var (
+	// dataPlaneAnnotations maps the GatewayConfiguration annotations which are
+	// propagated to DataPlanes to the annotations they are set as.
+	dataPlaneAnnotations = gatewayConfigurationAnnotations(map[string]string{
+		consts.AnnotationVersionChannel:               consts.AnnotationVersionChannel,
+		consts.AnnotationGatewayConfigurationRevision: consts.AnnotationGatewayConfigurationRevision,
+
+		consts.AnnotationNetworkPolicyProxySourceCIDRs:      consts.AnnotationNetworkPolicyProxySourceCIDRs,
+		consts.AnnotationNetworkPolicyProxySourceNamespaces: consts.AnnotationNetworkPolicyProxySourceNamespaces,
+		consts.AnnotationNetworkPolicyMetricsScrapers:       consts.AnnotationNetworkPolicyMetricsScrapers,
+		consts.AnnotationNetworkPolicyEgress:                consts.AnnotationNetworkPolicyEgress,
+		consts.AnnotationNetworkPolicyEgressCIDRs:           consts.AnnotationNetworkPolicyEgressCIDRs,
+	})
+	// controlPlaneAnnotations maps the GatewayConfiguration annotations which are
+	// propagated to ControlPlanes to the annotations they are set as.
+	controlPlaneAnnotations = gatewayConfigurationAnnotations(map[string]string{
+		consts.AnnotationControlPlaneVersionChannel:   consts.AnnotationVersionChannel,
+		consts.AnnotationGatewayConfigurationRevision: consts.AnnotationGatewayConfigurationRevision,
+	})
+)
+
+// gatewayConfigurationAnnotations returns the provided annotations mapping
+// extended with the maintenance window annotations, which are propagated as is.
+func gatewayConfigurationAnnotations(annotations map[string]string) map[string]string {
+	for _, key := range maintenance.Annotations {
+		annotations[key] = key
+	}
+	return annotations
+}
+
+// setDataPlaneAnnotations sets the annotations of the provided GatewayConfiguration
+// which apply to DataPlanes, like its maintenance window and version channel,
+// on the provided DataPlane. It returns true when the DataPlane's annotations
+// were changed.
+func setDataPlaneAnnotations(dataplane *operatorv1beta1.DataPlane, gatewayConfig *operatorv1beta1.GatewayConfiguration) bool {
+	return setGatewayConfigurationAnnotations(dataplane, gatewayConfig, dataPlaneAnnotations)
+}
+
+// setControlPlaneAnnotations sets the annotations of the provided GatewayConfiguration
+// which apply to ControlPlanes, like its maintenance window and ControlPlane
+// version channel, on the provided ControlPlane. It returns true when the
+// ControlPlane's annotations were changed.
+func setControlPlaneAnnotations(controlplane *operatorv1beta1.ControlPlane, gatewayConfig *operatorv1beta1.GatewayConfiguration) bool {
+	return setGatewayConfigurationAnnotations(controlplane, gatewayConfig, controlPlaneAnnotations)
+}
+
+// setGatewayConfigurationAnnotations sets the provided GatewayConfiguration
+// annotations on the provided object as the annotations they are mapped to,
+// and removes the ones which are not set on the GatewayConfiguration.
+// It returns true when the object's annotations were changed.
+func setGatewayConfigurationAnnotations(
+	obj metav1.Object,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	mapping map[string]string,
+) bool {
+	var (
+		annotations = obj.GetAnnotations()
+		changed     bool
+	)
+	for from, key := range mapping {
+		value, ok := gatewayConfig.Annotations[from]
+		current, exists := annotations[key]
+		switch {
+		case !ok && exists:
+			delete(annotations, key)
+			changed = true
+		case ok && (!exists || current != value):
+			if annotations == nil {
+				annotations = make(map[string]string, len(mapping))
+			}
+			annotations[key] = value
+			changed = true
+		}
+	}
+	obj.SetAnnotations(annotations)
+	return changed
+}
@@ -0,0 +1,53 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestSetGatewayConfigurationAnnotations(t *testing.T) {
+	gatewayConfig := &operatorv1beta1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				consts.AnnotationMaintenanceWindowSchedule:  "0 2 * * 6",
+				consts.AnnotationMaintenanceWindowDuration:  "2h",
+				consts.AnnotationVersionChannel:             "3.9.x",
+				consts.AnnotationControlPlaneVersionChannel: "3.4.x",
+				"unrelated": "value",
+			},
+		},
+	}
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				consts.AnnotationMaintenanceWindowDuration: "1h",
+				consts.AnnotationMaintenanceOverride:       "true",
+				"other":                                    "value",
+			},
+		},
+	}
+
+	require.True(t, setDataPlaneAnnotations(dataplane, gatewayConfig))
+	require.Equal(t, map[string]string{
+		consts.AnnotationMaintenanceWindowSchedule: "0 2 * * 6",
+		consts.AnnotationMaintenanceWindowDuration: "2h",
+		consts.AnnotationVersionChannel:            "3.9.x",
+		"other":                                    "value",
+	}, dataplane.Annotations)
+	require.False(t, setDataPlaneAnnotations(dataplane, gatewayConfig))
+
+	controlplane := &operatorv1beta1.ControlPlane{}
+	require.True(t, setControlPlaneAnnotations(controlplane, gatewayConfig))
+	require.Equal(t, map[string]string{
+		consts.AnnotationMaintenanceWindowSchedule: "0 2 * * 6",
+		consts.AnnotationMaintenanceWindowDuration: "2h",
+		consts.AnnotationVersionChannel:            "3.4.x",
+	}, controlplane.Annotations)
+	require.False(t, setControlPlaneAnnotations(&operatorv1beta1.ControlPlane{}, &operatorv1beta1.GatewayConfiguration{}))
+}
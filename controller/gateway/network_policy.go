@@ -0,0 +1,168 @@
+package gateway
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+const (
+	// networkPolicyEgressRestricted is the value of the
+	// consts.AnnotationNetworkPolicyEgress annotation restricting the egress
+	// traffic of DataPlane Pods.
+	networkPolicyEgressRestricted = "restricted"
+
+	// dnsPort is the port DataPlane Pods can always reach when their egress
+	// traffic is restricted, to resolve names.
+	dnsPort = 53
+
+	// konnectEndpointsPort is the port of the Konnect control plane and
+	// telemetry endpoints which DataPlanes using a KonnectExtension connect to.
+	konnectEndpointsPort = 443
+)
+
+// dataPlaneEgressRestricted returns true if the egress traffic of the provided
+// DataPlane's Pods is restricted with the consts.AnnotationNetworkPolicyEgress
+// annotation.
+func dataPlaneEgressRestricted(dataplane *operatorv1beta1.DataPlane) bool {
+	return dataplane.Annotations[consts.AnnotationNetworkPolicyEgress] == networkPolicyEgressRestricted
+}
+
+// proxyIngressPeers returns the peers allowed to reach the proxy ports of the
+// provided DataPlane according to its consts.AnnotationNetworkPolicyProxySourceCIDRs
+// and consts.AnnotationNetworkPolicyProxySourceNamespaces annotations, or nil
+// when the proxy ports are reachable from anywhere.
+func proxyIngressPeers(dataplane *operatorv1beta1.DataPlane) ([]networkingv1.NetworkPolicyPeer, error) {
+	peers, err := ipBlockPeers(dataplane.Annotations[consts.AnnotationNetworkPolicyProxySourceCIDRs])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", consts.AnnotationNetworkPolicyProxySourceCIDRs, err)
+	}
+	for _, namespace := range splitAnnotationList(dataplane.Annotations[consts.AnnotationNetworkPolicyProxySourceNamespaces], ",") {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: namespaceNameSelector(namespace),
+		})
+	}
+	return peers, nil
+}
+
+// metricsIngressPeers returns the scrapers allowed to reach the metrics port
+// of the provided DataPlane according to its consts.AnnotationNetworkPolicyMetricsScrapers
+// annotation, or nil when the metrics port is reachable from anywhere.
+func metricsIngressPeers(dataplane *operatorv1beta1.DataPlane) ([]networkingv1.NetworkPolicyPeer, error) {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, scraper := range splitAnnotationList(dataplane.Annotations[consts.AnnotationNetworkPolicyMetricsScrapers], ";") {
+		namespace, selector, _ := strings.Cut(scraper, "/")
+		if namespace == "" {
+			return nil, fmt.Errorf("invalid %s annotation: scraper %q has no namespace", consts.AnnotationNetworkPolicyMetricsScrapers, scraper)
+		}
+		peer := networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: namespaceNameSelector(namespace),
+		}
+		if selector != "" {
+			podSelector, err := metav1.ParseToLabelSelector(selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s annotation: scraper %q: %w", consts.AnnotationNetworkPolicyMetricsScrapers, scraper, err)
+			}
+			// Empty requirements are dropped by the API server, which would make
+			// the generated NetworkPolicy differ from the existing one.
+			if len(podSelector.MatchExpressions) == 0 {
+				podSelector.MatchExpressions = nil
+			}
+			peer.PodSelector = podSelector
+		}
+		peers = append(peers, peer)
+	}
+	return peers, nil
+}
+
+// dataPlaneEgressRules returns the egress rules of the provided DataPlane whose
+// egress traffic is restricted: DNS, the provided backends rules, Konnect when
+// the DataPlane uses a KonnectExtension and the CIDRs listed in its
+// consts.AnnotationNetworkPolicyEgressCIDRs annotation.
+func dataPlaneEgressRules(
+	dataplane *operatorv1beta1.DataPlane,
+	backends []networkingv1.NetworkPolicyEgressRule,
+) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	var (
+		protocolTCP = corev1.ProtocolTCP
+		protocolUDP = corev1.ProtocolUDP
+		dns         = intstr.FromInt(dnsPort)
+		konnect     = intstr.FromInt(konnectEndpointsPort)
+	)
+
+	// DNS servers are not selected by Pods as they can run outside of the
+	// cluster or on the Nodes (e.g. NodeLocal DNSCache), hence only the port is
+	// restricted.
+	rules := []networkingv1.NetworkPolicyEgressRule{{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: &protocolUDP, Port: &dns},
+			{Protocol: &protocolTCP, Port: &dns},
+		},
+	}}
+	rules = append(rules, backends...)
+
+	// Konnect endpoints are hostnames which can't be used in NetworkPolicies
+	// and resolve to addresses that change, hence only the port is restricted.
+	if lo.ContainsBy(dataplane.Spec.Extensions, func(ext commonv1alpha1.ExtensionRef) bool {
+		return ext.Group == konnectv1alpha1.SchemeGroupVersion.Group && ext.Kind == konnectv1alpha1.KonnectExtensionKind
+	}) {
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &protocolTCP, Port: &konnect},
+			},
+		})
+	}
+
+	peers, err := ipBlockPeers(dataplane.Annotations[consts.AnnotationNetworkPolicyEgressCIDRs])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", consts.AnnotationNetworkPolicyEgressCIDRs, err)
+	}
+	if len(peers) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{To: peers})
+	}
+	return rules, nil
+}
+
+// ipBlockPeers returns a peer for each CIDR of the provided comma separated list.
+func ipBlockPeers(cidrs string) ([]networkingv1.NetworkPolicyPeer, error) {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, cidr := range splitAnnotationList(cidrs, ",") {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, err
+		}
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+		})
+	}
+	return peers, nil
+}
+
+// namespaceNameSelector returns a selector of the namespace with the provided name.
+func namespaceNameSelector(namespace string) *metav1.LabelSelector {
+	// NamespaceDefaultLabelName feature gate must be enabled for this to work
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"kubernetes.io/metadata.name": namespace,
+		},
+	}
+}
+
+// splitAnnotationList returns the trimmed, non empty, elements of the provided
+// annotation value separated by sep.
+func splitAnnotationList(value, sep string) []string {
+	return lo.Compact(lo.Map(strings.Split(value, sep), func(s string, _ int) string {
+		return strings.TrimSpace(s)
+	}))
+}
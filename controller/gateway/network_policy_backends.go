@@ -0,0 +1,944 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// egressRoute is a route of any of the kinds whose backends DataPlane Pods
+// proxy to, reduced to what's needed to generate their egress rules.
+type egressRoute struct {
+	kind       gatewayv1.Kind
+	namespace  string
+	parentRefs []gatewayv1.ParentReference
+	hostnames  []gatewayv1.Hostname
+	// backends are the backends and the mirrors of the route's rules.
+	backends []gatewayv1.BackendObjectReference
+}
+
+// egressRouteForObject returns the egress route of the provided object, or
+// false when it's not a supported route.
+func egressRouteForObject(obj runtime.Object) (egressRoute, bool) {
+	switch route := obj.(type) {
+	case *gatewayv1.HTTPRoute:
+		r := egressRoute{
+			kind:       "HTTPRoute",
+			namespace:  route.Namespace,
+			parentRefs: route.Spec.ParentRefs,
+			hostnames:  route.Spec.Hostnames,
+		}
+		mirrors := func(filters []gatewayv1.HTTPRouteFilter) {
+			for _, filter := range filters {
+				if filter.RequestMirror != nil {
+					r.backends = append(r.backends, filter.RequestMirror.BackendRef)
+				}
+			}
+		}
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				r.backends = append(r.backends, backendRef.BackendObjectReference)
+				mirrors(backendRef.Filters)
+			}
+			mirrors(rule.Filters)
+		}
+		return r, true
+	case *gatewayv1.GRPCRoute:
+		r := egressRoute{
+			kind:       "GRPCRoute",
+			namespace:  route.Namespace,
+			parentRefs: route.Spec.ParentRefs,
+			hostnames:  route.Spec.Hostnames,
+		}
+		mirrors := func(filters []gatewayv1.GRPCRouteFilter) {
+			for _, filter := range filters {
+				if filter.RequestMirror != nil {
+					r.backends = append(r.backends, filter.RequestMirror.BackendRef)
+				}
+			}
+		}
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				r.backends = append(r.backends, backendRef.BackendObjectReference)
+				mirrors(backendRef.Filters)
+			}
+			mirrors(rule.Filters)
+		}
+		return r, true
+	case *gatewayv1alpha2.TLSRoute:
+		r := egressRoute{
+			kind:       "TLSRoute",
+			namespace:  route.Namespace,
+			parentRefs: route.Spec.ParentRefs,
+			hostnames:  route.Spec.Hostnames,
+		}
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				r.backends = append(r.backends, backendRef.BackendObjectReference)
+			}
+		}
+		return r, true
+	case *gatewayv1alpha2.TCPRoute:
+		r := egressRoute{
+			kind:       "TCPRoute",
+			namespace:  route.Namespace,
+			parentRefs: route.Spec.ParentRefs,
+		}
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				r.backends = append(r.backends, backendRef.BackendObjectReference)
+			}
+		}
+		return r, true
+	case *gatewayv1alpha2.UDPRoute:
+		r := egressRoute{
+			kind:       "UDPRoute",
+			namespace:  route.Namespace,
+			parentRefs: route.Spec.ParentRefs,
+		}
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				r.backends = append(r.backends, backendRef.BackendObjectReference)
+			}
+		}
+		return r, true
+	default:
+		return egressRoute{}, false
+	}
+}
+
+// egressObjectsGetter gets the objects which the egress rules of DataPlane
+// Pods depend on.
+type egressObjectsGetter interface {
+	// listRoutes lists the routes whose backends DataPlane Pods proxy to.
+	listRoutes(ctx context.Context) ([]egressRoute, error)
+	// getNamespace returns the Namespace or nil when it doesn't exist.
+	getNamespace(ctx context.Context, name string) (*corev1.Namespace, error)
+	// getService returns the Service or nil when it doesn't exist.
+	getService(ctx context.Context, nn types.NamespacedName) (*corev1.Service, error)
+	// listEndpointSlices lists the EndpointSlices of the Service.
+	listEndpointSlices(ctx context.Context, service types.NamespacedName) ([]discoveryv1.EndpointSlice, error)
+	// getConfigMap returns the ConfigMap or nil when it doesn't exist.
+	getConfigMap(ctx context.Context, nn types.NamespacedName) (*corev1.ConfigMap, error)
+	// listReferenceGrants lists the ReferenceGrants in the namespace.
+	listReferenceGrants(ctx context.Context, namespace string) ([]gatewayv1beta1.ReferenceGrant, error)
+}
+
+// clientEgressObjectsGetter gets the objects from the API server.
+type clientEgressObjectsGetter struct {
+	cl client.Client
+}
+
+var _ egressObjectsGetter = clientEgressObjectsGetter{}
+
+func (g clientEgressObjectsGetter) listRoutes(ctx context.Context) ([]egressRoute, error) {
+	var routes []egressRoute
+	for _, list := range []client.ObjectList{
+		&gatewayv1.HTTPRouteList{},
+		&gatewayv1.GRPCRouteList{},
+		&gatewayv1alpha2.TLSRouteList{},
+		&gatewayv1alpha2.TCPRouteList{},
+		&gatewayv1alpha2.UDPRouteList{},
+	} {
+		if err := g.cl.List(ctx, list); err != nil {
+			// The CRDs of the experimental routes might not be installed.
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed listing %T: %w", list, err)
+		}
+		if err := meta.EachListItem(list, func(obj runtime.Object) error {
+			if route, ok := egressRouteForObject(obj); ok {
+				routes = append(routes, route)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return routes, nil
+}
+
+func (g clientEgressObjectsGetter) getNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	var namespace corev1.Namespace
+	if err := g.cl.Get(ctx, client.ObjectKey{Name: name}, &namespace); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed getting Namespace %s: %w", name, err)
+	}
+	return &namespace, nil
+}
+
+func (g clientEgressObjectsGetter) getService(ctx context.Context, nn types.NamespacedName) (*corev1.Service, error) {
+	var service corev1.Service
+	if err := g.cl.Get(ctx, nn, &service); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed getting Service %s: %w", nn, err)
+	}
+	return &service, nil
+}
+
+func (g clientEgressObjectsGetter) listEndpointSlices(ctx context.Context, service types.NamespacedName) ([]discoveryv1.EndpointSlice, error) {
+	var endpointSlices discoveryv1.EndpointSliceList
+	if err := g.cl.List(ctx, &endpointSlices,
+		client.InNamespace(service.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: service.Name},
+	); err != nil {
+		return nil, fmt.Errorf("failed listing EndpointSlices of Service %s: %w", service, err)
+	}
+	return endpointSlices.Items, nil
+}
+
+func (g clientEgressObjectsGetter) getConfigMap(ctx context.Context, nn types.NamespacedName) (*corev1.ConfigMap, error) {
+	var configMap corev1.ConfigMap
+	if err := g.cl.Get(ctx, nn, &configMap); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed getting ConfigMap %s: %w", nn, err)
+	}
+	return &configMap, nil
+}
+
+func (g clientEgressObjectsGetter) listReferenceGrants(ctx context.Context, namespace string) ([]gatewayv1beta1.ReferenceGrant, error) {
+	var referenceGrants gatewayv1beta1.ReferenceGrantList
+	if err := g.cl.List(ctx, &referenceGrants, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed listing ReferenceGrants in namespace %s: %w", namespace, err)
+	}
+	return referenceGrants.Items, nil
+}
+
+// objectsEgressObjectsGetter gets the objects from the provided list, e.g.
+// when rendering resources without reaching out to the API server.
+type objectsEgressObjectsGetter []client.Object
+
+var _ egressObjectsGetter = objectsEgressObjectsGetter{}
+
+func (g objectsEgressObjectsGetter) listRoutes(context.Context) ([]egressRoute, error) {
+	var routes []egressRoute
+	for _, obj := range g {
+		if route, ok := egressRouteForObject(obj); ok {
+			routes = append(routes, route)
+		}
+	}
+	return routes, nil
+}
+
+func (g objectsEgressObjectsGetter) getNamespace(_ context.Context, name string) (*corev1.Namespace, error) {
+	return findObject[*corev1.Namespace](g, types.NamespacedName{Name: name}), nil
+}
+
+func (g objectsEgressObjectsGetter) getService(_ context.Context, nn types.NamespacedName) (*corev1.Service, error) {
+	return findObject[*corev1.Service](g, nn), nil
+}
+
+func (g objectsEgressObjectsGetter) listEndpointSlices(_ context.Context, service types.NamespacedName) ([]discoveryv1.EndpointSlice, error) {
+	var endpointSlices []discoveryv1.EndpointSlice
+	for _, obj := range g {
+		if endpointSlice, ok := obj.(*discoveryv1.EndpointSlice); ok &&
+			endpointSlice.Namespace == service.Namespace &&
+			endpointSlice.Labels[discoveryv1.LabelServiceName] == service.Name {
+			endpointSlices = append(endpointSlices, *endpointSlice)
+		}
+	}
+	return endpointSlices, nil
+}
+
+func (g objectsEgressObjectsGetter) getConfigMap(_ context.Context, nn types.NamespacedName) (*corev1.ConfigMap, error) {
+	return findObject[*corev1.ConfigMap](g, nn), nil
+}
+
+func (g objectsEgressObjectsGetter) listReferenceGrants(_ context.Context, namespace string) ([]gatewayv1beta1.ReferenceGrant, error) {
+	var referenceGrants []gatewayv1beta1.ReferenceGrant
+	for _, obj := range g {
+		if referenceGrant, ok := obj.(*gatewayv1beta1.ReferenceGrant); ok && referenceGrant.Namespace == namespace {
+			referenceGrants = append(referenceGrants, *referenceGrant)
+		}
+	}
+	return referenceGrants, nil
+}
+
+// findObject returns the object of type T with the provided name from the
+// provided objects, or nil when there is none.
+func findObject[T client.Object](objs []client.Object, nn types.NamespacedName) T {
+	var zero T
+	for _, obj := range objs {
+		if o, ok := obj.(T); ok && client.ObjectKeyFromObject(o) == nn {
+			return o
+		}
+	}
+	return zero
+}
+
+// listenerRouteKinds are the kinds of the routes which can attach to the
+// listeners of each protocol.
+var listenerRouteKinds = map[gatewayv1.ProtocolType][]gatewayv1.Kind{
+	gatewayv1.HTTPProtocolType:  {"HTTPRoute", "GRPCRoute"},
+	gatewayv1.HTTPSProtocolType: {"HTTPRoute", "GRPCRoute"},
+	gatewayv1.TLSProtocolType:   {"TLSRoute"},
+	gatewayv1.TCPProtocolType:   {"TCPRoute"},
+	gatewayv1.UDPProtocolType:   {"UDPRoute"},
+}
+
+// routeAttachesToGateway returns true if one of the route's parentRefs
+// references the Gateway and one of its listeners accepts the route: the
+// listener matches the parentRef's sectionName and port, allows the route's
+// kind and namespace and, when both have hostnames, their hostnames intersect.
+func routeAttachesToGateway(
+	ctx context.Context,
+	getter egressObjectsGetter,
+	route egressRoute,
+	gateway *gwtypes.Gateway,
+) (bool, error) {
+	for _, parentRef := range route.parentRefs {
+		if (parentRef.Group != nil && string(*parentRef.Group) != gatewayv1.GroupName) ||
+			(parentRef.Kind != nil && *parentRef.Kind != "Gateway") ||
+			string(parentRef.Name) != gateway.Name {
+			continue
+		}
+		namespace := route.namespace
+		if parentRef.Namespace != nil {
+			namespace = string(*parentRef.Namespace)
+		}
+		if namespace != gateway.Namespace {
+			continue
+		}
+
+		for _, listener := range gateway.Spec.Listeners {
+			if (parentRef.SectionName != nil && *parentRef.SectionName != listener.Name) ||
+				(parentRef.Port != nil && *parentRef.Port != listener.Port) ||
+				!listenerAllowsRouteKind(listener, route.kind) ||
+				!listenerHostnameMatches(listener.Hostname, route.hostnames) {
+				continue
+			}
+			allowed, err := listenerAllowsRouteNamespace(ctx, getter, gateway, listener, route.namespace)
+			if err != nil {
+				return false, err
+			}
+			if allowed {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// listenerAllowsRouteKind returns true if routes of the provided kind can
+// attach to the listener.
+func listenerAllowsRouteKind(listener gatewayv1.Listener, kind gatewayv1.Kind) bool {
+	if !slices.Contains(listenerRouteKinds[listener.Protocol], kind) {
+		return false
+	}
+	if listener.AllowedRoutes == nil || len(listener.AllowedRoutes.Kinds) == 0 {
+		return true
+	}
+	return lo.ContainsBy(listener.AllowedRoutes.Kinds, func(rgk gatewayv1.RouteGroupKind) bool {
+		return rgk.Kind == kind && (rgk.Group == nil || string(*rgk.Group) == gatewayv1.GroupName)
+	})
+}
+
+// listenerAllowsRouteNamespace returns true if routes from the provided
+// namespace can attach to the listener.
+func listenerAllowsRouteNamespace(
+	ctx context.Context,
+	getter egressObjectsGetter,
+	gateway *gwtypes.Gateway,
+	listener gatewayv1.Listener,
+	namespace string,
+) (bool, error) {
+	from := gatewayv1.NamespacesFromSame
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil && listener.AllowedRoutes.Namespaces.From != nil {
+		from = *listener.AllowedRoutes.Namespaces.From
+	}
+	switch from {
+	case gatewayv1.NamespacesFromAll:
+		return true, nil
+	case gatewayv1.NamespacesFromSame:
+		return namespace == gateway.Namespace, nil
+	case gatewayv1.NamespacesFromSelector:
+		selector, err := metav1.LabelSelectorAsSelector(listener.AllowedRoutes.Namespaces.Selector)
+		if err != nil {
+			return false, fmt.Errorf("invalid namespace selector of listener %s: %w", listener.Name, err)
+		}
+		ns, err := getter.getNamespace(ctx, namespace)
+		if err != nil || ns == nil {
+			return false, err
+		}
+		return selector.Matches(labels.Set(ns.Labels)), nil
+	default:
+		return false, nil
+	}
+}
+
+// listenerHostnameMatches returns true if the listener's hostname intersects
+// with one of the route's hostnames. Listeners and routes without hostnames
+// match all hostnames.
+func listenerHostnameMatches(listenerHostname *gatewayv1.Hostname, routeHostnames []gatewayv1.Hostname) bool {
+	if listenerHostname == nil || *listenerHostname == "" || len(routeHostnames) == 0 {
+		return true
+	}
+	return lo.ContainsBy(routeHostnames, func(hostname gatewayv1.Hostname) bool {
+		return hostnamesIntersect(string(*listenerHostname), string(hostname))
+	})
+}
+
+// hostnamesIntersect returns true if the provided hostnames, which may be
+// prefixed with a "*." wildcard label, match at least one common hostname.
+func hostnamesIntersect(a, b string) bool {
+	return a == b ||
+		(strings.HasPrefix(a, "*.") && strings.HasSuffix(b, a[1:])) ||
+		(strings.HasPrefix(b, "*.") && strings.HasSuffix(a, b[1:]))
+}
+
+// dataPlaneEgressBackends are the backends reached by the Pods of a DataPlane.
+type dataPlaneEgressBackends struct {
+	// services maps the Services to the ports referenced by the routes, nil
+	// meaning all of them.
+	services map[types.NamespacedName][]gatewayv1.PortNumber
+	// hybridEndpoints are the endpoints of the self-hosted hybrid control
+	// plane the DataPlane connects to.
+	hybridEndpoints []hybridControlPlaneEndpoint
+	// referenceGrantNamespaces are the namespaces of the Services referenced
+	// by routes from other namespaces, whose ReferenceGrants determine
+	// whether the Services are backends.
+	referenceGrantNamespaces map[string]struct{}
+}
+
+// hybridControlPlaneEndpoint is an endpoint of a self-hosted hybrid control plane.
+type hybridControlPlaneEndpoint struct {
+	host string
+	port int32
+}
+
+// service returns the Service the endpoint's host refers to when it's
+// the DNS name of a Service, e.g. "kong-cp.kong.svc.cluster.local" or
+// "kong-cp.kong", or a single label resolved in the DataPlane's namespace.
+func (e hybridControlPlaneEndpoint) service(namespace string) (types.NamespacedName, bool) {
+	if net.ParseIP(e.host) != nil {
+		return types.NamespacedName{}, false
+	}
+	parts := strings.Split(strings.TrimSuffix(e.host, "."), ".")
+	switch {
+	case len(parts) == 1:
+		return types.NamespacedName{Namespace: namespace, Name: parts[0]}, true
+	case len(parts) == 2, len(parts) >= 3 && parts[2] == "svc":
+		return types.NamespacedName{Namespace: parts[1], Name: parts[0]}, true
+	default:
+		return types.NamespacedName{}, false
+	}
+}
+
+// getDataPlaneEgressBackends returns the backends reached by the Pods of the
+// provided DataPlane: the Services referenced by the routes attached to the
+// listeners of the Gateways using the DataPlane and the endpoints of the
+// self-hosted hybrid control plane configured with the
+// consts.AnnotationDataPlaneHybridControlPlane annotation.
+// Services in other namespaces than the routes' are only backends when a
+// ReferenceGrant allows the routes to reference them.
+func getDataPlaneEgressBackends(
+	ctx context.Context,
+	getter egressObjectsGetter,
+	gateways []gwtypes.Gateway,
+	dataplane *operatorv1beta1.DataPlane,
+) (dataPlaneEgressBackends, error) {
+	backends := dataPlaneEgressBackends{
+		services:                 make(map[types.NamespacedName][]gatewayv1.PortNumber),
+		referenceGrantNamespaces: make(map[string]struct{}),
+	}
+
+	routes, err := getter.listRoutes(ctx)
+	if err != nil {
+		return backends, err
+	}
+	for _, route := range routes {
+		attached := false
+		for i := range gateways {
+			if attached, err = routeAttachesToGateway(ctx, getter, route, &gateways[i]); err != nil {
+				return backends, err
+			}
+			if attached {
+				break
+			}
+		}
+		if !attached {
+			continue
+		}
+		for _, ref := range route.backends {
+			if (ref.Group != nil && *ref.Group != "" && *ref.Group != "core") ||
+				(ref.Kind != nil && *ref.Kind != "Service") {
+				continue
+			}
+			nn := types.NamespacedName{Namespace: route.namespace, Name: string(ref.Name)}
+			if ref.Namespace != nil {
+				nn.Namespace = string(*ref.Namespace)
+			}
+			if nn.Namespace != route.namespace {
+				backends.referenceGrantNamespaces[nn.Namespace] = struct{}{}
+				allowed, err := crossNamespaceBackendAllowed(ctx, getter, route, nn)
+				if err != nil {
+					return backends, err
+				}
+				if !allowed {
+					continue
+				}
+			}
+			ports, ok := backends.services[nn]
+			switch {
+			case ok && ports == nil:
+			case ref.Port == nil:
+				backends.services[nn] = nil
+			default:
+				backends.services[nn] = append(ports, *ref.Port)
+			}
+		}
+	}
+
+	if cmName := dataplane.Annotations[consts.AnnotationDataPlaneHybridControlPlane]; cmName != "" {
+		cm, err := getter.getConfigMap(ctx, types.NamespacedName{Namespace: dataplane.Namespace, Name: cmName})
+		if err != nil {
+			return backends, err
+		}
+		// Missing or invalid configurations are reported in the DataPlane status.
+		if cm != nil {
+			for _, key := range []string{
+				consts.DataPlaneHybridConfigKeyControlPlane,
+				consts.DataPlaneHybridConfigKeyTelemetryEndpoint,
+			} {
+				host, port, err := net.SplitHostPort(cm.Data[key])
+				if err != nil {
+					continue
+				}
+				p, err := strconv.ParseInt(port, 10, 32)
+				if err != nil || p <= 0 {
+					continue
+				}
+				backends.hybridEndpoints = append(backends.hybridEndpoints, hybridControlPlaneEndpoint{host: host, port: int32(p)})
+			}
+		}
+	}
+	return backends, nil
+}
+
+// crossNamespaceBackendAllowed returns true if a ReferenceGrant in the namespace
+// of the provided Service allows the route to reference it.
+func crossNamespaceBackendAllowed(
+	ctx context.Context,
+	getter egressObjectsGetter,
+	route egressRoute,
+	service types.NamespacedName,
+) (bool, error) {
+	referenceGrants, err := getter.listReferenceGrants(ctx, service.Namespace)
+	if err != nil {
+		return false, err
+	}
+	return k8sutils.ReferenceGrantsAllow(referenceGrants,
+		gatewayv1beta1.ReferenceGrantFrom{
+			Group:     gatewayv1.GroupName,
+			Kind:      route.kind,
+			Namespace: gatewayv1.Namespace(route.namespace),
+		},
+		gatewayv1beta1.ReferenceGrantTo{
+			Kind: "Service",
+			Name: lo.ToPtr(gatewayv1.ObjectName(service.Name)),
+		},
+	), nil
+}
+
+// references returns true if the provided Service is one of the backends,
+// or might be the Service of one of the hybrid control plane endpoints.
+func (b dataPlaneEgressBackends) references(namespace string, nn types.NamespacedName) bool {
+	if _, ok := b.services[nn]; ok {
+		return true
+	}
+	return lo.ContainsBy(b.hybridEndpoints, func(e hybridControlPlaneEndpoint) bool {
+		service, ok := e.service(namespace)
+		return ok && service == nn
+	})
+}
+
+// dataPlaneBackendEgressRules returns the egress rules allowing the Pods of the
+// provided DataPlane to reach its backends:
+//   - the Pods of the Services referenced as backends, or as mirrors, by the
+//     HTTPRoutes, GRPCRoutes, TLSRoutes, TCPRoutes and UDPRoutes attached to the
+//     listeners of the Gateways using the DataPlane, on their target ports, or
+//     the addresses of their EndpointSlices when they have no selector, those
+//     in other namespaces than the routes' only when a ReferenceGrant allows it,
+//   - the endpoints of the self-hosted hybrid control plane: the Pods of the
+//     Service when the endpoint's host is the name of a Service, its address
+//     when it's an IP address and only its port otherwise.
+//
+// ExternalName Services are skipped as their endpoints can't be selected.
+func dataPlaneBackendEgressRules(
+	ctx context.Context,
+	getter egressObjectsGetter,
+	gateways []gwtypes.Gateway,
+	dataplane *operatorv1beta1.DataPlane,
+) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	backends, err := getDataPlaneEgressBackends(ctx, getter, gateways, dataplane)
+	if err != nil {
+		return nil, err
+	}
+	return backendsEgressRules(ctx, getter, dataplane, backends)
+}
+
+// backendsEgressRules returns the egress rules allowing the Pods of the provided
+// DataPlane to reach the provided backends, see dataPlaneBackendEgressRules.
+func backendsEgressRules(
+	ctx context.Context,
+	getter egressObjectsGetter,
+	dataplane *operatorv1beta1.DataPlane,
+	backends dataPlaneEgressBackends,
+) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	// Sort the Services so that the generated NetworkPolicy doesn't change
+	// with the order in which routes are listed.
+	services := lo.Keys(backends.services)
+	slices.SortFunc(services, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	var rules []networkingv1.NetworkPolicyEgressRule
+	for _, nn := range services {
+		rule, err := serviceEgressRule(ctx, getter, nn, backends.services[nn])
+		if err != nil {
+			return nil, err
+		}
+		if rule != nil {
+			rules = append(rules, *rule)
+		}
+	}
+
+	for _, endpoint := range backends.hybridEndpoints {
+		if nn, ok := endpoint.service(dataplane.Namespace); ok {
+			rule, err := serviceEgressRule(ctx, getter, nn, []gatewayv1.PortNumber{gatewayv1.PortNumber(endpoint.port)})
+			if err != nil {
+				return nil, err
+			}
+			if rule != nil {
+				rules = append(rules, *rule)
+				continue
+			}
+		}
+
+		protocolTCP := corev1.ProtocolTCP
+		port := intstr.FromInt32(endpoint.port)
+		rule := networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocolTCP, Port: &port}},
+		}
+		// Hostnames can't be used in NetworkPolicies and resolve to addresses
+		// that change, hence only the port is restricted for them.
+		if ip := net.ParseIP(endpoint.host); ip != nil {
+			rule.To = []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: hostCIDR(ip)}}}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// serviceEgressRule returns the egress rule allowing to reach the provided
+// Service on the provided ports, nil meaning all of them, or nil when the
+// Service doesn't exist or its endpoints can't be selected.
+func serviceEgressRule(
+	ctx context.Context,
+	getter egressObjectsGetter,
+	nn types.NamespacedName,
+	servicePorts []gatewayv1.PortNumber,
+) (*networkingv1.NetworkPolicyEgressRule, error) {
+	service, err := getter.getService(ctx, nn)
+	if err != nil || service == nil {
+		return nil, err
+	}
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return nil, nil
+	}
+	referenced := lo.Filter(service.Spec.Ports, func(servicePort corev1.ServicePort, _ int) bool {
+		return servicePorts == nil || slices.Contains(servicePorts, gatewayv1.PortNumber(servicePort.Port))
+	})
+	if len(referenced) == 0 {
+		return nil, nil
+	}
+
+	if len(service.Spec.Selector) > 0 {
+		var ports []networkingv1.NetworkPolicyPort
+		for _, servicePort := range referenced {
+			protocol := servicePortProtocol(servicePort.Protocol)
+			targetPort := servicePort.TargetPort
+			if targetPort.IntValue() == 0 && targetPort.StrVal == "" {
+				targetPort = intstr.FromInt32(servicePort.Port)
+			}
+			ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &targetPort})
+		}
+		return &networkingv1.NetworkPolicyEgressRule{
+			Ports: ports,
+			To: []networkingv1.NetworkPolicyPeer{{
+				PodSelector:       &metav1.LabelSelector{MatchLabels: service.Spec.Selector},
+				NamespaceSelector: namespaceNameSelector(service.Namespace),
+			}},
+		}, nil
+	}
+
+	// The endpoints of Services without selectors are managed by users, or
+	// other controllers, through EndpointSlices.
+	endpointSlices, err := getter.listEndpointSlices(ctx, nn)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ports []networkingv1.NetworkPolicyPort
+		cidrs []string
+	)
+	for _, endpointSlice := range endpointSlices {
+		if endpointSlice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+		var slicePorts []networkingv1.NetworkPolicyPort
+		for _, servicePort := range referenced {
+			for _, endpointPort := range endpointSlice.Ports {
+				if lo.FromPtr(endpointPort.Name) != servicePort.Name || endpointPort.Port == nil {
+					continue
+				}
+				protocol := servicePortProtocol(lo.FromPtr(endpointPort.Protocol))
+				port := intstr.FromInt32(*endpointPort.Port)
+				slicePorts = append(slicePorts, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+			}
+		}
+		if len(slicePorts) == 0 {
+			continue
+		}
+		for _, port := range slicePorts {
+			if !lo.ContainsBy(ports, func(p networkingv1.NetworkPolicyPort) bool {
+				return *p.Protocol == *port.Protocol && *p.Port == *port.Port
+			}) {
+				ports = append(ports, port)
+			}
+		}
+		for _, endpoint := range endpointSlice.Endpoints {
+			for _, address := range endpoint.Addresses {
+				if ip := net.ParseIP(address); ip != nil {
+					cidrs = append(cidrs, hostCIDR(ip))
+				}
+			}
+		}
+	}
+	if len(ports) == 0 || len(cidrs) == 0 {
+		return nil, nil
+	}
+	slices.Sort(cidrs)
+	return &networkingv1.NetworkPolicyEgressRule{
+		Ports: ports,
+		To: lo.Map(slices.Compact(cidrs), func(cidr string, _ int) networkingv1.NetworkPolicyPeer {
+			return networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}}
+		}),
+	}, nil
+}
+
+// servicePortProtocol returns the provided protocol or TCP when it's not set.
+func servicePortProtocol(protocol corev1.Protocol) corev1.Protocol {
+	if protocol == "" {
+		return corev1.ProtocolTCP
+	}
+	return protocol
+}
+
+// hostCIDR returns the CIDR matching only the provided IP address.
+func hostCIDR(ip net.IP) string {
+	if ip.To4() != nil {
+		return ip.String() + "/32"
+	}
+	return ip.String() + "/128"
+}
+
+// gatewaysUsingDataPlane returns the Gateways owning the provided DataPlane,
+// more than one when it's shared, so that the egress rules of each Gateway's
+// NetworkPolicy allow reaching the backends of all of them.
+func (r *Reconciler) gatewaysUsingDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+) ([]gwtypes.Gateway, error) {
+	var gateways []gwtypes.Gateway
+	for _, ownerRef := range dataplane.OwnerReferences {
+		if ownerRef.Kind != "Gateway" {
+			continue
+		}
+		var gateway gwtypes.Gateway
+		if err := r.Get(ctx, types.NamespacedName{Namespace: dataplane.Namespace, Name: ownerRef.Name}, &gateway); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed getting Gateway %s using DataPlane %s: %w", ownerRef.Name, dataplane.Name, err)
+		}
+		if gateway.UID == ownerRef.UID {
+			gateways = append(gateways, gateway)
+		}
+	}
+	return gateways, nil
+}
+
+// dataPlaneEgressDependencies are the objects which the egress rules of a
+// DataPlane with restricted egress depend on.
+type dataPlaneEgressDependencies struct {
+	// dataplane is the DataPlane.
+	dataplane types.NamespacedName
+	// hybridConfigMap is the name of the ConfigMap configuring the self-hosted
+	// hybrid control plane the DataPlane connects to, if any.
+	hybridConfigMap string
+	// gateways are the Gateways using the DataPlane.
+	gateways []types.NamespacedName
+	// backends are the backends reached by the DataPlane's Pods.
+	backends dataPlaneEgressBackends
+}
+
+// newDataPlaneEgressDependencies returns the dependencies of the egress rules
+// of the provided DataPlane, used by the provided Gateways, with the provided
+// backends.
+func newDataPlaneEgressDependencies(
+	gateways []gwtypes.Gateway,
+	dataplane *operatorv1beta1.DataPlane,
+	backends dataPlaneEgressBackends,
+) dataPlaneEgressDependencies {
+	return dataPlaneEgressDependencies{
+		dataplane:       client.ObjectKeyFromObject(dataplane),
+		hybridConfigMap: dataplane.Annotations[consts.AnnotationDataPlaneHybridControlPlane],
+		gateways: lo.Map(gateways, func(gateway gwtypes.Gateway, _ int) types.NamespacedName {
+			return client.ObjectKeyFromObject(&gateway)
+		}),
+		backends: backends,
+	}
+}
+
+// dependOn returns true if the egress rules depend on the provided route,
+// Service, EndpointSlice, ConfigMap or ReferenceGrant.
+func (d dataPlaneEgressDependencies) dependOn(obj client.Object) bool {
+	var service types.NamespacedName
+	switch o := obj.(type) {
+	case *corev1.ConfigMap:
+		return d.hybridConfigMap != "" &&
+			client.ObjectKeyFromObject(o) == types.NamespacedName{Namespace: d.dataplane.Namespace, Name: d.hybridConfigMap}
+	case *gatewayv1beta1.ReferenceGrant:
+		_, ok := d.backends.referenceGrantNamespaces[o.Namespace]
+		return ok
+	case *corev1.Service:
+		service = client.ObjectKeyFromObject(o)
+	case *discoveryv1.EndpointSlice:
+		serviceName := o.Labels[discoveryv1.LabelServiceName]
+		if serviceName == "" {
+			return false
+		}
+		service = types.NamespacedName{Namespace: o.Namespace, Name: serviceName}
+	default:
+		route, ok := egressRouteForObject(obj)
+		if !ok {
+			return false
+		}
+		// Routes referencing the Gateways are taken into account whether or
+		// not they are accepted by their listeners.
+		return lo.ContainsBy(route.parentRefs, func(parentRef gatewayv1.ParentReference) bool {
+			namespace := route.namespace
+			if parentRef.Namespace != nil {
+				namespace = string(*parentRef.Namespace)
+			}
+			return slices.Contains(d.gateways, types.NamespacedName{Namespace: namespace, Name: string(parentRef.Name)})
+		})
+	}
+	return d.backends.references(d.dataplane.Namespace, service)
+}
+
+// egressDependenciesCache caches the dependencies of the egress rules of the
+// DataPlanes with restricted egress, recorded when generating their
+// NetworkPolicies, so that the watches of the objects which they depend on
+// don't need to resolve the backends of all the DataPlanes for each event.
+// Its zero value is ready to use, while a nil cache records nothing, e.g. when
+// the Reconciler isn't set up with a manager and there are no watches.
+type egressDependenciesCache struct {
+	lock         sync.RWMutex
+	dependencies map[types.NamespacedName]dataPlaneEgressDependencies
+}
+
+// set records the dependencies of the egress rules of a DataPlane.
+func (c *egressDependenciesCache) set(dependencies dataPlaneEgressDependencies) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.dependencies == nil {
+		c.dependencies = make(map[types.NamespacedName]dataPlaneEgressDependencies)
+	}
+	c.dependencies[dependencies.dataplane] = dependencies
+}
+
+// delete removes the dependencies of the egress rules of the provided DataPlane,
+// e.g. when its egress isn't restricted anymore.
+func (c *egressDependenciesCache) delete(dataplane types.NamespacedName) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.dependencies, dataplane)
+}
+
+// deleteForGateway removes the dependencies of the egress rules of the
+// DataPlanes used by the provided Gateway. The dependencies of DataPlanes
+// shared with other Gateways are recorded again when the latter are reconciled.
+func (c *egressDependenciesCache) deleteForGateway(gateway types.NamespacedName) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	maps.DeleteFunc(c.dependencies, func(_ types.NamespacedName, d dataPlaneEgressDependencies) bool {
+		return slices.Contains(d.gateways, gateway)
+	})
+}
+
+// gatewaysDependingOn returns the Gateways using the DataPlanes whose egress
+// rules depend on the provided object.
+func (c *egressDependenciesCache) gatewaysDependingOn(obj client.Object) []types.NamespacedName {
+	if c == nil {
+		return nil
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var gateways []types.NamespacedName
+	for _, d := range c.dependencies {
+		if !d.dependOn(obj) {
+			continue
+		}
+		for _, gateway := range d.gateways {
+			if !slices.Contains(gateways, gateway) {
+				gateways = append(gateways, gateway)
+			}
+		}
+	}
+	return gateways
+}
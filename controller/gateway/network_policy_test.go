@@ -0,0 +1,649 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestGenerateDataPlaneNetworkPolicyRestrictions(t *testing.T) {
+	var (
+		protocolTCP = corev1.ProtocolTCP
+		protocolUDP = corev1.ProtocolUDP
+		backendPort = intstr.FromString("http")
+		dnsPort     = intstr.FromInt(53)
+		konnectPort = intstr.FromInt(443)
+		backends    = []networkingv1.NetworkPolicyEgressRule{{
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocolTCP, Port: &backendPort}},
+			To: []networkingv1.NetworkPolicyPeer{{
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "echo"}},
+				NamespaceSelector: namespaceNameSelector("apps"),
+			}},
+		}}
+		controlplane = &operatorv1beta1.ControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cp"},
+		}
+	)
+	dataplane := func(annotations map[string]string, extensions ...commonv1alpha1.ExtensionRef) *operatorv1beta1.DataPlane {
+		return &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dp", Annotations: annotations},
+			Spec: operatorv1beta1.DataPlaneSpec{
+				DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+					Extensions: extensions,
+					Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1beta1.DeploymentOptions{
+							PodTemplateSpec: &corev1.PodTemplateSpec{
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{{Name: consts.DataPlaneProxyContainerName}},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("unrestricted", func(t *testing.T) {
+		policy, err := generateDataPlaneNetworkPolicy("default", dataplane(nil), controlplane, backends)
+		require.NoError(t, err)
+		require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+		require.Len(t, policy.Spec.Ingress, 3)
+		require.Nil(t, policy.Spec.Ingress[1].From, "proxy ports should be reachable from anywhere")
+		require.Nil(t, policy.Spec.Ingress[2].From, "metrics port should be reachable from anywhere")
+		require.Nil(t, policy.Spec.Egress)
+	})
+
+	t.Run("proxy sources and metrics scrapers", func(t *testing.T) {
+		policy, err := generateDataPlaneNetworkPolicy("default", dataplane(map[string]string{
+			consts.AnnotationNetworkPolicyProxySourceCIDRs:      "10.0.0.0/8, 192.168.0.0/16",
+			consts.AnnotationNetworkPolicyProxySourceNamespaces: "frontend",
+			consts.AnnotationNetworkPolicyMetricsScrapers:       "monitoring/app.kubernetes.io/name=prometheus;datadog",
+		}), controlplane, backends)
+		require.NoError(t, err)
+		require.Equal(t, []networkingv1.NetworkPolicyPeer{
+			{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}},
+			{IPBlock: &networkingv1.IPBlock{CIDR: "192.168.0.0/16"}},
+			{NamespaceSelector: namespaceNameSelector("frontend")},
+		}, policy.Spec.Ingress[1].From)
+		require.Equal(t, []networkingv1.NetworkPolicyPeer{
+			{
+				NamespaceSelector: namespaceNameSelector("monitoring"),
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/name": "prometheus"}},
+			},
+			{NamespaceSelector: namespaceNameSelector("datadog")},
+		}, policy.Spec.Ingress[2].From)
+		require.Nil(t, policy.Spec.Egress)
+	})
+
+	t.Run("restricted egress", func(t *testing.T) {
+		policy, err := generateDataPlaneNetworkPolicy("default", dataplane(map[string]string{
+			consts.AnnotationNetworkPolicyEgress:      networkPolicyEgressRestricted,
+			consts.AnnotationNetworkPolicyEgressCIDRs: "203.0.113.0/24",
+		}, commonv1alpha1.ExtensionRef{
+			Group: konnectv1alpha1.SchemeGroupVersion.Group,
+			Kind:  konnectv1alpha1.KonnectExtensionKind,
+			NamespacedRef: commonv1alpha1.NamespacedRef{
+				Name: "konnect",
+			},
+		}), controlplane, backends)
+		require.NoError(t, err)
+		require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+		require.Equal(t, []networkingv1.NetworkPolicyEgressRule{
+			{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &protocolUDP, Port: &dnsPort},
+					{Protocol: &protocolTCP, Port: &dnsPort},
+				},
+			},
+			backends[0],
+			{
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocolTCP, Port: &konnectPort}},
+			},
+			{
+				To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "203.0.113.0/24"}}},
+			},
+		}, policy.Spec.Egress)
+	})
+
+	t.Run("invalid annotations", func(t *testing.T) {
+		for _, annotations := range []map[string]string{
+			{consts.AnnotationNetworkPolicyProxySourceCIDRs: "10.0.0.0"},
+			{consts.AnnotationNetworkPolicyMetricsScrapers: "/app=prometheus"},
+			{consts.AnnotationNetworkPolicyMetricsScrapers: "monitoring/app in prometheus"},
+			{
+				consts.AnnotationNetworkPolicyEgress:      networkPolicyEgressRestricted,
+				consts.AnnotationNetworkPolicyEgressCIDRs: "not-a-cidr",
+			},
+		} {
+			_, err := generateDataPlaneNetworkPolicy("default", dataplane(annotations), controlplane, nil)
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestDataPlaneBackendEgressRules(t *testing.T) {
+	var (
+		protocolTCP = corev1.ProtocolTCP
+		protocolUDP = corev1.ProtocolUDP
+	)
+	gatewayWithListeners := func(name string, listeners ...gatewayv1.Listener) gwtypes.Gateway {
+		return gwtypes.Gateway{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: gatewayv1.GroupVersion.String(),
+				Kind:       "Gateway",
+			},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)},
+			Spec:       gatewayv1.GatewaySpec{Listeners: listeners},
+		}
+	}
+	allNamespaces := &gatewayv1.AllowedRoutes{
+		Namespaces: &gatewayv1.RouteNamespaces{From: lo.ToPtr(gatewayv1.NamespacesFromAll)},
+	}
+	gateway := gatewayWithListeners("gw", gatewayv1.Listener{
+		Name:          "http",
+		Port:          80,
+		Protocol:      gatewayv1.HTTPProtocolType,
+		AllowedRoutes: allNamespaces,
+	})
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dp"},
+	}
+	parentRefs := func(gatewayName string, sectionName string) []gatewayv1.ParentReference {
+		ref := gatewayv1.ParentReference{
+			Name:      gatewayv1.ObjectName(gatewayName),
+			Namespace: lo.ToPtr(gatewayv1.Namespace("default")),
+		}
+		if sectionName != "" {
+			ref.SectionName = lo.ToPtr(gatewayv1.SectionName(sectionName))
+		}
+		return []gatewayv1.ParentReference{ref}
+	}
+	httpRoute := func(namespace, name, gatewayNamespace string, rules ...gatewayv1.HTTPRouteRule) *gwtypes.HTTPRoute {
+		return &gwtypes.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{
+						Name:      "gw",
+						Namespace: lo.ToPtr(gatewayv1.Namespace(gatewayNamespace)),
+					}},
+				},
+				Rules: rules,
+			},
+		}
+	}
+	backendRef := func(namespace, name string, port *gatewayv1.PortNumber) gatewayv1.BackendObjectReference {
+		ref := gatewayv1.BackendObjectReference{
+			Name: gatewayv1.ObjectName(name),
+			Port: port,
+		}
+		if namespace != "" {
+			ref.Namespace = lo.ToPtr(gatewayv1.Namespace(namespace))
+		}
+		return ref
+	}
+	service := func(namespace, name string, selector map[string]string, ports ...corev1.ServicePort) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: corev1.ServiceSpec{
+				Selector: selector,
+				Ports:    ports,
+			},
+		}
+	}
+	podsRule := func(namespace string, selector map[string]string, protocol *corev1.Protocol, port intstr.IntOrString) networkingv1.NetworkPolicyEgressRule {
+		return networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: protocol, Port: &port}},
+			To: []networkingv1.NetworkPolicyPeer{{
+				PodSelector:       &metav1.LabelSelector{MatchLabels: selector},
+				NamespaceSelector: namespaceNameSelector(namespace),
+			}},
+		}
+	}
+	rulesFor := func(t *testing.T, gateways []gwtypes.Gateway, dataplane *operatorv1beta1.DataPlane, objects ...client.Object) []networkingv1.NetworkPolicyEgressRule {
+		t.Helper()
+		cl := fakectrlruntimeclient.NewClientBuilder().
+			WithScheme(scheme.Get()).
+			WithObjects(objects...).
+			Build()
+		rules, err := dataPlaneBackendEgressRules(t.Context(), clientEgressObjectsGetter{cl: cl}, gateways, dataplane)
+		require.NoError(t, err)
+
+		// Rendering resolves the egress rules out of the same objects.
+		rendered, err := dataPlaneBackendEgressRules(t.Context(), objectsEgressObjectsGetter(objects), gateways, dataplane)
+		require.NoError(t, err)
+		require.Equal(t, rules, rendered)
+		return rules
+	}
+
+	t.Run("HTTPRoutes", func(t *testing.T) {
+		objects := []client.Object{
+			httpRoute("apps", "echo", "default", gatewayv1.HTTPRouteRule{
+				BackendRefs: []gatewayv1.HTTPBackendRef{
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef("", "echo", lo.ToPtr(gatewayv1.PortNumber(80)))}},
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef("", "missing", nil)}},
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef("", "external", nil)}},
+					// No ReferenceGrant allows referencing the Service.
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef("denied", "echo", nil)}},
+				},
+				Filters: []gatewayv1.HTTPRouteFilter{{
+					Type: gatewayv1.HTTPRouteFilterRequestMirror,
+					RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+						BackendRef: backendRef("shadow", "echo", nil),
+					},
+				}},
+			}),
+			httpRoute("apps", "other-gateway", "other", gatewayv1.HTTPRouteRule{
+				BackendRefs: []gatewayv1.HTTPBackendRef{
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef("", "other", nil)}},
+				},
+			}),
+			service("apps", "echo", map[string]string{"app": "echo"},
+				corev1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+				corev1.ServicePort{Name: "admin", Port: 8080},
+			),
+			service("apps", "external", nil),
+			service("apps", "other", map[string]string{"app": "other"}, corev1.ServicePort{Port: 80}),
+			service("shadow", "echo", map[string]string{"app": "shadow"},
+				corev1.ServicePort{Port: 53, Protocol: corev1.ProtocolUDP, TargetPort: intstr.FromInt(5353)},
+			),
+			&gatewayv1beta1.ReferenceGrant{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shadow", Name: "apps-routes"},
+				Spec: gatewayv1beta1.ReferenceGrantSpec{
+					From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "apps"}},
+					To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service"}},
+				},
+			},
+			service("denied", "echo", map[string]string{"app": "denied"}, corev1.ServicePort{Port: 80}),
+			&gatewayv1beta1.ReferenceGrant{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "denied", Name: "other-routes"},
+				Spec: gatewayv1beta1.ReferenceGrantSpec{
+					From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "other"}},
+					To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service"}},
+				},
+			},
+		}
+
+		require.Equal(t, []networkingv1.NetworkPolicyEgressRule{
+			podsRule("apps", map[string]string{"app": "echo"}, &protocolTCP, intstr.FromString("http")),
+			podsRule("shadow", map[string]string{"app": "shadow"}, &protocolUDP, intstr.FromInt(5353)),
+		}, rulesFor(t, []gwtypes.Gateway{gateway}, dataplane, objects...))
+	})
+
+	t.Run("routes of all kinds attached to matching listeners", func(t *testing.T) {
+		gateway := gatewayWithListeners("gw",
+			gatewayv1.Listener{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType, Hostname: lo.ToPtr(gatewayv1.Hostname("*.example.com"))},
+			gatewayv1.Listener{Name: "tls", Port: 443, Protocol: gatewayv1.TLSProtocolType},
+			gatewayv1.Listener{Name: "tcp", Port: 5432, Protocol: gatewayv1.TCPProtocolType},
+			gatewayv1.Listener{Name: "udp", Port: 53, Protocol: gatewayv1.UDPProtocolType, AllowedRoutes: &gatewayv1.AllowedRoutes{
+				Namespaces: &gatewayv1.RouteNamespaces{
+					From:     lo.ToPtr(gatewayv1.NamespacesFromSelector),
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"dns": "true"}},
+				},
+			}},
+		)
+		serviceBackend := func(name string) []gatewayv1.BackendRef {
+			return []gatewayv1.BackendRef{{BackendObjectReference: backendRef("", name, nil)}}
+		}
+		objects := []client.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"dns": "true"}}},
+			&gatewayv1.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "grpc"},
+				Spec: gatewayv1.GRPCRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs("gw", "")},
+					Hostnames:       []gatewayv1.Hostname{"grpc.example.com"},
+					Rules: []gatewayv1.GRPCRouteRule{{
+						BackendRefs: []gatewayv1.GRPCBackendRef{{BackendRef: serviceBackend("grpc")[0]}},
+					}},
+				},
+			},
+			// The hostname doesn't intersect with the HTTP listener's.
+			&gatewayv1.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "grpc-other-host"},
+				Spec: gatewayv1.GRPCRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs("gw", "")},
+					Hostnames:       []gatewayv1.Hostname{"grpc.example.org"},
+					Rules: []gatewayv1.GRPCRouteRule{{
+						BackendRefs: []gatewayv1.GRPCBackendRef{{BackendRef: serviceBackend("grpc-other-host")[0]}},
+					}},
+				},
+			},
+			&gatewayv1alpha2.TLSRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tls"},
+				Spec: gatewayv1alpha2.TLSRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs("gw", "tls")},
+					Rules:           []gatewayv1alpha2.TLSRouteRule{{BackendRefs: serviceBackend("tls")}},
+				},
+			},
+			&gatewayv1alpha2.TCPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tcp"},
+				Spec: gatewayv1alpha2.TCPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs("gw", "")},
+					Rules:           []gatewayv1alpha2.TCPRouteRule{{BackendRefs: serviceBackend("tcp")}},
+				},
+			},
+			// The section name references a listener which doesn't accept TCPRoutes.
+			&gatewayv1alpha2.TCPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tcp-wrong-section"},
+				Spec: gatewayv1alpha2.TCPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs("gw", "http")},
+					Rules:           []gatewayv1alpha2.TCPRouteRule{{BackendRefs: serviceBackend("tcp-wrong-section")}},
+				},
+			},
+			&gatewayv1alpha2.UDPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "udp"},
+				Spec: gatewayv1alpha2.UDPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs("gw", "")},
+					Rules:           []gatewayv1alpha2.UDPRouteRule{{BackendRefs: serviceBackend("udp")}},
+				},
+			},
+		}
+		for _, name := range []string{"grpc", "grpc-other-host", "tls", "tcp", "tcp-wrong-section"} {
+			objects = append(objects, service("default", name, map[string]string{"app": name}, corev1.ServicePort{Port: 8000}))
+		}
+		objects = append(objects, service("default", "udp", map[string]string{"app": "udp"}, corev1.ServicePort{Port: 53, Protocol: corev1.ProtocolUDP}))
+
+		require.Equal(t, []networkingv1.NetworkPolicyEgressRule{
+			podsRule("default", map[string]string{"app": "grpc"}, &protocolTCP, intstr.FromInt(8000)),
+			podsRule("default", map[string]string{"app": "tcp"}, &protocolTCP, intstr.FromInt(8000)),
+			podsRule("default", map[string]string{"app": "tls"}, &protocolTCP, intstr.FromInt(8000)),
+			podsRule("default", map[string]string{"app": "udp"}, &protocolUDP, intstr.FromInt(53)),
+		}, rulesFor(t, []gwtypes.Gateway{gateway}, dataplane, objects...))
+	})
+
+	t.Run("routes not allowed by the listeners", func(t *testing.T) {
+		gateway := gatewayWithListeners("gw", gatewayv1.Listener{
+			Name:     "http",
+			Port:     80,
+			Protocol: gatewayv1.HTTPProtocolType,
+			AllowedRoutes: &gatewayv1.AllowedRoutes{
+				Kinds: []gatewayv1.RouteGroupKind{{Kind: "GRPCRoute"}},
+			},
+		})
+		objects := []client.Object{
+			// HTTPRoutes are not allowed by the listener.
+			&gwtypes.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "http"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs("gw", "")},
+					Rules: []gatewayv1.HTTPRouteRule{{
+						BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef("", "echo", nil)}}},
+					}},
+				},
+			},
+			// Routes from other namespaces are not allowed by the listener.
+			&gatewayv1.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "grpc"},
+				Spec: gatewayv1.GRPCRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs("gw", "")},
+					Rules: []gatewayv1.GRPCRouteRule{{
+						BackendRefs: []gatewayv1.GRPCBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef("default", "echo", nil)}}},
+					}},
+				},
+			},
+			service("default", "echo", map[string]string{"app": "echo"}, corev1.ServicePort{Port: 80}),
+		}
+
+		require.Empty(t, rulesFor(t, []gwtypes.Gateway{gateway}, dataplane, objects...))
+	})
+
+	t.Run("Gateways sharing the DataPlane", func(t *testing.T) {
+		other := gatewayWithListeners("other", gatewayv1.Listener{Name: "http", Port: 8080, Protocol: gatewayv1.HTTPProtocolType})
+		objects := []client.Object{
+			&gwtypes.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs("other", "")},
+					Rules: []gatewayv1.HTTPRouteRule{{
+						BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef("", "echo", nil)}}},
+					}},
+				},
+			},
+			service("default", "echo", map[string]string{"app": "echo"}, corev1.ServicePort{Port: 80}),
+		}
+
+		require.Empty(t, rulesFor(t, []gwtypes.Gateway{gateway}, dataplane, objects...))
+		require.Equal(t, []networkingv1.NetworkPolicyEgressRule{
+			podsRule("default", map[string]string{"app": "echo"}, &protocolTCP, intstr.FromInt(80)),
+		}, rulesFor(t, []gwtypes.Gateway{gateway, other}, dataplane, objects...))
+	})
+
+	t.Run("Services without selector", func(t *testing.T) {
+		objects := []client.Object{
+			httpRoute("default", "external", "default", gatewayv1.HTTPRouteRule{
+				BackendRefs: []gatewayv1.HTTPBackendRef{
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef("", "external", lo.ToPtr(gatewayv1.PortNumber(443)))}},
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: backendRef("", "external-name", nil)}},
+				},
+			}),
+			service("default", "external", nil,
+				corev1.ServicePort{Name: "https", Port: 443, TargetPort: intstr.FromInt(8443)},
+				corev1.ServicePort{Name: "admin", Port: 8444},
+			),
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "external-name"},
+				Spec: corev1.ServiceSpec{
+					Type:         corev1.ServiceTypeExternalName,
+					ExternalName: "example.com",
+					Ports:        []corev1.ServicePort{{Port: 443}},
+				},
+			},
+			&discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "external-v4",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "external"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{Addresses: []string{"203.0.113.2"}},
+					{Addresses: []string{"203.0.113.1"}},
+				},
+				Ports: []discoveryv1.EndpointPort{
+					{Name: lo.ToPtr("https"), Port: lo.ToPtr(int32(8443))},
+					{Name: lo.ToPtr("admin"), Port: lo.ToPtr(int32(8444))},
+				},
+			},
+			&discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "external-v6",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "external"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv6,
+				Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"2001:db8::1"}}},
+				Ports:       []discoveryv1.EndpointPort{{Name: lo.ToPtr("https"), Port: lo.ToPtr(int32(8443))}},
+			},
+		}
+
+		port := intstr.FromInt(8443)
+		require.Equal(t, []networkingv1.NetworkPolicyEgressRule{
+			{
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocolTCP, Port: &port}},
+				To: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "2001:db8::1/128"}},
+					{IPBlock: &networkingv1.IPBlock{CIDR: "203.0.113.1/32"}},
+					{IPBlock: &networkingv1.IPBlock{CIDR: "203.0.113.2/32"}},
+				},
+			},
+		}, rulesFor(t, []gwtypes.Gateway{gateway}, dataplane, objects...))
+	})
+
+	t.Run("self-hosted hybrid control plane", func(t *testing.T) {
+		dataplane := &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "dp",
+				Annotations: map[string]string{
+					consts.AnnotationDataPlaneHybridControlPlane: "hybrid",
+				},
+			},
+		}
+		hybrid := func(controlPlane, telemetry string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "hybrid"},
+				Data: map[string]string{
+					consts.DataPlaneHybridConfigKeyControlPlane:      controlPlane,
+					consts.DataPlaneHybridConfigKeyTelemetryEndpoint: telemetry,
+				},
+			}
+		}
+		cp := service("kong", "kong-cp", map[string]string{"app": "kong-cp"},
+			corev1.ServicePort{Name: "cluster", Port: 8005},
+			corev1.ServicePort{Name: "telemetry", Port: 8006, TargetPort: intstr.FromInt(9006)},
+		)
+		portOnly := func(port int) networkingv1.NetworkPolicyEgressRule {
+			p := intstr.FromInt32(int32(port))
+			return networkingv1.NetworkPolicyEgressRule{
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocolTCP, Port: &p}},
+			}
+		}
+
+		require.Equal(t, []networkingv1.NetworkPolicyEgressRule{
+			podsRule("kong", map[string]string{"app": "kong-cp"}, &protocolTCP, intstr.FromInt(8005)),
+			podsRule("kong", map[string]string{"app": "kong-cp"}, &protocolTCP, intstr.FromInt(9006)),
+		}, rulesFor(t, []gwtypes.Gateway{gateway}, dataplane,
+			hybrid("kong-cp.kong.svc.cluster.local:8005", "kong-cp.kong:8006"), cp,
+		))
+
+		ipRule := portOnly(8005)
+		ipRule.To = []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "198.51.100.1/32"}}}
+		require.Equal(t, []networkingv1.NetworkPolicyEgressRule{
+			ipRule,
+			portOnly(8006),
+		}, rulesFor(t, []gwtypes.Gateway{gateway}, dataplane,
+			hybrid("198.51.100.1:8005", "cp.example.com:8006"), cp,
+		))
+
+		require.Empty(t, rulesFor(t, []gwtypes.Gateway{gateway}, dataplane, hybrid("invalid", "")))
+	})
+}
+
+func TestEgressRulesDependOnObject(t *testing.T) {
+	gateway := gwtypes.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+		},
+	}
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "dp",
+			Annotations: map[string]string{
+				consts.AnnotationNetworkPolicyEgress:         networkPolicyEgressRestricted,
+				consts.AnnotationDataPlaneHybridControlPlane: "hybrid",
+			},
+		},
+	}
+	route := &gwtypes.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "echo"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				BackendRefs: []gatewayv1.HTTPBackendRef{
+					{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "echo"},
+					}},
+					{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name:      "echo",
+							Namespace: lo.ToPtr(gatewayv1.Namespace("other")),
+						},
+					}},
+				},
+			}},
+		},
+	}
+	hybrid := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "hybrid"},
+		Data: map[string]string{
+			consts.DataPlaneHybridConfigKeyControlPlane: "kong-cp.kong.svc:8005",
+		},
+	}
+	getter := objectsEgressObjectsGetter{route, hybrid}
+
+	for _, tc := range []struct {
+		name    string
+		obj     client.Object
+		depends bool
+	}{
+		{name: "attached route", obj: route, depends: true},
+		{
+			name: "route attached to another Gateway",
+			obj: &gwtypes.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{{Name: "other"}},
+					},
+				},
+			},
+		},
+		{name: "backend Service", obj: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "echo"}}, depends: true},
+		{name: "other Service", obj: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}}},
+		{name: "hybrid control plane Service", obj: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "kong-cp"}}, depends: true},
+		{
+			name: "backend Service EndpointSlice",
+			obj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "echo-abcde",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "echo"},
+			}},
+			depends: true,
+		},
+		{name: "hybrid ConfigMap", obj: hybrid, depends: true},
+		{name: "other ConfigMap", obj: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}}},
+		{
+			name:    "ReferenceGrant in the namespace of a cross-namespace backend",
+			obj:     &gatewayv1beta1.ReferenceGrant{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "grant"}},
+			depends: true,
+		},
+		{name: "other ReferenceGrant", obj: &gatewayv1beta1.ReferenceGrant{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "grant"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			backends, err := getDataPlaneEgressBackends(t.Context(), getter, []gwtypes.Gateway{gateway}, dataplane)
+			require.NoError(t, err)
+			dependencies := newDataPlaneEgressDependencies([]gwtypes.Gateway{gateway}, dataplane, backends)
+			require.Equal(t, tc.depends, dependencies.dependOn(tc.obj))
+		})
+	}
+
+	t.Run("cache", func(t *testing.T) {
+		backends, err := getDataPlaneEgressBackends(t.Context(), getter, []gwtypes.Gateway{gateway}, dataplane)
+		require.NoError(t, err)
+
+		var cache egressDependenciesCache
+		require.Empty(t, cache.gatewaysDependingOn(route))
+
+		cache.set(newDataPlaneEgressDependencies([]gwtypes.Gateway{gateway}, dataplane, backends))
+		require.Equal(t, []types.NamespacedName{{Namespace: "default", Name: "gw"}}, cache.gatewaysDependingOn(route))
+		require.Empty(t, cache.gatewaysDependingOn(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}}))
+
+		cache.deleteForGateway(types.NamespacedName{Namespace: "default", Name: "gw"})
+		require.Empty(t, cache.gatewaysDependingOn(route))
+	})
+}
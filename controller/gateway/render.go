@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// RenderOwnedResourcesParams holds the parameters for RenderOwnedResources.
+type RenderOwnedResourcesParams struct {
+	GatewayClass  *gatewayv1.GatewayClass
+	Gateway       *gwtypes.Gateway
+	GatewayConfig *operatorv1beta1.GatewayConfiguration
+
+	// DataPlaneIngressServiceName and DataPlaneAdminServiceName are the names
+	// of the DataPlane's Services which the ControlPlane is configured with.
+	DataPlaneIngressServiceName string
+	DataPlaneAdminServiceName   string
+
+	DefaultDataPlaneImage   string
+	AnonymousReportsEnabled bool
+}
+
+// RenderDataPlane generates the DataPlane that the Gateway controller would
+// create for the provided Gateway without reaching out to the API server.
+// The name of the DataPlane is derived from its GenerateName.
+func RenderDataPlane(params RenderOwnedResourcesParams) (*operatorv1beta1.DataPlane, error) {
+	r := &Reconciler{
+		DefaultDataPlaneImage: params.DefaultDataPlaneImage,
+	}
+	gatewayConfig := params.GatewayConfig.DeepCopy()
+	r.setDataPlaneGatewayConfigDefaults(gatewayConfig)
+	dataplane, err := r.generateDataPlane(params.Gateway, gatewayConfig, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating DataPlane for Gateway %s: %w", params.Gateway.Name, err)
+	}
+	k8sutils.SetNameFromGenerateName(dataplane)
+	return dataplane, nil
+}
+
+// RenderControlPlane generates the ControlPlane that the Gateway controller would
+// create for the provided Gateway and its (already rendered) DataPlane without
+// reaching out to the API server.
+// The name of the ControlPlane is derived from its GenerateName.
+func RenderControlPlane(
+	params RenderOwnedResourcesParams,
+	dataplane *operatorv1beta1.DataPlane,
+) *operatorv1beta1.ControlPlane {
+	r := &Reconciler{
+		AnonymousReportsEnabled: params.AnonymousReportsEnabled,
+	}
+	gatewayConfig := params.GatewayConfig.DeepCopy()
+	r.setControlPlaneGatewayConfigDefaults(params.Gateway, gatewayConfig, nil,
+		dataplane.Name, params.DataPlaneIngressServiceName, params.DataPlaneAdminServiceName, "",
+	)
+	controlplane := generateControlPlane(params.GatewayClass, params.Gateway, gatewayConfig, dataplane.Name, nil)
+	k8sutils.SetNameFromGenerateName(controlplane)
+	return controlplane
+}
+
+// RenderNetworkPolicy generates the NetworkPolicy that the Gateway controller
+// would create for the provided Gateway, DataPlane and ControlPlane without
+// reaching out to the API server.
+// The name of the NetworkPolicy is derived from its GenerateName. When egress is
+// restricted, the routes, Services, EndpointSlices, Namespaces, ReferenceGrants
+// and ConfigMaps its egress rules depend on are looked up in the provided objects.
+func RenderNetworkPolicy(
+	gateway *gwtypes.Gateway,
+	dataplane *operatorv1beta1.DataPlane,
+	controlplane *operatorv1beta1.ControlPlane,
+	objs []client.Object,
+) (*networkingv1.NetworkPolicy, error) {
+	var backends []networkingv1.NetworkPolicyEgressRule
+	if dataPlaneEgressRestricted(dataplane) {
+		var err error
+		backends, err = dataPlaneBackendEgressRules(context.Background(), objectsEgressObjectsGetter(objs), []gwtypes.Gateway{*gateway}, dataplane)
+		if err != nil {
+			return nil, fmt.Errorf("failed generating network policy egress rules for DataPlane %s backends: %w", dataplane.Name, err)
+		}
+	}
+	policy, err := generateDataPlaneNetworkPolicy(gateway.Namespace, dataplane, controlplane, backends)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating network policy for DataPlane %s: %w", dataplane.Name, err)
+	}
+	k8sutils.SetOwnerForObject(policy, gateway)
+	gatewayutils.LabelObjectAsGatewayManaged(policy)
+	k8sutils.SetNameFromGenerateName(policy)
+	return policy, nil
+}
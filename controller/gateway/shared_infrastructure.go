@@ -0,0 +1,507 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/pkg/consts"
+	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// sharedInfrastructure describes the DataPlane and ControlPlane shared by the
+// Gateways which use a GatewayConfiguration with the
+// consts.AnnotationGatewayConfigurationSharedInfrastructure annotation set.
+type sharedInfrastructure struct {
+	// Group is the value of the consts.GatewaySharedInfrastructureLabel label
+	// set on the shared DataPlane and ControlPlane.
+	Group string
+	// Gateways are the Gateways sharing the infrastructure, the oldest first.
+	Gateways []gwtypes.Gateway
+}
+
+// sharedInfrastructureGroup returns the shared infrastructure group of the
+// Gateways using the provided GatewayConfiguration or an empty string when
+// the GatewayConfiguration doesn't enable shared infrastructure.
+func sharedInfrastructureGroup(gatewayConfig *operatorv1beta1.GatewayConfiguration) string {
+	if gatewayConfig.Annotations[consts.AnnotationGatewayConfigurationSharedInfrastructure] != "true" {
+		return ""
+	}
+	return string(gatewayConfig.UID)
+}
+
+// getSharedInfrastructure returns the shared infrastructure the Gateway is part
+// of or nil when the Gateway's GatewayConfiguration doesn't enable it or when
+// the Gateway's GatewayClass has Gateways which are not part of the group.
+func (r *Reconciler) getSharedInfrastructure(
+	ctx context.Context,
+	gatewayClass *gatewayv1.GatewayClass,
+	gateway *gwtypes.Gateway,
+) (*sharedInfrastructure, error) {
+	gatewayConfig, err := r.getOrCreateGatewayConfiguration(ctx, gatewayClass, gateway)
+	if err != nil {
+		// Invalid references are reported through the Gateway's Accepted condition.
+		if k8serrors.IsNotFound(err) || k8serrors.IsInvalid(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	group := sharedInfrastructureGroup(gatewayConfig)
+	if group == "" {
+		return nil, nil
+	}
+
+	// Gateways of the GatewayClass are listed in all namespaces as the shared
+	// ControlPlane reconciles all of them.
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways); err != nil {
+		return nil, fmt.Errorf("failed listing Gateways: %w", err)
+	}
+
+	// The reconciled Gateway is always part of the group, even when it's not
+	// in the cache yet, and it uses its most recent version.
+	shared := &sharedInfrastructure{Group: group, Gateways: []gwtypes.Gateway{*gateway}}
+	for _, gw := range gateways.Items {
+		if gw.UID == gateway.UID ||
+			!gw.DeletionTimestamp.IsZero() ||
+			gw.Spec.GatewayClassName != gateway.Spec.GatewayClassName {
+			continue
+		}
+		// KIC can only be restricted to a single Gateway or reconcile all the
+		// Gateways of its GatewayClass. The infrastructure is therefore shared
+		// only when the GatewayClass has no Gateways outside of the group,
+		// otherwise each Gateway gets its own DataPlane and ControlPlane.
+		if gw.Namespace != gateway.Namespace || !gatewayUsesGatewayConfig(&gw, gatewayClass, gatewayConfig) {
+			log.Debug(ctrllog.FromContext(ctx), "GatewayClass has Gateways outside of the shared infrastructure group, not sharing infrastructure",
+				"gatewayClass", gatewayClass.Name, "gateway", client.ObjectKeyFromObject(&gw))
+			return nil, nil
+		}
+		shared.Gateways = append(shared.Gateways, gw)
+	}
+	slices.SortFunc(shared.Gateways, func(a, b gwtypes.Gateway) int {
+		if c := a.CreationTimestamp.Compare(b.CreationTimestamp.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return shared, nil
+}
+
+// gatewayUsesGatewayConfig returns true if the provided GatewayConfiguration is
+// used by the Gateway, either through its spec.infrastructure.parametersRef or
+// its GatewayClass' parametersRef.
+func gatewayUsesGatewayConfig(
+	gateway *gwtypes.Gateway,
+	gatewayClass *gatewayv1.GatewayClass,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) bool {
+	if gateway.Spec.Infrastructure != nil && gateway.Spec.Infrastructure.ParametersRef != nil {
+		return gatewayInfrastructureReferencesGatewayConfig(gateway, gatewayConfig)
+	}
+	parametersRef := gatewayClass.Spec.ParametersRef
+	return parametersRef != nil &&
+		parametersRef.Namespace != nil &&
+		string(*parametersRef.Namespace) == gatewayConfig.Namespace &&
+		parametersRef.Name == gatewayConfig.Name
+}
+
+// infrastructureGateway returns the Gateway whose spec (e.g. infrastructure
+// labels and annotations or static addresses) is used to configure the DataPlane
+// and ControlPlane: the oldest of the Gateways sharing the infrastructure or the
+// Gateway itself otherwise.
+func (s *sharedInfrastructure) infrastructureGateway(gateway *gwtypes.Gateway) *gwtypes.Gateway {
+	if s == nil || len(s.Gateways) == 0 {
+		return gateway
+	}
+	return &s.Gateways[0]
+}
+
+// listenerConflict returns the reason of the conflict of the provided Gateway's
+// listener with the listeners of the Gateways sharing the infrastructure which
+// were created before it, together with the conflicting Gateway.
+// It returns an empty reason when there is no conflict.
+func (s *sharedInfrastructure) listenerConflict(
+	gateway *gwtypes.Gateway,
+	listener gatewayv1.Listener,
+) (gatewayv1.ListenerConditionReason, *gwtypes.Gateway) {
+	if s == nil {
+		return "", nil
+	}
+	for i := range s.Gateways {
+		older := &s.Gateways[i]
+		if older.UID == gateway.UID {
+			break
+		}
+		for _, l := range older.Spec.Listeners {
+			if l.Port != listener.Port {
+				continue
+			}
+			if l.Protocol != listener.Protocol {
+				return gatewayv1.ListenerReasonProtocolConflict, older
+			}
+			if (l.Hostname == nil && listener.Hostname == nil) ||
+				(l.Hostname != nil && listener.Hostname != nil && *l.Hostname == *listener.Hostname) {
+				return gatewayv1.ListenerReasonHostnameConflict, older
+			}
+		}
+	}
+	return "", nil
+}
+
+// listeners returns the provided Gateway's listeners or, when the infrastructure
+// is shared, the listeners of all the Gateways sharing it, merged.
+// Listeners conflicting with the listeners of older Gateways are skipped and only
+// a single listener is kept for each port.
+func (s *sharedInfrastructure) listeners(gateway *gwtypes.Gateway) []gatewayv1.Listener {
+	if s == nil {
+		return gateway.Spec.Listeners
+	}
+
+	var (
+		listeners []gatewayv1.Listener
+		ports     = make(map[gatewayv1.PortNumber]struct{})
+		names     = make(map[gatewayv1.SectionName]struct{})
+	)
+	for i := range s.Gateways {
+		gw := &s.Gateways[i]
+		for _, l := range gw.Spec.Listeners {
+			if reason, _ := s.listenerConflict(gw, l); reason != "" {
+				continue
+			}
+			if _, ok := ports[l.Port]; ok {
+				continue
+			}
+			ports[l.Port] = struct{}{}
+			// Listener names are only unique within a single Gateway.
+			if _, ok := names[l.Name]; ok {
+				l.Name = gatewayv1.SectionName(fmt.Sprintf("%s-%d", l.Name, l.Port))
+			}
+			names[l.Name] = struct{}{}
+			listeners = append(listeners, l)
+		}
+	}
+	return listeners
+}
+
+// setSharedInfrastructureConflicted marks the Gateway's listeners conflicting
+// with the listeners of older Gateways sharing the same infrastructure as conflicted.
+func (g *gatewayConditionsAndListenersAwareT) setSharedInfrastructureConflicted(shared *sharedInfrastructure) {
+	if shared == nil {
+		return
+	}
+	for i, l := range g.Spec.Listeners {
+		reason, other := shared.listenerConflict(g.Gateway, l)
+		if reason == "" {
+			continue
+		}
+		// Conflicts between the Gateway's own listeners take precedence.
+		listenerStatus := listenerConditionsAware(&g.Status.Listeners[i])
+		if cond, ok := k8sutils.GetCondition(kcfgconsts.ConditionType(gatewayv1.ListenerConditionConflicted), listenerStatus); ok &&
+			cond.Status == metav1.ConditionTrue {
+			continue
+		}
+		k8sutils.SetCondition(metav1.Condition{
+			Type:   string(gatewayv1.ListenerConditionConflicted),
+			Status: metav1.ConditionTrue,
+			Reason: string(reason),
+			Message: fmt.Sprintf("Listener conflicts with a listener of Gateway %s sharing the same DataPlane.",
+				client.ObjectKeyFromObject(other)),
+			LastTransitionTime: metav1.Now(),
+			ObservedGeneration: g.Generation,
+		}, listenerStatus)
+	}
+}
+
+// setInfrastructureOwner sets the Gateway as the owner of the provided DataPlane
+// or ControlPlane. Shared objects are named after the GatewayConfiguration as
+// they are not specific to a single Gateway.
+func setInfrastructureOwner(
+	obj client.Object,
+	gateway *gwtypes.Gateway,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	shared *sharedInfrastructure,
+) {
+	if shared == nil {
+		k8sutils.SetOwnerForObject(obj, gateway)
+		return
+	}
+	obj.SetGenerateName(k8sutils.TrimGenerateName(fmt.Sprintf("%s-", gatewayConfig.Name)))
+	setSharedOwner(obj, gateway, shared.Group)
+}
+
+// setSharedOwner marks the provided object as shared by the Gateways of the
+// shared infrastructure group, adding the Gateway to its owners.
+// Shared objects have no controller owner and are garbage collected only when
+// all the Gateways owning them are gone.
+func setSharedOwner(obj client.Object, gateway *gwtypes.Gateway, group string) {
+	ownerRefs := slices.DeleteFunc(obj.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+		return ref.UID == gateway.UID
+	})
+	ownerRef := k8sutils.GenerateOwnerReferenceForObject(gateway)
+	ownerRef.Controller = nil
+	obj.SetOwnerReferences(append(ownerRefs, ownerRef))
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[consts.GatewaySharedInfrastructureLabel] = group
+	obj.SetLabels(labels)
+}
+
+// sharedObjectsForGateway returns the object the Gateway should use out of the
+// objects it owns and the objects shared by its shared infrastructure group.
+// Owned objects which don't belong to the group, e.g. because the Gateway
+// joined or left the group, are released. When the infrastructure is shared
+// the oldest object of the group is used and the Gateway is added to its owners.
+func (r *Reconciler) sharedObjectsForGateway(
+	ctx context.Context,
+	gateway *gwtypes.Gateway,
+	shared *sharedInfrastructure,
+	owned []client.Object,
+	groupObjects []client.Object,
+) ([]client.Object, error) {
+	var group string
+	if shared != nil {
+		group = shared.Group
+	}
+
+	var selected client.Object
+	if group != "" {
+		for _, obj := range groupObjects {
+			if !obj.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+			if selected == nil {
+				selected = obj
+				continue
+			}
+			created, selectedCreated := obj.GetCreationTimestamp(), selected.GetCreationTimestamp()
+			if created.Before(&selectedCreated) {
+				selected = obj
+			}
+		}
+	}
+
+	var (
+		objects []client.Object
+		errs    []error
+	)
+	for _, obj := range owned {
+		switch {
+		case group == "" && obj.GetLabels()[consts.GatewaySharedInfrastructureLabel] == "":
+			objects = append(objects, obj)
+		case selected != nil && obj.GetUID() == selected.GetUID():
+			// The Gateway already owns the object it should use.
+		default:
+			if err := r.releaseSharedObject(ctx, obj, gateway); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if selected == nil {
+		return objects, nil
+	}
+	if !k8sutils.IsOwnedByRefUID(selected, gateway.UID) {
+		old := selected.DeepCopyObject().(client.Object)
+		setSharedOwner(selected, gateway, group)
+		// Owner references are patched as a whole list, the optimistic lock prevents
+		// overwriting the owners added or removed concurrently by other Gateways.
+		if err := r.Patch(ctx, selected, client.MergeFromWithOptions(old, client.MergeFromWithOptimisticLock{})); err != nil {
+			return nil, fmt.Errorf("failed adding Gateway %s to the owners of %s: %w",
+				client.ObjectKeyFromObject(gateway), client.ObjectKeyFromObject(selected), err)
+		}
+	}
+	return []client.Object{selected}, nil
+}
+
+// releaseSharedObject removes the Gateway from the owners of the provided object.
+// The object is deleted when the Gateway is its last owner.
+// Both the patch and the deletion fail with a conflict when the object was changed
+// in the meantime (e.g. another Gateway added itself to the owners), in which case
+// the Gateway has to be reconciled again.
+func (r *Reconciler) releaseSharedObject(ctx context.Context, obj client.Object, gateway *gwtypes.Gateway) error {
+	ownerRefs := slices.DeleteFunc(slices.Clone(obj.GetOwnerReferences()), func(ref metav1.OwnerReference) bool {
+		return ref.UID == gateway.UID
+	})
+	if len(ownerRefs) == 0 {
+		uid, resourceVersion := obj.GetUID(), obj.GetResourceVersion()
+		err := r.Delete(ctx, obj, client.Preconditions{UID: &uid, ResourceVersion: &resourceVersion})
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed deleting %s: %w", client.ObjectKeyFromObject(obj), err)
+		}
+		return nil
+	}
+
+	old := obj.DeepCopyObject().(client.Object)
+	obj.SetOwnerReferences(ownerRefs)
+	if err := r.Patch(ctx, obj, client.MergeFromWithOptions(old, client.MergeFromWithOptimisticLock{})); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed removing Gateway %s from the owners of %s: %w",
+			client.ObjectKeyFromObject(gateway), client.ObjectKeyFromObject(obj), err)
+	}
+	return nil
+}
+
+// deleteOrReleaseOwnedObject deletes the provided object owned by the Gateway.
+// Objects shared with other Gateways are only released by the Gateway so that
+// they are deleted together with the last Gateway using them.
+func (r *Reconciler) deleteOrReleaseOwnedObject(ctx context.Context, obj client.Object, gateway *gwtypes.Gateway) error {
+	if obj.GetLabels()[consts.GatewaySharedInfrastructureLabel] != "" {
+		return r.releaseSharedObject(ctx, obj, gateway)
+	}
+	if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	return nil
+}
+
+// listDataPlanesForGateway returns the DataPlanes the Gateway should use,
+// taking into account the shared infrastructure the Gateway is part of.
+func (r *Reconciler) listDataPlanesForGateway(
+	ctx context.Context,
+	gateway *gwtypes.Gateway,
+	shared *sharedInfrastructure,
+) ([]operatorv1beta1.DataPlane, error) {
+	owned, err := gatewayutils.ListDataPlanesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return nil, err
+	}
+	var groupDataPlanes operatorv1beta1.DataPlaneList
+	if shared != nil {
+		if err := r.List(ctx, &groupDataPlanes,
+			client.InNamespace(gateway.Namespace),
+			client.MatchingLabels{
+				consts.GatewayOperatorManagedByLabel:    consts.GatewayManagedLabelValue,
+				consts.GatewaySharedInfrastructureLabel: shared.Group,
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	objects, err := r.sharedObjectsForGateway(ctx, gateway, shared,
+		toObjects(owned), toObjects(groupDataPlanes.Items),
+	)
+	if err != nil {
+		return nil, err
+	}
+	dataplanes := make([]operatorv1beta1.DataPlane, 0, len(objects))
+	for _, obj := range objects {
+		dataplanes = append(dataplanes, *obj.(*operatorv1beta1.DataPlane))
+	}
+	return dataplanes, nil
+}
+
+// listControlPlanesForGateway returns the ControlPlanes the Gateway should use,
+// taking into account the shared infrastructure the Gateway is part of.
+func (r *Reconciler) listControlPlanesForGateway(
+	ctx context.Context,
+	gateway *gwtypes.Gateway,
+	shared *sharedInfrastructure,
+) ([]operatorv1beta1.ControlPlane, error) {
+	owned, err := gatewayutils.ListControlPlanesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return nil, err
+	}
+	var groupControlPlanes operatorv1beta1.ControlPlaneList
+	if shared != nil {
+		if err := r.List(ctx, &groupControlPlanes,
+			client.InNamespace(gateway.Namespace),
+			client.MatchingLabels{
+				consts.GatewayOperatorManagedByLabel:    consts.GatewayManagedLabelValue,
+				consts.GatewaySharedInfrastructureLabel: shared.Group,
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	objects, err := r.sharedObjectsForGateway(ctx, gateway, shared,
+		toObjects(owned), toObjects(groupControlPlanes.Items),
+	)
+	if err != nil {
+		return nil, err
+	}
+	controlplanes := make([]operatorv1beta1.ControlPlane, 0, len(objects))
+	for _, obj := range objects {
+		controlplanes = append(controlplanes, *obj.(*operatorv1beta1.ControlPlane))
+	}
+	return controlplanes, nil
+}
+
+// toObjects returns pointers to the provided items as client.Objects.
+func toObjects[T any, PT interface {
+	*T
+	client.Object
+}](items []T) []client.Object {
+	objects := make([]client.Object, 0, len(items))
+	for i := range items {
+		objects = append(objects, PT(&items[i]))
+	}
+	return objects
+}
+
+// listGatewaysSharingInfrastructure is a watch map func which enqueues the
+// Gateways sharing DataPlanes with the Gateway that changed, so that their
+// listeners' conflicts are reevaluated, and the other Gateways of its
+// GatewayClass, as whether they can share infrastructure depends on all the
+// Gateways of the GatewayClass.
+func (r *Reconciler) listGatewaysSharingInfrastructure(ctx context.Context, obj client.Object) []reconcile.Request {
+	gateway, ok := obj.(*gwtypes.Gateway)
+	if !ok {
+		return nil
+	}
+	dataplanes, err := gatewayutils.ListDataPlanesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return nil
+	}
+
+	recs := make(map[types.NamespacedName]struct{})
+	for _, dataplane := range dataplanes {
+		if dataplane.Labels[consts.GatewaySharedInfrastructureLabel] == "" {
+			continue
+		}
+		for _, ownerRef := range dataplane.OwnerReferences {
+			if ownerRef.Kind != "Gateway" || ownerRef.UID == gateway.UID {
+				continue
+			}
+			recs[types.NamespacedName{Namespace: gateway.Namespace, Name: ownerRef.Name}] = struct{}{}
+		}
+	}
+
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways); err != nil {
+		return nil
+	}
+	for _, gw := range gateways.Items {
+		if gw.UID == gateway.UID || gw.Spec.GatewayClassName != gateway.Spec.GatewayClassName {
+			continue
+		}
+		recs[client.ObjectKeyFromObject(&gw)] = struct{}{}
+	}
+
+	return lo.MapToSlice(recs, func(nn types.NamespacedName, _ struct{}) reconcile.Request {
+		return reconcile.Request{NamespacedName: nn}
+	})
+}
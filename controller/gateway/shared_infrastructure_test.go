@@ -0,0 +1,371 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func sharedInfrastructureTestGateway(name string, created time.Time, listeners ...gatewayv1.Listener) gwtypes.Gateway {
+	return gwtypes.Gateway{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gatewayv1.GroupVersion.String(),
+			Kind:       "Gateway",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              name,
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "kong",
+			Listeners:        listeners,
+		},
+	}
+}
+
+func TestGetSharedInfrastructure(t *testing.T) {
+	now := time.Now()
+	gatewayClass := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kong",
+		},
+		Spec: gatewayv1.GatewayClassSpec{
+			ParametersRef: &gatewayv1.ParametersReference{
+				Group:     gatewayv1.Group(operatorv1beta1.SchemeGroupVersion.Group),
+				Kind:      "GatewayConfiguration",
+				Namespace: lo.ToPtr(gatewayv1.Namespace("default")),
+				Name:      "shared",
+			},
+		},
+	}
+	gatewayConfig := &operatorv1beta1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "shared",
+			UID:       "shared-uid",
+			Annotations: map[string]string{
+				consts.AnnotationGatewayConfigurationSharedInfrastructure: "true",
+			},
+		},
+	}
+
+	first := sharedInfrastructureTestGateway("first", now)
+	second := sharedInfrastructureTestGateway("second", now.Add(time.Minute))
+	otherClass := sharedInfrastructureTestGateway("other-class", now)
+	otherClass.Spec.GatewayClassName = "other"
+	deleting := sharedInfrastructureTestGateway("deleting", now)
+	deleting.DeletionTimestamp = lo.ToPtr(metav1.Now())
+	deleting.Finalizers = []string{"test"}
+
+	t.Run("shared", func(t *testing.T) {
+		r := &Reconciler{
+			Client: fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(gatewayConfig, &first, &second, &otherClass, &deleting).
+				Build(),
+		}
+
+		shared, err := r.getSharedInfrastructure(t.Context(), gatewayClass, &second)
+		require.NoError(t, err)
+		require.NotNil(t, shared)
+		require.Equal(t, "shared-uid", shared.Group)
+		require.Equal(t, []string{"first", "second"}, lo.Map(shared.Gateways, func(g gwtypes.Gateway, _ int) string {
+			return g.Name
+		}))
+		require.Equal(t, "first", shared.infrastructureGateway(&second).Name)
+	})
+
+	t.Run("GatewayClass with other groups and non-shared Gateways", func(t *testing.T) {
+		withInfrastructure := func(gw gwtypes.Gateway, namespace, gatewayConfigName string) gwtypes.Gateway {
+			gw.Namespace = namespace
+			gw.Spec.Infrastructure = &gatewayv1.GatewayInfrastructure{
+				ParametersRef: &gatewayv1.LocalParametersReference{
+					Group: gatewayv1.Group(operatorv1beta1.SchemeGroupVersion.Group),
+					Kind:  "GatewayConfiguration",
+					Name:  gatewayConfigName,
+				},
+			}
+			return gw
+		}
+		otherGroupConfig := gatewayConfig.DeepCopy()
+		otherGroupConfig.Namespace = "other"
+		otherGroupConfig.Name = "other-shared"
+		otherGroupConfig.UID = "other-shared-uid"
+		notSharedConfig := gatewayConfig.DeepCopy()
+		notSharedConfig.Name = "not-shared"
+		notSharedConfig.UID = "not-shared-uid"
+		notSharedConfig.Annotations = nil
+
+		otherGroupFirst := withInfrastructure(sharedInfrastructureTestGateway("other-group-first", now), "other", "other-shared")
+		otherGroupSecond := withInfrastructure(sharedInfrastructureTestGateway("other-group-second", now), "other", "other-shared")
+		notShared := withInfrastructure(sharedInfrastructureTestGateway("not-shared", now), "default", "not-shared")
+
+		r := &Reconciler{
+			Client: fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(gatewayConfig, otherGroupConfig, notSharedConfig,
+					&first, &second, &otherGroupFirst, &otherGroupSecond, &notShared).
+				Build(),
+		}
+
+		// The ControlPlane of each group would reconcile the Gateways of the
+		// other group and the non-shared Gateway, so none of them is shared.
+		for _, gw := range []gwtypes.Gateway{first, second, otherGroupFirst, otherGroupSecond, notShared} {
+			shared, err := r.getSharedInfrastructure(t.Context(), gatewayClass, &gw)
+			require.NoError(t, err)
+			require.Nil(t, shared, "Gateway %s shouldn't share infrastructure", gw.Name)
+		}
+
+		t.Log("removing the other group and the non-shared Gateway lets the remaining group share infrastructure")
+		for _, gw := range []gwtypes.Gateway{otherGroupFirst, otherGroupSecond, notShared} {
+			require.NoError(t, r.Delete(t.Context(), &gw))
+		}
+		shared, err := r.getSharedInfrastructure(t.Context(), gatewayClass, &second)
+		require.NoError(t, err)
+		require.NotNil(t, shared)
+		require.Equal(t, []string{"first", "second"}, lo.Map(shared.Gateways, func(g gwtypes.Gateway, _ int) string {
+			return g.Name
+		}))
+	})
+
+	t.Run("watch enqueues the Gateways of the GatewayClass", func(t *testing.T) {
+		otherNamespace := sharedInfrastructureTestGateway("other-namespace", now)
+		otherNamespace.Namespace = "other"
+		r := &Reconciler{
+			Client: fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(gatewayConfig, &first, &second, &otherClass, &otherNamespace).
+				Build(),
+		}
+
+		recs := r.listGatewaysSharingInfrastructure(t.Context(), &otherNamespace)
+		require.ElementsMatch(t, []string{"default/first", "default/second"}, lo.Map(recs, func(rec reconcile.Request, _ int) string {
+			return rec.String()
+		}))
+	})
+
+	t.Run("not shared", func(t *testing.T) {
+		notShared := gatewayConfig.DeepCopy()
+		notShared.Annotations = nil
+		r := &Reconciler{
+			Client: fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(notShared, &first, &second).
+				Build(),
+		}
+
+		shared, err := r.getSharedInfrastructure(t.Context(), gatewayClass, &second)
+		require.NoError(t, err)
+		require.Nil(t, shared)
+		require.Equal(t, "second", shared.infrastructureGateway(&second).Name)
+		require.Equal(t, second.Spec.Listeners, shared.listeners(&second))
+	})
+
+	t.Run("missing GatewayConfiguration", func(t *testing.T) {
+		r := &Reconciler{
+			Client: fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(&first).
+				Build(),
+		}
+
+		shared, err := r.getSharedInfrastructure(t.Context(), gatewayClass, &first)
+		require.NoError(t, err)
+		require.Nil(t, shared)
+	})
+}
+
+func TestSharedInfrastructureListeners(t *testing.T) {
+	now := time.Now()
+	first := sharedInfrastructureTestGateway("first", now,
+		gatewayv1.Listener{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType, Hostname: lo.ToPtr(gatewayv1.Hostname("first.example.com"))},
+	)
+	second := sharedInfrastructureTestGateway("second", now.Add(time.Minute),
+		gatewayv1.Listener{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType, Hostname: lo.ToPtr(gatewayv1.Hostname("second.example.com"))},
+		gatewayv1.Listener{Name: "https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+		gatewayv1.Listener{Name: "tcp", Port: 80, Protocol: gatewayv1.TCPProtocolType},
+	)
+	third := sharedInfrastructureTestGateway("third", now.Add(2*time.Minute),
+		gatewayv1.Listener{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType, Hostname: lo.ToPtr(gatewayv1.Hostname("first.example.com"))},
+		gatewayv1.Listener{Name: "https", Port: 8443, Protocol: gatewayv1.HTTPSProtocolType},
+	)
+	shared := &sharedInfrastructure{
+		Group:    "group",
+		Gateways: []gwtypes.Gateway{first, second, third},
+	}
+
+	t.Run("conflicts", func(t *testing.T) {
+		reason, other := shared.listenerConflict(&first, first.Spec.Listeners[0])
+		require.Empty(t, reason)
+		require.Nil(t, other)
+
+		reason, _ = shared.listenerConflict(&second, second.Spec.Listeners[0])
+		require.Empty(t, reason, "listeners on the same port with different hostnames don't conflict")
+
+		reason, other = shared.listenerConflict(&second, second.Spec.Listeners[2])
+		require.Equal(t, gatewayv1.ListenerReasonProtocolConflict, reason)
+		require.Equal(t, "first", other.Name)
+
+		reason, other = shared.listenerConflict(&third, third.Spec.Listeners[0])
+		require.Equal(t, gatewayv1.ListenerReasonHostnameConflict, reason)
+		require.Equal(t, "first", other.Name)
+	})
+
+	t.Run("merged listeners", func(t *testing.T) {
+		listeners := shared.listeners(&third)
+		require.Equal(t,
+			[]gatewayv1.SectionName{"http", "https", "https-8443"},
+			lo.Map(listeners, func(l gatewayv1.Listener, _ int) gatewayv1.SectionName { return l.Name }),
+		)
+		require.Equal(t,
+			[]gatewayv1.PortNumber{80, 443, 8443},
+			lo.Map(listeners, func(l gatewayv1.Listener, _ int) gatewayv1.PortNumber { return l.Port }),
+		)
+	})
+
+	t.Run("conflicted condition", func(t *testing.T) {
+		gateway := third.DeepCopy()
+		gwConditionAware := gatewayConditionsAndListenersAware(gateway)
+		gwConditionAware.initListenersStatus()
+		gwConditionAware.setConflicted()
+		gwConditionAware.setSharedInfrastructureConflicted(shared)
+
+		cond, ok := k8sutils.GetCondition(kcfgconsts.ConditionType(gatewayv1.ListenerConditionConflicted), listenerConditionsAware(&gateway.Status.Listeners[0]))
+		require.True(t, ok)
+		require.Equal(t, metav1.ConditionTrue, cond.Status)
+		require.Equal(t, string(gatewayv1.ListenerReasonHostnameConflict), cond.Reason)
+		require.Contains(t, cond.Message, "default/first")
+
+		cond, ok = k8sutils.GetCondition(kcfgconsts.ConditionType(gatewayv1.ListenerConditionConflicted), listenerConditionsAware(&gateway.Status.Listeners[1]))
+		require.True(t, ok)
+		require.Equal(t, metav1.ConditionFalse, cond.Status)
+	})
+}
+
+func TestSharedDataPlaneOwnership(t *testing.T) {
+	now := time.Now()
+	first := sharedInfrastructureTestGateway("first", now)
+	second := sharedInfrastructureTestGateway("second", now.Add(time.Minute))
+	shared := &sharedInfrastructure{
+		Group:    "group",
+		Gateways: []gwtypes.Gateway{first, second},
+	}
+
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "shared",
+			UID:       "shared-dataplane",
+			Labels: map[string]string{
+				consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue,
+			},
+		},
+	}
+	setSharedOwner(dataplane, &first, shared.Group)
+	require.Nil(t, dataplane.OwnerReferences[0].Controller, "shared objects have no controller owner")
+
+	// A DataPlane the second Gateway owned before joining the group.
+	ownDataPlane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "own",
+			UID:       "own-dataplane",
+			Labels: map[string]string{
+				consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue,
+			},
+		},
+	}
+	k8sutils.SetOwnerForObject(ownDataPlane, &second)
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(dataplane, ownDataPlane).
+		Build()
+	r := &Reconciler{Client: cl}
+
+	dataplanes, err := r.listDataPlanesForGateway(t.Context(), &second, shared)
+	require.NoError(t, err)
+	require.Len(t, dataplanes, 1)
+	require.Equal(t, "shared", dataplanes[0].Name)
+
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), dataplane))
+	require.True(t, k8sutils.IsOwnedByRefUID(dataplane, first.UID))
+	require.True(t, k8sutils.IsOwnedByRefUID(dataplane, second.UID))
+	require.Error(t, cl.Get(t.Context(), client.ObjectKeyFromObject(ownDataPlane), ownDataPlane),
+		"the DataPlane owned before joining the group should be deleted")
+
+	// The DataPlane is kept until the last Gateway owning it is deleted.
+	deleted, err := r.ensureOwnedDataPlanesDeleted(t.Context(), &first)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), dataplane))
+	require.False(t, k8sutils.IsOwnedByRefUID(dataplane, first.UID))
+
+	deleted, err = r.ensureOwnedDataPlanesDeleted(t.Context(), &second)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	require.Error(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), dataplane))
+}
+
+func TestReleaseSharedObjectConflict(t *testing.T) {
+	now := time.Now()
+	first := sharedInfrastructureTestGateway("first", now)
+	second := sharedInfrastructureTestGateway("second", now.Add(time.Minute))
+
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "shared",
+			UID:       "shared-dataplane",
+			Labels: map[string]string{
+				consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue,
+			},
+		},
+	}
+	setSharedOwner(dataplane, &first, "group")
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(dataplane).
+		Build()
+	r := &Reconciler{Client: cl}
+
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), dataplane))
+	stale := dataplane.DeepCopy()
+
+	// The second Gateway joins the group in the meantime.
+	old := dataplane.DeepCopy()
+	setSharedOwner(dataplane, &second, "group")
+	require.NoError(t, cl.Patch(t.Context(), dataplane, client.MergeFrom(old)))
+
+	// Releasing the stale object must neither delete the DataPlane
+	// nor drop the second Gateway from its owners.
+	err := r.releaseSharedObject(t.Context(), stale, &first)
+	require.True(t, k8serrors.IsConflict(err), "expected a conflict, got %v", err)
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), dataplane))
+	require.True(t, k8sutils.IsOwnedByRefUID(dataplane, second.UID))
+
+	// With an up to date object the first Gateway is released.
+	require.NoError(t, r.releaseSharedObject(t.Context(), dataplane, &first))
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), dataplane))
+	require.False(t, k8sutils.IsOwnedByRefUID(dataplane, first.UID))
+	require.True(t, k8sutils.IsOwnedByRefUID(dataplane, second.UID))
+}
@@ -0,0 +1,402 @@
+package gateway
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/pkg/consts"
+	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	kcfgdataplane "github.com/kong/kubernetes-configuration/api/gateway-operator/dataplane"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+const (
+	// stagedRolloutConditionType is the type of the GatewayConfiguration condition
+	// reporting the progress of its staged rollout.
+	stagedRolloutConditionType kcfgconsts.ConditionType = "RolledOut"
+
+	// stagedRolloutReasonWaveInProgress is the reason used while a wave of
+	// Gateways is being rolled out.
+	stagedRolloutReasonWaveInProgress kcfgconsts.ConditionReason = "WaveInProgress"
+	// stagedRolloutReasonPaused is the reason used when the staged rollout is paused.
+	stagedRolloutReasonPaused kcfgconsts.ConditionReason = "Paused"
+	// stagedRolloutReasonCompleted is the reason used when all the Gateways
+	// have been rolled out.
+	stagedRolloutReasonCompleted kcfgconsts.ConditionReason = "Completed"
+	// stagedRolloutReasonInvalidConfiguration is the reason used when the staged
+	// rollout annotations can't be parsed.
+	stagedRolloutReasonInvalidConfiguration kcfgconsts.ConditionReason = "InvalidConfiguration"
+
+	// defaultStagedRolloutWaveTimeout is the default time the Gateways of a wave
+	// have to be rolled out before the staged rollout is paused.
+	defaultStagedRolloutWaveTimeout = 10 * time.Minute
+	// stagedRolloutCheckInterval is the interval in which the progress of
+	// a wave is checked.
+	stagedRolloutCheckInterval = 10 * time.Second
+)
+
+// stagedRolloutWave is a wave of a staged rollout, selecting Gateways either
+// with a label selector, by their number or by their percentage.
+type stagedRolloutWave struct {
+	selector labels.Selector
+	count    int
+	percent  int
+}
+
+// parseStagedRolloutWaves parses the waves of the consts.AnnotationStagedRolloutWaves
+// annotation, e.g. "env=canary;2;25%".
+func parseStagedRolloutWaves(s string) ([]stagedRolloutWave, error) {
+	var waves []stagedRolloutWave
+	for w := range strings.SplitSeq(s, ";") {
+		w = strings.TrimSpace(w)
+		switch {
+		case w == "":
+			return nil, fmt.Errorf("invalid staged rollout waves %q: empty wave", s)
+
+		case strings.HasSuffix(w, "%"):
+			percent, err := strconv.Atoi(strings.TrimSuffix(w, "%"))
+			if err != nil || percent < 1 || percent > 100 {
+				return nil, fmt.Errorf("invalid staged rollout wave %q: percentage must be between 1%% and 100%%", w)
+			}
+			waves = append(waves, stagedRolloutWave{percent: percent})
+
+		default:
+			if count, err := strconv.Atoi(w); err == nil {
+				if count < 1 {
+					return nil, fmt.Errorf("invalid staged rollout wave %q: number of Gateways must be positive", w)
+				}
+				waves = append(waves, stagedRolloutWave{count: count})
+				continue
+			}
+			selector, err := labels.Parse(w)
+			if err != nil {
+				return nil, fmt.Errorf("invalid staged rollout wave %q: %w", w, err)
+			}
+			waves = append(waves, stagedRolloutWave{selector: selector})
+		}
+	}
+	return waves, nil
+}
+
+// assignStagedRolloutWaves assigns the provided Gateways to the provided waves,
+// in the order of their namespaces and names. Gateways which are not part of
+// any wave are assigned to a final wave, and empty waves are dropped.
+// Gateways sharing DataPlanes, grouped by the provided groups, are assigned to
+// the wave of the first of them so that their DataPlanes are provisioned with
+// a single revision.
+func assignStagedRolloutWaves(waves []stagedRolloutWave, gateways []gwtypes.Gateway, groups map[types.UID]types.UID) [][]gwtypes.Gateway {
+	remaining := slices.Clone(gateways)
+	slices.SortFunc(remaining, func(a, b gwtypes.Gateway) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	// take moves the selected Gateways, together with the Gateways sharing
+	// DataPlanes with them, from the remaining ones to the returned ones.
+	take := func(selected func(int, gwtypes.Gateway) bool) []gwtypes.Gateway {
+		selectedGroups := make(map[types.UID]struct{})
+		for i, gateway := range remaining {
+			if group, ok := groups[gateway.UID]; ok && selected(i, gateway) {
+				selectedGroups[group] = struct{}{}
+			}
+		}
+		var members, rest []gwtypes.Gateway
+		for i, gateway := range remaining {
+			group, ok := groups[gateway.UID]
+			_, inSelectedGroup := selectedGroups[group]
+			if selected(i, gateway) || (ok && inSelectedGroup) {
+				members = append(members, gateway)
+			} else {
+				rest = append(rest, gateway)
+			}
+		}
+		remaining = rest
+		return members
+	}
+
+	var assigned [][]gwtypes.Gateway
+	for _, wave := range waves {
+		var members []gwtypes.Gateway
+		switch {
+		case wave.selector != nil:
+			members = take(func(_ int, gateway gwtypes.Gateway) bool {
+				return wave.selector.Matches(labels.Set(gateway.Labels))
+			})
+		default:
+			size := wave.count
+			if wave.percent > 0 {
+				size = int(math.Ceil(float64(wave.percent*len(gateways)) / 100))
+			}
+			members = take(func(i int, _ gwtypes.Gateway) bool {
+				return i < size
+			})
+		}
+		if len(members) > 0 {
+			assigned = append(assigned, members)
+		}
+	}
+	if len(remaining) > 0 {
+		assigned = append(assigned, remaining)
+	}
+	return assigned
+}
+
+// dataPlaneSharingGroups groups the provided Gateways which share DataPlanes,
+// directly or through other Gateways. It returns the group of each Gateway
+// sharing DataPlanes, identified by the UID of one of its Gateways.
+func dataPlaneSharingGroups(ctx context.Context, cl client.Client, gateways []gwtypes.Gateway) (map[types.UID]types.UID, error) {
+	parents := make(map[types.UID]types.UID, len(gateways))
+	for _, gateway := range gateways {
+		parents[gateway.UID] = gateway.UID
+	}
+	find := func(uid types.UID) types.UID {
+		for parents[uid] != uid {
+			uid = parents[uid]
+		}
+		return uid
+	}
+
+	shared := make(map[types.UID]struct{})
+	for namespace := range lo.SliceToMap(gateways, func(gateway gwtypes.Gateway) (string, struct{}) {
+		return gateway.Namespace, struct{}{}
+	}) {
+		var dataplanes operatorv1beta1.DataPlaneList
+		if err := cl.List(ctx, &dataplanes,
+			client.InNamespace(namespace),
+			client.MatchingLabels{consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue},
+		); err != nil {
+			return nil, fmt.Errorf("failed listing DataPlanes: %w", err)
+		}
+		for _, dataplane := range dataplanes.Items {
+			owners := lo.FilterMap(dataplane.OwnerReferences, func(ownerRef metav1.OwnerReference, _ int) (types.UID, bool) {
+				_, ok := parents[ownerRef.UID]
+				return ownerRef.UID, ok && ownerRef.Kind == "Gateway"
+			})
+			for _, owner := range owners[min(1, len(owners)):] {
+				parents[find(owner)] = find(owners[0])
+				shared[owner] = struct{}{}
+				shared[owners[0]] = struct{}{}
+			}
+		}
+	}
+
+	groups := make(map[types.UID]types.UID, len(shared))
+	for uid := range shared {
+		groups[uid] = find(uid)
+	}
+	return groups, nil
+}
+
+// stagedRolloutWaveTimeout returns the wave timeout configured on the
+// GatewayConfiguration with the consts.AnnotationStagedRolloutWaveTimeout annotation.
+func stagedRolloutWaveTimeout(gatewayConfig *operatorv1beta1.GatewayConfiguration) (time.Duration, error) {
+	value, ok := gatewayConfig.Annotations[consts.AnnotationStagedRolloutWaveTimeout]
+	if !ok {
+		return defaultStagedRolloutWaveTimeout, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid %s annotation %q: must be a positive duration", consts.AnnotationStagedRolloutWaveTimeout, value)
+	}
+	return timeout, nil
+}
+
+// -----------------------------------------------------------------------------
+// Staged rollouts - GatewayConfiguration revisions
+// -----------------------------------------------------------------------------
+
+// gatewayConfigRevisionName returns the name of the ControllerRevision holding
+// the current spec of the provided GatewayConfiguration.
+func gatewayConfigRevisionName(gatewayConfig *operatorv1beta1.GatewayConfiguration) (string, error) {
+	hash, err := k8sresources.CalculateHash(gatewayConfig.Spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate hash of GatewayConfiguration %s spec: %w", gatewayConfig.Name, err)
+	}
+	return gatewayConfig.Name + "-" + hash, nil
+}
+
+// listGatewayConfigRevisions lists the ControllerRevisions owned by the
+// provided GatewayConfiguration.
+func listGatewayConfigRevisions(
+	ctx context.Context,
+	cl client.Client,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) ([]appsv1.ControllerRevision, error) {
+	revisionList := &appsv1.ControllerRevisionList{}
+	if err := cl.List(ctx, revisionList, client.InNamespace(gatewayConfig.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed listing ControllerRevisions: %w", err)
+	}
+	return slices.DeleteFunc(revisionList.Items, func(revision appsv1.ControllerRevision) bool {
+		return !k8sutils.IsOwnedByRefUID(&revision, gatewayConfig.UID)
+	}), nil
+}
+
+// ensureGatewayConfigRevision ensures that a ControllerRevision holding the
+// current spec of the provided GatewayConfiguration exists and returns its name.
+func ensureGatewayConfigRevision(
+	ctx context.Context,
+	cl client.Client,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) (string, error) {
+	name, err := gatewayConfigRevisionName(gatewayConfig)
+	if err != nil {
+		return "", err
+	}
+	revisions, err := listGatewayConfigRevisions(ctx, cl, gatewayConfig)
+	if err != nil {
+		return "", err
+	}
+	if slices.ContainsFunc(revisions, func(revision appsv1.ControllerRevision) bool {
+		return revision.Name == name
+	}) {
+		return name, nil
+	}
+
+	data, err := json.Marshal(gatewayConfig.Spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal GatewayConfiguration %s spec: %w", gatewayConfig.Name, err)
+	}
+	revision := &appsv1.ControllerRevision{}
+	revision.Name = name
+	revision.Namespace = gatewayConfig.Namespace
+	revision.Data = runtime.RawExtension{Raw: data}
+	for _, r := range revisions {
+		revision.Revision = max(revision.Revision, r.Revision)
+	}
+	revision.Revision++
+	if err := controllerutil.SetControllerReference(gatewayConfig, revision, cl.Scheme()); err != nil {
+		return "", err
+	}
+	if err := cl.Create(ctx, revision); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed creating ControllerRevision %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// errGatewayConfigRevisionNotFound is returned when the GatewayConfiguration
+// revision a Gateway is pinned to doesn't exist.
+var errGatewayConfigRevisionNotFound = errors.New("GatewayConfiguration revision not found")
+
+// gatewayConfigForGatewayRevision returns the GatewayConfiguration to provision
+// the provided Gateway with. During staged rollouts, Gateways are pinned to
+// a revision of the GatewayConfiguration spec with the
+// consts.AnnotationGatewayConfigurationRevision annotation, in which case
+// a copy of the GatewayConfiguration with the revision's spec is returned.
+// Revisions only hold the spec so the copy keeps the current annotations.
+// It returns an error wrapping errGatewayConfigRevisionNotFound when the
+// revision the Gateway is pinned to doesn't exist, e.g. when it's not in the
+// cache yet, rather than provisioning the Gateway with the current spec.
+// The revision annotation is then propagated to the Gateway's DataPlane and
+// ControlPlane to report which revision they use.
+func gatewayConfigForGatewayRevision(
+	ctx context.Context,
+	cl client.Client,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	gateway *gwtypes.Gateway,
+) (*operatorv1beta1.GatewayConfiguration, error) {
+	if _, ok := gatewayConfig.Annotations[consts.AnnotationStagedRolloutWaves]; !ok {
+		return gatewayConfig, nil
+	}
+	// Gateways which are not pinned yet use the current spec.
+	name, ok := gateway.Annotations[consts.AnnotationGatewayConfigurationRevision]
+	if !ok {
+		return gatewayConfig, nil
+	}
+
+	revision := &appsv1.ControllerRevision{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: gatewayConfig.Namespace, Name: name}, revision); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", errGatewayConfigRevisionNotFound, name)
+		}
+		return nil, fmt.Errorf("failed getting ControllerRevision %s: %w", name, err)
+	}
+	// The Gateway might have been pinned to a revision of another GatewayConfiguration.
+	if !k8sutils.IsOwnedByRefUID(revision, gatewayConfig.UID) {
+		return gatewayConfig, nil
+	}
+
+	pinned := gatewayConfig.DeepCopy()
+	pinned.Spec = operatorv1beta1.GatewayConfigurationSpec{}
+	if err := json.Unmarshal(revision.Data.Raw, &pinned.Spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ControllerRevision %s: %w", name, err)
+	}
+	if pinned.Annotations == nil {
+		pinned.Annotations = make(map[string]string)
+	}
+	pinned.Annotations[consts.AnnotationGatewayConfigurationRevision] = name
+	return pinned, nil
+}
+
+// -----------------------------------------------------------------------------
+// Staged rollouts - Gateways status
+// -----------------------------------------------------------------------------
+
+// errGatewayRolloutFailed is returned when a Gateway failed to roll out a revision.
+var errGatewayRolloutFailed = errors.New("rollout failed")
+
+// gatewayRolledOut returns true when the provided Gateway is Programmed and
+// its DataPlanes use the provided revision of its GatewayConfiguration and are
+// Ready, or RolledOut when they use the BlueGreen rollout strategy.
+// It returns an error wrapping errGatewayRolloutFailed when the rollout failed.
+func gatewayRolledOut(ctx context.Context, cl client.Client, gateway *gwtypes.Gateway, revision string) (bool, error) {
+	programmed := meta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+	if programmed == nil || programmed.Status != metav1.ConditionTrue || programmed.ObservedGeneration != gateway.Generation {
+		return false, nil
+	}
+
+	dataplanes, err := gatewayutils.ListDataPlanesForGateway(ctx, cl, gateway)
+	if err != nil {
+		return false, err
+	}
+	for _, dataplane := range dataplanes {
+		if dataplane.Annotations[consts.AnnotationGatewayConfigurationRevision] != revision {
+			return false, nil
+		}
+
+		if dataplane.Spec.Deployment.Rollout == nil || dataplane.Spec.Deployment.Rollout.Strategy.BlueGreen == nil {
+			if ready, ok := k8sutils.GetCondition(kcfgdataplane.ReadyType, &dataplane); !ok ||
+				ready.Status != metav1.ConditionTrue || ready.ObservedGeneration != dataplane.Generation {
+				return false, nil
+			}
+			continue
+		}
+
+		rolledOut, ok := k8sutils.GetCondition(kcfgdataplane.DataPlaneConditionTypeRolledOut, dataplane.Status.RolloutStatus)
+		if !ok || rolledOut.ObservedGeneration != dataplane.Generation {
+			return false, nil
+		}
+		switch kcfgconsts.ConditionReason(rolledOut.Reason) {
+		case kcfgdataplane.DataPlaneConditionReasonRolloutFailed, kcfgdataplane.DataPlaneConditionReasonRolloutPromotionFailed:
+			return false, fmt.Errorf("%w: DataPlane %s: %s", errGatewayRolloutFailed, dataplane.Name, rolledOut.Message)
+		case kcfgdataplane.DataPlaneConditionReasonRolloutPromotionDone, kcfgdataplane.DataPlaneConditionReasonRolloutWaitingForChange:
+			// The preview Deployment has been promoted.
+		default:
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,418 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// StagedRolloutReconciler
+// -----------------------------------------------------------------------------
+
+// StagedRolloutReconciler reconciles GatewayConfigurations configured with the
+// consts.AnnotationStagedRolloutWaves annotation, rolling out changes to their
+// spec to the Gateways using them in waves. Each spec is stored in a
+// ControllerRevision and Gateways are pinned to the revision they have to be
+// provisioned with, which the Gateway Reconciler honors.
+type StagedRolloutReconciler struct {
+	client.Client
+	LoggingMode logging.Mode
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StagedRolloutReconciler) SetupWithManager(_ context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("gatewayconfiguration_staged_rollout").
+		For(&operatorv1beta1.GatewayConfiguration{}).
+		Owns(&appsv1.ControllerRevision{}).
+		// watch Gateways so that waves progress as soon as their Gateways are Programmed.
+		Watches(
+			&gwtypes.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewayConfigsForGateway)).
+		Complete(r)
+}
+
+// Reconcile moves the current state of an object to the intended state.
+func (r *StagedRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.GetLogger(ctx, "gatewayconfiguration_staged_rollout", r.LoggingMode)
+
+	log.Trace(logger, "reconciling GatewayConfiguration staged rollout")
+	var gatewayConfig operatorv1beta1.GatewayConfiguration
+	if err := r.Get(ctx, req.NamespacedName, &gatewayConfig); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	gateways, err := r.listStagedRolloutGateways(ctx, &gatewayConfig)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	wavesAnnotation, ok := gatewayConfig.Annotations[consts.AnnotationStagedRolloutWaves]
+	if !ok {
+		log.Trace(logger, "staged rollout not enabled, ensuring Gateways are not pinned to revisions")
+		return ctrl.Result{}, r.disableStagedRollout(ctx, &gatewayConfig, gateways)
+	}
+
+	waves, err := parseStagedRolloutWaves(wavesAnnotation)
+	var timeout time.Duration
+	if err == nil {
+		timeout, err = stagedRolloutWaveTimeout(&gatewayConfig)
+	}
+	if err != nil {
+		log.Debug(logger, "invalid staged rollout configuration", "error", err)
+		return patch.StatusWithCondition(ctx, r.Client, &gatewayConfig,
+			stagedRolloutConditionType, metav1.ConditionFalse, stagedRolloutReasonInvalidConfiguration, err.Error(),
+		)
+	}
+
+	revision, err := ensureGatewayConfigRevision(ctx, r.Client, &gatewayConfig)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now()
+	// Gateways which are not pinned yet, e.g. created after the rollout started,
+	// are already provisioned with the current spec.
+	for i := range gateways {
+		if _, ok := gateways[i].Annotations[consts.AnnotationGatewayConfigurationRevision]; !ok {
+			if err := r.pinGatewayToRevision(ctx, &gateways[i], revision, now); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	condition, hasCondition := k8sutils.GetCondition(stagedRolloutConditionType, &gatewayConfig)
+	if gatewayConfig.Annotations[consts.AnnotationStagedRolloutPaused] == "true" {
+		// Keep the message of the failure which paused the rollout.
+		if hasCondition && condition.Reason == string(stagedRolloutReasonPaused) && condition.ObservedGeneration == gatewayConfig.Generation {
+			return ctrl.Result{}, nil
+		}
+		return patch.StatusWithCondition(ctx, r.Client, &gatewayConfig,
+			stagedRolloutConditionType, metav1.ConditionFalse, stagedRolloutReasonPaused,
+			fmt.Sprintf("Staged rollout of revision %s paused with the %s annotation", revision, consts.AnnotationStagedRolloutPaused),
+		)
+	}
+
+	groups, err := dataPlaneSharingGroups(ctx, r.Client, gateways)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	assigned := assignStagedRolloutWaves(waves, gateways, groups)
+	// next is the first wave with Gateways which are not pinned to the revision yet.
+	next := lo.IndexOf(lo.Map(assigned, func(wave []gwtypes.Gateway, _ int) bool {
+		return lo.SomeBy(wave, func(gateway gwtypes.Gateway) bool {
+			return gateway.Annotations[consts.AnnotationGatewayConfigurationRevision] != revision
+		})
+	}), true)
+	if next == -1 {
+		next = len(assigned)
+		if hasCondition && condition.Reason == string(stagedRolloutReasonCompleted) && condition.ObservedGeneration == gatewayConfig.Generation {
+			log.Trace(logger, "staged rollout already completed")
+			return ctrl.Result{}, nil
+		}
+	}
+	rolledOut := func(waves int) int {
+		return len(lo.Flatten(assigned[:waves]))
+	}
+
+	// The next wave starts once the previous one has been rolled out.
+	if next > 0 {
+		previous := next - 1
+		// The wave in progress when the rollout was paused gets a new timeout
+		// when it's resumed, its Gateways being pinned to the revision again.
+		if hasCondition && condition.Reason == string(stagedRolloutReasonPaused) {
+			log.Debug(logger, "resuming staged rollout", "revision", revision, "wave", previous+1)
+			for i := range assigned[previous] {
+				if err := r.pinGatewayToRevision(ctx, &assigned[previous][i], revision, now); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+		}
+		done, failure, err := stagedRolloutWaveStatus(ctx, r.Client, assigned[previous], revision, timeout, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if failure != "" {
+			log.Info(logger, "pausing staged rollout", "revision", revision, "wave", previous+1, "reason", failure)
+			return ctrl.Result{}, r.pauseStagedRollout(ctx, &gatewayConfig,
+				fmt.Sprintf("Staged rollout of revision %s paused in wave %d of %d: %s", revision, previous+1, len(assigned), failure),
+			)
+		}
+		if !done {
+			return r.stagedRolloutInProgress(ctx, &gatewayConfig, revision, previous, len(assigned), rolledOut(previous), len(gateways))
+		}
+	}
+
+	if next == len(assigned) {
+		log.Debug(logger, "staged rollout completed", "revision", revision)
+		if err := r.pruneGatewayConfigRevisions(ctx, &gatewayConfig, gateways, revision); err != nil {
+			return ctrl.Result{}, err
+		}
+		return patch.StatusWithCondition(ctx, r.Client, &gatewayConfig,
+			stagedRolloutConditionType, metav1.ConditionTrue, stagedRolloutReasonCompleted,
+			fmt.Sprintf("Revision %s rolled out to all %d Gateways", revision, len(gateways)),
+		)
+	}
+
+	log.Debug(logger, "starting staged rollout wave", "revision", revision, "wave", next+1)
+	for i := range assigned[next] {
+		if err := r.pinGatewayToRevision(ctx, &assigned[next][i], revision, now); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return r.stagedRolloutInProgress(ctx, &gatewayConfig, revision, next, len(assigned), rolledOut(next), len(gateways))
+}
+
+// stagedRolloutInProgress reports the progress of the staged rollout in the
+// GatewayConfiguration status and requeues it to check the wave's progress.
+func (r *StagedRolloutReconciler) stagedRolloutInProgress(
+	ctx context.Context,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	revision string,
+	wave, waves int,
+	rolledOut, gateways int,
+) (ctrl.Result, error) {
+	res, err := patch.StatusWithCondition(ctx, r.Client, gatewayConfig,
+		stagedRolloutConditionType, metav1.ConditionFalse, stagedRolloutReasonWaveInProgress,
+		fmt.Sprintf("Rolling out revision %s: wave %d of %d in progress, %d of %d Gateways rolled out",
+			revision, wave+1, waves, rolledOut, gateways,
+		),
+	)
+	if err != nil || !res.IsZero() {
+		return res, err
+	}
+	// DataPlanes becoming Ready are not watched so check the wave periodically.
+	return ctrl.Result{RequeueAfter: stagedRolloutCheckInterval}, nil
+}
+
+// stagedRolloutWaveStatus returns true when all the Gateways of the wave have
+// been rolled out to the revision, or the reason of the failure of the wave
+// when one of them failed or they have not been rolled out within the timeout.
+func stagedRolloutWaveStatus(
+	ctx context.Context,
+	cl client.Client,
+	wave []gwtypes.Gateway,
+	revision string,
+	timeout time.Duration,
+	now time.Time,
+) (bool, string, error) {
+	var (
+		startedAt time.Time
+		pending   []string
+	)
+	for i := range wave {
+		gateway := &wave[i]
+		if t, err := time.Parse(time.RFC3339, gateway.Annotations[consts.AnnotationGatewayConfigurationRevisionTimestamp]); err == nil && t.After(startedAt) {
+			startedAt = t
+		}
+		done, err := gatewayRolledOut(ctx, cl, gateway, revision)
+		if err != nil {
+			if errors.Is(err, errGatewayRolloutFailed) {
+				return false, fmt.Sprintf("Gateway %s: %v", client.ObjectKeyFromObject(gateway), err), nil
+			}
+			return false, "", err
+		}
+		if !done {
+			pending = append(pending, client.ObjectKeyFromObject(gateway).String())
+		}
+	}
+
+	if len(pending) == 0 {
+		return true, "", nil
+	}
+	if now.After(startedAt.Add(timeout)) {
+		return false, fmt.Sprintf("Gateways %s not rolled out within %s", strings.Join(pending, ", "), timeout), nil
+	}
+	return false, "", nil
+}
+
+// listStagedRolloutGateways lists the Gateways provisioned with the provided
+// GatewayConfiguration. Gateways referencing another GatewayConfiguration in
+// their spec.infrastructure.parametersRef are not, even if their GatewayClass does.
+func (r *StagedRolloutReconciler) listStagedRolloutGateways(
+	ctx context.Context,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) ([]gwtypes.Gateway, error) {
+	gateways, err := listGatewaysUsingGatewayConfig(ctx, r.Client, gatewayConfig)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Filter(gateways, func(gateway gwtypes.Gateway, _ int) bool {
+		return gateway.Spec.Infrastructure == nil || gateway.Spec.Infrastructure.ParametersRef == nil ||
+			gatewayInfrastructureReferencesGatewayConfig(&gateway, gatewayConfig)
+	}), nil
+}
+
+// pinGatewayToRevision pins the Gateway to the provided GatewayConfiguration revision.
+func (r *StagedRolloutReconciler) pinGatewayToRevision(ctx context.Context, gateway *gwtypes.Gateway, revision string, now time.Time) error {
+	old := gateway.DeepCopy()
+	if gateway.Annotations == nil {
+		gateway.Annotations = make(map[string]string)
+	}
+	gateway.Annotations[consts.AnnotationGatewayConfigurationRevision] = revision
+	gateway.Annotations[consts.AnnotationGatewayConfigurationRevisionTimestamp] = now.UTC().Format(time.RFC3339)
+	if err := r.Patch(ctx, gateway, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed pinning Gateway %s to GatewayConfiguration revision %s: %w", client.ObjectKeyFromObject(gateway), revision, err)
+	}
+	return nil
+}
+
+// pauseStagedRollout pauses the staged rollout with the consts.AnnotationStagedRolloutPaused
+// annotation and reports why in the GatewayConfiguration status.
+func (r *StagedRolloutReconciler) pauseStagedRollout(ctx context.Context, gatewayConfig *operatorv1beta1.GatewayConfiguration, message string) error {
+	old := gatewayConfig.DeepCopy()
+	if gatewayConfig.Annotations == nil {
+		gatewayConfig.Annotations = make(map[string]string)
+	}
+	gatewayConfig.Annotations[consts.AnnotationStagedRolloutPaused] = "true"
+	if err := r.Patch(ctx, gatewayConfig, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed pausing staged rollout: %w", err)
+	}
+	_, err := patch.StatusWithCondition(ctx, r.Client, gatewayConfig,
+		stagedRolloutConditionType, metav1.ConditionFalse, stagedRolloutReasonPaused, message,
+	)
+	return err
+}
+
+// pruneGatewayConfigRevisions deletes the revisions of the GatewayConfiguration
+// which are neither the current one nor used by any Gateway.
+func (r *StagedRolloutReconciler) pruneGatewayConfigRevisions(
+	ctx context.Context,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	gateways []gwtypes.Gateway,
+	current string,
+) error {
+	revisions, err := listGatewayConfigRevisions(ctx, r.Client, gatewayConfig)
+	if err != nil {
+		return err
+	}
+	for i := range revisions {
+		revision := &revisions[i]
+		if revision.Name == current || lo.SomeBy(gateways, func(gateway gwtypes.Gateway) bool {
+			return gateway.Annotations[consts.AnnotationGatewayConfigurationRevision] == revision.Name
+		}) {
+			continue
+		}
+		if err := r.Delete(ctx, revision); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed deleting ControllerRevision %s: %w", revision.Name, err)
+		}
+	}
+	return nil
+}
+
+// disableStagedRollout unpins the Gateways from the revisions of the
+// GatewayConfiguration, so that they use its current spec, deletes the
+// revisions and removes the staged rollout condition.
+func (r *StagedRolloutReconciler) disableStagedRollout(
+	ctx context.Context,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	gateways []gwtypes.Gateway,
+) error {
+	for i := range gateways {
+		gateway := &gateways[i]
+		if _, ok := gateway.Annotations[consts.AnnotationGatewayConfigurationRevision]; !ok {
+			continue
+		}
+		old := gateway.DeepCopy()
+		delete(gateway.Annotations, consts.AnnotationGatewayConfigurationRevision)
+		delete(gateway.Annotations, consts.AnnotationGatewayConfigurationRevisionTimestamp)
+		if err := r.Patch(ctx, gateway, client.MergeFrom(old)); err != nil {
+			return fmt.Errorf("failed unpinning Gateway %s from GatewayConfiguration revision: %w", client.ObjectKeyFromObject(gateway), err)
+		}
+	}
+
+	revisions, err := listGatewayConfigRevisions(ctx, r.Client, gatewayConfig)
+	if err != nil {
+		return err
+	}
+	for i := range revisions {
+		if err := r.Delete(ctx, &revisions[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed deleting ControllerRevision %s: %w", revisions[i].Name, err)
+		}
+	}
+
+	if !k8sutils.HasCondition(stagedRolloutConditionType, gatewayConfig) {
+		return nil
+	}
+	old := gatewayConfig.DeepCopy()
+	gatewayConfig.Status.Conditions = lo.Reject(gatewayConfig.Status.Conditions, func(c metav1.Condition, _ int) bool {
+		return c.Type == string(stagedRolloutConditionType)
+	})
+	if err := r.Status().Patch(ctx, gatewayConfig, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed removing %s condition: %w", stagedRolloutConditionType, err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// StagedRolloutReconciler - Watch Map Funcs
+// -----------------------------------------------------------------------------
+
+// listGatewayConfigsForGateway is a watch predicate which finds the
+// GatewayConfiguration used by a Gateway.
+func (r *StagedRolloutReconciler) listGatewayConfigsForGateway(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := ctrllog.FromContext(ctx)
+
+	gateway, ok := obj.(*gwtypes.Gateway)
+	if !ok {
+		logger.Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "Gateway", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+
+	if gateway.Spec.Infrastructure != nil && gateway.Spec.Infrastructure.ParametersRef != nil {
+		parametersRef := gateway.Spec.Infrastructure.ParametersRef
+		if string(parametersRef.Group) != operatorv1beta1.SchemeGroupVersion.Group ||
+			string(parametersRef.Kind) != "GatewayConfiguration" {
+			return nil
+		}
+		return []reconcile.Request{{
+			NamespacedName: client.ObjectKey{Namespace: gateway.Namespace, Name: parametersRef.Name},
+		}}
+	}
+
+	var gatewayClass gatewayv1.GatewayClass
+	if err := r.Get(ctx, client.ObjectKey{Name: string(gateway.Spec.GatewayClassName)}, &gatewayClass); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			logger.Error(err, "failed to run map funcs", "gatewayclass", gateway.Spec.GatewayClassName)
+		}
+		return nil
+	}
+	parametersRef := gatewayClass.Spec.ParametersRef
+	if parametersRef == nil || parametersRef.Namespace == nil ||
+		string(parametersRef.Group) != operatorv1beta1.SchemeGroupVersion.Group ||
+		string(parametersRef.Kind) != "GatewayConfiguration" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: client.ObjectKey{Namespace: string(*parametersRef.Namespace), Name: parametersRef.Name},
+	}}
+}
@@ -0,0 +1,400 @@
+package gateway
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	"github.com/kong/gateway-operator/pkg/vars"
+
+	kcfgdataplane "github.com/kong/kubernetes-configuration/api/gateway-operator/dataplane"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func stagedRolloutTestGateway(name string, labels map[string]string) gwtypes.Gateway {
+	return gwtypes.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       name,
+			UID:        types.UID(name),
+			Labels:     labels,
+			Generation: 1,
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "kong",
+		},
+	}
+}
+
+func TestParseStagedRolloutWaves(t *testing.T) {
+	testCases := []struct {
+		name          string
+		waves         string
+		expected      []string
+		expectedError bool
+	}{
+		{
+			name:     "label selector, count and percentage",
+			waves:    "env=canary;2;25%",
+			expected: []string{"selector env=canary", "count 2", "percent 25"},
+		},
+		{
+			name:     "whitespace is ignored",
+			waves:    " env in (dev,staging) ; 50% ",
+			expected: []string{"selector env in (dev,staging)", "percent 50"},
+		},
+		{
+			name:          "empty wave",
+			waves:         "1;;2",
+			expectedError: true,
+		},
+		{
+			name:          "zero Gateways",
+			waves:         "0",
+			expectedError: true,
+		},
+		{
+			name:          "percentage above 100%",
+			waves:         "150%",
+			expectedError: true,
+		},
+		{
+			name:          "invalid label selector",
+			waves:         "env==canary=",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			waves, err := parseStagedRolloutWaves(tc.waves)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, lo.Map(waves, func(w stagedRolloutWave, _ int) string {
+				switch {
+				case w.selector != nil:
+					return "selector " + w.selector.String()
+				case w.percent > 0:
+					return fmt.Sprintf("percent %d", w.percent)
+				default:
+					return fmt.Sprintf("count %d", w.count)
+				}
+			}))
+		})
+	}
+}
+
+func TestAssignStagedRolloutWaves(t *testing.T) {
+	gateways := []gwtypes.Gateway{
+		stagedRolloutTestGateway("gw-e", nil),
+		stagedRolloutTestGateway("gw-d", map[string]string{"env": "canary"}),
+		stagedRolloutTestGateway("gw-c", nil),
+		stagedRolloutTestGateway("gw-b", nil),
+		stagedRolloutTestGateway("gw-a", nil),
+		stagedRolloutTestGateway("gw-f", map[string]string{"env": "canary"}),
+	}
+	names := func(assigned [][]gwtypes.Gateway) [][]string {
+		return lo.Map(assigned, func(wave []gwtypes.Gateway, _ int) []string {
+			return lo.Map(wave, func(gateway gwtypes.Gateway, _ int) string { return gateway.Name })
+		})
+	}
+
+	testCases := []struct {
+		name     string
+		waves    string
+		groups   map[types.UID]types.UID
+		expected [][]string
+	}{
+		{
+			name:     "label selector, count and remaining Gateways",
+			waves:    "env=canary;1",
+			expected: [][]string{{"gw-d", "gw-f"}, {"gw-a"}, {"gw-b", "gw-c", "gw-e"}},
+		},
+		{
+			name:     "percentages are rounded up",
+			waves:    "25%;50%",
+			expected: [][]string{{"gw-a", "gw-b"}, {"gw-c", "gw-d", "gw-e"}, {"gw-f"}},
+		},
+		{
+			name:     "empty waves are dropped",
+			waves:    "env=prod;10;1",
+			expected: [][]string{{"gw-a", "gw-b", "gw-c", "gw-d", "gw-e", "gw-f"}},
+		},
+		{
+			name:  "Gateways sharing DataPlanes are assigned to the same wave",
+			waves: "env=canary;1",
+			groups: map[types.UID]types.UID{
+				"gw-a": "gw-a",
+				"gw-e": "gw-a",
+				"gw-c": "gw-d",
+				"gw-d": "gw-d",
+			},
+			expected: [][]string{{"gw-c", "gw-d", "gw-f"}, {"gw-a", "gw-e"}, {"gw-b"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			waves, err := parseStagedRolloutWaves(tc.waves)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, names(assignStagedRolloutWaves(waves, gateways, tc.groups)))
+		})
+	}
+}
+
+func TestDataPlaneSharingGroups(t *testing.T) {
+	gateways := []gwtypes.Gateway{
+		stagedRolloutTestGateway("gw-a", nil),
+		stagedRolloutTestGateway("gw-b", nil),
+		stagedRolloutTestGateway("gw-c", nil),
+		stagedRolloutTestGateway("gw-d", nil),
+	}
+	dataplane := func(name string, owners ...string) *operatorv1beta1.DataPlane {
+		return &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				Labels:    map[string]string{consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue},
+				OwnerReferences: lo.Map(owners, func(owner string, _ int) metav1.OwnerReference {
+					return metav1.OwnerReference{Kind: "Gateway", Name: owner, UID: types.UID(owner)}
+				}),
+			},
+		}
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(
+			dataplane("dp-a", "gw-a"),
+			dataplane("dp-ab", "gw-a", "gw-b"),
+			dataplane("dp-bc", "gw-b", "gw-c"),
+			dataplane("dp-d", "gw-d"),
+			dataplane("dp-d-other", "gw-d", "gw-other"),
+		).
+		Build()
+
+	groups, err := dataPlaneSharingGroups(t.Context(), cl, gateways)
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+	require.Equal(t, groups["gw-a"], groups["gw-b"])
+	require.Equal(t, groups["gw-a"], groups["gw-c"])
+	require.NotContains(t, groups, types.UID("gw-d"))
+}
+
+func TestStagedRolloutReconciler(t *testing.T) {
+	ctx := t.Context()
+
+	gatewayClass := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+		Spec: gatewayv1.GatewayClassSpec{
+			ControllerName: gatewayv1.GatewayController(vars.ControllerName()),
+			ParametersRef: &gatewayv1.ParametersReference{
+				Group:     gatewayv1.Group(operatorv1beta1.SchemeGroupVersion.Group),
+				Kind:      "GatewayConfiguration",
+				Name:      "gwc",
+				Namespace: lo.ToPtr(gatewayv1.Namespace("default")),
+			},
+		},
+	}
+	gatewayConfig := &operatorv1beta1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "gwc",
+			UID:         "gwc-uid",
+			Generation:  1,
+			Annotations: map[string]string{consts.AnnotationStagedRolloutWaves: "1"},
+		},
+	}
+	gateways := []gwtypes.Gateway{
+		stagedRolloutTestGateway("gw-a", nil),
+		stagedRolloutTestGateway("gw-b", nil),
+		stagedRolloutTestGateway("gw-c", nil),
+	}
+
+	builder := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(gatewayClass, gatewayConfig).
+		WithStatusSubresource(gatewayConfig, &operatorv1beta1.DataPlane{})
+	for i := range gateways {
+		builder = builder.WithObjects(&gateways[i]).WithStatusSubresource(&gateways[i])
+	}
+	cl := builder.Build()
+	r := &StagedRolloutReconciler{Client: cl}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gatewayConfig)}
+
+	getGatewayConfig := func(t *testing.T) *operatorv1beta1.GatewayConfiguration {
+		var gwc operatorv1beta1.GatewayConfiguration
+		require.NoError(t, cl.Get(ctx, req.NamespacedName, &gwc))
+		return &gwc
+	}
+	requireCondition := func(t *testing.T, reason string) metav1.Condition {
+		c, ok := k8sutils.GetCondition(stagedRolloutConditionType, getGatewayConfig(t))
+		require.True(t, ok)
+		require.Equal(t, reason, c.Reason)
+		return c
+	}
+	gatewayRevision := func(t *testing.T, name string) string {
+		var gateway gwtypes.Gateway
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &gateway))
+		return gateway.Annotations[consts.AnnotationGatewayConfigurationRevision]
+	}
+	// rollOut simulates the Gateway Reconciler provisioning the Gateway with its revision.
+	rollOut := func(t *testing.T, name string) {
+		var gateway gwtypes.Gateway
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &gateway))
+		gateway.Status.Conditions = []metav1.Condition{{
+			Type:               string(gatewayv1.GatewayConditionProgrammed),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(gatewayv1.GatewayReasonProgrammed),
+			ObservedGeneration: gateway.Generation,
+			LastTransitionTime: metav1.Now(),
+		}}
+		require.NoError(t, cl.Status().Update(ctx, &gateway))
+
+		dataplane := &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		}
+		_, err := ctrl.CreateOrUpdate(ctx, cl, dataplane, func() error {
+			dataplane.Labels = map[string]string{consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue}
+			dataplane.Annotations = map[string]string{
+				consts.AnnotationGatewayConfigurationRevision: gateway.Annotations[consts.AnnotationGatewayConfigurationRevision],
+			}
+			return ctrl.SetControllerReference(&gateway, dataplane, cl.Scheme())
+		})
+		require.NoError(t, err)
+		dataplane.Status.Conditions = []metav1.Condition{{
+			Type:               string(kcfgdataplane.ReadyType),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(kcfgdataplane.ResourceReadyReason),
+			ObservedGeneration: dataplane.Generation,
+			LastTransitionTime: metav1.Now(),
+		}}
+		require.NoError(t, cl.Status().Update(ctx, dataplane))
+	}
+	reconcile := func(t *testing.T) ctrl.Result {
+		res, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Log("Gateways are pinned to the first revision, which they already use")
+	res := reconcile(t)
+	require.Equal(t, stagedRolloutCheckInterval, res.RequeueAfter)
+	firstRevision := gatewayRevision(t, "gw-a")
+	require.NotEmpty(t, firstRevision)
+	require.Equal(t, firstRevision, gatewayRevision(t, "gw-b"))
+	require.Equal(t, firstRevision, gatewayRevision(t, "gw-c"))
+	requireCondition(t, string(stagedRolloutReasonWaveInProgress))
+
+	for _, name := range []string{"gw-a", "gw-b", "gw-c"} {
+		rollOut(t, name)
+	}
+	reconcile(t)
+	completed := requireCondition(t, string(stagedRolloutReasonCompleted))
+	require.Equal(t, metav1.ConditionTrue, completed.Status)
+
+	t.Log("a spec change is rolled out to the first wave only")
+	gwc := getGatewayConfig(t)
+	gwc.Spec.DataPlaneOptions = &operatorv1beta1.GatewayConfigDataPlaneOptions{
+		Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+			DeploymentOptions: operatorv1beta1.DeploymentOptions{Replicas: lo.ToPtr(int32(3))},
+		},
+	}
+	gwc.Generation = 2
+	require.NoError(t, cl.Update(ctx, gwc))
+	reconcile(t)
+	secondRevision := gatewayRevision(t, "gw-a")
+	require.NotEqual(t, firstRevision, secondRevision)
+	require.Equal(t, firstRevision, gatewayRevision(t, "gw-b"))
+	require.Equal(t, firstRevision, gatewayRevision(t, "gw-c"))
+	inProgress := requireCondition(t, string(stagedRolloutReasonWaveInProgress))
+	require.Contains(t, inProgress.Message, "wave 1 of 2")
+
+	t.Log("Gateways of the next waves are provisioned with the previous revision")
+	var gatewayB gwtypes.Gateway
+	require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "gw-b"}, &gatewayB))
+	pinned, err := gatewayConfigForGatewayRevision(ctx, cl, getGatewayConfig(t), &gatewayB)
+	require.NoError(t, err)
+	require.Nil(t, pinned.Spec.DataPlaneOptions)
+	require.Equal(t, firstRevision, pinned.Annotations[consts.AnnotationGatewayConfigurationRevision])
+
+	t.Log("Gateways pinned to a revision which doesn't exist are not provisioned")
+	gatewayPinnedToMissingRevision := gatewayB.DeepCopy()
+	gatewayPinnedToMissingRevision.Annotations[consts.AnnotationGatewayConfigurationRevision] = "gwc-missing"
+	_, err = gatewayConfigForGatewayRevision(ctx, cl, getGatewayConfig(t), gatewayPinnedToMissingRevision)
+	require.ErrorIs(t, err, errGatewayConfigRevisionNotFound)
+
+	t.Log("the next wave waits for the first one to be rolled out")
+	reconcile(t)
+	require.Equal(t, firstRevision, gatewayRevision(t, "gw-b"))
+	rollOut(t, "gw-a")
+	reconcile(t)
+	require.Equal(t, secondRevision, gatewayRevision(t, "gw-b"))
+	require.Equal(t, secondRevision, gatewayRevision(t, "gw-c"))
+	inProgress = requireCondition(t, string(stagedRolloutReasonWaveInProgress))
+	require.Contains(t, inProgress.Message, "wave 2 of 2 in progress, 1 of 3 Gateways rolled out")
+
+	t.Log("the rollout is paused when a wave is not rolled out within the timeout")
+	require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "gw-b"}, &gatewayB))
+	old := gatewayB.DeepCopy()
+	gatewayB.Annotations[consts.AnnotationGatewayConfigurationRevisionTimestamp] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	require.NoError(t, cl.Patch(ctx, &gatewayB, client.MergeFrom(old)))
+	var gatewayC gwtypes.Gateway
+	require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "gw-c"}, &gatewayC))
+	old = gatewayC.DeepCopy()
+	gatewayC.Annotations[consts.AnnotationGatewayConfigurationRevisionTimestamp] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	require.NoError(t, cl.Patch(ctx, &gatewayC, client.MergeFrom(old)))
+	reconcile(t)
+	require.Equal(t, "true", getGatewayConfig(t).Annotations[consts.AnnotationStagedRolloutPaused])
+	paused := requireCondition(t, string(stagedRolloutReasonPaused))
+	require.Contains(t, paused.Message, "default/gw-b, default/gw-c not rolled out within 10m0s")
+	reconcile(t)
+	require.Equal(t, paused.Message, requireCondition(t, string(stagedRolloutReasonPaused)).Message)
+
+	t.Log("resuming the rollout restarts the timeout of the wave in progress")
+	gwc = getGatewayConfig(t)
+	delete(gwc.Annotations, consts.AnnotationStagedRolloutPaused)
+	require.NoError(t, cl.Update(ctx, gwc))
+	reconcile(t)
+	require.NotContains(t, getGatewayConfig(t).Annotations, consts.AnnotationStagedRolloutPaused)
+	inProgress = requireCondition(t, string(stagedRolloutReasonWaveInProgress))
+	require.Contains(t, inProgress.Message, "wave 2 of 2 in progress")
+	for _, name := range []string{"gw-b", "gw-c"} {
+		var gateway gwtypes.Gateway
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &gateway))
+		startedAt, err := time.Parse(time.RFC3339, gateway.Annotations[consts.AnnotationGatewayConfigurationRevisionTimestamp])
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now(), startedAt, time.Minute)
+	}
+	reconcile(t)
+	requireCondition(t, string(stagedRolloutReasonWaveInProgress))
+
+	t.Log("disabling staged rollouts unpins Gateways and deletes revisions")
+	gwc = getGatewayConfig(t)
+	delete(gwc.Annotations, consts.AnnotationStagedRolloutWaves)
+	require.NoError(t, cl.Update(ctx, gwc))
+	reconcile(t)
+	for _, name := range []string{"gw-a", "gw-b", "gw-c"} {
+		require.Empty(t, gatewayRevision(t, name))
+	}
+	var revisions appsv1.ControllerRevisionList
+	require.NoError(t, cl.List(ctx, &revisions, client.InNamespace("default")))
+	require.Empty(t, revisions.Items)
+	require.False(t, k8sutils.HasCondition(stagedRolloutConditionType, getGatewayConfig(t)))
+}
@@ -0,0 +1,208 @@
+package gateway
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// staticAddresses holds the static addresses requested in Gateway's spec.addresses.
+type staticAddresses struct {
+	// IPs are the IP addresses which can be bound to the DataPlane's ingress Service.
+	IPs []string
+	// Hostnames are the hostnames which are set on the DataPlane's ingress Service
+	// using the external-dns hostname annotation.
+	Hostnames []string
+	// Unusable are the requested addresses which cannot be bound to the DataPlane's
+	// ingress Service.
+	Unusable []string
+	// HasEmptyValue is true when any of the requested addresses has an empty
+	// value, asking the implementation to assign an address, which is not supported.
+	HasEmptyValue bool
+}
+
+// gatewayAddressTypeSupported returns true if the provided address type is
+// supported in Gateway's spec.addresses.
+func gatewayAddressTypeSupported(addressType *gatewayv1.AddressType) bool {
+	// When unset, the address type defaults to IPAddress.
+	return addressType == nil ||
+		*addressType == gatewayv1.IPAddressType ||
+		*addressType == gatewayv1.HostnameAddressType
+}
+
+// gatewayStaticAddresses returns the static addresses requested in Gateway's
+// spec.addresses for a DataPlane ingress Service of the provided type.
+// LoadBalancer Services can only be assigned a single IP address.
+func gatewayStaticAddresses(gateway *gwtypes.Gateway, serviceType corev1.ServiceType) staticAddresses {
+	var addresses staticAddresses
+	for _, address := range gateway.Spec.Addresses {
+		if !gatewayAddressTypeSupported(address.Type) {
+			continue
+		}
+		switch {
+		case address.Value == "":
+			addresses.HasEmptyValue = true
+		case address.Type != nil && *address.Type == gatewayv1.HostnameAddressType:
+			addresses.Hostnames = append(addresses.Hostnames, address.Value)
+		case net.ParseIP(address.Value) == nil:
+			addresses.Unusable = append(addresses.Unusable, address.Value)
+		case serviceType == corev1.ServiceTypeLoadBalancer && len(addresses.IPs) > 0:
+			addresses.Unusable = append(addresses.Unusable, address.Value)
+		default:
+			addresses.IPs = append(addresses.IPs, address.Value)
+		}
+	}
+	return addresses
+}
+
+// dataPlaneIngressServiceType returns the type of the ingress Service for the
+// provided DataPlane options.
+func dataPlaneIngressServiceType(opts *operatorv1beta1.DataPlaneOptions) corev1.ServiceType {
+	if opts.Network.Services == nil ||
+		opts.Network.Services.Ingress == nil ||
+		opts.Network.Services.Ingress.Type == "" {
+		return k8sresources.DefaultDataPlaneIngressServiceType
+	}
+	return opts.Network.Services.Ingress.Type
+}
+
+// setDataPlaneIngressServiceHostnames sets the external-dns hostname annotation
+// of the DataPlane's ingress Service with the provided hostnames.
+func setDataPlaneIngressServiceHostnames(opts *operatorv1beta1.DataPlaneOptions, hostnames []string) {
+	if len(hostnames) == 0 {
+		return
+	}
+	if opts.Network.Services == nil {
+		opts.Network.Services = &operatorv1beta1.DataPlaneServices{}
+	}
+	if opts.Network.Services.Ingress == nil {
+		opts.Network.Services.Ingress = &operatorv1beta1.DataPlaneServiceOptions{}
+	}
+
+	// The annotations might be shared with the GatewayConfiguration, hence
+	// they are copied before being modified.
+	annotations := maps.Clone(opts.Network.Services.Ingress.Annotations)
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[consts.ExternalDNSHostnameAnnotation] = strings.Join(hostnames, ",")
+	opts.Network.Services.Ingress.Annotations = annotations
+}
+
+// setDataPlaneStaticIPs sets the consts.AnnotationDataPlaneIngressServiceStaticIPs
+// annotation on the DataPlane with the provided IP addresses. It returns true
+// when the DataPlane's annotations were changed.
+func setDataPlaneStaticIPs(dataplane *operatorv1beta1.DataPlane, ips []string) bool {
+	value := strings.Join(ips, ",")
+	current, ok := dataplane.Annotations[consts.AnnotationDataPlaneIngressServiceStaticIPs]
+	switch {
+	case value == "" && !ok:
+		return false
+	case value == "":
+		delete(dataplane.Annotations, consts.AnnotationDataPlaneIngressServiceStaticIPs)
+		return true
+	case current == value:
+		return false
+	}
+
+	if dataplane.Annotations == nil {
+		dataplane.Annotations = make(map[string]string, 1)
+	}
+	dataplane.Annotations[consts.AnnotationDataPlaneIngressServiceStaticIPs] = value
+	return true
+}
+
+// setUnsupportedAddress marks the Gateway as not accepted with the UnsupportedAddress
+// reason when any of the addresses in its spec.addresses is of an unsupported type.
+func (g *gatewayConditionsAndListenersAwareT) setUnsupportedAddress() {
+	var unsupported []string
+	for _, address := range g.Spec.Addresses {
+		if !gatewayAddressTypeSupported(address.Type) {
+			unsupported = append(unsupported, fmt.Sprintf("%s (%s)", address.Value, *address.Type))
+		}
+	}
+	if len(unsupported) == 0 {
+		return
+	}
+
+	k8sutils.SetCondition(metav1.Condition{
+		Type:   string(gatewayv1.GatewayConditionAccepted),
+		Status: metav1.ConditionFalse,
+		Reason: string(gatewayv1.GatewayReasonUnsupportedAddress),
+		Message: fmt.Sprintf("Only %s and %s address types are supported, unsupported addresses: %s",
+			gatewayv1.IPAddressType, gatewayv1.HostnameAddressType, strings.Join(unsupported, ", ")),
+		ObservedGeneration: g.Generation,
+		LastTransitionTime: metav1.Now(),
+	}, g)
+}
+
+// gatewayStatusAddressesForStaticAddresses returns the Gateway status addresses
+// when static addresses were requested in the Gateway's spec.addresses, based
+// on the addresses of the DataPlane's ingress Service.
+// It returns a non empty reason when not all the requested addresses are assigned:
+//   - AddressNotUsable when any of the requested addresses cannot be used,
+//   - AddressNotAssigned when any of the requested addresses has an empty value
+//     or any of the requested IP addresses is not assigned to the ingress Service (yet).
+func gatewayStatusAddressesForStaticAddresses(
+	requested staticAddresses,
+	serviceAddresses []gwtypes.GatewayStatusAddress,
+) ([]gwtypes.GatewayStatusAddress, gatewayv1.GatewayConditionReason, string) {
+	var (
+		addresses  = make([]gwtypes.GatewayStatusAddress, 0, len(requested.IPs)+len(requested.Hostnames))
+		unassigned []string
+	)
+	for _, ip := range requested.IPs {
+		if !slices.ContainsFunc(serviceAddresses, func(a gwtypes.GatewayStatusAddress) bool {
+			return a.Value == ip
+		}) {
+			unassigned = append(unassigned, ip)
+			continue
+		}
+		addresses = append(addresses, gwtypes.GatewayStatusAddress{
+			Type:  lo.ToPtr(gatewayv1.IPAddressType),
+			Value: ip,
+		})
+	}
+	// Hostnames are resolved by DNS to the ingress Service's addresses, hence they
+	// are considered assigned as soon as the Service has an address.
+	if len(serviceAddresses) > 0 {
+		for _, hostname := range requested.Hostnames {
+			addresses = append(addresses, gwtypes.GatewayStatusAddress{
+				Type:  lo.ToPtr(gatewayv1.HostnameAddressType),
+				Value: hostname,
+			})
+		}
+	}
+
+	switch {
+	case len(requested.Unusable) > 0:
+		return addresses, gatewayv1.GatewayReasonAddressNotUsable,
+			fmt.Sprintf("Requested addresses cannot be used: %q. Addresses must be valid IP addresses or hostnames "+
+				"and LoadBalancer Services support a single static IP address.", requested.Unusable)
+	case requested.HasEmptyValue:
+		return addresses, gatewayv1.GatewayReasonAddressNotAssigned,
+			"Addresses with an empty value are not supported"
+	case len(unassigned) > 0:
+		return addresses, gatewayv1.GatewayReasonAddressNotAssigned,
+			fmt.Sprintf("Requested addresses are not assigned yet: %q", unassigned)
+	case len(requested.Hostnames) > 0 && len(serviceAddresses) == 0:
+		return addresses, gatewayv1.GatewayReasonAddressNotAssigned,
+			"The DataPlane ingress Service has no addresses yet"
+	default:
+		return addresses, "", ""
+	}
+}
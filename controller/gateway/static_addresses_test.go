@@ -0,0 +1,187 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestGatewayStaticAddresses(t *testing.T) {
+	addresses := []gatewayv1.GatewaySpecAddress{
+		{Value: "203.0.113.10"},
+		{Type: lo.ToPtr(gatewayv1.IPAddressType), Value: "203.0.113.11"},
+		{Type: lo.ToPtr(gatewayv1.HostnameAddressType), Value: "gw.example.com"},
+		{Type: lo.ToPtr(gatewayv1.IPAddressType), Value: "not-an-ip"},
+		{Type: lo.ToPtr(gatewayv1.NamedAddressType), Value: "named"},
+	}
+	gateway := &gwtypes.Gateway{
+		Spec: gatewayv1.GatewaySpec{
+			Addresses: addresses,
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		serviceType corev1.ServiceType
+		expected    staticAddresses
+	}{
+		{
+			name:        "LoadBalancer",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			expected: staticAddresses{
+				IPs:       []string{"203.0.113.10"},
+				Hostnames: []string{"gw.example.com"},
+				Unusable:  []string{"203.0.113.11", "not-an-ip"},
+			},
+		},
+		{
+			name:        "ClusterIP",
+			serviceType: corev1.ServiceTypeClusterIP,
+			expected: staticAddresses{
+				IPs:       []string{"203.0.113.10", "203.0.113.11"},
+				Hostnames: []string{"gw.example.com"},
+				Unusable:  []string{"not-an-ip"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, gatewayStaticAddresses(gateway, tc.serviceType))
+		})
+	}
+}
+
+func TestSetDataPlaneStaticAddresses(t *testing.T) {
+	gatewayConfigAnnotations := map[string]string{"foo": "bar"}
+	opts := &operatorv1beta1.DataPlaneOptions{
+		Network: operatorv1beta1.DataPlaneNetworkOptions{
+			Services: &operatorv1beta1.DataPlaneServices{
+				Ingress: &operatorv1beta1.DataPlaneServiceOptions{
+					ServiceOptions: operatorv1beta1.ServiceOptions{
+						Annotations: gatewayConfigAnnotations,
+					},
+				},
+			},
+		},
+	}
+	setDataPlaneIngressServiceHostnames(opts, []string{"a.example.com", "b.example.com"})
+	require.Equal(t, map[string]string{
+		"foo": "bar",
+		"external-dns.alpha.kubernetes.io/hostname": "a.example.com,b.example.com",
+	}, opts.Network.Services.Ingress.Annotations)
+	require.Equal(t, map[string]string{"foo": "bar"}, gatewayConfigAnnotations, "GatewayConfiguration annotations should not be modified")
+
+	dataplane := &operatorv1beta1.DataPlane{}
+	require.False(t, setDataPlaneStaticIPs(dataplane, nil))
+	require.True(t, setDataPlaneStaticIPs(dataplane, []string{"203.0.113.10"}))
+	require.Equal(t, "203.0.113.10", dataplane.Annotations[consts.AnnotationDataPlaneIngressServiceStaticIPs])
+	require.False(t, setDataPlaneStaticIPs(dataplane, []string{"203.0.113.10"}))
+	require.True(t, setDataPlaneStaticIPs(dataplane, nil))
+	require.NotContains(t, dataplane.Annotations, consts.AnnotationDataPlaneIngressServiceStaticIPs)
+}
+
+func TestSetUnsupportedAddress(t *testing.T) {
+	gateway := gatewayConditionsAndListenersAware(&gwtypes.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Generation: 1,
+		},
+		Spec: gatewayv1.GatewaySpec{
+			Addresses: []gatewayv1.GatewaySpecAddress{
+				{Value: "203.0.113.10"},
+				{Type: lo.ToPtr(gatewayv1.NamedAddressType), Value: "named"},
+			},
+		},
+	})
+	gateway.setUnsupportedAddress()
+
+	accepted, ok := k8sutils.GetCondition(kcfgconsts.ConditionType(gatewayv1.GatewayConditionAccepted), gateway)
+	require.True(t, ok)
+	require.Equal(t, metav1.ConditionFalse, accepted.Status)
+	require.Equal(t, string(gatewayv1.GatewayReasonUnsupportedAddress), accepted.Reason)
+	require.Equal(t, "Only IPAddress and Hostname address types are supported, unsupported addresses: named (NamedAddress)", accepted.Message)
+}
+
+func TestGatewayStatusAddressesForStaticAddresses(t *testing.T) {
+	serviceAddresses := []gwtypes.GatewayStatusAddress{
+		{Type: lo.ToPtr(gatewayv1.IPAddressType), Value: "203.0.113.10"},
+	}
+
+	testCases := []struct {
+		name              string
+		requested         staticAddresses
+		serviceAddresses  []gwtypes.GatewayStatusAddress
+		expectedAddresses []gwtypes.GatewayStatusAddress
+		expectedReason    gatewayv1.GatewayConditionReason
+	}{
+		{
+			name: "all addresses assigned",
+			requested: staticAddresses{
+				IPs:       []string{"203.0.113.10"},
+				Hostnames: []string{"gw.example.com"},
+			},
+			serviceAddresses: serviceAddresses,
+			expectedAddresses: []gwtypes.GatewayStatusAddress{
+				{Type: lo.ToPtr(gatewayv1.IPAddressType), Value: "203.0.113.10"},
+				{Type: lo.ToPtr(gatewayv1.HostnameAddressType), Value: "gw.example.com"},
+			},
+		},
+		{
+			name: "IP address not assigned",
+			requested: staticAddresses{
+				IPs: []string{"203.0.113.11"},
+			},
+			serviceAddresses:  serviceAddresses,
+			expectedAddresses: []gwtypes.GatewayStatusAddress{},
+			expectedReason:    gatewayv1.GatewayReasonAddressNotAssigned,
+		},
+		{
+			name: "hostname without Service addresses",
+			requested: staticAddresses{
+				Hostnames: []string{"gw.example.com"},
+			},
+			expectedAddresses: []gwtypes.GatewayStatusAddress{},
+			expectedReason:    gatewayv1.GatewayReasonAddressNotAssigned,
+		},
+		{
+			name: "empty value",
+			requested: staticAddresses{
+				HasEmptyValue: true,
+			},
+			serviceAddresses:  serviceAddresses,
+			expectedAddresses: []gwtypes.GatewayStatusAddress{},
+			expectedReason:    gatewayv1.GatewayReasonAddressNotAssigned,
+		},
+		{
+			name: "unusable address",
+			requested: staticAddresses{
+				IPs:      []string{"203.0.113.10"},
+				Unusable: []string{"203.0.113.11"},
+			},
+			serviceAddresses: serviceAddresses,
+			expectedAddresses: []gwtypes.GatewayStatusAddress{
+				{Type: lo.ToPtr(gatewayv1.IPAddressType), Value: "203.0.113.10"},
+			},
+			expectedReason: gatewayv1.GatewayReasonAddressNotUsable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			addresses, reason, _ := gatewayStatusAddressesForStaticAddresses(tc.requested, tc.serviceAddresses)
+			require.Equal(t, tc.expectedAddresses, addresses)
+			require.Equal(t, tc.expectedReason, reason)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	orascreds "oras.land/oras-go/v2/registry/remote/credentials"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -25,6 +26,7 @@ import (
 
 	"github.com/kong/gateway-operator/controller/kongplugininstallation/image"
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/secrets/ref"
 	"github.com/kong/gateway-operator/modules/manager/logging"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
@@ -40,13 +42,17 @@ type Reconciler struct {
 	client.Client
 	Scheme      *runtime.Scheme
 	LoggingMode logging.Mode
+
+	eventRecorder record.EventRecorder
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("kongplugininstallation")
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&operatorv1alpha1.KongPluginInstallation{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, pause.AnnotationChangedPredicate)).
 		Owns(&corev1.ConfigMap{}, builder.WithPredicates(
 			predicate.Funcs{
 				DeleteFunc: func(e event.DeleteEvent) bool {
@@ -94,6 +100,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if err := r.Get(ctx, req.NamespacedName, &kpi); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+
+	log.Trace(logger, "checking if KongPluginInstallation reconciliation is paused")
+	if paused, res, err := pause.Reconcile(ctx, r.Client, r.eventRecorder, &kpi, kongPluginInstallationConditionsAware{&kpi}); err != nil || paused || !res.IsZero() {
+		if paused {
+			log.Debug(logger, "KongPluginInstallation reconciliation is paused")
+		}
+		return res, err
+	}
 	if err := setStatusConditionForKongPluginInstallation(
 		ctx, r.Client, &kpi, metav1.ConditionFalse, operatorv1alpha1.KongPluginInstallationReasonPending, "fetching plugin is in progress",
 	); err != nil {
@@ -253,6 +267,22 @@ func (r *Reconciler) listReferenceGrantsForKongPluginInstallation(ctx context.Co
 	return recs
 }
 
+// kongPluginInstallationConditionsAware allows managing status conditions
+// of a KongPluginInstallation.
+type kongPluginInstallationConditionsAware struct {
+	*operatorv1alpha1.KongPluginInstallation
+}
+
+// GetConditions returns the status conditions.
+func (k kongPluginInstallationConditionsAware) GetConditions() []metav1.Condition {
+	return k.Status.Conditions
+}
+
+// SetConditions sets the status conditions.
+func (k kongPluginInstallationConditionsAware) SetConditions(conditions []metav1.Condition) {
+	k.Status.Conditions = conditions
+}
+
 func setStatusConditionFailedForKongPluginInstallation(
 	ctx context.Context, client client.Client, kpi *operatorv1alpha1.KongPluginInstallation, msg string,
 ) error {
@@ -163,3 +163,26 @@ func _setControlPlaneGroupMembersReferenceResolvedCondition(
 		cpGroup,
 	)
 }
+
+const (
+	// ControlPlaneGroupMembershipConditionType sets the condition for control planes
+	// declaring the control plane group they are members of (through the
+	// konnect.konghq.com/control-plane-group annotation) to show whether
+	// they are attached to the group.
+	ControlPlaneGroupMembershipConditionType = "ControlPlaneGroupMembership"
+	// ControlPlaneGroupMembershipReasonAttached indicates that the control plane
+	// is attached to the control plane group in Konnect.
+	ControlPlaneGroupMembershipReasonAttached kcfgconsts.ConditionReason = "Attached"
+	// ControlPlaneGroupMembershipReasonInvalidGroupRef indicates that the declared
+	// control plane group does not exist or is not a control plane group.
+	ControlPlaneGroupMembershipReasonInvalidGroupRef kcfgconsts.ConditionReason = "InvalidGroupRef"
+	// ControlPlaneGroupMembershipReasonConflict indicates that the control plane
+	// is listed as a member of another control plane group.
+	ControlPlaneGroupMembershipReasonConflict kcfgconsts.ConditionReason = "Conflict"
+	// ControlPlaneGroupMembershipReasonPending indicates that the control plane
+	// is not created in Konnect yet and can't be attached to the group.
+	ControlPlaneGroupMembershipReasonPending kcfgconsts.ConditionReason = "Pending"
+	// ControlPlaneGroupMembershipReasonFailedToSet indicates that error happened
+	// on setting the members of the control plane group.
+	ControlPlaneGroupMembershipReasonFailedToSet kcfgconsts.ConditionReason = "FailedToSet"
+)
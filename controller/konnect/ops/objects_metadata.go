@@ -86,9 +86,13 @@ func generateKubernetesMetadataTags(obj ObjectWithMetadata) []string {
 		{Key: KubernetesGroupLabelKey, Value: obj.GetObjectKind().GroupVersionKind().GroupVersion().Group},
 		{Key: KubernetesKindLabelKey, Value: obj.GetObjectKind().GroupVersionKind().Kind},
 		{Key: KubernetesNameLabelKey, Value: obj.GetName()},
-		{Key: KubernetesUIDLabelKey, Value: string(obj.GetUID())},
 		{Key: KubernetesVersionLabelKey, Value: obj.GetObjectKind().GroupVersionKind().GroupVersion().Version},
 	}
+	// The UID is empty only for objects that are orphaning their Konnect entities
+	// (see DeletionPolicyOrphan) which should not be tied to the object anymore.
+	if uid := obj.GetUID(); uid != "" {
+		labels = append(labels, lo.Entry[string, string]{Key: KubernetesUIDLabelKey, Value: string(uid)})
+	}
 	if k8sNamespace := obj.GetNamespace(); k8sNamespace != "" {
 		labels = append(labels, lo.Entry[string, string]{Key: KubernetesNamespaceLabelKey, Value: k8sNamespace})
 	}
@@ -107,12 +111,14 @@ func generateKubernetesMetadataTags(obj ObjectWithMetadata) []string {
 func WithKubernetesMetadataLabels(obj ObjectWithMetadata, userSetLabels map[string]string) map[string]string {
 	labels := map[string]string{
 		KubernetesNameLabelKey:       obj.GetName(),
-		KubernetesUIDLabelKey:        string(obj.GetUID()),
 		KubernetesGenerationLabelKey: fmt.Sprintf("%d", obj.GetGeneration()),
 		KubernetesKindLabelKey:       obj.GetObjectKind().GroupVersionKind().Kind,
 		KubernetesGroupLabelKey:      obj.GetObjectKind().GroupVersionKind().GroupVersion().Group,
 		KubernetesVersionLabelKey:    obj.GetObjectKind().GroupVersionKind().GroupVersion().Version,
 	}
+	if uid := obj.GetUID(); uid != "" {
+		labels[KubernetesUIDLabelKey] = string(uid)
+	}
 	if k8sNamespace := obj.GetNamespace(); k8sNamespace != "" {
 		labels[KubernetesNamespaceLabelKey] = k8sNamespace
 	}
@@ -203,6 +203,10 @@ func Create[
 		SetKonnectEntityProgrammedConditionFalse(e, kcfgkonnect.KonnectEntitiesFailedToCreateReason, err)
 	default:
 		SetKonnectEntityProgrammedConditionTrue(e)
+		// The status update triggers another reconciliation which polls
+		// the provisioning state so the poll interval is not needed here.
+		// Resources which are not provisioned yet are not programmed.
+		_ = setCloudGatewayProvisionedCondition(e, sdk.GetServerURL(), metricRecorder, start)
 	}
 
 	if err != nil {
@@ -240,6 +244,8 @@ func Create[
 }
 
 // Delete deletes a Konnect entity.
+// When the entity's deletion policy (see GetDeletionPolicy) is DeletionPolicyOrphan,
+// the entity is not deleted but orphaned instead.
 // It returns an error if the entity does not have a Konnect ID or if the operation fails.
 func Delete[
 	T constraints.SupportedKonnectEntityType,
@@ -256,8 +262,15 @@ func Delete[
 		return nil
 	}
 
+	policy, err := GetDeletionPolicy(ctx, cl, ent)
+	if err != nil {
+		return err
+	}
+	if policy == DeletionPolicyOrphan {
+		return orphan(ctx, sdk, ent)
+	}
+
 	var (
-		err        error
 		start      = time.Now()
 		entityType = ent.GetTypeName()
 		statusCode int
@@ -396,8 +409,25 @@ func Update[
 ) (ctrl.Result, error) {
 	now := time.Now()
 
-	if ok, res := shouldUpdate(ctx, e, syncPeriod, now); !ok {
-		return res, nil
+	// Konnect Cloud Gateway resources which are not ready are polled
+	// for their state regardless of the sync period.
+	polling := isCloudGatewayPolling(e)
+	if !polling {
+		if ok, res := shouldUpdate(ctx, e, syncPeriod, now); !ok {
+			// Members of control plane groups are enforced regardless of the sync period
+			// as changes of control planes declaring to be members of a group
+			// do not change the group's generation. They're only set when they
+			// differ from the current members in Konnect.
+			if cp, isCP := any(e).(*konnectv1alpha1.KonnectGatewayControlPlane); isCP {
+				if err := ensureGroupMembers(ctx, cl, cp, cp.GetKonnectID(), sdk.GetControlPlaneGroupSDK()); err != nil {
+					SetKonnectEntityProgrammedConditionFalse(e,
+						konnectv1alpha1.KonnectGatewayControlPlaneProgrammedReasonFailedToSetControlPlaneGroupMembers, err,
+					)
+					return ctrl.Result{}, err
+				}
+			}
+			return res, nil
+		}
 	}
 
 	if e.GetKonnectStatus().GetKonnectID() == "" {
@@ -422,7 +452,11 @@ func Update[
 	case *konnectv1alpha1.KonnectCloudGatewayNetwork:
 		err = updateKonnectNetwork(ctx, sdk.GetCloudGatewaysSDK(), ent)
 	case *konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfiguration:
-		err = updateKonnectDataPlaneGroupConfiguration(ctx, sdk.GetCloudGatewaysSDK(), cl, ent, sdk.GetServer())
+		if polling {
+			err = getKonnectDataPlaneGroupConfiguration(ctx, sdk.GetCloudGatewaysSDK(), ent)
+		} else {
+			err = updateKonnectDataPlaneGroupConfiguration(ctx, sdk.GetCloudGatewaysSDK(), cl, ent, sdk.GetServer())
+		}
 	case *konnectv1alpha1.KonnectCloudGatewayTransitGateway:
 		err = updateKonnectTransitGateway(ctx, sdk.GetCloudGatewaysSDK(), ent)
 	case *configurationv1alpha1.KongService:
@@ -484,10 +518,21 @@ func Update[
 		SetKonnectEntityProgrammedConditionFalse(e, errRelationsFailed.Reason, err)
 	case err != nil:
 		SetKonnectEntityProgrammedConditionFalse(e, kcfgkonnect.KonnectEntitiesFailedToUpdateReason, err)
+	case k8sutils.HasConditionFalse(ControlPlaneGroupMembershipConditionType, e):
+		// Control planes which failed to join the control plane group they declare
+		// to be members of are not programmed. Their Programmed condition is set
+		// based on their other conditions so it's left as is.
 	default:
 		SetKonnectEntityProgrammedConditionTrue(e)
 	}
 
+	var res ctrl.Result
+	if err == nil {
+		if pollInterval := setCloudGatewayProvisionedCondition(e, sdk.GetServerURL(), metricRecorder, now); pollInterval > 0 {
+			res.RequeueAfter = min(pollInterval, syncPeriod)
+		}
+	}
+
 	if err != nil {
 		metricRecorder.RecordKonnectEntityOperationFailure(
 			sdk.GetServerURL(),
@@ -519,7 +564,7 @@ func Update[
 
 	logOpComplete(ctx, start, UpdateOp, e, err)
 
-	return ctrl.Result{}, IgnoreUnrecoverableAPIErr(err, loggerForEntity(ctx, e, UpdateOp))
+	return res, IgnoreUnrecoverableAPIErr(err, loggerForEntity(ctx, e, UpdateOp))
 }
 
 func loggerForEntity[
@@ -0,0 +1,308 @@
+package ops
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	"k8s.io/apimachinery/pkg/types"
+
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+// BulkSyncObjects holds the objects whose entities in a Konnect ControlPlane
+// are synced in bulk. All of them are expected to have a Konnect ID set in status.
+type BulkSyncObjects struct {
+	Services  []*configurationv1alpha1.KongService
+	Routes    []*configurationv1alpha1.KongRoute
+	Consumers []*configurationv1.KongConsumer
+}
+
+// BulkSyncResult describes the outcome of BulkSync.
+type BulkSyncResult struct {
+	// Applied is the number of Konnect entities which were created or updated.
+	Applied int
+	// Pending is the number of Konnect entities which differ from their
+	// objects but were not updated because the batch size was reached.
+	Pending int
+}
+
+// bulkSyncObject is an object whose entity is synced in bulk.
+type bulkSyncObject interface {
+	entityType
+	GetUID() types.UID
+	GetKonnectStatus() *konnectv1alpha1.KonnectEntityStatus
+	SetKonnectID(string)
+}
+
+// Fields of the entities which Konnect does not default: they are owned by
+// the objects so unset desired values are compared with the actual ones
+// (see isKonnectEntityInSync).
+var (
+	bulkSyncServiceOwnedFields  = []string{"name", "path"}
+	bulkSyncRouteOwnedFields    = []string{"name", "hosts", "paths", "methods", "headers", "snis", "sources", "destinations"}
+	bulkSyncConsumerOwnedFields = []string{"username", "custom_id"}
+)
+
+// bulkSyncChange is a change of a Konnect entity computed by BulkSync.
+type bulkSyncChange struct {
+	obj   bulkSyncObject
+	apply func(context.Context) error
+}
+
+// BulkSync syncs the entities of the provided objects with the Konnect ControlPlane
+// in bulk: the ControlPlane's services, routes and consumers are listed at once,
+// the entities matching the objects (by their UID tags) are compared with the
+// desired state computed from the objects and only the differing or missing
+// entities are upserted, at most batchSize of them.
+//
+// The objects' Konnect IDs are updated with the IDs of the matching entities and
+// their Programmed conditions are set according to the outcome. The objects'
+// status is not persisted, that's up to the caller.
+// Consumer group assignments of consumers are not synced.
+func BulkSync(
+	ctx context.Context,
+	sdk sdkops.SDKWrapper,
+	cpID string,
+	objs BulkSyncObjects,
+	batchSize int,
+) (BulkSyncResult, error) {
+	services, routes, consumers, err := listServicesRoutesAndConsumers(ctx, sdk, cpID)
+	if err != nil {
+		return BulkSyncResult{}, err
+	}
+
+	var (
+		changes        []bulkSyncChange
+		servicesByUID  = entitiesByUID(services)
+		routesByUID    = entitiesByUID(routes)
+		consumersByUID = entitiesByUID(consumers)
+	)
+	for _, svc := range objs.Services {
+		desired := kongServiceToSDKServiceInput(svc)
+		// URL is write-only, Konnect returns the protocol, host, port and path set from it.
+		desired.URL = nil
+		if diffBulkSyncEntity(svc, desired, servicesByUID, bulkSyncServiceOwnedFields...) {
+			changes = append(changes, bulkSyncChange{
+				obj: svc,
+				apply: func(ctx context.Context) error {
+					return updateService(ctx, sdk.GetServicesSDK(), svc)
+				},
+			})
+		}
+	}
+	for _, route := range objs.Routes {
+		if diffBulkSyncEntity(route, kongRouteToSDKRouteInput(route).RouteJSON, routesByUID, bulkSyncRouteOwnedFields...) {
+			changes = append(changes, bulkSyncChange{
+				obj: route,
+				apply: func(ctx context.Context) error {
+					return updateRoute(ctx, sdk.GetRoutesSDK(), route)
+				},
+			})
+		}
+	}
+	for _, consumer := range objs.Consumers {
+		if diffBulkSyncEntity(consumer, kongConsumerToSDKConsumerInput(consumer), consumersByUID, bulkSyncConsumerOwnedFields...) {
+			changes = append(changes, bulkSyncChange{
+				obj: consumer,
+				apply: func(ctx context.Context) error {
+					return upsertConsumer(ctx, sdk.GetConsumersSDK(), consumer)
+				},
+			})
+		}
+	}
+
+	var (
+		result = BulkSyncResult{
+			Pending: max(len(changes)-batchSize, 0),
+		}
+		errs []error
+	)
+	for _, change := range changes[:len(changes)-result.Pending] {
+		if err := change.apply(ctx); err != nil {
+			SetKonnectEntityProgrammedConditionFalse(change.obj, konnectv1alpha1.KonnectEntityProgrammedReasonKonnectAPIOpFailed, err)
+			errs = append(errs, err)
+			continue
+		}
+		SetKonnectEntityProgrammedConditionTrue(change.obj)
+		result.Applied++
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// entitiesByUID returns the provided entities keyed by the UIDs of the objects
+// they were created for. Entities which were not created for objects are skipped.
+func entitiesByUID[
+	T any,
+	TPtr interface {
+		*T
+		entityWithTags
+	},
+](entities []T) map[types.UID]T {
+	ret := make(map[types.UID]T, len(entities))
+	for _, e := range entities {
+		if m, ok := objectMetadataFromTags(TPtr(&e).GetTags()); ok {
+			ret[m.GetUID()] = e
+		}
+	}
+	return ret
+}
+
+// diffBulkSyncEntity looks for the entity created for the provided object among
+// the listed entities and returns true when it's missing or differs from the
+// desired one.
+// When the entity exists and is up to date, the object's Programmed condition
+// is set to true.
+// The object's Konnect ID is updated with the ID of the found entity.
+func diffBulkSyncEntity[
+	T any,
+	TPtr interface {
+		*T
+		entityWithTags
+	},
+](
+	obj bulkSyncObject,
+	desired any,
+	entities map[types.UID]T,
+	ownedFields ...string,
+) bool {
+	entity, found := entities[obj.GetUID()]
+	if !found {
+		return true
+	}
+	if id := TPtr(&entity).GetID(); id != nil && *id != obj.GetKonnectStatus().GetKonnectID() {
+		obj.SetKonnectID(*id)
+	}
+
+	inSync, err := isKonnectEntityInSync(desired, entity, ownedFields...)
+	if err != nil || !inSync {
+		return true
+	}
+	SetKonnectEntityProgrammedConditionTrue(obj)
+	return false
+}
+
+// isKonnectEntityInSync returns true when all the fields set (to non-zero values)
+// in the desired entity are equal to the ones of the actual entity, as serialized to JSON.
+// Fields which are only set in the actual entity (e.g. IDs, timestamps or
+// defaults applied by Konnect) are not compared: an empty desired value
+// (e.g. an empty string or list) is considered unset and matches any actual value.
+// The top level ownedFields are the exception: Konnect does not default them so
+// they're not in sync when they're unset in the desired entity but set in the actual one.
+func isKonnectEntityInSync(desired, actual any, ownedFields ...string) (bool, error) {
+	toJSONValue := func(v any) (any, error) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var ret any
+		if err := json.Unmarshal(b, &ret); err != nil {
+			return nil, err
+		}
+		return ret, nil
+	}
+
+	d, err := toJSONValue(desired)
+	if err != nil {
+		return false, fmt.Errorf("failed to serialize desired entity: %w", err)
+	}
+	a, err := toJSONValue(actual)
+	if err != nil {
+		return false, fmt.Errorf("failed to serialize actual entity: %w", err)
+	}
+	// Desired tags are sorted (see GenerateTagsForObject) while the order
+	// of the tags returned by Konnect is not relevant.
+	if m, ok := a.(map[string]any); ok {
+		if tags, ok := m["tags"].([]any); ok {
+			slices.SortFunc(tags, func(x, y any) int {
+				return cmp.Compare(fmt.Sprint(x), fmt.Sprint(y))
+			})
+		}
+	}
+	if !isJSONSubset(d, a) {
+		return false, nil
+	}
+	for _, f := range ownedFields {
+		var desiredValue, actualValue any
+		if m, ok := d.(map[string]any); ok {
+			desiredValue = m[f]
+		}
+		if m, ok := a.(map[string]any); ok {
+			actualValue = m[f]
+		}
+		if isJSONZero(desiredValue) && !isJSONZero(actualValue) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// isJSONSubset returns true when the desired JSON value is a subset of the actual one.
+// Zero values in the desired value are considered unset.
+func isJSONSubset(desired, actual any) bool {
+	if isJSONZero(desired) {
+		return true
+	}
+	d, ok := desired.(map[string]any)
+	if !ok {
+		return reflect.DeepEqual(desired, actual)
+	}
+	a, ok := actual.(map[string]any)
+	if !ok {
+		return false
+	}
+	for k, v := range d {
+		if !isJSONSubset(v, a[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isJSONZero returns true for JSON values which are equivalent to unset fields.
+func isJSONZero(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// upsertConsumer upserts the Konnect Consumer entity of the provided KongConsumer
+// without handling its consumer group assignments.
+func upsertConsumer(
+	ctx context.Context,
+	sdk sdkops.ConsumersSDK,
+	consumer *configurationv1.KongConsumer,
+) error {
+	cpID := consumer.GetControlPlaneID()
+	if cpID == "" {
+		return CantPerformOperationWithoutControlPlaneIDError{Entity: consumer, Op: UpdateOp}
+	}
+
+	_, err := sdk.UpsertConsumer(ctx,
+		sdkkonnectops.UpsertConsumerRequest{
+			ControlPlaneID: cpID,
+			ConsumerID:     consumer.GetKonnectStatus().GetKonnectID(),
+			Consumer:       kongConsumerToSDKConsumerInput(consumer),
+		},
+	)
+	return wrapErrIfKonnectOpFailed(err, UpdateOp, consumer)
+}
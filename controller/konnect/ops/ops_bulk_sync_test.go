@@ -0,0 +1,196 @@
+package ops
+
+import (
+	"testing"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestBulkSync(t *testing.T) {
+	const cpID = "cp-id"
+
+	newService := func(name, host string) *configurationv1alpha1.KongService {
+		svc := &configurationv1alpha1.KongService{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: configurationv1alpha1.GroupVersion.String(),
+				Kind:       "KongService",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  "default",
+				UID:        types.UID("uid-" + name),
+				Generation: 1,
+			},
+			Spec: configurationv1alpha1.KongServiceSpec{
+				KongServiceAPISpec: configurationv1alpha1.KongServiceAPISpec{
+					Host: host,
+				},
+			},
+		}
+		svc.SetControlPlaneID(cpID)
+		svc.SetKonnectID("id-" + name)
+		return svc
+	}
+	inSyncSvc := newService("in-sync", "in-sync.example.com")
+	driftedSvc := newService("drifted", "drifted.example.com")
+	missingSvc := newService("missing", "missing.example.com")
+	consumer := &configurationv1.KongConsumer{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configurationv1.GroupVersion.String(),
+			Kind:       "KongConsumer",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "consumer",
+			Namespace:  "default",
+			UID:        "uid-consumer",
+			Generation: 1,
+		},
+		Username: "alice",
+	}
+	consumer.SetControlPlaneID(cpID)
+	consumer.SetKonnectID("stale-id")
+
+	sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+	sdk.ServicesSDK.EXPECT().ListService(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListServiceResponse{
+			Object: &sdkkonnectops.ListServiceResponseBody{
+				Data: []sdkkonnectcomp.ServiceOutput{
+					{
+						ID:       lo.ToPtr("id-in-sync"),
+						Host:     "in-sync.example.com",
+						Port:     lo.ToPtr(int64(80)),
+						Protocol: lo.ToPtr(sdkkonnectcomp.ProtocolHTTP),
+						// Tags are compared regardless of their order.
+						Tags: lo.Reverse(GenerateTagsForObject(inSyncSvc)),
+					},
+					{
+						ID:   lo.ToPtr("id-drifted"),
+						Host: "changed-in-konnect.example.com",
+						Tags: GenerateTagsForObject(driftedSvc),
+					},
+				},
+			},
+		}, nil).
+		Once()
+	sdk.RoutesSDK.EXPECT().ListRoute(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListRouteResponse{
+			Object: &sdkkonnectops.ListRouteResponseBody{},
+		}, nil).
+		Once()
+	sdk.ConsumersSDK.EXPECT().ListConsumer(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListConsumerResponse{
+			Object: &sdkkonnectops.ListConsumerResponseBody{
+				Data: []sdkkonnectcomp.Consumer{
+					{
+						ID:       lo.ToPtr("consumer-id"),
+						Username: lo.ToPtr("alice"),
+						Tags:     GenerateTagsForObject(consumer),
+					},
+				},
+			},
+		}, nil).
+		Once()
+	sdk.ServicesSDK.EXPECT().
+		UpsertService(mock.Anything, mock.MatchedBy(func(req sdkkonnectops.UpsertServiceRequest) bool {
+			return req.ServiceID == "id-drifted" && req.Service.Host == "drifted.example.com"
+		})).
+		Return(&sdkkonnectops.UpsertServiceResponse{}, nil).
+		Once()
+
+	result, err := BulkSync(t.Context(), sdk, cpID, BulkSyncObjects{
+		Services:  []*configurationv1alpha1.KongService{inSyncSvc, driftedSvc, missingSvc},
+		Consumers: []*configurationv1.KongConsumer{consumer},
+	}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, BulkSyncResult{Applied: 1, Pending: 1}, result)
+
+	for _, svc := range []*configurationv1alpha1.KongService{inSyncSvc, driftedSvc} {
+		assert.True(t, k8sutils.HasConditionTrue(konnectv1alpha1.KonnectEntityProgrammedConditionType, svc), svc.Name)
+	}
+	_, ok := k8sutils.GetCondition(konnectv1alpha1.KonnectEntityProgrammedConditionType, missingSvc)
+	assert.False(t, ok, "changes exceeding the batch size should not be applied")
+	assert.Equal(t, "consumer-id", consumer.GetKonnectID(), "Konnect ID should be written back")
+	assert.True(t, k8sutils.HasConditionTrue(konnectv1alpha1.KonnectEntityProgrammedConditionType, consumer))
+}
+
+func TestIsKonnectEntityInSync(t *testing.T) {
+	testCases := []struct {
+		name        string
+		desired     any
+		actual      any
+		ownedFields []string
+		expected    bool
+	}{
+		{
+			name:     "fields set only in the actual entity are ignored",
+			desired:  sdkkonnectcomp.Consumer{Username: lo.ToPtr("alice"), CustomID: lo.ToPtr("")},
+			actual:   sdkkonnectcomp.Consumer{ID: lo.ToPtr("id"), Username: lo.ToPtr("alice"), CreatedAt: lo.ToPtr(int64(1))},
+			expected: true,
+		},
+		{
+			name:     "differing field",
+			desired:  sdkkonnectcomp.Consumer{Username: lo.ToPtr("alice")},
+			actual:   sdkkonnectcomp.Consumer{Username: lo.ToPtr("bob")},
+			expected: false,
+		},
+		{
+			name:     "missing field",
+			desired:  sdkkonnectcomp.RouteJSON{Paths: []string{"/a"}},
+			actual:   sdkkonnectcomp.RouteJSON{},
+			expected: false,
+		},
+		{
+			name:     "differing list",
+			desired:  sdkkonnectcomp.RouteJSON{Paths: []string{"/a", "/b"}},
+			actual:   sdkkonnectcomp.RouteJSON{Paths: []string{"/a"}},
+			expected: false,
+		},
+		{
+			name: "nested fields",
+			desired: sdkkonnectcomp.RouteJSON{
+				Service: &sdkkonnectcomp.RouteJSONService{ID: lo.ToPtr("svc")},
+			},
+			actual: sdkkonnectcomp.RouteJSON{
+				Service: &sdkkonnectcomp.RouteJSONService{ID: lo.ToPtr("svc")},
+				Paths:   []string{"/a"},
+			},
+			expected: true,
+		},
+		{
+			name:        "owned field unset in the desired entity",
+			desired:     sdkkonnectcomp.RouteJSON{Paths: []string{"/a"}},
+			actual:      sdkkonnectcomp.RouteJSON{Paths: []string{"/a"}, Hosts: []string{"example.com"}},
+			ownedFields: bulkSyncRouteOwnedFields,
+			expected:    false,
+		},
+		{
+			name:        "owned field unset in both entities",
+			desired:     sdkkonnectcomp.RouteJSON{Paths: []string{"/a"}},
+			actual:      sdkkonnectcomp.RouteJSON{Paths: []string{"/a"}, Protocols: []sdkkonnectcomp.RouteJSONProtocols{"https"}},
+			ownedFields: bulkSyncRouteOwnedFields,
+			expected:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inSync, err := isKonnectEntityInSync(tc.desired, tc.actual, tc.ownedFields...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, inSync)
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package ops
+
+import (
+	"fmt"
+	"time"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/gateway-operator/controller/konnect/constraints"
+	"github.com/kong/gateway-operator/internal/metrics"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+const (
+	// CloudGatewayProvisionedConditionType is the condition type mirroring the
+	// provisioning state of Konnect Cloud Gateway resources: networks,
+	// transit gateways and data plane group configurations.
+	CloudGatewayProvisionedConditionType kcfgconsts.ConditionType = "Provisioned"
+	// CloudGatewayProvisionedReasonInitializing indicates that the resource
+	// is still being provisioned in Konnect.
+	CloudGatewayProvisionedReasonInitializing kcfgconsts.ConditionReason = "Initializing"
+	// CloudGatewayProvisionedReasonReady indicates that the resource
+	// is provisioned and ready to be used.
+	CloudGatewayProvisionedReasonReady kcfgconsts.ConditionReason = "Ready"
+	// CloudGatewayProvisionedReasonError indicates that the resource is in a state
+	// which does not allow it to be used, e.g. an offline network.
+	CloudGatewayProvisionedReasonError kcfgconsts.ConditionReason = "Error"
+	// CloudGatewayProvisionedReasonTerminating indicates that the resource
+	// is being terminated in Konnect.
+	CloudGatewayProvisionedReasonTerminating kcfgconsts.ConditionReason = "Terminating"
+
+	// KonnectEntityProgrammedReasonNotProvisioned indicates that the Konnect Cloud
+	// Gateway resource has been accepted by Konnect but it is not provisioned yet.
+	KonnectEntityProgrammedReasonNotProvisioned kcfgconsts.ConditionReason = "NotProvisioned"
+)
+
+const (
+	// MinProvisioningPollInterval is the interval at which Konnect Cloud Gateway
+	// resources are polled right after they started provisioning.
+	MinProvisioningPollInterval = 5 * time.Second
+	// MaxProvisioningPollInterval is the maximum interval at which Konnect Cloud Gateway
+	// resources are polled while they are provisioning.
+	MaxProvisioningPollInterval = time.Minute
+)
+
+// ProvisioningPollInterval returns the interval after which a Konnect Cloud Gateway
+// resource that has been provisioning for the provided duration should be polled.
+// The interval grows with the provisioning duration so that long running
+// provisioning does not flood Konnect with requests.
+func ProvisioningPollInterval(provisioningFor time.Duration) time.Duration {
+	return min(max(provisioningFor/2, MinProvisioningPollInterval), MaxProvisioningPollInterval)
+}
+
+// provisioningStateToReason maps a Konnect Cloud Gateway resource state
+// to the reason of the Provisioned condition.
+func provisioningStateToReason(state string) kcfgconsts.ConditionReason {
+	switch state {
+	case string(sdkkonnectcomp.NetworkStateReady):
+		return CloudGatewayProvisionedReasonReady
+	case string(sdkkonnectcomp.NetworkStateCreated),
+		string(sdkkonnectcomp.NetworkStateInitializing),
+		string(sdkkonnectcomp.TransitGatewayStatePendingAcceptance):
+		return CloudGatewayProvisionedReasonInitializing
+	case string(sdkkonnectcomp.NetworkStateTerminating),
+		string(sdkkonnectcomp.NetworkStateTerminated):
+		return CloudGatewayProvisionedReasonTerminating
+	default:
+		return CloudGatewayProvisionedReasonError
+	}
+}
+
+// provisioningReasonPriority is used to aggregate the states of data plane groups:
+// the reason with the highest priority determines the reason of the configuration.
+var provisioningReasonPriority = map[kcfgconsts.ConditionReason]int{
+	CloudGatewayProvisionedReasonReady:        0,
+	CloudGatewayProvisionedReasonInitializing: 1,
+	CloudGatewayProvisionedReasonTerminating:  2,
+	CloudGatewayProvisionedReasonError:        3,
+}
+
+// cloudGatewayProvisioningReason returns the reason of the Provisioned condition
+// for the provided entity together with the state it has been derived from.
+// It returns false when the entity is not a Konnect Cloud Gateway resource
+// or when its state is not known yet.
+func cloudGatewayProvisioningReason(e any) (kcfgconsts.ConditionReason, string, bool) {
+	switch ent := e.(type) {
+	case *konnectv1alpha1.KonnectCloudGatewayNetwork:
+		if ent.Status.State == "" {
+			return "", "", false
+		}
+		return provisioningStateToReason(ent.Status.State), ent.Status.State, true
+	case *konnectv1alpha1.KonnectCloudGatewayTransitGateway:
+		if ent.Status.State == "" {
+			return "", "", false
+		}
+		return provisioningStateToReason(string(ent.Status.State)), string(ent.Status.State), true
+	case *konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfiguration:
+		// Data plane groups are reported once Konnect starts provisioning them.
+		if len(ent.Status.DataPlaneGroups) == 0 {
+			return CloudGatewayProvisionedReasonInitializing, "", true
+		}
+		var (
+			reason = CloudGatewayProvisionedReasonReady
+			state  = string(sdkkonnectcomp.StateReady)
+		)
+		for _, g := range ent.Status.DataPlaneGroups {
+			if r := provisioningStateToReason(g.State); provisioningReasonPriority[r] > provisioningReasonPriority[reason] {
+				reason, state = r, g.State
+			}
+		}
+		return reason, state, true
+	default:
+		return "", "", false
+	}
+}
+
+// isCloudGatewayPolling returns true when the entity is a Konnect Cloud Gateway
+// resource which is not ready yet and whose spec has not changed since its
+// provisioning state has been observed.
+// Such entities are only read from Konnect: their configuration is submitted
+// again only when their spec changes.
+func isCloudGatewayPolling(e entityType) bool {
+	cond, ok := k8sutils.GetCondition(CloudGatewayProvisionedConditionType, e)
+	return ok &&
+		cond.ObservedGeneration == e.GetGeneration() &&
+		cond.Reason != string(CloudGatewayProvisionedReasonReady)
+}
+
+// setCloudGatewayProvisionedCondition mirrors the provisioning state of Konnect Cloud
+// Gateway resources into the Provisioned condition and records how long the
+// provisioning took once the resource becomes ready.
+// Resources which are not provisioned yet have their Programmed condition set
+// to false as they cannot be used until then.
+// It returns the interval after which the entity should be polled again, which
+// is zero when the entity is not provisioning.
+func setCloudGatewayProvisionedCondition[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](
+	e TEnt,
+	serverURL string,
+	metricRecorder metrics.Recorder,
+	now time.Time,
+) time.Duration {
+	reason, state, ok := cloudGatewayProvisioningReason(e)
+	if !ok {
+		return 0
+	}
+
+	var (
+		typeName = e.GetTypeName()
+		status   = metav1.ConditionFalse
+		msg      string
+	)
+	switch reason {
+	case CloudGatewayProvisionedReasonReady:
+		status = metav1.ConditionTrue
+		msg = fmt.Sprintf("%s is ready", typeName)
+	case CloudGatewayProvisionedReasonInitializing:
+		msg = fmt.Sprintf("%s is being provisioned in Konnect", typeName)
+	case CloudGatewayProvisionedReasonTerminating:
+		msg = fmt.Sprintf("%s is being terminated in Konnect", typeName)
+	default:
+		msg = fmt.Sprintf("%s is in unexpected state %q", typeName, state)
+	}
+
+	prev, hadPrev := k8sutils.GetCondition(CloudGatewayProvisionedConditionType, e)
+	if hadPrev &&
+		prev.Reason == string(CloudGatewayProvisionedReasonInitializing) &&
+		reason == CloudGatewayProvisionedReasonReady {
+		metricRecorder.RecordKonnectCloudGatewayProvisioningDuration(
+			serverURL, typeName, now.Sub(prev.LastTransitionTime.Time),
+		)
+	}
+
+	_setKonnectEntityConditon(e, CloudGatewayProvisionedConditionType, status, reason, msg)
+	if status != metav1.ConditionTrue {
+		_setKonnectEntityConditon(e, konnectv1alpha1.KonnectEntityProgrammedConditionType,
+			metav1.ConditionFalse, KonnectEntityProgrammedReasonNotProvisioned, msg,
+		)
+	}
+
+	if reason != CloudGatewayProvisionedReasonInitializing && reason != CloudGatewayProvisionedReasonTerminating {
+		return 0
+	}
+	cond, _ := k8sutils.GetCondition(CloudGatewayProvisionedConditionType, e)
+	return ProvisioningPollInterval(now.Sub(cond.LastTransitionTime.Time))
+}
@@ -0,0 +1,272 @@
+package ops
+
+import (
+	"testing"
+	"time"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestProvisioningPollInterval(t *testing.T) {
+	assert.Equal(t, MinProvisioningPollInterval, ProvisioningPollInterval(0))
+	assert.Equal(t, 10*time.Second, ProvisioningPollInterval(20*time.Second))
+	assert.Equal(t, MaxProvisioningPollInterval, ProvisioningPollInterval(time.Hour))
+}
+
+func TestCloudGatewayProvisioningReason(t *testing.T) {
+	testCases := []struct {
+		name           string
+		entity         any
+		expectedReason string
+		expectedOK     bool
+	}{
+		{
+			name:   "network without state",
+			entity: &konnectv1alpha1.KonnectCloudGatewayNetwork{},
+		},
+		{
+			name: "offline network",
+			entity: &konnectv1alpha1.KonnectCloudGatewayNetwork{
+				Status: konnectv1alpha1.KonnectCloudGatewayNetworkStatus{
+					State: string(sdkkonnectcomp.NetworkStateOffline),
+				},
+			},
+			expectedReason: string(CloudGatewayProvisionedReasonError),
+			expectedOK:     true,
+		},
+		{
+			name: "transit gateway pending acceptance",
+			entity: &konnectv1alpha1.KonnectCloudGatewayTransitGateway{
+				Status: konnectv1alpha1.KonnectCloudGatewayTransitGatewayStatus{
+					State: sdkkonnectcomp.TransitGatewayStatePendingAcceptance,
+				},
+			},
+			expectedReason: string(CloudGatewayProvisionedReasonInitializing),
+			expectedOK:     true,
+		},
+		{
+			name: "data plane group configuration with a group still initializing",
+			entity: &konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfiguration{
+				Status: konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfigurationStatus{
+					DataPlaneGroups: []konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfigurationStatusGroup{
+						{State: string(sdkkonnectcomp.StateReady)},
+						{State: string(sdkkonnectcomp.StateInitializing)},
+					},
+				},
+			},
+			expectedReason: string(CloudGatewayProvisionedReasonInitializing),
+			expectedOK:     true,
+		},
+		{
+			name: "data plane group configuration with all groups ready",
+			entity: &konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfiguration{
+				Status: konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfigurationStatus{
+					DataPlaneGroups: []konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfigurationStatusGroup{
+						{State: string(sdkkonnectcomp.StateReady)},
+						{State: string(sdkkonnectcomp.StateReady)},
+					},
+				},
+			},
+			expectedReason: string(CloudGatewayProvisionedReasonReady),
+			expectedOK:     true,
+		},
+		{
+			name:   "not a Cloud Gateway resource",
+			entity: &konnectv1alpha1.KonnectGatewayControlPlane{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, _, ok := cloudGatewayProvisioningReason(tc.entity)
+			require.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedReason, string(reason))
+		})
+	}
+}
+
+func TestUpdate_PollsProvisioningCloudGatewayNetwork(t *testing.T) {
+	network := &konnectv1alpha1.KonnectCloudGatewayNetwork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "network",
+			Namespace:  "default",
+			Generation: 1,
+		},
+		Status: konnectv1alpha1.KonnectCloudGatewayNetworkStatus{
+			KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+				ID: "network-id",
+			},
+			State: string(sdkkonnectcomp.NetworkStateInitializing),
+			Conditions: []metav1.Condition{
+				{
+					Type:               konnectv1alpha1.KonnectEntityProgrammedConditionType,
+					Status:             metav1.ConditionTrue,
+					Reason:             konnectv1alpha1.KonnectEntityProgrammedReasonProgrammed,
+					ObservedGeneration: 1,
+					LastTransitionTime: metav1.Now(),
+				},
+				{
+					Type:               string(CloudGatewayProvisionedConditionType),
+					Status:             metav1.ConditionFalse,
+					Reason:             string(CloudGatewayProvisionedReasonInitializing),
+					Message:            "KonnectCloudGatewayNetwork is being provisioned in Konnect",
+					ObservedGeneration: 1,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-20 * time.Second)),
+				},
+			},
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+	sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+	expectGetNetwork := func(state sdkkonnectcomp.NetworkState) {
+		sdk.CloudGatewaysSDK.EXPECT().GetNetwork(mock.Anything, "network-id").
+			Return(&sdkkonnectops.GetNetworkResponse{
+				Network: &sdkkonnectcomp.Network{ID: "network-id", State: state},
+			}, nil).
+			Once()
+	}
+
+	t.Log("the network is polled within the sync period while it is initializing")
+	expectGetNetwork(sdkkonnectcomp.NetworkStateInitializing)
+	res, err := Update(t.Context(), sdk, time.Hour, cl, &metrics.MockRecorder{}, network)
+	require.NoError(t, err)
+	assert.InDelta(t, 10*time.Second, res.RequeueAfter, float64(time.Second))
+	programmed, ok := k8sutils.GetCondition(konnectv1alpha1.KonnectEntityProgrammedConditionType, network)
+	require.True(t, ok)
+	assert.Equal(t, metav1.ConditionFalse, programmed.Status)
+	assert.Equal(t, string(KonnectEntityProgrammedReasonNotProvisioned), programmed.Reason)
+
+	t.Log("the network which is not programmed is still polled")
+	expectGetNetwork(sdkkonnectcomp.NetworkStateInitializing)
+	res, err = Update(t.Context(), sdk, time.Hour, cl, &metrics.MockRecorder{}, network)
+	require.NoError(t, err)
+	assert.Positive(t, res.RequeueAfter)
+
+	t.Log("the Provisioned condition becomes true once the network is ready")
+	expectGetNetwork(sdkkonnectcomp.NetworkStateReady)
+	res, err = Update(t.Context(), sdk, time.Hour, cl, &metrics.MockRecorder{}, network)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	cond, ok := k8sutils.GetCondition(CloudGatewayProvisionedConditionType, network)
+	require.True(t, ok)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, string(CloudGatewayProvisionedReasonReady), cond.Reason)
+	assert.True(t, k8sutils.HasConditionTrue(konnectv1alpha1.KonnectEntityProgrammedConditionType, network))
+
+	t.Log("the ready network is not polled again within the sync period")
+	res, err = Update(t.Context(), sdk, time.Hour, cl, &metrics.MockRecorder{}, network)
+	require.NoError(t, err)
+	assert.Positive(t, res.RequeueAfter)
+}
+
+func TestUpdate_PollsProvisioningDataPlaneGroupConfigurationWithoutSubmittingIt(t *testing.T) {
+	cfg := &konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "configuration",
+			Namespace:  "default",
+			Generation: 1,
+		},
+		Spec: konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfigurationSpec{
+			DataplaneGroups: []konnectv1alpha1.KonnectConfigurationDataPlaneGroup{
+				{
+					Provider: sdkkonnectcomp.ProviderNameAws,
+					Region:   "us-west-2",
+				},
+			},
+		},
+		Status: konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfigurationStatus{
+			KonnectEntityStatusWithControlPlaneRef: konnectv1alpha1.KonnectEntityStatusWithControlPlaneRef{
+				KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+					ID: "configuration-id",
+				},
+				ControlPlaneID: "cp-id",
+			},
+			Conditions: []metav1.Condition{
+				{
+					Type:               konnectv1alpha1.KonnectEntityProgrammedConditionType,
+					Status:             metav1.ConditionFalse,
+					Reason:             string(KonnectEntityProgrammedReasonNotProvisioned),
+					ObservedGeneration: 1,
+					LastTransitionTime: metav1.Now(),
+				},
+				{
+					Type:               string(CloudGatewayProvisionedConditionType),
+					Status:             metav1.ConditionFalse,
+					Reason:             string(CloudGatewayProvisionedReasonInitializing),
+					ObservedGeneration: 1,
+					LastTransitionTime: metav1.Now(),
+				},
+			},
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+	sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+	expectGetConfiguration := func(state sdkkonnectcomp.State) {
+		sdk.CloudGatewaysSDK.EXPECT().GetConfiguration(mock.Anything, "configuration-id").
+			Return(&sdkkonnectops.GetConfigurationResponse{
+				ConfigurationManifest: &sdkkonnectcomp.ConfigurationManifest{
+					ID: "configuration-id",
+					DataplaneGroups: []sdkkonnectcomp.ConfigurationDataPlaneGroup{
+						{ID: "group-id", State: state},
+					},
+				},
+			}, nil).
+			Once()
+	}
+
+	t.Log("the configuration is read from Konnect while its data plane groups are initializing")
+	expectGetConfiguration(sdkkonnectcomp.StateInitializing)
+	res, err := Update(t.Context(), sdk, time.Hour, cl, &metrics.MockRecorder{}, cfg)
+	require.NoError(t, err)
+	assert.Positive(t, res.RequeueAfter)
+	assert.False(t, k8sutils.HasConditionTrue(konnectv1alpha1.KonnectEntityProgrammedConditionType, cfg))
+
+	t.Log("the configuration becomes programmed once its data plane groups are ready")
+	expectGetConfiguration(sdkkonnectcomp.StateReady)
+	res, err = Update(t.Context(), sdk, time.Hour, cl, &metrics.MockRecorder{}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.True(t, k8sutils.HasConditionTrue(CloudGatewayProvisionedConditionType, cfg))
+	assert.True(t, k8sutils.HasConditionTrue(konnectv1alpha1.KonnectEntityProgrammedConditionType, cfg))
+
+	sdk.CloudGatewaysSDK.AssertNotCalled(t, "CreateConfiguration", mock.Anything, mock.Anything)
+}
+
+func TestCreate_CloudGatewayNetworkIsNotProgrammedUntilProvisioned(t *testing.T) {
+	network := &konnectv1alpha1.KonnectCloudGatewayNetwork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "network",
+			Namespace:  "default",
+			Generation: 1,
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+	sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+	sdk.CloudGatewaysSDK.EXPECT().CreateNetwork(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.CreateNetworkResponse{
+			Network: &sdkkonnectcomp.Network{ID: "network-id", State: sdkkonnectcomp.NetworkStateInitializing},
+		}, nil)
+
+	_, err := Create(t.Context(), sdk, cl, &metrics.MockRecorder{}, network)
+	require.NoError(t, err)
+	assert.Equal(t, "network-id", network.GetKonnectID())
+	programmed, ok := k8sutils.GetCondition(konnectv1alpha1.KonnectEntityProgrammedConditionType, network)
+	require.True(t, ok)
+	assert.Equal(t, metav1.ConditionFalse, programmed.Status)
+	assert.Equal(t, string(KonnectEntityProgrammedReasonNotProvisioned), programmed.Reason)
+	assert.True(t, isCloudGatewayPolling(network))
+}
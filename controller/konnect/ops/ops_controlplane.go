@@ -2,8 +2,12 @@ package ops
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"slices"
 	"sort"
+	"strings"
 
 	sdkkonnectgo "github.com/Kong/sdk-konnect-go"
 	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
@@ -11,10 +15,14 @@ import (
 	"github.com/samber/lo"
 	"github.com/sourcegraph/conc/iter"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/internal/utils/index"
 
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
 	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
 	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
 )
@@ -175,19 +183,43 @@ func updateControlPlane(
 	return nil
 }
 
+// setGroupMembers sets the members of the control plane group in Konnect to
+// the members listed in its spec and the control planes declaring to be its members.
 func setGroupMembers(
 	ctx context.Context,
 	cl client.Client,
 	cp *konnectv1alpha1.KonnectGatewayControlPlane,
 	id string,
 	sdkGroups sdkops.ControlPlaneGroupSDK,
+) error {
+	return reconcileGroupMembers(ctx, cl, cp, id, sdkGroups, false)
+}
+
+// ensureGroupMembers is like setGroupMembers but it sets the members of the
+// control plane group in Konnect only when they differ from the current ones.
+func ensureGroupMembers(
+	ctx context.Context,
+	cl client.Client,
+	cp *konnectv1alpha1.KonnectGatewayControlPlane,
+	id string,
+	sdkGroups sdkops.ControlPlaneGroupSDK,
+) error {
+	return reconcileGroupMembers(ctx, cl, cp, id, sdkGroups, true)
+}
+
+func reconcileGroupMembers(
+	ctx context.Context,
+	cl client.Client,
+	cp *konnectv1alpha1.KonnectGatewayControlPlane,
+	id string,
+	sdkGroups sdkops.ControlPlaneGroupSDK,
+	onlyWhenChanged bool,
 ) error {
 	// if the source type is Mirror, don't touch the Konnect entity.
 	if isMirrorEntity(cp) {
 		return nil
 	}
-	if cp.Spec.ClusterType == nil ||
-		*cp.Spec.ClusterType != sdkkonnectcomp.CreateControlPlaneRequestClusterTypeClusterTypeControlPlaneGroup {
+	if !IsControlPlaneGroup(cp) {
 		return nil
 	}
 
@@ -227,26 +259,219 @@ func setGroupMembers(
 		return fmt.Errorf("failed to set group members, some members couldn't be found: %w", err)
 	}
 
+	declaredMembers, err := listDeclaredGroupMembers(ctx, cl, cp)
+	if err != nil {
+		return err
+	}
+	for _, m := range declaredMembers {
+		if m.reason != ControlPlaneGroupMembershipReasonAttached {
+			continue
+		}
+		if !lo.ContainsBy(members, func(member sdkkonnectcomp.Members) bool {
+			return member.ID == m.cp.GetKonnectID()
+		}) {
+			members = append(members, sdkkonnectcomp.Members{
+				ID: m.cp.GetKonnectID(),
+			})
+		}
+	}
+
 	sort.Sort(membersByID(members))
-	gm := sdkkonnectcomp.GroupMembership{
-		Members: members,
+	changed := true
+	if onlyWhenChanged {
+		currentIDs, err := getGroupMemberIDs(ctx, sdkGroups, id)
+		if err != nil {
+			return fmt.Errorf("failed to get members of control plane group %s: %w",
+				client.ObjectKeyFromObject(cp), err,
+			)
+		}
+		changed = !slices.Equal(currentIDs, lo.Map(members, func(m sdkkonnectcomp.Members, _ int) string {
+			return m.ID
+		}))
+	}
+	if changed {
+		gm := sdkkonnectcomp.GroupMembership{
+			Members: members,
+		}
+		_, err = sdkGroups.PutControlPlanesIDGroupMemberships(ctx, id, &gm)
 	}
-	_, err = sdkGroups.PutControlPlanesIDGroupMemberships(ctx, id, &gm)
 	if err != nil {
+		for i := range declaredMembers {
+			if declaredMembers[i].reason == ControlPlaneGroupMembershipReasonAttached {
+				declaredMembers[i].reason = ControlPlaneGroupMembershipReasonFailedToSet
+				declaredMembers[i].msg = err.Error()
+			}
+		}
 		SetControlPlaneGroupMembersReferenceResolvedConditionFalse(
 			cp,
 			ControlPlaneGroupMembersReferenceResolvedReasonFailedToSet,
 			err.Error(),
 		)
-		return fmt.Errorf("failed to set members on control plane group %s: %w",
-			client.ObjectKeyFromObject(cp), err,
+		return errors.Join(
+			fmt.Errorf("failed to set members on control plane group %s: %w",
+				client.ObjectKeyFromObject(cp), err,
+			),
+			patchDeclaredGroupMembersStatus(ctx, cl, declaredMembers),
 		)
 	}
 
 	SetControlPlaneGroupMembersReferenceResolvedCondition(
 		cp,
 	)
-	return nil
+	return patchDeclaredGroupMembersStatus(ctx, cl, declaredMembers)
+}
+
+// IsControlPlaneGroup returns true when the KonnectGatewayControlPlane is a control plane group.
+func IsControlPlaneGroup(cp *konnectv1alpha1.KonnectGatewayControlPlane) bool {
+	return cp.Spec.ClusterType != nil &&
+		*cp.Spec.ClusterType == sdkkonnectcomp.CreateControlPlaneRequestClusterTypeClusterTypeControlPlaneGroup
+}
+
+// declaredGroupMember is a KonnectGatewayControlPlane which declares to be a member
+// of a control plane group through the konnect.konghq.com/control-plane-group annotation,
+// along with the state of its membership.
+type declaredGroupMember struct {
+	cp     *konnectv1alpha1.KonnectGatewayControlPlane
+	reason kcfgconsts.ConditionReason
+	msg    string
+}
+
+// listDeclaredGroupMembers lists the KonnectGatewayControlPlanes declaring to be members
+// of the provided control plane group and determines which of them can be attached to it.
+// Control planes listed as members of other groups (in their spec.members) are not
+// attached as that's in conflict with the declared membership.
+// Control planes which are being deleted or are control plane groups themselves are omitted.
+func listDeclaredGroupMembers(
+	ctx context.Context,
+	cl client.Client,
+	group *konnectv1alpha1.KonnectGatewayControlPlane,
+) ([]declaredGroupMember, error) {
+	var l konnectv1alpha1.KonnectGatewayControlPlaneList
+	if err := cl.List(ctx, &l,
+		// TODO: change this when cross namespace refs are allowed.
+		client.InNamespace(group.Namespace),
+		client.MatchingFields{
+			index.IndexFieldKonnectGatewayControlPlaneOnGroupRef: group.Name,
+		},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list control planes declaring to be members of control plane group %s: %w",
+			client.ObjectKeyFromObject(group), err,
+		)
+	}
+
+	ret := make([]declaredGroupMember, 0, len(l.Items))
+	for _, cp := range l.Items {
+		if !cp.DeletionTimestamp.IsZero() || IsControlPlaneGroup(&cp) {
+			continue
+		}
+		m := declaredGroupMember{
+			cp:     &cp,
+			reason: ControlPlaneGroupMembershipReasonAttached,
+		}
+
+		var groups konnectv1alpha1.KonnectGatewayControlPlaneList
+		if err := cl.List(ctx, &groups,
+			client.InNamespace(group.Namespace),
+			client.MatchingFields{
+				index.IndexFieldKonnectGatewayControlPlaneGroupOnMembers: cp.Name,
+			},
+		); err != nil {
+			return nil, fmt.Errorf("failed to list control plane groups of %s: %w",
+				client.ObjectKeyFromObject(&cp), err,
+			)
+		}
+		otherGroups := lo.FilterMap(groups.Items, func(g konnectv1alpha1.KonnectGatewayControlPlane, _ int) (string, bool) {
+			return g.Name, g.Name != group.Name
+		})
+
+		switch {
+		case len(otherGroups) > 0:
+			m.reason = ControlPlaneGroupMembershipReasonConflict
+			m.msg = fmt.Sprintf("control plane is listed as a member of control plane group(s) %s",
+				strings.Join(otherGroups, ", "),
+			)
+		case cp.GetKonnectID() == "":
+			m.reason = ControlPlaneGroupMembershipReasonPending
+			m.msg = "control plane is not created in Konnect yet"
+		}
+		ret = append(ret, m)
+	}
+	return ret, nil
+}
+
+// patchDeclaredGroupMembersStatus patches the ControlPlaneGroupMembership condition
+// of the provided control planes declaring to be members of a control plane group.
+func patchDeclaredGroupMembersStatus(
+	ctx context.Context,
+	cl client.Client,
+	members []declaredGroupMember,
+) error {
+	var errs []error
+	for _, m := range members {
+		status := metav1.ConditionFalse
+		if m.reason == ControlPlaneGroupMembershipReasonAttached {
+			status = metav1.ConditionTrue
+		}
+		if _, err := patch.StatusWithCondition(
+			ctx, cl, m.cp,
+			ControlPlaneGroupMembershipConditionType,
+			status,
+			m.reason,
+			m.msg,
+		); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// groupMembershipsPageSize is the page size used when listing the members of
+// control plane groups.
+const groupMembershipsPageSize = 100
+
+// getGroupMemberIDs returns the sorted Konnect IDs of the current members of
+// the control plane group with the provided Konnect ID.
+func getGroupMemberIDs(
+	ctx context.Context,
+	sdkGroups sdkops.ControlPlaneGroupSDK,
+	id string,
+) ([]string, error) {
+	var (
+		ids       []string
+		pageAfter *string
+	)
+	for {
+		resp, err := sdkGroups.GetControlPlanesIDGroupMemberships(ctx, sdkkonnectops.GetControlPlanesIDGroupMembershipsRequest{
+			ID:        id,
+			PageSize:  lo.ToPtr(int64(groupMembershipsPageSize)),
+			PageAfter: pageAfter,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil || resp.ListGroupMemberships == nil {
+			return nil, ErrNilResponse
+		}
+		for _, member := range resp.ListGroupMemberships.Data {
+			ids = append(ids, member.ID)
+		}
+
+		next := resp.ListGroupMemberships.Meta.Page.Next
+		if next == nil || *next == "" {
+			break
+		}
+		nextURL, err := url.Parse(*next)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse next page URL %q: %w", *next, err)
+		}
+		after := nextURL.Query().Get("page[after]")
+		if after == "" {
+			break
+		}
+		pageAfter = &after
+	}
+	slices.Sort(ids)
+	return ids, nil
 }
 
 type membersByID []sdkkonnectcomp.Members
@@ -3,6 +3,7 @@ package ops
 import (
 	"errors"
 	"testing"
+	"time"
 
 	sdkkonnectgo "github.com/Kong/sdk-konnect-go"
 	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
@@ -21,7 +22,10 @@ import (
 
 	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
 	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/internal/utils/index"
 	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 
 	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
 	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
@@ -268,10 +272,7 @@ func TestCreateControlPlane(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			sdk, sdkGroups, cp := tc.mockCPTuple(t)
-			fakeClient := fake.NewClientBuilder().
-				WithScheme(scheme.Get()).
-				WithObjects(tc.objects...).
-				Build()
+			fakeClient := newFakeClientWithKonnectGatewayControlPlaneIndexes(tc.objects...)
 
 			err := createControlPlane(ctx, sdk, sdkGroups, fakeClient, cp)
 			if tc.expectedErrContains != "" {
@@ -960,11 +961,7 @@ func TestSetGroupMembers(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			fakeClient := fake.NewClientBuilder().
-				WithScheme(scheme.Get()).
-				WithObjects(tc.group).
-				WithObjects(tc.cps...).
-				Build()
+			fakeClient := newFakeClientWithKonnectGatewayControlPlaneIndexes(append(tc.cps, tc.group)...)
 
 			sdk := tc.sdk(t)
 			err := setGroupMembers(t.Context(), fakeClient, tc.group, "cpg-12345", sdk)
@@ -984,3 +981,170 @@ func TestSetGroupMembers(t *testing.T) {
 		})
 	}
 }
+
+func TestSetGroupMembersDeclaredByMembers(t *testing.T) {
+	newCP := func(name, id, group string) *konnectv1alpha1.KonnectGatewayControlPlane {
+		cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+				CreateControlPlaneRequest: konnectv1alpha1.CreateControlPlaneRequest{
+					Name: lo.ToPtr(name),
+				},
+				Source: lo.ToPtr(commonv1alpha1.EntitySourceOrigin),
+			},
+		}
+		if group != "" {
+			cp.Annotations = map[string]string{
+				consts.KonnectControlPlaneGroupAnnotationKey: group,
+			}
+		}
+		cp.SetKonnectID(id)
+		return cp
+	}
+	newGroup := func(name string, members ...string) *konnectv1alpha1.KonnectGatewayControlPlane {
+		group := newCP(name, "", "")
+		group.Spec.ClusterType = lo.ToPtr(sdkkonnectcomp.CreateControlPlaneRequestClusterTypeClusterTypeControlPlaneGroup)
+		for _, m := range members {
+			group.Spec.Members = append(group.Spec.Members, corev1.LocalObjectReference{Name: m})
+		}
+		return group
+	}
+	objects := func() []client.Object {
+		deleting := newCP("cp-deleting", "id-deleting", "cp-group")
+		deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		deleting.Finalizers = []string{"test"}
+		return []client.Object{
+			newGroup("cp-group", "cp-listed"),
+			newGroup("other-group", "cp-conflict"),
+			// Declared members which are listed in the group's spec.members are not duplicated.
+			newCP("cp-listed", "id-listed", "cp-group"),
+			newCP("cp-declared", "id-declared", "cp-group"),
+			newCP("cp-pending", "", "cp-group"),
+			newCP("cp-conflict", "id-conflict", "cp-group"),
+			newCP("cp-other", "id-other", "other-group"),
+			deleting,
+		}
+	}
+	expectedMembership := &sdkkonnectcomp.GroupMembership{
+		Members: []sdkkonnectcomp.Members{
+			{ID: "id-declared"},
+			{ID: "id-listed"},
+		},
+	}
+	assertMembership := func(
+		t *testing.T,
+		cl client.Client,
+		name string,
+		status metav1.ConditionStatus,
+		reason kcfgconsts.ConditionReason,
+	) {
+		t.Helper()
+		var cp konnectv1alpha1.KonnectGatewayControlPlane
+		require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, &cp))
+		cond, ok := k8sutils.GetCondition(ControlPlaneGroupMembershipConditionType, &cp)
+		require.True(t, ok, "%s should have the membership condition", name)
+		assert.Equal(t, status, cond.Status, name)
+		assert.Equal(t, string(reason), cond.Reason, name)
+	}
+
+	t.Run("declared members are attached", func(t *testing.T) {
+		cl := newFakeClientWithKonnectGatewayControlPlaneIndexes(objects()...)
+		sdk := sdkmocks.NewMockControlPlaneGroupSDK(t)
+		sdk.EXPECT().
+			PutControlPlanesIDGroupMemberships(mock.Anything, "cpg-12345", expectedMembership).
+			Return(&sdkkonnectops.PutControlPlanesIDGroupMembershipsResponse{}, nil)
+
+		var group konnectv1alpha1.KonnectGatewayControlPlane
+		require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-group"}, &group))
+		require.NoError(t, setGroupMembers(t.Context(), cl, &group, "cpg-12345", sdk))
+
+		assert.True(t, k8sutils.HasConditionTrue(ControlPlaneGroupMembersReferenceResolvedConditionType, &group))
+		assertMembership(t, cl, "cp-declared", metav1.ConditionTrue, ControlPlaneGroupMembershipReasonAttached)
+		assertMembership(t, cl, "cp-listed", metav1.ConditionTrue, ControlPlaneGroupMembershipReasonAttached)
+		assertMembership(t, cl, "cp-pending", metav1.ConditionFalse, ControlPlaneGroupMembershipReasonPending)
+		assertMembership(t, cl, "cp-conflict", metav1.ConditionFalse, ControlPlaneGroupMembershipReasonConflict)
+
+		var deleting konnectv1alpha1.KonnectGatewayControlPlane
+		require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-deleting"}, &deleting))
+		assert.False(t, k8sutils.HasCondition(ControlPlaneGroupMembershipConditionType, &deleting))
+	})
+
+	t.Run("failure to set members is reported on declared members", func(t *testing.T) {
+		cl := newFakeClientWithKonnectGatewayControlPlaneIndexes(objects()...)
+		sdk := sdkmocks.NewMockControlPlaneGroupSDK(t)
+		sdk.EXPECT().
+			PutControlPlanesIDGroupMemberships(mock.Anything, "cpg-12345", expectedMembership).
+			Return(nil, errors.New("failed to set group members"))
+
+		var group konnectv1alpha1.KonnectGatewayControlPlane
+		require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-group"}, &group))
+		require.Error(t, setGroupMembers(t.Context(), cl, &group, "cpg-12345", sdk))
+
+		assertMembership(t, cl, "cp-declared", metav1.ConditionFalse, ControlPlaneGroupMembershipReasonFailedToSet)
+		assertMembership(t, cl, "cp-pending", metav1.ConditionFalse, ControlPlaneGroupMembershipReasonPending)
+	})
+
+	currentMembers := func(next *string, ids ...string) *sdkkonnectops.GetControlPlanesIDGroupMembershipsResponse {
+		return &sdkkonnectops.GetControlPlanesIDGroupMembershipsResponse{
+			ListGroupMemberships: &sdkkonnectcomp.ListGroupMemberships{
+				Meta: sdkkonnectcomp.CursorPaginatedMetaWithSizeAndTotal{
+					Page: sdkkonnectcomp.CursorMetaWithSizeAndTotal{Next: next},
+				},
+				Data: lo.Map(ids, func(id string, _ int) sdkkonnectcomp.ControlPlane {
+					return sdkkonnectcomp.ControlPlane{ID: id}
+				}),
+			},
+		}
+	}
+
+	t.Run("unchanged members are not set again", func(t *testing.T) {
+		cl := newFakeClientWithKonnectGatewayControlPlaneIndexes(objects()...)
+		sdk := sdkmocks.NewMockControlPlaneGroupSDK(t)
+		sdk.EXPECT().
+			GetControlPlanesIDGroupMemberships(mock.Anything, mock.MatchedBy(func(r sdkkonnectops.GetControlPlanesIDGroupMembershipsRequest) bool {
+				return r.ID == "cpg-12345" && r.PageAfter == nil
+			})).
+			Return(currentMembers(lo.ToPtr("/v2/control-planes/cpg-12345/group-memberships?page%5Bafter%5D=cursor"), "id-listed"), nil)
+		sdk.EXPECT().
+			GetControlPlanesIDGroupMemberships(mock.Anything, mock.MatchedBy(func(r sdkkonnectops.GetControlPlanesIDGroupMembershipsRequest) bool {
+				return r.ID == "cpg-12345" && lo.FromPtr(r.PageAfter) == "cursor"
+			})).
+			Return(currentMembers(nil, "id-declared"), nil)
+
+		var group konnectv1alpha1.KonnectGatewayControlPlane
+		require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-group"}, &group))
+		require.NoError(t, ensureGroupMembers(t.Context(), cl, &group, "cpg-12345", sdk))
+
+		assert.True(t, k8sutils.HasConditionTrue(ControlPlaneGroupMembersReferenceResolvedConditionType, &group))
+		assertMembership(t, cl, "cp-declared", metav1.ConditionTrue, ControlPlaneGroupMembershipReasonAttached)
+	})
+
+	t.Run("changed members are set", func(t *testing.T) {
+		cl := newFakeClientWithKonnectGatewayControlPlaneIndexes(objects()...)
+		sdk := sdkmocks.NewMockControlPlaneGroupSDK(t)
+		sdk.EXPECT().
+			GetControlPlanesIDGroupMemberships(mock.Anything, mock.Anything).
+			Return(currentMembers(nil, "id-listed"), nil)
+		sdk.EXPECT().
+			PutControlPlanesIDGroupMemberships(mock.Anything, "cpg-12345", expectedMembership).
+			Return(&sdkkonnectops.PutControlPlanesIDGroupMembershipsResponse{}, nil)
+
+		var group konnectv1alpha1.KonnectGatewayControlPlane
+		require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-group"}, &group))
+		require.NoError(t, ensureGroupMembers(t.Context(), cl, &group, "cpg-12345", sdk))
+	})
+}
+
+func newFakeClientWithKonnectGatewayControlPlaneIndexes(objs ...client.Object) client.Client {
+	builder := fake.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(objs...).
+		WithStatusSubresource(&konnectv1alpha1.KonnectGatewayControlPlane{})
+	for _, opt := range index.OptionsForKonnectGatewayControlPlane() {
+		builder = builder.WithIndex(opt.Object, opt.Field, opt.ExtractValueFn)
+	}
+	return builder.Build()
+}
@@ -0,0 +1,193 @@
+package ops
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	"github.com/samber/lo"
+
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+)
+
+// KongCredentialMTLS is an mtls-auth credential of a consumer in Konnect.
+// There is no Kubernetes resource for mtls-auth credentials, they are generated
+// from the Secrets labeled as mtls-auth credentials and used by KongConsumers.
+type KongCredentialMTLS struct {
+	// ControlPlaneID is the Konnect ID of the control plane of the consumer.
+	ControlPlaneID string
+	// ConsumerID is the Konnect ID of the consumer.
+	ConsumerID string
+	// SubjectName is the subject name of the client certificates the
+	// credential authenticates.
+	SubjectName string
+	// CACertificateID is the Konnect ID of the CA certificate the client
+	// certificates have to be issued by, empty when any trusted CA is accepted.
+	CACertificateID string
+	// Tags are the tags of the credential, identifying the Secret it's generated
+	// from with its UID tag.
+	Tags []string
+}
+
+// EnsureKongCredentialMTLS creates or updates the provided mtls-auth credential
+// in Konnect. Existing credentials of the consumer are matched by the provided
+// UID tag, the duplicates being deleted.
+func EnsureKongCredentialMTLS(
+	ctx context.Context,
+	sdk sdkops.KongCredentialMTLSSDK,
+	cred KongCredentialMTLS,
+	uidTag string,
+) (string, error) {
+	existing, err := listKongCredentialMTLSForUID(ctx, sdk, cred.ControlPlaneID, cred.ConsumerID, uidTag)
+	if err != nil {
+		return "", err
+	}
+
+	if len(existing) == 0 {
+		resp, err := sdk.CreateMtlsAuthWithConsumer(ctx,
+			sdkkonnectops.CreateMtlsAuthWithConsumerRequest{
+				ControlPlaneID:              cred.ControlPlaneID,
+				ConsumerIDForNestedEntities: cred.ConsumerID,
+				MTLSAuthWithoutParents:      kongCredentialMTLSToMTLSWithoutParents(cred),
+			},
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed creating mtls-auth credential of consumer %s: %w", cred.ConsumerID, err)
+		}
+		if resp == nil || resp.MTLSAuth == nil || resp.MTLSAuth.ID == nil {
+			return "", fmt.Errorf("failed creating mtls-auth credential of consumer %s: %w", cred.ConsumerID, ErrNilResponse)
+		}
+		return *resp.MTLSAuth.ID, nil
+	}
+
+	current, duplicates := existing[0], existing[1:]
+	for _, duplicate := range duplicates {
+		if err := deleteKongCredentialMTLS(ctx, sdk, cred.ControlPlaneID, cred.ConsumerID, lo.FromPtr(duplicate.ID)); err != nil {
+			return "", err
+		}
+	}
+
+	id := lo.FromPtr(current.ID)
+	if kongCredentialMTLSMatches(current, cred) {
+		return id, nil
+	}
+	_, err = sdk.UpsertMtlsAuthWithConsumer(ctx,
+		sdkkonnectops.UpsertMtlsAuthWithConsumerRequest{
+			ControlPlaneID:              cred.ControlPlaneID,
+			ConsumerIDForNestedEntities: cred.ConsumerID,
+			MTLSAuthID:                  id,
+			MTLSAuthWithoutParents:      kongCredentialMTLSToMTLSWithoutParents(cred),
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed updating mtls-auth credential %s of consumer %s: %w", id, cred.ConsumerID, err)
+	}
+	return id, nil
+}
+
+// DeleteKongCredentialMTLSForUID deletes the mtls-auth credentials of the
+// consumer tagged with the provided UID tag. Credentials which are already
+// gone, e.g. deleted along with their consumer, are skipped.
+func DeleteKongCredentialMTLSForUID(
+	ctx context.Context,
+	sdk sdkops.KongCredentialMTLSSDK,
+	controlPlaneID string,
+	consumerID string,
+	uidTag string,
+) error {
+	existing, err := listKongCredentialMTLSForUID(ctx, sdk, controlPlaneID, consumerID, uidTag)
+	if err != nil {
+		if errIsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	for _, cred := range existing {
+		if err := deleteKongCredentialMTLS(ctx, sdk, controlPlaneID, consumerID, lo.FromPtr(cred.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteKongCredentialMTLS(
+	ctx context.Context,
+	sdk sdkops.KongCredentialMTLSSDK,
+	controlPlaneID string,
+	consumerID string,
+	id string,
+) error {
+	_, err := sdk.DeleteMtlsAuthWithConsumer(ctx,
+		sdkkonnectops.DeleteMtlsAuthWithConsumerRequest{
+			ControlPlaneID:              controlPlaneID,
+			ConsumerIDForNestedEntities: consumerID,
+			MTLSAuthID:                  id,
+		},
+	)
+	if err != nil && !errIsNotFound(err) {
+		return fmt.Errorf("failed deleting mtls-auth credential %s of consumer %s: %w", id, consumerID, err)
+	}
+	return nil
+}
+
+// listKongCredentialMTLSForUID lists the mtls-auth credentials of the consumer
+// tagged with the provided UID tag, oldest first.
+func listKongCredentialMTLSForUID(
+	ctx context.Context,
+	sdk sdkops.KongCredentialMTLSSDK,
+	controlPlaneID string,
+	consumerID string,
+	uidTag string,
+) ([]sdkkonnectcomp.MTLSAuth, error) {
+	resp, err := sdk.ListMtlsAuth(ctx, sdkkonnectops.ListMtlsAuthRequest{
+		ControlPlaneID: controlPlaneID,
+		Tags:           lo.ToPtr(uidTag),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed listing mtls-auth credentials: %w", err)
+	}
+	if resp == nil || resp.Object == nil {
+		return nil, fmt.Errorf("failed listing mtls-auth credentials: %w", ErrNilResponse)
+	}
+
+	// The same Secret can be used by several consumers of the control plane.
+	creds := lo.Filter(resp.Object.Data, func(cred sdkkonnectcomp.MTLSAuth, _ int) bool {
+		return cred.ID != nil && cred.Consumer != nil && lo.FromPtr(cred.Consumer.ID) == consumerID
+	})
+	slices.SortStableFunc(creds, func(a, b sdkkonnectcomp.MTLSAuth) int {
+		return cmp.Compare(lo.FromPtr(a.CreatedAt), lo.FromPtr(b.CreatedAt))
+	})
+	return creds, nil
+}
+
+// kongCredentialMTLSMatches returns true if the credential in Konnect matches
+// the provided one.
+func kongCredentialMTLSMatches(current sdkkonnectcomp.MTLSAuth, cred KongCredentialMTLS) bool {
+	var caCertificateID string
+	if current.CaCertificate != nil {
+		caCertificateID = lo.FromPtr(current.CaCertificate.ID)
+	}
+	currentTags := slices.Clone(current.Tags)
+	slices.Sort(currentTags)
+	tags := slices.Clone(cred.Tags)
+	slices.Sort(tags)
+	return current.SubjectName == cred.SubjectName &&
+		caCertificateID == cred.CACertificateID &&
+		slices.Equal(currentTags, tags)
+}
+
+func kongCredentialMTLSToMTLSWithoutParents(cred KongCredentialMTLS) sdkkonnectcomp.MTLSAuthWithoutParents {
+	ret := sdkkonnectcomp.MTLSAuthWithoutParents{
+		SubjectName: cred.SubjectName,
+		Tags:        cred.Tags,
+	}
+	if cred.CACertificateID != "" {
+		ret.CaCertificate = &sdkkonnectcomp.MTLSAuthWithoutParentsCaCertificate{
+			ID: lo.ToPtr(cred.CACertificateID),
+		}
+	}
+	return ret
+}
@@ -0,0 +1,182 @@
+package ops
+
+import (
+	"net/http"
+	"testing"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	sdkkonnecterrs "github.com/Kong/sdk-konnect-go/models/sdkerrors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+)
+
+func TestEnsureKongCredentialMTLS(t *testing.T) {
+	const (
+		cpID       = "cp-id"
+		consumerID = "consumer-id"
+		uidTag     = "k8s-uid:secret-uid"
+	)
+	cred := KongCredentialMTLS{
+		ControlPlaneID:  cpID,
+		ConsumerID:      consumerID,
+		SubjectName:     "client.example.com",
+		CACertificateID: "ca-id",
+		Tags:            []string{uidTag, "k8s-name:secret"},
+	}
+	listRequest := sdkkonnectops.ListMtlsAuthRequest{
+		ControlPlaneID: cpID,
+		Tags:           lo.ToPtr(uidTag),
+	}
+	listResponse := func(creds ...sdkkonnectcomp.MTLSAuth) *sdkkonnectops.ListMtlsAuthResponse {
+		return &sdkkonnectops.ListMtlsAuthResponse{
+			Object: &sdkkonnectops.ListMtlsAuthResponseBody{
+				Data: creds,
+			},
+		}
+	}
+	existing := func(id string, createdAt int64, consumerID string, subjectName string) sdkkonnectcomp.MTLSAuth {
+		return sdkkonnectcomp.MTLSAuth{
+			ID:            lo.ToPtr(id),
+			CreatedAt:     lo.ToPtr(createdAt),
+			Consumer:      &sdkkonnectcomp.MTLSAuthConsumer{ID: lo.ToPtr(consumerID)},
+			CaCertificate: &sdkkonnectcomp.MTLSAuthCaCertificate{ID: lo.ToPtr("ca-id")},
+			SubjectName:   subjectName,
+			Tags:          []string{"k8s-name:secret", uidTag},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		mock        func(*sdkmocks.MockKongCredentialMTLSSDK)
+		expectedID  string
+		expectedErr bool
+	}{
+		{
+			name: "creates the credential when it doesn't exist",
+			mock: func(sdk *sdkmocks.MockKongCredentialMTLSSDK) {
+				sdk.EXPECT().ListMtlsAuth(mock.Anything, listRequest).
+					Return(listResponse(existing("other", 1, "other-consumer", "client.example.com")), nil)
+				sdk.EXPECT().CreateMtlsAuthWithConsumer(mock.Anything, sdkkonnectops.CreateMtlsAuthWithConsumerRequest{
+					ControlPlaneID:              cpID,
+					ConsumerIDForNestedEntities: consumerID,
+					MTLSAuthWithoutParents:      kongCredentialMTLSToMTLSWithoutParents(cred),
+				}).Return(&sdkkonnectops.CreateMtlsAuthWithConsumerResponse{
+					MTLSAuth: &sdkkonnectcomp.MTLSAuth{ID: lo.ToPtr("created")},
+				}, nil)
+			},
+			expectedID: "created",
+		},
+		{
+			name: "does nothing when the credential is up to date",
+			mock: func(sdk *sdkmocks.MockKongCredentialMTLSSDK) {
+				sdk.EXPECT().ListMtlsAuth(mock.Anything, listRequest).
+					Return(listResponse(existing("current", 1, consumerID, "client.example.com")), nil)
+			},
+			expectedID: "current",
+		},
+		{
+			name: "updates the credential when it differs",
+			mock: func(sdk *sdkmocks.MockKongCredentialMTLSSDK) {
+				sdk.EXPECT().ListMtlsAuth(mock.Anything, listRequest).
+					Return(listResponse(existing("current", 1, consumerID, "old.example.com")), nil)
+				sdk.EXPECT().UpsertMtlsAuthWithConsumer(mock.Anything, sdkkonnectops.UpsertMtlsAuthWithConsumerRequest{
+					ControlPlaneID:              cpID,
+					ConsumerIDForNestedEntities: consumerID,
+					MTLSAuthID:                  "current",
+					MTLSAuthWithoutParents:      kongCredentialMTLSToMTLSWithoutParents(cred),
+				}).Return(&sdkkonnectops.UpsertMtlsAuthWithConsumerResponse{}, nil)
+			},
+			expectedID: "current",
+		},
+		{
+			name: "keeps the oldest credential and deletes the duplicates",
+			mock: func(sdk *sdkmocks.MockKongCredentialMTLSSDK) {
+				sdk.EXPECT().ListMtlsAuth(mock.Anything, listRequest).
+					Return(listResponse(
+						existing("duplicate", 2, consumerID, "client.example.com"),
+						existing("current", 1, consumerID, "client.example.com"),
+					), nil)
+				sdk.EXPECT().DeleteMtlsAuthWithConsumer(mock.Anything, sdkkonnectops.DeleteMtlsAuthWithConsumerRequest{
+					ControlPlaneID:              cpID,
+					ConsumerIDForNestedEntities: consumerID,
+					MTLSAuthID:                  "duplicate",
+				}).Return(&sdkkonnectops.DeleteMtlsAuthWithConsumerResponse{}, nil)
+			},
+			expectedID: "current",
+		},
+		{
+			name: "fails when listing fails",
+			mock: func(sdk *sdkmocks.MockKongCredentialMTLSSDK) {
+				sdk.EXPECT().ListMtlsAuth(mock.Anything, listRequest).
+					Return(nil, &sdkkonnecterrs.BadRequestError{Status: http.StatusBadRequest})
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sdk := sdkmocks.NewMockKongCredentialMTLSSDK(t)
+			tc.mock(sdk)
+
+			id, err := EnsureKongCredentialMTLS(t.Context(), sdk, cred, uidTag)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedID, id)
+		})
+	}
+}
+
+func TestDeleteKongCredentialMTLSForUID(t *testing.T) {
+	const (
+		cpID       = "cp-id"
+		consumerID = "consumer-id"
+		uidTag     = "k8s-uid:secret-uid"
+	)
+	listRequest := sdkkonnectops.ListMtlsAuthRequest{
+		ControlPlaneID: cpID,
+		Tags:           lo.ToPtr(uidTag),
+	}
+
+	t.Run("deletes the credentials of the consumer", func(t *testing.T) {
+		sdk := sdkmocks.NewMockKongCredentialMTLSSDK(t)
+		sdk.EXPECT().ListMtlsAuth(mock.Anything, listRequest).
+			Return(&sdkkonnectops.ListMtlsAuthResponse{
+				Object: &sdkkonnectops.ListMtlsAuthResponseBody{
+					Data: []sdkkonnectcomp.MTLSAuth{
+						{
+							ID:       lo.ToPtr("cred"),
+							Consumer: &sdkkonnectcomp.MTLSAuthConsumer{ID: lo.ToPtr(consumerID)},
+						},
+						{
+							ID:       lo.ToPtr("other"),
+							Consumer: &sdkkonnectcomp.MTLSAuthConsumer{ID: lo.ToPtr("other-consumer")},
+						},
+					},
+				},
+			}, nil)
+		sdk.EXPECT().DeleteMtlsAuthWithConsumer(mock.Anything, sdkkonnectops.DeleteMtlsAuthWithConsumerRequest{
+			ControlPlaneID:              cpID,
+			ConsumerIDForNestedEntities: consumerID,
+			MTLSAuthID:                  "cred",
+		}).Return(nil, &sdkkonnecterrs.NotFoundError{Status: http.StatusNotFound})
+
+		require.NoError(t, DeleteKongCredentialMTLSForUID(t.Context(), sdk, cpID, consumerID, uidTag))
+	})
+
+	t.Run("ignores a control plane which is gone", func(t *testing.T) {
+		sdk := sdkmocks.NewMockKongCredentialMTLSSDK(t)
+		sdk.EXPECT().ListMtlsAuth(mock.Anything, listRequest).
+			Return(nil, &sdkkonnecterrs.NotFoundError{Status: http.StatusNotFound})
+
+		require.NoError(t, DeleteKongCredentialMTLSForUID(t.Context(), sdk, cpID, consumerID, uidTag))
+	})
+}
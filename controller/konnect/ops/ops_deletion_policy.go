@@ -0,0 +1,202 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/konnect/constraints"
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	configurationv1beta1 "github.com/kong/kubernetes-configuration/api/configuration/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+// DeletionPolicy determines what happens with a Konnect entity when the object
+// it was created for is deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the Konnect entity when the object is deleted.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+
+	// DeletionPolicyOrphan keeps the Konnect entity when the object is deleted.
+	// The Kubernetes UID tag (or label) is removed from the entity so that
+	// it's not tied to the deleted object anymore.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// GetDeletionPolicy returns the deletion policy of the provided object.
+// It's read from the consts.KonnectDeletionPolicyAnnotationKey annotation of
+// the object or, when the object doesn't have it, of the object's Namespace.
+// It defaults to DeletionPolicyDelete.
+func GetDeletionPolicy(ctx context.Context, cl client.Client, obj client.Object) (DeletionPolicy, error) {
+	if policy, ok := obj.GetAnnotations()[consts.KonnectDeletionPolicyAnnotationKey]; ok {
+		return parseDeletionPolicy(policy, obj)
+	}
+
+	if obj.GetNamespace() == "" {
+		return DeletionPolicyDelete, nil
+	}
+	var ns corev1.Namespace
+	if err := cl.Get(ctx, client.ObjectKey{Name: obj.GetNamespace()}, &ns); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return DeletionPolicyDelete, nil
+		}
+		return "", fmt.Errorf("failed getting Namespace %s to determine the deletion policy: %w", obj.GetNamespace(), err)
+	}
+	if policy, ok := ns.GetAnnotations()[consts.KonnectDeletionPolicyAnnotationKey]; ok {
+		return parseDeletionPolicy(policy, &ns)
+	}
+	return DeletionPolicyDelete, nil
+}
+
+func parseDeletionPolicy(policy string, annotatedObj client.Object) (DeletionPolicy, error) {
+	switch p := DeletionPolicy(policy); p {
+	case DeletionPolicyDelete, DeletionPolicyOrphan:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid %s annotation value %q on %T %s, supported values: %s, %s",
+			consts.KonnectDeletionPolicyAnnotationKey, policy, annotatedObj, client.ObjectKeyFromObject(annotatedObj),
+			DeletionPolicyDelete, DeletionPolicyOrphan,
+		)
+	}
+}
+
+// orphan leaves the Konnect entity of the provided object in place, removing
+// the Kubernetes UID tag (or label) from it so that it can be adopted later on.
+// Entities which are not tagged with the Kubernetes UID are left untouched.
+// Entities which were already deleted from Konnect are not recreated as the tags
+// (labels) are patched instead of upserting the entity.
+func orphan[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](ctx context.Context, sdk sdkops.SDKWrapper, ent TEnt) error {
+	var (
+		logger = loggerForEntity(ctx, ent, DeleteOp)
+		id     = ent.GetKonnectStatus().GetKonnectID()
+		start  = time.Now()
+		err    error
+	)
+	switch e := any(ent).(type) {
+	case *konnectv1alpha1.KonnectGatewayControlPlane:
+		if isMirrorEntity(e) {
+			return nil
+		}
+		// Only the UID label is removed to not change the group members
+		// and the other labels of the ControlPlane.
+		err = sdk.GetEntityMetadataSDK().RemoveControlPlaneLabels(ctx, id, []string{KubernetesUIDLabelKey})
+	case *konnectv1alpha1.KonnectCloudGatewayNetwork,
+		*konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfiguration,
+		*konnectv1alpha1.KonnectCloudGatewayTransitGateway,
+		*configurationv1alpha1.KongDataPlaneClientCertificate:
+		// These entities are not tagged with the Kubernetes UID.
+	default:
+		err = orphanTaggedEntity(ctx, sdk.GetEntityMetadataSDK(), ent)
+	}
+	if errIsNotFound(err) {
+		log.Debug(logger, "entity not found in Konnect, nothing to orphan", "konnect_id", id)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed removing Kubernetes UID tag from orphaned %s %s: %w",
+			ent.GetTypeName(), id, err,
+		)
+	}
+
+	log.Info(logger, "entity orphaned in Konnect, not deleting it",
+		"konnect_id", id,
+		"duration", time.Since(start).String(),
+	)
+	return nil
+}
+
+// orphanTaggedEntity removes the Kubernetes UID tag from the tags of the
+// Konnect entity of the provided object. It returns a not found error when
+// the entity doesn't exist in Konnect.
+func orphanTaggedEntity[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](ctx context.Context, sdk sdkops.EntityMetadataSDK, ent TEnt) error {
+	cpID, path, ok := konnectEntityPath(ent)
+	if !ok {
+		return fmt.Errorf("unsupported entity type %s", ent.GetTypeName())
+	}
+
+	tags, err := sdk.GetEntityTags(ctx, cpID, path)
+	if err != nil {
+		return err
+	}
+	uidTagPrefix := KubernetesUIDLabelKey + ":"
+	orphanedTags := lo.Reject(tags, func(tag string, _ int) bool {
+		return strings.HasPrefix(tag, uidTagPrefix)
+	})
+	if len(orphanedTags) == len(tags) {
+		return nil
+	}
+	return sdk.PatchEntityTags(ctx, cpID, path, orphanedTags)
+}
+
+// konnectEntityPath returns the ID of the Konnect ControlPlane the entity of the
+// provided object belongs to and the path of the entity relative to the
+// ControlPlane's core entities endpoint.
+func konnectEntityPath[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](ent TEnt) (string, string, bool) {
+	id := url.PathEscape(ent.GetKonnectStatus().GetKonnectID())
+	switch e := any(ent).(type) {
+	case *configurationv1alpha1.KongService:
+		return e.GetControlPlaneID(), "services/" + id, true
+	case *configurationv1alpha1.KongRoute:
+		return e.GetControlPlaneID(), "routes/" + id, true
+	case *configurationv1.KongConsumer:
+		return e.GetControlPlaneID(), "consumers/" + id, true
+	case *configurationv1beta1.KongConsumerGroup:
+		return e.GetControlPlaneID(), "consumer_groups/" + id, true
+	case *configurationv1alpha1.KongPluginBinding:
+		return e.GetControlPlaneID(), "plugins/" + id, true
+	case *configurationv1alpha1.KongCredentialBasicAuth:
+		return e.GetControlPlaneID(), "basic-auths/" + id, true
+	case *configurationv1alpha1.KongCredentialAPIKey:
+		return e.GetControlPlaneID(), "key-auths/" + id, true
+	case *configurationv1alpha1.KongCredentialACL:
+		return e.GetControlPlaneID(), "acls/" + id, true
+	case *configurationv1alpha1.KongCredentialJWT:
+		return e.GetControlPlaneID(), "jwts/" + id, true
+	case *configurationv1alpha1.KongCredentialHMAC:
+		return e.GetControlPlaneID(), "hmac-auths/" + id, true
+	case *configurationv1alpha1.KongUpstream:
+		return e.GetControlPlaneID(), "upstreams/" + id, true
+	case *configurationv1alpha1.KongTarget:
+		if e.Status.Konnect == nil {
+			return "", "", false
+		}
+		return e.GetControlPlaneID(), "upstreams/" + url.PathEscape(e.Status.Konnect.UpstreamID) + "/targets/" + id, true
+	case *configurationv1alpha1.KongCACertificate:
+		return e.GetControlPlaneID(), "ca_certificates/" + id, true
+	case *configurationv1alpha1.KongCertificate:
+		return e.GetControlPlaneID(), "certificates/" + id, true
+	case *configurationv1alpha1.KongSNI:
+		return e.GetControlPlaneID(), "snis/" + id, true
+	case *configurationv1alpha1.KongVault:
+		return e.GetControlPlaneID(), "vaults/" + id, true
+	case *configurationv1alpha1.KongKey:
+		return e.GetControlPlaneID(), "keys/" + id, true
+	case *configurationv1alpha1.KongKeySet:
+		return e.GetControlPlaneID(), "key-sets/" + id, true
+	default:
+		return "", "", false
+	}
+}
@@ -0,0 +1,336 @@
+package ops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdkkonnecterrs "github.com/Kong/sdk-konnect-go/models/sdkerrors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestGetDeletionPolicy(t *testing.T) {
+	namespace := func(name string, policy string) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if policy != "" {
+			ns.Annotations = map[string]string{consts.KonnectDeletionPolicyAnnotationKey: policy}
+		}
+		return ns
+	}
+	service := func(namespace string, policy string) *configurationv1alpha1.KongService {
+		svc := &configurationv1alpha1.KongService{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: namespace},
+		}
+		if policy != "" {
+			svc.Annotations = map[string]string{consts.KonnectDeletionPolicyAnnotationKey: policy}
+		}
+		return svc
+	}
+
+	testCases := []struct {
+		name           string
+		obj            client.Object
+		namespaces     []client.Object
+		expectedPolicy DeletionPolicy
+		expectedErr    string
+	}{
+		{
+			name:           "defaults to Delete",
+			obj:            service("ns", ""),
+			namespaces:     []client.Object{namespace("ns", "")},
+			expectedPolicy: DeletionPolicyDelete,
+		},
+		{
+			name:           "defaults to Delete when Namespace is not found",
+			obj:            service("ns", ""),
+			expectedPolicy: DeletionPolicyDelete,
+		},
+		{
+			name:           "object's annotation",
+			obj:            service("ns", "Orphan"),
+			namespaces:     []client.Object{namespace("ns", "")},
+			expectedPolicy: DeletionPolicyOrphan,
+		},
+		{
+			name:           "Namespace's annotation",
+			obj:            service("ns", ""),
+			namespaces:     []client.Object{namespace("ns", "Orphan")},
+			expectedPolicy: DeletionPolicyOrphan,
+		},
+		{
+			name:           "object's annotation takes precedence over Namespace's one",
+			obj:            service("ns", "Delete"),
+			namespaces:     []client.Object{namespace("ns", "Orphan")},
+			expectedPolicy: DeletionPolicyDelete,
+		},
+		{
+			name:        "invalid object's annotation",
+			obj:         service("ns", "Keep"),
+			expectedErr: `invalid konnect.konghq.com/deletion-policy annotation value "Keep"`,
+		},
+		{
+			name:        "invalid Namespace's annotation",
+			obj:         service("ns", ""),
+			namespaces:  []client.Object{namespace("ns", "orphan")},
+			expectedErr: `invalid konnect.konghq.com/deletion-policy annotation value "orphan"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(tc.namespaces...).
+				Build()
+
+			policy, err := GetDeletionPolicy(t.Context(), cl, tc.obj)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPolicy, policy)
+		})
+	}
+}
+
+func TestDeleteWithOrphanDeletionPolicy(t *testing.T) {
+	t.Run("KonnectGatewayControlPlane", func(t *testing.T) {
+		cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: konnectv1alpha1.GroupVersion.String(),
+				Kind:       "KonnectGatewayControlPlane",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cp",
+				Namespace: "test-ns",
+				UID:       "cp-uid",
+				Annotations: map[string]string{
+					consts.KonnectDeletionPolicyAnnotationKey: "Orphan",
+				},
+			},
+			Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+				CreateControlPlaneRequest: konnectv1alpha1.CreateControlPlaneRequest{
+					Name:   lo.ToPtr("test-cp"),
+					Labels: map[string]string{"team": "a"},
+				},
+				Source: lo.ToPtr(commonv1alpha1.EntitySourceOrigin),
+			},
+			Status: konnectv1alpha1.KonnectGatewayControlPlaneStatus{
+				KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+					ID: "12345",
+				},
+			},
+		}
+
+		sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+		sdk.EntityMetadataSDK.
+			EXPECT().
+			RemoveControlPlaneLabels(mock.Anything, "12345", []string{KubernetesUIDLabelKey}).
+			Return(nil).
+			Once()
+
+		cl := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+		require.NoError(t, Delete(t.Context(), sdk, cl, &metrics.MockRecorder{}, cp))
+		assert.Equal(t, "cp-uid", string(cp.GetUID()), "the deleted object should not be modified")
+	})
+
+	t.Run("KongService in Namespace with Orphan deletion policy", func(t *testing.T) {
+		svc := &configurationv1alpha1.KongService{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: configurationv1alpha1.GroupVersion.String(),
+				Kind:       "KongService",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "svc",
+				Namespace: "test-ns",
+				UID:       "svc-uid",
+			},
+			Spec: configurationv1alpha1.KongServiceSpec{
+				KongServiceAPISpec: configurationv1alpha1.KongServiceAPISpec{
+					Host: "example.com",
+				},
+			},
+			Status: configurationv1alpha1.KongServiceStatus{
+				Konnect: &konnectv1alpha1.KonnectEntityStatusWithControlPlaneRef{
+					ControlPlaneID: "cp-12345",
+					KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+						ID: "svc-12345",
+					},
+				},
+			},
+		}
+
+		sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+		sdk.EntityMetadataSDK.
+			EXPECT().
+			GetEntityTags(mock.Anything, "cp-12345", "services/svc-12345").
+			Return([]string{"k8s-name:svc", "k8s-uid:svc-uid", "team:a"}, nil).
+			Once()
+		sdk.EntityMetadataSDK.
+			EXPECT().
+			PatchEntityTags(mock.Anything, "cp-12345", "services/svc-12345", []string{"k8s-name:svc", "team:a"}).
+			Return(nil).
+			Once()
+
+		cl := fakectrlruntimeclient.NewClientBuilder().
+			WithScheme(scheme.Get()).
+			WithObjects(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ns",
+					Annotations: map[string]string{
+						consts.KonnectDeletionPolicyAnnotationKey: "Orphan",
+					},
+				},
+			}).
+			Build()
+		require.NoError(t, Delete(t.Context(), sdk, cl, &metrics.MockRecorder{}, svc))
+	})
+
+	t.Run("KongTarget deleted from Konnect is not recreated", func(t *testing.T) {
+		target := &configurationv1alpha1.KongTarget{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: configurationv1alpha1.GroupVersion.String(),
+				Kind:       "KongTarget",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "target",
+				Namespace: "test-ns",
+				UID:       "target-uid",
+				Annotations: map[string]string{
+					consts.KonnectDeletionPolicyAnnotationKey: "Orphan",
+				},
+			},
+			Status: configurationv1alpha1.KongTargetStatus{
+				Konnect: &konnectv1alpha1.KonnectEntityStatusWithControlPlaneAndUpstreamRefs{
+					ControlPlaneID: "cp-12345",
+					UpstreamID:     "upstream-12345",
+					KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+						ID: "target-12345",
+					},
+				},
+			},
+		}
+
+		sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+		sdk.EntityMetadataSDK.
+			EXPECT().
+			GetEntityTags(mock.Anything, "cp-12345", "upstreams/upstream-12345/targets/target-12345").
+			Return(nil, &sdkkonnecterrs.NotFoundError{}).
+			Once()
+
+		cl := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+		require.NoError(t, Delete(t.Context(), sdk, cl, &metrics.MockRecorder{}, target))
+	})
+
+	t.Run("invalid deletion policy prevents deletion", func(t *testing.T) {
+		svc := &configurationv1alpha1.KongService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "svc",
+				Namespace: "test-ns",
+				Annotations: map[string]string{
+					consts.KonnectDeletionPolicyAnnotationKey: "Keep",
+				},
+			},
+			Status: configurationv1alpha1.KongServiceStatus{
+				Konnect: &konnectv1alpha1.KonnectEntityStatusWithControlPlaneRef{
+					ControlPlaneID: "cp-12345",
+					KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+						ID: "svc-12345",
+					},
+				},
+			},
+		}
+
+		sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+		cl := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+		require.ErrorContains(t, Delete(t.Context(), sdk, cl, &metrics.MockRecorder{}, svc), "invalid konnect.konghq.com/deletion-policy")
+	})
+}
+
+// entityMetadataSDKWrapper is an SDKWrapper using the provided EntityMetadataSDK
+// instead of a mock.
+type entityMetadataSDKWrapper struct {
+	*sdkmocks.MockSDKWrapper
+	entityMetadata sdkops.EntityMetadataSDK
+}
+
+func (w entityMetadataSDKWrapper) GetEntityMetadataSDK() sdkops.EntityMetadataSDK {
+	return w.entityMetadata
+}
+
+func TestOrphanKonnectGatewayControlPlaneRemovesUIDLabel(t *testing.T) {
+	// The server mimics how Konnect updates ControlPlanes: provided labels
+	// are merged into the existing ones and labels set to null are removed.
+	labels := map[string]string{
+		KubernetesUIDLabelKey:  "cp-uid",
+		KubernetesNameLabelKey: "test-cp",
+		"team":                 "a",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/v2/control-planes/12345" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req struct {
+			Labels map[string]*string `json:"labels"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for k, v := range req.Labels {
+			if v == nil {
+				delete(labels, k)
+				continue
+			}
+			labels[k] = *v
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cp",
+			Namespace: "test-ns",
+			UID:       "cp-uid",
+		},
+		Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+			Source: lo.ToPtr(commonv1alpha1.EntitySourceOrigin),
+		},
+		Status: konnectv1alpha1.KonnectGatewayControlPlaneStatus{
+			KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+				ID: "12345",
+			},
+		},
+	}
+	sdk := entityMetadataSDKWrapper{
+		MockSDKWrapper: sdkmocks.NewMockSDKWrapperWithT(t),
+		entityMetadata: sdkops.NewEntityMetadataSDK(srv.URL, "token", srv.Client()),
+	}
+
+	require.NoError(t, orphan(t.Context(), sdk, cp))
+	assert.Equal(t, map[string]string{
+		KubernetesNameLabelKey: "test-cp",
+		"team":                 "a",
+	}, labels)
+}
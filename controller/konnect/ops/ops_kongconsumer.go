@@ -80,19 +80,11 @@ func updateConsumer(
 	}
 	id := consumer.GetKonnectStatus().GetKonnectID()
 
-	_, err := sdk.UpsertConsumer(ctx,
-		sdkkonnectops.UpsertConsumerRequest{
-			ControlPlaneID: cpID,
-			ConsumerID:     id,
-			Consumer:       kongConsumerToSDKConsumerInput(consumer),
-		},
-	)
-
-	if errWrap := wrapErrIfKonnectOpFailed(err, UpdateOp, consumer); errWrap != nil {
-		return errWrap
+	if err := upsertConsumer(ctx, sdk, consumer); err != nil {
+		return err
 	}
 
-	if err = handleConsumerGroupAssignments(ctx, consumer, cl, cgSDK, cpID); err != nil {
+	if err := handleConsumerGroupAssignments(ctx, consumer, cl, cgSDK, cpID); err != nil {
 		return KonnectEntityCreatedButRelationsFailedError{
 			KonnectID: id,
 			Reason:    kcfgkonnect.FailedToAttachConsumerToConsumerGroupReason,
@@ -92,17 +92,7 @@ func updateKonnectDataPlaneGroupConfiguration(
 		}
 
 		if transientError {
-			id := n.GetKonnectID()
-			resp, err := sdk.GetConfiguration(ctx, id)
-			if errWrap := wrapErrIfKonnectOpFailed(err, GetOp, n); errWrap != nil {
-				return errWrap
-			}
-			if resp == nil || resp.ConfigurationManifest == nil {
-				return fmt.Errorf("failed getting %s: %w", n.GetTypeName(), ErrNilResponse)
-			}
-			n.SetKonnectID(resp.ConfigurationManifest.ID)
-			n.Status.DataPlaneGroups = dataPlaneGroupsResponseToStatus(resp.ConfigurationManifest.GetDataplaneGroups())
-			return nil
+			return getKonnectDataPlaneGroupConfiguration(ctx, sdk, n)
 		}
 
 		// If there was an error which wasn't a conflict, complaining about submitting
@@ -124,6 +114,26 @@ func updateKonnectDataPlaneGroupConfiguration(
 	return nil
 }
 
+// getKonnectDataPlaneGroupConfiguration gets the Konnect DataPlaneGroupConfiguration
+// and sets the state of its data plane groups in the status.
+// It is used to poll the provisioning state without submitting the configuration.
+func getKonnectDataPlaneGroupConfiguration(
+	ctx context.Context,
+	sdk sdkops.CloudGatewaysSDK,
+	n *konnectv1alpha1.KonnectCloudGatewayDataPlaneGroupConfiguration,
+) error {
+	resp, err := sdk.GetConfiguration(ctx, n.GetKonnectID())
+	if errWrap := wrapErrIfKonnectOpFailed(err, GetOp, n); errWrap != nil {
+		return errWrap
+	}
+	if resp == nil || resp.ConfigurationManifest == nil {
+		return fmt.Errorf("failed getting %s: %w", n.GetTypeName(), ErrNilResponse)
+	}
+	n.SetKonnectID(resp.ConfigurationManifest.ID)
+	n.Status.DataPlaneGroups = dataPlaneGroupsResponseToStatus(resp.ConfigurationManifest.GetDataplaneGroups())
+	return nil
+}
+
 // deleteKonnectDataPlaneGroupConfiguration deletes a Konnect DataPlaneGroupConfiguration.
 // It is assumed that the Konnect DataPlaneGroupConfiguration has a Konnect ID.
 func deleteKonnectDataPlaneGroupConfiguration(
@@ -0,0 +1,161 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+)
+
+// UnmanagedKonnectEntities holds the entities of a Konnect ControlPlane which
+// were not created for Kubernetes objects, e.g. because they were configured
+// in the Konnect UI.
+type UnmanagedKonnectEntities struct {
+	Services  []sdkkonnectcomp.ServiceOutput
+	Routes    []sdkkonnectcomp.RouteJSON
+	Consumers []sdkkonnectcomp.Consumer
+
+	// ManagedServices maps the Konnect IDs of the services created for KongServices
+	// to the metadata of these KongServices so that unmanaged routes of managed
+	// services can be related to them.
+	ManagedServices map[string]metav1.PartialObjectMetadata
+}
+
+// ListUnmanagedKonnectEntities lists the services, routes and consumers in the
+// provided Konnect ControlPlane which are not tagged with the Kubernetes metadata
+// of an object (see GenerateTagsForObject).
+// Expression routes are not listed as they can't be represented as KongRoutes.
+func ListUnmanagedKonnectEntities(
+	ctx context.Context,
+	sdk sdkops.SDKWrapper,
+	cpID string,
+) (UnmanagedKonnectEntities, error) {
+	services, routes, consumers, err := listServicesRoutesAndConsumers(ctx, sdk, cpID)
+	if err != nil {
+		return UnmanagedKonnectEntities{}, err
+	}
+
+	return UnmanagedKonnectEntities{
+		Services:        filterUnmanaged(services),
+		Routes:          filterUnmanaged(routes),
+		Consumers:       filterUnmanaged(consumers),
+		ManagedServices: managedServices(services),
+	}, nil
+}
+
+// managedServices returns the metadata of the KongServices the provided
+// services were created for, keyed by the services' Konnect IDs.
+func managedServices(services []sdkkonnectcomp.ServiceOutput) map[string]metav1.PartialObjectMetadata {
+	ret := make(map[string]metav1.PartialObjectMetadata)
+	for _, svc := range services {
+		if svc.ID == nil {
+			continue
+		}
+		obj, ok := objectMetadataFromTags(svc.Tags)
+		if !ok || obj.GroupVersionKind().Kind != "KongService" {
+			continue
+		}
+		ret[*svc.ID] = obj
+	}
+	return ret
+}
+
+// listServicesRoutesAndConsumers lists all the services, routes and consumers
+// in the provided Konnect ControlPlane.
+// Expression routes are not listed.
+func listServicesRoutesAndConsumers(
+	ctx context.Context,
+	sdk sdkops.SDKWrapper,
+	cpID string,
+) ([]sdkkonnectcomp.ServiceOutput, []sdkkonnectcomp.RouteJSON, []sdkkonnectcomp.Consumer, error) {
+	services, err := listAllPages(func(offset *string) ([]sdkkonnectcomp.ServiceOutput, *string, error) {
+		resp, err := sdk.GetServicesSDK().ListService(ctx, sdkkonnectops.ListServiceRequest{
+			ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+		})
+		if err != nil || resp == nil || resp.Object == nil {
+			return nil, nil, listErr(err)
+		}
+		return resp.Object.Data, resp.Object.Offset, nil
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed listing Service entities in ControlPlane %s: %w", cpID, err)
+	}
+
+	routes, err := listAllPages(func(offset *string) ([]sdkkonnectcomp.Route, *string, error) {
+		resp, err := sdk.GetRoutesSDK().ListRoute(ctx, sdkkonnectops.ListRouteRequest{
+			ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+		})
+		if err != nil || resp == nil || resp.Object == nil {
+			return nil, nil, listErr(err)
+		}
+		return resp.Object.Data, resp.Object.Offset, nil
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed listing Route entities in ControlPlane %s: %w", cpID, err)
+	}
+	routesJSON := lo.FilterMap(routes, func(r sdkkonnectcomp.Route, _ int) (sdkkonnectcomp.RouteJSON, bool) {
+		if r.RouteJSON == nil {
+			return sdkkonnectcomp.RouteJSON{}, false
+		}
+		return *r.RouteJSON, true
+	})
+
+	consumers, err := listAllPages(func(offset *string) ([]sdkkonnectcomp.Consumer, *string, error) {
+		resp, err := sdk.GetConsumersSDK().ListConsumer(ctx, sdkkonnectops.ListConsumerRequest{
+			ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+		})
+		if err != nil || resp == nil || resp.Object == nil {
+			return nil, nil, listErr(err)
+		}
+		return resp.Object.Data, resp.Object.Offset, nil
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed listing Consumer entities in ControlPlane %s: %w", cpID, err)
+	}
+
+	return services, routesJSON, consumers, nil
+}
+
+// listAllPages calls list until all the pages are listed.
+func listAllPages[T any](
+	list func(offset *string) ([]T, *string, error),
+) ([]T, error) {
+	var (
+		all    []T
+		offset *string
+	)
+	for {
+		page, next, err := list(offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if next == nil || *next == "" {
+			return all, nil
+		}
+		offset = next
+	}
+}
+
+// filterUnmanaged returns the entities which have an ID and are not tagged
+// with the Kubernetes metadata of an object.
+func filterUnmanaged[
+	T any,
+	TPtr interface {
+		*T
+		entityWithTags
+	},
+](data []T) []T {
+	return lo.Filter(data, func(e T, _ int) bool {
+		if TPtr(&e).GetID() == nil {
+			return false
+		}
+		_, managed := objectMetadataFromTags(TPtr(&e).GetTags())
+		return !managed
+	})
+}
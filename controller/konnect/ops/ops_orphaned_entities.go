@@ -0,0 +1,383 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+)
+
+// TaggedKonnectEntity is a Konnect entity tagged with the Kubernetes metadata
+// of the object it was created for (see GenerateTagsForObject).
+type TaggedKonnectEntity struct {
+	// EntityType is the type of the Konnect entity, e.g. Service.
+	EntityType string
+	// ID is the Konnect ID of the entity.
+	ID string
+	// Object holds the Kubernetes metadata of the object the entity was created for.
+	Object metav1.PartialObjectMetadata
+}
+
+// maxKubernetesMetadataTagLength is the length which the Kubernetes metadata
+// tags are truncated to (see generateKubernetesMetadataTags).
+const maxKubernetesMetadataTagLength = 128
+
+// listPageSize is the number of entities requested per page when listing
+// Konnect entities.
+const listPageSize = int64(100)
+
+type entityWithTags interface {
+	GetID() *string
+	GetTags() []string
+}
+
+// taggedEntitiesSDK describes how to list and delete a type of Konnect entities
+// which can be tagged with Kubernetes metadata.
+type taggedEntitiesSDK struct {
+	entityType string
+	// objectKind is the kind of the objects the operator creates the entities for.
+	objectKind string
+	list       func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error)
+	delete     func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error
+}
+
+// NOTE: Entities nested in other entities (e.g. credentials or targets) are
+// not listed as they're deleted together with their parents.
+var taggedEntitiesSDKs = []taggedEntitiesSDK{
+	{
+		entityType: "Service",
+		objectKind: "KongService",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetServicesSDK().ListService(ctx, sdkkonnectops.ListServiceRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			return toEntitiesWithTags(resp.Object.Data), resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetServicesSDK().DeleteService(ctx, cpID, id)
+			return err
+		},
+	},
+	{
+		entityType: "Route",
+		objectKind: "KongRoute",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetRoutesSDK().ListRoute(ctx, sdkkonnectops.ListRouteRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			routes := make([]entityWithTags, 0, len(resp.Object.Data))
+			for _, route := range resp.Object.Data {
+				switch {
+				case route.RouteJSON != nil:
+					routes = append(routes, route.RouteJSON)
+				case route.RouteExpression != nil:
+					routes = append(routes, route.RouteExpression)
+				}
+			}
+			return routes, resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetRoutesSDK().DeleteRoute(ctx, cpID, id)
+			return err
+		},
+	},
+	{
+		entityType: "Consumer",
+		objectKind: "KongConsumer",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetConsumersSDK().ListConsumer(ctx, sdkkonnectops.ListConsumerRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			return toEntitiesWithTags(resp.Object.Data), resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetConsumersSDK().DeleteConsumer(ctx, cpID, id)
+			return err
+		},
+	},
+	{
+		entityType: "ConsumerGroup",
+		objectKind: "KongConsumerGroup",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetConsumerGroupsSDK().ListConsumerGroup(ctx, sdkkonnectops.ListConsumerGroupRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			return toEntitiesWithTags(resp.Object.Data), resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetConsumerGroupsSDK().DeleteConsumerGroup(ctx, cpID, id)
+			return err
+		},
+	},
+	{
+		entityType: "Plugin",
+		objectKind: "KongPluginBinding",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetPluginSDK().ListPlugin(ctx, sdkkonnectops.ListPluginRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			return toEntitiesWithTags(resp.Object.Data), resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetPluginSDK().DeletePlugin(ctx, cpID, id)
+			return err
+		},
+	},
+	{
+		entityType: "Upstream",
+		objectKind: "KongUpstream",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetUpstreamsSDK().ListUpstream(ctx, sdkkonnectops.ListUpstreamRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			return toEntitiesWithTags(resp.Object.Data), resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetUpstreamsSDK().DeleteUpstream(ctx, cpID, id)
+			return err
+		},
+	},
+	{
+		entityType: "Certificate",
+		objectKind: "KongCertificate",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetCertificatesSDK().ListCertificate(ctx, sdkkonnectops.ListCertificateRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			return toEntitiesWithTags(resp.Object.Data), resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetCertificatesSDK().DeleteCertificate(ctx, cpID, id)
+			return err
+		},
+	},
+	{
+		entityType: "CACertificate",
+		objectKind: "KongCACertificate",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetCACertificatesSDK().ListCaCertificate(ctx, sdkkonnectops.ListCaCertificateRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			return toEntitiesWithTags(resp.Object.Data), resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetCACertificatesSDK().DeleteCaCertificate(ctx, cpID, id)
+			return err
+		},
+	},
+	{
+		entityType: "Key",
+		objectKind: "KongKey",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetKeysSDK().ListKey(ctx, sdkkonnectops.ListKeyRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			return toEntitiesWithTags(resp.Object.Data), resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetKeysSDK().DeleteKey(ctx, cpID, id)
+			return err
+		},
+	},
+	{
+		entityType: "KeySet",
+		objectKind: "KongKeySet",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetKeySetsSDK().ListKeySet(ctx, sdkkonnectops.ListKeySetRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			return toEntitiesWithTags(resp.Object.Data), resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetKeySetsSDK().DeleteKeySet(ctx, cpID, id)
+			return err
+		},
+	},
+	{
+		entityType: "Vault",
+		objectKind: "KongVault",
+		list: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, offset *string) ([]entityWithTags, *string, error) {
+			resp, err := sdk.GetVaultSDK().ListVault(ctx, sdkkonnectops.ListVaultRequest{
+				ControlPlaneID: cpID, Size: lo.ToPtr(listPageSize), Offset: offset,
+			})
+			if err != nil || resp == nil || resp.Object == nil {
+				return nil, nil, listErr(err)
+			}
+			return toEntitiesWithTags(resp.Object.Data), resp.Object.Offset, nil
+		},
+		delete: func(ctx context.Context, sdk sdkops.SDKWrapper, cpID string, id string) error {
+			_, err := sdk.GetVaultSDK().DeleteVault(ctx, cpID, id)
+			return err
+		},
+	},
+}
+
+// TaggedKonnectEntityTypes returns the types of Konnect entities which are
+// listed by ListTaggedKonnectEntities.
+func TaggedKonnectEntityTypes() []string {
+	return lo.Map(taggedEntitiesSDKs, func(s taggedEntitiesSDK, _ int) string {
+		return s.entityType
+	})
+}
+
+// ListTaggedKonnectEntities lists all the Konnect entities in the provided
+// Konnect ControlPlane which were created by the operator and are tagged with
+// the Kubernetes metadata (including the UID) of the object they were created for.
+func ListTaggedKonnectEntities(
+	ctx context.Context,
+	sdk sdkops.SDKWrapper,
+	cpID string,
+) ([]TaggedKonnectEntity, error) {
+	var entities []TaggedKonnectEntity
+	for _, s := range taggedEntitiesSDKs {
+		var offset *string
+		for {
+			page, next, err := s.list(ctx, sdk, cpID, offset)
+			if err != nil {
+				return nil, fmt.Errorf("failed listing %s entities in ControlPlane %s: %w", s.entityType, cpID, err)
+			}
+			for _, e := range page {
+				obj, ok := operatorObjectMetadataFromTags(e.GetTags(), s.objectKind)
+				if !ok || e.GetID() == nil {
+					continue
+				}
+				entities = append(entities, TaggedKonnectEntity{
+					EntityType: s.entityType,
+					ID:         *e.GetID(),
+					Object:     obj,
+				})
+			}
+			if next == nil || *next == "" {
+				break
+			}
+			offset = next
+		}
+	}
+	return entities, nil
+}
+
+// DeleteTaggedKonnectEntity deletes the provided Konnect entity from
+// the provided Konnect ControlPlane. Entities which are not found are
+// considered deleted.
+func DeleteTaggedKonnectEntity(
+	ctx context.Context,
+	sdk sdkops.SDKWrapper,
+	cpID string,
+	entity TaggedKonnectEntity,
+) error {
+	s, ok := lo.Find(taggedEntitiesSDKs, func(s taggedEntitiesSDK) bool {
+		return s.entityType == entity.EntityType
+	})
+	if !ok {
+		return fmt.Errorf("unsupported Konnect entity type %s", entity.EntityType)
+	}
+	if err := s.delete(ctx, sdk, cpID, entity.ID); err != nil && !errIsNotFound(err) {
+		return fmt.Errorf("failed deleting %s %s in ControlPlane %s: %w", entity.EntityType, entity.ID, cpID, err)
+	}
+	return nil
+}
+
+// operatorObjectMetadataFromTags returns the Kubernetes metadata stored in the
+// provided tags of an entity created by the operator for an object of the provided
+// kind. It returns false when the tags are of an object of another kind, so that
+// entities tagged with Kubernetes metadata by other tools (e.g. KIC creating
+// Services out of Kubernetes Services) are never considered. It also returns
+// false when the name of the object was truncated in the tags as the object
+// could not be identified reliably.
+func operatorObjectMetadataFromTags(tags []string, kind string) (metav1.PartialObjectMetadata, bool) {
+	obj, ok := objectMetadataFromTags(tags)
+	if !ok ||
+		obj.GroupVersionKind().GroupKind() != (schema.GroupKind{Group: configurationv1alpha1.GroupVersion.Group, Kind: kind}) ||
+		len(KubernetesNameLabelKey+":"+obj.GetName()) >= maxKubernetesMetadataTagLength {
+		return metav1.PartialObjectMetadata{}, false
+	}
+	return obj, true
+}
+
+// objectMetadataFromTags returns the Kubernetes metadata stored in the provided
+// tags. It returns false when the tags do not identify a Kubernetes object.
+func objectMetadataFromTags(tags []string) (metav1.PartialObjectMetadata, bool) {
+	values := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if k, v, ok := strings.Cut(tag, ":"); ok {
+			values[k] = v
+		}
+	}
+
+	var obj metav1.PartialObjectMetadata
+	if values[KubernetesUIDLabelKey] == "" ||
+		values[KubernetesKindLabelKey] == "" ||
+		values[KubernetesVersionLabelKey] == "" ||
+		values[KubernetesNameLabelKey] == "" {
+		return obj, false
+	}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   values[KubernetesGroupLabelKey],
+		Version: values[KubernetesVersionLabelKey],
+		Kind:    values[KubernetesKindLabelKey],
+	})
+	obj.SetName(values[KubernetesNameLabelKey])
+	obj.SetNamespace(values[KubernetesNamespaceLabelKey])
+	obj.SetUID(types.UID(values[KubernetesUIDLabelKey]))
+	return obj, true
+}
+
+func toEntitiesWithTags[
+	T any,
+	TPtr interface {
+		*T
+		entityWithTags
+	},
+](data []T) []entityWithTags {
+	result := make([]entityWithTags, 0, len(data))
+	for i := range data {
+		result = append(result, TPtr(&data[i]))
+	}
+	return result
+}
+
+func listErr(err error) error {
+	if err != nil {
+		return err
+	}
+	return ErrNilResponse
+}
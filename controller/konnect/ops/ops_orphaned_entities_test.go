@@ -0,0 +1,171 @@
+package ops
+
+import (
+	"strings"
+	"testing"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	sdkkonnecterrs "github.com/Kong/sdk-konnect-go/models/sdkerrors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+)
+
+func TestListTaggedKonnectEntities(t *testing.T) {
+	const cpID = "cp-id"
+	svcTags := []string{
+		"k8s-generation:1",
+		"k8s-group:configuration.konghq.com",
+		"k8s-kind:KongService",
+		"k8s-name:svc",
+		"k8s-namespace:default",
+		"k8s-uid:svc-uid",
+		"k8s-version:v1alpha1",
+		"user-tag",
+	}
+
+	sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+	sdk.ServicesSDK.EXPECT().
+		ListService(mock.Anything, mock.MatchedBy(func(req sdkkonnectops.ListServiceRequest) bool {
+			return req.ControlPlaneID == cpID && req.Offset == nil
+		})).
+		Return(&sdkkonnectops.ListServiceResponse{
+			Object: &sdkkonnectops.ListServiceResponseBody{
+				Data: []sdkkonnectcomp.ServiceOutput{
+					{ID: lo.ToPtr("svc-1"), Tags: svcTags},
+					// Entities not created by the operator are skipped.
+					{ID: lo.ToPtr("svc-2"), Tags: []string{"user-tag"}},
+					// Entities tagged with Kubernetes metadata by other tools (e.g. KIC) are skipped.
+					{ID: lo.ToPtr("svc-kic"), Tags: []string{
+						"k8s-group:", "k8s-kind:Service", "k8s-name:svc", "k8s-namespace:default", "k8s-uid:kic-uid", "k8s-version:v1",
+					}},
+					// Entities created for objects of groups the operator doesn't manage are skipped.
+					{ID: lo.ToPtr("svc-other-group"), Tags: []string{
+						"k8s-group:example.com", "k8s-kind:Example",
+						"k8s-name:svc", "k8s-namespace:default", "k8s-uid:example-uid", "k8s-version:v1",
+					}},
+					// Entities created for objects of other kinds than the one the operator
+					// creates the entity type for are skipped.
+					{ID: lo.ToPtr("svc-other-kind"), Tags: []string{
+						"k8s-group:configuration.konghq.com", "k8s-kind:KongRoute",
+						"k8s-name:svc", "k8s-namespace:default", "k8s-uid:route-uid", "k8s-version:v1alpha1",
+					}},
+					// Entities whose object name was truncated in the tags are skipped.
+					{ID: lo.ToPtr("svc-truncated"), Tags: []string{
+						"k8s-group:configuration.konghq.com", "k8s-kind:KongService",
+						("k8s-name:" + strings.Repeat("s", 200))[:128],
+						"k8s-namespace:default", "k8s-uid:truncated-uid", "k8s-version:v1alpha1",
+					}},
+				},
+				Offset: lo.ToPtr("page-2"),
+			},
+		}, nil).
+		Once()
+	sdk.ServicesSDK.EXPECT().
+		ListService(mock.Anything, mock.MatchedBy(func(req sdkkonnectops.ListServiceRequest) bool {
+			return req.ControlPlaneID == cpID && lo.FromPtr(req.Offset) == "page-2"
+		})).
+		Return(&sdkkonnectops.ListServiceResponse{
+			Object: &sdkkonnectops.ListServiceResponseBody{
+				Data: []sdkkonnectcomp.ServiceOutput{
+					{ID: lo.ToPtr("svc-3"), Tags: []string{"k8s-kind:KongService", "k8s-name:svc-3"}},
+				},
+			},
+		}, nil).
+		Once()
+	sdk.RoutesSDK.EXPECT().
+		ListRoute(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListRouteResponse{
+			Object: &sdkkonnectops.ListRouteResponseBody{
+				Data: []sdkkonnectcomp.Route{
+					sdkkonnectcomp.CreateRouteRouteJSON(sdkkonnectcomp.RouteJSON{
+						ID: lo.ToPtr("route-1"),
+						Tags: []string{
+							"k8s-group:configuration.konghq.com",
+							"k8s-kind:KongRoute",
+							"k8s-name:route",
+							"k8s-namespace:default",
+							"k8s-uid:route-uid",
+							"k8s-version:v1alpha1",
+						},
+					}),
+				},
+			},
+		}, nil)
+	sdk.ConsumersSDK.EXPECT().ListConsumer(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListConsumerResponse{Object: &sdkkonnectops.ListConsumerResponseBody{}}, nil)
+	sdk.ConsumerGroupSDK.EXPECT().ListConsumerGroup(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListConsumerGroupResponse{Object: &sdkkonnectops.ListConsumerGroupResponseBody{}}, nil)
+	sdk.PluginSDK.EXPECT().ListPlugin(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListPluginResponse{Object: &sdkkonnectops.ListPluginResponseBody{}}, nil)
+	sdk.UpstreamsSDK.EXPECT().ListUpstream(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListUpstreamResponse{Object: &sdkkonnectops.ListUpstreamResponseBody{}}, nil)
+	sdk.CertificatesSDK.EXPECT().ListCertificate(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListCertificateResponse{Object: &sdkkonnectops.ListCertificateResponseBody{}}, nil)
+	sdk.CACertificatesSDK.EXPECT().ListCaCertificate(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListCaCertificateResponse{Object: &sdkkonnectops.ListCaCertificateResponseBody{}}, nil)
+	sdk.KeysSDK.EXPECT().ListKey(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListKeyResponse{Object: &sdkkonnectops.ListKeyResponseBody{}}, nil)
+	sdk.KeySetsSDK.EXPECT().ListKeySet(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListKeySetResponse{Object: &sdkkonnectops.ListKeySetResponseBody{}}, nil)
+	sdk.VaultSDK.EXPECT().ListVault(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListVaultResponse{Object: &sdkkonnectops.ListVaultResponseBody{}}, nil)
+
+	entities, err := ListTaggedKonnectEntities(t.Context(), sdk, cpID)
+	require.NoError(t, err)
+	require.Len(t, entities, 2)
+
+	assert.Equal(t, "Service", entities[0].EntityType)
+	assert.Equal(t, "svc-1", entities[0].ID)
+	assert.Equal(t, metav1.TypeMeta{
+		APIVersion: "configuration.konghq.com/v1alpha1",
+		Kind:       "KongService",
+	}, entities[0].Object.TypeMeta)
+	assert.Equal(t, "default", entities[0].Object.Namespace)
+	assert.Equal(t, "svc", entities[0].Object.Name)
+	assert.Equal(t, "svc-uid", string(entities[0].Object.UID))
+
+	assert.Equal(t, "Route", entities[1].EntityType)
+	assert.Equal(t, "route-1", entities[1].ID)
+	assert.Equal(t, "KongRoute", entities[1].Object.Kind)
+	assert.Equal(t, "route-uid", string(entities[1].Object.UID))
+}
+
+func TestDeleteTaggedKonnectEntity(t *testing.T) {
+	t.Run("deletes entity", func(t *testing.T) {
+		sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+		sdk.PluginSDK.EXPECT().
+			DeletePlugin(mock.Anything, "cp-id", "plugin-id").
+			Return(&sdkkonnectops.DeletePluginResponse{}, nil)
+
+		require.NoError(t, DeleteTaggedKonnectEntity(t.Context(), sdk, "cp-id", TaggedKonnectEntity{
+			EntityType: "Plugin",
+			ID:         "plugin-id",
+		}))
+	})
+
+	t.Run("entity not found is not an error", func(t *testing.T) {
+		sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+		sdk.ServicesSDK.EXPECT().
+			DeleteService(mock.Anything, "cp-id", "svc-id").
+			Return(nil, &sdkkonnecterrs.NotFoundError{Status: 404, Detail: "Not found"})
+
+		require.NoError(t, DeleteTaggedKonnectEntity(t.Context(), sdk, "cp-id", TaggedKonnectEntity{
+			EntityType: "Service",
+			ID:         "svc-id",
+		}))
+	})
+
+	t.Run("unsupported entity type", func(t *testing.T) {
+		sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+		require.Error(t, DeleteTaggedKonnectEntity(t.Context(), sdk, "cp-id", TaggedKonnectEntity{
+			EntityType: "Target",
+			ID:         "target-id",
+		}))
+	})
+}
@@ -9,5 +9,6 @@ import (
 
 // ControlPlaneGroupSDK is the interface for the Konnect ControlPlaneGroupSDK SDK.
 type ControlPlaneGroupSDK interface {
+	GetControlPlanesIDGroupMemberships(ctx context.Context, request sdkkonnectops.GetControlPlanesIDGroupMembershipsRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.GetControlPlanesIDGroupMembershipsResponse, error)
 	PutControlPlanesIDGroupMemberships(ctx context.Context, id string, groupMembership *sdkkonnectcomp.GroupMembership, opts ...sdkkonnectops.Option) (*sdkkonnectops.PutControlPlanesIDGroupMembershipsResponse, error)
 }
@@ -0,0 +1,15 @@
+package sdk
+
+import (
+	"context"
+
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+)
+
+// KongCredentialMTLSSDK is the interface for the Konnect KongCredentialMTLSSDK.
+type KongCredentialMTLSSDK interface {
+	CreateMtlsAuthWithConsumer(ctx context.Context, req sdkkonnectops.CreateMtlsAuthWithConsumerRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.CreateMtlsAuthWithConsumerResponse, error)
+	DeleteMtlsAuthWithConsumer(ctx context.Context, request sdkkonnectops.DeleteMtlsAuthWithConsumerRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.DeleteMtlsAuthWithConsumerResponse, error)
+	UpsertMtlsAuthWithConsumer(ctx context.Context, request sdkkonnectops.UpsertMtlsAuthWithConsumerRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.UpsertMtlsAuthWithConsumerResponse, error)
+	ListMtlsAuth(ctx context.Context, request sdkkonnectops.ListMtlsAuthRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.ListMtlsAuthResponse, error)
+}
@@ -0,0 +1,126 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	sdkkonnecterrs "github.com/Kong/sdk-konnect-go/models/sdkerrors"
+)
+
+// EntityMetadataSDK is the interface to operate the tags of Kong entities and
+// the labels of control planes in Konnect without upserting them, which the
+// Konnect SDK doesn't allow as it only provides PUT operations for entities.
+//
+// Paths are relative to the control plane's core entities endpoint, e.g.
+// "services/<id>" or "upstreams/<upstream-id>/targets/<id>".
+type EntityMetadataSDK interface {
+	// GetEntityTags returns the tags of the entity.
+	GetEntityTags(ctx context.Context, controlPlaneID string, path string) ([]string, error)
+	// PatchEntityTags replaces the tags of the entity. It fails with a not
+	// found error when the entity doesn't exist instead of creating it.
+	PatchEntityTags(ctx context.Context, controlPlaneID string, path string, tags []string) error
+	// RemoveControlPlaneLabels removes the labels with the provided keys from
+	// the control plane, leaving its other labels untouched.
+	RemoveControlPlaneLabels(ctx context.Context, controlPlaneID string, keys []string) error
+}
+
+// entityMetadataSDK is an EntityMetadataSDK which sends requests to the Konnect API.
+type entityMetadataSDK struct {
+	serverURL  string
+	token      string
+	httpClient *http.Client
+}
+
+// NewEntityMetadataSDK returns an EntityMetadataSDK sending requests to the
+// Konnect API served at the provided URL, authenticated with the provided token.
+func NewEntityMetadataSDK(serverURL string, token SDKToken, httpClient *http.Client) EntityMetadataSDK {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return entityMetadataSDK{
+		serverURL:  strings.TrimSuffix(serverURL, "/"),
+		token:      string(token),
+		httpClient: httpClient,
+	}
+}
+
+// GetEntityTags returns the tags of the entity.
+func (s entityMetadataSDK) GetEntityTags(ctx context.Context, controlPlaneID string, path string) ([]string, error) {
+	var entity struct {
+		Tags []string `json:"tags"`
+	}
+	if err := s.do(ctx, http.MethodGet, s.entityURL(controlPlaneID, path), nil, &entity); err != nil {
+		return nil, err
+	}
+	return entity.Tags, nil
+}
+
+// PatchEntityTags replaces the tags of the entity.
+func (s entityMetadataSDK) PatchEntityTags(ctx context.Context, controlPlaneID string, path string, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	body := map[string]any{"tags": tags}
+	return s.do(ctx, http.MethodPatch, s.entityURL(controlPlaneID, path), body, nil)
+}
+
+// RemoveControlPlaneLabels removes the labels with the provided keys from the
+// control plane. Labels are merged on updates, setting them to null removes them.
+func (s entityMetadataSDK) RemoveControlPlaneLabels(ctx context.Context, controlPlaneID string, keys []string) error {
+	labels := make(map[string]*string, len(keys))
+	for _, key := range keys {
+		labels[key] = nil
+	}
+	body := map[string]any{"labels": labels}
+	return s.do(ctx, http.MethodPatch, s.serverURL+"/v2/control-planes/"+url.PathEscape(controlPlaneID), body, nil)
+}
+
+func (s entityMetadataSDK) entityURL(controlPlaneID string, path string) string {
+	return s.serverURL + "/v2/control-planes/" + url.PathEscape(controlPlaneID) + "/core-entities/" + path
+}
+
+// do sends the request and decodes the response into out when it's not nil.
+// Unsuccessful responses are returned as SDK errors so that they're handled
+// like the ones returned by the Konnect SDK.
+func (s entityMetadataSDK) do(ctx context.Context, method, u string, in any, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return sdkkonnecterrs.NewSDKError(fmt.Sprintf("%s %s: unexpected status code", method, req.URL.Path), resp.StatusCode, string(respBody), resp)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
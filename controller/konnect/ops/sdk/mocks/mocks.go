@@ -331,6 +331,72 @@ func (_m *MockControlPlaneGroupSDK) EXPECT() *MockControlPlaneGroupSDK_Expecter
 	return &MockControlPlaneGroupSDK_Expecter{mock: &_m.Mock}
 }
 
+// GetControlPlanesIDGroupMemberships provides a mock function for the type MockControlPlaneGroupSDK
+func (_mock *MockControlPlaneGroupSDK) GetControlPlanesIDGroupMemberships(ctx context.Context, request operations.GetControlPlanesIDGroupMembershipsRequest, opts ...operations.Option) (*operations.GetControlPlanesIDGroupMembershipsResponse, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, request, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, request)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetControlPlanesIDGroupMemberships")
+	}
+
+	var r0 *operations.GetControlPlanesIDGroupMembershipsResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, operations.GetControlPlanesIDGroupMembershipsRequest, ...operations.Option) (*operations.GetControlPlanesIDGroupMembershipsResponse, error)); ok {
+		return returnFunc(ctx, request, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, operations.GetControlPlanesIDGroupMembershipsRequest, ...operations.Option) *operations.GetControlPlanesIDGroupMembershipsResponse); ok {
+		r0 = returnFunc(ctx, request, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*operations.GetControlPlanesIDGroupMembershipsResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, operations.GetControlPlanesIDGroupMembershipsRequest, ...operations.Option) error); ok {
+		r1 = returnFunc(ctx, request, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockControlPlaneGroupSDK_GetControlPlanesIDGroupMemberships_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetControlPlanesIDGroupMemberships'
+type MockControlPlaneGroupSDK_GetControlPlanesIDGroupMemberships_Call struct {
+	*mock.Call
+}
+
+// GetControlPlanesIDGroupMemberships is a helper method to define mock.On call
+//   - ctx
+//   - request
+//   - opts
+func (_e *MockControlPlaneGroupSDK_Expecter) GetControlPlanesIDGroupMemberships(ctx interface{}, request interface{}, opts ...interface{}) *MockControlPlaneGroupSDK_GetControlPlanesIDGroupMemberships_Call {
+	return &MockControlPlaneGroupSDK_GetControlPlanesIDGroupMemberships_Call{Call: _e.mock.On("GetControlPlanesIDGroupMemberships",
+		append([]interface{}{ctx, request}, opts...)...)}
+}
+
+func (_c *MockControlPlaneGroupSDK_GetControlPlanesIDGroupMemberships_Call) Run(run func(ctx context.Context, request operations.GetControlPlanesIDGroupMembershipsRequest, opts ...operations.Option)) *MockControlPlaneGroupSDK_GetControlPlanesIDGroupMemberships_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[2].([]operations.Option)
+		run(args[0].(context.Context), args[1].(operations.GetControlPlanesIDGroupMembershipsRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockControlPlaneGroupSDK_GetControlPlanesIDGroupMemberships_Call) Return(getControlPlanesIDGroupMembershipsResponse *operations.GetControlPlanesIDGroupMembershipsResponse, err error) *MockControlPlaneGroupSDK_GetControlPlanesIDGroupMemberships_Call {
+	_c.Call.Return(getControlPlanesIDGroupMembershipsResponse, err)
+	return _c
+}
+
+func (_c *MockControlPlaneGroupSDK_GetControlPlanesIDGroupMemberships_Call) RunAndReturn(run func(ctx context.Context, request operations.GetControlPlanesIDGroupMembershipsRequest, opts ...operations.Option) (*operations.GetControlPlanesIDGroupMembershipsResponse, error)) *MockControlPlaneGroupSDK_GetControlPlanesIDGroupMemberships_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PutControlPlanesIDGroupMemberships provides a mock function for the type MockControlPlaneGroupSDK
 func (_mock *MockControlPlaneGroupSDK) PutControlPlanesIDGroupMemberships(ctx context.Context, id string, groupMembership *components.GroupMembership, opts ...operations.Option) (*operations.PutControlPlanesIDGroupMembershipsResponse, error) {
 	var tmpRet mock.Arguments
@@ -1853,6 +1919,297 @@ func (_c *MockKongCredentialJWTSDK_UpsertJwtWithConsumer_Call) RunAndReturn(run
 	return _c
 }
 
+// NewMockKongCredentialMTLSSDK creates a new instance of MockKongCredentialMTLSSDK. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockKongCredentialMTLSSDK(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockKongCredentialMTLSSDK {
+	mock := &MockKongCredentialMTLSSDK{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockKongCredentialMTLSSDK is an autogenerated mock type for the KongCredentialMTLSSDK type
+type MockKongCredentialMTLSSDK struct {
+	mock.Mock
+}
+
+type MockKongCredentialMTLSSDK_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockKongCredentialMTLSSDK) EXPECT() *MockKongCredentialMTLSSDK_Expecter {
+	return &MockKongCredentialMTLSSDK_Expecter{mock: &_m.Mock}
+}
+
+// CreateMtlsAuthWithConsumer provides a mock function for the type MockKongCredentialMTLSSDK
+func (_mock *MockKongCredentialMTLSSDK) CreateMtlsAuthWithConsumer(ctx context.Context, req operations.CreateMtlsAuthWithConsumerRequest, opts ...operations.Option) (*operations.CreateMtlsAuthWithConsumerResponse, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, req, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, req)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMtlsAuthWithConsumer")
+	}
+
+	var r0 *operations.CreateMtlsAuthWithConsumerResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, operations.CreateMtlsAuthWithConsumerRequest, ...operations.Option) (*operations.CreateMtlsAuthWithConsumerResponse, error)); ok {
+		return returnFunc(ctx, req, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, operations.CreateMtlsAuthWithConsumerRequest, ...operations.Option) *operations.CreateMtlsAuthWithConsumerResponse); ok {
+		r0 = returnFunc(ctx, req, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*operations.CreateMtlsAuthWithConsumerResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, operations.CreateMtlsAuthWithConsumerRequest, ...operations.Option) error); ok {
+		r1 = returnFunc(ctx, req, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKongCredentialMTLSSDK_CreateMtlsAuthWithConsumer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateMtlsAuthWithConsumer'
+type MockKongCredentialMTLSSDK_CreateMtlsAuthWithConsumer_Call struct {
+	*mock.Call
+}
+
+// CreateMtlsAuthWithConsumer is a helper method to define mock.On call
+//   - ctx
+//   - req
+//   - opts
+func (_e *MockKongCredentialMTLSSDK_Expecter) CreateMtlsAuthWithConsumer(ctx interface{}, req interface{}, opts ...interface{}) *MockKongCredentialMTLSSDK_CreateMtlsAuthWithConsumer_Call {
+	return &MockKongCredentialMTLSSDK_CreateMtlsAuthWithConsumer_Call{Call: _e.mock.On("CreateMtlsAuthWithConsumer",
+		append([]interface{}{ctx, req}, opts...)...)}
+}
+
+func (_c *MockKongCredentialMTLSSDK_CreateMtlsAuthWithConsumer_Call) Run(run func(ctx context.Context, req operations.CreateMtlsAuthWithConsumerRequest, opts ...operations.Option)) *MockKongCredentialMTLSSDK_CreateMtlsAuthWithConsumer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[2].([]operations.Option)
+		run(args[0].(context.Context), args[1].(operations.CreateMtlsAuthWithConsumerRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockKongCredentialMTLSSDK_CreateMtlsAuthWithConsumer_Call) Return(createMtlsAuthWithConsumerResponse *operations.CreateMtlsAuthWithConsumerResponse, err error) *MockKongCredentialMTLSSDK_CreateMtlsAuthWithConsumer_Call {
+	_c.Call.Return(createMtlsAuthWithConsumerResponse, err)
+	return _c
+}
+
+func (_c *MockKongCredentialMTLSSDK_CreateMtlsAuthWithConsumer_Call) RunAndReturn(run func(ctx context.Context, req operations.CreateMtlsAuthWithConsumerRequest, opts ...operations.Option) (*operations.CreateMtlsAuthWithConsumerResponse, error)) *MockKongCredentialMTLSSDK_CreateMtlsAuthWithConsumer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteMtlsAuthWithConsumer provides a mock function for the type MockKongCredentialMTLSSDK
+func (_mock *MockKongCredentialMTLSSDK) DeleteMtlsAuthWithConsumer(ctx context.Context, request operations.DeleteMtlsAuthWithConsumerRequest, opts ...operations.Option) (*operations.DeleteMtlsAuthWithConsumerResponse, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, request, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, request)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMtlsAuthWithConsumer")
+	}
+
+	var r0 *operations.DeleteMtlsAuthWithConsumerResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, operations.DeleteMtlsAuthWithConsumerRequest, ...operations.Option) (*operations.DeleteMtlsAuthWithConsumerResponse, error)); ok {
+		return returnFunc(ctx, request, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, operations.DeleteMtlsAuthWithConsumerRequest, ...operations.Option) *operations.DeleteMtlsAuthWithConsumerResponse); ok {
+		r0 = returnFunc(ctx, request, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*operations.DeleteMtlsAuthWithConsumerResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, operations.DeleteMtlsAuthWithConsumerRequest, ...operations.Option) error); ok {
+		r1 = returnFunc(ctx, request, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKongCredentialMTLSSDK_DeleteMtlsAuthWithConsumer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMtlsAuthWithConsumer'
+type MockKongCredentialMTLSSDK_DeleteMtlsAuthWithConsumer_Call struct {
+	*mock.Call
+}
+
+// DeleteMtlsAuthWithConsumer is a helper method to define mock.On call
+//   - ctx
+//   - request
+//   - opts
+func (_e *MockKongCredentialMTLSSDK_Expecter) DeleteMtlsAuthWithConsumer(ctx interface{}, request interface{}, opts ...interface{}) *MockKongCredentialMTLSSDK_DeleteMtlsAuthWithConsumer_Call {
+	return &MockKongCredentialMTLSSDK_DeleteMtlsAuthWithConsumer_Call{Call: _e.mock.On("DeleteMtlsAuthWithConsumer",
+		append([]interface{}{ctx, request}, opts...)...)}
+}
+
+func (_c *MockKongCredentialMTLSSDK_DeleteMtlsAuthWithConsumer_Call) Run(run func(ctx context.Context, request operations.DeleteMtlsAuthWithConsumerRequest, opts ...operations.Option)) *MockKongCredentialMTLSSDK_DeleteMtlsAuthWithConsumer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[2].([]operations.Option)
+		run(args[0].(context.Context), args[1].(operations.DeleteMtlsAuthWithConsumerRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockKongCredentialMTLSSDK_DeleteMtlsAuthWithConsumer_Call) Return(deleteMtlsAuthWithConsumerResponse *operations.DeleteMtlsAuthWithConsumerResponse, err error) *MockKongCredentialMTLSSDK_DeleteMtlsAuthWithConsumer_Call {
+	_c.Call.Return(deleteMtlsAuthWithConsumerResponse, err)
+	return _c
+}
+
+func (_c *MockKongCredentialMTLSSDK_DeleteMtlsAuthWithConsumer_Call) RunAndReturn(run func(ctx context.Context, request operations.DeleteMtlsAuthWithConsumerRequest, opts ...operations.Option) (*operations.DeleteMtlsAuthWithConsumerResponse, error)) *MockKongCredentialMTLSSDK_DeleteMtlsAuthWithConsumer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListMtlsAuth provides a mock function for the type MockKongCredentialMTLSSDK
+func (_mock *MockKongCredentialMTLSSDK) ListMtlsAuth(ctx context.Context, request operations.ListMtlsAuthRequest, opts ...operations.Option) (*operations.ListMtlsAuthResponse, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, request, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, request)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMtlsAuth")
+	}
+
+	var r0 *operations.ListMtlsAuthResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, operations.ListMtlsAuthRequest, ...operations.Option) (*operations.ListMtlsAuthResponse, error)); ok {
+		return returnFunc(ctx, request, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, operations.ListMtlsAuthRequest, ...operations.Option) *operations.ListMtlsAuthResponse); ok {
+		r0 = returnFunc(ctx, request, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*operations.ListMtlsAuthResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, operations.ListMtlsAuthRequest, ...operations.Option) error); ok {
+		r1 = returnFunc(ctx, request, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKongCredentialMTLSSDK_ListMtlsAuth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListMtlsAuth'
+type MockKongCredentialMTLSSDK_ListMtlsAuth_Call struct {
+	*mock.Call
+}
+
+// ListMtlsAuth is a helper method to define mock.On call
+//   - ctx
+//   - request
+//   - opts
+func (_e *MockKongCredentialMTLSSDK_Expecter) ListMtlsAuth(ctx interface{}, request interface{}, opts ...interface{}) *MockKongCredentialMTLSSDK_ListMtlsAuth_Call {
+	return &MockKongCredentialMTLSSDK_ListMtlsAuth_Call{Call: _e.mock.On("ListMtlsAuth",
+		append([]interface{}{ctx, request}, opts...)...)}
+}
+
+func (_c *MockKongCredentialMTLSSDK_ListMtlsAuth_Call) Run(run func(ctx context.Context, request operations.ListMtlsAuthRequest, opts ...operations.Option)) *MockKongCredentialMTLSSDK_ListMtlsAuth_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[2].([]operations.Option)
+		run(args[0].(context.Context), args[1].(operations.ListMtlsAuthRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockKongCredentialMTLSSDK_ListMtlsAuth_Call) Return(listMtlsAuthResponse *operations.ListMtlsAuthResponse, err error) *MockKongCredentialMTLSSDK_ListMtlsAuth_Call {
+	_c.Call.Return(listMtlsAuthResponse, err)
+	return _c
+}
+
+func (_c *MockKongCredentialMTLSSDK_ListMtlsAuth_Call) RunAndReturn(run func(ctx context.Context, request operations.ListMtlsAuthRequest, opts ...operations.Option) (*operations.ListMtlsAuthResponse, error)) *MockKongCredentialMTLSSDK_ListMtlsAuth_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertMtlsAuthWithConsumer provides a mock function for the type MockKongCredentialMTLSSDK
+func (_mock *MockKongCredentialMTLSSDK) UpsertMtlsAuthWithConsumer(ctx context.Context, request operations.UpsertMtlsAuthWithConsumerRequest, opts ...operations.Option) (*operations.UpsertMtlsAuthWithConsumerResponse, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, request, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, request)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertMtlsAuthWithConsumer")
+	}
+
+	var r0 *operations.UpsertMtlsAuthWithConsumerResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, operations.UpsertMtlsAuthWithConsumerRequest, ...operations.Option) (*operations.UpsertMtlsAuthWithConsumerResponse, error)); ok {
+		return returnFunc(ctx, request, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, operations.UpsertMtlsAuthWithConsumerRequest, ...operations.Option) *operations.UpsertMtlsAuthWithConsumerResponse); ok {
+		r0 = returnFunc(ctx, request, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*operations.UpsertMtlsAuthWithConsumerResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, operations.UpsertMtlsAuthWithConsumerRequest, ...operations.Option) error); ok {
+		r1 = returnFunc(ctx, request, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKongCredentialMTLSSDK_UpsertMtlsAuthWithConsumer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertMtlsAuthWithConsumer'
+type MockKongCredentialMTLSSDK_UpsertMtlsAuthWithConsumer_Call struct {
+	*mock.Call
+}
+
+// UpsertMtlsAuthWithConsumer is a helper method to define mock.On call
+//   - ctx
+//   - request
+//   - opts
+func (_e *MockKongCredentialMTLSSDK_Expecter) UpsertMtlsAuthWithConsumer(ctx interface{}, request interface{}, opts ...interface{}) *MockKongCredentialMTLSSDK_UpsertMtlsAuthWithConsumer_Call {
+	return &MockKongCredentialMTLSSDK_UpsertMtlsAuthWithConsumer_Call{Call: _e.mock.On("UpsertMtlsAuthWithConsumer",
+		append([]interface{}{ctx, request}, opts...)...)}
+}
+
+func (_c *MockKongCredentialMTLSSDK_UpsertMtlsAuthWithConsumer_Call) Run(run func(ctx context.Context, request operations.UpsertMtlsAuthWithConsumerRequest, opts ...operations.Option)) *MockKongCredentialMTLSSDK_UpsertMtlsAuthWithConsumer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[2].([]operations.Option)
+		run(args[0].(context.Context), args[1].(operations.UpsertMtlsAuthWithConsumerRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockKongCredentialMTLSSDK_UpsertMtlsAuthWithConsumer_Call) Return(upsertMtlsAuthWithConsumerResponse *operations.UpsertMtlsAuthWithConsumerResponse, err error) *MockKongCredentialMTLSSDK_UpsertMtlsAuthWithConsumer_Call {
+	_c.Call.Return(upsertMtlsAuthWithConsumerResponse, err)
+	return _c
+}
+
+func (_c *MockKongCredentialMTLSSDK_UpsertMtlsAuthWithConsumer_Call) RunAndReturn(run func(ctx context.Context, request operations.UpsertMtlsAuthWithConsumerRequest, opts ...operations.Option) (*operations.UpsertMtlsAuthWithConsumerResponse, error)) *MockKongCredentialMTLSSDK_UpsertMtlsAuthWithConsumer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockCloudGatewaysSDK creates a new instance of MockCloudGatewaysSDK. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockCloudGatewaysSDK(t interface {
@@ -4273,6 +4630,186 @@ func (_c *MockDataPlaneClientCertificatesSDK_ListDpClientCertificates_Call) RunA
 	return _c
 }
 
+// NewMockEntityMetadataSDK creates a new instance of MockEntityMetadataSDK. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockEntityMetadataSDK(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockEntityMetadataSDK {
+	mock := &MockEntityMetadataSDK{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockEntityMetadataSDK is an autogenerated mock type for the EntityMetadataSDK type
+type MockEntityMetadataSDK struct {
+	mock.Mock
+}
+
+type MockEntityMetadataSDK_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockEntityMetadataSDK) EXPECT() *MockEntityMetadataSDK_Expecter {
+	return &MockEntityMetadataSDK_Expecter{mock: &_m.Mock}
+}
+
+// GetEntityTags provides a mock function for the type MockEntityMetadataSDK
+func (_mock *MockEntityMetadataSDK) GetEntityTags(ctx context.Context, controlPlaneID string, path string) ([]string, error) {
+	ret := _mock.Called(ctx, controlPlaneID, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEntityTags")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) ([]string, error)); ok {
+		return returnFunc(ctx, controlPlaneID, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = returnFunc(ctx, controlPlaneID, path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, controlPlaneID, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEntityMetadataSDK_GetEntityTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEntityTags'
+type MockEntityMetadataSDK_GetEntityTags_Call struct {
+	*mock.Call
+}
+
+// GetEntityTags is a helper method to define mock.On call
+//   - ctx
+//   - controlPlaneID
+//   - path
+func (_e *MockEntityMetadataSDK_Expecter) GetEntityTags(ctx interface{}, controlPlaneID interface{}, path interface{}) *MockEntityMetadataSDK_GetEntityTags_Call {
+	return &MockEntityMetadataSDK_GetEntityTags_Call{Call: _e.mock.On("GetEntityTags", ctx, controlPlaneID, path)}
+}
+
+func (_c *MockEntityMetadataSDK_GetEntityTags_Call) Run(run func(ctx context.Context, controlPlaneID string, path string)) *MockEntityMetadataSDK_GetEntityTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockEntityMetadataSDK_GetEntityTags_Call) Return(tags []string, err error) *MockEntityMetadataSDK_GetEntityTags_Call {
+	_c.Call.Return(tags, err)
+	return _c
+}
+
+func (_c *MockEntityMetadataSDK_GetEntityTags_Call) RunAndReturn(run func(ctx context.Context, controlPlaneID string, path string) ([]string, error)) *MockEntityMetadataSDK_GetEntityTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PatchEntityTags provides a mock function for the type MockEntityMetadataSDK
+func (_mock *MockEntityMetadataSDK) PatchEntityTags(ctx context.Context, controlPlaneID string, path string, tags []string) error {
+	ret := _mock.Called(ctx, controlPlaneID, path, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchEntityTags")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, []string) error); ok {
+		r0 = returnFunc(ctx, controlPlaneID, path, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockEntityMetadataSDK_PatchEntityTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PatchEntityTags'
+type MockEntityMetadataSDK_PatchEntityTags_Call struct {
+	*mock.Call
+}
+
+// PatchEntityTags is a helper method to define mock.On call
+//   - ctx
+//   - controlPlaneID
+//   - path
+//   - tags
+func (_e *MockEntityMetadataSDK_Expecter) PatchEntityTags(ctx interface{}, controlPlaneID interface{}, path interface{}, tags interface{}) *MockEntityMetadataSDK_PatchEntityTags_Call {
+	return &MockEntityMetadataSDK_PatchEntityTags_Call{Call: _e.mock.On("PatchEntityTags", ctx, controlPlaneID, path, tags)}
+}
+
+func (_c *MockEntityMetadataSDK_PatchEntityTags_Call) Run(run func(ctx context.Context, controlPlaneID string, path string, tags []string)) *MockEntityMetadataSDK_PatchEntityTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *MockEntityMetadataSDK_PatchEntityTags_Call) Return(err error) *MockEntityMetadataSDK_PatchEntityTags_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockEntityMetadataSDK_PatchEntityTags_Call) RunAndReturn(run func(ctx context.Context, controlPlaneID string, path string, tags []string) error) *MockEntityMetadataSDK_PatchEntityTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveControlPlaneLabels provides a mock function for the type MockEntityMetadataSDK
+func (_mock *MockEntityMetadataSDK) RemoveControlPlaneLabels(ctx context.Context, controlPlaneID string, keys []string) error {
+	ret := _mock.Called(ctx, controlPlaneID, keys)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveControlPlaneLabels")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = returnFunc(ctx, controlPlaneID, keys)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockEntityMetadataSDK_RemoveControlPlaneLabels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveControlPlaneLabels'
+type MockEntityMetadataSDK_RemoveControlPlaneLabels_Call struct {
+	*mock.Call
+}
+
+// RemoveControlPlaneLabels is a helper method to define mock.On call
+//   - ctx
+//   - controlPlaneID
+//   - keys
+func (_e *MockEntityMetadataSDK_Expecter) RemoveControlPlaneLabels(ctx interface{}, controlPlaneID interface{}, keys interface{}) *MockEntityMetadataSDK_RemoveControlPlaneLabels_Call {
+	return &MockEntityMetadataSDK_RemoveControlPlaneLabels_Call{Call: _e.mock.On("RemoveControlPlaneLabels", ctx, controlPlaneID, keys)}
+}
+
+func (_c *MockEntityMetadataSDK_RemoveControlPlaneLabels_Call) Run(run func(ctx context.Context, controlPlaneID string, keys []string)) *MockEntityMetadataSDK_RemoveControlPlaneLabels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MockEntityMetadataSDK_RemoveControlPlaneLabels_Call) Return(err error) *MockEntityMetadataSDK_RemoveControlPlaneLabels_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockEntityMetadataSDK_RemoveControlPlaneLabels_Call) RunAndReturn(run func(ctx context.Context, controlPlaneID string, keys []string) error) *MockEntityMetadataSDK_RemoveControlPlaneLabels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockKeysSDK creates a new instance of MockKeysSDK. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockKeysSDK(t interface {
@@ -6705,6 +7242,100 @@ func (_c *MockMeSDK_GetOrganizationsMe_Call) RunAndReturn(run func(ctx context.C
 	return _c
 }
 
+// NewMockSystemAccountsAccessTokensSDK creates a new instance of MockSystemAccountsAccessTokensSDK. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSystemAccountsAccessTokensSDK(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSystemAccountsAccessTokensSDK {
+	mock := &MockSystemAccountsAccessTokensSDK{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockSystemAccountsAccessTokensSDK is an autogenerated mock type for the SystemAccountsAccessTokensSDK type
+type MockSystemAccountsAccessTokensSDK struct {
+	mock.Mock
+}
+
+type MockSystemAccountsAccessTokensSDK_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSystemAccountsAccessTokensSDK) EXPECT() *MockSystemAccountsAccessTokensSDK_Expecter {
+	return &MockSystemAccountsAccessTokensSDK_Expecter{mock: &_m.Mock}
+}
+
+// GetSystemAccountsIDAccessTokensID provides a mock function for the type MockSystemAccountsAccessTokensSDK
+func (_mock *MockSystemAccountsAccessTokensSDK) GetSystemAccountsIDAccessTokensID(ctx context.Context, accountID string, tokenID string, opts ...operations.Option) (*operations.GetSystemAccountsIDAccessTokensIDResponse, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, accountID, tokenID, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, accountID, tokenID)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSystemAccountsIDAccessTokensID")
+	}
+
+	var r0 *operations.GetSystemAccountsIDAccessTokensIDResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, ...operations.Option) (*operations.GetSystemAccountsIDAccessTokensIDResponse, error)); ok {
+		return returnFunc(ctx, accountID, tokenID, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, ...operations.Option) *operations.GetSystemAccountsIDAccessTokensIDResponse); ok {
+		r0 = returnFunc(ctx, accountID, tokenID, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*operations.GetSystemAccountsIDAccessTokensIDResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, ...operations.Option) error); ok {
+		r1 = returnFunc(ctx, accountID, tokenID, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSystemAccountsAccessTokensSDK_GetSystemAccountsIDAccessTokensID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSystemAccountsIDAccessTokensID'
+type MockSystemAccountsAccessTokensSDK_GetSystemAccountsIDAccessTokensID_Call struct {
+	*mock.Call
+}
+
+// GetSystemAccountsIDAccessTokensID is a helper method to define mock.On call
+//   - ctx
+//   - accountID
+//   - tokenID
+//   - opts
+func (_e *MockSystemAccountsAccessTokensSDK_Expecter) GetSystemAccountsIDAccessTokensID(ctx interface{}, accountID interface{}, tokenID interface{}, opts ...interface{}) *MockSystemAccountsAccessTokensSDK_GetSystemAccountsIDAccessTokensID_Call {
+	return &MockSystemAccountsAccessTokensSDK_GetSystemAccountsIDAccessTokensID_Call{Call: _e.mock.On("GetSystemAccountsIDAccessTokensID",
+		append([]interface{}{ctx, accountID, tokenID}, opts...)...)}
+}
+
+func (_c *MockSystemAccountsAccessTokensSDK_GetSystemAccountsIDAccessTokensID_Call) Run(run func(ctx context.Context, accountID string, tokenID string, opts ...operations.Option)) *MockSystemAccountsAccessTokensSDK_GetSystemAccountsIDAccessTokensID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := args[3].([]operations.Option)
+		run(args[0].(context.Context), args[1].(string), args[2].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockSystemAccountsAccessTokensSDK_GetSystemAccountsIDAccessTokensID_Call) Return(getSystemAccountsIDAccessTokensIDResponse *operations.GetSystemAccountsIDAccessTokensIDResponse, err error) *MockSystemAccountsAccessTokensSDK_GetSystemAccountsIDAccessTokensID_Call {
+	_c.Call.Return(getSystemAccountsIDAccessTokensIDResponse, err)
+	return _c
+}
+
+func (_c *MockSystemAccountsAccessTokensSDK_GetSystemAccountsIDAccessTokensID_Call) RunAndReturn(run func(ctx context.Context, accountID string, tokenID string, opts ...operations.Option) (*operations.GetSystemAccountsIDAccessTokensIDResponse, error)) *MockSystemAccountsAccessTokensSDK_GetSystemAccountsIDAccessTokensID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockPluginSDK creates a new instance of MockPluginSDK. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockPluginSDK(t interface {
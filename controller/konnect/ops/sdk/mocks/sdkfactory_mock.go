@@ -24,11 +24,13 @@ type MockSDKWrapper struct {
 	UpstreamsSDK                *MockUpstreamsSDK
 	TargetsSDK                  *MockTargetsSDK
 	MeSDK                       *MockMeSDK
+	SystemAccountsAccessTokens  *MockSystemAccountsAccessTokensSDK
 	KongCredentialsBasicAuthSDK *MockKongCredentialBasicAuthSDK
 	KongCredentialsAPIKeySDK    *MockKongCredentialAPIKeySDK
 	KongCredentialsACLSDK       *MockKongCredentialACLSDK
 	KongCredentialsJWTSDK       *MockKongCredentialJWTSDK
 	KongCredentialsHMACSDK      *MockKongCredentialHMACSDK
+	KongCredentialsMTLSSDK      *MockKongCredentialMTLSSDK
 	CACertificatesSDK           *MockCACertificatesSDK
 	CertificatesSDK             *MockCertificatesSDK
 	VaultSDK                    *MockVaultSDK
@@ -36,6 +38,7 @@ type MockSDKWrapper struct {
 	KeySetsSDK                  *MockKeySetsSDK
 	SNIsSDK                     *MockSNIsSDK
 	DataPlaneCertificatesSDK    *MockDataPlaneClientCertificatesSDK
+	EntityMetadataSDK           *MockEntityMetadataSDK
 	server                      server.Server
 }
 
@@ -54,11 +57,13 @@ func NewMockSDKWrapperWithT(t *testing.T) *MockSDKWrapper {
 		UpstreamsSDK:                NewMockUpstreamsSDK(t),
 		TargetsSDK:                  NewMockTargetsSDK(t),
 		MeSDK:                       NewMockMeSDK(t),
+		SystemAccountsAccessTokens:  NewMockSystemAccountsAccessTokensSDK(t),
 		KongCredentialsBasicAuthSDK: NewMockKongCredentialBasicAuthSDK(t),
 		KongCredentialsAPIKeySDK:    NewMockKongCredentialAPIKeySDK(t),
 		KongCredentialsACLSDK:       NewMockKongCredentialACLSDK(t),
 		KongCredentialsJWTSDK:       NewMockKongCredentialJWTSDK(t),
 		KongCredentialsHMACSDK:      NewMockKongCredentialHMACSDK(t),
+		KongCredentialsMTLSSDK:      NewMockKongCredentialMTLSSDK(t),
 		CACertificatesSDK:           NewMockCACertificatesSDK(t),
 		CertificatesSDK:             NewMockCertificatesSDK(t),
 		VaultSDK:                    NewMockVaultSDK(t),
@@ -66,6 +71,7 @@ func NewMockSDKWrapperWithT(t *testing.T) *MockSDKWrapper {
 		KeySetsSDK:                  NewMockKeySetsSDK(t),
 		SNIsSDK:                     NewMockSNIsSDK(t),
 		DataPlaneCertificatesSDK:    NewMockDataPlaneClientCertificatesSDK(t),
+		EntityMetadataSDK:           NewMockEntityMetadataSDK(t),
 
 		server: lo.Must(server.NewServer[*operatorv1beta1.ControlPlane](SDKServerURL)),
 	}
@@ -136,6 +142,10 @@ func (m MockSDKWrapper) GetHMACCredentialsSDK() sdkops.KongCredentialHMACSDK {
 	return m.KongCredentialsHMACSDK
 }
 
+func (m MockSDKWrapper) GetMTLSCredentialsSDK() sdkops.KongCredentialMTLSSDK {
+	return m.KongCredentialsMTLSSDK
+}
+
 func (m MockSDKWrapper) GetTargetsSDK() sdkops.TargetsSDK {
 	return m.TargetsSDK
 }
@@ -148,6 +158,10 @@ func (m MockSDKWrapper) GetMeSDK() sdkops.MeSDK {
 	return m.MeSDK
 }
 
+func (m MockSDKWrapper) GetSystemAccountsAccessTokensSDK() sdkops.SystemAccountsAccessTokensSDK {
+	return m.SystemAccountsAccessTokens
+}
+
 func (m MockSDKWrapper) GetCACertificatesSDK() sdkops.CACertificatesSDK {
 	return m.CACertificatesSDK
 }
@@ -176,6 +190,10 @@ func (m MockSDKWrapper) GetCloudGatewaysSDK() sdkops.CloudGatewaysSDK {
 	return m.CloudGatewaysSDK
 }
 
+func (m MockSDKWrapper) GetEntityMetadataSDK() sdkops.EntityMetadataSDK {
+	return m.EntityMetadataSDK
+}
+
 type MockSDKFactory struct {
 	t   *testing.T
 	SDK *MockSDKWrapper
@@ -20,11 +20,13 @@ type SDKWrapper interface {
 	GetTargetsSDK() TargetsSDK
 	GetVaultSDK() VaultSDK
 	GetMeSDK() MeSDK
+	GetSystemAccountsAccessTokensSDK() SystemAccountsAccessTokensSDK
 	GetBasicAuthCredentialsSDK() KongCredentialBasicAuthSDK
 	GetAPIKeyCredentialsSDK() KongCredentialAPIKeySDK
 	GetACLCredentialsSDK() KongCredentialACLSDK
 	GetJWTCredentialsSDK() KongCredentialJWTSDK
 	GetHMACCredentialsSDK() KongCredentialHMACSDK
+	GetMTLSCredentialsSDK() KongCredentialMTLSSDK
 	GetCACertificatesSDK() CACertificatesSDK
 	GetCertificatesSDK() CertificatesSDK
 	GetKeysSDK() KeysSDK
@@ -32,6 +34,7 @@ type SDKWrapper interface {
 	GetSNIsSDK() SNIsSDK
 	GetDataPlaneCertificatesSDK() DataPlaneClientCertificatesSDK
 	GetCloudGatewaysSDK() CloudGatewaysSDK
+	GetEntityMetadataSDK() EntityMetadataSDK
 
 	// GetServerURL returns the server URL for recording metrics.
 	GetServerURL() string
@@ -39,8 +42,9 @@ type SDKWrapper interface {
 }
 
 type sdkWrapper struct {
-	server server.Server
-	sdk    *sdkkonnectgo.SDK
+	server         server.Server
+	sdk            *sdkkonnectgo.SDK
+	entityMetadata EntityMetadataSDK
 }
 
 var _ SDKWrapper = sdkWrapper{}
@@ -150,6 +154,16 @@ func (w sdkWrapper) GetHMACCredentialsSDK() KongCredentialHMACSDK {
 	return w.sdk.HMACAuthCredentials
 }
 
+// GetSystemAccountsAccessTokensSDK returns the SDK to get system accounts access tokens.
+func (w sdkWrapper) GetSystemAccountsAccessTokensSDK() SystemAccountsAccessTokensSDK {
+	return w.sdk.SystemAccountsAccessTokens
+}
+
+// GetMTLSCredentialsSDK returns the SDK to operate mTLS auth credentials.
+func (w sdkWrapper) GetMTLSCredentialsSDK() KongCredentialMTLSSDK {
+	return w.sdk.MTLSAuthCredentials
+}
+
 // GetKeysSDK returns the SDK to operate keys.
 func (w sdkWrapper) GetKeysSDK() KeysSDK {
 	return w.sdk.Keys
@@ -170,6 +184,12 @@ func (w sdkWrapper) GetCloudGatewaysSDK() CloudGatewaysSDK {
 	return w.sdk.CloudGateways
 }
 
+// GetEntityMetadataSDK returns the SDK to operate the tags and labels of Konnect
+// entities without upserting them.
+func (w sdkWrapper) GetEntityMetadataSDK() EntityMetadataSDK {
+	return w.entityMetadata
+}
+
 // SDKToken is a token used to authenticate with the Konnect SDK.
 type SDKToken string
 
@@ -197,5 +217,6 @@ func (f sdkFactory) NewKonnectSDK(server server.Server, token SDKToken) SDKWrapp
 			),
 			sdkkonnectgo.WithServerURL(server.URL()),
 		),
+		entityMetadata: NewEntityMetadataSDK(server.URL(), token, nil),
 	}
 }
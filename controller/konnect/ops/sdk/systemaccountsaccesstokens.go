@@ -0,0 +1,13 @@
+package sdk
+
+import (
+	"context"
+
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+)
+
+// SystemAccountsAccessTokensSDK is the interface for the Konnect system accounts
+// access tokens SDK.
+type SystemAccountsAccessTokensSDK interface {
+	GetSystemAccountsIDAccessTokensID(ctx context.Context, accountID string, tokenID string, opts ...sdkkonnectops.Option) (*sdkkonnectops.GetSystemAccountsIDAccessTokensIDResponse, error)
+}
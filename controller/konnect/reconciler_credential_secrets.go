@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
@@ -11,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kong/gateway-operator/controller/konnect/constraints"
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
 	"github.com/kong/gateway-operator/internal/utils/index"
@@ -48,6 +51,11 @@ const (
 	// KongCredentialTypeHMAC is the type of HMAC credential, it's used
 	// as the value for konghq.com/credential label.
 	KongCredentialTypeHMAC = "hmac"
+	// KongCredentialTypeMTLS is the type of mtls-auth credential, it's used
+	// as the value for konghq.com/credential label.
+	// There is no Kubernetes resource for mtls-auth credentials, they are created
+	// in Konnect directly.
+	KongCredentialTypeMTLS = "mtls-auth"
 )
 
 const (
@@ -67,25 +75,37 @@ const (
 	CredentialSecretKeyNameHMACUsername = "username"
 	// CredentialSecretKeyNameHMACSecret is the credential secret key name for HMAC secret type.
 	CredentialSecretKeyNameHMACSecret = "secret"
+	// CredentialSecretKeyNameMTLSSubjectName is the credential secret key name for mtls-auth subject name.
+	CredentialSecretKeyNameMTLSSubjectName = "subject_name"
+	// CredentialSecretKeyNameMTLSCACertificate is the credential secret key name for the name
+	// of the KongCACertificate (from the Secret's namespace) the mtls-auth client certificates
+	// have to be issued by.
+	CredentialSecretKeyNameMTLSCACertificate = "ca_certificate"
 )
 
 // KongCredentialSecretReconciler reconciles a KongPlugin object.
 type KongCredentialSecretReconciler struct {
-	loggingMode logging.Mode
-	client      client.Client
-	scheme      *runtime.Scheme
+	sdkFactory    sdkops.SDKFactory
+	loggingMode   logging.Mode
+	client        client.Client
+	scheme        *runtime.Scheme
+	eventRecorder record.EventRecorder
+	now           func() time.Time
 }
 
 // NewKongCredentialSecretReconciler creates a new KongCredentialSecretReconciler.
 func NewKongCredentialSecretReconciler(
+	sdkFactory sdkops.SDKFactory,
 	loggingMode logging.Mode,
 	client client.Client,
 	scheme *runtime.Scheme,
 ) *KongCredentialSecretReconciler {
 	return &KongCredentialSecretReconciler{
+		sdkFactory:  sdkFactory,
 		loggingMode: loggingMode,
 		client:      client,
 		scheme:      scheme,
+		now:         time.Now,
 	}
 }
 
@@ -104,14 +124,21 @@ func (r *KongCredentialSecretReconciler) SetupWithManager(_ context.Context, mgr
 		return err
 	}
 
+	r.eventRecorder = mgr.GetEventRecorderFor("KongCredentialSecret")
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("KongCredentialSecret").
 		For(
 			&corev1.Secret{},
 			builder.WithPredicates(
 				labelSelectorPredicate,
-				predicate.NewPredicateFuncs(
-					secretIsUsedByConsumerAttachedToKonnectControlPlane(mgr.GetClient()),
+				predicate.Or(
+					predicate.NewPredicateFuncs(
+						secretIsUsedByConsumerAttachedToKonnectControlPlane(mgr.GetClient()),
+					),
+					// Secrets no longer used by consumers still have to be reconciled
+					// to delete the mtls-auth credentials created in Konnect out of them.
+					predicate.NewPredicateFuncs(secretHasMTLSCredentialCleanupFinalizer),
 				),
 			),
 		).
@@ -121,6 +148,9 @@ func (r *KongCredentialSecretReconciler) SetupWithManager(_ context.Context, mgr
 				predicate.NewPredicateFuncs(objRefersToKonnectGatewayControlPlane[configurationv1.KongConsumer]),
 			),
 		).
+		Watches(&configurationv1alpha1.KongCACertificate{},
+			handler.EnqueueRequestsFromMapFunc(enqueueMTLSCredentialSecretsForKongCACertificate(mgr.GetClient())),
+		).
 		// NOTE: We use MatchEveryOwner because we set both the KongConsumer and
 		// the Secret holding the credentials as owners.
 		// The KongConsumer is set for obvious reasons, when that's deleted we want
@@ -202,6 +232,11 @@ func (r *KongCredentialSecretReconciler) Reconcile(ctx context.Context, req ctrl
 
 	if !secret.GetDeletionTimestamp().IsZero() {
 		log.Debug(logger, "secret is being deleted")
+		// mtls-auth credentials only exist in Konnect, delete them before
+		// letting the Secret go.
+		if secretHasMTLSCredentialCleanupFinalizer(&secret) {
+			return r.reconcileMTLSCredentialSecret(ctx, &secret, nil)
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -212,6 +247,12 @@ func (r *KongCredentialSecretReconciler) Reconcile(ctx context.Context, req ctrl
 		return ctrl.Result{}, err
 	}
 
+	// Generate or rotate the credential values when requested.
+	rotationRes, err := r.ensureGeneratedCredentialSecret(ctx, &secret, credType)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Validate the Secret using the credential type label.
 	if err := validateSecret(&secret, credType); err != nil {
 		return ctrl.Result{}, err
@@ -230,6 +271,10 @@ func (r *KongCredentialSecretReconciler) Reconcile(ctx context.Context, req ctrl
 		return ctrl.Result{}, fmt.Errorf("failed listing KongConsumers for Secret: %w", err)
 	}
 
+	if credType == KongCredentialTypeMTLS {
+		return r.reconcileMTLSCredentialSecret(ctx, &secret, kongConsumerList.Items)
+	}
+
 	switch len(kongConsumerList.Items) {
 	case 0:
 		// If there are no Consumers that use the Secret then remove all the managed
@@ -240,15 +285,26 @@ func (r *KongCredentialSecretReconciler) Reconcile(ctx context.Context, req ctrl
 
 	default:
 		for _, kongConsumer := range kongConsumerList.Items {
-			if res, err := r.handleConsumerUsingCredentialSecret(ctx, &secret, credType, &kongConsumer); err != nil || !res.IsZero() {
+			res, err := r.handleConsumerUsingCredentialSecret(ctx, &secret, credType, &kongConsumer)
+			if err != nil {
 				return ctrl.Result{}, err
-			} else if !res.IsZero() {
+			}
+			if res.Requeue {
 				return res, nil
 			}
+			rotationRes = earliestRequeue(rotationRes, res)
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return rotationRes, nil
+}
+
+// earliestRequeue returns the result which requeues the earliest.
+func earliestRequeue(a, b ctrl.Result) ctrl.Result {
+	if a.RequeueAfter == 0 || (b.RequeueAfter > 0 && b.RequeueAfter < a.RequeueAfter) {
+		return b
+	}
+	return a
 }
 
 const (
@@ -516,6 +572,10 @@ func validateSecret(
 		if err := validateSecretForKongCredentialHMAC(s); err != nil {
 			return err
 		}
+	case KongCredentialTypeMTLS:
+		if err := validateSecretForKongCredentialMTLS(s); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("Secret %s used as credential, but has unsupported type %s",
 			nn, credType,
@@ -765,7 +825,7 @@ func setKongCredentialHMACSpec(
 	spec.Secret = lo.ToPtr(string(s.Data[CredentialSecretKeyNameHMACSecret]))
 }
 
-func (r KongCredentialSecretReconciler) handleConsumerUsingCredentialSecret(
+func (r *KongCredentialSecretReconciler) handleConsumerUsingCredentialSecret(
 	ctx context.Context,
 	s *corev1.Secret,
 	credType string,
@@ -785,7 +845,7 @@ func (r KongCredentialSecretReconciler) handleConsumerUsingCredentialSecret(
 			return ctrl.Result{}, fmt.Errorf("failed listing KongCredentialBasicAuth: %w", err)
 		}
 
-		if res, err := handleCreds(ctx, r.client, s, credType, consumer, l.Items, r.scheme); err != nil || !res.IsZero() {
+		if res, err := handleCreds(ctx, r, s, credType, consumer, l.Items); err != nil || !res.IsZero() {
 			return res, err
 		}
 
@@ -802,7 +862,7 @@ func (r KongCredentialSecretReconciler) handleConsumerUsingCredentialSecret(
 			return ctrl.Result{}, fmt.Errorf("failed listing KongCredentialAPIKey: %w", err)
 		}
 
-		if res, err := handleCreds(ctx, r.client, s, credType, consumer, l.Items, r.scheme); err != nil || !res.IsZero() {
+		if res, err := handleCreds(ctx, r, s, credType, consumer, l.Items); err != nil || !res.IsZero() {
 			return res, err
 		}
 
@@ -818,7 +878,7 @@ func (r KongCredentialSecretReconciler) handleConsumerUsingCredentialSecret(
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed listing KongCredentialACL: %w", err)
 		}
-		if res, err := handleCreds(ctx, r.client, s, credType, consumer, l.Items, r.scheme); err != nil || !res.IsZero() {
+		if res, err := handleCreds(ctx, r, s, credType, consumer, l.Items); err != nil || !res.IsZero() {
 			return res, err
 		}
 
@@ -834,7 +894,7 @@ func (r KongCredentialSecretReconciler) handleConsumerUsingCredentialSecret(
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed listing KongCrenentialJWT: %w", err)
 		}
-		if res, err := handleCreds(ctx, r.client, s, credType, consumer, l.Items, r.scheme); err != nil || !res.IsZero() {
+		if res, err := handleCreds(ctx, r, s, credType, consumer, l.Items); err != nil || !res.IsZero() {
 			return res, err
 		}
 
@@ -850,7 +910,7 @@ func (r KongCredentialSecretReconciler) handleConsumerUsingCredentialSecret(
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed listing KongCredentialHMAC: %w", err)
 		}
-		if res, err := handleCreds(ctx, r.client, s, credType, consumer, l.Items, r.scheme); err != nil || !res.IsZero() {
+		if res, err := handleCreds(ctx, r, s, credType, consumer, l.Items); err != nil || !res.IsZero() {
 			return res, err
 		}
 
@@ -868,16 +928,28 @@ func handleCreds[
 	TPtr constraints.KongCredential[T],
 ](
 	ctx context.Context,
-	cl client.Client,
+	r *KongCredentialSecretReconciler,
 	s *corev1.Secret,
 	credType string,
 	consumer *configurationv1.KongConsumer,
 	creds []T,
-	scheme *runtime.Scheme,
 ) (ctrl.Result, error) {
+	cl := r.client
+
+	// Credentials retired by a rotation are kept until the rotation overlap
+	// elapses and are not considered when enforcing the Secret's credential.
+	if err := retireReplacedCredentials[T, TPtr](ctx, cl, s, creds, r.now()); err != nil {
+		return ctrl.Result{}, err
+	}
+	creds, retired, activeProgrammed := splitRetiredCredentials[T, TPtr](creds)
+	retiredRes, err := r.deleteRetiredCredentials(ctx, s, retired, activeProgrammed)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	switch len(creds) {
 	case 0:
-		if err := ensureCredentialExists(ctx, cl, s, credType, consumer, scheme); err != nil {
+		if err := ensureCredentialExists(ctx, cl, s, credType, consumer, r.scheme); err != nil {
 			return ctrl.Result{}, err
 		}
 
@@ -905,5 +977,5 @@ func handleCreds[
 
 	}
 
-	return ctrl.Result{}, nil
+	return retiredRes, nil
 }
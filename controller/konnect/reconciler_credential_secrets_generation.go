@@ -0,0 +1,379 @@
+package konnect
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/konnect/constraints"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+const (
+	// KongCredentialGeneratedEventReason is the reason of the event emitted
+	// when credential values are generated in a Secret.
+	KongCredentialGeneratedEventReason = "KongCredentialGenerated"
+	// KongCredentialRotatedEventReason is the reason of the event emitted
+	// when credential values in a Secret are rotated.
+	KongCredentialRotatedEventReason = "KongCredentialRotated"
+	// KongCredentialRetiredDeletedEventReason is the reason of the event emitted
+	// when a credential retired by a rotation is deleted after the rotation overlap.
+	KongCredentialRetiredDeletedEventReason = "KongCredentialRetiredDeleted"
+	// KongCredentialRotationRejectedEventReason is the reason of the event emitted
+	// when the rotation of a credential which username is set in the Secret is due.
+	KongCredentialRotationRejectedEventReason = "KongCredentialRotationRejected"
+)
+
+// credentialGeneratedValueLength is the number of random bytes used for generated
+// keys, passwords and secrets.
+const credentialGeneratedValueLength = 32
+
+// generatableCredentialSecretKeys maps credential types which support generation
+// to the Secret keys holding their secret values.
+var generatableCredentialSecretKeys = map[string][]string{
+	KongCredentialTypeAPIKey:    {CredentialSecretKeyNameAPIKeyKey},
+	KongCredentialTypeBasicAuth: {corev1.BasicAuthPasswordKey},
+	KongCredentialTypeHMAC:      {CredentialSecretKeyNameHMACSecret},
+}
+
+// credentialSecretUsernameKeys maps credential types which support generation
+// to the Secret keys holding their usernames.
+// Usernames are generated only when missing from the Secret.
+var credentialSecretUsernameKeys = map[string]string{
+	KongCredentialTypeBasicAuth: corev1.BasicAuthUsernameKey,
+	KongCredentialTypeHMAC:      CredentialSecretKeyNameHMACUsername,
+}
+
+// ensureGeneratedCredentialSecret generates the missing credential values in
+// a Secret annotated with konnect.konghq.com/credential-generate and rotates
+// them when a rotation is due.
+// The credentials using the previous values are retired afterwards, once the
+// Secret is updated (see retireReplacedCredentials).
+// Credentials which username is set in the Secret (and not generated) are not
+// rotated: usernames have to be unique so a new credential cannot be created
+// while the previous one is kept for the rotation overlap, and updating the
+// credential in place would break its clients at once.
+// It returns a result with RequeueAfter set to the time of the next scheduled rotation.
+func (r *KongCredentialSecretReconciler) ensureGeneratedCredentialSecret(
+	ctx context.Context,
+	secret *corev1.Secret,
+	credType string,
+) (ctrl.Result, error) {
+	if secret.Annotations[consts.KongCredentialGenerateAnnotationKey] != "true" {
+		return ctrl.Result{}, nil
+	}
+	valueKeys, ok := generatableCredentialSecretKeys[credType]
+	if !ok {
+		return ctrl.Result{}, fmt.Errorf("Secret %s requests credential generation, but credential type %s does not support it",
+			client.ObjectKeyFromObject(secret), credType,
+		)
+	}
+	interval, err := durationFromAnnotation(secret, consts.KongCredentialRotationIntervalAnnotationKey, 0)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var (
+		now           = r.now()
+		old           = secret.DeepCopy()
+		generatedKeys = generatedCredentialSecretKeys(secret)
+		generated     []string
+	)
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	if usernameKey, ok := credentialSecretUsernameKeys[credType]; ok {
+		if _, ok := secret.Data[usernameKey]; !ok {
+			secret.Data[usernameKey] = []byte(generateCredentialUsername(secret))
+			generated = append(generated, usernameKey)
+		}
+	}
+	for _, k := range valueKeys {
+		if _, ok := secret.Data[k]; !ok {
+			secret.Data[k] = []byte(generateCredentialValue())
+			generated = append(generated, k)
+		}
+	}
+
+	var (
+		usernameKey, hasUsername = credentialSecretUsernameKeys[credType]
+		fixedUsername            = hasUsername && !slices.Contains(generatedKeys, usernameKey) && !slices.Contains(generated, usernameKey)
+		rotated, rejected        bool
+	)
+	switch {
+	case len(generated) > 0:
+		generatedKeys = lo.Uniq(append(generatedKeys, generated...))
+		if _, ok := secret.Annotations[consts.KongCredentialRotatedAtAnnotationKey]; !ok {
+			secret.Annotations[consts.KongCredentialRotatedAtAnnotationKey] = now.Format(time.RFC3339)
+			secret.Annotations[consts.KongCredentialRotateHandledAnnotationKey] = secret.Annotations[consts.KongCredentialRotateAnnotationKey]
+		}
+	case len(generatedKeys) > 0 && fixedUsername && credentialRotationDue(secret, interval, now):
+		// The rotation is recorded as handled so that it's not attempted again
+		// before the next rotation request or interval.
+		secret.Annotations[consts.KongCredentialRotatedAtAnnotationKey] = now.Format(time.RFC3339)
+		secret.Annotations[consts.KongCredentialRotateHandledAnnotationKey] = secret.Annotations[consts.KongCredentialRotateAnnotationKey]
+		rejected = true
+	case len(generatedKeys) > 0 && credentialRotationDue(secret, interval, now):
+		for _, k := range generatedKeys {
+			if k == usernameKey && hasUsername {
+				secret.Data[k] = []byte(generateCredentialUsername(secret))
+				continue
+			}
+			secret.Data[k] = []byte(generateCredentialValue())
+		}
+		secret.Annotations[consts.KongCredentialRotatedAtAnnotationKey] = now.Format(time.RFC3339)
+		secret.Annotations[consts.KongCredentialRotateHandledAnnotationKey] = secret.Annotations[consts.KongCredentialRotateAnnotationKey]
+		rotated = true
+	}
+
+	if rejected {
+		if err := r.client.Patch(ctx, secret, client.MergeFrom(old)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update Secret %s with rejected credential rotation: %w",
+				client.ObjectKeyFromObject(secret), err,
+			)
+		}
+		r.eventRecorder.Eventf(secret, corev1.EventTypeWarning, KongCredentialRotationRejectedEventReason,
+			"Cannot rotate %s credential which username is set in the Secret, remove the %s key to have it generated",
+			credType, usernameKey,
+		)
+	}
+
+	if len(generated) > 0 || rotated {
+		slices.Sort(generatedKeys)
+		secret.Annotations[consts.KongCredentialGeneratedKeysAnnotationKey] = strings.Join(generatedKeys, ",")
+		if err := r.client.Patch(ctx, secret, client.MergeFrom(old)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update Secret %s with generated credential: %w",
+				client.ObjectKeyFromObject(secret), err,
+			)
+		}
+		if rotated {
+			r.eventRecorder.Eventf(secret, corev1.EventTypeNormal, KongCredentialRotatedEventReason,
+				"Rotated %s credential at %s", credType, now.Format(time.RFC3339),
+			)
+		} else {
+			r.eventRecorder.Eventf(secret, corev1.EventTypeNormal, KongCredentialGeneratedEventReason,
+				"Generated %s credential keys: %s", credType, strings.Join(generated, ","),
+			)
+		}
+	}
+
+	if interval <= 0 {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{
+		RequeueAfter: credentialRotatedAt(secret).Add(interval).Sub(now),
+	}, nil
+}
+
+// credentialRotationDue returns true if the rotation of the Secret's generated
+// credential was requested through the konnect.konghq.com/credential-rotate annotation
+// or if the rotation interval has elapsed since the last rotation.
+func credentialRotationDue(secret *corev1.Secret, interval time.Duration, now time.Time) bool {
+	if v, ok := secret.Annotations[consts.KongCredentialRotateAnnotationKey]; ok &&
+		v != secret.Annotations[consts.KongCredentialRotateHandledAnnotationKey] {
+		return true
+	}
+	return interval > 0 && !now.Before(credentialRotatedAt(secret).Add(interval))
+}
+
+// credentialRotatedAt returns the time at which the Secret's credential was
+// last generated or rotated, falling back to the Secret's creation time.
+func credentialRotatedAt(secret *corev1.Secret) time.Time {
+	if t, err := time.Parse(time.RFC3339, secret.Annotations[consts.KongCredentialRotatedAtAnnotationKey]); err == nil {
+		return t
+	}
+	return secret.CreationTimestamp.Time
+}
+
+// generatedCredentialSecretKeys returns the Secret's keys which values were generated.
+func generatedCredentialSecretKeys(secret *corev1.Secret) []string {
+	v := secret.Annotations[consts.KongCredentialGeneratedKeysAnnotationKey]
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// durationFromAnnotation returns the duration set in the object's annotation
+// or the provided default when the annotation is not set.
+func durationFromAnnotation(obj client.Object, key string, def time.Duration) (time.Duration, error) {
+	v, ok := obj.GetAnnotations()[key]
+	if !ok {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s annotation value %q on %s", key, v, client.ObjectKeyFromObject(obj))
+	}
+	return d, nil
+}
+
+func generateCredentialValue() string {
+	b := make([]byte, credentialGeneratedValueLength)
+	// NOTE: crypto/rand.Read never returns an error.
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func generateCredentialUsername(secret *corev1.Secret) string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return secret.Name + "-" + hex.EncodeToString(b)
+}
+
+// retireReplacedCredentials retires the active credentials created from the
+// generated Secret which values were replaced in the Secret (e.g. by a rotation)
+// so that new credentials are created for the current values while the retired
+// ones are kept for the rotation overlap.
+// Only credentials which can coexist with the new ones are retired, i.e. key-auth
+// credentials and credentials which usernames changed, as usernames have to be unique.
+// The credentials are compared with the current values of the Secret so that
+// retirement is retried until it succeeds and never happens before the Secret
+// is updated.
+func retireReplacedCredentials[
+	T constraints.SupportedCredentialType,
+	TPtr constraints.KongCredential[T],
+](
+	ctx context.Context,
+	cl client.Client,
+	secret *corev1.Secret,
+	creds []T,
+	now time.Time,
+) error {
+	if secret.Annotations[consts.KongCredentialGenerateAnnotationKey] != "true" {
+		return nil
+	}
+	for i := range creds {
+		var cred TPtr = &creds[i]
+		if !isOwnedBySecret(cred, secret) || isRetiredCredential(cred) ||
+			!cred.GetDeletionTimestamp().IsZero() || !credentialReplacedInSecret(cred, secret) {
+			continue
+		}
+		old := cred.DeepCopyObject().(client.Object)
+		annotations := cred.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[consts.KongCredentialRetiredAtAnnotationKey] = now.Format(time.RFC3339)
+		cred.SetAnnotations(annotations)
+		if err := cl.Patch(ctx, cred, client.MergeFrom(old)); err != nil {
+			return fmt.Errorf("failed to retire credential %s: %w", client.ObjectKeyFromObject(cred), err)
+		}
+	}
+	return nil
+}
+
+// credentialReplacedInSecret returns true when the credential's key (for key-auth
+// credentials) or username (for basic-auth and HMAC credentials) differs from
+// the one in the Secret.
+func credentialReplacedInSecret(cred client.Object, secret *corev1.Secret) bool {
+	switch cred := cred.(type) {
+	case *configurationv1alpha1.KongCredentialAPIKey:
+		return cred.Spec.Key != string(secret.Data[CredentialSecretKeyNameAPIKeyKey])
+	case *configurationv1alpha1.KongCredentialBasicAuth:
+		return cred.Spec.Username != string(secret.Data[corev1.BasicAuthUsernameKey])
+	case *configurationv1alpha1.KongCredentialHMAC:
+		return lo.FromPtr(cred.Spec.Username) != string(secret.Data[CredentialSecretKeyNameHMACUsername])
+	default:
+		return false
+	}
+}
+
+func isRetiredCredential(obj client.Object) bool {
+	_, ok := obj.GetAnnotations()[consts.KongCredentialRetiredAtAnnotationKey]
+	return ok
+}
+
+func isOwnedBySecret(obj client.Object, secret *corev1.Secret) bool {
+	return lo.ContainsBy(obj.GetOwnerReferences(), func(or metav1.OwnerReference) bool {
+		return or.UID == secret.UID
+	})
+}
+
+// deleteRetiredCredentials deletes the credentials created from the Secret and
+// retired by a rotation once the rotation overlap has elapsed and one of the
+// active credentials is programmed in Konnect.
+// It returns a result with RequeueAfter set when a retired credential is still
+// within the rotation overlap.
+func (r *KongCredentialSecretReconciler) deleteRetiredCredentials(
+	ctx context.Context,
+	secret *corev1.Secret,
+	retired []client.Object,
+	activeProgrammed bool,
+) (ctrl.Result, error) {
+	if len(retired) == 0 {
+		return ctrl.Result{}, nil
+	}
+	overlap, err := durationFromAnnotation(secret,
+		consts.KongCredentialRotationOverlapAnnotationKey, consts.DefaultKongCredentialRotationOverlap,
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var (
+		now = r.now()
+		res ctrl.Result
+	)
+	for _, cred := range retired {
+		if !isOwnedBySecret(cred, secret) || !cred.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		retiredAt, err := time.Parse(time.RFC3339, cred.GetAnnotations()[consts.KongCredentialRetiredAtAnnotationKey])
+		if err == nil {
+			if wait := retiredAt.Add(overlap).Sub(now); wait > 0 {
+				if res.RequeueAfter == 0 || wait < res.RequeueAfter {
+					res.RequeueAfter = wait
+				}
+				continue
+			}
+		}
+		// The retired credential is kept until the new one is programmed.
+		// The change of its status triggers the reconciliation of the Secret.
+		if !activeProgrammed {
+			continue
+		}
+		if err := r.client.Delete(ctx, cred); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete retired credential %s: %w", client.ObjectKeyFromObject(cred), err)
+		}
+		r.eventRecorder.Eventf(secret, corev1.EventTypeNormal, KongCredentialRetiredDeletedEventReason,
+			"Deleted credential %s retired at %s", cred.GetName(), cred.GetAnnotations()[consts.KongCredentialRetiredAtAnnotationKey],
+		)
+	}
+	return res, nil
+}
+
+// splitRetiredCredentials splits the credentials into the active and the
+// retired ones and reports whether any of the active credentials is programmed.
+func splitRetiredCredentials[
+	T constraints.SupportedCredentialType,
+	TPtr constraints.KongCredential[T],
+](creds []T) (active []T, retired []client.Object, activeProgrammed bool) {
+	for i := range creds {
+		var cred TPtr = &creds[i]
+		if isRetiredCredential(cred) {
+			retired = append(retired, cred)
+			continue
+		}
+		active = append(active, creds[i])
+		if k8sutils.HasConditionTrue(konnectv1alpha1.KonnectEntityProgrammedConditionType, cred) {
+			activeProgrammed = true
+		}
+	}
+	return active, retired, activeProgrammed
+}
@@ -0,0 +1,344 @@
+package konnect
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kong/gateway-operator/internal/utils/index"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func newCredentialSecretTestClient(objs ...client.Object) client.Client {
+	builder := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(objs...)
+	opts := lo.Filter(index.OptionsForKongConsumer(nil), func(opt index.Option, _ int) bool {
+		return opt.Field == index.IndexFieldKongConsumerReferencesSecrets
+	})
+	opts = append(opts, index.OptionsForCredentialsAPIKey()...)
+	opts = append(opts, index.OptionsForCredentialsBasicAuth()...)
+	for _, opt := range opts {
+		builder = builder.WithIndex(opt.Object, opt.Field, opt.ExtractValueFn)
+	}
+	return builder.Build()
+}
+
+func credentialSecretTestConsumer() *configurationv1.KongConsumer {
+	return &configurationv1.KongConsumer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "consumer",
+			Namespace: "default",
+		},
+		Credentials: []string{"secret"},
+		Spec: configurationv1.KongConsumerSpec{
+			ControlPlaneRef: &commonv1alpha1.ControlPlaneRef{
+				Type: commonv1alpha1.ControlPlaneRefKonnectNamespacedRef,
+				KonnectNamespacedRef: &commonv1alpha1.KonnectNamespacedRef{
+					Name: "cp",
+				},
+			},
+		},
+	}
+}
+
+func setCredentialProgrammed(t *testing.T, cl client.Client, cred *configurationv1alpha1.KongCredentialAPIKey) {
+	t.Helper()
+	cred.Status.Conditions = []metav1.Condition{
+		{
+			Type:               konnectv1alpha1.KonnectEntityProgrammedConditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             konnectv1alpha1.KonnectEntityProgrammedReasonProgrammed,
+			LastTransitionTime: metav1.Now(),
+		},
+	}
+	require.NoError(t, cl.Update(t.Context(), cred))
+}
+
+func TestKongCredentialSecretReconciler_GenerateAndRotateAPIKey(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+			UID:       "secret-uid",
+			Labels: map[string]string{
+				CredentialTypeLabel: KongCredentialTypeAPIKey,
+			},
+			Annotations: map[string]string{
+				consts.KongCredentialGenerateAnnotationKey:         "true",
+				consts.KongCredentialRotationIntervalAnnotationKey: "24h",
+			},
+		},
+	}
+	cl := newCredentialSecretTestClient(secret, credentialSecretTestConsumer())
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	eventRecorder := record.NewFakeRecorder(10)
+	r := NewKongCredentialSecretReconciler(nil, logging.DevelopmentMode, cl, scheme.Get())
+	r.eventRecorder = eventRecorder
+	r.now = func() time.Time { return now }
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "secret"}}
+
+	listCreds := func(t *testing.T) []configurationv1alpha1.KongCredentialAPIKey {
+		t.Helper()
+		var l configurationv1alpha1.KongCredentialAPIKeyList
+		require.NoError(t, cl.List(t.Context(), &l))
+		return l.Items
+	}
+
+	t.Log("the key is generated and the credential is created")
+	res, err := r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: 24 * time.Hour}, res)
+	require.NoError(t, cl.Get(t.Context(), req.NamespacedName, secret))
+	generatedKey := string(secret.Data[CredentialSecretKeyNameAPIKeyKey])
+	assert.Len(t, generatedKey, 43)
+	assert.Equal(t, "key", secret.Annotations[consts.KongCredentialGeneratedKeysAnnotationKey])
+	assert.Equal(t, "2025-01-01T00:00:00Z", secret.Annotations[consts.KongCredentialRotatedAtAnnotationKey])
+	require.Len(t, eventRecorder.Events, 1)
+	assert.Equal(t, "Normal KongCredentialGenerated Generated key-auth credential keys: key", <-eventRecorder.Events)
+	creds := listCreds(t)
+	require.Len(t, creds, 1)
+	assert.Equal(t, generatedKey, creds[0].Spec.Key)
+	setCredentialProgrammed(t, cl, &creds[0])
+
+	t.Log("the key is rotated when the rotation interval elapses and the previous credential is retired")
+	now = now.Add(24 * time.Hour)
+	res, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Hour}, res)
+	require.NoError(t, cl.Get(t.Context(), req.NamespacedName, secret))
+	rotatedKey := string(secret.Data[CredentialSecretKeyNameAPIKeyKey])
+	assert.NotEqual(t, generatedKey, rotatedKey)
+	assert.Equal(t, "2025-01-02T00:00:00Z", secret.Annotations[consts.KongCredentialRotatedAtAnnotationKey])
+	require.Len(t, eventRecorder.Events, 1)
+	assert.Equal(t, "Normal KongCredentialRotated Rotated key-auth credential at 2025-01-02T00:00:00Z", <-eventRecorder.Events)
+	creds = listCreds(t)
+	require.Len(t, creds, 2)
+	oldCred, ok := lo.Find(creds, func(c configurationv1alpha1.KongCredentialAPIKey) bool { return c.Spec.Key == generatedKey })
+	require.True(t, ok)
+	assert.Equal(t, "2025-01-02T00:00:00Z", oldCred.Annotations[consts.KongCredentialRetiredAtAnnotationKey])
+	newCred, ok := lo.Find(creds, func(c configurationv1alpha1.KongCredentialAPIKey) bool { return c.Spec.Key == rotatedKey })
+	require.True(t, ok)
+
+	t.Log("the retired credential is kept until the new one is programmed")
+	now = now.Add(time.Hour)
+	res, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: 23 * time.Hour}, res)
+	assert.Len(t, listCreds(t), 2)
+
+	t.Log("the retired credential is deleted after the rotation overlap")
+	setCredentialProgrammed(t, cl, &newCred)
+	_, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	creds = listCreds(t)
+	require.Len(t, creds, 1)
+	assert.Equal(t, rotatedKey, creds[0].Spec.Key)
+	require.Len(t, eventRecorder.Events, 1)
+	assert.Contains(t, <-eventRecorder.Events, "Normal KongCredentialRetiredDeleted Deleted credential")
+}
+
+func TestKongCredentialSecretReconciler_RotateBasicAuthWithProvidedUsername(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+			UID:       "secret-uid",
+			Labels: map[string]string{
+				CredentialTypeLabel: KongCredentialTypeBasicAuth,
+			},
+			Annotations: map[string]string{
+				consts.KongCredentialGenerateAnnotationKey: "true",
+			},
+		},
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("user"),
+		},
+	}
+	cl := newCredentialSecretTestClient(secret, credentialSecretTestConsumer())
+
+	eventRecorder := record.NewFakeRecorder(10)
+	r := NewKongCredentialSecretReconciler(nil, logging.DevelopmentMode, cl, scheme.Get())
+	r.eventRecorder = eventRecorder
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "secret"}}
+
+	t.Log("only the password is generated")
+	res, err := r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	require.NoError(t, cl.Get(t.Context(), req.NamespacedName, secret))
+	assert.Equal(t, "user", string(secret.Data[corev1.BasicAuthUsernameKey]))
+	assert.Equal(t, "password", secret.Annotations[consts.KongCredentialGeneratedKeysAnnotationKey])
+	password := string(secret.Data[corev1.BasicAuthPasswordKey])
+	assert.NotEmpty(t, password)
+
+	var l configurationv1alpha1.KongCredentialBasicAuthList
+	require.NoError(t, cl.List(t.Context(), &l))
+	require.Len(t, l.Items, 1)
+	require.Len(t, eventRecorder.Events, 1)
+	<-eventRecorder.Events
+
+	t.Log("rotation requested through the annotation is rejected as the username is not generated")
+	secret.Annotations[consts.KongCredentialRotateAnnotationKey] = "1"
+	require.NoError(t, cl.Update(t.Context(), secret))
+	_, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(t.Context(), req.NamespacedName, secret))
+	assert.Equal(t, password, string(secret.Data[corev1.BasicAuthPasswordKey]))
+	assert.Equal(t, "1", secret.Annotations[consts.KongCredentialRotateHandledAnnotationKey])
+	require.Len(t, eventRecorder.Events, 1)
+	assert.Equal(t,
+		"Warning KongCredentialRotationRejected Cannot rotate basic-auth credential which username is set in the Secret, remove the username key to have it generated",
+		<-eventRecorder.Events,
+	)
+
+	require.NoError(t, cl.List(t.Context(), &l))
+	require.Len(t, l.Items, 1)
+	assert.Equal(t, "user", l.Items[0].Spec.Username)
+	assert.Equal(t, password, l.Items[0].Spec.Password)
+	assert.NotContains(t, l.Items[0].Annotations, consts.KongCredentialRetiredAtAnnotationKey)
+
+	t.Log("handled rotation requests are not rejected again")
+	_, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Empty(t, eventRecorder.Events)
+}
+
+func TestKongCredentialSecretReconciler_RotateBasicAuthWithGeneratedUsername(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+			UID:       "secret-uid",
+			Labels: map[string]string{
+				CredentialTypeLabel: KongCredentialTypeBasicAuth,
+			},
+			Annotations: map[string]string{
+				consts.KongCredentialGenerateAnnotationKey: "true",
+			},
+		},
+	}
+	cl := newCredentialSecretTestClient(secret, credentialSecretTestConsumer())
+
+	r := NewKongCredentialSecretReconciler(nil, logging.DevelopmentMode, cl, scheme.Get())
+	r.eventRecorder = record.NewFakeRecorder(10)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "secret"}}
+
+	_, err := r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(t.Context(), req.NamespacedName, secret))
+	username := string(secret.Data[corev1.BasicAuthUsernameKey])
+
+	t.Log("rotation creates a credential with a new username and retires the previous one")
+	secret.Annotations[consts.KongCredentialRotateAnnotationKey] = "1"
+	require.NoError(t, cl.Update(t.Context(), secret))
+	_, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(t.Context(), req.NamespacedName, secret))
+	rotatedUsername := string(secret.Data[corev1.BasicAuthUsernameKey])
+	assert.NotEqual(t, username, rotatedUsername)
+
+	var l configurationv1alpha1.KongCredentialBasicAuthList
+	require.NoError(t, cl.List(t.Context(), &l))
+	require.Len(t, l.Items, 2)
+	oldCred, ok := lo.Find(l.Items, func(c configurationv1alpha1.KongCredentialBasicAuth) bool { return c.Spec.Username == username })
+	require.True(t, ok)
+	assert.Contains(t, oldCred.Annotations, consts.KongCredentialRetiredAtAnnotationKey)
+	newCred, ok := lo.Find(l.Items, func(c configurationv1alpha1.KongCredentialBasicAuth) bool { return c.Spec.Username == rotatedUsername })
+	require.True(t, ok)
+	assert.Equal(t, string(secret.Data[corev1.BasicAuthPasswordKey]), newCred.Spec.Password)
+	assert.NotContains(t, newCred.Annotations, consts.KongCredentialRetiredAtAnnotationKey)
+}
+
+func TestKongCredentialSecretReconciler_RotationSecretPatchFailure(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+			UID:       "secret-uid",
+			Labels: map[string]string{
+				CredentialTypeLabel: KongCredentialTypeAPIKey,
+			},
+			Annotations: map[string]string{
+				consts.KongCredentialGenerateAnnotationKey: "true",
+			},
+		},
+	}
+	var failSecretPatch bool
+	cl := interceptor.NewClient(newCredentialSecretTestClient(secret, credentialSecretTestConsumer()).(client.WithWatch), interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if _, ok := obj.(*corev1.Secret); ok && failSecretPatch {
+				return errors.New("patch failed")
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	})
+
+	r := NewKongCredentialSecretReconciler(nil, logging.DevelopmentMode, cl, scheme.Get())
+	r.eventRecorder = record.NewFakeRecorder(10)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "secret"}}
+
+	_, err := r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(t.Context(), req.NamespacedName, secret))
+	key := string(secret.Data[CredentialSecretKeyNameAPIKeyKey])
+
+	t.Log("the credential is not retired when the Secret update fails")
+	secret.Annotations[consts.KongCredentialRotateAnnotationKey] = "1"
+	require.NoError(t, cl.Update(t.Context(), secret))
+	failSecretPatch = true
+	_, err = r.Reconcile(t.Context(), req)
+	require.ErrorContains(t, err, "patch failed")
+	failSecretPatch = false
+	_, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+
+	var l configurationv1alpha1.KongCredentialAPIKeyList
+	require.NoError(t, cl.List(t.Context(), &l))
+	require.Len(t, l.Items, 2, "only the rotated credential should be created")
+	oldCred, ok := lo.Find(l.Items, func(c configurationv1alpha1.KongCredentialAPIKey) bool { return c.Spec.Key == key })
+	require.True(t, ok)
+	assert.Contains(t, oldCred.Annotations, consts.KongCredentialRetiredAtAnnotationKey)
+}
+
+func TestKongCredentialSecretReconciler_GenerateUnsupportedType(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+			Labels: map[string]string{
+				CredentialTypeLabel: KongCredentialTypeACL,
+			},
+			Annotations: map[string]string{
+				consts.KongCredentialGenerateAnnotationKey: "true",
+			},
+		},
+	}
+	cl := newCredentialSecretTestClient(secret)
+	r := NewKongCredentialSecretReconciler(nil, logging.DevelopmentMode, cl, scheme.Get())
+	r.eventRecorder = record.NewFakeRecorder(10)
+
+	_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(secret)})
+	require.ErrorContains(t, err, "credential type acl does not support it")
+}
@@ -0,0 +1,282 @@
+package konnect
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kong/gateway-operator/controller/konnect/ops"
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/konnect/server"
+	"github.com/kong/gateway-operator/controller/pkg/controlplane"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+// mtlsCredentialConsumer is a consumer which an mtls-auth credential was created
+// for in Konnect out of a Secret.
+type mtlsCredentialConsumer struct {
+	controlPlane types.NamespacedName
+	consumerID   string
+}
+
+func (c mtlsCredentialConsumer) String() string {
+	return c.controlPlane.Namespace + "/" + c.controlPlane.Name + "/" + c.consumerID
+}
+
+// mtlsCredentialConsumersFromSecret returns the consumers recorded in the
+// KongCredentialMTLSConsumersAnnotationKey annotation of the Secret.
+func mtlsCredentialConsumersFromSecret(secret *corev1.Secret) []mtlsCredentialConsumer {
+	v := secret.Annotations[consts.KongCredentialMTLSConsumersAnnotationKey]
+	if v == "" {
+		return nil
+	}
+	var ret []mtlsCredentialConsumer
+	for _, entry := range strings.Split(v, ",") {
+		parts := strings.Split(entry, "/")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			continue
+		}
+		ret = append(ret, mtlsCredentialConsumer{
+			controlPlane: types.NamespacedName{Namespace: parts[0], Name: parts[1]},
+			consumerID:   parts[2],
+		})
+	}
+	return ret
+}
+
+func secretHasMTLSCredentialCleanupFinalizer(obj client.Object) bool {
+	return controllerutil.ContainsFinalizer(obj, consts.KongCredentialMTLSCleanupFinalizer)
+}
+
+func validateSecretForKongCredentialMTLS(s *corev1.Secret) error {
+	if len(s.Data[CredentialSecretKeyNameMTLSSubjectName]) == 0 {
+		return fmt.Errorf("Secret %s used as mtls-auth credential, but lacks %s key",
+			client.ObjectKeyFromObject(s), CredentialSecretKeyNameMTLSSubjectName,
+		)
+	}
+	return nil
+}
+
+// reconcileMTLSCredentialSecret ensures an mtls-auth credential exists in Konnect
+// for each of the provided consumers and deletes the ones created for consumers
+// which no longer use the Secret.
+// The consumers the credentials were created for are recorded in the Secret's
+// annotation and the Secret is kept with a finalizer until all of them are deleted.
+func (r *KongCredentialSecretReconciler) reconcileMTLSCredentialSecret(
+	ctx context.Context,
+	secret *corev1.Secret,
+	consumers []configurationv1.KongConsumer,
+) (ctrl.Result, error) {
+	desired := make(map[mtlsCredentialConsumer]*konnectv1alpha1.KonnectGatewayControlPlane)
+	for _, consumer := range consumers {
+		// Consumers not created in Konnect yet are skipped, the Secret is enqueued
+		// again when their status gets updated.
+		if consumer.Spec.ControlPlaneRef == nil || consumer.GetKonnectID() == "" {
+			continue
+		}
+		cp, err := controlplane.GetCPForRef(ctx, r.client, *consumer.Spec.ControlPlaneRef, consumer.Namespace)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed getting KonnectGatewayControlPlane of KongConsumer %s: %w",
+				client.ObjectKeyFromObject(&consumer), err,
+			)
+		}
+		if cp.GetKonnectID() == "" {
+			continue
+		}
+		desired[mtlsCredentialConsumer{
+			controlPlane: client.ObjectKeyFromObject(cp),
+			consumerID:   consumer.GetKonnectID(),
+		}] = cp
+	}
+
+	// Add the finalizer before creating anything in Konnect so that the credentials
+	// are never left behind.
+	if len(desired) > 0 && !secretHasMTLSCredentialCleanupFinalizer(secret) {
+		old := secret.DeepCopy()
+		controllerutil.AddFinalizer(secret, consts.KongCredentialMTLSCleanupFinalizer)
+		if err := r.client.Patch(ctx, secret, client.MergeFrom(old)); err != nil {
+			if k8serrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("failed adding finalizer to Secret %s: %w", client.ObjectKeyFromObject(secret), err)
+		}
+	}
+
+	uidTag := ops.UIDLabelForObject(secret)
+	s := secret.DeepCopy()
+	s.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	tags := ops.GenerateTagsForObject(s)
+
+	keys := make([]mtlsCredentialConsumer, 0, len(desired))
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b mtlsCredentialConsumer) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, k := range keys {
+		cp := desired[k]
+		caCertificateID, err := mtlsCredentialCACertificateID(ctx, r.client, secret, cp)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		sdk, err := r.konnectSDKForControlPlane(ctx, cp)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		_, err = ops.EnsureKongCredentialMTLS(ctx, sdk.GetMTLSCredentialsSDK(),
+			ops.KongCredentialMTLS{
+				ControlPlaneID:  cp.GetKonnectID(),
+				ConsumerID:      k.consumerID,
+				SubjectName:     string(secret.Data[CredentialSecretKeyNameMTLSSubjectName]),
+				CACertificateID: caCertificateID,
+				Tags:            tags,
+			},
+			uidTag,
+		)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, c := range mtlsCredentialConsumersFromSecret(secret) {
+		if _, ok := desired[c]; ok {
+			continue
+		}
+		var cp konnectv1alpha1.KonnectGatewayControlPlane
+		if err := r.client.Get(ctx, c.controlPlane, &cp); err != nil {
+			// Deleting a control plane deletes its entities in Konnect.
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return ctrl.Result{}, fmt.Errorf("failed getting KonnectGatewayControlPlane %s: %w", c.controlPlane, err)
+		}
+		if cp.GetKonnectID() == "" || !cp.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		sdk, err := r.konnectSDKForControlPlane(ctx, &cp)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := ops.DeleteKongCredentialMTLSForUID(ctx, sdk.GetMTLSCredentialsSDK(), cp.GetKonnectID(), c.consumerID, uidTag); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	old := secret.DeepCopy()
+	if len(keys) > 0 {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		entries := make([]string, 0, len(keys))
+		for _, k := range keys {
+			entries = append(entries, k.String())
+		}
+		secret.Annotations[consts.KongCredentialMTLSConsumersAnnotationKey] = strings.Join(entries, ",")
+	} else {
+		delete(secret.Annotations, consts.KongCredentialMTLSConsumersAnnotationKey)
+		controllerutil.RemoveFinalizer(secret, consts.KongCredentialMTLSCleanupFinalizer)
+	}
+	if err := r.client.Patch(ctx, secret, client.MergeFrom(old)); err != nil {
+		if k8serrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed updating Secret %s: %w", client.ObjectKeyFromObject(secret), err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// mtlsCredentialCACertificateID returns the Konnect ID of the KongCACertificate
+// referenced by the mtls-auth credential Secret in the provided control plane.
+// It returns an empty string when the Secret doesn't reference a CA certificate.
+func mtlsCredentialCACertificateID(
+	ctx context.Context,
+	cl client.Client,
+	secret *corev1.Secret,
+	cp *konnectv1alpha1.KonnectGatewayControlPlane,
+) (string, error) {
+	name := string(secret.Data[CredentialSecretKeyNameMTLSCACertificate])
+	if name == "" {
+		return "", nil
+	}
+	nn := types.NamespacedName{Namespace: secret.Namespace, Name: name}
+	var caCert configurationv1alpha1.KongCACertificate
+	if err := cl.Get(ctx, nn, &caCert); err != nil {
+		return "", fmt.Errorf("failed getting KongCACertificate %s referenced by Secret %s: %w",
+			nn, client.ObjectKeyFromObject(secret), err,
+		)
+	}
+	if caCert.GetKonnectID() == "" || caCert.GetControlPlaneID() != cp.GetKonnectID() {
+		return "", fmt.Errorf("KongCACertificate %s referenced by Secret %s is not programmed in KonnectGatewayControlPlane %s",
+			nn, client.ObjectKeyFromObject(secret), client.ObjectKeyFromObject(cp),
+		)
+	}
+	return caCert.GetKonnectID(), nil
+}
+
+// konnectSDKForControlPlane returns the Konnect SDK authenticated with the
+// KonnectAPIAuthConfiguration of the provided control plane.
+func (r *KongCredentialSecretReconciler) konnectSDKForControlPlane(
+	ctx context.Context,
+	cp *konnectv1alpha1.KonnectGatewayControlPlane,
+) (sdkops.SDKWrapper, error) {
+	apiAuthRef, err := getAPIAuthRefNN(ctx, r.client, cp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get APIAuth ref for %s: %w", client.ObjectKeyFromObject(cp), err)
+	}
+	var apiAuth konnectv1alpha1.KonnectAPIAuthConfiguration
+	if err := r.client.Get(ctx, apiAuthRef, &apiAuth); err != nil {
+		return nil, fmt.Errorf("failed to get KonnectAPIAuthConfiguration %s: %w", apiAuthRef, err)
+	}
+	token, err := getTokenFromKonnectAPIAuthConfiguration(ctx, r.client, r.sdkFactory, &apiAuth)
+	if err != nil {
+		return nil, err
+	}
+	server, err := server.NewServer[konnectv1alpha1.KonnectGatewayControlPlane](apiAuth.Spec.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server URL: %w", err)
+	}
+	return r.sdkFactory.NewKonnectSDK(server, sdkops.SDKToken(token)), nil
+}
+
+// enqueueMTLSCredentialSecretsForKongCACertificate returns a map function which
+// enqueues the mtls-auth credential Secrets referencing the KongCACertificate.
+func enqueueMTLSCredentialSecretsForKongCACertificate(cl client.Client) func(context.Context, client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		caCert, ok := obj.(*configurationv1alpha1.KongCACertificate)
+		if !ok {
+			return nil
+		}
+		var secrets corev1.SecretList
+		if err := cl.List(ctx, &secrets,
+			client.InNamespace(caCert.Namespace),
+			client.MatchingLabels{CredentialTypeLabel: KongCredentialTypeMTLS},
+		); err != nil {
+			return nil
+		}
+		var ret []reconcile.Request
+		for _, s := range secrets.Items {
+			if string(s.Data[CredentialSecretKeyNameMTLSCACertificate]) != caCert.Name {
+				continue
+			}
+			ret = append(ret, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&s),
+			})
+		}
+		return ret
+	}
+}
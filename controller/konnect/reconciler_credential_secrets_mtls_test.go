@@ -0,0 +1,143 @@
+package konnect
+
+import (
+	"testing"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestKongCredentialSecretReconciler_MTLS(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+			UID:       "secret-uid",
+			Labels: map[string]string{
+				CredentialTypeLabel: KongCredentialTypeMTLS,
+			},
+		},
+		Data: map[string][]byte{
+			CredentialSecretKeyNameMTLSSubjectName: []byte("client.example.com"),
+		},
+	}
+	consumer := credentialSecretTestConsumer()
+	consumer.SetKonnectID("consumer-id")
+	cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cp",
+			Namespace: "default",
+		},
+		Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+			KonnectConfiguration: konnectv1alpha1.KonnectConfiguration{
+				APIAuthConfigurationRef: konnectv1alpha1.KonnectAPIAuthConfigurationRef{
+					Name: "auth",
+				},
+			},
+		},
+		Status: konnectv1alpha1.KonnectGatewayControlPlaneStatus{
+			KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+				ID: "cp-id",
+			},
+		},
+	}
+	apiAuth := &konnectv1alpha1.KonnectAPIAuthConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth",
+			Namespace: "default",
+		},
+		Spec: konnectv1alpha1.KonnectAPIAuthConfigurationSpec{
+			Type:      konnectv1alpha1.KonnectAPIAuthTypeToken,
+			Token:     "kpat_xxxxxxxxxxxx",
+			ServerURL: "us.api.konghq.com",
+		},
+	}
+	cl := newCredentialSecretTestClient(secret, consumer, cp, apiAuth)
+	factory := sdkmocks.NewMockSDKFactory(t)
+	sdk := factory.SDK.KongCredentialsMTLSSDK
+	r := NewKongCredentialSecretReconciler(factory, logging.DevelopmentMode, cl, scheme.Get())
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "secret"}}
+	listRequest := sdkkonnectops.ListMtlsAuthRequest{
+		ControlPlaneID: "cp-id",
+		Tags:           lo.ToPtr("k8s-uid:secret-uid"),
+	}
+
+	t.Log("the credential is created in Konnect for the consumer")
+	sdk.EXPECT().ListMtlsAuth(mock.Anything, listRequest).
+		Return(&sdkkonnectops.ListMtlsAuthResponse{
+			Object: &sdkkonnectops.ListMtlsAuthResponseBody{},
+		}, nil).Once()
+	sdk.EXPECT().CreateMtlsAuthWithConsumer(mock.Anything, mock.MatchedBy(
+		func(req sdkkonnectops.CreateMtlsAuthWithConsumerRequest) bool {
+			return req.ControlPlaneID == "cp-id" &&
+				req.ConsumerIDForNestedEntities == "consumer-id" &&
+				req.MTLSAuthWithoutParents.SubjectName == "client.example.com" &&
+				req.MTLSAuthWithoutParents.CaCertificate == nil &&
+				lo.Contains(req.MTLSAuthWithoutParents.Tags, "k8s-uid:secret-uid")
+		},
+	)).Return(&sdkkonnectops.CreateMtlsAuthWithConsumerResponse{
+		MTLSAuth: &sdkkonnectcomp.MTLSAuth{ID: lo.ToPtr("cred-id")},
+	}, nil).Once()
+	res, err := r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	require.NoError(t, cl.Get(t.Context(), req.NamespacedName, secret))
+	assert.Contains(t, secret.Finalizers, consts.KongCredentialMTLSCleanupFinalizer)
+	assert.Equal(t, "default/cp/consumer-id", secret.Annotations[consts.KongCredentialMTLSConsumersAnnotationKey])
+
+	t.Log("the credential is deleted from Konnect when the consumer stops using the Secret")
+	consumer.Credentials = nil
+	require.NoError(t, cl.Update(t.Context(), consumer))
+	sdk.EXPECT().ListMtlsAuth(mock.Anything, listRequest).
+		Return(&sdkkonnectops.ListMtlsAuthResponse{
+			Object: &sdkkonnectops.ListMtlsAuthResponseBody{
+				Data: []sdkkonnectcomp.MTLSAuth{
+					{
+						ID:       lo.ToPtr("cred-id"),
+						Consumer: &sdkkonnectcomp.MTLSAuthConsumer{ID: lo.ToPtr("consumer-id")},
+					},
+				},
+			},
+		}, nil).Once()
+	sdk.EXPECT().DeleteMtlsAuthWithConsumer(mock.Anything, sdkkonnectops.DeleteMtlsAuthWithConsumerRequest{
+		ControlPlaneID:              "cp-id",
+		ConsumerIDForNestedEntities: "consumer-id",
+		MTLSAuthID:                  "cred-id",
+	}).Return(&sdkkonnectops.DeleteMtlsAuthWithConsumerResponse{}, nil).Once()
+	res, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	require.NoError(t, cl.Get(t.Context(), req.NamespacedName, secret))
+	assert.NotContains(t, secret.Finalizers, consts.KongCredentialMTLSCleanupFinalizer)
+	assert.NotContains(t, secret.Annotations, consts.KongCredentialMTLSConsumersAnnotationKey)
+}
+
+func TestValidateSecretForKongCredentialMTLS(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+	}
+	require.Error(t, validateSecret(secret, KongCredentialTypeMTLS))
+
+	secret.Data = map[string][]byte{
+		CredentialSecretKeyNameMTLSSubjectName: []byte("client.example.com"),
+	}
+	require.NoError(t, validateSecret(secret, KongCredentialTypeMTLS))
+}
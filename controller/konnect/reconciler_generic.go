@@ -8,6 +8,7 @@ import (
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -22,6 +23,7 @@ import (
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/op"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/internal/metrics"
 	"github.com/kong/gateway-operator/modules/manager/logging"
 	"github.com/kong/gateway-operator/pkg/consts"
@@ -47,6 +49,8 @@ type KonnectEntityReconciler[T constraints.SupportedKonnectEntityType, TEnt cons
 	MaxConcurrentReconciles uint
 
 	MetricRecorder metrics.Recorder
+
+	eventRecorder record.EventRecorder
 }
 
 // KonnectEntityReconcilerOption is a functional option for the KonnectEntityReconciler.
@@ -123,6 +127,8 @@ func (r *KonnectEntityReconciler[T, TEnt]) SetupWithManager(ctx context.Context,
 			)
 	)
 
+	r.eventRecorder = mgr.GetEventRecorderFor(entityTypeName)
+
 	for _, dep := range ReconciliationWatchOptionsForEntity(r.Client, ent) {
 		b = dep(b)
 	}
@@ -152,6 +158,20 @@ func (r *KonnectEntityReconciler[T, TEnt]) Reconcile(
 	ctx = ctrllog.IntoContext(ctx, logger)
 	log.Debug(logger, "reconciling")
 
+	// Objects mirroring Konnect entities are read-only, they're managed
+	// by the KonnectEntityMirrorReconciler.
+	if _, ok := ent.GetLabels()[consts.KonnectMirroredFromControlPlaneLabelKey]; ok {
+		log.Debug(logger, "object mirrors a Konnect entity, skipping")
+		return ctrl.Result{}, nil
+	}
+
+	if paused, res, err := pause.Reconcile(ctx, r.Client, r.eventRecorder, ent, ent, pauseReferences(ent)...); err != nil || paused || !res.IsZero() {
+		if paused {
+			log.Debug(logger, "reconciliation is paused")
+		}
+		return res, err
+	}
+
 	// If a type has a ControlPlane ref, handle it.
 	res, err := handleControlPlaneRef(ctx, r.Client, ent)
 	if err != nil || !res.IsZero() {
@@ -334,7 +354,7 @@ func (r *KonnectEntityReconciler[T, TEnt]) Reconcile(
 		return res, retErr
 	}
 
-	token, err := getTokenFromKonnectAPIAuthConfiguration(ctx, r.Client, &apiAuth)
+	token, err := getTokenFromKonnectAPIAuthConfiguration(ctx, r.Client, r.sdkFactory, &apiAuth)
 	if err != nil {
 		if res, errStatus := patch.StatusWithCondition(
 			ctx, r.Client, &apiAuth,
@@ -366,7 +386,11 @@ func (r *KonnectEntityReconciler[T, TEnt]) Reconcile(
 		if errDel := (&ReferencedObjectIsBeingDeleted{}); !errors.As(err, errDel) ||
 			ent.GetDeletionTimestamp().IsZero() {
 			log.Debug(logger, "error handling KonnectNetwork ref", "error", err)
-			return patchWithProgrammedStatusConditionBasedOnOtherConditions(ctx, r.Client, ent)
+			if resPatch, errPatch := patchWithProgrammedStatusConditionBasedOnOtherConditions(ctx, r.Client, ent); errPatch != nil || !resPatch.IsZero() {
+				return resPatch, errPatch
+			}
+			// Referenced networks which are still provisioning are checked again later.
+			return res, nil
 		}
 		if !res.IsZero() {
 			return res, err
@@ -470,6 +494,15 @@ func (r *KonnectEntityReconciler[T, TEnt]) Reconcile(
 		return ctrl.Result{}, nil
 	}
 
+	// Programmed entities of ControlPlanes in the bulk sync mode are
+	// periodically synced by the KonnectBulkSyncReconciler.
+	if bulk, err := isSyncedInBulk(ctx, r.Client, ent); err != nil {
+		return ctrl.Result{}, err
+	} else if bulk {
+		log.Debug(logger, "entity is synced in bulk with its ControlPlane, skipping update")
+		return ctrl.Result{}, nil
+	}
+
 	res, err = ops.Update(ctx, sdk, r.SyncPeriod, r.Client, r.MetricRecorder, ent)
 	// Set the server URL and org ID regardless of the error.
 	setStatusServerURLAndOrgID(ent, server, apiAuth.Status.OrganizationID)
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -15,6 +17,7 @@ import (
 	"github.com/kong/gateway-operator/controller/konnect/ops"
 	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/pkg/consts"
 
 	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
 	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
@@ -43,7 +46,7 @@ func handleTypeSpecific[
 	case *configurationv1.KongConsumer:
 		updated, isProblem = handleKongConsumerSpecific(ctx, cl, e)
 	case *konnectv1alpha1.KonnectGatewayControlPlane:
-		updated, err = handleKonnectGatewayControlPlaneSpecific(ctx, sdk, e)
+		updated, err = handleKonnectGatewayControlPlaneSpecific(ctx, sdk, cl, e)
 		if err != nil {
 			return false, ctrl.Result{}, err
 		}
@@ -60,14 +63,20 @@ func handleTypeSpecific[
 func handleKonnectGatewayControlPlaneSpecific(
 	ctx context.Context,
 	sdk sdkops.SDKWrapper,
+	cl client.Client,
 	kgcp *konnectv1alpha1.KonnectGatewayControlPlane,
 ) (updated bool, err error) {
+	updated, err = handleKonnectGatewayControlPlaneGroupRef(ctx, cl, kgcp)
+	if err != nil {
+		return false, err
+	}
+
 	// If it's not set it means that first time we are reconciling the KonnectGatewayControlPlane,
 	// in subsequent reconciliations it should be set. Otherwise it will be reported and everything
 	// in this helper is irrelevant.
 	kgcpID := kgcp.GetKonnectID()
 	if kgcpID == "" {
-		return false, nil
+		return updated, nil
 	}
 	konnectCP, err := ops.GetControlPlaneByID(ctx, sdk.GetControlPlaneSDK(), kgcpID)
 	if err != nil {
@@ -80,6 +89,61 @@ func handleKonnectGatewayControlPlaneSpecific(
 	return true, nil
 }
 
+// handleKonnectGatewayControlPlaneGroupRef validates the control plane group
+// the KonnectGatewayControlPlane declares to be a member of through the
+// konnect.konghq.com/control-plane-group annotation.
+// Valid memberships are reported by the group's reconciliation which attaches
+// the control plane to the group, invalid ones are reported here.
+// It returns true when the ControlPlaneGroupMembership condition has been changed.
+func handleKonnectGatewayControlPlaneGroupRef(
+	ctx context.Context,
+	cl client.Client,
+	kgcp *konnectv1alpha1.KonnectGatewayControlPlane,
+) (bool, error) {
+	groupName := kgcp.GetAnnotations()[consts.KonnectControlPlaneGroupAnnotationKey]
+	if groupName == "" {
+		// Drop the condition left from the previous membership.
+		n := len(kgcp.Status.Conditions)
+		kgcp.Status.Conditions = slices.DeleteFunc(kgcp.Status.Conditions, func(c metav1.Condition) bool {
+			return c.Type == ops.ControlPlaneGroupMembershipConditionType
+		})
+		return n != len(kgcp.Status.Conditions), nil
+	}
+
+	var msg string
+	if ops.IsControlPlaneGroup(kgcp) {
+		msg = "control plane groups can't be members of other control plane groups"
+	} else {
+		var (
+			group konnectv1alpha1.KonnectGatewayControlPlane
+			nn    = types.NamespacedName{
+				// TODO: change this when cross namespace refs are allowed.
+				Namespace: kgcp.Namespace,
+				Name:      groupName,
+			}
+		)
+		switch err := cl.Get(ctx, nn, &group); {
+		case k8serrors.IsNotFound(err):
+			msg = fmt.Sprintf("control plane group %s not found", nn)
+		case err != nil:
+			return false, fmt.Errorf("failed to get control plane group %s: %w", nn, err)
+		case !ops.IsControlPlaneGroup(&group):
+			msg = fmt.Sprintf("%s is not a control plane group", nn)
+		}
+	}
+	if msg == "" {
+		return false, nil
+	}
+
+	return patch.SetStatusWithConditionIfDifferent(
+		kgcp,
+		ops.ControlPlaneGroupMembershipConditionType,
+		metav1.ConditionFalse,
+		ops.ControlPlaneGroupMembershipReasonInvalidGroupRef,
+		msg,
+	), nil
+}
+
 func handleKongConsumerSpecific(
 	ctx context.Context,
 	cl client.Client,
@@ -3,15 +3,22 @@ package konnect
 import (
 	"testing"
 
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/kong/gateway-operator/controller/konnect/ops"
 	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 
 	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
 )
 
 func TestHandleKongConsumerSpecific(t *testing.T) {
@@ -203,3 +210,102 @@ func TestHandleKongConsumerSpecific(t *testing.T) {
 		}
 	})
 }
+
+func TestHandleKonnectGatewayControlPlaneGroupRef(t *testing.T) {
+	group := &konnectv1alpha1.KonnectGatewayControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "group",
+			Namespace: "test",
+		},
+		Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+			CreateControlPlaneRequest: konnectv1alpha1.CreateControlPlaneRequest{
+				ClusterType: lo.ToPtr(sdkkonnectcomp.CreateControlPlaneRequestClusterTypeClusterTypeControlPlaneGroup),
+			},
+		},
+	}
+	notGroup := &konnectv1alpha1.KonnectGatewayControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "not-group",
+			Namespace: "test",
+		},
+	}
+	newMember := func(groupName string, conditions ...metav1.Condition) *konnectv1alpha1.KonnectGatewayControlPlane {
+		cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "member",
+				Namespace: "test",
+			},
+			Status: konnectv1alpha1.KonnectGatewayControlPlaneStatus{
+				Conditions: conditions,
+			},
+		}
+		if groupName != "" {
+			cp.Annotations = map[string]string{
+				consts.KonnectControlPlaneGroupAnnotationKey: groupName,
+			}
+		}
+		return cp
+	}
+	attached := metav1.Condition{
+		Type:   ops.ControlPlaneGroupMembershipConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: string(ops.ControlPlaneGroupMembershipReasonAttached),
+	}
+
+	testCases := []struct {
+		name             string
+		cp               *konnectv1alpha1.KonnectGatewayControlPlane
+		wantUpdated      bool
+		wantCondition    bool
+		wantInvalidGroup bool
+	}{
+		{
+			name: "no group",
+			cp:   newMember(""),
+		},
+		{
+			name:        "condition is removed when the annotation is removed",
+			cp:          newMember("", attached),
+			wantUpdated: true,
+		},
+		{
+			name:          "valid group ref leaves the condition to the group",
+			cp:            newMember("group", attached),
+			wantCondition: true,
+		},
+		{
+			name:             "group not found",
+			cp:               newMember("missing"),
+			wantUpdated:      true,
+			wantCondition:    true,
+			wantInvalidGroup: true,
+		},
+		{
+			name:             "referenced control plane is not a group",
+			cp:               newMember("not-group", attached),
+			wantUpdated:      true,
+			wantCondition:    true,
+			wantInvalidGroup: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(group, notGroup).
+				Build()
+
+			updated, err := handleKonnectGatewayControlPlaneGroupRef(t.Context(), cl, tc.cp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, updated)
+
+			cond, ok := k8sutils.GetCondition(ops.ControlPlaneGroupMembershipConditionType, tc.cp)
+			assert.Equal(t, tc.wantCondition, ok)
+			if tc.wantInvalidGroup {
+				assert.Equal(t, metav1.ConditionFalse, cond.Status)
+				assert.Equal(t, string(ops.ControlPlaneGroupMembershipReasonInvalidGroupRef), cond.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package konnect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/kong/gateway-operator/controller/konnect/constraints"
+	"github.com/kong/gateway-operator/controller/konnect/ops"
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/konnect/server"
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
+	"github.com/kong/gateway-operator/internal/utils/index"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+// konnectBulkSyncPendingRequeueAfter is the duration after which a bulk sync
+// is performed again when not all the changes fitted in a batch.
+const konnectBulkSyncPendingRequeueAfter = 5 * time.Second
+
+// KonnectBulkSyncReconciler periodically syncs the KongServices, KongRoutes
+// and KongConsumers of KonnectGatewayControlPlanes with Konnect in bulk.
+// Only KonnectGatewayControlPlanes annotated with consts.KonnectSyncModeAnnotationKey
+// set to consts.KonnectSyncModeBulk are synced.
+//
+// Instead of each object being updated in Konnect every sync period, the
+// ControlPlane's entities are read from Konnect at once, compared with the
+// desired state computed from the objects and only the differing or missing
+// entities are updated, at most BatchSize of them per sync.
+// Objects' Konnect IDs and Programmed conditions are written back.
+//
+// Objects are still created, deleted and updated on spec changes on their own
+// by the KonnectEntityReconciler, only the objects which are programmed for
+// their current generation are synced in bulk.
+type KonnectBulkSyncReconciler struct {
+	sdkFactory  sdkops.SDKFactory
+	LoggingMode logging.Mode
+	Client      client.Client
+	SyncPeriod  time.Duration
+	BatchSize   uint
+}
+
+// NewKonnectBulkSyncReconciler creates a new KonnectBulkSyncReconciler.
+func NewKonnectBulkSyncReconciler(
+	sdkFactory sdkops.SDKFactory,
+	loggingMode logging.Mode,
+	client client.Client,
+	syncPeriod time.Duration,
+	batchSize uint,
+) *KonnectBulkSyncReconciler {
+	return &KonnectBulkSyncReconciler{
+		sdkFactory:  sdkFactory,
+		LoggingMode: loggingMode,
+		Client:      client,
+		SyncPeriod:  syncPeriod,
+		BatchSize:   batchSize,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KonnectBulkSyncReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("KonnectBulkSync").
+		For(&konnectv1alpha1.KonnectGatewayControlPlane{},
+			builder.WithPredicates(
+				// Only changes of the annotation and of the status (e.g. the Konnect ID)
+				// are relevant, the ControlPlanes are synced periodically otherwise.
+				predicate.Or(
+					predicate.AnnotationChangedPredicate{},
+					predicate.Funcs{
+						UpdateFunc: func(e event.UpdateEvent) bool {
+							return konnectID(e.ObjectOld) != konnectID(e.ObjectNew)
+						},
+					},
+				),
+			),
+		).
+		Complete(r)
+}
+
+// Reconcile syncs the entities of the Konnect ControlPlane of the KonnectGatewayControlPlane in bulk.
+func (r *KonnectBulkSyncReconciler) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	logger := log.GetLogger(ctx, "KonnectBulkSync", r.LoggingMode)
+
+	var cp konnectv1alpha1.KonnectGatewayControlPlane
+	if err := r.Client.Get(ctx, req.NamespacedName, &cp); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !isInBulkSyncMode(&cp) || !cp.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Paused ControlPlanes are reconciled again when they're resumed
+	// as the annotation changes.
+	if pause.IsPaused(&cp) {
+		log.Debug(logger, "ControlPlane reconciliation is paused, skipping bulk sync")
+		return ctrl.Result{}, nil
+	}
+
+	cpID := cp.GetKonnectID()
+	if cpID == "" {
+		log.Debug(logger, "ControlPlane does not have a Konnect ID yet, skipping bulk sync")
+		return ctrl.Result{}, nil
+	}
+
+	apiAuthRef, err := getAPIAuthRefNN(ctx, r.Client, &cp)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get APIAuth ref for %s: %w", client.ObjectKeyFromObject(&cp), err)
+	}
+	var apiAuth konnectv1alpha1.KonnectAPIAuthConfiguration
+	if err := r.Client.Get(ctx, apiAuthRef, &apiAuth); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get KonnectAPIAuthConfiguration %s: %w", apiAuthRef, err)
+	}
+	token, err := getTokenFromKonnectAPIAuthConfiguration(ctx, r.Client, r.sdkFactory, &apiAuth)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	server, err := server.NewServer[konnectv1alpha1.KonnectGatewayControlPlane](apiAuth.Spec.ServerURL)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to parse server URL: %w", err)
+	}
+	sdk := r.sdkFactory.NewKonnectSDK(server, sdkops.SDKToken(token))
+
+	// NOTE: Objects can only reference ControlPlanes from their own namespace.
+	var (
+		services  configurationv1alpha1.KongServiceList
+		routes    configurationv1alpha1.KongRouteList
+		consumers configurationv1.KongConsumerList
+	)
+	if err := r.Client.List(ctx, &services, client.InNamespace(cp.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list KongServices: %w", err)
+	}
+	if err := r.Client.List(ctx, &routes, client.InNamespace(cp.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list KongRoutes: %w", err)
+	}
+	if err := r.Client.List(ctx, &consumers, client.InNamespace(cp.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list KongConsumers: %w", err)
+	}
+
+	svcs, oldSvcs, err := bulkSyncCandidates(r.Client.Scheme(), services.Items, cpID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	rts, oldRts, err := bulkSyncCandidates(r.Client.Scheme(), routes.Items, cpID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	cons, oldCons, err := bulkSyncCandidates(r.Client.Scheme(), consumers.Items, cpID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	result, errSync := ops.BulkSync(ctx, sdk, cpID, ops.BulkSyncObjects{
+		Services:  svcs,
+		Routes:    rts,
+		Consumers: cons,
+	}, int(r.BatchSize)) //nolint:gosec
+	log.Debug(logger, "synced entities in bulk",
+		"applied", result.Applied,
+		"pending", result.Pending,
+	)
+
+	// Write the Konnect IDs and Programmed conditions back regardless of the error.
+	errs := []error{errSync}
+	errs = append(errs, patchBulkSyncedStatus(ctx, r.Client, logger, svcs, oldSvcs)...)
+	errs = append(errs, patchBulkSyncedStatus(ctx, r.Client, logger, rts, oldRts)...)
+	errs = append(errs, patchBulkSyncedStatus(ctx, r.Client, logger, cons, oldCons)...)
+	if err := errors.Join(errs...); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if result.Pending > 0 {
+		return ctrl.Result{RequeueAfter: konnectBulkSyncPendingRequeueAfter}, nil
+	}
+	return ctrl.Result{RequeueAfter: r.SyncPeriod}, nil
+}
+
+// bulkSyncCandidates returns the provided objects whose Konnect entities in the
+// provided ControlPlane are synced in bulk, together with their copies.
+func bulkSyncCandidates[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](scheme *runtime.Scheme, items []T, cpID string) ([]TEnt, []TEnt, error) {
+	var objs, old []TEnt
+	for i := range items {
+		ent := TEnt(&items[i])
+		cpIDGetter, ok := any(ent).(interface{ GetControlPlaneID() string })
+		if !ok ||
+			cpIDGetter.GetControlPlaneID() != cpID ||
+			ent.GetKonnectStatus().GetKonnectID() == "" ||
+			!ent.GetDeletionTimestamp().IsZero() ||
+			pause.IsPaused(ent) ||
+			!isProgrammedForGeneration(ent) {
+			continue
+		}
+		if _, ok := ent.GetLabels()[consts.KonnectMirroredFromControlPlaneLabelKey]; ok {
+			continue
+		}
+		// Listed objects might not have their type meta set while it's part
+		// of the Kubernetes metadata tags of their Konnect entities.
+		gvk, err := apiutil.GVKForObject(ent, scheme)
+		if err != nil {
+			return nil, nil, err
+		}
+		ent.GetObjectKind().SetGroupVersionKind(gvk)
+		objs = append(objs, ent)
+		old = append(old, ent.DeepCopyObject().(TEnt))
+	}
+	return objs, old, nil
+}
+
+// patchBulkSyncedStatus patches the status of the objects synced in bulk.
+func patchBulkSyncedStatus[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	objs []TEnt,
+	old []TEnt,
+) []error {
+	var errs []error
+	for i, obj := range objs {
+		if _, err := patch.ApplyStatusPatchIfNotEmpty(ctx, cl, logger, obj, old[i]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update status of %s %s: %w",
+				obj.GetTypeName(), client.ObjectKeyFromObject(obj), err,
+			))
+		}
+	}
+	return errs
+}
+
+// isProgrammedForGeneration returns true when the object's Programmed condition
+// is true for the object's current generation.
+func isProgrammedForGeneration(obj interface {
+	k8sutils.ConditionsAware
+	GetGeneration() int64
+},
+) bool {
+	cond, ok := k8sutils.GetCondition(konnectv1alpha1.KonnectEntityProgrammedConditionType, obj)
+	return ok &&
+		cond.Status == metav1.ConditionTrue &&
+		cond.Reason == konnectv1alpha1.KonnectEntityProgrammedReasonProgrammed &&
+		cond.ObservedGeneration == obj.GetGeneration()
+}
+
+// isInBulkSyncMode returns true when the KonnectGatewayControlPlane's entities
+// are synced in bulk.
+func isInBulkSyncMode(cp *konnectv1alpha1.KonnectGatewayControlPlane) bool {
+	return cp.GetAnnotations()[consts.KonnectSyncModeAnnotationKey] == consts.KonnectSyncModeBulk
+}
+
+// isSyncedInBulk returns true when the provided object's Konnect entity is
+// periodically synced by the KonnectBulkSyncReconciler, i.e. when it's a KongService,
+// KongRoute or KongConsumer programmed for its current generation whose
+// KonnectGatewayControlPlane is in the bulk sync mode.
+func isSyncedInBulk[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](
+	ctx context.Context,
+	cl client.Client,
+	ent TEnt,
+) (bool, error) {
+	switch any(ent).(type) {
+	case *configurationv1alpha1.KongService,
+		*configurationv1alpha1.KongRoute,
+		*configurationv1.KongConsumer:
+	default:
+		return false, nil
+	}
+	if !isProgrammedForGeneration(ent) {
+		return false, nil
+	}
+	cpID := any(ent).(interface{ GetControlPlaneID() string }).GetControlPlaneID()
+	if cpID == "" {
+		return false, nil
+	}
+
+	// NOTE: Objects can only reference ControlPlanes from their own namespace.
+	var cps konnectv1alpha1.KonnectGatewayControlPlaneList
+	if err := cl.List(ctx, &cps,
+		client.InNamespace(ent.GetNamespace()),
+		client.MatchingFields{
+			index.IndexFieldKonnectGatewayControlPlaneOnKonnectID: cpID,
+		},
+	); err != nil {
+		return false, fmt.Errorf("failed to list KonnectGatewayControlPlanes: %w", err)
+	}
+	return lo.ContainsBy(cps.Items, func(cp konnectv1alpha1.KonnectGatewayControlPlane) bool {
+		return isInBulkSyncMode(&cp)
+	}), nil
+}
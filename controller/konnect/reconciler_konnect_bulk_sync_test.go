@@ -0,0 +1,156 @@
+package konnect
+
+import (
+	"testing"
+	"time"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/controller/konnect/ops"
+	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+	"github.com/kong/gateway-operator/internal/utils/index"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestKonnectBulkSyncReconciler(t *testing.T) {
+	const cpID = "cp-id"
+
+	cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cp",
+			Namespace: "default",
+			Annotations: map[string]string{
+				consts.KonnectSyncModeAnnotationKey: consts.KonnectSyncModeBulk,
+			},
+		},
+		Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+			KonnectConfiguration: konnectv1alpha1.KonnectConfiguration{
+				APIAuthConfigurationRef: konnectv1alpha1.KonnectAPIAuthConfigurationRef{
+					Name: "auth",
+				},
+			},
+		},
+		Status: konnectv1alpha1.KonnectGatewayControlPlaneStatus{
+			KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+				ID: cpID,
+			},
+		},
+	}
+	apiAuth := &konnectv1alpha1.KonnectAPIAuthConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth",
+			Namespace: "default",
+		},
+		Spec: konnectv1alpha1.KonnectAPIAuthConfigurationSpec{
+			Type:      konnectv1alpha1.KonnectAPIAuthTypeToken,
+			Token:     "kpat_xxxxxxxxxxxx",
+			ServerURL: "us.api.konghq.com",
+		},
+	}
+	newService := func(name string, programmed bool) *configurationv1alpha1.KongService {
+		svc := &configurationv1alpha1.KongService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  "default",
+				UID:        types.UID(name + "-uid"),
+				Generation: 1,
+			},
+			Spec: configurationv1alpha1.KongServiceSpec{
+				KongServiceAPISpec: configurationv1alpha1.KongServiceAPISpec{
+					Host: name + ".example.com",
+				},
+			},
+		}
+		svc.SetControlPlaneID(cpID)
+		svc.SetKonnectID(name + "-id")
+		if programmed {
+			ops.SetKonnectEntityProgrammedConditionTrue(svc)
+		}
+		return svc
+	}
+	programmed := newService("programmed", true)
+	notProgrammed := newService("not-programmed", false)
+
+	builder := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(cp, apiAuth, programmed, notProgrammed).
+		WithStatusSubresource(&configurationv1alpha1.KongService{})
+	for _, opt := range index.OptionsForKonnectGatewayControlPlane() {
+		builder = builder.WithIndex(opt.Object, opt.Field, opt.ExtractValueFn)
+	}
+	cl := builder.Build()
+
+	t.Log("programmed entities of ControlPlanes in the bulk sync mode are not updated on their own")
+	for _, svc := range []*configurationv1alpha1.KongService{programmed, notProgrammed} {
+		bulk, err := isSyncedInBulk(t.Context(), cl, svc)
+		require.NoError(t, err)
+		assert.Equal(t, svc == programmed, bulk, svc.Name)
+	}
+
+	sdkFactory := sdkmocks.NewMockSDKFactory(t)
+	sdk := sdkFactory.SDK
+	tagged := programmed.DeepCopy()
+	tagged.SetGroupVersionKind(configurationv1alpha1.GroupVersion.WithKind("KongService"))
+	sdk.ServicesSDK.EXPECT().ListService(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListServiceResponse{
+			Object: &sdkkonnectops.ListServiceResponseBody{
+				Data: []sdkkonnectcomp.ServiceOutput{
+					{
+						// The entity was recreated with a different ID.
+						ID:   lo.ToPtr("recreated-id"),
+						Host: "changed.example.com",
+						Tags: ops.GenerateTagsForObject(tagged),
+					},
+				},
+			},
+		}, nil).
+		Once()
+	sdk.RoutesSDK.EXPECT().ListRoute(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListRouteResponse{Object: &sdkkonnectops.ListRouteResponseBody{}}, nil).
+		Once()
+	sdk.ConsumersSDK.EXPECT().ListConsumer(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListConsumerResponse{Object: &sdkkonnectops.ListConsumerResponseBody{}}, nil).
+		Once()
+	sdk.ServicesSDK.EXPECT().
+		UpsertService(mock.Anything, mock.MatchedBy(func(req sdkkonnectops.UpsertServiceRequest) bool {
+			return req.ServiceID == "recreated-id" && req.Service.Host == "programmed.example.com"
+		})).
+		Return(&sdkkonnectops.UpsertServiceResponse{}, nil).
+		Once()
+
+	r := NewKonnectBulkSyncReconciler(sdkFactory, logging.DevelopmentMode, cl, time.Minute, 10)
+	res, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "cp"}})
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, res)
+
+	var svc configurationv1alpha1.KongService
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(programmed), &svc))
+	assert.Equal(t, "recreated-id", svc.GetKonnectID())
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(notProgrammed), &svc))
+	assert.Equal(t, "not-programmed-id", svc.GetKonnectID(), "objects which are not programmed should not be synced in bulk")
+
+	t.Log("entities are not synced in bulk when the annotation is removed")
+	cp.Annotations = nil
+	require.NoError(t, cl.Update(t.Context(), cp))
+	res, err = r.Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "cp"}})
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	bulk, err := isSyncedInBulk(t.Context(), cl, programmed)
+	require.NoError(t, err)
+	assert.False(t, bulk)
+}
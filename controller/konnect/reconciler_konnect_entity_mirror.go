@@ -0,0 +1,517 @@
+package konnect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/kong/gateway-operator/controller/konnect/ops"
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/konnect/server"
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+const (
+	// KonnectEntityMirrorConflictEventReason is the reason of the event emitted for
+	// KonnectGatewayControlPlanes when a Konnect entity can't be mirrored because
+	// an object with the same name already exists.
+	KonnectEntityMirrorConflictEventReason = "KonnectEntityMirrorConflict"
+)
+
+// KonnectEntityMirrorReconciler mirrors the entities configured directly in
+// Konnect ControlPlanes (e.g. through the Konnect UI) into the cluster.
+// Only KonnectGatewayControlPlanes annotated with
+// consts.KonnectMirrorEntitiesAnnotationKey set to "true" are mirrored.
+//
+// Services, routes and consumers which were not created for Kubernetes objects
+// are materialized as KongServices, KongRoutes and KongConsumers in the
+// ControlPlane's namespace, labeled with consts.KonnectMirroredFromControlPlaneLabelKey.
+// Such objects are read-only: their status is set by this reconciler and they
+// are not reconciled against Konnect, so that KongRoutes and KongPluginBindings
+// can reference them like any other object. They are refreshed every sync period.
+type KonnectEntityMirrorReconciler struct {
+	sdkFactory  sdkops.SDKFactory
+	LoggingMode logging.Mode
+	Client      client.Client
+	SyncPeriod  time.Duration
+
+	eventRecorder record.EventRecorder
+}
+
+// NewKonnectEntityMirrorReconciler creates a new KonnectEntityMirrorReconciler.
+func NewKonnectEntityMirrorReconciler(
+	sdkFactory sdkops.SDKFactory,
+	loggingMode logging.Mode,
+	client client.Client,
+	syncPeriod time.Duration,
+) *KonnectEntityMirrorReconciler {
+	return &KonnectEntityMirrorReconciler{
+		sdkFactory:  sdkFactory,
+		LoggingMode: loggingMode,
+		Client:      client,
+		SyncPeriod:  syncPeriod,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KonnectEntityMirrorReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("KonnectEntityMirror")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("KonnectEntityMirror").
+		For(&konnectv1alpha1.KonnectGatewayControlPlane{},
+			builder.WithPredicates(
+				// Only changes of the annotation and of the status (e.g. the Konnect ID)
+				// are relevant, the ControlPlanes are mirrored periodically otherwise.
+				predicate.Or(
+					predicate.AnnotationChangedPredicate{},
+					predicate.Funcs{
+						UpdateFunc: func(e event.UpdateEvent) bool {
+							return konnectID(e.ObjectOld) != konnectID(e.ObjectNew)
+						},
+					},
+				),
+			),
+		).
+		Complete(r)
+}
+
+// Reconcile mirrors the entities of the Konnect ControlPlane of the KonnectGatewayControlPlane.
+func (r *KonnectEntityMirrorReconciler) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	logger := log.GetLogger(ctx, "KonnectEntityMirror", r.LoggingMode)
+
+	var cp konnectv1alpha1.KonnectGatewayControlPlane
+	if err := r.Client.Get(ctx, req.NamespacedName, &cp); err != nil {
+		// Mirrored objects are owned by the ControlPlane so they are garbage
+		// collected together with it.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if cp.GetAnnotations()[consts.KonnectMirrorEntitiesAnnotationKey] != "true" ||
+		!cp.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, r.pruneMirroredObjects(ctx, &cp, nil)
+	}
+
+	// Paused ControlPlanes are reconciled again when they're resumed
+	// as the annotation changes.
+	if pause.IsPaused(&cp) {
+		log.Debug(logger, "ControlPlane reconciliation is paused, skipping mirroring Konnect entities")
+		return ctrl.Result{}, nil
+	}
+
+	cpID := cp.GetKonnectID()
+	if cpID == "" {
+		log.Debug(logger, "ControlPlane does not have a Konnect ID yet, skipping mirroring Konnect entities")
+		return ctrl.Result{}, nil
+	}
+
+	apiAuthRef, err := getAPIAuthRefNN(ctx, r.Client, &cp)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get APIAuth ref for %s: %w", client.ObjectKeyFromObject(&cp), err)
+	}
+	var apiAuth konnectv1alpha1.KonnectAPIAuthConfiguration
+	if err := r.Client.Get(ctx, apiAuthRef, &apiAuth); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get KonnectAPIAuthConfiguration %s: %w", apiAuthRef, err)
+	}
+	token, err := getTokenFromKonnectAPIAuthConfiguration(ctx, r.Client, r.sdkFactory, &apiAuth)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	server, err := server.NewServer[konnectv1alpha1.KonnectGatewayControlPlane](apiAuth.Spec.ServerURL)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to parse server URL: %w", err)
+	}
+	sdk := r.sdkFactory.NewKonnectSDK(server, sdkops.SDKToken(token))
+
+	entities, err := ops.ListUnmanagedKonnectEntities(ctx, sdk, cpID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var (
+		// mirrored maps Konnect IDs of mirrored entities to the names of their objects.
+		mirrored = make(map[string]string)
+		// serviceNames maps Konnect IDs of services to the names of the KongServices
+		// mirroring them or managed by the operator so that mirrored KongRoutes
+		// can reference them.
+		serviceNames = make(map[string]string)
+		errs         []error
+	)
+	ensure := func(obj mirroredObject, konnectID string, setSpec func(), setStatus func()) bool {
+		err := r.ensureMirroredObject(ctx, &cp, obj, setSpec, setStatus)
+		var errConflict errMirroredObjectConflict
+		switch {
+		case errors.As(err, &errConflict):
+			r.eventRecorder.Event(&cp, corev1.EventTypeWarning, KonnectEntityMirrorConflictEventReason, err.Error())
+			return false
+		case err != nil:
+			errs = append(errs, err)
+			return false
+		}
+		mirrored[konnectID] = obj.GetName()
+		return true
+	}
+
+	svcObjNames := mirroredObjectNames(&cp, entities.Services, func(svc sdkkonnectcomp.ServiceOutput) (*string, string) {
+		return svc.Name, *svc.ID
+	})
+	for _, svc := range entities.Services {
+		id := *svc.ID
+		obj := &configurationv1alpha1.KongService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      svcObjNames[id],
+				Namespace: cp.Namespace,
+			},
+		}
+		if ensure(obj, id,
+			func() {
+				obj.Spec = configurationv1alpha1.KongServiceSpec{
+					ControlPlaneRef:    mirroredObjectControlPlaneRef(&cp),
+					KongServiceAPISpec: kongServiceAPISpecFromKonnect(svc),
+				}
+			},
+			func() {
+				obj.Status.Konnect = &konnectv1alpha1.KonnectEntityStatusWithControlPlaneRef{
+					KonnectEntityStatus: mirroredObjectKonnectStatus(&cp, id),
+					ControlPlaneID:      cpID,
+				}
+				ops.SetKonnectEntityProgrammedConditionTrue(obj)
+			},
+		) {
+			serviceNames[id] = obj.Name
+		}
+	}
+
+	// Routes of services managed by the operator reference the KongServices
+	// these services were created for when they're in the ControlPlane's namespace.
+	for id, obj := range entities.ManagedServices {
+		if obj.GetNamespace() == cp.Namespace {
+			serviceNames[id] = obj.GetName()
+		}
+	}
+
+	routeObjNames := mirroredObjectNames(&cp, entities.Routes, func(route sdkkonnectcomp.RouteJSON) (*string, string) {
+		return route.Name, *route.ID
+	})
+	for _, route := range entities.Routes {
+		id := *route.ID
+		serviceID := ""
+		if route.Service != nil && route.Service.ID != nil {
+			serviceID = *route.Service.ID
+		}
+		obj := &configurationv1alpha1.KongRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      routeObjNames[id],
+				Namespace: cp.Namespace,
+			},
+		}
+		ensure(obj, id,
+			func() {
+				obj.Spec = configurationv1alpha1.KongRouteSpec{
+					KongRouteAPISpec: kongRouteAPISpecFromKonnect(route),
+				}
+				// Routes of services which are neither mirrored nor managed by the operator
+				// in the ControlPlane's namespace are mirrored as serviceless routes.
+				if svcName, ok := serviceNames[serviceID]; ok {
+					obj.Spec.ServiceRef = &configurationv1alpha1.ServiceRef{
+						Type: configurationv1alpha1.ServiceRefNamespacedRef,
+						NamespacedRef: &commonv1alpha1.NameRef{
+							Name: svcName,
+						},
+					}
+				} else {
+					obj.Spec.ControlPlaneRef = mirroredObjectControlPlaneRef(&cp)
+				}
+			},
+			func() {
+				obj.Status.Konnect = &konnectv1alpha1.KonnectEntityStatusWithControlPlaneAndServiceRefs{
+					KonnectEntityStatus: mirroredObjectKonnectStatus(&cp, id),
+					ControlPlaneID:      cpID,
+					ServiceID:           serviceID,
+				}
+				ops.SetKonnectEntityProgrammedConditionTrue(obj)
+			},
+		)
+	}
+
+	consumerObjNames := mirroredObjectNames(&cp, entities.Consumers, func(consumer sdkkonnectcomp.Consumer) (*string, string) {
+		if consumer.Username != nil {
+			return consumer.Username, *consumer.ID
+		}
+		return consumer.CustomID, *consumer.ID
+	})
+	for _, consumer := range entities.Consumers {
+		id := *consumer.ID
+		obj := &configurationv1.KongConsumer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      consumerObjNames[id],
+				Namespace: cp.Namespace,
+			},
+		}
+		ensure(obj, id,
+			func() {
+				obj.Username = lo.FromPtr(consumer.Username)
+				obj.CustomID = lo.FromPtr(consumer.CustomID)
+				obj.Spec = configurationv1.KongConsumerSpec{
+					ControlPlaneRef: mirroredObjectControlPlaneRef(&cp),
+					Tags:            consumer.Tags,
+				}
+			},
+			func() {
+				obj.Status.Konnect = &konnectv1alpha1.KonnectEntityStatusWithControlPlaneRef{
+					KonnectEntityStatus: mirroredObjectKonnectStatus(&cp, id),
+					ControlPlaneID:      cpID,
+				}
+				ops.SetKonnectEntityProgrammedConditionTrue(obj)
+			},
+		)
+	}
+
+	if err := r.pruneMirroredObjects(ctx, &cp, mirrored); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Debug(logger, "mirrored Konnect entities", "count", len(mirrored))
+	return ctrl.Result{RequeueAfter: r.SyncPeriod}, nil
+}
+
+// mirroredObject is an object mirroring a Konnect entity.
+type mirroredObject interface {
+	client.Object
+	GetKonnectStatus() *konnectv1alpha1.KonnectEntityStatus
+}
+
+// errMirroredObjectConflict is returned when a Konnect entity can't be mirrored
+// because an object which does not mirror it already exists.
+type errMirroredObjectConflict struct {
+	obj client.Object
+}
+
+func (e errMirroredObjectConflict) Error() string {
+	return fmt.Sprintf("%T %s already exists and is not mirrored from Konnect",
+		e.obj, client.ObjectKeyFromObject(e.obj),
+	)
+}
+
+// ensureMirroredObject creates or updates the provided object mirroring a
+// Konnect entity of the provided ControlPlane. setSpec and setStatus are called
+// to set the desired spec and status on the object.
+func (r *KonnectEntityMirrorReconciler) ensureMirroredObject(
+	ctx context.Context,
+	cp *konnectv1alpha1.KonnectGatewayControlPlane,
+	obj mirroredObject,
+	setSpec func(),
+	setStatus func(),
+) error {
+	logger := ctrl.LoggerFrom(ctx)
+
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	switch {
+	case k8serrors.IsNotFound(err):
+		setSpec()
+		obj.SetLabels(map[string]string{consts.KonnectMirroredFromControlPlaneLabelKey: cp.Name})
+		if err := controllerutil.SetOwnerReference(cp, obj, r.Client.Scheme()); err != nil {
+			return err
+		}
+		if err := r.Client.Create(ctx, obj); err != nil {
+			return fmt.Errorf("failed creating %T %s: %w", obj, client.ObjectKeyFromObject(obj), err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed getting %T %s: %w", obj, client.ObjectKeyFromObject(obj), err)
+	default:
+		if obj.GetLabels()[consts.KonnectMirroredFromControlPlaneLabelKey] != cp.Name {
+			return errMirroredObjectConflict{obj: obj}
+		}
+		old := obj.DeepCopyObject().(mirroredObject)
+		setSpec()
+		if _, _, err := patch.ApplyPatchIfNotEmpty(ctx, r.Client, logger, obj, old, true); err != nil {
+			return err
+		}
+	}
+
+	old := obj.DeepCopyObject().(mirroredObject)
+	setStatus()
+	_, err = patch.ApplyStatusPatchIfNotEmpty(ctx, r.Client, logger, obj, old)
+	return err
+}
+
+// pruneMirroredObjects deletes the objects mirrored from the provided ControlPlane
+// whose Konnect entities are not in mirrored anymore or are mirrored by objects
+// with other names, e.g. because their names started colliding.
+func (r *KonnectEntityMirrorReconciler) pruneMirroredObjects(
+	ctx context.Context,
+	cp *konnectv1alpha1.KonnectGatewayControlPlane,
+	mirrored map[string]string,
+) error {
+	opts := []client.ListOption{
+		client.InNamespace(cp.Namespace),
+		client.MatchingLabels{consts.KonnectMirroredFromControlPlaneLabelKey: cp.Name},
+	}
+
+	var (
+		services  configurationv1alpha1.KongServiceList
+		routes    configurationv1alpha1.KongRouteList
+		consumers configurationv1.KongConsumerList
+		objs      []mirroredObject
+	)
+	if err := r.Client.List(ctx, &services, opts...); err != nil {
+		return fmt.Errorf("failed listing mirrored KongServices: %w", err)
+	}
+	if err := r.Client.List(ctx, &routes, opts...); err != nil {
+		return fmt.Errorf("failed listing mirrored KongRoutes: %w", err)
+	}
+	if err := r.Client.List(ctx, &consumers, opts...); err != nil {
+		return fmt.Errorf("failed listing mirrored KongConsumers: %w", err)
+	}
+	for i := range services.Items {
+		objs = append(objs, &services.Items[i])
+	}
+	for i := range routes.Items {
+		objs = append(objs, &routes.Items[i])
+	}
+	for i := range consumers.Items {
+		objs = append(objs, &consumers.Items[i])
+	}
+
+	var errs []error
+	for _, obj := range objs {
+		if name, ok := mirrored[obj.GetKonnectStatus().GetKonnectID()]; ok && name == obj.GetName() {
+			continue
+		}
+		if err := r.Client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("failed deleting %T %s: %w", obj, client.ObjectKeyFromObject(obj), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// mirroredObjectNames returns the names of the objects mirroring the provided
+// Konnect entities of a single kind, keyed by the entities' Konnect IDs.
+// Konnect names are case sensitive so the Konnect IDs are appended to the names
+// of entities which would otherwise be mirrored as objects with the same name.
+func mirroredObjectNames[T any](
+	cp *konnectv1alpha1.KonnectGatewayControlPlane,
+	entities []T,
+	nameAndID func(T) (*string, string),
+) map[string]string {
+	var (
+		names  = make(map[string]string, len(entities))
+		counts = make(map[string]int, len(entities))
+	)
+	for _, e := range entities {
+		name, id := nameAndID(e)
+		names[id] = mirroredObjectName(cp, name, id)
+		counts[names[id]]++
+	}
+	for id, name := range names {
+		if counts[name] < 2 {
+			continue
+		}
+		if n := name + "-" + id; len(validation.IsDNS1123Subdomain(n)) == 0 {
+			names[id] = n
+		} else {
+			names[id] = cp.Name + "-" + id
+		}
+	}
+	return names
+}
+
+// mirroredObjectName returns the name of the object mirroring a Konnect entity:
+// the ControlPlane's name followed by the entity's name or, when the entity's
+// name can't be used as an object name, by its Konnect ID.
+func mirroredObjectName(cp *konnectv1alpha1.KonnectGatewayControlPlane, name *string, id string) string {
+	if name != nil {
+		n := strings.ToLower(cp.Name + "-" + *name)
+		if len(validation.IsDNS1123Subdomain(n)) == 0 {
+			return n
+		}
+	}
+	return cp.Name + "-" + id
+}
+
+func mirroredObjectControlPlaneRef(cp *konnectv1alpha1.KonnectGatewayControlPlane) *commonv1alpha1.ControlPlaneRef {
+	return &commonv1alpha1.ControlPlaneRef{
+		Type: commonv1alpha1.ControlPlaneRefKonnectNamespacedRef,
+		KonnectNamespacedRef: &commonv1alpha1.KonnectNamespacedRef{
+			Name: cp.Name,
+		},
+	}
+}
+
+func mirroredObjectKonnectStatus(cp *konnectv1alpha1.KonnectGatewayControlPlane, id string) konnectv1alpha1.KonnectEntityStatus {
+	return konnectv1alpha1.KonnectEntityStatus{
+		ID:        id,
+		ServerURL: cp.Status.ServerURL,
+		OrgID:     cp.Status.OrgID,
+	}
+}
+
+func kongServiceAPISpecFromKonnect(svc sdkkonnectcomp.ServiceOutput) configurationv1alpha1.KongServiceAPISpec {
+	return configurationv1alpha1.KongServiceAPISpec{
+		ConnectTimeout: svc.ConnectTimeout,
+		Enabled:        svc.Enabled,
+		Host:           svc.Host,
+		Name:           svc.Name,
+		Path:           svc.Path,
+		Port:           lo.FromPtr(svc.Port),
+		Protocol:       lo.FromPtr(svc.Protocol),
+		ReadTimeout:    svc.ReadTimeout,
+		Retries:        svc.Retries,
+		Tags:           svc.Tags,
+		TLSVerify:      svc.TLSVerify,
+		TLSVerifyDepth: svc.TLSVerifyDepth,
+		WriteTimeout:   svc.WriteTimeout,
+	}
+}
+
+func kongRouteAPISpecFromKonnect(route sdkkonnectcomp.RouteJSON) configurationv1alpha1.KongRouteAPISpec {
+	return configurationv1alpha1.KongRouteAPISpec{
+		Destinations:            route.Destinations,
+		Headers:                 route.Headers,
+		Hosts:                   route.Hosts,
+		HTTPSRedirectStatusCode: route.HTTPSRedirectStatusCode,
+		Methods:                 route.Methods,
+		Name:                    route.Name,
+		PathHandling:            route.PathHandling,
+		Paths:                   route.Paths,
+		PreserveHost:            route.PreserveHost,
+		Protocols:               route.Protocols,
+		RegexPriority:           route.RegexPriority,
+		RequestBuffering:        route.RequestBuffering,
+		ResponseBuffering:       route.ResponseBuffering,
+		Snis:                    route.Snis,
+		Sources:                 route.Sources,
+		StripPath:               route.StripPath,
+		Tags:                    route.Tags,
+	}
+}
@@ -0,0 +1,321 @@
+package konnect
+
+import (
+	"testing"
+	"time"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestKonnectEntityMirrorReconciler(t *testing.T) {
+	const cpID = "cp-id"
+
+	cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cp",
+			Namespace: "default",
+			UID:       "cp-uid",
+			Annotations: map[string]string{
+				consts.KonnectMirrorEntitiesAnnotationKey: "true",
+			},
+		},
+		Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+			KonnectConfiguration: konnectv1alpha1.KonnectConfiguration{
+				APIAuthConfigurationRef: konnectv1alpha1.KonnectAPIAuthConfigurationRef{
+					Name: "auth",
+				},
+			},
+		},
+		Status: konnectv1alpha1.KonnectGatewayControlPlaneStatus{
+			KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+				ID:        cpID,
+				ServerURL: "https://us.api.konghq.com",
+				OrgID:     "org-id",
+			},
+		},
+	}
+	apiAuth := &konnectv1alpha1.KonnectAPIAuthConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth",
+			Namespace: "default",
+		},
+		Spec: konnectv1alpha1.KonnectAPIAuthConfigurationSpec{
+			Type:      konnectv1alpha1.KonnectAPIAuthTypeToken,
+			Token:     "kpat_xxxxxxxxxxxx",
+			ServerURL: "us.api.konghq.com",
+		},
+	}
+	// A KongConsumer created by a user with the name of a mirrored consumer.
+	userConsumer := &configurationv1.KongConsumer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cp-bob",
+			Namespace: "default",
+		},
+		Username: "bob",
+	}
+
+	sdkFactory := sdkmocks.NewMockSDKFactory(t)
+	sdk := sdkFactory.SDK
+	expectServices := func(services ...sdkkonnectcomp.ServiceOutput) {
+		sdk.ServicesSDK.EXPECT().ListService(mock.Anything, mock.Anything).
+			Return(&sdkkonnectops.ListServiceResponse{
+				Object: &sdkkonnectops.ListServiceResponseBody{Data: services},
+			}, nil).Once()
+	}
+	expectRoutes := func(routes ...sdkkonnectcomp.Route) {
+		sdk.RoutesSDK.EXPECT().ListRoute(mock.Anything, mock.Anything).
+			Return(&sdkkonnectops.ListRouteResponse{
+				Object: &sdkkonnectops.ListRouteResponseBody{Data: routes},
+			}, nil).Once()
+	}
+	expectConsumers := func(consumers ...sdkkonnectcomp.Consumer) {
+		sdk.ConsumersSDK.EXPECT().ListConsumer(mock.Anything, mock.Anything).
+			Return(&sdkkonnectops.ListConsumerResponse{
+				Object: &sdkkonnectops.ListConsumerResponseBody{Data: consumers},
+			}, nil).Once()
+	}
+	payments := sdkkonnectcomp.ServiceOutput{
+		ID:   lo.ToPtr("svc-payments"),
+		Name: lo.ToPtr("Payments"),
+		Host: "payments.example.com",
+		Port: lo.ToPtr(int64(443)),
+	}
+	managed := sdkkonnectcomp.ServiceOutput{
+		ID:   lo.ToPtr("svc-managed"),
+		Name: lo.ToPtr("managed"),
+		Tags: []string{
+			"k8s-group:configuration.konghq.com",
+			"k8s-kind:KongService",
+			"k8s-name:managed",
+			"k8s-namespace:default",
+			"k8s-uid:managed-uid",
+			"k8s-version:v1alpha1",
+		},
+	}
+	route := sdkkonnectcomp.Route{
+		RouteJSON: &sdkkonnectcomp.RouteJSON{
+			ID:      lo.ToPtr("route-id"),
+			Name:    lo.ToPtr("payments_route"),
+			Paths:   []string{"/payments"},
+			Service: &sdkkonnectcomp.RouteJSONService{ID: lo.ToPtr("svc-payments")},
+		},
+	}
+	alice := sdkkonnectcomp.Consumer{ID: lo.ToPtr("consumer-alice"), Username: lo.ToPtr("alice")}
+	bob := sdkkonnectcomp.Consumer{ID: lo.ToPtr("consumer-bob"), Username: lo.ToPtr("bob")}
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(cp, apiAuth, userConsumer).
+		WithStatusSubresource(
+			&configurationv1alpha1.KongService{},
+			&configurationv1alpha1.KongRoute{},
+			&configurationv1.KongConsumer{},
+		).
+		Build()
+
+	eventRecorder := record.NewFakeRecorder(10)
+	r := NewKonnectEntityMirrorReconciler(sdkFactory, logging.DevelopmentMode, cl, time.Minute)
+	r.eventRecorder = eventRecorder
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "cp"}}
+
+	t.Log("unmanaged Konnect entities are mirrored")
+	expectServices(payments, managed)
+	expectRoutes(route)
+	expectConsumers(alice, bob)
+	res, err := r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, res)
+
+	var svc configurationv1alpha1.KongService
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-payments"}, &svc))
+	assert.Equal(t, "cp", svc.Labels[consts.KonnectMirroredFromControlPlaneLabelKey])
+	assert.Equal(t, "payments.example.com", svc.Spec.Host)
+	assert.Equal(t, "cp", svc.Spec.ControlPlaneRef.KonnectNamespacedRef.Name)
+	assert.Equal(t, "svc-payments", svc.GetKonnectID())
+	assert.Equal(t, cpID, svc.Status.Konnect.ControlPlaneID)
+	assert.Equal(t, "org-id", svc.Status.Konnect.OrgID)
+	assert.True(t, k8sutils.HasConditionTrue(konnectv1alpha1.KonnectEntityProgrammedConditionType, &svc))
+
+	var kongRoute configurationv1alpha1.KongRoute
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-route-id"}, &kongRoute))
+	require.NotNil(t, kongRoute.Spec.ServiceRef)
+	assert.Equal(t, "cp-payments", kongRoute.Spec.ServiceRef.NamespacedRef.Name)
+	assert.Equal(t, []string{"/payments"}, kongRoute.Spec.Paths)
+	assert.Equal(t, "svc-payments", kongRoute.Status.Konnect.ServiceID)
+
+	var consumer configurationv1.KongConsumer
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-alice"}, &consumer))
+	assert.Equal(t, "alice", consumer.Username)
+	assert.Equal(t, "consumer-alice", consumer.GetKonnectID())
+
+	var services configurationv1alpha1.KongServiceList
+	require.NoError(t, cl.List(t.Context(), &services))
+	assert.Len(t, services.Items, 1, "entities managed by the operator should not be mirrored")
+
+	require.Len(t, eventRecorder.Events, 1)
+	assert.Contains(t, <-eventRecorder.Events, "Warning KonnectEntityMirrorConflict")
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(userConsumer), userConsumer))
+	assert.Empty(t, userConsumer.GetKonnectID(), "objects not mirrored from Konnect should not be modified")
+
+	t.Log("objects of entities deleted from Konnect are deleted")
+	expectServices(payments)
+	expectRoutes()
+	expectConsumers(alice)
+	_, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	var routes configurationv1alpha1.KongRouteList
+	require.NoError(t, cl.List(t.Context(), &routes))
+	assert.Empty(t, routes.Items)
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-payments"}, &svc))
+
+	t.Log("mirrored objects are deleted when the annotation is removed")
+	cp.Annotations = nil
+	require.NoError(t, cl.Update(t.Context(), cp))
+	res, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	require.NoError(t, cl.List(t.Context(), &services))
+	assert.Empty(t, services.Items)
+	var consumers configurationv1.KongConsumerList
+	require.NoError(t, cl.List(t.Context(), &consumers))
+	require.Len(t, consumers.Items, 1)
+	assert.Equal(t, "cp-bob", consumers.Items[0].Name)
+}
+
+func TestKonnectEntityMirrorReconciler_NameCollisionsAndManagedServiceRoutes(t *testing.T) {
+	cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cp",
+			Namespace: "default",
+			UID:       "cp-uid",
+			Annotations: map[string]string{
+				consts.KonnectMirrorEntitiesAnnotationKey: "true",
+			},
+		},
+		Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+			KonnectConfiguration: konnectv1alpha1.KonnectConfiguration{
+				APIAuthConfigurationRef: konnectv1alpha1.KonnectAPIAuthConfigurationRef{
+					Name: "auth",
+				},
+			},
+		},
+		Status: konnectv1alpha1.KonnectGatewayControlPlaneStatus{
+			KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+				ID: "cp-id",
+			},
+		},
+	}
+	apiAuth := &konnectv1alpha1.KonnectAPIAuthConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth",
+			Namespace: "default",
+		},
+		Spec: konnectv1alpha1.KonnectAPIAuthConfigurationSpec{
+			Type:      konnectv1alpha1.KonnectAPIAuthTypeToken,
+			Token:     "kpat_xxxxxxxxxxxx",
+			ServerURL: "us.api.konghq.com",
+		},
+	}
+
+	sdkFactory := sdkmocks.NewMockSDKFactory(t)
+	sdk := sdkFactory.SDK
+	expectEntities := func(services []sdkkonnectcomp.ServiceOutput, routes []sdkkonnectcomp.Route) {
+		sdk.ServicesSDK.EXPECT().ListService(mock.Anything, mock.Anything).
+			Return(&sdkkonnectops.ListServiceResponse{
+				Object: &sdkkonnectops.ListServiceResponseBody{Data: services},
+			}, nil).Once()
+		sdk.RoutesSDK.EXPECT().ListRoute(mock.Anything, mock.Anything).
+			Return(&sdkkonnectops.ListRouteResponse{
+				Object: &sdkkonnectops.ListRouteResponseBody{Data: routes},
+			}, nil).Once()
+		sdk.ConsumersSDK.EXPECT().ListConsumer(mock.Anything, mock.Anything).
+			Return(&sdkkonnectops.ListConsumerResponse{
+				Object: &sdkkonnectops.ListConsumerResponseBody{},
+			}, nil).Once()
+	}
+	orders := sdkkonnectcomp.ServiceOutput{ID: lo.ToPtr("svc-orders"), Name: lo.ToPtr("orders")}
+	ordersUpper := sdkkonnectcomp.ServiceOutput{ID: lo.ToPtr("svc-orders-upper"), Name: lo.ToPtr("Orders")}
+	managed := sdkkonnectcomp.ServiceOutput{
+		ID:   lo.ToPtr("svc-managed"),
+		Name: lo.ToPtr("managed"),
+		Tags: []string{
+			"k8s-group:configuration.konghq.com",
+			"k8s-kind:KongService",
+			"k8s-name:managed-svc",
+			"k8s-namespace:default",
+			"k8s-uid:managed-uid",
+			"k8s-version:v1alpha1",
+		},
+	}
+	routeOfManaged := sdkkonnectcomp.Route{
+		RouteJSON: &sdkkonnectcomp.RouteJSON{
+			ID:      lo.ToPtr("route-id"),
+			Name:    lo.ToPtr("ui-route"),
+			Service: &sdkkonnectcomp.RouteJSONService{ID: lo.ToPtr("svc-managed")},
+		},
+	}
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(cp, apiAuth).
+		WithStatusSubresource(
+			&configurationv1alpha1.KongService{},
+			&configurationv1alpha1.KongRoute{},
+			&configurationv1.KongConsumer{},
+		).
+		Build()
+
+	r := NewKonnectEntityMirrorReconciler(sdkFactory, logging.DevelopmentMode, cl, time.Minute)
+	r.eventRecorder = record.NewFakeRecorder(10)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "cp"}}
+	listServiceNames := func() []string {
+		var services configurationv1alpha1.KongServiceList
+		require.NoError(t, cl.List(t.Context(), &services))
+		return lo.Map(services.Items, func(s configurationv1alpha1.KongService, _ int) string { return s.Name })
+	}
+
+	t.Log("a single service is mirrored under its name")
+	expectEntities([]sdkkonnectcomp.ServiceOutput{orders, managed}, []sdkkonnectcomp.Route{routeOfManaged})
+	_, err := r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"cp-orders"}, listServiceNames())
+
+	t.Log("routes of services managed by the operator reference their KongServices")
+	var kongRoute configurationv1alpha1.KongRoute
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-ui-route"}, &kongRoute))
+	require.NotNil(t, kongRoute.Spec.ServiceRef)
+	assert.Equal(t, "managed-svc", kongRoute.Spec.ServiceRef.NamespacedRef.Name)
+	assert.Nil(t, kongRoute.Spec.ControlPlaneRef)
+	assert.Equal(t, "svc-managed", kongRoute.Status.Konnect.ServiceID)
+
+	t.Log("services whose lowercased names collide are mirrored with their Konnect IDs appended")
+	expectEntities([]sdkkonnectcomp.ServiceOutput{orders, ordersUpper, managed}, []sdkkonnectcomp.Route{routeOfManaged})
+	_, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"cp-orders-svc-orders", "cp-orders-svc-orders-upper"}, listServiceNames())
+
+	var svc configurationv1alpha1.KongService
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "cp-orders-svc-orders-upper"}, &svc))
+	assert.Equal(t, "svc-orders-upper", svc.GetKonnectID())
+}
@@ -5,11 +5,10 @@ import (
 	"fmt"
 	"time"
 
-	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +20,7 @@ import (
 	"github.com/kong/gateway-operator/controller/konnect/server"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/internal/metrics"
 	"github.com/kong/gateway-operator/modules/manager/logging"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 
@@ -29,14 +29,21 @@ import (
 
 // KonnectAPIAuthConfigurationReconciler reconciles a KonnectAPIAuthConfiguration object.
 type KonnectAPIAuthConfigurationReconciler struct {
-	sdkFactory  sdkops.SDKFactory
-	client      client.Client
-	loggingMode logging.Mode
+	sdkFactory                  sdkops.SDKFactory
+	client                      client.Client
+	loggingMode                 logging.Mode
+	tokenExpiryWarningThreshold time.Duration
+	metricRecorder              metrics.Recorder
+	eventRecorder               record.EventRecorder
+	now                         func() time.Time
 }
 
 const (
 	// SecretTokenKey is the key used to store the token in the Secret.
 	SecretTokenKey = "token"
+	// SecretSecondaryTokenKey is the key used to store the secondary token in the Secret.
+	// The secondary token is used when the primary one is expired or rejected by Konnect.
+	SecretSecondaryTokenKey = "secondary-token"
 	// SecretCredentialLabel is the label used to identify Secrets holding
 	// KonnectAPIAuthConfiguration tokens.
 	SecretCredentialLabel = "konghq.com/credential" //nolint:gosec
@@ -50,11 +57,16 @@ func NewKonnectAPIAuthConfigurationReconciler(
 	sdkFactory sdkops.SDKFactory,
 	loggingMode logging.Mode,
 	client client.Client,
+	tokenExpiryWarningThreshold time.Duration,
+	metricRecorder metrics.Recorder,
 ) *KonnectAPIAuthConfigurationReconciler {
 	return &KonnectAPIAuthConfigurationReconciler{
-		sdkFactory:  sdkFactory,
-		loggingMode: loggingMode,
-		client:      client,
+		sdkFactory:                  sdkFactory,
+		loggingMode:                 loggingMode,
+		client:                      client,
+		tokenExpiryWarningThreshold: tokenExpiryWarningThreshold,
+		metricRecorder:              metricRecorder,
+		now:                         time.Now,
 	}
 }
 
@@ -71,6 +83,8 @@ func (r *KonnectAPIAuthConfigurationReconciler) SetupWithManager(ctx context.Con
 		return fmt.Errorf("failed to create Secret label selector predicate: %w", err)
 	}
 
+	r.eventRecorder = mgr.GetEventRecorderFor("KonnectAPIAuthConfiguration")
+
 	b := ctrl.NewControllerManagedBy(mgr).
 		For(&konnectv1alpha1.KonnectAPIAuthConfiguration{}).
 		Watches(
@@ -91,6 +105,9 @@ func (r *KonnectAPIAuthConfigurationReconciler) Reconcile(
 ) (ctrl.Result, error) {
 	var apiAuth konnectv1alpha1.KonnectAPIAuthConfiguration
 	if err := r.client.Get(ctx, req.NamespacedName, &apiAuth); err != nil {
+		if k8serrors.IsNotFound(err) {
+			r.metricRecorder.ForgetKonnectAPITokenExpiry(req.String())
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -102,6 +119,7 @@ func (r *KonnectAPIAuthConfigurationReconciler) Reconcile(
 	log.Debug(logger, "reconciling")
 	if !apiAuth.GetDeletionTimestamp().IsZero() {
 		logger.Info("resource is being deleted")
+		r.metricRecorder.ForgetKonnectAPITokenExpiry(req.String())
 		// wait for termination grace period before cleaning up
 		if apiAuth.GetDeletionTimestamp().After(time.Now()) {
 			logger.Info("resource still under grace period, requeueing")
@@ -117,7 +135,7 @@ func (r *KonnectAPIAuthConfigurationReconciler) Reconcile(
 		return ctrl.Result{}, nil
 	}
 
-	token, err := getTokenFromKonnectAPIAuthConfiguration(ctx, r.client, &apiAuth)
+	tokens, err := getKonnectAPIAuthTokens(ctx, r.client, &apiAuth)
 	if err != nil {
 		if res, errStatus := patch.StatusWithCondition(
 			ctx, r.client, &apiAuth,
@@ -135,7 +153,8 @@ func (r *KonnectAPIAuthConfigurationReconciler) Reconcile(
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to parse server URL: %w", err)
 	}
-	sdk := r.sdkFactory.NewKonnectSDK(server, sdkops.SDKToken(token))
+	r.readTokensExpiryFromKonnect(ctx, server, &tokens)
+	r.recordTokensExpiry(server, &apiAuth, tokens)
 
 	// TODO(pmalek): check if api auth config has a valid status condition
 	// If not then return an error.
@@ -143,26 +162,13 @@ func (r *KonnectAPIAuthConfigurationReconciler) Reconcile(
 	// NOTE: /organizations/me is not public in OpenAPI spec so we can use it
 	// but not using the SDK
 	// https://kongstrong.slack.com/archives/C04RXLGNB6K/p1719830395775599?thread_ts=1719406468.883729&cid=C04RXLGNB6K
-
-	// NOTE: This is needed because currently the SDK only lists the prod global API as supported:
-	// https://github.com/Kong/sdk-konnect-go/blob/999d9a987e1aa7d2e09ac11b1450f4563adf21ea/models/operations/getorganizationsme.go#L10-L12
-	respOrg, err := sdk.GetMeSDK().GetOrganizationsMe(ctx, sdkkonnectops.WithServerURL(server.URL()))
-	if err != nil ||
-		respOrg == nil ||
-		respOrg.MeOrganization == nil ||
-		respOrg.MeOrganization.ID == nil {
-
-		var errMsg string
-		if err != nil {
-			errMsg = err.Error()
-		} else {
-			errMsg = "response from Konnect is nil"
-		}
-
+	orgID, tokenInUse, err := r.getOrganizationID(ctx, server, tokens)
+	if err != nil {
 		logger.Error(err, "failed to get organization info from Konnect")
 		if cond, ok := k8sutils.GetCondition(konnectv1alpha1.KonnectEntityAPIAuthConfigurationValidConditionType, &apiAuth); !ok ||
 			cond.Status != metav1.ConditionFalse ||
 			cond.Reason != konnectv1alpha1.KonnectEntityAPIAuthConfigurationReasonInvalid ||
+			cond.Message != err.Error() ||
 			cond.ObservedGeneration != apiAuth.GetGeneration() ||
 			apiAuth.Status.OrganizationID != "" ||
 			apiAuth.Status.ServerURL != server.URL() {
@@ -175,7 +181,7 @@ func (r *KonnectAPIAuthConfigurationReconciler) Reconcile(
 				konnectv1alpha1.KonnectEntityAPIAuthConfigurationValidConditionType,
 				metav1.ConditionFalse,
 				konnectv1alpha1.KonnectEntityAPIAuthConfigurationReasonInvalid,
-				errMsg,
+				err.Error(),
 			)
 
 			_, errUpdate := patch.ApplyStatusPatchIfNotEmpty(ctx, r.client, ctrllog.FromContext(ctx), &apiAuth, old)
@@ -191,83 +197,68 @@ func (r *KonnectAPIAuthConfigurationReconciler) Reconcile(
 		return ctrl.Result{}, nil
 	}
 
-	// Update the status only if it would change to prevent unnecessary updates.
 	condMessage := "Token is valid"
-	if apiAuth.Spec.Type == konnectv1alpha1.KonnectAPIAuthTypeSecretRef {
-		nn := types.NamespacedName{
-			Namespace: apiAuth.Spec.SecretRef.Namespace,
-			Name:      apiAuth.Spec.SecretRef.Name,
+	if tokens.secretNN != nil {
+		condMessage = fmt.Sprintf("Token from Secret %s is valid", tokens.secretNN)
+		if tokenInUse.name == konnectAPITokenSecondary {
+			condMessage = fmt.Sprintf("Secondary token from Secret %s is valid", tokens.secretNN)
 		}
-		if nn.Namespace == "" {
-			nn.Namespace = apiAuth.Namespace
-		}
-		condMessage = fmt.Sprintf("Token from Secret %s is valid", nn)
 	}
-	if cond, ok := k8sutils.GetCondition(konnectv1alpha1.KonnectEntityAPIAuthConfigurationValidConditionType, &apiAuth); !ok ||
-		cond.Status != metav1.ConditionTrue ||
-		cond.Message != condMessage ||
-		cond.Reason != konnectv1alpha1.KonnectEntityAPIAuthConfigurationReasonValid ||
-		cond.ObservedGeneration != apiAuth.GetGeneration() ||
-		apiAuth.Status.OrganizationID != *respOrg.MeOrganization.ID ||
-		apiAuth.Status.ServerURL != server.URL() {
-
-		old := apiAuth.DeepCopy()
 
-		apiAuth.Status.OrganizationID = *respOrg.MeOrganization.ID
-		apiAuth.Status.ServerURL = server.URL()
-
-		_ = patch.SetStatusWithConditionIfDifferent(&apiAuth,
-			konnectv1alpha1.KonnectEntityAPIAuthConfigurationValidConditionType,
-			metav1.ConditionTrue,
-			konnectv1alpha1.KonnectEntityAPIAuthConfigurationReasonValid,
-			condMessage,
-		)
+	old := apiAuth.DeepCopy()
+	apiAuth.Status.OrganizationID = orgID
+	apiAuth.Status.ServerURL = server.URL()
+	_ = patch.SetStatusWithConditionIfDifferent(&apiAuth,
+		konnectv1alpha1.KonnectEntityAPIAuthConfigurationValidConditionType,
+		metav1.ConditionTrue,
+		konnectv1alpha1.KonnectEntityAPIAuthConfigurationReasonValid,
+		condMessage,
+	)
+	requeueAfter, expiring, failover := r.setTokenStatusConditions(&apiAuth, tokens, tokenInUse)
 
-		_, errUpdate := patch.ApplyStatusPatchIfNotEmpty(ctx, r.client, ctrllog.FromContext(ctx), &apiAuth, old)
-		if errUpdate != nil {
-			if k8serrors.IsConflict(errUpdate) {
-				return ctrl.Result{Requeue: true}, nil
-			}
-			return ctrl.Result{}, errUpdate
+	// Update the status only if it would change to prevent unnecessary updates.
+	_, errUpdate := patch.ApplyStatusPatchIfNotEmpty(ctx, r.client, ctrllog.FromContext(ctx), &apiAuth, old)
+	if errUpdate != nil {
+		if k8serrors.IsConflict(errUpdate) {
+			return ctrl.Result{Requeue: true}, nil
 		}
+		return ctrl.Result{}, errUpdate
+	}
 
-		return ctrl.Result{}, nil
+	if failover {
+		r.eventRecorder.Eventf(&apiAuth, corev1.EventTypeWarning, KonnectAPITokenFailoverEventReason,
+			"Primary token from Secret %s is expired or invalid, failed over to the secondary token", tokens.secretNN,
+		)
+	}
+	if expiring {
+		cond, _ := k8sutils.GetCondition(KonnectAPIAuthConfigurationTokenExpiringConditionType, &apiAuth)
+		r.eventRecorder.Event(&apiAuth, corev1.EventTypeWarning, KonnectAPITokenExpiringEventReason, cond.Message)
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // getTokenFromKonnectAPIAuthConfiguration returns the token from the secret reference or the token field.
+// The secondary token from the secret reference is returned when the operator
+// failed over to it, as reported by the SecondaryTokenInUse condition, or when
+// the primary token is rejected by Konnect (see primaryTokenRejected) as it might
+// have been revoked since the KonnectAPIAuthConfiguration was reconciled.
 func getTokenFromKonnectAPIAuthConfiguration(
-	ctx context.Context, cl client.Client, apiAuth *konnectv1alpha1.KonnectAPIAuthConfiguration,
+	ctx context.Context,
+	cl client.Client,
+	sdkFactory sdkops.SDKFactory,
+	apiAuth *konnectv1alpha1.KonnectAPIAuthConfiguration,
 ) (string, error) {
-	switch apiAuth.Spec.Type {
-	case konnectv1alpha1.KonnectAPIAuthTypeToken:
-		return apiAuth.Spec.Token, nil
-	case konnectv1alpha1.KonnectAPIAuthTypeSecretRef:
-		nn := types.NamespacedName{
-			Namespace: apiAuth.Spec.SecretRef.Namespace,
-			Name:      apiAuth.Spec.SecretRef.Name,
-		}
-		if nn.Namespace == "" {
-			nn.Namespace = apiAuth.Namespace
-		}
-
-		var secret corev1.Secret
-		if err := cl.Get(ctx, nn, &secret); err != nil {
-			return "", fmt.Errorf("failed to get Secret %s: %w", nn, err)
-		}
-		if secret.Labels == nil || secret.Labels[SecretCredentialLabel] != SecretCredentialLabelValueKonnect {
-			return "", fmt.Errorf("secret %s does not have label %s: %s", nn, SecretCredentialLabel, SecretCredentialLabelValueKonnect)
-		}
-		if secret.Data == nil {
-			return "", fmt.Errorf("secret %s has no data", nn)
-		}
-		if _, ok := secret.Data[SecretTokenKey]; !ok {
-			return "", fmt.Errorf("secret %s does not have key %s", nn, SecretTokenKey)
-		}
-		return string(secret.Data[SecretTokenKey]), nil
+	tokens, err := getKonnectAPIAuthTokens(ctx, cl, apiAuth)
+	if err != nil {
+		return "", err
 	}
-
-	return "", fmt.Errorf("unknown KonnectAPIAuthType: %s", apiAuth.Spec.Type)
+	if tokens.secondary == nil {
+		return tokens.primary.value, nil
+	}
+	if k8sutils.HasConditionTrue(KonnectAPIAuthConfigurationSecondaryTokenInUseConditionType, apiAuth) ||
+		primaryTokenRejected(ctx, sdkFactory, apiAuth.Spec.ServerURL, tokens.primary, time.Now()) {
+		return tokens.secondary.value, nil
+	}
+	return tokens.primary.value, nil
 }
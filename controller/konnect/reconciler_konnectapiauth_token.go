@@ -0,0 +1,463 @@
+package konnect
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	sdkkonnecterrs "github.com/Kong/sdk-konnect-go/models/sdkerrors"
+	"golang.org/x/sync/singleflight"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/konnect/server"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+const (
+	// KonnectAPIAuthConfigurationTokenExpiringConditionType is the type of the
+	// KonnectAPIAuthConfiguration condition which reports whether the Konnect API
+	// token in use expires within the configured warning threshold.
+	// It is set only when the token's expiry is known, either read from Konnect for
+	// system account access tokens identified with the konnect.konghq.com/system-account-token
+	// annotation or declared with the konnect.konghq.com/token-expires-at annotation
+	// (or their konnect.konghq.com/secondary- prefixed counterparts).
+	KonnectAPIAuthConfigurationTokenExpiringConditionType kcfgconsts.ConditionType = "TokenExpiring"
+
+	// KonnectAPIAuthConfigurationReasonTokenExpiring is the reason used with the
+	// TokenExpiring condition when the token expires within the warning threshold.
+	KonnectAPIAuthConfigurationReasonTokenExpiring kcfgconsts.ConditionReason = "Expiring"
+	// KonnectAPIAuthConfigurationReasonTokenExpired is the reason used with the
+	// TokenExpiring condition when the token has expired.
+	KonnectAPIAuthConfigurationReasonTokenExpired kcfgconsts.ConditionReason = "Expired"
+	// KonnectAPIAuthConfigurationReasonTokenNotExpiring is the reason used with the
+	// TokenExpiring condition when the token does not expire within the warning threshold.
+	KonnectAPIAuthConfigurationReasonTokenNotExpiring kcfgconsts.ConditionReason = "NotExpiring"
+
+	// KonnectAPIAuthConfigurationSecondaryTokenInUseConditionType is the type of the
+	// KonnectAPIAuthConfiguration condition which reports whether the operator failed
+	// over to the secondary token stored in the referenced Secret.
+	// It is set only when the Secret holds a secondary token.
+	KonnectAPIAuthConfigurationSecondaryTokenInUseConditionType kcfgconsts.ConditionType = "SecondaryTokenInUse"
+
+	// KonnectAPIAuthConfigurationReasonPrimaryTokenValid is the reason used with the
+	// SecondaryTokenInUse condition when the primary token is used.
+	KonnectAPIAuthConfigurationReasonPrimaryTokenValid kcfgconsts.ConditionReason = "PrimaryTokenValid"
+	// KonnectAPIAuthConfigurationReasonPrimaryTokenInvalid is the reason used with the
+	// SecondaryTokenInUse condition when the primary token is expired or rejected by Konnect.
+	KonnectAPIAuthConfigurationReasonPrimaryTokenInvalid kcfgconsts.ConditionReason = "PrimaryTokenInvalid"
+)
+
+const (
+	// KonnectAPITokenExpiringEventReason is the reason of the event emitted when
+	// the Konnect API token in use expires within the warning threshold.
+	KonnectAPITokenExpiringEventReason = "KonnectAPITokenExpiring"
+	// KonnectAPITokenFailoverEventReason is the reason of the event emitted when
+	// the operator fails over to the secondary Konnect API token.
+	KonnectAPITokenFailoverEventReason = "KonnectAPITokenFailover"
+)
+
+// konnectAPIToken is one of the Konnect API tokens of a KonnectAPIAuthConfiguration.
+type konnectAPIToken struct {
+	// name is either "primary" or "secondary".
+	name  string
+	value string
+	// expiresAt is the expiry of the token, nil when not known.
+	expiresAt *time.Time
+	// systemAccountToken identifies the token in Konnect when it's a system
+	// account access token, nil when not known.
+	systemAccountToken *systemAccountTokenRef
+}
+
+// systemAccountTokenRef identifies a system account access token in Konnect.
+type systemAccountTokenRef struct {
+	accountID string
+	tokenID   string
+}
+
+const (
+	konnectAPITokenPrimary   = "primary"
+	konnectAPITokenSecondary = "secondary"
+)
+
+// konnectAPIAuthTokens holds the Konnect API tokens of a KonnectAPIAuthConfiguration.
+type konnectAPIAuthTokens struct {
+	primary konnectAPIToken
+	// secondary is set only when the referenced Secret holds a secondary token.
+	secondary *konnectAPIToken
+	// secretNN is the namespaced name of the referenced Secret, if any.
+	secretNN *types.NamespacedName
+}
+
+// candidates returns the tokens in the order in which they should be tried.
+// The primary token is tried first unless its declared expiry has passed.
+func (t konnectAPIAuthTokens) candidates(now time.Time) []konnectAPIToken {
+	if t.secondary == nil {
+		return []konnectAPIToken{t.primary}
+	}
+	if t.primary.expiresAt != nil && !now.Before(*t.primary.expiresAt) {
+		return []konnectAPIToken{*t.secondary, t.primary}
+	}
+	return []konnectAPIToken{t.primary, *t.secondary}
+}
+
+// getKonnectAPIAuthTokens returns the Konnect API tokens configured in the
+// KonnectAPIAuthConfiguration, either inline or in the referenced Secret.
+func getKonnectAPIAuthTokens(
+	ctx context.Context, cl client.Client, apiAuth *konnectv1alpha1.KonnectAPIAuthConfiguration,
+) (konnectAPIAuthTokens, error) {
+	switch apiAuth.Spec.Type {
+	case konnectv1alpha1.KonnectAPIAuthTypeToken:
+		expiresAt, err := tokenExpiresAtFromAnnotation(apiAuth, consts.KonnectAPITokenExpiresAtAnnotationKey)
+		if err != nil {
+			return konnectAPIAuthTokens{}, err
+		}
+		systemAccountToken, err := systemAccountTokenFromAnnotation(apiAuth, consts.KonnectAPISystemAccountTokenAnnotationKey)
+		if err != nil {
+			return konnectAPIAuthTokens{}, err
+		}
+		return konnectAPIAuthTokens{
+			primary: konnectAPIToken{
+				name:               konnectAPITokenPrimary,
+				value:              apiAuth.Spec.Token,
+				expiresAt:          expiresAt,
+				systemAccountToken: systemAccountToken,
+			},
+		}, nil
+
+	case konnectv1alpha1.KonnectAPIAuthTypeSecretRef:
+		nn := types.NamespacedName{
+			Namespace: apiAuth.Spec.SecretRef.Namespace,
+			Name:      apiAuth.Spec.SecretRef.Name,
+		}
+		if nn.Namespace == "" {
+			nn.Namespace = apiAuth.Namespace
+		}
+
+		var secret corev1.Secret
+		if err := cl.Get(ctx, nn, &secret); err != nil {
+			return konnectAPIAuthTokens{}, fmt.Errorf("failed to get Secret %s: %w", nn, err)
+		}
+		if secret.Labels == nil || secret.Labels[SecretCredentialLabel] != SecretCredentialLabelValueKonnect {
+			return konnectAPIAuthTokens{}, fmt.Errorf("secret %s does not have label %s: %s", nn, SecretCredentialLabel, SecretCredentialLabelValueKonnect)
+		}
+		if secret.Data == nil {
+			return konnectAPIAuthTokens{}, fmt.Errorf("secret %s has no data", nn)
+		}
+		if _, ok := secret.Data[SecretTokenKey]; !ok {
+			return konnectAPIAuthTokens{}, fmt.Errorf("secret %s does not have key %s", nn, SecretTokenKey)
+		}
+		expiresAt, err := tokenExpiresAtFromAnnotation(&secret, consts.KonnectAPITokenExpiresAtAnnotationKey)
+		if err != nil {
+			return konnectAPIAuthTokens{}, err
+		}
+		systemAccountToken, err := systemAccountTokenFromAnnotation(&secret, consts.KonnectAPISystemAccountTokenAnnotationKey)
+		if err != nil {
+			return konnectAPIAuthTokens{}, err
+		}
+		tokens := konnectAPIAuthTokens{
+			primary: konnectAPIToken{
+				name:               konnectAPITokenPrimary,
+				value:              string(secret.Data[SecretTokenKey]),
+				expiresAt:          expiresAt,
+				systemAccountToken: systemAccountToken,
+			},
+			secretNN: &nn,
+		}
+		if secondary, ok := secret.Data[SecretSecondaryTokenKey]; ok && len(secondary) > 0 {
+			expiresAt, err := tokenExpiresAtFromAnnotation(&secret, consts.KonnectAPISecondaryTokenExpiresAtAnnotationKey)
+			if err != nil {
+				return konnectAPIAuthTokens{}, err
+			}
+			systemAccountToken, err := systemAccountTokenFromAnnotation(&secret, consts.KonnectAPISecondarySystemAccountTokenAnnotationKey)
+			if err != nil {
+				return konnectAPIAuthTokens{}, err
+			}
+			tokens.secondary = &konnectAPIToken{
+				name:               konnectAPITokenSecondary,
+				value:              string(secondary),
+				expiresAt:          expiresAt,
+				systemAccountToken: systemAccountToken,
+			}
+		}
+		return tokens, nil
+	}
+
+	return konnectAPIAuthTokens{}, fmt.Errorf("unknown KonnectAPIAuthType: %s", apiAuth.Spec.Type)
+}
+
+func tokenExpiresAtFromAnnotation(obj client.Object, key string) (*time.Time, error) {
+	v, ok := obj.GetAnnotations()[key]
+	if !ok {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation value %q on %s: %w", key, v, client.ObjectKeyFromObject(obj), err)
+	}
+	return &t, nil
+}
+
+func systemAccountTokenFromAnnotation(obj client.Object, key string) (*systemAccountTokenRef, error) {
+	v, ok := obj.GetAnnotations()[key]
+	if !ok {
+		return nil, nil
+	}
+	accountID, tokenID, ok := strings.Cut(v, "/")
+	if !ok || accountID == "" || tokenID == "" || strings.Contains(tokenID, "/") {
+		return nil, fmt.Errorf("invalid %s annotation value %q on %s: expected <system account ID>/<token ID>", key, v, client.ObjectKeyFromObject(obj))
+	}
+	return &systemAccountTokenRef{accountID: accountID, tokenID: tokenID}, nil
+}
+
+// readTokensExpiryFromKonnect reads the expiry of the system account access
+// tokens from Konnect, overriding their declared expiry. Tokens whose expiry
+// can't be read, e.g. because they lack the permission to read their system
+// account's tokens, keep their declared expiry.
+func (r *KonnectAPIAuthConfigurationReconciler) readTokensExpiryFromKonnect(
+	ctx context.Context, server server.Server, tokens *konnectAPIAuthTokens,
+) {
+	logger := ctrllog.FromContext(ctx)
+	for _, token := range []*konnectAPIToken{&tokens.primary, tokens.secondary} {
+		if token == nil || token.systemAccountToken == nil {
+			continue
+		}
+		sdk := r.sdkFactory.NewKonnectSDK(server, sdkops.SDKToken(token.value))
+		resp, err := sdk.GetSystemAccountsAccessTokensSDK().GetSystemAccountsIDAccessTokensID(ctx,
+			token.systemAccountToken.accountID, token.systemAccountToken.tokenID,
+		)
+		if err != nil {
+			logger.Error(err, "failed to read the expiry of the token from Konnect", "token", token.name)
+			continue
+		}
+		if resp == nil || resp.SystemAccountAccessToken == nil || resp.SystemAccountAccessToken.ExpiresAt == nil {
+			continue
+		}
+		expiresAt := *resp.SystemAccountAccessToken.ExpiresAt
+		token.expiresAt = &expiresAt
+	}
+}
+
+// getOrganizationID returns the ID of the Konnect organization using the first
+// of the candidate tokens which is accepted by Konnect, together with that token.
+func (r *KonnectAPIAuthConfigurationReconciler) getOrganizationID(
+	ctx context.Context, server server.Server, tokens konnectAPIAuthTokens,
+) (string, konnectAPIToken, error) {
+	var (
+		candidates = tokens.candidates(r.now())
+		errs       = make([]error, 0, len(candidates))
+	)
+	for _, token := range candidates {
+		sdk := r.sdkFactory.NewKonnectSDK(server, sdkops.SDKToken(token.value))
+
+		// NOTE: This is needed because currently the SDK only lists the prod global API as supported:
+		// https://github.com/Kong/sdk-konnect-go/blob/999d9a987e1aa7d2e09ac11b1450f4563adf21ea/models/operations/getorganizationsme.go#L10-L12
+		respOrg, err := sdk.GetMeSDK().GetOrganizationsMe(ctx, sdkkonnectops.WithServerURL(server.URL()))
+		if err == nil && respOrg != nil && respOrg.MeOrganization != nil && respOrg.MeOrganization.ID != nil {
+			return *respOrg.MeOrganization.ID, token, nil
+		}
+		if err == nil {
+			err = errors.New("response from Konnect is nil")
+		}
+		if len(candidates) > 1 {
+			err = fmt.Errorf("%s token: %w", token.name, err)
+		}
+		errs = append(errs, err)
+	}
+	return "", konnectAPIToken{}, errors.Join(errs...)
+}
+
+// konnectAPITokenCheckPeriod is how long the result of checking whether Konnect
+// rejects a primary token is reused for before the token is checked again.
+const konnectAPITokenCheckPeriod = time.Minute
+
+// konnectAPITokenChecks caches the results of the primary token checks so that
+// Konnect is not queried on each reconciliation of the entities using the tokens.
+var konnectAPITokenChecks = &konnectAPITokenCheckCache{
+	checks: map[string]konnectAPITokenCheck{},
+}
+
+type konnectAPITokenCheck struct {
+	rejected  bool
+	checkedAt time.Time
+}
+
+// konnectAPITokenCheckCache holds the results of the primary token checks keyed
+// by the hash of the server URL and the token. Concurrent checks of the same
+// token are deduplicated so that Konnect is queried once per token, without
+// blocking the checks of other tokens.
+type konnectAPITokenCheckCache struct {
+	group  singleflight.Group
+	lock   sync.Mutex
+	checks map[string]konnectAPITokenCheck
+}
+
+// get returns the result of the check of the token if it's still fresh.
+func (c *konnectAPITokenCheckCache) get(key string, now time.Time) (rejected bool, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	check, ok := c.checks[key]
+	if !ok || now.Sub(check.checkedAt) >= konnectAPITokenCheckPeriod {
+		return false, false
+	}
+	return check.rejected, true
+}
+
+// set stores the result of the check of the token, dropping the stale ones.
+func (c *konnectAPITokenCheckCache) set(key string, rejected bool, now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for k, check := range c.checks {
+		if now.Sub(check.checkedAt) >= konnectAPITokenCheckPeriod {
+			delete(c.checks, k)
+		}
+	}
+	c.checks[key] = konnectAPITokenCheck{rejected: rejected, checkedAt: now}
+}
+
+// primaryTokenRejected returns true when the primary token's declared expiry has
+// passed or when Konnect rejects it as unauthorized. Other errors (e.g. Konnect
+// being unavailable) don't make the token considered rejected.
+func primaryTokenRejected(
+	ctx context.Context,
+	sdkFactory sdkops.SDKFactory,
+	serverURL string,
+	primary konnectAPIToken,
+	now time.Time,
+) bool {
+	if primary.expiresAt != nil && !now.Before(*primary.expiresAt) {
+		return true
+	}
+	if sdkFactory == nil {
+		return false
+	}
+
+	key := fmt.Sprintf("%x", sha256.Sum256([]byte(serverURL+"/"+primary.value)))
+	c := konnectAPITokenChecks
+	if rejected, ok := c.get(key, now); ok {
+		return rejected
+	}
+
+	rejected, _, _ := c.group.Do(key, func() (any, error) {
+		// The token might have been checked while waiting for the previous check.
+		if rejected, ok := c.get(key, now); ok {
+			return rejected, nil
+		}
+		server, err := server.NewServer[konnectv1alpha1.KonnectAPIAuthConfiguration](serverURL)
+		if err != nil {
+			return false, nil
+		}
+		sdk := sdkFactory.NewKonnectSDK(server, sdkops.SDKToken(primary.value))
+		_, err = sdk.GetMeSDK().GetOrganizationsMe(ctx, sdkkonnectops.WithServerURL(server.URL()))
+		rejected := errIsUnauthorized(err)
+		c.set(key, rejected, now)
+		return rejected, nil
+	})
+	return rejected.(bool)
+}
+
+// errIsUnauthorized returns true when Konnect rejected the request's token.
+func errIsUnauthorized(err error) bool {
+	var (
+		errUnauthorized *sdkkonnecterrs.UnauthorizedError
+		errSDK          *sdkkonnecterrs.SDKError
+	)
+	return errors.As(err, &errUnauthorized) ||
+		(errors.As(err, &errSDK) && errSDK.StatusCode == http.StatusUnauthorized)
+}
+
+// setTokenStatusConditions sets the SecondaryTokenInUse and TokenExpiring
+// conditions of the KonnectAPIAuthConfiguration for the token in use.
+// It returns the duration after which the TokenExpiring condition has to be
+// re-evaluated (0 when it doesn't) and whether the token started to be reported
+// as expiring or the operator failed over to the secondary token.
+func (r *KonnectAPIAuthConfigurationReconciler) setTokenStatusConditions(
+	apiAuth *konnectv1alpha1.KonnectAPIAuthConfiguration,
+	tokens konnectAPIAuthTokens,
+	inUse konnectAPIToken,
+) (requeueAfter time.Duration, expiring bool, failover bool) {
+	if tokens.secondary == nil {
+		meta.RemoveStatusCondition(&apiAuth.Status.Conditions, string(KonnectAPIAuthConfigurationSecondaryTokenInUseConditionType))
+	} else if inUse.name == konnectAPITokenSecondary {
+		failover = !k8sutils.HasConditionTrue(KonnectAPIAuthConfigurationSecondaryTokenInUseConditionType, apiAuth)
+		_ = patch.SetStatusWithConditionIfDifferent(apiAuth,
+			KonnectAPIAuthConfigurationSecondaryTokenInUseConditionType,
+			metav1.ConditionTrue,
+			KonnectAPIAuthConfigurationReasonPrimaryTokenInvalid,
+			fmt.Sprintf("Primary token from Secret %s is expired or invalid, secondary token is used", tokens.secretNN),
+		)
+	} else {
+		_ = patch.SetStatusWithConditionIfDifferent(apiAuth,
+			KonnectAPIAuthConfigurationSecondaryTokenInUseConditionType,
+			metav1.ConditionFalse,
+			KonnectAPIAuthConfigurationReasonPrimaryTokenValid,
+			fmt.Sprintf("Primary token from Secret %s is used", tokens.secretNN),
+		)
+	}
+
+	if inUse.expiresAt == nil {
+		meta.RemoveStatusCondition(&apiAuth.Status.Conditions, string(KonnectAPIAuthConfigurationTokenExpiringConditionType))
+		return 0, false, failover
+	}
+
+	var (
+		now        = r.now()
+		expiresAt  = *inUse.expiresAt
+		warnAt     = expiresAt.Add(-r.tokenExpiryWarningThreshold)
+		status     metav1.ConditionStatus
+		reason     kcfgconsts.ConditionReason
+		msgExpires = expiresAt.Format(time.RFC3339)
+		msg        string
+	)
+	switch {
+	case !now.Before(expiresAt):
+		status, reason = metav1.ConditionTrue, KonnectAPIAuthConfigurationReasonTokenExpired
+		msg = fmt.Sprintf("The %s token expired at %s", inUse.name, msgExpires)
+	case !now.Before(warnAt):
+		status, reason = metav1.ConditionTrue, KonnectAPIAuthConfigurationReasonTokenExpiring
+		msg = fmt.Sprintf("The %s token expires at %s", inUse.name, msgExpires)
+		requeueAfter = expiresAt.Sub(now)
+	default:
+		status, reason = metav1.ConditionFalse, KonnectAPIAuthConfigurationReasonTokenNotExpiring
+		msg = fmt.Sprintf("The %s token expires at %s", inUse.name, msgExpires)
+		requeueAfter = warnAt.Sub(now)
+	}
+	expiring = status == metav1.ConditionTrue &&
+		!k8sutils.HasConditionTrue(KonnectAPIAuthConfigurationTokenExpiringConditionType, apiAuth)
+	_ = patch.SetStatusWithConditionIfDifferent(apiAuth,
+		KonnectAPIAuthConfigurationTokenExpiringConditionType, status, reason, msg,
+	)
+	return requeueAfter, expiring, failover
+}
+
+// recordTokensExpiry records the known expiry of the KonnectAPIAuthConfiguration's tokens.
+func (r *KonnectAPIAuthConfigurationReconciler) recordTokensExpiry(
+	server server.Server,
+	apiAuth *konnectv1alpha1.KonnectAPIAuthConfiguration,
+	tokens konnectAPIAuthTokens,
+) {
+	nn := client.ObjectKeyFromObject(apiAuth).String()
+	r.metricRecorder.ForgetKonnectAPITokenExpiry(nn)
+	for _, token := range []*konnectAPIToken{&tokens.primary, tokens.secondary} {
+		if token == nil || token.expiresAt == nil {
+			continue
+		}
+		r.metricRecorder.RecordKonnectAPITokenExpiry(server.URL(), nn, token.name, *token.expiresAt)
+	}
+}
@@ -0,0 +1,434 @@
+package konnect
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	sdkkonnecterrs "github.com/Kong/sdk-konnect-go/models/sdkerrors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+	"github.com/kong/gateway-operator/controller/konnect/server"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+// tokenSDKFactory returns a different SDK for each token.
+type tokenSDKFactory map[sdkops.SDKToken]*sdkmocks.MockSDKWrapper
+
+func (f tokenSDKFactory) NewKonnectSDK(_ server.Server, token sdkops.SDKToken) sdkops.SDKWrapper {
+	return *f[token]
+}
+
+func expectOrganizationsMe(sdk *sdkmocks.MockSDKWrapper, valid bool) {
+	call := sdk.MeSDK.EXPECT().GetOrganizationsMe(mock.Anything, mock.Anything)
+	if valid {
+		call.Return(&sdkkonnectops.GetOrganizationsMeResponse{
+			MeOrganization: &sdkkonnectcomp.MeOrganization{ID: lo.ToPtr("org-id")},
+		}, nil)
+		return
+	}
+	call.Return(nil, &sdkkonnecterrs.UnauthorizedError{
+		Status: 401,
+		Title:  "Unauthenticated",
+		Detail: "A valid token is required",
+	})
+}
+
+func TestKonnectAPIAuthConfigurationReconciler_SecondaryTokenFailover(t *testing.T) {
+	apiAuth := &konnectv1alpha1.KonnectAPIAuthConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth",
+			Namespace: "default",
+		},
+		Spec: konnectv1alpha1.KonnectAPIAuthConfigurationSpec{
+			Type:      konnectv1alpha1.KonnectAPIAuthTypeSecretRef,
+			SecretRef: &corev1.SecretReference{Name: "token"},
+			ServerURL: "us.api.konghq.com",
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "token",
+			Namespace: "default",
+			Labels: map[string]string{
+				SecretCredentialLabel: SecretCredentialLabelValueKonnect,
+			},
+		},
+		Data: map[string][]byte{
+			SecretTokenKey:          []byte("kpat_primary"),
+			SecretSecondaryTokenKey: []byte("kpat_secondary"),
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		primaryExpiresAt   string
+		primaryValid       *bool
+		expectedToken      string
+		expectedMessage    string
+		expectedSecondary  metav1.ConditionStatus
+		expectedEventCount int
+	}{
+		{
+			name:              "primary token is used when valid",
+			primaryValid:      lo.ToPtr(true),
+			expectedToken:     "kpat_primary",
+			expectedMessage:   "Token from Secret default/token is valid",
+			expectedSecondary: metav1.ConditionFalse,
+		},
+		{
+			name:               "secondary token is used when the primary one is rejected",
+			primaryValid:       lo.ToPtr(false),
+			expectedToken:      "kpat_secondary",
+			expectedMessage:    "Secondary token from Secret default/token is valid",
+			expectedSecondary:  metav1.ConditionTrue,
+			expectedEventCount: 1,
+		},
+		{
+			name:             "secondary token is used without checking the primary one when it has expired",
+			primaryExpiresAt: "2025-01-01T00:00:00Z",
+			// The primary token is not checked.
+			primaryValid:       nil,
+			expectedToken:      "kpat_secondary",
+			expectedMessage:    "Secondary token from Secret default/token is valid",
+			expectedSecondary:  metav1.ConditionTrue,
+			expectedEventCount: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			secret := secret.DeepCopy()
+			if tc.primaryExpiresAt != "" {
+				secret.Annotations = map[string]string{
+					consts.KonnectAPITokenExpiresAtAnnotationKey: tc.primaryExpiresAt,
+				}
+			}
+			apiAuth := apiAuth.DeepCopy()
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(apiAuth, secret).
+				WithStatusSubresource(apiAuth).
+				Build()
+
+			primary, secondary := sdkmocks.NewMockSDKWrapperWithT(t), sdkmocks.NewMockSDKWrapperWithT(t)
+			if tc.primaryValid != nil {
+				expectOrganizationsMe(primary, *tc.primaryValid)
+			}
+			if tc.primaryValid == nil || !*tc.primaryValid {
+				expectOrganizationsMe(secondary, true)
+			}
+			factory := tokenSDKFactory{"kpat_primary": primary, "kpat_secondary": secondary}
+
+			eventRecorder := record.NewFakeRecorder(10)
+			r := NewKonnectAPIAuthConfigurationReconciler(
+				factory, logging.DevelopmentMode, cl, consts.DefaultKonnectAPITokenExpiryWarningThreshold, &metrics.MockRecorder{},
+			)
+			r.eventRecorder = eventRecorder
+			r.now = func() time.Time { return time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC) }
+
+			_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(apiAuth)})
+			require.NoError(t, err)
+
+			require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(apiAuth), apiAuth))
+			assert.Equal(t, "org-id", apiAuth.Status.OrganizationID)
+			valid, ok := k8sutils.GetCondition(konnectv1alpha1.KonnectEntityAPIAuthConfigurationValidConditionType, apiAuth)
+			require.True(t, ok)
+			assert.Equal(t, metav1.ConditionTrue, valid.Status)
+			assert.Equal(t, tc.expectedMessage, valid.Message)
+			secondaryInUse, ok := k8sutils.GetCondition(KonnectAPIAuthConfigurationSecondaryTokenInUseConditionType, apiAuth)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedSecondary, secondaryInUse.Status)
+			require.Len(t, eventRecorder.Events, tc.expectedEventCount)
+			if tc.expectedEventCount > 0 {
+				assert.Contains(t, <-eventRecorder.Events, "Warning KonnectAPITokenFailover")
+			}
+
+			token, err := getTokenFromKonnectAPIAuthConfiguration(t.Context(), cl, factory, apiAuth)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedToken, token)
+		})
+	}
+}
+
+func TestKonnectAPIAuthConfigurationReconciler_TokenExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name             string
+		expiresAt        string
+		expectedStatus   metav1.ConditionStatus
+		expectedReason   string
+		expectedRequeue  time.Duration
+		expectedWarnings int
+	}{
+		{
+			name:            "token does not expire within the threshold",
+			expiresAt:       "2025-01-31T00:00:00Z",
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  string(KonnectAPIAuthConfigurationReasonTokenNotExpiring),
+			expectedRequeue: 23 * 24 * time.Hour,
+		},
+		{
+			name:             "token expires within the threshold",
+			expiresAt:        "2025-01-03T00:00:00Z",
+			expectedStatus:   metav1.ConditionTrue,
+			expectedReason:   string(KonnectAPIAuthConfigurationReasonTokenExpiring),
+			expectedRequeue:  2 * 24 * time.Hour,
+			expectedWarnings: 1,
+		},
+		{
+			name:             "token has expired",
+			expiresAt:        "2024-12-31T00:00:00Z",
+			expectedStatus:   metav1.ConditionTrue,
+			expectedReason:   string(KonnectAPIAuthConfigurationReasonTokenExpired),
+			expectedWarnings: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiAuth := &konnectv1alpha1.KonnectAPIAuthConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "auth",
+					Namespace: "default",
+					Annotations: map[string]string{
+						consts.KonnectAPITokenExpiresAtAnnotationKey: tc.expiresAt,
+					},
+				},
+				Spec: konnectv1alpha1.KonnectAPIAuthConfigurationSpec{
+					Type:      konnectv1alpha1.KonnectAPIAuthTypeToken,
+					Token:     "kpat_primary",
+					ServerURL: "us.api.konghq.com",
+				},
+			}
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(apiAuth).
+				WithStatusSubresource(apiAuth).
+				Build()
+
+			sdkFactory := sdkmocks.NewMockSDKFactory(t)
+			expectOrganizationsMe(sdkFactory.SDK, true)
+
+			eventRecorder := record.NewFakeRecorder(10)
+			r := NewKonnectAPIAuthConfigurationReconciler(
+				sdkFactory, logging.DevelopmentMode, cl, consts.DefaultKonnectAPITokenExpiryWarningThreshold, &metrics.MockRecorder{},
+			)
+			r.eventRecorder = eventRecorder
+			r.now = func() time.Time { return now }
+
+			res, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(apiAuth)})
+			require.NoError(t, err)
+			assert.Equal(t, ctrl.Result{RequeueAfter: tc.expectedRequeue}, res)
+
+			require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(apiAuth), apiAuth))
+			cond, ok := k8sutils.GetCondition(KonnectAPIAuthConfigurationTokenExpiringConditionType, apiAuth)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedStatus, cond.Status)
+			assert.Equal(t, tc.expectedReason, cond.Reason)
+			assert.Contains(t, cond.Message, tc.expiresAt)
+			_, ok = k8sutils.GetCondition(KonnectAPIAuthConfigurationSecondaryTokenInUseConditionType, apiAuth)
+			assert.False(t, ok, "SecondaryTokenInUse condition should not be set without a secondary token")
+			require.Len(t, eventRecorder.Events, tc.expectedWarnings)
+			if tc.expectedWarnings > 0 {
+				assert.Contains(t, <-eventRecorder.Events, "Warning KonnectAPITokenExpiring")
+			}
+
+			t.Log("the expiry is reported only once")
+			_, err = r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(apiAuth)})
+			require.NoError(t, err)
+			assert.Empty(t, eventRecorder.Events)
+		})
+	}
+}
+
+func TestGetTokenFromKonnectAPIAuthConfiguration_PrimaryTokenRejected(t *testing.T) {
+	testCases := []struct {
+		name string
+		// primaryToken differs between test cases as the results of the checks are cached.
+		primaryToken  string
+		primaryErr    error
+		expectedToken string
+	}{
+		{
+			name:         "secondary token is used when the primary one is rejected",
+			primaryToken: "kpat_primary_revoked",
+			primaryErr: &sdkkonnecterrs.UnauthorizedError{
+				Status: 401,
+				Title:  "Unauthenticated",
+			},
+			expectedToken: "kpat_secondary",
+		},
+		{
+			name:          "primary token is used when Konnect is unavailable",
+			primaryToken:  "kpat_primary_unavailable",
+			primaryErr:    sdkkonnecterrs.NewSDKError("unavailable", 503, "", nil),
+			expectedToken: "kpat_primary_unavailable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiAuth := &konnectv1alpha1.KonnectAPIAuthConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "auth",
+					Namespace: "default",
+				},
+				Spec: konnectv1alpha1.KonnectAPIAuthConfigurationSpec{
+					Type:      konnectv1alpha1.KonnectAPIAuthTypeSecretRef,
+					SecretRef: &corev1.SecretReference{Name: "token"},
+					ServerURL: "us.api.konghq.com",
+				},
+				Status: konnectv1alpha1.KonnectAPIAuthConfigurationStatus{
+					// The primary token was valid when the KonnectAPIAuthConfiguration was reconciled.
+					Conditions: []metav1.Condition{{
+						Type:   string(KonnectAPIAuthConfigurationSecondaryTokenInUseConditionType),
+						Status: metav1.ConditionFalse,
+						Reason: string(KonnectAPIAuthConfigurationReasonPrimaryTokenValid),
+					}},
+				},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "token",
+					Namespace: "default",
+					Labels: map[string]string{
+						SecretCredentialLabel: SecretCredentialLabelValueKonnect,
+					},
+				},
+				Data: map[string][]byte{
+					SecretTokenKey:          []byte(tc.primaryToken),
+					SecretSecondaryTokenKey: []byte("kpat_secondary"),
+				},
+			}
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(secret).
+				Build()
+
+			primary := sdkmocks.NewMockSDKWrapperWithT(t)
+			primary.MeSDK.EXPECT().
+				GetOrganizationsMe(mock.Anything, mock.Anything).
+				Return(nil, tc.primaryErr).
+				Once()
+			factory := tokenSDKFactory{sdkops.SDKToken(tc.primaryToken): primary}
+
+			token, err := getTokenFromKonnectAPIAuthConfiguration(t.Context(), cl, factory, apiAuth)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedToken, token)
+
+			t.Log("the result of the check is reused")
+			token, err = getTokenFromKonnectAPIAuthConfiguration(t.Context(), cl, factory, apiAuth)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedToken, token)
+		})
+	}
+}
+
+func TestPrimaryTokenRejected_ConcurrentChecks(t *testing.T) {
+	const token = "kpat_primary_concurrent"
+
+	primary := sdkmocks.NewMockSDKWrapperWithT(t)
+	release := make(chan struct{})
+	primary.MeSDK.EXPECT().
+		GetOrganizationsMe(mock.Anything, mock.Anything).
+		RunAndReturn(func(context.Context, ...sdkkonnectops.Option) (*sdkkonnectops.GetOrganizationsMeResponse, error) {
+			<-release
+			return nil, &sdkkonnecterrs.UnauthorizedError{Status: 401}
+		}).
+		Once()
+	factory := tokenSDKFactory{sdkops.SDKToken(token): primary}
+
+	t.Log("concurrent checks of the same token query Konnect once")
+	var (
+		wg      sync.WaitGroup
+		results = make([]bool, 5)
+		now     = time.Now()
+	)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = primaryTokenRejected(t.Context(), factory, "us.api.konghq.com", konnectAPIToken{value: token}, now)
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for _, rejected := range results {
+		assert.True(t, rejected)
+	}
+}
+
+func TestKonnectAPIAuthConfigurationReconciler_TokenExpiryFromKonnect(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	apiAuth := &konnectv1alpha1.KonnectAPIAuthConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth",
+			Namespace: "default",
+			Annotations: map[string]string{
+				consts.KonnectAPITokenExpiresAtAnnotationKey:     "2025-06-30T00:00:00Z",
+				consts.KonnectAPISystemAccountTokenAnnotationKey: "account-id/token-id",
+			},
+		},
+		Spec: konnectv1alpha1.KonnectAPIAuthConfigurationSpec{
+			Type:      konnectv1alpha1.KonnectAPIAuthTypeToken,
+			Token:     "spat_primary",
+			ServerURL: "us.api.konghq.com",
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(apiAuth).
+		WithStatusSubresource(apiAuth).
+		Build()
+
+	sdkFactory := sdkmocks.NewMockSDKFactory(t)
+	expectOrganizationsMe(sdkFactory.SDK, true)
+	sdkFactory.SDK.SystemAccountsAccessTokens.EXPECT().
+		GetSystemAccountsIDAccessTokensID(mock.Anything, "account-id", "token-id").
+		Return(&sdkkonnectops.GetSystemAccountsIDAccessTokensIDResponse{
+			SystemAccountAccessToken: &sdkkonnectcomp.SystemAccountAccessToken{
+				ExpiresAt: lo.ToPtr(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)),
+			},
+		}, nil)
+
+	r := NewKonnectAPIAuthConfigurationReconciler(
+		sdkFactory, logging.DevelopmentMode, cl, consts.DefaultKonnectAPITokenExpiryWarningThreshold, &metrics.MockRecorder{},
+	)
+	r.eventRecorder = record.NewFakeRecorder(10)
+	r.now = func() time.Time { return now }
+
+	t.Log("the expiry read from Konnect takes precedence over the declared one")
+	res, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(apiAuth)})
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: 2 * 24 * time.Hour}, res)
+
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(apiAuth), apiAuth))
+	cond, ok := k8sutils.GetCondition(KonnectAPIAuthConfigurationTokenExpiringConditionType, apiAuth)
+	require.True(t, ok)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, string(KonnectAPIAuthConfigurationReasonTokenExpiring), cond.Reason)
+	assert.Contains(t, cond.Message, "2025-01-03T00:00:00Z")
+}
@@ -3,6 +3,7 @@ package konnect
 import (
 	"context"
 	"fmt"
+	"time"
 
 	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
 	"github.com/samber/lo"
@@ -14,6 +15,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/gateway-operator/controller/konnect/constraints"
+	"github.com/kong/gateway-operator/controller/konnect/ops"
 	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
@@ -87,7 +89,13 @@ func handleKonnectNetworkRef[T constraints.SupportedKonnectEntityType, TEnt cons
 				nn := client.ObjectKeyFromObject(&network)
 				msg := fmt.Sprintf("Referenced KonnectCloudGatewayNetwork %s: is not ready yet, current state: %s", nn, network.Status.State)
 				setInvalidWithMsg(msg)
-				return ctrl.Result{}, ReferencedObjectIsInvalid{
+				// Network state changes trigger reconciliation of dependents
+				// but check it again in case the network's status is not updated.
+				var provisioningFor time.Duration
+				if cond, ok := k8sutils.GetCondition(ops.CloudGatewayProvisionedConditionType, &network); ok {
+					provisioningFor = time.Since(cond.LastTransitionTime.Time)
+				}
+				return ctrl.Result{RequeueAfter: ops.ProvisioningPollInterval(provisioningFor)}, ReferencedObjectIsInvalid{
 					Reference: nn.String(),
 					Msg:       msg,
 				}
@@ -107,7 +115,8 @@ func handleKonnectNetworkRef[T constraints.SupportedKonnectEntityType, TEnt cons
 			if n.Network.State != sdkkonnectcomp.NetworkStateReady {
 				msg := fmt.Sprintf("Referenced KonnectCloudGatewayNetwork <konnectID:%s>: is not ready yet, current state: %s", *ref.KonnectID, n.Network.State)
 				setInvalidWithMsg(msg)
-				return ctrl.Result{}, ReferencedObjectIsInvalid{
+				// Networks referenced by Konnect ID are not watched so poll them.
+				return ctrl.Result{RequeueAfter: ops.MaxProvisioningPollInterval}, ReferencedObjectIsInvalid{
 					Reference: *ref.KonnectID,
 					Msg:       msg,
 				}
@@ -0,0 +1,357 @@
+package konnect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/kong/gateway-operator/controller/konnect/ops"
+	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/konnect/server"
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+const (
+	// KonnectEntityOrphanedEventReason is the reason of the event emitted for
+	// KonnectGatewayControlPlanes when an orphaned Konnect entity is found.
+	KonnectEntityOrphanedEventReason = "KonnectEntityOrphaned"
+	// KonnectOrphanedEntityDeletedEventReason is the reason of the event emitted for
+	// KonnectGatewayControlPlanes when an orphaned Konnect entity is deleted.
+	KonnectOrphanedEntityDeletedEventReason = "KonnectOrphanedEntityDeleted"
+	// KonnectOrphanedEntityDeletionFailedEventReason is the reason of the event emitted for
+	// KonnectGatewayControlPlanes when an orphaned Konnect entity could not be deleted.
+	KonnectOrphanedEntityDeletionFailedEventReason = "KonnectOrphanedEntityDeletionFailed"
+)
+
+// KonnectOrphanedEntitiesReconciler periodically looks for Konnect entities
+// which were created for Kubernetes objects that do not exist anymore (e.g.
+// because their finalizer was removed by hand or because the operator was not
+// running when they were deleted).
+// Only KonnectGatewayControlPlanes annotated with
+// consts.KonnectOrphanedEntitiesGCAnnotationKey set to "true" are checked.
+// Orphaned entities are reported as metrics and events and, when a deletion
+// grace period is set, they are deleted from Konnect after that period.
+type KonnectOrphanedEntitiesReconciler struct {
+	sdkFactory  sdkops.SDKFactory
+	LoggingMode logging.Mode
+	Client      client.Client
+	// APIReader reads the objects the Konnect entities were created for directly
+	// from the API server so that objects missing from the cache (e.g. because
+	// they were just created) are not considered deleted.
+	APIReader           client.Reader
+	Period              time.Duration
+	DeletionGracePeriod time.Duration
+	MetricRecorder      metrics.Recorder
+
+	eventRecorder record.EventRecorder
+
+	lock sync.Mutex
+	// controlPlanes holds the state of the checked KonnectGatewayControlPlanes.
+	controlPlanes map[types.NamespacedName]orphanedEntitiesState
+	// now returns the current time. It's overridden in tests.
+	now func() time.Time
+}
+
+// orphanedEntitiesState is the state of orphaned entities of a ControlPlane.
+type orphanedEntitiesState struct {
+	controlPlaneID string
+	// firstSeen stores when orphaned entities were found for the first time,
+	// by their Konnect IDs.
+	firstSeen map[string]time.Time
+}
+
+// NewKonnectOrphanedEntitiesReconciler creates a new KonnectOrphanedEntitiesReconciler.
+// Orphaned entities are deleted only when deletionGracePeriod is greater than 0.
+func NewKonnectOrphanedEntitiesReconciler(
+	sdkFactory sdkops.SDKFactory,
+	loggingMode logging.Mode,
+	client client.Client,
+	apiReader client.Reader,
+	period time.Duration,
+	deletionGracePeriod time.Duration,
+	metricRecorder metrics.Recorder,
+) *KonnectOrphanedEntitiesReconciler {
+	return &KonnectOrphanedEntitiesReconciler{
+		sdkFactory:          sdkFactory,
+		LoggingMode:         loggingMode,
+		Client:              client,
+		APIReader:           apiReader,
+		Period:              period,
+		DeletionGracePeriod: deletionGracePeriod,
+		MetricRecorder:      metricRecorder,
+		controlPlanes:       make(map[types.NamespacedName]orphanedEntitiesState),
+		now:                 time.Now,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KonnectOrphanedEntitiesReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("KonnectOrphanedEntities")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("KonnectOrphanedEntities").
+		For(&konnectv1alpha1.KonnectGatewayControlPlane{},
+			builder.WithPredicates(
+				// Only changes of the annotation and of the status (e.g. the Konnect ID)
+				// are relevant, the ControlPlanes are checked periodically otherwise.
+				predicate.Or(
+					predicate.AnnotationChangedPredicate{},
+					predicate.Funcs{
+						UpdateFunc: func(e event.UpdateEvent) bool {
+							return konnectID(e.ObjectOld) != konnectID(e.ObjectNew)
+						},
+					},
+				),
+			),
+		).
+		Complete(r)
+}
+
+// Reconcile looks for orphaned entities in the Konnect ControlPlane of the KonnectGatewayControlPlane.
+func (r *KonnectOrphanedEntitiesReconciler) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	logger := log.GetLogger(ctx, "KonnectOrphanedEntities", r.LoggingMode)
+
+	var cp konnectv1alpha1.KonnectGatewayControlPlane
+	if err := r.Client.Get(ctx, req.NamespacedName, &cp); err != nil {
+		if k8serrors.IsNotFound(err) {
+			r.forget(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if cp.GetAnnotations()[consts.KonnectOrphanedEntitiesGCAnnotationKey] != "true" ||
+		!cp.GetDeletionTimestamp().IsZero() {
+		r.forget(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	// Paused ControlPlanes are reconciled again when they're resumed
+	// as the annotation changes.
+	if pause.IsPaused(&cp) {
+		log.Debug(logger, "ControlPlane reconciliation is paused, skipping looking for orphaned entities")
+		return ctrl.Result{}, nil
+	}
+
+	cpID := cp.GetKonnectID()
+	if cpID == "" {
+		log.Debug(logger, "ControlPlane does not have a Konnect ID yet, skipping looking for orphaned entities")
+		return ctrl.Result{}, nil
+	}
+
+	// The configuration of KIC ControlPlanes is owned by KIC, which tags the
+	// entities it creates with Kubernetes metadata as well.
+	if lo.FromPtr(cp.Spec.ClusterType) == sdkkonnectcomp.CreateControlPlaneRequestClusterTypeClusterTypeK8SIngressController {
+		log.Debug(logger, "ControlPlane is managed by KIC, skipping looking for orphaned entities")
+		r.forget(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	apiAuthRef, err := getAPIAuthRefNN(ctx, r.Client, &cp)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get APIAuth ref for %s: %w", client.ObjectKeyFromObject(&cp), err)
+	}
+	var apiAuth konnectv1alpha1.KonnectAPIAuthConfiguration
+	if err := r.Client.Get(ctx, apiAuthRef, &apiAuth); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get KonnectAPIAuthConfiguration %s: %w", apiAuthRef, err)
+	}
+	token, err := getTokenFromKonnectAPIAuthConfiguration(ctx, r.Client, r.sdkFactory, &apiAuth)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	server, err := server.NewServer[konnectv1alpha1.KonnectGatewayControlPlane](apiAuth.Spec.ServerURL)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to parse server URL: %w", err)
+	}
+	sdk := r.sdkFactory.NewKonnectSDK(server, sdkops.SDKToken(token))
+
+	entities, err := ops.ListTaggedKonnectEntities(ctx, sdk, cpID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var (
+		now       = r.now()
+		state     = r.stateFor(req.NamespacedName, cpID)
+		firstSeen = make(map[string]time.Time)
+		counts    = make(map[string]int)
+		uids      = make(map[orphanedEntitiesObjectsKey]sets.Set[types.UID])
+		errs      []error
+	)
+	for _, entity := range entities {
+		orphaned, err := r.isOrphaned(ctx, entity, uids)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !orphaned {
+			continue
+		}
+
+		seen, ok := state.firstSeen[entity.ID]
+		if !ok {
+			seen = now
+		}
+		desc := describeOrphanedEntity(entity)
+		log.Info(logger, "found orphaned Konnect entity",
+			"entity_type", entity.EntityType,
+			"konnect_id", entity.ID,
+			"object_kind", entity.Object.Kind,
+			"object", client.ObjectKeyFromObject(&entity.Object),
+			"object_uid", entity.Object.UID,
+		)
+
+		if r.DeletionGracePeriod <= 0 || now.Sub(seen) < r.DeletionGracePeriod {
+			r.eventRecorder.Event(&cp, corev1.EventTypeWarning, KonnectEntityOrphanedEventReason, desc)
+			firstSeen[entity.ID] = seen
+			counts[entity.EntityType]++
+			continue
+		}
+
+		if err := ops.DeleteTaggedKonnectEntity(ctx, sdk, cpID, entity); err != nil {
+			r.eventRecorder.Event(&cp, corev1.EventTypeWarning, KonnectOrphanedEntityDeletionFailedEventReason,
+				fmt.Sprintf("%s: %v", desc, err),
+			)
+			errs = append(errs, err)
+			firstSeen[entity.ID] = seen
+			counts[entity.EntityType]++
+			continue
+		}
+		r.eventRecorder.Event(&cp, corev1.EventTypeNormal, KonnectOrphanedEntityDeletedEventReason, desc)
+		log.Info(logger, "deleted orphaned Konnect entity",
+			"entity_type", entity.EntityType,
+			"konnect_id", entity.ID,
+		)
+	}
+
+	for _, entityType := range ops.TaggedKonnectEntityTypes() {
+		r.MetricRecorder.RecordKonnectOrphanedEntities(server.URL(), cpID, entityType, counts[entityType])
+	}
+	r.setState(req.NamespacedName, orphanedEntitiesState{
+		controlPlaneID: cpID,
+		firstSeen:      firstSeen,
+	})
+
+	if err := errors.Join(errs...); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: r.Period}, nil
+}
+
+// orphanedEntitiesObjectsKey identifies the objects of a kind in a namespace.
+type orphanedEntitiesObjectsKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+}
+
+// isOrphaned returns true when the Kubernetes object which the Konnect entity
+// was created for does not exist anymore. The object is matched by the UID from
+// the entity's tags, as the name tag might be truncated. The UIDs of the objects
+// of a kind in a namespace are listed once per reconciliation (and stored in uids)
+// with the uncached APIReader so that a stale cache never leads to deleting a live entity.
+// Objects of kinds which are not known to the cluster (or the operator) are not considered
+// orphaned as the entity might have been created by another tool.
+func (r *KonnectOrphanedEntitiesReconciler) isOrphaned(
+	ctx context.Context,
+	entity ops.TaggedKonnectEntity,
+	uids map[orphanedEntitiesObjectsKey]sets.Set[types.UID],
+) (bool, error) {
+	gvk := entity.Object.GroupVersionKind()
+	if !r.Client.Scheme().Recognizes(gvk) {
+		return false, nil
+	}
+
+	key := orphanedEntitiesObjectsKey{gvk: gvk, namespace: entity.Object.GetNamespace()}
+	existing, ok := uids[key]
+	if !ok {
+		var l metav1.PartialObjectMetadataList
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err := r.APIReader.List(ctx, &l, client.InNamespace(key.namespace))
+		switch {
+		case meta.IsNoMatchError(err):
+			return false, nil
+		case err != nil:
+			return false, fmt.Errorf("failed listing %s for %s %s: %w",
+				entity.Object.Kind, entity.EntityType, entity.ID, err,
+			)
+		}
+		existing = sets.New[types.UID]()
+		for _, obj := range l.Items {
+			existing.Insert(obj.GetUID())
+		}
+		uids[key] = existing
+	}
+	return !existing.Has(entity.Object.GetUID()), nil
+}
+
+func (r *KonnectOrphanedEntitiesReconciler) stateFor(nn types.NamespacedName, cpID string) orphanedEntitiesState {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	state, ok := r.controlPlanes[nn]
+	if !ok || state.controlPlaneID != cpID {
+		return orphanedEntitiesState{controlPlaneID: cpID}
+	}
+	return state
+}
+
+func (r *KonnectOrphanedEntitiesReconciler) setState(nn types.NamespacedName, state orphanedEntitiesState) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if old, ok := r.controlPlanes[nn]; ok && old.controlPlaneID != state.controlPlaneID {
+		r.MetricRecorder.ForgetKonnectOrphanedEntities(old.controlPlaneID)
+	}
+	r.controlPlanes[nn] = state
+}
+
+func (r *KonnectOrphanedEntitiesReconciler) forget(nn types.NamespacedName) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if state, ok := r.controlPlanes[nn]; ok {
+		r.MetricRecorder.ForgetKonnectOrphanedEntities(state.controlPlaneID)
+		delete(r.controlPlanes, nn)
+	}
+}
+
+func describeOrphanedEntity(entity ops.TaggedKonnectEntity) string {
+	return fmt.Sprintf("Konnect %s %s was created for %s %s (UID %s) which does not exist anymore",
+		entity.EntityType, entity.ID,
+		entity.Object.Kind, client.ObjectKeyFromObject(&entity.Object), entity.Object.UID,
+	)
+}
+
+func konnectID(obj client.Object) string {
+	cp, ok := obj.(*konnectv1alpha1.KonnectGatewayControlPlane)
+	if !ok {
+		return ""
+	}
+	return cp.GetKonnectID()
+}
@@ -0,0 +1,191 @@
+package konnect
+
+import (
+	"testing"
+	"time"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/modules/manager/logging"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestKonnectOrphanedEntitiesReconciler(t *testing.T) {
+	const cpID = "cp-id"
+
+	cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cp",
+			Namespace: "default",
+			Annotations: map[string]string{
+				consts.KonnectOrphanedEntitiesGCAnnotationKey: "true",
+			},
+		},
+		Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+			KonnectConfiguration: konnectv1alpha1.KonnectConfiguration{
+				APIAuthConfigurationRef: konnectv1alpha1.KonnectAPIAuthConfigurationRef{
+					Name: "auth",
+				},
+			},
+		},
+		Status: konnectv1alpha1.KonnectGatewayControlPlaneStatus{
+			KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{
+				ID: cpID,
+			},
+		},
+	}
+	apiAuth := &konnectv1alpha1.KonnectAPIAuthConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth",
+			Namespace: "default",
+		},
+		Spec: konnectv1alpha1.KonnectAPIAuthConfigurationSpec{
+			Type:      konnectv1alpha1.KonnectAPIAuthTypeToken,
+			Token:     "kpat_xxxxxxxxxxxx",
+			ServerURL: "us.api.konghq.com",
+		},
+	}
+	svc := &configurationv1alpha1.KongService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "svc",
+			Namespace: "default",
+			UID:       "svc-uid",
+		},
+	}
+	svcTags := func(name, uid string) []string {
+		return []string{
+			"k8s-group:configuration.konghq.com",
+			"k8s-kind:KongService",
+			"k8s-name:" + name,
+			"k8s-namespace:default",
+			"k8s-uid:" + uid,
+			"k8s-version:v1alpha1",
+		}
+	}
+
+	sdkFactory := sdkmocks.NewMockSDKFactory(t)
+	sdk := sdkFactory.SDK
+	sdk.ServicesSDK.EXPECT().
+		ListService(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListServiceResponse{
+			Object: &sdkkonnectops.ListServiceResponseBody{
+				Data: []sdkkonnectcomp.ServiceOutput{
+					// The KongService exists.
+					{ID: lo.ToPtr("svc-live"), Tags: svcTags("svc", "svc-uid")},
+					// The KongService is matched by its UID, not by its name.
+					{ID: lo.ToPtr("svc-live-uid"), Tags: svcTags("svc-other-name", "svc-uid")},
+					// The KongService was recreated with a different UID.
+					{ID: lo.ToPtr("svc-recreated"), Tags: svcTags("svc", "old-svc-uid")},
+					// The KongService does not exist.
+					{ID: lo.ToPtr("svc-gone"), Tags: svcTags("gone", "gone-uid")},
+					// The kind is not known to the operator.
+					{ID: lo.ToPtr("svc-unknown-kind"), Tags: []string{
+						"k8s-group:example.com", "k8s-kind:Unknown", "k8s-name:unknown", "k8s-uid:unknown-uid", "k8s-version:v1",
+					}},
+				},
+			},
+		}, nil)
+	sdk.RoutesSDK.EXPECT().ListRoute(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListRouteResponse{Object: &sdkkonnectops.ListRouteResponseBody{}}, nil)
+	sdk.ConsumersSDK.EXPECT().ListConsumer(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListConsumerResponse{Object: &sdkkonnectops.ListConsumerResponseBody{}}, nil)
+	sdk.ConsumerGroupSDK.EXPECT().ListConsumerGroup(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListConsumerGroupResponse{Object: &sdkkonnectops.ListConsumerGroupResponseBody{}}, nil)
+	sdk.PluginSDK.EXPECT().ListPlugin(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListPluginResponse{Object: &sdkkonnectops.ListPluginResponseBody{}}, nil)
+	sdk.UpstreamsSDK.EXPECT().ListUpstream(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListUpstreamResponse{Object: &sdkkonnectops.ListUpstreamResponseBody{}}, nil)
+	sdk.CertificatesSDK.EXPECT().ListCertificate(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListCertificateResponse{Object: &sdkkonnectops.ListCertificateResponseBody{}}, nil)
+	sdk.CACertificatesSDK.EXPECT().ListCaCertificate(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListCaCertificateResponse{Object: &sdkkonnectops.ListCaCertificateResponseBody{}}, nil)
+	sdk.KeysSDK.EXPECT().ListKey(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListKeyResponse{Object: &sdkkonnectops.ListKeyResponseBody{}}, nil)
+	sdk.KeySetsSDK.EXPECT().ListKeySet(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListKeySetResponse{Object: &sdkkonnectops.ListKeySetResponseBody{}}, nil)
+	sdk.VaultSDK.EXPECT().ListVault(mock.Anything, mock.Anything).
+		Return(&sdkkonnectops.ListVaultResponse{Object: &sdkkonnectops.ListVaultResponseBody{}}, nil)
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(cp, apiAuth).
+		Build()
+	// The KongService is read from the API server as it might be missing
+	// from the (cached) client when it was just created.
+	apiReader := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(svc).
+		Build()
+
+	now := time.Now()
+	eventRecorder := record.NewFakeRecorder(10)
+	r := NewKonnectOrphanedEntitiesReconciler(
+		sdkFactory, logging.DevelopmentMode, cl, apiReader, time.Hour, 2*time.Hour, &metrics.MockRecorder{},
+	)
+	r.eventRecorder = eventRecorder
+	r.now = func() time.Time { return now }
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "cp"}}
+
+	t.Log("orphaned entities are reported")
+	res, err := r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Hour}, res)
+	require.Len(t, eventRecorder.Events, 2)
+	assert.Contains(t, <-eventRecorder.Events, "Warning KonnectEntityOrphaned Konnect Service svc-recreated was created for KongService default/svc (UID old-svc-uid)")
+	assert.Contains(t, <-eventRecorder.Events, "Warning KonnectEntityOrphaned Konnect Service svc-gone was created for KongService default/gone (UID gone-uid)")
+
+	t.Log("orphaned entities are not deleted before the grace period")
+	now = now.Add(time.Hour)
+	_, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.Len(t, eventRecorder.Events, 2)
+	<-eventRecorder.Events
+	<-eventRecorder.Events
+
+	t.Log("orphaned entities are deleted after the grace period")
+	now = now.Add(time.Hour)
+	sdk.ServicesSDK.EXPECT().DeleteService(mock.Anything, cpID, "svc-recreated").
+		Return(&sdkkonnectops.DeleteServiceResponse{}, nil).Once()
+	sdk.ServicesSDK.EXPECT().DeleteService(mock.Anything, cpID, "svc-gone").
+		Return(&sdkkonnectops.DeleteServiceResponse{}, nil).Once()
+	_, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.Len(t, eventRecorder.Events, 2)
+	assert.Contains(t, <-eventRecorder.Events, "Normal KonnectOrphanedEntityDeleted Konnect Service svc-recreated")
+	assert.Contains(t, <-eventRecorder.Events, "Normal KonnectOrphanedEntityDeleted Konnect Service svc-gone")
+
+	t.Log("ControlPlanes managed by KIC are not checked")
+	cp.Spec.ClusterType = lo.ToPtr(sdkkonnectcomp.CreateControlPlaneRequestClusterTypeClusterTypeK8SIngressController)
+	require.NoError(t, cl.Update(t.Context(), cp))
+	res, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.Empty(t, eventRecorder.Events)
+	assert.Empty(t, r.controlPlanes)
+	cp.Spec.ClusterType = nil
+	require.NoError(t, cl.Update(t.Context(), cp))
+
+	t.Log("ControlPlanes without the annotation are not checked")
+	cp.Annotations = nil
+	require.NoError(t, cl.Update(t.Context(), cp))
+	res, err = r.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.Empty(t, r.controlPlanes)
+}
@@ -0,0 +1,65 @@
+package konnect
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/gateway-operator/controller/konnect/constraints"
+	"github.com/kong/gateway-operator/controller/pkg/controlplane"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+// pauseReferences returns the references of the provided entity whose pause
+// annotation is propagated to the entity: its KonnectGatewayControlPlane
+// and its parent entity (e.g. the KongService of a KongRoute).
+// This way pausing a KonnectGatewayControlPlane pauses all of its entities.
+func pauseReferences[T constraints.SupportedKonnectEntityType, TEnt constraints.EntityType[T]](
+	ent TEnt,
+) []pause.Reference {
+	var refs []pause.Reference
+
+	if cpRef, ok := controlplane.GetControlPlaneRef(ent).Get(); ok &&
+		cpRef.Type == commonv1alpha1.ControlPlaneRefKonnectNamespacedRef && cpRef.KonnectNamespacedRef != nil {
+		nn := types.NamespacedName{
+			Namespace: ent.GetNamespace(),
+			Name:      cpRef.KonnectNamespacedRef.Name,
+		}
+		// Cluster scoped entities (KongVault) reference the namespace of their ControlPlane.
+		if nn.Namespace == "" {
+			nn.Namespace = cpRef.KonnectNamespacedRef.Namespace
+		}
+		refs = append(refs, pause.Reference{
+			GroupVersionKind: konnectv1alpha1.GroupVersion.WithKind("KonnectGatewayControlPlane"),
+			NamespacedName:   nn,
+		})
+	}
+
+	parentRef := func(gvk schema.GroupVersionKind, name string) {
+		refs = append(refs, pause.Reference{
+			GroupVersionKind: gvk,
+			NamespacedName:   types.NamespacedName{Namespace: ent.GetNamespace(), Name: name},
+		})
+	}
+	if ref, ok := getServiceRef(ent).Get(); ok && ref.Type == configurationv1alpha1.ServiceRefNamespacedRef && ref.NamespacedRef != nil {
+		parentRef(configurationv1alpha1.GroupVersion.WithKind("KongService"), ref.NamespacedRef.Name)
+	}
+	if ref, ok := getKongUpstreamRef(ent).Get(); ok {
+		parentRef(configurationv1alpha1.GroupVersion.WithKind("KongUpstream"), ref.Name)
+	}
+	if ref, ok := getKongCertificateRef(ent).Get(); ok {
+		parentRef(configurationv1alpha1.GroupVersion.WithKind("KongCertificate"), ref.Name)
+	}
+	if ref, ok := getKeySetRef(ent).Get(); ok && ref.Type == configurationv1alpha1.KeySetRefNamespacedRef && ref.NamespacedRef != nil {
+		parentRef(configurationv1alpha1.GroupVersion.WithKind("KongKeySet"), ref.NamespacedRef.Name)
+	}
+	if ref, ok := getConsumerRef(ent).Get(); ok {
+		parentRef(configurationv1.GroupVersion.WithKind("KongConsumer"), ref.Name)
+	}
+
+	return refs
+}
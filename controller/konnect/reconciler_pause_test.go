@@ -0,0 +1,61 @@
+package konnect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/gateway-operator/controller/pkg/pause"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestPauseReferences(t *testing.T) {
+	t.Run("KongRoute references its ControlPlane and KongService", func(t *testing.T) {
+		route := &configurationv1alpha1.KongRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "default"},
+			Spec: configurationv1alpha1.KongRouteSpec{
+				ControlPlaneRef: &commonv1alpha1.ControlPlaneRef{
+					Type:                 commonv1alpha1.ControlPlaneRefKonnectNamespacedRef,
+					KonnectNamespacedRef: &commonv1alpha1.KonnectNamespacedRef{Name: "cp"},
+				},
+				ServiceRef: &configurationv1alpha1.ServiceRef{
+					Type:          configurationv1alpha1.ServiceRefNamespacedRef,
+					NamespacedRef: &commonv1alpha1.NameRef{Name: "svc"},
+				},
+			},
+		}
+		assert.Equal(t, []pause.Reference{
+			{
+				GroupVersionKind: konnectv1alpha1.GroupVersion.WithKind("KonnectGatewayControlPlane"),
+				NamespacedName:   types.NamespacedName{Namespace: "default", Name: "cp"},
+			},
+			{
+				GroupVersionKind: configurationv1alpha1.GroupVersion.WithKind("KongService"),
+				NamespacedName:   types.NamespacedName{Namespace: "default", Name: "svc"},
+			},
+		}, pauseReferences(route))
+	})
+
+	t.Run("KongVault references the namespace of its ControlPlane", func(t *testing.T) {
+		vault := &configurationv1alpha1.KongVault{
+			ObjectMeta: metav1.ObjectMeta{Name: "vault"},
+			Spec: configurationv1alpha1.KongVaultSpec{
+				ControlPlaneRef: &commonv1alpha1.ControlPlaneRef{
+					Type:                 commonv1alpha1.ControlPlaneRefKonnectNamespacedRef,
+					KonnectNamespacedRef: &commonv1alpha1.KonnectNamespacedRef{Name: "cp", Namespace: "ns"},
+				},
+			},
+		}
+		assert.Equal(t, []pause.Reference{
+			{
+				GroupVersionKind: konnectv1alpha1.GroupVersion.WithKind("KonnectGatewayControlPlane"),
+				NamespacedName:   types.NamespacedName{Namespace: "ns", Name: "cp"},
+			},
+		}, pauseReferences(vault))
+	})
+}
@@ -3,12 +3,16 @@ package konnect
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kong/gateway-operator/internal/utils/index"
+	"github.com/kong/gateway-operator/pkg/consts"
 
 	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
 )
@@ -44,16 +48,49 @@ func KonnectGatewayControlPlaneReconciliationWatchOptions(
 		func(b *ctrl.Builder) *ctrl.Builder {
 			return b.Watches(
 				&konnectv1alpha1.KonnectGatewayControlPlane{},
-				handler.EnqueueRequestsFromMapFunc(
-					enqueueKonnectGatewayControlPlaneGroupForMembers(cl),
-				),
+				enqueueKonnectGatewayControlPlaneGroupForMembers(cl),
 			)
 		},
 	}
 }
 
+// enqueueKonnectGatewayControlPlaneGroupForMembers returns an event handler enqueuing
+// the groups the changed KonnectGatewayControlPlanes are members of.
+// On updates, the groups of both the old and the new object are enqueued so that
+// the group a ControlPlane left by changing its group annotation drops it.
 func enqueueKonnectGatewayControlPlaneGroupForMembers(
 	cl client.Client,
+) handler.EventHandler {
+	mapFn := konnectGatewayControlPlaneGroupsForMember(cl)
+	enqueue := func(
+		ctx context.Context,
+		q workqueue.TypedRateLimitingInterface[reconcile.Request],
+		objs ...client.Object,
+	) {
+		for _, obj := range objs {
+			for _, req := range mapFn(ctx, obj) {
+				q.Add(req)
+			}
+		}
+	}
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+		GenericFunc: func(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+	}
+}
+
+func konnectGatewayControlPlaneGroupsForMember(
+	cl client.Client,
 ) func(ctx context.Context, obj client.Object) []reconcile.Request {
 	return func(ctx context.Context, obj client.Object) []reconcile.Request {
 		cp, ok := obj.(*konnectv1alpha1.KonnectGatewayControlPlane)
@@ -71,7 +108,20 @@ func enqueueKonnectGatewayControlPlaneGroupForMembers(
 		); err != nil {
 			return nil
 		}
+		ret := objectListToReconcileRequests(l.Items)
+
+		// Enqueue the group that this control plane declares to be a member of
+		// so that its membership is updated when the member changes or is deleted.
+		// TODO: change this when cross namespace refs are allowed.
+		if group := cp.GetAnnotations()[consts.KonnectControlPlaneGroupAnnotationKey]; group != "" {
+			ret = append(ret, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: cp.GetNamespace(),
+					Name:      group,
+				},
+			})
+		}
 
-		return objectListToReconcileRequests(l.Items)
+		return ret
 	}
 }
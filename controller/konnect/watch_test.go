@@ -6,13 +6,17 @@ import (
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kong/gateway-operator/controller/konnect/constraints"
 	"github.com/kong/gateway-operator/internal/utils/index"
 	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
 
 	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
 	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
@@ -212,3 +216,36 @@ func TestEnqueueObjectForKonnectGatewayControlPlane(t *testing.T) {
 		}
 	})
 }
+
+func TestEnqueueKonnectGatewayControlPlaneGroupForMembers(t *testing.T) {
+	member := func(group string) *konnectv1alpha1.KonnectGatewayControlPlane {
+		return &konnectv1alpha1.KonnectGatewayControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "member",
+				Namespace: "default",
+				Annotations: map[string]string{
+					consts.KonnectControlPlaneGroupAnnotationKey: group,
+				},
+			},
+		}
+	}
+	builder := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get())
+	for _, opt := range index.OptionsForKonnectGatewayControlPlane() {
+		builder = builder.WithIndex(opt.Object, opt.Field, opt.ExtractValueFn)
+	}
+	h := enqueueKonnectGatewayControlPlaneGroupForMembers(builder.Build())
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	h.Update(t.Context(), event.UpdateEvent{ObjectOld: member("group-a"), ObjectNew: member("group-b")}, q)
+
+	var enqueued []string
+	for q.Len() > 0 {
+		req, _ := q.Get()
+		enqueued = append(enqueued, req.Name)
+		q.Done(req)
+	}
+	require.ElementsMatch(t, []string{"group-a", "group-b"}, enqueued,
+		"both the group the ControlPlane left and the one it joined should be enqueued",
+	)
+}
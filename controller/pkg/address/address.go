@@ -3,6 +3,8 @@ package address
 import (
 	"fmt"
 	"net/netip"
+	"slices"
+	"strings"
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
@@ -81,6 +83,48 @@ func AddressesFromService(service *corev1.Service) ([]operatorv1beta1.Address, e
 	return addresses, nil
 }
 
+// AddressesFromNodes retrieves addresses from the provided Nodes. It's meant
+// to be used for workloads using the host network, which are reachable on the
+// addresses of the Nodes they run on.
+//
+// Nodes' ExternalIP addresses are added first, as public IPs, followed by their
+// InternalIP addresses, as private IPs. Nodes are processed in the order of their
+// names and duplicate addresses are skipped.
+func AddressesFromNodes(nodes []corev1.Node) []operatorv1beta1.Address {
+	nodes = slices.Clone(nodes)
+	slices.SortFunc(nodes, func(a, b corev1.Node) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var addresses []operatorv1beta1.Address
+	seen := make(map[string]struct{})
+	for _, addressType := range []struct {
+		nodeAddressType corev1.NodeAddressType
+		sourceType      operatorv1beta1.AddressSourceType
+	}{
+		{nodeAddressType: corev1.NodeExternalIP, sourceType: operatorv1beta1.PublicIPAddressSourceType},
+		{nodeAddressType: corev1.NodeInternalIP, sourceType: operatorv1beta1.PrivateIPAddressSourceType},
+	} {
+		for _, node := range nodes {
+			for _, nodeAddress := range node.Status.Addresses {
+				if nodeAddress.Type != addressType.nodeAddressType || nodeAddress.Address == "" {
+					continue
+				}
+				if _, ok := seen[nodeAddress.Address]; ok {
+					continue
+				}
+				seen[nodeAddress.Address] = struct{}{}
+				addresses = append(addresses, operatorv1beta1.Address{
+					Type:       lo.ToPtr(operatorv1beta1.IPAddressType),
+					Value:      nodeAddress.Address,
+					SourceType: addressType.sourceType,
+				})
+			}
+		}
+	}
+	return addresses
+}
+
 const (
 	// https://kubernetes-sigs.github.io/aws-load-balancer-controller/v2.6/guide/service/annotations/#lb-scheme
 	serviceAnnotationAWSLoadBalancerSchemeKey            = "service.beta.kubernetes.io/aws-load-balancer-scheme"
@@ -292,3 +292,58 @@ func Test_AddressesFromService(t *testing.T) {
 		})
 	}
 }
+
+func Test_AddressesFromNodes(t *testing.T) {
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-b"},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{
+					{Type: corev1.NodeHostName, Address: "node-b"},
+					{Type: corev1.NodeInternalIP, Address: "10.0.0.2"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{
+					{Type: corev1.NodeInternalIP, Address: "10.0.0.1"},
+					{Type: corev1.NodeExternalIP, Address: "203.0.113.1"},
+				},
+			},
+		},
+		{
+			// Node listed twice, e.g. because it runs multiple Pods.
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{
+					{Type: corev1.NodeInternalIP, Address: "10.0.0.1"},
+					{Type: corev1.NodeExternalIP, Address: "203.0.113.1"},
+				},
+			},
+		},
+	}
+
+	require.Equal(t,
+		[]operatorv1beta1.Address{
+			{
+				Type:       lo.ToPtr(operatorv1beta1.IPAddressType),
+				Value:      "203.0.113.1",
+				SourceType: operatorv1beta1.PublicIPAddressSourceType,
+			},
+			{
+				Type:       lo.ToPtr(operatorv1beta1.IPAddressType),
+				Value:      "10.0.0.1",
+				SourceType: operatorv1beta1.PrivateIPAddressSourceType,
+			},
+			{
+				Type:       lo.ToPtr(operatorv1beta1.IPAddressType),
+				Value:      "10.0.0.2",
+				SourceType: operatorv1beta1.PrivateIPAddressSourceType,
+			},
+		},
+		AddressesFromNodes(nodes),
+	)
+	require.Empty(t, AddressesFromNodes(nil))
+}
@@ -0,0 +1,357 @@
+package backendtls
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+
+	"github.com/kong/gateway-operator/controller/pkg/secrets/ref"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/pkg/consts"
+	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
+)
+
+// The following condition types and reasons are defined by the Gateway API
+// for BackendTLSPolicies in versions newer than the one the operator depends on.
+const (
+	// PolicyConditionResolvedRefs indicates whether the references of the
+	// BackendTLSPolicy have been resolved.
+	PolicyConditionResolvedRefs gatewayv1alpha2.PolicyConditionType = "ResolvedRefs"
+
+	// PolicyReasonResolvedRefs is used with the "ResolvedRefs" condition when
+	// all the references of the BackendTLSPolicy have been resolved.
+	PolicyReasonResolvedRefs gatewayv1alpha2.PolicyConditionReason = "ResolvedRefs"
+
+	// PolicyReasonInvalidKind is used with the "ResolvedRefs" condition when any
+	// of the CA certificate references is of an unsupported kind.
+	PolicyReasonInvalidKind gatewayv1alpha2.PolicyConditionReason = "InvalidKind"
+
+	// PolicyReasonInvalidCACertificateRef is used with the "ResolvedRefs" condition
+	// when any of the CA certificate references doesn't exist or doesn't contain
+	// a valid CA certificate.
+	PolicyReasonInvalidCACertificateRef gatewayv1alpha2.PolicyConditionReason = "InvalidCACertificateRef"
+
+	// PolicyReasonRefNotPermitted is used with the "ResolvedRefs" condition when
+	// any of the CA certificate references is not permitted by a ReferenceGrant.
+	PolicyReasonRefNotPermitted gatewayv1alpha2.PolicyConditionReason = "RefNotPermitted"
+
+	// PolicyReasonNoValidCACertificate is used with the "Accepted" condition when
+	// none of the CA certificate references of the BackendTLSPolicy is valid.
+	PolicyReasonNoValidCACertificate gatewayv1alpha2.PolicyConditionReason = "NoValidCACertificate"
+)
+
+// Resolution is the result of resolving the CA certificates of a BackendTLSPolicy
+// for a Gateway.
+type Resolution struct {
+	// CACertificates are the PEM encoded CA certificates which were resolved.
+	CACertificates [][]byte
+	// AcceptedReason is the reason of the policy's Accepted condition.
+	AcceptedReason gatewayv1alpha2.PolicyConditionReason
+	// ResolvedRefsReason is the reason of the policy's ResolvedRefs condition.
+	ResolvedRefsReason gatewayv1alpha2.PolicyConditionReason
+	// Message explains why the policy is not accepted or its references are not resolved.
+	Message string
+}
+
+// Accepted returns true if the policy is accepted.
+func (r Resolution) Accepted() bool {
+	return r.AcceptedReason == gatewayv1alpha2.PolicyReasonAccepted
+}
+
+// Conditions returns the Accepted and ResolvedRefs conditions of the policy
+// ancestor status corresponding to the resolution.
+func (r Resolution) Conditions(generation int64) []metav1.Condition {
+	accepted := metav1.Condition{
+		Type:               string(gatewayv1alpha2.PolicyConditionAccepted),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(r.AcceptedReason),
+		ObservedGeneration: generation,
+		LastTransitionTime: metav1.Now(),
+	}
+	if !r.Accepted() {
+		accepted.Status = metav1.ConditionFalse
+		accepted.Message = r.Message
+	}
+	resolvedRefs := metav1.Condition{
+		Type:               string(PolicyConditionResolvedRefs),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(r.ResolvedRefsReason),
+		ObservedGeneration: generation,
+		LastTransitionTime: metav1.Now(),
+	}
+	if r.ResolvedRefsReason != PolicyReasonResolvedRefs {
+		resolvedRefs.Status = metav1.ConditionFalse
+		resolvedRefs.Message = r.Message
+	}
+	return []metav1.Condition{accepted, resolvedRefs}
+}
+
+// PolicyTargetsService returns true if the BackendTLSPolicy targets the Service
+// with the provided namespace and name.
+func PolicyTargetsService(policy *gatewayv1alpha3.BackendTLSPolicy, namespace, name string) bool {
+	if policy.Namespace != namespace {
+		return false
+	}
+	return slices.ContainsFunc(policy.Spec.TargetRefs, func(t gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName) bool {
+		return t.Group == "" && t.Kind == "Service" && string(t.Name) == name
+	})
+}
+
+// PolicyReferencesCACertificate returns true if the BackendTLSPolicy references
+// the provided ConfigMap or Secret as a CA certificate.
+func PolicyReferencesCACertificate(policy *gatewayv1alpha3.BackendTLSPolicy, obj client.Object) bool {
+	var kind gatewayv1.Kind
+	switch obj.(type) {
+	case *corev1.ConfigMap:
+		kind = "ConfigMap"
+	case *corev1.Secret:
+		kind = "Secret"
+	default:
+		return false
+	}
+	return policy.Namespace == obj.GetNamespace() &&
+		slices.ContainsFunc(policy.Spec.Validation.CACertificateRefs, func(r gatewayv1.LocalObjectReference) bool {
+			return r.Group == "" && r.Kind == kind && string(r.Name) == obj.GetName()
+		})
+}
+
+// httpRouteBackendServices returns the namespaced names of the Services used as
+// backends by the HTTPRoute.
+func httpRouteBackendServices(route *gwtypes.HTTPRoute) []types.NamespacedName {
+	var services []types.NamespacedName
+	for _, rule := range route.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			if (backendRef.Group != nil && *backendRef.Group != "") ||
+				(backendRef.Kind != nil && *backendRef.Kind != "Service") {
+				continue
+			}
+			nn := types.NamespacedName{Namespace: route.Namespace, Name: string(backendRef.Name)}
+			if backendRef.Namespace != nil {
+				nn.Namespace = string(*backendRef.Namespace)
+			}
+			if !slices.Contains(services, nn) {
+				services = append(services, nn)
+			}
+		}
+	}
+	return services
+}
+
+// ListPoliciesForGateway returns the BackendTLSPolicies targeting the Services
+// used as backends by the HTTPRoutes attached to the Gateway, sorted by
+// namespace and name.
+func ListPoliciesForGateway(
+	ctx context.Context,
+	cl client.Client,
+	gateway *gwtypes.Gateway,
+) ([]gatewayv1alpha3.BackendTLSPolicy, error) {
+	// Gateways fetched from the cache have no TypeMeta set, which is used to
+	// match the HTTPRoutes' parentRefs.
+	gw := gateway.DeepCopy()
+	gw.SetGroupVersionKind(gatewayv1.SchemeGroupVersion.WithKind("Gateway"))
+	routes, err := gatewayutils.ListHTTPRoutesForGateway(ctx, cl, gw)
+	if err != nil {
+		return nil, err
+	}
+	var services []types.NamespacedName
+	for i := range routes {
+		services = append(services, httpRouteBackendServices(&routes[i])...)
+	}
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	var policyList gatewayv1alpha3.BackendTLSPolicyList
+	if err := cl.List(ctx, &policyList); err != nil {
+		return nil, fmt.Errorf("failed listing BackendTLSPolicies: %w", err)
+	}
+	var policies []gatewayv1alpha3.BackendTLSPolicy
+	for _, policy := range policyList.Items {
+		if slices.ContainsFunc(services, func(nn types.NamespacedName) bool {
+			return PolicyTargetsService(&policy, nn.Namespace, nn.Name)
+		}) {
+			policies = append(policies, policy)
+		}
+	}
+	slices.SortFunc(policies, func(a, b gatewayv1alpha3.BackendTLSPolicy) int {
+		if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return policies, nil
+}
+
+// ListGatewaysForPolicy returns the namespaced names of the Gateways which the
+// HTTPRoutes using the Services targeted by the BackendTLSPolicy are attached to.
+func ListGatewaysForPolicy(
+	ctx context.Context,
+	cl client.Client,
+	policy *gatewayv1alpha3.BackendTLSPolicy,
+) ([]types.NamespacedName, error) {
+	var routes gwtypes.HTTPRouteList
+	if err := cl.List(ctx, &routes); err != nil {
+		return nil, fmt.Errorf("failed listing HTTPRoutes: %w", err)
+	}
+
+	var gateways []types.NamespacedName
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if !slices.ContainsFunc(httpRouteBackendServices(route), func(nn types.NamespacedName) bool {
+			return PolicyTargetsService(policy, nn.Namespace, nn.Name)
+		}) {
+			continue
+		}
+		for _, parentRef := range route.Spec.ParentRefs {
+			if (parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName) ||
+				(parentRef.Kind != nil && *parentRef.Kind != "Gateway") {
+				continue
+			}
+			nn := types.NamespacedName{Namespace: route.Namespace, Name: string(parentRef.Name)}
+			if parentRef.Namespace != nil {
+				nn.Namespace = string(*parentRef.Namespace)
+			}
+			if !slices.Contains(gateways, nn) {
+				gateways = append(gateways, nn)
+			}
+		}
+	}
+	return gateways, nil
+}
+
+// ResolveCACertificates resolves the CA certificates referenced by the
+// BackendTLSPolicy for the provided Gateway.
+// CA certificates in a namespace different than the Gateway's one have to be
+// granted to the Gateway with a ReferenceGrant as its DataPlane uses them to
+// verify the certificates of the targeted Services.
+func ResolveCACertificates(
+	ctx context.Context,
+	cl client.Client,
+	policy *gatewayv1alpha3.BackendTLSPolicy,
+	gateway *gwtypes.Gateway,
+) (Resolution, error) {
+	res := Resolution{
+		AcceptedReason:     gatewayv1alpha2.PolicyReasonAccepted,
+		ResolvedRefsReason: PolicyReasonResolvedRefs,
+	}
+
+	validation := policy.Spec.Validation
+	if validation.WellKnownCACertificates != nil && *validation.WellKnownCACertificates != "" {
+		// Kong trusts the system CA certificates by default.
+		if *validation.WellKnownCACertificates != gatewayv1alpha3.WellKnownCACertificatesSystem {
+			res.AcceptedReason = gatewayv1alpha2.PolicyReasonInvalid
+			res.Message = fmt.Sprintf("WellKnownCACertificates %q is not supported", *validation.WellKnownCACertificates)
+		}
+		return res, nil
+	}
+
+	// The Gateway is used as the source of the reference when checking ReferenceGrants.
+	from := gateway.DeepCopy()
+	from.SetGroupVersionKind(gatewayv1.SchemeGroupVersion.WithKind("Gateway"))
+
+	var messages []string
+	for _, caRef := range validation.CACertificateRefs {
+		if caRef.Group != "" || (caRef.Kind != "ConfigMap" && caRef.Kind != "Secret") {
+			res.ResolvedRefsReason = PolicyReasonInvalidKind
+			messages = append(messages, fmt.Sprintf("CA certificate reference %s %s is not a ConfigMap or a Secret.", caRef.Kind, caRef.Name))
+			continue
+		}
+
+		whyNotGranted, granted, err := ref.CheckReferenceGrantForCoreObject(ctx, cl, from,
+			caRef.Kind, gatewayv1.Namespace(policy.Namespace), caRef.Name)
+		if err != nil {
+			return Resolution{}, err
+		}
+		if !granted {
+			res.ResolvedRefsReason = PolicyReasonRefNotPermitted
+			messages = append(messages, whyNotGranted+".")
+			continue
+		}
+
+		pemBytes, err := getCACertificate(ctx, cl, policy.Namespace, caRef)
+		if err != nil {
+			if !k8serrors.IsNotFound(err) && !isInvalidCACertificate(err) {
+				return Resolution{}, err
+			}
+			res.ResolvedRefsReason = PolicyReasonInvalidCACertificateRef
+			messages = append(messages, fmt.Sprintf("CA certificate reference %s %s is invalid: %v.", caRef.Kind, caRef.Name, err))
+			continue
+		}
+		res.CACertificates = append(res.CACertificates, pemBytes)
+	}
+
+	res.Message = strings.Join(messages, " ")
+	if len(res.CACertificates) == 0 {
+		res.AcceptedReason = PolicyReasonNoValidCACertificate
+	}
+	return res, nil
+}
+
+type invalidCACertificateError struct {
+	msg string
+}
+
+func (e invalidCACertificateError) Error() string {
+	return e.msg
+}
+
+func isInvalidCACertificate(err error) bool {
+	_, ok := err.(invalidCACertificateError)
+	return ok
+}
+
+// getCACertificate returns the PEM encoded CA certificate stored under the
+// consts.CACRT key of the referenced ConfigMap or Secret.
+func getCACertificate(
+	ctx context.Context,
+	cl client.Client,
+	namespace string,
+	caRef gatewayv1.LocalObjectReference,
+) ([]byte, error) {
+	var (
+		data []byte
+		key  = client.ObjectKey{Namespace: namespace, Name: string(caRef.Name)}
+	)
+	switch caRef.Kind {
+	case "ConfigMap":
+		var cm corev1.ConfigMap
+		if err := cl.Get(ctx, key, &cm); err != nil {
+			return nil, err
+		}
+		data = []byte(cm.Data[consts.CACRT])
+	case "Secret":
+		var secret corev1.Secret
+		if err := cl.Get(ctx, key, &secret); err != nil {
+			return nil, err
+		}
+		data = secret.Data[consts.CACRT]
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, invalidCACertificateError{msg: fmt.Sprintf("no %s key", consts.CACRT)}
+	}
+	for rest := data; len(bytes.TrimSpace(rest)) > 0; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, invalidCACertificateError{msg: "invalid PEM data"}
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, invalidCACertificateError{msg: fmt.Sprintf("invalid certificate: %v", err)}
+		}
+	}
+	return bytes.TrimSpace(data), nil
+}
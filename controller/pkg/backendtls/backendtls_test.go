@@ -0,0 +1,231 @@
+package backendtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+func generateCACertificate(t *testing.T, commonName string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func testPolicy(namespace, name, service string, refs ...gatewayv1.LocalObjectReference) *gatewayv1alpha3.BackendTLSPolicy {
+	return &gatewayv1alpha3.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{
+				{
+					LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{
+						Kind: "Service",
+						Name: gatewayv1.ObjectName(service),
+					},
+				},
+			},
+			Validation: gatewayv1alpha3.BackendTLSPolicyValidation{
+				CACertificateRefs: refs,
+				Hostname:          "backend.example.com",
+			},
+		},
+	}
+}
+
+func TestListPoliciesAndGateways(t *testing.T) {
+	gateway := &gwtypes.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "gw", Name: "gateway"},
+	}
+	route := &gwtypes.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "gw", Name: "route"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: "local",
+						}}},
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name:      "remote",
+							Namespace: lo.ToPtr(gatewayv1.Namespace("backend")),
+						}}},
+					},
+				},
+			},
+		},
+	}
+	local := testPolicy("gw", "local", "local")
+	remote := testPolicy("backend", "remote", "remote")
+	unrelated := testPolicy("gw", "unrelated", "other")
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(gateway, route, local, remote, unrelated).
+		Build()
+
+	policies, err := ListPoliciesForGateway(t.Context(), cl, gateway)
+	require.NoError(t, err)
+	require.Equal(t,
+		[]string{"backend/remote", "gw/local"},
+		lo.Map(policies, func(p gatewayv1alpha3.BackendTLSPolicy, _ int) string {
+			return client.ObjectKeyFromObject(&p).String()
+		}),
+	)
+
+	gateways, err := ListGatewaysForPolicy(t.Context(), cl, remote)
+	require.NoError(t, err)
+	require.Equal(t, []types.NamespacedName{{Namespace: "gw", Name: "gateway"}}, gateways)
+
+	gateways, err = ListGatewaysForPolicy(t.Context(), cl, unrelated)
+	require.NoError(t, err)
+	require.Empty(t, gateways)
+}
+
+func TestResolveCACertificates(t *testing.T) {
+	caCert := generateCACertificate(t, "ca")
+	gateway := &gwtypes.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "gw", Name: "gateway"},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "backend", Name: "ca"},
+		Data:       map[string]string{consts.CACRT: string(caCert)},
+	}
+	invalidSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "backend", Name: "invalid"},
+		Data:       map[string][]byte{consts.CACRT: []byte("not a certificate")},
+	}
+	referenceGrant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "backend", Name: "grant"},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{
+				{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "gw"},
+			},
+			To: []gatewayv1beta1.ReferenceGrantTo{
+				{Kind: "ConfigMap"},
+				{Kind: "Secret"},
+			},
+		},
+	}
+	configMapRef := gatewayv1.LocalObjectReference{Kind: "ConfigMap", Name: "ca"}
+
+	testCases := []struct {
+		name                       string
+		policy                     *gatewayv1alpha3.BackendTLSPolicy
+		objects                    []client.Object
+		expectedAcceptedReason     gatewayv1alpha2.PolicyConditionReason
+		expectedResolvedRefsReason gatewayv1alpha2.PolicyConditionReason
+		expectedCACertificates     int
+	}{
+		{
+			name:                       "ConfigMap granted by a ReferenceGrant",
+			policy:                     testPolicy("backend", "policy", "svc", configMapRef),
+			objects:                    []client.Object{configMap, referenceGrant},
+			expectedAcceptedReason:     gatewayv1alpha2.PolicyReasonAccepted,
+			expectedResolvedRefsReason: PolicyReasonResolvedRefs,
+			expectedCACertificates:     1,
+		},
+		{
+			name:                       "ConfigMap not granted",
+			policy:                     testPolicy("backend", "policy", "svc", configMapRef),
+			objects:                    []client.Object{configMap},
+			expectedAcceptedReason:     PolicyReasonNoValidCACertificate,
+			expectedResolvedRefsReason: PolicyReasonRefNotPermitted,
+		},
+		{
+			name: "invalid kind and invalid Secret along a valid ConfigMap",
+			policy: testPolicy("backend", "policy", "svc",
+				configMapRef,
+				gatewayv1.LocalObjectReference{Kind: "Service", Name: "ca"},
+				gatewayv1.LocalObjectReference{Kind: "Secret", Name: "invalid"},
+			),
+			objects:                    []client.Object{configMap, invalidSecret, referenceGrant},
+			expectedAcceptedReason:     gatewayv1alpha2.PolicyReasonAccepted,
+			expectedResolvedRefsReason: PolicyReasonInvalidCACertificateRef,
+			expectedCACertificates:     1,
+		},
+		{
+			name: "missing ConfigMap",
+			policy: testPolicy("gw", "policy", "svc",
+				gatewayv1.LocalObjectReference{Kind: "ConfigMap", Name: "missing"},
+			),
+			expectedAcceptedReason:     PolicyReasonNoValidCACertificate,
+			expectedResolvedRefsReason: PolicyReasonInvalidCACertificateRef,
+		},
+		{
+			name: "system well known CA certificates",
+			policy: func() *gatewayv1alpha3.BackendTLSPolicy {
+				p := testPolicy("gw", "policy", "svc")
+				p.Spec.Validation.WellKnownCACertificates = lo.ToPtr(gatewayv1alpha3.WellKnownCACertificatesSystem)
+				return p
+			}(),
+			expectedAcceptedReason:     gatewayv1alpha2.PolicyReasonAccepted,
+			expectedResolvedRefsReason: PolicyReasonResolvedRefs,
+		},
+		{
+			name: "unsupported well known CA certificates",
+			policy: func() *gatewayv1alpha3.BackendTLSPolicy {
+				p := testPolicy("gw", "policy", "svc")
+				p.Spec.Validation.WellKnownCACertificates = lo.ToPtr(gatewayv1alpha3.WellKnownCACertificatesType("Other"))
+				return p
+			}(),
+			expectedAcceptedReason:     gatewayv1alpha2.PolicyReasonInvalid,
+			expectedResolvedRefsReason: PolicyReasonResolvedRefs,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(tc.objects...).
+				Build()
+
+			res, err := ResolveCACertificates(t.Context(), cl, tc.policy, gateway)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedAcceptedReason, res.AcceptedReason)
+			require.Equal(t, tc.expectedResolvedRefsReason, res.ResolvedRefsReason)
+			require.Len(t, res.CACertificates, tc.expectedCACertificates)
+		})
+	}
+}
@@ -25,7 +25,11 @@ type DefaultsArgs struct {
 	DataPlaneIngressServiceName string
 	DataPlaneAdminServiceName   string
 	OwnedByGateway              string
-	AnonymousReportsEnabled     bool
+	// SharedByGateways is set when the ControlPlane is shared by the Gateways
+	// of a shared infrastructure group and reconciles all the Gateways of
+	// their GatewayClass instead of a single Gateway.
+	SharedByGateways        bool
+	AnonymousReportsEnabled bool
 }
 
 // -----------------------------------------------------------------------------
@@ -132,7 +136,7 @@ func SetDefaults(
 		}
 	}
 
-	if args.OwnedByGateway != "" {
+	if args.OwnedByGateway != "" || args.SharedByGateways {
 		// If the controlplane is managed by a gateway, the controlplane may take some time to properly connect to the dataplane,
 		// as the controlplane and the dataplane are deployed together. For this reason, we set the env var CONTROLLER_KONG_ADMIN_INIT_RETRY_DELAY
 		// to 5s (the default value is 1s) to:
@@ -145,7 +149,15 @@ func SetDefaults(
 			}
 		}
 
-		if _, isOverrideDisabled := dontOverride["CONTROLLER_GATEWAY_TO_RECONCILE"]; !isOverrideDisabled {
+		if args.SharedByGateways {
+			// A shared ControlPlane has to reconcile all the Gateways of its group,
+			// so it can't be restricted to a single Gateway, e.g. the Gateway which
+			// owned it before it joined the group.
+			if env := k8sutils.RejectEnvByName(container.Env, "CONTROLLER_GATEWAY_TO_RECONCILE"); len(env) != len(container.Env) {
+				container.Env = env
+				changed = true
+			}
+		} else if _, isOverrideDisabled := dontOverride["CONTROLLER_GATEWAY_TO_RECONCILE"]; !isOverrideDisabled {
 			gatewayOwner := fmt.Sprintf("%s/%s", args.Namespace, args.OwnedByGateway)
 			if k8sutils.EnvValueByName(container.Env, "CONTROLLER_GATEWAY_TO_RECONCILE") != gatewayOwner {
 				container.Env = k8sutils.UpdateEnv(container.Env, "CONTROLLER_GATEWAY_TO_RECONCILE", gatewayOwner)
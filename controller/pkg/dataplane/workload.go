@@ -0,0 +1,38 @@
+package dataplane
+
+import (
+	"fmt"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// WorkloadKind returns the kind of workload running the DataPlane's Pods as
+// configured through the consts.AnnotationDataPlaneWorkloadKind annotation.
+func WorkloadKind(dataplane *operatorv1beta1.DataPlane) (string, error) {
+	switch kind := dataplane.Annotations[consts.AnnotationDataPlaneWorkloadKind]; kind {
+	case "", consts.DataPlaneWorkloadKindDeployment:
+		return consts.DataPlaneWorkloadKindDeployment, nil
+	case consts.DataPlaneWorkloadKindDaemonSet:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("invalid %s annotation value %q, supported values: %s, %s",
+			consts.AnnotationDataPlaneWorkloadKind, kind, consts.DataPlaneWorkloadKindDeployment, consts.DataPlaneWorkloadKindDaemonSet,
+		)
+	}
+}
+
+// IsDaemonSetWorkload returns true when the DataPlane's Pods are run by a DaemonSet.
+func IsDaemonSetWorkload(dataplane *operatorv1beta1.DataPlane) bool {
+	kind, err := WorkloadKind(dataplane)
+	return err == nil && kind == consts.DataPlaneWorkloadKindDaemonSet
+}
+
+// IsHostNetworkDaemonSetWorkload returns true when the DataPlane's Pods are run
+// by a DaemonSet and use the host network. Such DataPlanes are reachable on the
+// addresses of the Nodes their Pods run on.
+func IsHostNetworkDaemonSetWorkload(dataplane *operatorv1beta1.DataPlane) bool {
+	podTemplateSpec := dataplane.Spec.Deployment.PodTemplateSpec
+	return IsDaemonSetWorkload(dataplane) && podTemplateSpec != nil && podTemplateSpec.Spec.HostNetwork
+}
@@ -0,0 +1,268 @@
+// Package deletionprotection implements the opt-in protection of Gateways and
+// DataPlanes from being deleted while they are in use, using the
+// consts.AnnotationDeletionProtection annotation.
+package deletionprotection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kong/gateway-operator/controller/controlplane_extensions/metricsscraper"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+const (
+	// Finalizer is the finalizer set on protected objects which is only removed
+	// once their deletion is not blocked anymore.
+	Finalizer = "gateway-operator.konghq.com/deletion-protection"
+
+	// ConditionType is the type of the condition set on protected objects whose
+	// deletion is blocked because they are in use. The admission policy denying
+	// the deletion of protected objects uses its message to explain why.
+	ConditionType kcfgconsts.ConditionType = "DeletionBlocked"
+	// ReasonInUse is the reason used for the DeletionBlocked condition (and the
+	// event emitted when a deletion is blocked).
+	ReasonInUse kcfgconsts.ConditionReason = "InUse"
+
+	// DegradedConditionType is the type of the condition set on protected
+	// objects when it can't be determined whether they are in use, e.g. when
+	// the request rate of their DataPlanes is not scraped, in which case their
+	// deletion is not blocked by it.
+	DegradedConditionType kcfgconsts.ConditionType = "DeletionProtectionDegraded"
+	// ReasonMetricsUnavailable is the reason used for the DeletionProtectionDegraded
+	// condition when the request rate of DataPlanes is not scraped.
+	ReasonMetricsUnavailable kcfgconsts.ConditionReason = "MetricsUnavailable"
+)
+
+// requeueAfter is the duration after which protected objects are requeued to
+// check whether they are still in use, as the scraped request rate changes
+// without triggering reconciliation.
+const requeueAfter = 30 * time.Second
+
+// Blockers holds why a protected object must not be deleted.
+type Blockers struct {
+	// Reasons are the reasons why the object is in use.
+	Reasons []string
+	// Unavailable are the reasons why it can't be determined whether the object
+	// is in use, e.g. DataPlanes whose request rate is not scraped.
+	Unavailable []string
+}
+
+// DataPlaneMetrics provides the metrics computed for DataPlanes from the
+// metrics scraped from their Pods.
+type DataPlaneMetrics interface {
+	Get(dataplane types.NamespacedName) (metricsscraper.DataPlaneMetrics, bool)
+}
+
+// IsEnabled returns true if the provided object is protected from deletion
+// using the consts.AnnotationDeletionProtection annotation.
+func IsEnabled(obj metav1.Object) bool {
+	return obj.GetAnnotations()[consts.AnnotationDeletionProtection] == "true"
+}
+
+// IsConfirmed returns true if the deletion of the provided object has been
+// confirmed using the consts.AnnotationConfirmDeletion annotation.
+func IsConfirmed(obj metav1.Object) bool {
+	return obj.GetAnnotations()[consts.AnnotationConfirmDeletion] == "true"
+}
+
+// IsManagedByGateway returns true if the provided object is owned by a Gateway,
+// or several ones for shared DataPlanes, in which case it's protected through
+// its Gateways.
+func IsManagedByGateway(obj metav1.Object) bool {
+	return lo.ContainsBy(obj.GetOwnerReferences(), func(ownerRef metav1.OwnerReference) bool {
+		return ownerRef.Kind == "Gateway"
+	})
+}
+
+// AddDataPlaneTraffic adds why the provided DataPlane must not be deleted when
+// it serves traffic, according to the request rate scraped from its Pods.
+//
+// The request rate is only scraped for DataPlanes whose ControlPlane has a
+// DataPlaneMetricsExtension attached. When it's not available, the DataPlane
+// is added to the unavailable reasons instead, so that it's reported with the
+// DeletionProtectionDegraded condition rather than silently not blocking the
+// deletion.
+func (b *Blockers) AddDataPlaneTraffic(metrics DataPlaneMetrics, dataplane *operatorv1beta1.DataPlane) {
+	var (
+		m  metricsscraper.DataPlaneMetrics
+		ok bool
+	)
+	if metrics != nil {
+		m, ok = metrics.Get(client.ObjectKeyFromObject(dataplane))
+	}
+	switch {
+	case !ok:
+		b.Unavailable = append(b.Unavailable, fmt.Sprintf(
+			"the request rate of DataPlane %s is not scraped, attach a DataPlaneMetricsExtension to its ControlPlane",
+			dataplane.Name,
+		))
+	case m.Values[metricsscraper.DataPlaneMetricRequestsPerSecond] > 0:
+		b.Reasons = append(b.Reasons, fmt.Sprintf("DataPlane %s serves traffic", dataplane.Name))
+	}
+}
+
+// Message returns the message explaining why the deletion of the provided
+// object is denied.
+func Message(kind string, obj metav1.Object, blockers []string) string {
+	return fmt.Sprintf("%s %s/%s is protected from deletion with the %s annotation: %s. Set the %s annotation to \"true\" to delete it",
+		kind, obj.GetNamespace(), obj.GetName(), consts.AnnotationDeletionProtection,
+		strings.Join(blockers, "; "), consts.AnnotationConfirmDeletion,
+	)
+}
+
+// Reconcile manages the deletion protection of the provided object: it sets
+// the Finalizer on protected objects and reports why they must not be deleted
+// with the DeletionBlocked condition, using blockers to find out whether they
+// are in use. When a protected object is being deleted while in use, without
+// its deletion being confirmed, it emits an event and keeps the Finalizer.
+// conditionsAware has to operate on the provided object's status.
+//
+// When blocked is true, the caller must not clean up the object and should
+// return the provided result and error. When blocked is false and the result
+// is not zero (e.g. on patch conflicts) the caller should return it as well.
+// When it can't be determined whether the object is in use, this is reported
+// with the DeletionProtectionDegraded condition.
+func Reconcile(
+	ctx context.Context,
+	cl client.Client,
+	recorder record.EventRecorder,
+	kind string,
+	obj client.Object,
+	conditionsAware k8sutils.ConditionsAware,
+	blockers func() (Blockers, error),
+) (blocked bool, res ctrl.Result, err error) {
+	deleting := !obj.GetDeletionTimestamp().IsZero()
+	protected := IsEnabled(obj)
+	// Finalizers can't be added to objects being deleted.
+	if deleting && !controllerutil.ContainsFinalizer(obj, Finalizer) {
+		return false, ctrl.Result{}, nil
+	}
+
+	var b Blockers
+	if protected && !(deleting && IsConfirmed(obj)) {
+		if b, err = blockers(); err != nil {
+			return false, ctrl.Result{}, err
+		}
+	}
+	blocked = deleting && len(b.Reasons) > 0
+
+	if res, err := setConditions(ctx, cl, obj, conditionsAware, b); err != nil || !res.IsZero() {
+		return blocked, res, err
+	}
+
+	if blocked {
+		if recorder != nil {
+			recorder.Event(obj, corev1.EventTypeWarning, string(ReasonInUse), Message(kind, obj, b.Reasons))
+		}
+		return true, ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	old := obj.DeepCopyObject().(client.Object)
+	var changed bool
+	if protected && !deleting {
+		changed = controllerutil.AddFinalizer(obj, Finalizer)
+	} else {
+		changed = controllerutil.RemoveFinalizer(obj, Finalizer)
+	}
+	if !changed {
+		return false, ctrl.Result{}, nil
+	}
+	if err := cl.Patch(ctx, obj, client.MergeFrom(old)); err != nil {
+		if k8serrors.IsConflict(err) || k8serrors.IsNotFound(err) {
+			return false, ctrl.Result{Requeue: true}, nil
+		}
+		return false, ctrl.Result{}, fmt.Errorf("failed to patch %s finalizer: %w", Finalizer, err)
+	}
+	return false, ctrl.Result{}, nil
+}
+
+// RequeueProtected returns the provided result, making sure that protected
+// objects are requeued periodically so that their DeletionBlocked condition,
+// used to deny their deletion upfront, follows the scraped request rate.
+// It's meant to be deferred by the reconcilers of protected objects.
+func RequeueProtected(obj metav1.Object, res ctrl.Result, err error) ctrl.Result {
+	if err != nil || !IsEnabled(obj) || res.Requeue ||
+		(res.RequeueAfter > 0 && res.RequeueAfter <= requeueAfter) {
+		return res
+	}
+	res.RequeueAfter = requeueAfter
+	return res
+}
+
+// setConditions sets the DeletionBlocked condition on the provided object when
+// there are reasons blocking its deletion, and the DeletionProtectionDegraded
+// condition when it can't be determined whether it's in use. They're removed
+// otherwise.
+func setConditions(
+	ctx context.Context,
+	cl client.Client,
+	obj client.Object,
+	conditionsAware k8sutils.ConditionsAware,
+	b Blockers,
+) (ctrl.Result, error) {
+	old := obj.DeepCopyObject().(client.Object)
+	blockedChanged := setCondition(obj, conditionsAware, ConditionType, ReasonInUse, b.Reasons)
+	degradedChanged := setCondition(obj, conditionsAware, DegradedConditionType, ReasonMetricsUnavailable, b.Unavailable)
+	if !blockedChanged && !degradedChanged {
+		return ctrl.Result{}, nil
+	}
+	if err := cl.Status().Patch(ctx, obj, client.MergeFrom(old)); err != nil {
+		if k8serrors.IsConflict(err) || k8serrors.IsNotFound(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to patch status with %s and %s conditions: %w", ConditionType, DegradedConditionType, err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// setCondition sets the condition of the provided type on the provided object
+// when there are reasons for it and removes it otherwise. It returns true when
+// the conditions changed.
+func setCondition(
+	obj client.Object,
+	conditionsAware k8sutils.ConditionsAware,
+	conditionType kcfgconsts.ConditionType,
+	reason kcfgconsts.ConditionReason,
+	reasons []string,
+) bool {
+	message := strings.Join(reasons, "; ")
+	cond, ok := k8sutils.GetCondition(conditionType, conditionsAware)
+	switch {
+	case len(reasons) == 0 && !ok:
+		return false
+	case len(reasons) > 0 && ok && cond.Status == metav1.ConditionTrue &&
+		cond.Message == message && cond.ObservedGeneration == obj.GetGeneration():
+		return false
+	}
+
+	if len(reasons) > 0 {
+		k8sutils.SetCondition(
+			k8sutils.NewConditionWithGeneration(conditionType, metav1.ConditionTrue, reason, message, obj.GetGeneration()),
+			conditionsAware,
+		)
+	} else {
+		// Remove the condition instead of setting it to False as other conditions
+		// like Ready or Programmed are only True when all the other ones are True.
+		conditionsAware.SetConditions(lo.Reject(conditionsAware.GetConditions(), func(c metav1.Condition, _ int) bool {
+			return c.Type == string(conditionType)
+		}))
+	}
+	return true
+}
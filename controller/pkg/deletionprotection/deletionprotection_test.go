@@ -0,0 +1,196 @@
+package deletionprotection
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kong/gateway-operator/controller/controlplane_extensions/metricsscraper"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+type staticDataPlaneMetrics map[types.NamespacedName]float64
+
+func (m staticDataPlaneMetrics) Get(dataplane types.NamespacedName) (metricsscraper.DataPlaneMetrics, bool) {
+	rps, ok := m[dataplane]
+	if !ok {
+		return metricsscraper.DataPlaneMetrics{}, false
+	}
+	return metricsscraper.DataPlaneMetrics{
+		Values:    map[string]float64{metricsscraper.DataPlaneMetricRequestsPerSecond: rps},
+		Timestamp: time.Now(),
+	}, true
+}
+
+func TestDataPlaneTrafficBlocker(t *testing.T) {
+	metrics := staticDataPlaneMetrics{
+		{Namespace: "default", Name: "busy"}: 12.5,
+		{Namespace: "default", Name: "idle"}: 0,
+	}
+	dataplane := func(name string) *operatorv1beta1.DataPlane {
+		return &operatorv1beta1.DataPlane{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+	}
+
+	var blockers Blockers
+	blockers.AddDataPlaneTraffic(metrics, dataplane("busy"))
+	blockers.AddDataPlaneTraffic(metrics, dataplane("idle"))
+	blockers.AddDataPlaneTraffic(metrics, dataplane("not-scraped"))
+	blockers.AddDataPlaneTraffic(nil, dataplane("busy"))
+	require.Equal(t, []string{"DataPlane busy serves traffic"}, blockers.Reasons)
+	require.Equal(t, []string{
+		"the request rate of DataPlane not-scraped is not scraped, attach a DataPlaneMetricsExtension to its ControlPlane",
+		"the request rate of DataPlane busy is not scraped, attach a DataPlaneMetricsExtension to its ControlPlane",
+	}, blockers.Unavailable)
+}
+
+func TestRequeueProtected(t *testing.T) {
+	protected := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{consts.AnnotationDeletionProtection: "true"},
+		},
+	}
+	unprotected := &operatorv1beta1.DataPlane{}
+
+	require.Equal(t, ctrl.Result{}, RequeueProtected(unprotected, ctrl.Result{}, nil))
+	require.Equal(t, ctrl.Result{RequeueAfter: requeueAfter}, RequeueProtected(protected, ctrl.Result{}, nil))
+	require.Equal(t, ctrl.Result{RequeueAfter: requeueAfter}, RequeueProtected(protected, ctrl.Result{RequeueAfter: time.Hour}, nil))
+	require.Equal(t, ctrl.Result{RequeueAfter: time.Second}, RequeueProtected(protected, ctrl.Result{RequeueAfter: time.Second}, nil))
+	require.Equal(t, ctrl.Result{Requeue: true}, RequeueProtected(protected, ctrl.Result{Requeue: true}, nil))
+	require.Equal(t, ctrl.Result{}, RequeueProtected(protected, ctrl.Result{}, errors.New("error")))
+}
+
+func TestReconcile(t *testing.T) {
+	inUse := func() (Blockers, error) { return Blockers{Reasons: []string{"DataPlane dp serves traffic"}}, nil }
+	notInUse := func() (Blockers, error) { return Blockers{}, nil }
+	notScraped := func() (Blockers, error) {
+		return Blockers{Unavailable: []string{"the request rate of DataPlane dp is not scraped"}}, nil
+	}
+
+	testCases := []struct {
+		name              string
+		annotations       map[string]string
+		finalizers        []string
+		deleting          bool
+		blockers          func() (Blockers, error)
+		expectedBlocked   bool
+		expectedFinalizer bool
+		expectedCondition bool
+		expectedDegraded  bool
+		expectedEvents    int
+	}{
+		{
+			name:     "unprotected DataPlane",
+			blockers: inUse,
+		},
+		{
+			name:              "protected DataPlane in use",
+			annotations:       map[string]string{consts.AnnotationDeletionProtection: "true"},
+			blockers:          inUse,
+			expectedFinalizer: true,
+			expectedCondition: true,
+		},
+		{
+			name:              "protected DataPlane not in use",
+			annotations:       map[string]string{consts.AnnotationDeletionProtection: "true"},
+			blockers:          notInUse,
+			expectedFinalizer: true,
+		},
+		{
+			name:              "protected DataPlane not scraped",
+			annotations:       map[string]string{consts.AnnotationDeletionProtection: "true"},
+			blockers:          notScraped,
+			expectedFinalizer: true,
+			expectedDegraded:  true,
+		},
+		{
+			name:             "deleting protected DataPlane not scraped",
+			annotations:      map[string]string{consts.AnnotationDeletionProtection: "true"},
+			finalizers:       []string{Finalizer, "other"},
+			deleting:         true,
+			blockers:         notScraped,
+			expectedDegraded: true,
+		},
+		{
+			name:       "protection removed",
+			finalizers: []string{Finalizer},
+			blockers:   inUse,
+		},
+		{
+			name:              "deleting protected DataPlane in use",
+			annotations:       map[string]string{consts.AnnotationDeletionProtection: "true"},
+			finalizers:        []string{Finalizer, "other"},
+			deleting:          true,
+			blockers:          inUse,
+			expectedBlocked:   true,
+			expectedFinalizer: true,
+			expectedCondition: true,
+			expectedEvents:    1,
+		},
+		{
+			name: "deleting protected DataPlane in use with confirmation",
+			annotations: map[string]string{
+				consts.AnnotationDeletionProtection: "true",
+				consts.AnnotationConfirmDeletion:    "true",
+			},
+			finalizers: []string{Finalizer, "other"},
+			deleting:   true,
+			blockers:   inUse,
+		},
+		{
+			name:        "deleting protected DataPlane not in use",
+			annotations: map[string]string{consts.AnnotationDeletionProtection: "true"},
+			finalizers:  []string{Finalizer, "other"},
+			deleting:    true,
+			blockers:    notInUse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dataplane := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        "dp",
+					Annotations: tc.annotations,
+					Finalizers:  tc.finalizers,
+				},
+			}
+			if tc.deleting {
+				dataplane.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			}
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(dataplane).
+				WithStatusSubresource(dataplane).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), dataplane))
+
+			blocked, res, err := Reconcile(t.Context(), cl, recorder, "DataPlane", dataplane, dataplane, tc.blockers)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedBlocked, blocked)
+			if blocked {
+				require.Equal(t, requeueAfter, res.RequeueAfter)
+			}
+
+			require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), dataplane))
+			require.Equal(t, tc.expectedFinalizer, controllerutil.ContainsFinalizer(dataplane, Finalizer))
+			require.Equal(t, tc.expectedCondition, k8sutils.HasCondition(ConditionType, dataplane))
+			require.Equal(t, tc.expectedDegraded, k8sutils.HasCondition(DegradedConditionType, dataplane))
+			require.Len(t, recorder.Events, tc.expectedEvents)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package maintenance implements maintenance windows which restrict when changes
+// affecting the Pods of DataPlanes and ControlPlanes are rolled out, using the
+// consts.AnnotationMaintenanceWindowSchedule family of annotations.
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+)
+
+const (
+	// ConditionType is the type of the condition set on objects whose Pod
+	// template changes are held until the next maintenance window.
+	// It is removed once there are no held changes anymore.
+	ConditionType kcfgconsts.ConditionType = "PendingMaintenance"
+
+	// ReasonWaitingForMaintenanceWindow is the reason used with the
+	// PendingMaintenance condition when changes are held.
+	ReasonWaitingForMaintenanceWindow kcfgconsts.ConditionReason = "WaitingForMaintenanceWindow"
+)
+
+// Annotations are the annotations configuring maintenance windows. They are
+// propagated from GatewayConfigurations to the DataPlanes and ControlPlanes
+// generated for them.
+var Annotations = []string{
+	consts.AnnotationMaintenanceWindowSchedule,
+	consts.AnnotationMaintenanceWindowDuration,
+	consts.AnnotationMaintenanceWindowTimezone,
+	consts.AnnotationMaintenanceOverride,
+}
+
+// Window is a recurring maintenance window.
+type Window struct {
+	schedule *schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// ParseWindow parses a maintenance window opening according to the provided
+// cron schedule, evaluated in the provided IANA time zone (UTC when empty),
+// and staying open for the provided Go duration.
+func ParseWindow(cronSchedule, duration, timezone string) (*Window, error) {
+	s, err := parseSchedule(cronSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", cronSchedule, err)
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration %q: %w", duration, err)
+	}
+	if d <= 0 {
+		return nil, fmt.Errorf("invalid duration %q: must be positive", duration)
+	}
+	location := time.UTC
+	if timezone != "" {
+		if location, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+	}
+	return &Window{
+		schedule: s,
+		duration: d,
+		location: location,
+	}, nil
+}
+
+// WindowForObject returns the maintenance window configured on the provided
+// object with its annotations, or nil when it has no maintenance window.
+func WindowForObject(obj metav1.Object) (*Window, error) {
+	annotations := obj.GetAnnotations()
+	cronSchedule, ok := annotations[consts.AnnotationMaintenanceWindowSchedule]
+	if !ok {
+		return nil, nil
+	}
+	duration, ok := annotations[consts.AnnotationMaintenanceWindowDuration]
+	if !ok {
+		return nil, fmt.Errorf("%s annotation has to be set together with %s annotation",
+			consts.AnnotationMaintenanceWindowDuration, consts.AnnotationMaintenanceWindowSchedule,
+		)
+	}
+	w, err := ParseWindow(cronSchedule, duration, annotations[consts.AnnotationMaintenanceWindowTimezone])
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window: %w", err)
+	}
+	return w, nil
+}
+
+// IsOpen returns true if the maintenance window is open at the provided time.
+func (w *Window) IsOpen(now time.Time) bool {
+	start := w.schedule.next(now.In(w.location).Add(-w.duration))
+	return !start.IsZero() && !start.After(now)
+}
+
+// NextOpen returns the time at which the maintenance window opens next, or
+// the provided time when the window is open. It returns the zero time when
+// the window never opens.
+func (w *Window) NextOpen(now time.Time) time.Time {
+	if w.IsOpen(now) {
+		return now
+	}
+	return w.schedule.next(now.In(w.location))
+}
+
+// IsOverridden returns true if the maintenance window of the provided object
+// has been overridden with the consts.AnnotationMaintenanceOverride annotation.
+func IsOverridden(obj metav1.Object) bool {
+	return obj.GetAnnotations()[consts.AnnotationMaintenanceOverride] == "true"
+}
+
+// HoldChanges returns true if changes affecting the Pods of the provided object
+// have to be held at the provided time, because its maintenance window is
+// closed and hasn't been overridden. It also returns the time at which the
+// window opens next, which is the zero time when the window never opens.
+func HoldChanges(obj metav1.Object, now time.Time) (bool, time.Time, error) {
+	w, err := WindowForObject(obj)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	if w == nil || IsOverridden(obj) || w.IsOpen(now) {
+		return false, time.Time{}, nil
+	}
+	nextOpen := w.NextOpen(now)
+	if nextOpen.IsZero() {
+		return false, time.Time{}, errors.New("invalid maintenance window: schedule never activates")
+	}
+	return true, nextOpen, nil
+}
+
+// HoldPodTemplateChanges keeps the existing Pod template in the desired workload
+// when it differs from the desired one, so that the Pods are not rolled.
+// The spec hash annotation of the existing workload is kept as well so that
+// the held changes are still detected once they can be rolled out.
+// It returns true when changes were held.
+func HoldPodTemplateChanges(
+	existing, desired metav1.Object,
+	existingTemplate, desiredTemplate *corev1.PodTemplateSpec,
+) bool {
+	k8sresources.SetDefaultsPodTemplateSpec(desiredTemplate)
+	if cmp.Equal(*existingTemplate, *desiredTemplate, cmp.Comparer(k8sresources.ResourceRequirementsEqual)) {
+		return false
+	}
+	*desiredTemplate = *existingTemplate.DeepCopy()
+
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	if hash, ok := existing.GetAnnotations()[consts.AnnotationSpecHash]; ok {
+		annotations[consts.AnnotationSpecHash] = hash
+	} else {
+		delete(annotations, consts.AnnotationSpecHash)
+	}
+	desired.SetAnnotations(annotations)
+	return true
+}
+
+// PendingCondition returns the PendingMaintenance condition for changes held
+// until the provided time.
+func PendingCondition(nextOpen time.Time, observedGeneration int64) metav1.Condition {
+	return k8sutils.NewConditionWithGeneration(
+		ConditionType,
+		metav1.ConditionTrue,
+		ReasonWaitingForMaintenanceWindow,
+		fmt.Sprintf("Pod template changes are held until the maintenance window opens at %s", nextOpen.UTC().Format(time.RFC3339)),
+		observedGeneration,
+	)
+}
@@ -0,0 +1,189 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestParseWindow(t *testing.T) {
+	testCases := []struct {
+		name          string
+		schedule      string
+		duration      string
+		timezone      string
+		expectedError bool
+	}{
+		{name: "valid", schedule: "0 2 * * 6", duration: "2h"},
+		{name: "valid with timezone", schedule: "0 2 * * 6", duration: "2h", timezone: "Europe/Berlin"},
+		{name: "invalid schedule", schedule: "0 2 * *", duration: "2h", expectedError: true},
+		{name: "invalid duration", schedule: "0 2 * * 6", duration: "2 hours", expectedError: true},
+		{name: "non positive duration", schedule: "0 2 * * 6", duration: "0s", expectedError: true},
+		{name: "invalid timezone", schedule: "0 2 * * 6", duration: "2h", timezone: "Mars/Olympus", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseWindow(tc.schedule, tc.duration, tc.timezone)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	// Opens on Saturdays at 02:00 in Berlin (01:00 UTC in winter) for 2 hours.
+	w, err := ParseWindow("0 2 * * 6", "2h", "Europe/Berlin")
+	require.NoError(t, err)
+	saturdayOpen := time.Date(2026, 3, 7, 1, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name             string
+		now              time.Time
+		expectedOpen     bool
+		expectedNextOpen time.Time
+	}{
+		{
+			name:             "before the window",
+			now:              time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+			expectedNextOpen: saturdayOpen,
+		},
+		{
+			name:             "when the window opens",
+			now:              saturdayOpen,
+			expectedOpen:     true,
+			expectedNextOpen: saturdayOpen,
+		},
+		{
+			name:             "inside the window",
+			now:              saturdayOpen.Add(119 * time.Minute),
+			expectedOpen:     true,
+			expectedNextOpen: saturdayOpen.Add(119 * time.Minute),
+		},
+		{
+			name:             "when the window closes",
+			now:              saturdayOpen.Add(2 * time.Hour),
+			expectedNextOpen: time.Date(2026, 3, 14, 1, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOpen, w.IsOpen(tc.now))
+			nextOpen := w.NextOpen(tc.now)
+			require.True(t, tc.expectedNextOpen.Equal(nextOpen), "expected %s, got %s", tc.expectedNextOpen, nextOpen)
+		})
+	}
+}
+
+func TestHoldChanges(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC) // Monday
+	window := map[string]string{
+		consts.AnnotationMaintenanceWindowSchedule: "0 2 * * 6",
+		consts.AnnotationMaintenanceWindowDuration: "2h",
+	}
+	withAnnotations := func(annotations ...map[string]string) *operatorv1beta1.DataPlane {
+		dp := &operatorv1beta1.DataPlane{}
+		dp.Annotations = map[string]string{}
+		for _, a := range annotations {
+			for k, v := range a {
+				dp.Annotations[k] = v
+			}
+		}
+		return dp
+	}
+
+	testCases := []struct {
+		name             string
+		dataplane        *operatorv1beta1.DataPlane
+		expectedHold     bool
+		expectedNextOpen time.Time
+		expectedError    bool
+	}{
+		{
+			name:      "no maintenance window",
+			dataplane: withAnnotations(),
+		},
+		{
+			name:             "window closed",
+			dataplane:        withAnnotations(window),
+			expectedHold:     true,
+			expectedNextOpen: time.Date(2026, 3, 7, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "window open",
+			dataplane: withAnnotations(window, map[string]string{consts.AnnotationMaintenanceWindowSchedule: "0 9 * * 1"}),
+		},
+		{
+			name:      "window overridden",
+			dataplane: withAnnotations(window, map[string]string{consts.AnnotationMaintenanceOverride: "true"}),
+		},
+		{
+			name:          "missing duration",
+			dataplane:     withAnnotations(map[string]string{consts.AnnotationMaintenanceWindowSchedule: "0 2 * * 6"}),
+			expectedError: true,
+		},
+		{
+			name:          "window never opening",
+			dataplane:     withAnnotations(window, map[string]string{consts.AnnotationMaintenanceWindowSchedule: "0 0 30 2 *"}),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hold, nextOpen, err := HoldChanges(tc.dataplane, now)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedHold, hold)
+			require.True(t, tc.expectedNextOpen.Equal(nextOpen), "expected %s, got %s", tc.expectedNextOpen, nextOpen)
+		})
+	}
+}
+
+func TestHoldPodTemplateChanges(t *testing.T) {
+	newDeployment := func(image, hash string) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{consts.AnnotationSpecHash: hash},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "proxy", Image: image}},
+					},
+				},
+			},
+		}
+		k8sresources.SetDefaultsPodTemplateSpec(&d.Spec.Template)
+		return d
+	}
+
+	t.Run("unchanged Pod template", func(t *testing.T) {
+		existing, desired := newDeployment("kong:3.9", "old"), newDeployment("kong:3.9", "new")
+		require.False(t, HoldPodTemplateChanges(existing, desired, &existing.Spec.Template, &desired.Spec.Template))
+		require.Equal(t, "new", desired.Annotations[consts.AnnotationSpecHash])
+	})
+
+	t.Run("changed Pod template", func(t *testing.T) {
+		existing, desired := newDeployment("kong:3.9", "old"), newDeployment("kong:3.10", "new")
+		require.True(t, HoldPodTemplateChanges(existing, desired, &existing.Spec.Template, &desired.Spec.Template))
+		require.Equal(t, "kong:3.9", desired.Spec.Template.Spec.Containers[0].Image)
+		require.Equal(t, "old", desired.Annotations[consts.AnnotationSpecHash])
+	})
+}
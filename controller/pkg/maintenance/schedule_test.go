@@ -0,0 +1,119 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          string
+		expectedError bool
+	}{
+		{name: "every minute", spec: "* * * * *"},
+		{name: "lists, ranges and steps", spec: "0,30 1-5 */2 1-12/3 1-5"},
+		{name: "Sunday as 7", spec: "0 0 * * 7"},
+		{name: "too few fields", spec: "0 0 * *", expectedError: true},
+		{name: "too many fields", spec: "0 0 * * * *", expectedError: true},
+		{name: "minute out of range", spec: "60 0 * * *", expectedError: true},
+		{name: "day of month out of range", spec: "0 0 0 * *", expectedError: true},
+		{name: "invalid range", spec: "0 5-1 * * *", expectedError: true},
+		{name: "invalid step", spec: "*/0 * * * *", expectedError: true},
+		{name: "names are not supported", spec: "0 0 * * MON", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseSchedule(tc.spec)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		spec     string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "next minute",
+			spec:     "* * * * *",
+			from:     time.Date(2026, 3, 2, 10, 15, 30, 0, time.UTC),
+			expected: time.Date(2026, 3, 2, 10, 16, 0, 0, time.UTC),
+		},
+		{
+			name:     "strictly after the provided time",
+			spec:     "0 2 * * *",
+			from:     time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 3, 3, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "every 15 minutes",
+			spec:     "*/15 * * * *",
+			from:     time.Date(2026, 3, 2, 10, 16, 0, 0, time.UTC),
+			expected: time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "Saturday",
+			spec:     "0 2 * * 6",
+			from:     time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), // Monday
+			expected: time.Date(2026, 3, 7, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Sunday as 7",
+			spec:     "0 2 * * 7",
+			from:     time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), // Monday
+			expected: time.Date(2026, 3, 8, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week when both are restricted",
+			spec:     "0 0 15 * 0",
+			from:     time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), // Monday
+			expected: time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "next year",
+			spec:     "30 4 1 1 *",
+			from:     time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+			expected: time.Date(2027, 1, 1, 4, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "in the location of the provided time",
+			spec:     "0 2 * * *",
+			from:     time.Date(2026, 3, 2, 10, 0, 0, 0, berlin),
+			expected: time.Date(2026, 3, 3, 2, 0, 0, 0, berlin),
+		},
+		{
+			name:     "skipped by daylight saving time",
+			spec:     "30 2 * * *",
+			from:     time.Date(2026, 3, 29, 0, 0, 0, 0, berlin),
+			expected: time.Date(2026, 3, 30, 2, 30, 0, 0, berlin),
+		},
+		{
+			name: "never",
+			spec: "0 0 30 2 *",
+			from: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := parseSchedule(tc.spec)
+			require.NoError(t, err)
+			next := s.next(tc.from)
+			require.True(t, tc.expected.Equal(next), "expected %s, got %s", tc.expected, next)
+		})
+	}
+}
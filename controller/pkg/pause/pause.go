@@ -0,0 +1,264 @@
+// Package pause implements pausing of the reconciliation of operator managed
+// objects using the consts.AnnotationReconciliationPaused annotation.
+package pause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+const (
+	// ConditionType is the type of the condition set on objects whose
+	// reconciliation has been paused.
+	ConditionType kcfgconsts.ConditionType = "Paused"
+
+	// ReasonReconciliationPaused is the reason used for the Paused condition
+	// (and the emitted event) when the reconciliation is paused.
+	ReasonReconciliationPaused kcfgconsts.ConditionReason = "ReconciliationPaused"
+	// ReasonReconciliationResumed is the reason used for the event emitted
+	// when the reconciliation is resumed.
+	ReasonReconciliationResumed kcfgconsts.ConditionReason = "ReconciliationResumed"
+)
+
+// IsPaused returns true if reconciliation of the provided object has been paused
+// using the consts.AnnotationReconciliationPaused annotation.
+// Objects that are being deleted are never considered paused so that their
+// finalizers can be handled and deletion is never blocked.
+func IsPaused(obj metav1.Object) bool {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return false
+	}
+	return obj.GetAnnotations()[consts.AnnotationReconciliationPaused] == "true"
+}
+
+// ownerPausedRequeueAfter is the duration after which objects paused through
+// their owner (or a referenced object) are requeued to check whether it has been resumed.
+const ownerPausedRequeueAfter = 30 * time.Second
+
+// managingOwnerGroupKinds is the set of owner (and referenced) kinds whose
+// pause annotation is propagated to the objects they own (or that reference them).
+var managingOwnerGroupKinds = map[schema.GroupKind]struct{}{
+	{Group: gatewayv1.GroupName, Kind: "Gateway"}:                           {},
+	{Group: operatorv1alpha1.SchemeGroupVersion.Group, Kind: "AIGateway"}:   {},
+	{Group: operatorv1beta1.SchemeGroupVersion.Group, Kind: "ControlPlane"}: {},
+	{Group: operatorv1beta1.SchemeGroupVersion.Group, Kind: "DataPlane"}:    {},
+	// Konnect entities reference their control plane and parent entities
+	// through their spec.
+	{Group: konnectv1alpha1.SchemeGroupVersion.Group, Kind: "KonnectGatewayControlPlane"}: {},
+	{Group: configurationv1alpha1.SchemeGroupVersion.Group, Kind: "KongService"}:          {},
+	{Group: configurationv1alpha1.SchemeGroupVersion.Group, Kind: "KongUpstream"}:         {},
+	{Group: configurationv1alpha1.SchemeGroupVersion.Group, Kind: "KongCertificate"}:      {},
+	{Group: configurationv1alpha1.SchemeGroupVersion.Group, Kind: "KongKeySet"}:           {},
+	{Group: configurationv1.SchemeGroupVersion.Group, Kind: "KongConsumer"}:               {},
+}
+
+// Reference is a reference to an object, other than the controller owner,
+// whose pause annotation is propagated to the referencing object, e.g. the
+// KonnectGatewayControlPlane referenced by a Konnect entity.
+type Reference struct {
+	GroupVersionKind schema.GroupVersionKind
+	types.NamespacedName
+}
+
+// PausedOwner returns the controller owner of the provided object if its
+// reconciliation has been paused. This allows pausing the reconciliation of
+// a whole tree of objects, e.g. a Gateway together with its DataPlane and ControlPlane.
+// It returns nil if the object has no paused owner.
+func PausedOwner(ctx context.Context, cl client.Client, obj metav1.Object) (*metav1.PartialObjectMetadata, error) {
+	ownerRef := metav1.GetControllerOf(obj)
+	if ownerRef == nil {
+		return nil, nil
+	}
+	gv, err := schema.ParseGroupVersion(ownerRef.APIVersion)
+	if err != nil {
+		return nil, nil //nolint:nilerr
+	}
+	owner, err := pausedObject(ctx, cl, gv.WithKind(ownerRef.Kind), types.NamespacedName{Namespace: obj.GetNamespace(), Name: ownerRef.Name})
+	if err != nil || owner == nil || owner.UID != ownerRef.UID {
+		return nil, err
+	}
+	return owner, nil
+}
+
+// PausedReference returns the first of the provided referenced objects whose
+// reconciliation has been paused.
+// It returns nil if none of the referenced objects is paused.
+func PausedReference(ctx context.Context, cl client.Client, refs ...Reference) (*metav1.PartialObjectMetadata, error) {
+	for _, ref := range refs {
+		obj, err := pausedObject(ctx, cl, ref.GroupVersionKind, ref.NamespacedName)
+		if err != nil || obj != nil {
+			return obj, err
+		}
+	}
+	return nil, nil
+}
+
+// pausedObject returns the metadata of the object with the provided kind and
+// name if its reconciliation has been paused.
+func pausedObject(
+	ctx context.Context,
+	cl client.Client,
+	gvk schema.GroupVersionKind,
+	nn types.NamespacedName,
+) (*metav1.PartialObjectMetadata, error) {
+	// Only check the kinds the operator manages (and hence has permissions to
+	// watch) to not block reconciliation on caches that would never sync.
+	if _, ok := managingOwnerGroupKinds[gvk.GroupKind()]; !ok {
+		return nil, nil
+	}
+
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	if err := cl.Get(ctx, nn, obj); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s %s: %w", gvk.Kind, nn.Name, err)
+	}
+	if !IsPaused(obj) {
+		return nil, nil
+	}
+	return obj, nil
+}
+
+// Reconcile checks whether the reconciliation of the provided object (or its
+// controller owner) has been paused and reflects that in the object's Paused
+// status condition (which is removed on resume), emitting an event whenever
+// the reconciliation gets paused or resumed.
+// The reconciliation is also considered paused when any of the provided
+// references (e.g. a Konnect entity's control plane) is paused.
+// conditionsAware has to operate on the provided object's status.
+//
+// When paused is true, the caller should stop reconciling the object and return
+// the provided result and error. When paused is false and the result is not
+// zero (e.g. on status patch conflicts) the caller should return it as well.
+func Reconcile(
+	ctx context.Context,
+	cl client.Client,
+	recorder record.EventRecorder,
+	obj client.Object,
+	conditionsAware k8sutils.ConditionsAware,
+	refs ...Reference,
+) (paused bool, res ctrl.Result, err error) {
+	var (
+		message     string
+		requeueWhen time.Duration
+	)
+	switch {
+	case IsPaused(obj):
+		message = fmt.Sprintf("Reconciliation paused using %s annotation", consts.AnnotationReconciliationPaused)
+	// Do not check the owner for objects being deleted so that their
+	// finalizers are always handled.
+	case obj.GetDeletionTimestamp().IsZero():
+		owner, err := PausedOwner(ctx, cl, obj)
+		if err != nil {
+			return false, ctrl.Result{}, err
+		}
+		if owner != nil {
+			message = fmt.Sprintf("Reconciliation paused using %s annotation on owner %s %s",
+				consts.AnnotationReconciliationPaused, owner.Kind, owner.Name,
+			)
+			// Changes to the owner's annotations do not trigger reconciliation
+			// of the owned objects so periodically check whether it has been resumed.
+			requeueWhen = ownerPausedRequeueAfter
+			break
+		}
+		ref, err := PausedReference(ctx, cl, refs...)
+		if err != nil {
+			return false, ctrl.Result{}, err
+		}
+		if ref != nil {
+			message = fmt.Sprintf("Reconciliation paused using %s annotation on referenced %s %s",
+				consts.AnnotationReconciliationPaused, ref.Kind, ref.Name,
+			)
+			requeueWhen = ownerPausedRequeueAfter
+		}
+	}
+	paused = message != ""
+
+	cond, ok := k8sutils.GetCondition(ConditionType, conditionsAware)
+	if !paused && !ok {
+		return false, ctrl.Result{}, nil
+	}
+	if paused && ok && cond.Status == metav1.ConditionTrue && cond.Message == message &&
+		cond.ObservedGeneration == obj.GetGeneration() {
+		return true, ctrl.Result{RequeueAfter: requeueWhen}, nil
+	}
+
+	old := obj.DeepCopyObject().(client.Object)
+	reason := ReasonReconciliationResumed
+	if paused {
+		reason = ReasonReconciliationPaused
+		k8sutils.SetCondition(
+			k8sutils.NewConditionWithGeneration(ConditionType, metav1.ConditionTrue, reason, message, obj.GetGeneration()),
+			conditionsAware,
+		)
+	} else {
+		// Remove the condition instead of setting it to False as other conditions
+		// like Ready or Programmed are only True when all the other ones are True.
+		message = "Reconciliation resumed"
+		conditionsAware.SetConditions(lo.Reject(conditionsAware.GetConditions(), func(c metav1.Condition, _ int) bool {
+			return c.Type == string(ConditionType)
+		}))
+	}
+	if err := cl.Status().Patch(ctx, obj, client.MergeFrom(old)); err != nil {
+		if k8serrors.IsConflict(err) {
+			return paused, ctrl.Result{Requeue: true}, nil
+		}
+		return paused, ctrl.Result{}, fmt.Errorf("failed to patch status with %s condition: %w", ConditionType, err)
+	}
+
+	if recorder != nil && (!ok || !paused) {
+		recorder.Event(obj, corev1.EventTypeNormal, string(reason), message)
+	}
+	return paused, ctrl.Result{RequeueAfter: requeueWhen}, nil
+}
+
+// AnnotationChangedPredicate is a predicate which passes update events for objects
+// whose consts.AnnotationReconciliationPaused annotation value has changed.
+// It can be used to trigger reconciliation on pause and resume when other
+// predicates (e.g. predicate.GenerationChangedPredicate) filter out metadata changes.
+var AnnotationChangedPredicate = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld == nil || e.ObjectNew == nil {
+			return false
+		}
+		return e.ObjectNew.GetAnnotations()[consts.AnnotationReconciliationPaused] !=
+			e.ObjectOld.GetAnnotations()[consts.AnnotationReconciliationPaused]
+	},
+	DeleteFunc: func(event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(event.GenericEvent) bool {
+		return false
+	},
+}
@@ -0,0 +1,185 @@
+package pause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	pausedAnnotation := map[string]string{
+		consts.AnnotationReconciliationPaused: "true",
+	}
+	gateway := &gatewayv1.Gateway{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gatewayv1.GroupVersion.String(),
+			Kind:       "Gateway",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "gw",
+			Namespace:   "default",
+			UID:         "gw-uid",
+			Annotations: pausedAnnotation,
+		},
+	}
+
+	controlPlane := &konnectv1alpha1.KonnectGatewayControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cp",
+			Namespace:   "default",
+			Annotations: pausedAnnotation,
+		},
+	}
+	controlPlaneRef := Reference{
+		GroupVersionKind: konnectv1alpha1.GroupVersion.WithKind("KonnectGatewayControlPlane"),
+		NamespacedName:   types.NamespacedName{Namespace: "default", Name: "cp"},
+	}
+
+	testCases := []struct {
+		name            string
+		dataplane       *operatorv1beta1.DataPlane
+		objects         []client.Object
+		refs            []Reference
+		expectedPaused  bool
+		expectedRequeue bool
+		expectedCond    *metav1.Condition
+		expectedEvents  int
+	}{
+		{
+			name: "not paused DataPlane without condition",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "dp", Namespace: "default"},
+			},
+		},
+		{
+			name: "paused DataPlane",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "dp", Namespace: "default", Annotations: pausedAnnotation},
+			},
+			expectedPaused: true,
+			expectedCond: &metav1.Condition{
+				Type:   string(ConditionType),
+				Status: metav1.ConditionTrue,
+				Reason: string(ReasonReconciliationPaused),
+			},
+			expectedEvents: 1,
+		},
+		{
+			name: "paused DataPlane being deleted is not paused",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "dp",
+					Namespace:         "default",
+					Annotations:       pausedAnnotation,
+					DeletionTimestamp: lo.ToPtr(metav1.Now()),
+					Finalizers:        []string{"test"},
+				},
+			},
+		},
+		{
+			name: "resumed DataPlane",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "dp", Namespace: "default"},
+				Status: operatorv1beta1.DataPlaneStatus{
+					Conditions: []metav1.Condition{
+						k8sutils.NewCondition(ConditionType, metav1.ConditionTrue, ReasonReconciliationPaused, ""),
+					},
+				},
+			},
+			expectedEvents: 1,
+		},
+		{
+			name: "DataPlane owned by a paused Gateway",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "dp",
+					Namespace:       "default",
+					OwnerReferences: []metav1.OwnerReference{k8sutils.GenerateOwnerReferenceForObject(gateway)},
+				},
+			},
+			objects:         []client.Object{gateway},
+			expectedPaused:  true,
+			expectedRequeue: true,
+			expectedCond: &metav1.Condition{
+				Type:   string(ConditionType),
+				Status: metav1.ConditionTrue,
+				Reason: string(ReasonReconciliationPaused),
+			},
+			expectedEvents: 1,
+		},
+		{
+			name: "DataPlane referencing a paused KonnectGatewayControlPlane",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "dp", Namespace: "default"},
+			},
+			objects:         []client.Object{controlPlane},
+			refs:            []Reference{controlPlaneRef},
+			expectedPaused:  true,
+			expectedRequeue: true,
+			expectedCond: &metav1.Condition{
+				Type:   string(ConditionType),
+				Status: metav1.ConditionTrue,
+				Reason: string(ReasonReconciliationPaused),
+			},
+			expectedEvents: 1,
+		},
+		{
+			name: "DataPlane referencing a missing KonnectGatewayControlPlane",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "dp", Namespace: "default"},
+			},
+			refs: []Reference{controlPlaneRef},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(append(tc.objects, tc.dataplane)...).
+				WithStatusSubresource(tc.dataplane).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+
+			dp := &operatorv1beta1.DataPlane{}
+			require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(tc.dataplane), dp))
+			paused, res, err := Reconcile(ctx, cl, recorder, dp, dp, tc.refs...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPaused, paused)
+			assert.Equal(t, tc.expectedRequeue, res.RequeueAfter > 0)
+			assert.Len(t, recorder.Events, tc.expectedEvents)
+
+			require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(tc.dataplane), dp))
+			cond, ok := k8sutils.GetCondition(ConditionType, dp)
+			if tc.expectedCond == nil {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedCond.Status, cond.Status)
+			assert.Equal(t, tc.expectedCond.Reason, cond.Reason)
+
+			// Subsequent reconciliation should not emit events again.
+			_, _, err = Reconcile(ctx, cl, recorder, dp, dp, tc.refs...)
+			require.NoError(t, err)
+			assert.Len(t, recorder.Events, tc.expectedEvents)
+		})
+	}
+}
@@ -81,7 +81,17 @@ func CheckReferenceGrantForSecret(
 	if secretRef.Namespace == nil || *secretRef.Namespace == "" {
 		return "", false, fmt.Errorf("caller must ensure that Namespace in SecretObjectReference is set (bug in the code)")
 	}
-	if gatewayv1.Namespace(fromObj.GetNamespace()) == *secretRef.Namespace {
+	return CheckReferenceGrantForCoreObject(ctx, c, fromObj, "Secret", *secretRef.Namespace, secretRef.Name)
+}
+
+// CheckReferenceGrantForCoreObject checks if the reference from the object (fromObj) to the core group object
+// (e.g. a Secret or a ConfigMap) of the provided kind, namespace and name is granted. Examining returned values
+// makes sense only if err is nil. When isReferenceGranted is false, whyNotGranted provides the reason (otherwise it is
+// expected to be discarded).
+func CheckReferenceGrantForCoreObject(
+	ctx context.Context, c client.Client, fromObj client.Object, kind gatewayv1.Kind, namespace gatewayv1.Namespace, name gatewayv1.ObjectName,
+) (whyNotGranted string, isReferenceGranted bool, err error) {
+	if gatewayv1.Namespace(fromObj.GetNamespace()) == namespace {
 		return "", true, nil
 	}
 
@@ -92,18 +102,18 @@ func CheckReferenceGrantForSecret(
 			Kind:      gatewayv1.Kind(fromObj.GetObjectKind().GroupVersionKind().Kind),
 			Namespace: gatewayv1.Namespace(fromObj.GetNamespace()),
 		},
-		string(*secretRef.Namespace),
+		string(namespace),
 		gatewayv1beta1.ReferenceGrantTo{
-			Kind: "Secret",
-			Name: lo.ToPtr(secretRef.Name),
+			Kind: kind,
+			Name: lo.ToPtr(name),
 		},
 	)
 	if err != nil {
-		return "", false, fmt.Errorf("failed to check if Secret %s/%s is allowed by ReferenceGrants: %w",
-			*secretRef.Namespace, secretRef.Name, err)
+		return "", false, fmt.Errorf("failed to check if %s %s/%s is allowed by ReferenceGrants: %w",
+			kind, namespace, name, err)
 	}
 	if !allowed {
-		return fmt.Sprintf("Secret %s/%s reference not allowed by any ReferenceGrant", *secretRef.Namespace, secretRef.Name), false, nil
+		return fmt.Sprintf("%s %s/%s reference not allowed by any ReferenceGrant", kind, namespace, name), false, nil
 	}
 	return "", true, nil
 }
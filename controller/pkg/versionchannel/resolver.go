@@ -0,0 +1,232 @@
+package versionchannel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/sync/singleflight"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+
+	"github.com/kong/gateway-operator/internal/versions"
+	"github.com/kong/gateway-operator/modules/manager/metadata"
+)
+
+// DefaultCheckInterval is the default interval in which image repositories
+// are checked for new releases.
+const DefaultCheckInterval = time.Hour
+
+// TagLister lists the tags of an image repository, authenticating with the
+// provided credentials store. When authentication is not needed the store is nil.
+type TagLister interface {
+	ListTags(ctx context.Context, repository string, credentialsStore credentials.Store) ([]string, error)
+}
+
+// RegistryTagLister is a TagLister listing the tags of image repositories
+// from their registry.
+type RegistryTagLister struct{}
+
+// ListTags lists the tags of the provided image repository, e.g. "kong/kong-gateway".
+func (RegistryTagLister) ListTags(ctx context.Context, repository string, credentialsStore credentials.Store) ([]string, error) {
+	ref, err := name.NewRepository(repository)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected format of image repository %s: %w", repository, err)
+	}
+	registry, err := remote.NewRegistry(ref.RegistryStr())
+	if err != nil {
+		return nil, fmt.Errorf("for image repository: %s unexpected registry: %s, because: %w", repository, ref.RegistryStr(), err)
+	}
+	var credentialFunc auth.CredentialFunc
+	if credentialsStore != nil {
+		credentialFunc = credentials.Credential(credentialsStore)
+	}
+	registry.Client = &auth.Client{
+		Client:     auth.DefaultClient.Client,
+		Header:     map[string][]string{"User-Agent": {metadata.Metadata().UserAgent()}},
+		Cache:      auth.NewCache(),
+		Credential: credentialFunc,
+	}
+	repo, err := registry.Repository(ctx, ref.RepositoryStr())
+	if err != nil {
+		return nil, fmt.Errorf("for image repository: %s unexpected repository: %s, because: %w", repository, ref.RepositoryStr(), err)
+	}
+
+	var tags []string
+	if err := repo.Tags(ctx, "", func(page []string) error {
+		tags = append(tags, page...)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("can't list tags of image repository: %s, because: %w", repository, err)
+	}
+	return tags, nil
+}
+
+// Credentials are the credentials used to list the tags of image repositories.
+type Credentials struct {
+	// Store holds the credentials of registries, nil for anonymous access.
+	Store credentials.Store
+	// Key identifies the credentials, so that tags listed with different
+	// credentials are cached separately.
+	Key string
+}
+
+// Resolution is an image resolved from a version channel.
+type Resolution struct {
+	// Image is the resolved image.
+	Image string
+	// CheckedAt is the time at which the tags of the image repository were listed.
+	CheckedAt time.Time
+}
+
+// Resolver resolves the newest image of version channels. The tags of image
+// repositories are cached for the check interval so that many objects can track
+// the same channel without hitting the registry on every reconciliation, and
+// concurrent listings of the same repository are deduplicated.
+type Resolver struct {
+	lister        TagLister
+	checkInterval time.Duration
+	now           func() time.Time
+
+	lock  sync.Mutex
+	cache map[string]cachedTags
+	group singleflight.Group
+}
+
+type cachedTags struct {
+	tags      []string
+	checkedAt time.Time
+}
+
+// NewResolver creates a Resolver listing tags with the provided TagLister and
+// checking image repositories for new releases in the provided interval.
+func NewResolver(lister TagLister, checkInterval time.Duration) *Resolver {
+	return &Resolver{
+		lister:        lister,
+		checkInterval: checkInterval,
+		now:           time.Now,
+		cache:         make(map[string]cachedTags),
+	}
+}
+
+var defaultResolver = sync.OnceValue(func() *Resolver {
+	return NewResolver(RegistryTagLister{}, DefaultCheckInterval)
+})
+
+// ResolverOrDefault returns the provided Resolver, or the default Resolver
+// shared by the controllers when it's nil.
+func ResolverOrDefault(r *Resolver) *Resolver {
+	if r != nil {
+		return r
+	}
+	return defaultResolver()
+}
+
+// CheckInterval returns the interval in which image repositories are checked
+// for new releases.
+func (r *Resolver) CheckInterval() time.Duration {
+	return r.checkInterval
+}
+
+// Resolve returns the newest release of the provided version channel, e.g. "3.9.x",
+// in the repository of the provided image, listing its tags with the provided
+// credentials. Releases which are not supported according to the provided
+// validators are skipped.
+func (r *Resolver) Resolve(
+	ctx context.Context,
+	image string,
+	channel string,
+	creds Credentials,
+	validators ...versions.VersionValidationOption,
+) (Resolution, error) {
+	c, err := versions.ParseChannel(channel)
+	if err != nil {
+		return Resolution{}, err
+	}
+	repository, err := repositoryFromImage(image)
+	if err != nil {
+		return Resolution{}, err
+	}
+	tags, checkedAt, err := r.tags(ctx, repository, creds)
+	if err != nil {
+		return Resolution{}, err
+	}
+
+	for _, tag := range versions.ReleaseTagsInChannel(c, tags) {
+		candidate := repository + ":" + tag
+		if supported(candidate, validators) {
+			return Resolution{Image: candidate, CheckedAt: checkedAt}, nil
+		}
+	}
+	return Resolution{}, fmt.Errorf("no supported release of %s found in version channel %s", repository, c)
+}
+
+func (r *Resolver) tags(ctx context.Context, repository string, creds Credentials) ([]string, time.Time, error) {
+	key := repository
+	if creds.Key != "" {
+		key += "#" + creds.Key
+	}
+	if cached, ok := r.cached(key); ok {
+		return cached.tags, cached.checkedAt, nil
+	}
+
+	v, err, _ := r.group.Do(key, func() (any, error) {
+		// The tags may have been listed by a concurrent call which completed
+		// in the meantime.
+		if cached, ok := r.cached(key); ok {
+			return cached, nil
+		}
+		now := r.now()
+		tags, err := r.lister.ListTags(ctx, repository, creds.Store)
+		if err != nil {
+			return nil, err
+		}
+		cached := cachedTags{tags: tags, checkedAt: now}
+		r.lock.Lock()
+		r.cache[key] = cached
+		r.lock.Unlock()
+		return cached, nil
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	cached := v.(cachedTags)
+	return cached.tags, cached.checkedAt, nil
+}
+
+// cached returns the tags cached with the provided key if they have been
+// listed within the check interval.
+func (r *Resolver) cached(key string) (cachedTags, bool) {
+	r.lock.Lock()
+	cached, ok := r.cache[key]
+	r.lock.Unlock()
+	if !ok || !r.now().Before(cached.checkedAt.Add(r.checkInterval)) {
+		return cachedTags{}, false
+	}
+	return cached, true
+}
+
+func supported(image string, validators []versions.VersionValidationOption) bool {
+	for _, v := range validators {
+		if ok, err := v(image); err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// repositoryFromImage returns the repository of the provided image, i.e. the
+// image without its tag.
+func repositoryFromImage(image string) (string, error) {
+	if strings.Contains(image, "@") {
+		return "", fmt.Errorf("image %s referenced by digest can't track a version channel", image)
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], nil
+	}
+	return image, nil
+}
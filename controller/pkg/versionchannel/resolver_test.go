@@ -0,0 +1,180 @@
+package versionchannel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+
+	"github.com/kong/gateway-operator/internal/versions"
+)
+
+type fakeTagLister struct {
+	tags map[string][]string
+	err  error
+	// block, when set, blocks listing tags until it's closed.
+	block chan struct{}
+
+	lock  sync.Mutex
+	calls int
+	store credentials.Store
+}
+
+func (f *fakeTagLister) ListTags(_ context.Context, repository string, store credentials.Store) ([]string, error) {
+	if f.block != nil {
+		<-f.block
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.calls++
+	f.store = store
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.tags[repository], nil
+}
+
+func TestResolverResolve(t *testing.T) {
+	lister := &fakeTagLister{
+		tags: map[string][]string{
+			"kong/kong-gateway": {"3.8.1.0", "3.9.0.0", "3.9.1.1", "3.9.1.2", "3.10.0.0", "latest"},
+		},
+	}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	resolver := NewResolver(lister, time.Hour)
+	resolver.now = func() time.Time { return now }
+
+	t.Run("newest release of the channel", func(t *testing.T) {
+		resolution, err := resolver.Resolve(t.Context(), "kong/kong-gateway:3.9.0.0", "3.9.x", Credentials{})
+		require.NoError(t, err)
+		require.Equal(t, Resolution{Image: "kong/kong-gateway:3.9.1.2", CheckedAt: now}, resolution)
+		require.Equal(t, 1, lister.calls)
+	})
+
+	t.Run("tags are cached for the check interval", func(t *testing.T) {
+		resolution, err := resolver.Resolve(t.Context(), "kong/kong-gateway", "3.x", Credentials{})
+		require.NoError(t, err)
+		require.Equal(t, "kong/kong-gateway:3.10.0.0", resolution.Image)
+		require.Equal(t, 1, lister.calls)
+
+		now = now.Add(time.Hour)
+		resolution, err = resolver.Resolve(t.Context(), "kong/kong-gateway", "3.x", Credentials{})
+		require.NoError(t, err)
+		require.Equal(t, now, resolution.CheckedAt)
+		require.Equal(t, 2, lister.calls)
+	})
+
+	t.Run("unsupported releases are skipped", func(t *testing.T) {
+		notNewest := func(image string) (bool, error) {
+			return image != "kong/kong-gateway:3.9.1.2", nil
+		}
+		resolution, err := resolver.Resolve(t.Context(), "kong/kong-gateway:3.9", "3.9.x", Credentials{},
+			versions.VersionValidationOption(notNewest),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "kong/kong-gateway:3.9.1.1", resolution.Image)
+	})
+
+	t.Run("no release in the channel", func(t *testing.T) {
+		_, err := resolver.Resolve(t.Context(), "kong/kong-gateway", "4.x", Credentials{})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid channel", func(t *testing.T) {
+		_, err := resolver.Resolve(t.Context(), "kong/kong-gateway", "3.9", Credentials{})
+		require.Error(t, err)
+	})
+
+	t.Run("image referenced by digest", func(t *testing.T) {
+		_, err := resolver.Resolve(t.Context(), "kong/kong-gateway@sha256:abcd", "3.9.x", Credentials{})
+		require.Error(t, err)
+	})
+
+	t.Run("listing tags fails", func(t *testing.T) {
+		resolver := NewResolver(&fakeTagLister{err: errors.New("registry unavailable")}, time.Hour)
+		_, err := resolver.Resolve(t.Context(), "kong/kong-gateway", "3.9.x", Credentials{})
+		require.Error(t, err)
+	})
+}
+
+func TestResolverResolveConcurrently(t *testing.T) {
+	lister := &fakeTagLister{
+		tags: map[string][]string{
+			"kong/kong-gateway": {"3.9.0", "3.9.1"},
+		},
+		block: make(chan struct{}),
+	}
+	resolver := NewResolver(lister, time.Hour)
+
+	var (
+		wg       sync.WaitGroup
+		resolved atomic.Int32
+	)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resolution, err := resolver.Resolve(t.Context(), "kong/kong-gateway", "3.9.x", Credentials{}); err == nil &&
+				resolution.Image == "kong/kong-gateway:3.9.1" {
+				resolved.Add(1)
+			}
+		}()
+	}
+	// Let the calls pile up on the first listing.
+	time.Sleep(100 * time.Millisecond)
+	close(lister.block)
+	wg.Wait()
+
+	require.EqualValues(t, 10, resolved.Load())
+	require.Equal(t, 1, lister.calls)
+}
+
+func TestResolverResolveWithCredentials(t *testing.T) {
+	lister := &fakeTagLister{
+		tags: map[string][]string{
+			"registry.example.com/kong/kong-gateway": {"3.9.0", "3.9.1"},
+		},
+	}
+	resolver := NewResolver(lister, time.Hour)
+	store := credentials.NewMemoryStore()
+	creds := Credentials{Store: store, Key: "default/pull-secret@1"}
+
+	_, err := resolver.Resolve(t.Context(), "registry.example.com/kong/kong-gateway", "3.9.x", creds)
+	require.NoError(t, err)
+	require.Equal(t, 1, lister.calls)
+	require.Equal(t, store, lister.store)
+
+	t.Log("tags listed with credentials are cached for the same credentials only")
+	_, err = resolver.Resolve(t.Context(), "registry.example.com/kong/kong-gateway", "3.9.x", creds)
+	require.NoError(t, err)
+	require.Equal(t, 1, lister.calls)
+	_, err = resolver.Resolve(t.Context(), "registry.example.com/kong/kong-gateway", "3.9.x", Credentials{})
+	require.NoError(t, err)
+	require.Equal(t, 2, lister.calls)
+	require.Nil(t, lister.store)
+}
+
+func TestRepositoryFromImage(t *testing.T) {
+	testCases := []struct {
+		image      string
+		repository string
+	}{
+		{image: "kong/kong-gateway:3.9", repository: "kong/kong-gateway"},
+		{image: "kong/kong-gateway", repository: "kong/kong-gateway"},
+		{image: "registry.example.com:5000/kong/kong-gateway", repository: "registry.example.com:5000/kong/kong-gateway"},
+		{image: "registry.example.com:5000/kong/kong-gateway:3.9", repository: "registry.example.com:5000/kong/kong-gateway"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			repository, err := repositoryFromImage(tc.image)
+			require.NoError(t, err)
+			require.Equal(t, tc.repository, repository)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+// Package versionchannel implements automatic upgrades of DataPlane and
+// ControlPlane images to the newest release of a version channel, configured
+// with the consts.AnnotationVersionChannel annotation.
+package versionchannel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/internal/versions"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+)
+
+const (
+	// ConditionType is the type of the condition set on objects tracking
+	// a version channel, reporting the resolved image and the time of the last check.
+	ConditionType kcfgconsts.ConditionType = "VersionChannelResolved"
+
+	// ReasonResolved is the reason used with the VersionChannelResolved
+	// condition when an image has been resolved from the version channel.
+	ReasonResolved kcfgconsts.ConditionReason = "Resolved"
+	// ReasonResolutionFailed is the reason used with the VersionChannelResolved
+	// condition when no image could be resolved from the version channel.
+	ReasonResolutionFailed kcfgconsts.ConditionReason = "ResolutionFailed"
+)
+
+// resolutionRetryInterval is the interval in which failed resolutions are retried.
+const resolutionRetryInterval = time.Minute
+
+// EnsureImage resolves the image of the version channel tracked by the provided
+// object with the consts.AnnotationVersionChannel annotation, in the repository
+// of the provided image. The resolved image is recorded in the object's
+// consts.AnnotationVersionChannelResolvedImage annotation and reported, together
+// with the time of the last check, in its VersionChannelResolved condition.
+// The tags of the repository are listed with the credentials of the provided
+// image pull Secrets, from the object's namespace, like the kubelet pulls the
+// image. When the resolution fails, the previously resolved image is used.
+//
+// It returns the image to use, which is empty when the object doesn't track
+// a version channel or no image has been resolved yet, and a result requeuing
+// the object when the version channel has to be checked again.
+func EnsureImage[T interface {
+	client.Object
+	k8sutils.ConditionsAware
+}](
+	ctx context.Context,
+	cl client.Client,
+	resolver *Resolver,
+	obj T,
+	image string,
+	imagePullSecrets []corev1.LocalObjectReference,
+	validators ...versions.VersionValidationOption,
+) (string, ctrl.Result, error) {
+	channel, ok := obj.GetAnnotations()[consts.AnnotationVersionChannel]
+	if !ok {
+		return "", ctrl.Result{}, removeVersionChannelStatus(ctx, cl, obj)
+	}
+
+	var resolution Resolution
+	creds, err := credentialsFromImagePullSecrets(ctx, cl, obj.GetNamespace(), imagePullSecrets)
+	if err == nil {
+		resolution, err = resolver.Resolve(ctx, image, channel, creds, validators...)
+	}
+	if err != nil {
+		previous := obj.GetAnnotations()[consts.AnnotationVersionChannelResolvedImage]
+		status, reason := metav1.ConditionFalse, ReasonResolutionFailed
+		message := fmt.Sprintf("Failed resolving version channel %s: %v", channel, err)
+		if previous != "" {
+			// The previously resolved image is still in use.
+			status, reason = metav1.ConditionTrue, ReasonResolved
+			message = fmt.Sprintf("Using image %s previously resolved from version channel %s, failed checking for newer releases: %v",
+				previous, channel, err,
+			)
+		}
+		res, err := patch.StatusWithCondition(ctx, cl, obj, ConditionType, status, reason, message)
+		if err != nil || !res.IsZero() {
+			return previous, res, err
+		}
+		return previous, ctrl.Result{RequeueAfter: resolutionRetryInterval}, nil
+	}
+
+	if obj.GetAnnotations()[consts.AnnotationVersionChannelResolvedImage] != resolution.Image {
+		old := obj.DeepCopyObject().(T)
+		annotations := maps.Clone(obj.GetAnnotations())
+		annotations[consts.AnnotationVersionChannelResolvedImage] = resolution.Image
+		obj.SetAnnotations(annotations)
+		if err := cl.Patch(ctx, obj, client.MergeFrom(old)); err != nil {
+			return "", ctrl.Result{}, fmt.Errorf("failed recording image resolved from version channel %s: %w", channel, err)
+		}
+	}
+
+	res, err := patch.StatusWithCondition(ctx, cl, obj, ConditionType, metav1.ConditionTrue, ReasonResolved,
+		fmt.Sprintf("Resolved image %s from version channel %s, last checked at %s",
+			resolution.Image, channel, resolution.CheckedAt.UTC().Format(time.RFC3339),
+		),
+	)
+	if err != nil || !res.IsZero() {
+		return resolution.Image, res, err
+	}
+	// Nothing changes when new releases are published so requeue to check for them.
+	nextCheck := resolution.CheckedAt.Add(resolver.CheckInterval())
+	return resolution.Image, ctrl.Result{RequeueAfter: max(time.Until(nextCheck), time.Second)}, nil
+}
+
+// credentialsFromImagePullSecrets returns the registry credentials held by the
+// provided image pull Secrets. Like the kubelet does, Secrets which don't exist
+// or aren't of the kubernetes.io/dockerconfigjson type are ignored and the
+// credentials of the first Secret holding ones for a registry are used.
+func credentialsFromImagePullSecrets(
+	ctx context.Context,
+	cl client.Client,
+	namespace string,
+	imagePullSecrets []corev1.LocalObjectReference,
+) (Credentials, error) {
+	var (
+		stores imagePullSecretsStore
+		keys   []string
+	)
+	for _, ref := range imagePullSecrets {
+		var secret corev1.Secret
+		if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, &secret); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return Credentials{}, fmt.Errorf("failed getting image pull Secret %s/%s: %w", namespace, ref.Name, err)
+		}
+		data, ok := secret.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			continue
+		}
+		store, err := credentials.NewMemoryStoreFromDockerConfig(data)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("can't parse image pull Secret %s/%s: %w", namespace, ref.Name, err)
+		}
+		stores = append(stores, store)
+		// The resource version is part of the key so that rotated credentials
+		// are used right away.
+		keys = append(keys, secret.Namespace+"/"+secret.Name+"@"+secret.ResourceVersion)
+	}
+	if len(stores) == 0 {
+		return Credentials{}, nil
+	}
+	return Credentials{Store: stores, Key: strings.Join(keys, ",")}, nil
+}
+
+// imagePullSecretsStore is a read-only credentials.Store returning, for each
+// registry, the credentials of the first image pull Secret holding ones.
+type imagePullSecretsStore []credentials.Store
+
+var errReadOnlyStore = errors.New("credentials of image pull Secrets are read-only")
+
+// Get returns the credentials of the provided registry.
+func (s imagePullSecretsStore) Get(ctx context.Context, serverAddress string) (auth.Credential, error) {
+	for _, store := range s {
+		cred, err := store.Get(ctx, serverAddress)
+		if err != nil {
+			return auth.EmptyCredential, err
+		}
+		if cred != auth.EmptyCredential {
+			return cred, nil
+		}
+	}
+	return auth.EmptyCredential, nil
+}
+
+// Put always fails as the store is read-only.
+func (imagePullSecretsStore) Put(context.Context, string, auth.Credential) error {
+	return errReadOnlyStore
+}
+
+// Delete always fails as the store is read-only.
+func (imagePullSecretsStore) Delete(context.Context, string) error {
+	return errReadOnlyStore
+}
+
+// removeVersionChannelStatus removes the version channel annotation and condition
+// set by EnsureImage from objects which don't track a version channel anymore.
+func removeVersionChannelStatus[T interface {
+	client.Object
+	k8sutils.ConditionsAware
+}](ctx context.Context, cl client.Client, obj T) error {
+	if _, ok := obj.GetAnnotations()[consts.AnnotationVersionChannelResolvedImage]; ok {
+		old := obj.DeepCopyObject().(T)
+		annotations := maps.Clone(obj.GetAnnotations())
+		delete(annotations, consts.AnnotationVersionChannelResolvedImage)
+		obj.SetAnnotations(annotations)
+		if err := cl.Patch(ctx, obj, client.MergeFrom(old)); err != nil {
+			return fmt.Errorf("failed removing %s annotation: %w", consts.AnnotationVersionChannelResolvedImage, err)
+		}
+	}
+
+	if !k8sutils.HasCondition(ConditionType, obj) {
+		return nil
+	}
+	old := obj.DeepCopyObject().(T)
+	obj.SetConditions(lo.Reject(obj.GetConditions(), func(c metav1.Condition, _ int) bool {
+		return c.Type == string(ConditionType)
+	}))
+	if err := cl.Status().Patch(ctx, obj, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed removing %s condition: %w", ConditionType, err)
+	}
+	return nil
+}
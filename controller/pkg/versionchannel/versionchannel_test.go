@@ -0,0 +1,161 @@
+package versionchannel
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestEnsureImage(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+			Annotations: map[string]string{
+				consts.AnnotationVersionChannel: "3.9.x",
+			},
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(dataplane).
+		WithStatusSubresource(dataplane).
+		Build()
+	lister := &fakeTagLister{
+		tags: map[string][]string{
+			"kong/kong-gateway": {"3.9.0", "3.9.1"},
+		},
+	}
+	now := time.Now()
+	resolver := NewResolver(lister, time.Hour)
+	resolver.now = func() time.Time { return now }
+
+	requireCondition := func(t *testing.T, status metav1.ConditionStatus, reason string) {
+		t.Helper()
+		var dp operatorv1beta1.DataPlane
+		require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), &dp))
+		c, ok := k8sutils.GetCondition(ConditionType, &dp)
+		require.True(t, ok)
+		require.Equal(t, status, c.Status)
+		require.Equal(t, reason, c.Reason)
+	}
+
+	t.Run("resolves the newest release", func(t *testing.T) {
+		image, res, err := EnsureImage(t.Context(), cl, resolver, dataplane, "kong/kong-gateway:3.9.0", nil)
+		require.NoError(t, err)
+		require.Equal(t, "kong/kong-gateway:3.9.1", image)
+		require.InDelta(t, time.Hour, res.RequeueAfter, float64(time.Minute))
+		require.Equal(t, "kong/kong-gateway:3.9.1", dataplane.Annotations[consts.AnnotationVersionChannelResolvedImage])
+		requireCondition(t, metav1.ConditionTrue, string(ReasonResolved))
+	})
+
+	t.Run("uses the previously resolved image when the resolution fails", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		lister.err = errors.New("registry unavailable")
+		image, res, err := EnsureImage(t.Context(), cl, resolver, dataplane, "kong/kong-gateway:3.9.0", nil)
+		require.NoError(t, err)
+		require.Equal(t, "kong/kong-gateway:3.9.1", image)
+		require.Equal(t, resolutionRetryInterval, res.RequeueAfter)
+		requireCondition(t, metav1.ConditionTrue, string(ReasonResolved))
+	})
+
+	t.Run("reports failures when no image has been resolved", func(t *testing.T) {
+		dataplane.Annotations[consts.AnnotationVersionChannel] = "4.x"
+		delete(dataplane.Annotations, consts.AnnotationVersionChannelResolvedImage)
+		lister.err = nil
+		image, _, err := EnsureImage(t.Context(), cl, resolver, dataplane, "kong/kong-gateway:3.9.0", nil)
+		require.NoError(t, err)
+		require.Empty(t, image)
+		requireCondition(t, metav1.ConditionFalse, string(ReasonResolutionFailed))
+	})
+
+	t.Run("removes the status when the version channel is removed", func(t *testing.T) {
+		dataplane.Annotations[consts.AnnotationVersionChannel] = "3.9.x"
+		_, _, err := EnsureImage(t.Context(), cl, resolver, dataplane, "kong/kong-gateway:3.9.0", nil)
+		require.NoError(t, err)
+
+		delete(dataplane.Annotations, consts.AnnotationVersionChannel)
+		image, res, err := EnsureImage(t.Context(), cl, resolver, dataplane, "kong/kong-gateway:3.9.0", nil)
+		require.NoError(t, err)
+		require.Empty(t, image)
+		require.Zero(t, res)
+
+		var dp operatorv1beta1.DataPlane
+		require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(dataplane), &dp))
+		require.NotContains(t, dp.Annotations, consts.AnnotationVersionChannelResolvedImage)
+		require.False(t, k8sutils.HasCondition(ConditionType, &dp))
+	})
+}
+
+func TestCredentialsFromImagePullSecrets(t *testing.T) {
+	secret := func(name string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Data:       data,
+		}
+	}
+	dockerConfig := func(registry, username string) map[string][]byte {
+		return map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"` + registry + `":{"username":"` + username + `","password":"password"}}}`),
+		}
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(
+			secret("first", dockerConfig("registry.example.com", "first")),
+			secret("second", dockerConfig("registry.example.com", "second")),
+			secret("other", dockerConfig("other.example.com", "other")),
+			secret("opaque", map[string][]byte{"key": []byte("value")}),
+			secret("invalid", map[string][]byte{corev1.DockerConfigJsonKey: []byte("invalid")}),
+		).
+		Build()
+
+	t.Run("no image pull Secrets", func(t *testing.T) {
+		creds, err := credentialsFromImagePullSecrets(t.Context(), cl, "default", nil)
+		require.NoError(t, err)
+		require.Equal(t, Credentials{}, creds)
+	})
+
+	t.Run("credentials of the first Secret holding ones for a registry are used", func(t *testing.T) {
+		creds, err := credentialsFromImagePullSecrets(t.Context(), cl, "default", []corev1.LocalObjectReference{
+			{Name: "missing"}, {Name: "opaque"}, {Name: "other"}, {Name: "first"}, {Name: "second"},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, creds.Key)
+
+		cred, err := creds.Store.Get(t.Context(), "registry.example.com")
+		require.NoError(t, err)
+		require.Equal(t, "first", cred.Username)
+		cred, err = creds.Store.Get(t.Context(), "other.example.com")
+		require.NoError(t, err)
+		require.Equal(t, "other", cred.Username)
+		cred, err = creds.Store.Get(t.Context(), "unknown.example.com")
+		require.NoError(t, err)
+		require.Empty(t, cred.Username)
+	})
+
+	t.Run("Secrets which don't hold credentials are ignored", func(t *testing.T) {
+		creds, err := credentialsFromImagePullSecrets(t.Context(), cl, "default", []corev1.LocalObjectReference{
+			{Name: "missing"}, {Name: "opaque"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, Credentials{}, creds)
+	})
+
+	t.Run("invalid Secret", func(t *testing.T) {
+		_, err := credentialsFromImagePullSecrets(t.Context(), cl, "default", []corev1.LocalObjectReference{{Name: "invalid"}})
+		require.Error(t, err)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,6 +16,7 @@ import (
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/watch"
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
 	"github.com/kong/gateway-operator/internal/utils/gatewayclass"
@@ -35,10 +37,14 @@ type AIGatewayReconciler struct {
 
 	Scheme      *runtime.Scheme
 	LoggingMode logging.Mode
+
+	eventRecorder record.EventRecorder
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AIGatewayReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("aigateway")
+
 	return ctrl.NewControllerManagedBy(mgr).
 		// watch AIGateway objects, filtering out any Gateways which are not
 		// configured with a supported GatewayClass controller name.
@@ -105,6 +111,14 @@ func (r *AIGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
+	log.Trace(logger, "checking if aigateway reconciliation is paused")
+	if paused, res, err := pause.Reconcile(ctx, r.Client, r.eventRecorder, &aigateway, &aigateway); err != nil || paused || !res.IsZero() {
+		if paused {
+			log.Debug(logger, "aigateway reconciliation is paused")
+		}
+		return res, err
+	}
+
 	log.Trace(logger, "marking aigateway as accepted")
 	oldAIGateway := aigateway.DeepCopy()
 	k8sutils.SetCondition(newAIGatewayAcceptedCondition(&aigateway), &aigateway)
@@ -13,6 +13,11 @@ import (
 type Recorder interface {
 	RecordKonnectEntityOperationSuccess(serverURL string, operationType KonnectEntityOperation, entityType string, duration time.Duration)
 	RecordKonnectEntityOperationFailure(serverURL string, operationType KonnectEntityOperation, entityType string, duration time.Duration, statusCode int)
+	RecordKonnectOrphanedEntities(serverURL string, controlPlaneID string, entityType string, count int)
+	ForgetKonnectOrphanedEntities(controlPlaneID string)
+	RecordKonnectAPITokenExpiry(serverURL string, apiAuthConfiguration string, token string, expiresAt time.Time)
+	ForgetKonnectAPITokenExpiry(apiAuthConfiguration string)
+	RecordKonnectCloudGatewayProvisioningDuration(serverURL string, entityType string, duration time.Duration)
 }
 
 // KonnectEntityOperation specifies the type of Konnect entity operation, including `create`, `update`, and `delete`.
@@ -38,6 +43,12 @@ const (
 	// It is always `0` for successful operations.
 	// When the opertion fails, it will be the actual status code if we can get it. Otherwise it will also be `0`.
 	StatusCodeKey = "status_code"
+	// KonnectControlPlaneIDKey is the key for the ID of the Konnect ControlPlane.
+	KonnectControlPlaneIDKey = "control_plane_id"
+	// KonnectAPIAuthConfigurationKey is the key for the namespaced name of the KonnectAPIAuthConfiguration.
+	KonnectAPIAuthConfigurationKey = "api_auth_configuration"
+	// KonnectAPITokenKey is the key for the Konnect API token: `primary` or `secondary`.
+	KonnectAPITokenKey = "token"
 )
 
 // metric names for konnect entity operations.
@@ -46,6 +57,15 @@ const (
 	MetricNameKonnectEntityOperationCount = "gateway_operator_konnect_entity_operation_count"
 	// MetricNameKonnectEntityOperationDuration is the metric of durations of the operations.
 	MetricNameKonnectEntityOperationDuration = "gateway_operator_konnect_entity_operation_duration_milliseconds"
+	// MetricNameKonnectOrphanedEntities is the metric of number of orphaned entities in Konnect ControlPlanes,
+	// grouped by server URL, ControlPlane ID and entity type.
+	MetricNameKonnectOrphanedEntities = "gateway_operator_konnect_orphaned_entities"
+	// MetricNameKonnectAPITokenExpiry is the metric of the expiry time of Konnect API tokens,
+	// grouped by server URL, KonnectAPIAuthConfiguration and token.
+	MetricNameKonnectAPITokenExpiry = "gateway_operator_konnect_api_token_expiry_timestamp_seconds"
+	// MetricNameKonnectCloudGatewayProvisioningDuration is the metric of durations of provisioning
+	// of Konnect Cloud Gateway resources, grouped by server URL and entity type.
+	MetricNameKonnectCloudGatewayProvisioningDuration = "gateway_operator_konnect_cloud_gateway_provisioning_duration_seconds"
 )
 
 var (
@@ -94,6 +114,54 @@ var (
 		},
 		[]string{KonnectServerURLKey, KonnectEntityOperationTypeKey, KonnectEntityTypeKey, SuccessKey, StatusCodeKey},
 	)
+
+	konnectOrphanedEntities = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricNameKonnectOrphanedEntities,
+			Help: fmt.Sprintf(
+				"Number of Konnect entities tagged with the UID of a Kubernetes object which does not exist anymore. "+
+					"`%s` describes the URL of the Konnect server. "+
+					"`%s` describes the ID of the Konnect ControlPlane. "+
+					"`%s` describes the type of the orphaned entities.",
+				KonnectServerURLKey,
+				KonnectControlPlaneIDKey,
+				KonnectEntityTypeKey,
+			),
+		},
+		[]string{KonnectServerURLKey, KonnectControlPlaneIDKey, KonnectEntityTypeKey},
+	)
+
+	konnectAPITokenExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricNameKonnectAPITokenExpiry,
+			Help: fmt.Sprintf(
+				"Unix time at which the Konnect API token declared in a KonnectAPIAuthConfiguration expires. "+
+					"`%s` describes the URL of the Konnect server. "+
+					"`%s` describes the namespaced name of the KonnectAPIAuthConfiguration. "+
+					"`%s` describes the token (`primary` or `secondary`).",
+				KonnectServerURLKey,
+				KonnectAPIAuthConfigurationKey,
+				KonnectAPITokenKey,
+			),
+		},
+		[]string{KonnectServerURLKey, KonnectAPIAuthConfigurationKey, KonnectAPITokenKey},
+	)
+
+	konnectCloudGatewayProvisioningDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: MetricNameKonnectCloudGatewayProvisioningDuration,
+			Help: fmt.Sprintf(
+				"How long did it take for a Konnect Cloud Gateway resource to become ready in seconds. "+
+					"`%s` describes the URL of the Konnect server. "+
+					"`%s` describes the type of the provisioned entity.",
+				KonnectServerURLKey,
+				KonnectEntityTypeKey,
+			),
+			// Duration range from 1s to 2h.
+			Buckets: prometheus.ExponentialBucketsRange(1, 2*time.Hour.Seconds(), 15),
+		},
+		[]string{KonnectServerURLKey, KonnectEntityTypeKey},
+	)
 )
 
 // GlobalCtrlRuntimeMetricsRecorder is a metrics recorder that uses a global Prometheus registry
@@ -140,6 +208,52 @@ func (r *GlobalCtrlRuntimeMetricsRecorder) recordKonnectEntityOperationDuration(
 	konnectEntityOperationDuration.With(labels).Observe(duration.Seconds())
 }
 
+// RecordKonnectOrphanedEntities is called with the number of orphaned entities of a type found in a Konnect ControlPlane.
+func (r *GlobalCtrlRuntimeMetricsRecorder) RecordKonnectOrphanedEntities(
+	serverURL string, controlPlaneID string, entityType string, count int,
+) {
+	konnectOrphanedEntities.With(prometheus.Labels{
+		KonnectServerURLKey:      serverURL,
+		KonnectControlPlaneIDKey: controlPlaneID,
+		KonnectEntityTypeKey:     entityType,
+	}).Set(float64(count))
+}
+
+// ForgetKonnectOrphanedEntities is called when orphaned entities of a Konnect ControlPlane are not looked for anymore.
+func (r *GlobalCtrlRuntimeMetricsRecorder) ForgetKonnectOrphanedEntities(controlPlaneID string) {
+	konnectOrphanedEntities.DeletePartialMatch(prometheus.Labels{
+		KonnectControlPlaneIDKey: controlPlaneID,
+	})
+}
+
+// RecordKonnectAPITokenExpiry is called with the expiry time of a Konnect API token.
+func (r *GlobalCtrlRuntimeMetricsRecorder) RecordKonnectAPITokenExpiry(
+	serverURL string, apiAuthConfiguration string, token string, expiresAt time.Time,
+) {
+	konnectAPITokenExpiry.With(prometheus.Labels{
+		KonnectServerURLKey:            serverURL,
+		KonnectAPIAuthConfigurationKey: apiAuthConfiguration,
+		KonnectAPITokenKey:             token,
+	}).Set(float64(expiresAt.Unix()))
+}
+
+// ForgetKonnectAPITokenExpiry is called when the expiry of the tokens of a KonnectAPIAuthConfiguration is not known anymore.
+func (r *GlobalCtrlRuntimeMetricsRecorder) ForgetKonnectAPITokenExpiry(apiAuthConfiguration string) {
+	konnectAPITokenExpiry.DeletePartialMatch(prometheus.Labels{
+		KonnectAPIAuthConfigurationKey: apiAuthConfiguration,
+	})
+}
+
+// RecordKonnectCloudGatewayProvisioningDuration is called when a Konnect Cloud Gateway resource becomes ready.
+func (r *GlobalCtrlRuntimeMetricsRecorder) RecordKonnectCloudGatewayProvisioningDuration(
+	serverURL string, entityType string, duration time.Duration,
+) {
+	konnectCloudGatewayProvisioningDuration.With(prometheus.Labels{
+		KonnectServerURLKey:  serverURL,
+		KonnectEntityTypeKey: entityType,
+	}).Observe(duration.Seconds())
+}
+
 // konnectEntityOperationLabels generates the labels for recording metrics about Konnect entity opertions,
 // including: server URL, operation type, entity type, whether the opertion succeeded, and status code.
 func konnectEntityOperationLabels(
@@ -164,6 +278,9 @@ func init() {
 	allMetrics := []prometheus.Collector{
 		konnectEntityOperationCount,
 		konnectEntityOperationDuration,
+		konnectOrphanedEntities,
+		konnectAPITokenExpiry,
+		konnectCloudGatewayProvisioningDuration,
 	}
 	for _, m := range allMetrics {
 		ctrlmetrics.Registry.MustRegister(m)
@@ -185,3 +302,21 @@ func (m *MockRecorder) RecordKonnectEntityOperationSuccess(
 func (m *MockRecorder) RecordKonnectEntityOperationFailure(
 	serverURL string, operationType KonnectEntityOperation, entityType string, duration time.Duration, statusCode int) {
 }
+
+func (m *MockRecorder) RecordKonnectOrphanedEntities(
+	serverURL string, controlPlaneID string, entityType string, count int) {
+}
+
+func (m *MockRecorder) ForgetKonnectOrphanedEntities(controlPlaneID string) {
+}
+
+func (m *MockRecorder) RecordKonnectAPITokenExpiry(
+	serverURL string, apiAuthConfiguration string, token string, expiresAt time.Time) {
+}
+
+func (m *MockRecorder) ForgetKonnectAPITokenExpiry(apiAuthConfiguration string) {
+}
+
+func (m *MockRecorder) RecordKonnectCloudGatewayProvisioningDuration(
+	serverURL string, entityType string, duration time.Duration) {
+}
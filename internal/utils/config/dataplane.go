@@ -102,6 +102,51 @@ func KongInKonnectDefaults(
 	return newEnvSet
 }
 
+// HybridClusterConfig is the configuration of a self-hosted Kong control plane
+// running in hybrid mode, which the DataPlane joins as a data plane.
+type HybridClusterConfig struct {
+	// ControlPlane is the host:port of the control plane's cluster listener.
+	ControlPlane string
+	// ServerName is the SNI used when connecting to the control plane.
+	ServerName string
+	// TelemetryEndpoint is the host:port of the control plane's telemetry listener.
+	TelemetryEndpoint string
+	// TelemetryServerName is the SNI used when connecting to the telemetry endpoint.
+	TelemetryServerName string
+	// MTLS is the cluster mTLS mode, either "shared" or "pki".
+	MTLS string
+	// CACertPath is the path of the CA certificate used to verify the
+	// control plane's certificate in the "pki" mode.
+	CACertPath string
+}
+
+// KongInHybridDefaults returns the map of env vars configuring the proxy as a data plane
+// of the provided self-hosted Kong control plane running in hybrid mode.
+func KongInHybridDefaults(cfg HybridClusterConfig) map[string]string {
+	envSet := map[string]string{
+		"KONG_ROLE":                        "data_plane",
+		"KONG_CLUSTER_MTLS":                cfg.MTLS,
+		"KONG_CLUSTER_CONTROL_PLANE":       cfg.ControlPlane,
+		"KONG_CLUSTER_CERT":                consts.KongClusterCertVolumeMountPath + "/tls.crt",
+		"KONG_CLUSTER_CERT_KEY":            consts.KongClusterCertVolumeMountPath + "/tls.key",
+		"KONG_LUA_SSL_TRUSTED_CERTIFICATE": "system",
+	}
+	if cfg.ServerName != "" {
+		envSet["KONG_CLUSTER_SERVER_NAME"] = cfg.ServerName
+	}
+	if cfg.TelemetryEndpoint != "" {
+		envSet["KONG_CLUSTER_TELEMETRY_ENDPOINT"] = cfg.TelemetryEndpoint
+	}
+	if cfg.TelemetryServerName != "" {
+		envSet["KONG_CLUSTER_TELEMETRY_SERVER_NAME"] = cfg.TelemetryServerName
+	}
+	if cfg.CACertPath != "" {
+		envSet["KONG_CLUSTER_CA_CERT"] = cfg.CACertPath
+		envSet["KONG_LUA_SSL_TRUSTED_CERTIFICATE"] = "system," + cfg.CACertPath
+	}
+	return envSet
+}
+
 func sanitizeEndpoint(endpoint string) string {
 	return strings.TrimPrefix(endpoint, "https://")
 }
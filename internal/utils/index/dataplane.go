@@ -3,6 +3,8 @@ package index
 import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/kong/gateway-operator/pkg/consts"
+
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
 )
 
@@ -10,6 +12,10 @@ const (
 	// KongPluginInstallationsIndex is the key to be used to access the .spec.pluginsToInstall indexed values,
 	// in a form of list of namespace/name strings.
 	KongPluginInstallationsIndex = "KongPluginInstallations"
+
+	// HybridControlPlaneConfigMapIndex is the key to be used to access the names of the
+	// ConfigMaps referenced by DataPlanes through the hybrid control plane annotation.
+	HybridControlPlaneConfigMapIndex = "HybridControlPlaneConfigMap"
 )
 
 // DataPlaneFlags contains flags that control which indexes are created for the DataPlane object.
@@ -19,7 +25,13 @@ type DataPlaneFlags struct {
 }
 
 func OptionsForDataPlane(flags DataPlaneFlags) []Option {
-	var opts []Option
+	opts := []Option{
+		{
+			Object:         &operatorv1beta1.DataPlane{},
+			Field:          HybridControlPlaneConfigMapIndex,
+			ExtractValueFn: hybridControlPlaneConfigMapOnDataPlane,
+		},
+	}
 
 	if flags.KonnectControllersEnabled {
 		opts = append(opts, Option{
@@ -55,3 +67,16 @@ func kongPluginInstallationsOnDataPlane(o client.Object) []string {
 	}
 	return result
 }
+
+// hybridControlPlaneConfigMapOnDataPlane indexes the name of the ConfigMap referenced
+// by the DataPlane through the consts.AnnotationDataPlaneHybridControlPlane annotation.
+func hybridControlPlaneConfigMapOnDataPlane(o client.Object) []string {
+	dp, ok := o.(*operatorv1beta1.DataPlane)
+	if !ok {
+		return nil
+	}
+	if cm := dp.Annotations[consts.AnnotationDataPlaneHybridControlPlane]; cm != "" {
+		return []string{cm}
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/kong/gateway-operator/pkg/consts"
+
 	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
 )
 
@@ -16,6 +18,9 @@ const (
 
 	// IndexFieldKonnectGatewayControlPlaneOnKonnectID is the index field for KonnectGatewayControlPlane -> KonnectID.
 	IndexFieldKonnectGatewayControlPlaneOnKonnectID = "konnectGatewayControlPlaneKonnectID"
+
+	// IndexFieldKonnectGatewayControlPlaneOnGroupRef is the index field for KonnectGatewayControlPlane -> the group it declares to be a member of.
+	IndexFieldKonnectGatewayControlPlaneOnGroupRef = "konnectGatewayControlPlaneGroupRef"
 )
 
 // OptionsForKonnectGatewayControlPlane returns required Index options for KonnectGatewayControlPlane reconciler.
@@ -36,6 +41,11 @@ func OptionsForKonnectGatewayControlPlane() []Option {
 			Field:          IndexFieldKonnectGatewayControlPlaneOnKonnectID,
 			ExtractValueFn: konnectGatewayControlPlaneKonnectID,
 		},
+		{
+			Object:         &konnectv1alpha1.KonnectGatewayControlPlane{},
+			Field:          IndexFieldKonnectGatewayControlPlaneOnGroupRef,
+			ExtractValueFn: konnectGatewayControlPlaneGroupRef,
+		},
 	}
 }
 
@@ -81,3 +91,15 @@ func konnectGatewayControlPlaneKonnectID(object client.Object) []string {
 	}
 	return nil
 }
+
+func konnectGatewayControlPlaneGroupRef(object client.Object) []string {
+	cp, ok := object.(*konnectv1alpha1.KonnectGatewayControlPlane)
+	if !ok {
+		return nil
+	}
+
+	if group := cp.GetAnnotations()[consts.KonnectControlPlaneGroupAnnotationKey]; group != "" {
+		return []string{group}
+	}
+	return nil
+}
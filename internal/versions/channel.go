@@ -0,0 +1,89 @@
+package versions
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kong/semver/v4"
+)
+
+// Channel is a version channel, tracking the newest releases of a major or
+// a minor version, e.g. "3.9.x" tracks the newest 3.9 patch release.
+type Channel struct {
+	major uint64
+	minor *uint64
+}
+
+// releaseTagRE matches the tags of releases: full semver versions, with the
+// additional Kong enterprise revision segment, but without flavour suffixes.
+var releaseTagRE = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+(\.[0-9]+)?$`)
+
+// ParseChannel parses a version channel in the "<major>.x" or "<major>.<minor>.x"
+// format.
+func ParseChannel(channel string) (Channel, error) {
+	segments := strings.Split(channel, ".")
+	if len(segments) < 2 || len(segments) > 3 || segments[len(segments)-1] != "x" {
+		return Channel{}, fmt.Errorf(`invalid version channel %q: expected "<major>.x" or "<major>.<minor>.x" format`, channel)
+	}
+
+	var (
+		c   Channel
+		err error
+	)
+	if c.major, err = strconv.ParseUint(segments[0], 10, 64); err != nil {
+		return Channel{}, fmt.Errorf("invalid version channel %q: invalid major version: %w", channel, err)
+	}
+	if len(segments) == 3 {
+		minor, err := strconv.ParseUint(segments[1], 10, 64)
+		if err != nil {
+			return Channel{}, fmt.Errorf("invalid version channel %q: invalid minor version: %w", channel, err)
+		}
+		c.minor = &minor
+	}
+	return c, nil
+}
+
+// String returns the version channel in the format accepted by ParseChannel.
+func (c Channel) String() string {
+	if c.minor == nil {
+		return fmt.Sprintf("%d.x", c.major)
+	}
+	return fmt.Sprintf("%d.%d.x", c.major, *c.minor)
+}
+
+// Contains returns true if the provided version belongs to the channel.
+func (c Channel) Contains(v semver.Version) bool {
+	return v.Major == c.major && (c.minor == nil || v.Minor == *c.minor)
+}
+
+// ReleaseTagsInChannel returns the tags of the provided image tags which are
+// releases belonging to the channel, sorted from the newest to the oldest.
+func ReleaseTagsInChannel(channel Channel, tags []string) []string {
+	type release struct {
+		tag     string
+		version semver.Version
+	}
+	var releases []release
+	for _, tag := range tags {
+		if !releaseTagRE.MatchString(tag) {
+			continue
+		}
+		v, err := FromImage("image:" + tag)
+		if err != nil || !channel.Contains(v) {
+			continue
+		}
+		releases = append(releases, release{tag: tag, version: v})
+	}
+	slices.SortStableFunc(releases, func(a, b release) int {
+		return b.version.Compare(a.version)
+	})
+
+	result := make([]string, 0, len(releases))
+	for _, r := range releases {
+		result = append(result, r.tag)
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package versions
+
+import (
+	"testing"
+
+	"github.com/kong/semver/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChannel(t *testing.T) {
+	testCases := []struct {
+		channel       string
+		expectedError bool
+	}{
+		{channel: "3.9.x"},
+		{channel: "3.x"},
+		{channel: "3.9", expectedError: true},
+		{channel: "3.9.1", expectedError: true},
+		{channel: "x", expectedError: true},
+		{channel: "3.9.1.x", expectedError: true},
+		{channel: "a.9.x", expectedError: true},
+		{channel: "3.b.x", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.channel, func(t *testing.T) {
+			c, err := ParseChannel(tc.channel)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.channel, c.String())
+		})
+	}
+}
+
+func TestChannelContains(t *testing.T) {
+	minorChannel, err := ParseChannel("3.9.x")
+	require.NoError(t, err)
+	majorChannel, err := ParseChannel("3.x")
+	require.NoError(t, err)
+
+	require.True(t, minorChannel.Contains(semver.MustParse("3.9.0")))
+	require.True(t, minorChannel.Contains(semver.MustParse("3.9.12")))
+	require.False(t, minorChannel.Contains(semver.MustParse("3.10.0")))
+	require.False(t, minorChannel.Contains(semver.MustParse("4.9.0")))
+	require.True(t, majorChannel.Contains(semver.MustParse("3.10.0")))
+	require.False(t, majorChannel.Contains(semver.MustParse("4.0.0")))
+}
+
+func TestReleaseTagsInChannel(t *testing.T) {
+	tags := []string{
+		"latest",
+		"3.8.4",
+		"3.9",
+		"3.9.0",
+		"3.9.1",
+		"3.9.1-ubuntu",
+		"3.9.10",
+		"3.9.2.1",
+		"3.9.2",
+		"3.10.0",
+		"3.10.0-rc.1",
+	}
+
+	channel, err := ParseChannel("3.9.x")
+	require.NoError(t, err)
+	require.Equal(t,
+		[]string{"3.9.10", "3.9.2.1", "3.9.2", "3.9.1", "3.9.0"},
+		ReleaseTagsInChannel(channel, tags),
+	)
+
+	channel, err = ParseChannel("3.x")
+	require.NoError(t, err)
+	require.Equal(t, "3.10.0", ReleaseTagsInChannel(channel, tags)[0])
+
+	channel, err = ParseChannel("4.x")
+	require.NoError(t, err)
+	require.Empty(t, ReleaseTagsInChannel(channel, tags))
+}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/samber/lo"
@@ -56,6 +57,8 @@ func New(m metadata.Info) *CLI {
 	flagSet.BoolVar(&cfg.DataPlaneControllerEnabled, "enable-controller-dataplane", true, "Enable the DataPlane controller.")
 	flagSet.BoolVar(&cfg.DataPlaneBlueGreenControllerEnabled, "enable-controller-dataplane-bluegreen", true, "Enable the DataPlane BlueGreen controller. Mutually exclusive with DataPlane controller.")
 	flagSet.BoolVar(&cfg.ControlPlaneExtensionsControllerEnabled, "enable-controller-controlplaneextensions", true, "Enable the ControlPlane extensions controller.")
+	flagSet.BoolVar(&cfg.CustomMetricsAPIEnabled, "enable-custom-metrics-api", false, "Serve the custom metrics API (custom.metrics.k8s.io) with metrics scraped from DataPlanes. Requires the ControlPlane extensions controller.")
+	flagSet.StringVar(&cfg.CustomMetricsAPIAddr, "custom-metrics-api-bind-address", ":6443", "The address the custom metrics API server binds to.")
 
 	// controllers for specialized APIs and features
 	flagSet.BoolVar(&cfg.AIGatewayControllerEnabled, "enable-controller-aigateway", false, "Enable the AIGateway controller. (Experimental).")
@@ -66,6 +69,10 @@ func New(m metadata.Info) *CLI {
 	flagSet.BoolVar(&cfg.KonnectControllersEnabled, "enable-controller-konnect", false, "Enable the Konnect controllers.")
 	flagSet.DurationVar(&cfg.KonnectSyncPeriod, "konnect-sync-period", consts.DefaultKonnectSyncPeriod, "Sync period for Konnect entities. After a successful reconciliation of Konnect entities the controller will wait this duration before enforcing configuration on Konnect once again.")
 	flagSet.UintVar(&cfg.KonnectMaxConcurrentReconciles, "konnect-controller-max-concurrent-reconciles", consts.DefaultKonnectMaxConcurrentReconciles, "Maximum number of concurrent reconciles for Konnect entities.")
+	flagSet.DurationVar(&cfg.KonnectOrphanedEntitiesGCPeriod, "konnect-orphaned-entities-gc-period", consts.DefaultKonnectOrphanedEntitiesGCPeriod, "Period of looking for orphaned Konnect entities in KonnectGatewayControlPlanes annotated with konnect.konghq.com/orphaned-entities-gc: \"true\".")
+	flagSet.DurationVar(&cfg.KonnectAPITokenExpiryWarningThreshold, "konnect-api-token-expiry-warning-threshold", consts.DefaultKonnectAPITokenExpiryWarningThreshold, "Duration before the expiry of a Konnect API token (declared with the konnect.konghq.com/token-expires-at annotation) at which the expiry is reported in KonnectAPIAuthConfiguration's TokenExpiring condition.")
+	flagSet.DurationVar(&cfg.KonnectOrphanedEntitiesDeletionGracePeriod, "konnect-orphaned-entities-deletion-grace-period", 0, "Duration after which orphaned Konnect entities are deleted from Konnect. Orphaned entities are only reported when set to 0.")
+	flagSet.Var(NewValidatedValue(&cfg.KonnectBulkSyncBatchSize, parsePositiveUint, WithDefault(consts.DefaultKonnectBulkSyncBatchSize)), "konnect-bulk-sync-batch-size", "Maximum number of Konnect entities updated at once when syncing KonnectGatewayControlPlanes annotated with konnect.konghq.com/sync-mode: \"Bulk\".")
 
 	// webhook and validation options
 	var validatingWebhookEnabled bool
@@ -213,3 +220,16 @@ func (c *CLI) Parse(arguments []string) manager.Config {
 func (c *CLI) FlagSet() *flag.FlagSet {
 	return c.flagSet
 }
+
+// parsePositiveUint parses the provided flag value as an unsigned integer
+// greater than 0.
+func parsePositiveUint(s string) (uint, error) {
+	v, err := strconv.ParseUint(s, 10, 0)
+	if err != nil {
+		return 0, err
+	}
+	if v < 1 {
+		return 0, fmt.Errorf("must be at least 1, got %d", v)
+	}
+	return uint(v), nil
+}
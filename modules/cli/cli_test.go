@@ -99,6 +99,17 @@ func TestParse(t *testing.T) {
 				return cfg
 			},
 		},
+		{
+			name: "konnect bulk sync batch size argument is set",
+			args: []string{
+				"--konnect-bulk-sync-batch-size=10",
+			},
+			expectedCfg: func() manager.Config {
+				cfg := expectedDefaultCfg()
+				cfg.KonnectBulkSyncBatchSize = 10
+				return cfg
+			},
+		},
 		{
 			name: "cluster CA key type argument is set",
 			args: []string{
@@ -131,6 +142,13 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestKonnectBulkSyncBatchSizeValidation(t *testing.T) {
+	cli := New(metadata.Metadata())
+	require.Error(t, cli.FlagSet().Set("konnect-bulk-sync-batch-size", "0"))
+	require.Error(t, cli.FlagSet().Set("konnect-bulk-sync-batch-size", "-1"))
+	require.NoError(t, cli.FlagSet().Set("konnect-bulk-sync-batch-size", "1"))
+}
+
 func TestParseWithAdditionalFlags(t *testing.T) {
 	type additionalConfig struct {
 		OptionBool   bool
@@ -187,10 +205,14 @@ func expectedDefaultCfg() manager.Config {
 		DataPlaneControllerEnabled:              true,
 		DataPlaneBlueGreenControllerEnabled:     true,
 		ControlPlaneExtensionsControllerEnabled: true,
+		CustomMetricsAPIAddr:                    ":6443",
 		KonnectControllersEnabled:               false,
 		KonnectSyncPeriod:                       consts.DefaultKonnectSyncPeriod,
 		KongPluginInstallationControllerEnabled: false,
 		LoggerOpts:                              &zap.Options{},
 		KonnectMaxConcurrentReconciles:          consts.DefaultKonnectMaxConcurrentReconciles,
+		KonnectOrphanedEntitiesGCPeriod:         consts.DefaultKonnectOrphanedEntitiesGCPeriod,
+		KonnectAPITokenExpiryWarningThreshold:   consts.DefaultKonnectAPITokenExpiryWarningThreshold,
+		KonnectBulkSyncBatchSize:                consts.DefaultKonnectBulkSyncBatchSize,
 	}
 }
@@ -1,6 +1,9 @@
 package cli
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
 // ValidatedValueOpt is a function that modifies a ValidatedValue.
 type ValidatedValueOpt[T any] func(*ValidatedValue[T])
@@ -20,6 +23,8 @@ func stringFromAny(s any) string {
 	switch ss := s.(type) {
 	case string:
 		return ss
+	case uint:
+		return strconv.FormatUint(uint64(ss), 10)
 	case fmt.Stringer:
 		return fmt.Sprintf("%q", ss.String())
 	default:
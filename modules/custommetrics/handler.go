@@ -0,0 +1,251 @@
+package custommetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/controlplane_extensions/metricsscraper"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// DataPlaneMetricsProvider provides the metrics computed for DataPlanes.
+type DataPlaneMetricsProvider interface {
+	Get(dataplane types.NamespacedName) (metricsscraper.DataPlaneMetrics, bool)
+}
+
+var (
+	apiPath = "/apis/" + GroupVersion.String()
+
+	// dataPlaneResource is the resource custom metrics are served for.
+	// Using e.g. an HPA Object metric with DataPlane as the described object
+	// makes HPA controller request metrics for this resource.
+	dataPlaneResource = schema.GroupResource{
+		Group:    operatorv1beta1.SchemeGroupVersion.Group,
+		Resource: "dataplanes",
+	}
+)
+
+// handler serves the custom metrics API for DataPlanes.
+type handler struct {
+	cl         client.Reader
+	provider   DataPlaneMetricsProvider
+	authorizer authorizer.Authorizer
+}
+
+// newHandler returns an http.Handler serving the custom metrics API for DataPlanes.
+// When authz is not nil, requests are authorized for the user set in the request's
+// context (e.g. by an authentication filter).
+func newHandler(cl client.Reader, provider DataPlaneMetricsProvider, authz authorizer.Authorizer) http.Handler {
+	h := &handler{
+		cl:         cl,
+		provider:   provider,
+		authorizer: authz,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+apiPath, h.serveAPIResourceList)
+	mux.HandleFunc("GET "+apiPath+"/namespaces/{namespace}/{resource}/{name}/{metric}", h.serveMetric)
+	return mux
+}
+
+func (h *handler) serveAPIResourceList(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, authorizer.AttributesRecord{
+		Verb: "get",
+		Path: r.URL.Path,
+	}) {
+		return
+	}
+
+	list := metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: GroupVersion.String(),
+	}
+	for _, name := range metricsscraper.DataPlaneMetricNames {
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:       dataPlaneResource.String() + "/" + name,
+			Namespaced: true,
+			Kind:       "MetricValueList",
+			Verbs:      metav1.Verbs{"get"},
+		})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (h *handler) serveMetric(w http.ResponseWriter, r *http.Request) {
+	var (
+		namespace = r.PathValue("namespace")
+		res       = r.PathValue("resource")
+		name      = r.PathValue("name")
+		metric    = r.PathValue("metric")
+	)
+	if !h.authorize(w, r, authorizer.AttributesRecord{
+		Verb:            "get",
+		Namespace:       namespace,
+		APIGroup:        GroupVersion.Group,
+		APIVersion:      GroupVersion.Version,
+		Resource:        res,
+		Subresource:     metric,
+		Name:            name,
+		ResourceRequest: true,
+	}) {
+		return
+	}
+
+	if res != dataPlaneResource.String() || !slices.Contains(metricsscraper.DataPlaneMetricNames, metric) {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound,
+			fmt.Sprintf("the server could not find the metric %s for %s", metric, res),
+		)
+		return
+	}
+
+	list := MetricValueList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MetricValueList",
+			APIVersion: GroupVersion.String(),
+		},
+		Items: []MetricValue{},
+	}
+
+	if name != "*" {
+		dataplane := types.NamespacedName{Namespace: namespace, Name: name}
+		value, ok := h.metricValue(dataplane, metric)
+		if !ok {
+			writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound,
+				fmt.Sprintf("the server could not find the metric %s for %s %s", metric, res, dataplane),
+			)
+			return
+		}
+		list.Items = append(list.Items, value)
+		writeJSON(w, http.StatusOK, list)
+		return
+	}
+
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest,
+			fmt.Sprintf("invalid label selector: %v", err),
+		)
+		return
+	}
+	var dataplanes operatorv1beta1.DataPlaneList
+	if err := h.cl.List(r.Context(), &dataplanes,
+		client.InNamespace(namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		writeStatus(w, http.StatusInternalServerError, metav1.StatusReasonInternalError,
+			fmt.Sprintf("failed listing DataPlanes: %v", err),
+		)
+		return
+	}
+	for _, dp := range dataplanes.Items {
+		if value, ok := h.metricValue(client.ObjectKeyFromObject(&dp), metric); ok {
+			list.Items = append(list.Items, value)
+		}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// metricValue returns the value of the provided metric for the provided DataPlane.
+func (h *handler) metricValue(dataplane types.NamespacedName, metric string) (MetricValue, bool) {
+	metrics, ok := h.provider.Get(dataplane)
+	if !ok {
+		return MetricValue{}, false
+	}
+	value, ok := metrics.Values[metric]
+	if !ok {
+		return MetricValue{}, false
+	}
+
+	mv := MetricValue{
+		DescribedObject: corev1.ObjectReference{
+			APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+			Kind:       "DataPlane",
+			Namespace:  dataplane.Namespace,
+			Name:       dataplane.Name,
+		},
+		Metric: MetricIdentifier{
+			Name: metric,
+		},
+		Timestamp: metav1.NewTime(metrics.Timestamp),
+		Value:     *resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI),
+	}
+	if metric != metricsscraper.DataPlaneMetricActiveConnections && metrics.Window > 0 {
+		mv.WindowSeconds = lo.ToPtr(int64(metrics.Window.Seconds()))
+	}
+	return mv, true
+}
+
+// authorize authorizes the request with the provided attributes and writes
+// an error response when it's not allowed.
+func (h *handler) authorize(w http.ResponseWriter, r *http.Request, attrs authorizer.AttributesRecord) bool {
+	if h.authorizer == nil {
+		return true
+	}
+	u, ok := request.UserFrom(r.Context())
+	if !ok {
+		writeStatus(w, http.StatusUnauthorized, metav1.StatusReasonUnauthorized, "Unauthorized")
+		return false
+	}
+	attrs.User = u
+
+	decision, reason, err := h.authorizer.Authorize(r.Context(), attrs)
+	if err != nil {
+		writeStatus(w, http.StatusInternalServerError, metav1.StatusReasonInternalError,
+			fmt.Sprintf("authorization for user %s failed: %v", u.GetName(), err),
+		)
+		return false
+	}
+	if decision != authorizer.DecisionAllow {
+		writeStatus(w, http.StatusForbidden, metav1.StatusReasonForbidden,
+			forbiddenMessage(u, attrs, reason),
+		)
+		return false
+	}
+	return true
+}
+
+func forbiddenMessage(u user.Info, attrs authorizer.AttributesRecord, reason string) string {
+	if !attrs.ResourceRequest {
+		return fmt.Sprintf("user %q cannot get path %q: %s", u.GetName(), attrs.Path, reason)
+	}
+	return fmt.Sprintf("user %q cannot get resource %q in API group %q in the namespace %q: %s",
+		u.GetName(), attrs.Resource+"/"+attrs.Subresource, attrs.APIGroup, attrs.Namespace, reason,
+	)
+}
+
+func writeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason, message string) {
+	writeJSON(w, code, metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status:  metav1.StatusFailure,
+		Code:    int32(code), //nolint:gosec
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
@@ -0,0 +1,168 @@
+package custommetrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/controller/controlplane_extensions/metricsscraper"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+type fakeProvider map[types.NamespacedName]metricsscraper.DataPlaneMetrics
+
+func (p fakeProvider) Get(dataplane types.NamespacedName) (metricsscraper.DataPlaneMetrics, bool) {
+	m, ok := p[dataplane]
+	return m, ok
+}
+
+type fakeAuthorizer struct {
+	allowedUser string
+}
+
+func (a fakeAuthorizer) Authorize(_ context.Context, attrs authorizer.Attributes) (authorizer.Decision, string, error) {
+	if attrs.GetUser().GetName() == a.allowedUser {
+		return authorizer.DecisionAllow, "", nil
+	}
+	return authorizer.DecisionDeny, "not allowed", nil
+}
+
+func TestHandler(t *testing.T) {
+	now := time.Now()
+	provider := fakeProvider{
+		{Namespace: "default", Name: "dp-1"}: {
+			Values: map[string]float64{
+				metricsscraper.DataPlaneMetricRequestsPerSecond: 12.5,
+				metricsscraper.DataPlaneMetricActiveConnections: 3,
+			},
+			Timestamp: now,
+			Window:    10 * time.Second,
+		},
+		{Namespace: "default", Name: "dp-2"}: {
+			Values: map[string]float64{
+				metricsscraper.DataPlaneMetricRequestsPerSecond: 1,
+			},
+			Timestamp: now,
+			Window:    10 * time.Second,
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(
+			&operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "dp-1", Namespace: "default", Labels: map[string]string{"app": "a"}},
+			},
+			&operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "dp-2", Namespace: "default", Labels: map[string]string{"app": "b"}},
+			},
+			&operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "dp-3", Namespace: "default", Labels: map[string]string{"app": "a"}},
+			},
+		).
+		Build()
+	h := newHandler(cl, provider, fakeAuthorizer{allowedUser: "system:serviceaccount:kube-system:horizontal-pod-autoscaler"})
+
+	testCases := []struct {
+		name           string
+		path           string
+		user           string
+		expectedStatus int
+		assert         func(t *testing.T, body []byte)
+	}{
+		{
+			name:           "API resources",
+			path:           "/apis/custom.metrics.k8s.io/v1beta2",
+			user:           "system:serviceaccount:kube-system:horizontal-pod-autoscaler",
+			expectedStatus: http.StatusOK,
+			assert: func(t *testing.T, body []byte) {
+				var list metav1.APIResourceList
+				require.NoError(t, json.Unmarshal(body, &list))
+				assert.Equal(t, "custom.metrics.k8s.io/v1beta2", list.GroupVersion)
+				require.Len(t, list.APIResources, len(metricsscraper.DataPlaneMetricNames))
+				assert.Equal(t, "dataplanes.gateway-operator.konghq.com/kong_requests_per_second", list.APIResources[0].Name)
+			},
+		},
+		{
+			name:           "metric for a DataPlane",
+			path:           "/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/dataplanes.gateway-operator.konghq.com/dp-1/kong_requests_per_second",
+			user:           "system:serviceaccount:kube-system:horizontal-pod-autoscaler",
+			expectedStatus: http.StatusOK,
+			assert: func(t *testing.T, body []byte) {
+				var list MetricValueList
+				require.NoError(t, json.Unmarshal(body, &list))
+				require.Len(t, list.Items, 1)
+				item := list.Items[0]
+				assert.Equal(t, "DataPlane", item.DescribedObject.Kind)
+				assert.Equal(t, "dp-1", item.DescribedObject.Name)
+				assert.Equal(t, "kong_requests_per_second", item.Metric.Name)
+				assert.Equal(t, "12500m", item.Value.String())
+				require.NotNil(t, item.WindowSeconds)
+				assert.Equal(t, int64(10), *item.WindowSeconds)
+			},
+		},
+		{
+			name:           "metrics for DataPlanes matching label selector",
+			path:           "/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/dataplanes.gateway-operator.konghq.com/*/kong_requests_per_second?labelSelector=app%3Da",
+			user:           "system:serviceaccount:kube-system:horizontal-pod-autoscaler",
+			expectedStatus: http.StatusOK,
+			assert: func(t *testing.T, body []byte) {
+				var list MetricValueList
+				require.NoError(t, json.Unmarshal(body, &list))
+				require.Len(t, list.Items, 1)
+				assert.Equal(t, "dp-1", list.Items[0].DescribedObject.Name)
+			},
+		},
+		{
+			name:           "missing metric for a DataPlane",
+			path:           "/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/dataplanes.gateway-operator.konghq.com/dp-2/kong_active_connections",
+			user:           "system:serviceaccount:kube-system:horizontal-pod-autoscaler",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown resource",
+			path:           "/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/pods/dp-1/kong_requests_per_second",
+			user:           "system:serviceaccount:kube-system:horizontal-pod-autoscaler",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unauthorized user",
+			path:           "/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/dataplanes.gateway-operator.konghq.com/dp-1/kong_requests_per_second",
+			user:           "someone",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "unauthenticated request",
+			path:           "/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/dataplanes.gateway-operator.konghq.com/dp-1/kong_requests_per_second",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.user != "" {
+				req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: tc.user}))
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			require.Equal(t, tc.expectedStatus, rec.Code, rec.Body.String())
+			if tc.assert != nil {
+				tc.assert(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
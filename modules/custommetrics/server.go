@@ -0,0 +1,209 @@
+// Package custommetrics implements a server for the Kubernetes custom metrics
+// API (custom.metrics.k8s.io) serving the metrics computed for DataPlanes from
+// the metrics scraped by the operator, so that they can be used to autoscale
+// DataPlanes with HorizontalPodAutoscalers.
+package custommetrics
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/apis/apiserver"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/authenticatorfactory"
+	"k8s.io/apiserver/pkg/authentication/request/headerrequest"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// authenticationConfigMapNamespace and authenticationConfigMapName identify
+	// the ConfigMap which kube-apiserver populates with the configuration needed
+	// to authenticate the requests it proxies to aggregated API servers.
+	authenticationConfigMapNamespace = "kube-system"
+	authenticationConfigMapName      = "extension-apiserver-authentication"
+
+	shutdownTimeout = 10 * time.Second
+)
+
+// webhookRetryBackoff is used for TokenReview and SubjectAccessReview requests.
+// It's copied from k8s.io/apiserver/pkg/server/options to not depend on that package.
+var webhookRetryBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   1.5,
+	Jitter:   0.2,
+	Steps:    5,
+}
+
+// Server serves the custom metrics API for DataPlanes.
+// It is meant to be registered in the cluster through an APIService.
+// Requests proxied by kube-apiserver are authenticated using its front proxy
+// client certificate and the request headers configured in the
+// kube-system/extension-apiserver-authentication ConfigMap. Requests using
+// bearer tokens are authenticated using TokenReviews. All requests are
+// authorized using SubjectAccessReviews.
+type Server struct {
+	logger        logr.Logger
+	addr          string
+	handler       http.Handler
+	authenticator authenticator.Request
+
+	requestHeaderCAController *dynamiccertificates.ConfigMapCAController
+	requestHeaderController   *headerrequest.RequestHeaderAuthRequestController
+}
+
+// NewServer creates a new custom metrics API Server listening on the provided address.
+func NewServer(
+	logger logr.Logger,
+	addr string,
+	restCfg *rest.Config,
+	httpClient *http.Client,
+	cl client.Reader,
+	provider DataPlaneMetricsProvider,
+) (*Server, error) {
+	kubeClient, err := kubernetes.NewForConfigAndClient(restCfg, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating Kubernetes client: %w", err)
+	}
+
+	requestHeaderCAController, err := dynamiccertificates.NewDynamicCAFromConfigMapController(
+		"request-header", authenticationConfigMapNamespace, authenticationConfigMapName, "requestheader-client-ca-file", kubeClient,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request header CA controller: %w", err)
+	}
+	requestHeaderController := headerrequest.NewRequestHeaderAuthRequestController(
+		authenticationConfigMapName, authenticationConfigMapNamespace, kubeClient,
+		"requestheader-username-headers",
+		"requestheader-uid-headers",
+		"requestheader-group-headers",
+		"requestheader-extra-headers-prefix",
+		"requestheader-allowed-names",
+	)
+
+	authn, _, err := authenticatorfactory.DelegatingAuthenticatorConfig{
+		Anonymous:                &apiserver.AnonymousAuthConfig{Enabled: false},
+		CacheTTL:                 time.Minute,
+		TokenAccessReviewClient:  kubeClient.AuthenticationV1(),
+		TokenAccessReviewTimeout: 10 * time.Second,
+		WebhookRetryBackoff:      &webhookRetryBackoff,
+		RequestHeaderConfig: &authenticatorfactory.RequestHeaderConfig{
+			UsernameHeaders:     headerrequest.StringSliceProviderFunc(requestHeaderController.UsernameHeaders),
+			UIDHeaders:          headerrequest.StringSliceProviderFunc(requestHeaderController.UIDHeaders),
+			GroupHeaders:        headerrequest.StringSliceProviderFunc(requestHeaderController.GroupHeaders),
+			ExtraHeaderPrefixes: headerrequest.StringSliceProviderFunc(requestHeaderController.ExtraHeaderPrefixes),
+			AllowedClientNames:  headerrequest.StringSliceProviderFunc(requestHeaderController.AllowedClientNames),
+			CAContentProvider:   requestHeaderCAController,
+		},
+	}.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating authenticator: %w", err)
+	}
+
+	authz, err := authorizerfactory.DelegatingAuthorizerConfig{
+		SubjectAccessReviewClient: kubeClient.AuthorizationV1(),
+		AllowCacheTTL:             5 * time.Minute,
+		DenyCacheTTL:              30 * time.Second,
+		WebhookRetryBackoff:       &webhookRetryBackoff,
+	}.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating authorizer: %w", err)
+	}
+
+	return &Server{
+		logger:                    logger,
+		addr:                      addr,
+		handler:                   newHandler(cl, provider, authz),
+		authenticator:             authn,
+		requestHeaderCAController: requestHeaderCAController,
+		requestHeaderController:   requestHeaderController,
+	}, nil
+}
+
+// Start starts the server and blocks until the provided context is done.
+// This satisfies the Runnable interface and can be used with controller-runtime Manager.
+func (s *Server) Start(ctx context.Context) error {
+	// Load the authentication configuration before serving so that the first
+	// requests are not rejected. Errors are logged as the configuration
+	// gets loaded by the controllers once available.
+	if err := s.requestHeaderCAController.RunOnce(ctx); err != nil {
+		s.logger.Error(err, "failed loading request header CA")
+	}
+	if err := s.requestHeaderController.RunOnce(ctx); err != nil {
+		s.logger.Error(err, "failed loading request header configuration")
+	}
+	go s.requestHeaderCAController.Run(ctx, 1)
+	go s.requestHeaderController.Run(ctx, 1)
+
+	// The server uses a self-signed certificate so the APIService has to skip
+	// the TLS verification.
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed generating serving certificate: %w", err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("failed loading serving certificate: %w", err)
+	}
+
+	listener, err := tls.Listen("tcp", s.addr, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		// Client certificates are verified by the authenticator.
+		ClientAuth: tls.RequestClientCert,
+		MinVersion: tls.VersionTLS12,
+	})
+	if err != nil {
+		return fmt.Errorf("failed listening on %s: %w", s.addr, err)
+	}
+
+	srv := &http.Server{
+		Handler:           s.withAuthentication(s.handler),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			s.logger.Error(err, "failed shutting down custom metrics API server")
+		}
+	}()
+
+	s.logger.Info("starting custom metrics API server", "address", s.addr)
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// withAuthentication authenticates requests and sets the authenticated user
+// in the request's context.
+func (s *Server) withAuthentication(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, ok, err := s.authenticator.AuthenticateRequest(r)
+		if err != nil {
+			s.logger.Error(err, "authentication failed")
+		}
+		if err != nil || !ok {
+			writeStatus(w, http.StatusUnauthorized, metav1.StatusReasonUnauthorized, "Unauthorized")
+			return
+		}
+		handler.ServeHTTP(w, r.WithContext(request.WithUser(r.Context(), res.User)))
+	})
+}
@@ -0,0 +1,57 @@
+package custommetrics
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersion is the group version of the custom metrics API served by the operator.
+var GroupVersion = schema.GroupVersion{Group: "custom.metrics.k8s.io", Version: "v1beta2"}
+
+// The below types mirror the custom.metrics.k8s.io/v1beta2 API types from
+// k8s.io/metrics which only need to be serialized to JSON here.
+
+// MetricValueList is a list of values for a given metric for some set of objects.
+type MetricValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of metric values.
+	Items []MetricValue `json:"items"`
+}
+
+// MetricValue is the metric value for some object.
+type MetricValue struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// DescribedObject is a reference to the described object.
+	DescribedObject corev1.ObjectReference `json:"describedObject"`
+
+	// Metric identifies the metric.
+	Metric MetricIdentifier `json:"metric"`
+
+	// Timestamp indicates the time at which the metrics were produced.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// WindowSeconds indicates the window ([Timestamp-Window, Timestamp]) from
+	// which these metrics were calculated, when returning rate metrics
+	// calculated from cumulative metrics (or zero for non-calculated
+	// instantaneous metrics).
+	WindowSeconds *int64 `json:"windowSeconds,omitempty"`
+
+	// Value is the value of the metric for this object.
+	Value resource.Quantity `json:"value"`
+}
+
+// MetricIdentifier identifies a metric by name and, optionally, selector.
+type MetricIdentifier struct {
+	// Name is the name of the given metric.
+	Name string `json:"name"`
+
+	// Selector represents the label selector that could be used to select
+	// this metric, and will generally just be the selector passed in to
+	// the query used to fetch this metric.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
@@ -17,7 +17,9 @@ import (
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 
+	"github.com/kong/gateway-operator/controller/backendtlspolicy"
 	"github.com/kong/gateway-operator/controller/controlplane"
 	"github.com/kong/gateway-operator/controller/controlplane_extensions"
 	"github.com/kong/gateway-operator/controller/controlplane_extensions/metricsscraper"
@@ -33,6 +35,7 @@ import (
 	"github.com/kong/gateway-operator/controller/specialized"
 	"github.com/kong/gateway-operator/internal/metrics"
 	"github.com/kong/gateway-operator/internal/utils/index"
+	"github.com/kong/gateway-operator/modules/custommetrics"
 	"github.com/kong/gateway-operator/modules/manager/logging"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
@@ -50,6 +53,10 @@ const (
 	GatewayClassControllerName = "GatewayClass"
 	// GatewayControllerName is the name of the Gateway controller.
 	GatewayControllerName = "Gateway"
+	// GatewayConfigurationStagedRolloutControllerName is the name of the GatewayConfigurationStagedRollout controller.
+	GatewayConfigurationStagedRolloutControllerName = "GatewayConfigurationStagedRollout"
+	// BackendTLSPolicyControllerName is the name of the BackendTLSPolicy controller.
+	BackendTLSPolicyControllerName = "BackendTLSPolicy"
 	// ControlPlaneControllerName is the name of ControlPlane controller.
 	ControlPlaneControllerName = "ControlPlane"
 	// DataPlaneControllerName is the name of the DataPlane controller.
@@ -62,6 +69,8 @@ const (
 	DataPlaneOwnedSecretFinalizerControllerName = "DataPlaneOwnedSecretFinalizer"
 	// DataPlaneOwnedDeploymentFinalizerControllerName is the name of the DataPlaneOwnedDeploymentFinalizer controller.
 	DataPlaneOwnedDeploymentFinalizerControllerName = "DataPlaneOwnedDeploymentFinalizer"
+	// DataPlaneOwnedDaemonSetFinalizerControllerName is the name of the DataPlaneOwnedDaemonSetFinalizer controller.
+	DataPlaneOwnedDaemonSetFinalizerControllerName = "DataPlaneOwnedDaemonSetFinalizer"
 	// KonnectExtensionControllerName is the name of the KonnectExtension controller.
 	KonnectExtensionControllerName = "KonnectExtension"
 	// AIGatewayControllerName is the name of the AIGateway controller.
@@ -78,6 +87,12 @@ const (
 	KonnectCloudGatewayDataPlaneGroupConfigurationControllerName = "KonnectCloudGatewayDataPlaneGroupConfiguration"
 	// KonnectCloudGatewayTransitGatewayControllerName is the name of the KonnectCloudGatewayTransitGateway controller.
 	KonnectCloudGatewayTransitGatewayControllerName = "KonnectCloudGatewayTransitGateway"
+	// KonnectOrphanedEntitiesControllerName is the name of the controller looking for orphaned Konnect entities.
+	KonnectOrphanedEntitiesControllerName = "KonnectOrphanedEntities"
+	// KonnectEntityMirrorControllerName is the name of the controller mirroring Konnect entities into the cluster.
+	KonnectEntityMirrorControllerName = "KonnectEntityMirror"
+	// KonnectBulkSyncControllerName is the name of the controller syncing Konnect entities in bulk.
+	KonnectBulkSyncControllerName = "KonnectBulkSync"
 	// KongServiceControllerName is the name of the KongService controller.
 	KongServiceControllerName = "KongService"
 	// KongRouteControllerName is the name of the KongRoute controller.
@@ -183,6 +198,11 @@ func SetupCacheIndexes(ctx context.Context, mgr manager.Manager, cfg Config) err
 	if cfg.ControlPlaneControllerEnabled || cfg.GatewayControllerEnabled {
 		indexOptions = slices.Concat(indexOptions,
 			index.OptionsForControlPlane(cfg.KonnectControllersEnabled),
+		)
+	}
+	if cfg.ControlPlaneControllerEnabled || cfg.GatewayControllerEnabled ||
+		cfg.DataPlaneControllerEnabled || cfg.DataPlaneBlueGreenControllerEnabled {
+		indexOptions = slices.Concat(indexOptions,
 			index.OptionsForDataPlane(index.DataPlaneFlags{
 				KongPluginInstallationControllerEnabled: cfg.KongPluginInstallationControllerEnabled,
 				KonnectControllersEnabled:               cfg.KonnectControllersEnabled,
@@ -423,6 +443,21 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 		}
 	}
 
+	// BackendTLSPolicy is part of the Gateway API experimental channel, hence
+	// its support is only enabled when the CRD is installed.
+	backendTLSPolicyEnabled := false
+	if c.GatewayControllerEnabled {
+		ok, err := checker.CRDExists(schema.GroupVersionResource{
+			Group:    gatewayv1alpha3.SchemeGroupVersion.Group,
+			Version:  gatewayv1alpha3.SchemeGroupVersion.Version,
+			Resource: "backendtlspolicies",
+		})
+		if err != nil {
+			return nil, err
+		}
+		backendTLSPolicyEnabled = ok
+	}
+
 	keyType, err := KeyTypeToX509PublicKeyAlgorithm(c.ClusterCAKeyType)
 	if err != nil {
 		return nil, fmt.Errorf("unsupported cluster CA key type: %w", err)
@@ -451,6 +486,29 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 		return nil, fmt.Errorf("failed to add scrapers manager to controller-runtime manager: %w", err)
 	}
 
+	// The metrics computed for DataPlanes are served through the custom metrics
+	// API and used to protect DataPlanes serving traffic from deletion.
+	// Metrics not refreshed within a few scrape intervals are considered stale.
+	dataPlaneMetricsStore := metricsscraper.NewDataPlaneMetricsStore(3 * metricsScrapeInterval)
+	scrapersMgr.WithDataPlaneMetricsStore(dataPlaneMetricsStore)
+
+	if c.CustomMetricsAPIEnabled {
+		customMetricsServer, err := custommetrics.NewServer(
+			mgr.GetLogger().WithName("custom_metrics_api"),
+			c.CustomMetricsAPIAddr,
+			mgr.GetConfig(),
+			mgr.GetHTTPClient(),
+			mgr.GetClient(),
+			dataPlaneMetricsStore,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create custom metrics API server: %w", err)
+		}
+		if err := mgr.Add(customMetricsServer); err != nil {
+			return nil, fmt.Errorf("failed to add custom metrics API server to controller-runtime manager: %w", err)
+		}
+	}
+
 	controllers := map[string]ControllerDef{
 		// GatewayClass controller
 		GatewayClassControllerName: {
@@ -472,6 +530,23 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 				KonnectEnabled:          c.KonnectControllersEnabled,
 				AnonymousReportsEnabled: c.AnonymousReports,
 				LoggingMode:             c.LoggingMode,
+				DataPlaneMetrics:        dataPlaneMetricsStore,
+			},
+		},
+		// GatewayConfiguration staged rollout controller
+		GatewayConfigurationStagedRolloutControllerName: {
+			Enabled: c.GatewayControllerEnabled,
+			Controller: &gateway.StagedRolloutReconciler{
+				Client:      mgr.GetClient(),
+				LoggingMode: c.LoggingMode,
+			},
+		},
+		// BackendTLSPolicy controller
+		BackendTLSPolicyControllerName: {
+			Enabled: backendTLSPolicyEnabled,
+			Controller: &backendtlspolicy.Reconciler{
+				Client:      mgr.GetClient(),
+				LoggingMode: c.LoggingMode,
 			},
 		},
 		// ControlPlane controller
@@ -509,6 +584,7 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 				EnforceConfig:          c.EnforceConfig,
 				LoggingMode:            c.LoggingMode,
 				ValidateDataPlaneImage: c.ValidateImages,
+				DataPlaneMetrics:       dataPlaneMetricsStore,
 			},
 		},
 		// DataPlaneBlueGreen controller
@@ -534,6 +610,7 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 					EnforceConfig:          c.EnforceConfig,
 					ValidateDataPlaneImage: c.ValidateImages,
 					LoggingMode:            c.LoggingMode,
+					DataPlaneMetrics:       dataPlaneMetricsStore,
 				},
 				Callbacks: dataplane.DataPlaneCallbacks{
 					BeforeDeployment: dataplane.CreateCallbackManager(),
@@ -544,6 +621,7 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 				EnforceConfig:          c.EnforceConfig,
 				ValidateDataPlaneImage: c.ValidateImages,
 				LoggingMode:            c.LoggingMode,
+				DataPlaneMetrics:       dataPlaneMetricsStore,
 			},
 		},
 		DataPlaneOwnedServiceFinalizerControllerName: {
@@ -567,6 +645,13 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 				c.LoggingMode,
 			),
 		},
+		DataPlaneOwnedDaemonSetFinalizerControllerName: {
+			Enabled: c.DataPlaneControllerEnabled || c.DataPlaneBlueGreenControllerEnabled,
+			Controller: dataplane.NewDataPlaneOwnedResourceFinalizerReconciler[appsv1.DaemonSet](
+				mgr.GetClient(),
+				c.LoggingMode,
+			),
+		},
 		// AIGateway Controller
 		AIGatewayControllerName: {
 			Enabled: c.AIGatewayControllerEnabled,
@@ -614,6 +699,8 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 					sdkFactory,
 					c.LoggingMode,
 					mgr.GetClient(),
+					c.KonnectAPITokenExpiryWarningThreshold,
+					metricRecorder,
 				),
 			},
 
@@ -628,12 +715,47 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 			KongCredentialsSecretControllerName: {
 				Enabled: c.KonnectControllersEnabled,
 				Controller: konnect.NewKongCredentialSecretReconciler(
+					sdkFactory,
 					c.LoggingMode,
 					mgr.GetClient(),
 					mgr.GetScheme(),
 				),
 			},
 
+			KonnectOrphanedEntitiesControllerName: {
+				Enabled: c.KonnectControllersEnabled,
+				Controller: konnect.NewKonnectOrphanedEntitiesReconciler(
+					sdkFactory,
+					c.LoggingMode,
+					mgr.GetClient(),
+					mgr.GetAPIReader(),
+					c.KonnectOrphanedEntitiesGCPeriod,
+					c.KonnectOrphanedEntitiesDeletionGracePeriod,
+					metricRecorder,
+				),
+			},
+
+			KonnectEntityMirrorControllerName: {
+				Enabled: c.KonnectControllersEnabled,
+				Controller: konnect.NewKonnectEntityMirrorReconciler(
+					sdkFactory,
+					c.LoggingMode,
+					mgr.GetClient(),
+					c.KonnectSyncPeriod,
+				),
+			},
+
+			KonnectBulkSyncControllerName: {
+				Enabled: c.KonnectControllersEnabled,
+				Controller: konnect.NewKonnectBulkSyncReconciler(
+					sdkFactory,
+					c.LoggingMode,
+					mgr.GetClient(),
+					c.KonnectSyncPeriod,
+					c.KonnectBulkSyncBatchSize,
+				),
+			},
+
 			KonnectExtensionControllerName: {
 				Enabled: (c.DataPlaneControllerEnabled || c.DataPlaneBlueGreenControllerEnabled) && c.KonnectControllersEnabled,
 				Controller: &konnect.KonnectExtensionReconciler{
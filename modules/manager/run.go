@@ -87,9 +87,25 @@ type Config struct {
 	KongPluginInstallationControllerEnabled bool
 	KonnectSyncPeriod                       time.Duration
 	KonnectMaxConcurrentReconciles          uint
+	// KonnectOrphanedEntitiesGCPeriod is the period of looking for orphaned Konnect entities.
+	KonnectOrphanedEntitiesGCPeriod time.Duration
+	// KonnectOrphanedEntitiesDeletionGracePeriod is the duration after which orphaned
+	// Konnect entities are deleted. They're never deleted when set to 0.
+	KonnectOrphanedEntitiesDeletionGracePeriod time.Duration
+	// KonnectAPITokenExpiryWarningThreshold is the duration before the declared
+	// expiry of a Konnect API token at which the expiry is reported.
+	KonnectAPITokenExpiryWarningThreshold time.Duration
+	// KonnectBulkSyncBatchSize is the maximum number of Konnect entities updated
+	// at once by a bulk sync of a Konnect ControlPlane.
+	KonnectBulkSyncBatchSize                uint
 	GatewayAPIExperimentalEnabled           bool
 	ControlPlaneExtensionsControllerEnabled bool
 
+	// CustomMetricsAPIEnabled enables serving the custom metrics API
+	// (custom.metrics.k8s.io) with metrics scraped from DataPlanes.
+	CustomMetricsAPIEnabled bool
+	CustomMetricsAPIAddr    string
+
 	// Controllers for Konnect APIs.
 	KonnectControllersEnabled bool
 }
@@ -0,0 +1,81 @@
+package render
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+// Command is the name of the subcommand used to render resources offline.
+const Command = "render"
+
+type filesFlag []string
+
+func (f *filesFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *filesFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+// Run parses the provided arguments (which should not include the subcommand name),
+// renders the resources for the objects read from the provided files (or stdin
+// when none or "-" is provided) and writes them as YAML to out.
+func Run(args []string, scheme *runtime.Scheme, stdin io.Reader, out io.Writer) error {
+	var (
+		files   filesFlag
+		cfg     Config
+		flagSet = flag.NewFlagSet(Command, flag.ContinueOnError)
+	)
+	flagSet.Var(&files, "f", "Path to a file containing the manifests to render resources for. Can be provided multiple times. Use - to read from stdin.")
+	flagSet.BoolVar(&cfg.ValidateImages, "validate-images", true, "Validate the images set in ControlPlane and DataPlane specifications.")
+	flagSet.BoolVar(&cfg.AnonymousReportsEnabled, "anonymous-reports", true, "Render ControlPlanes with anonymized usage data reporting enabled.")
+	flagSet.StringVar(&cfg.DefaultDataPlaneImage, "default-dataplane-image", consts.DefaultDataPlaneImage, "The image used for DataPlanes which do not specify one.")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		files = filesFlag{"-"}
+	}
+
+	renderer := NewRenderer(cfg, scheme)
+	var objs []client.Object
+	for _, f := range files {
+		decoded, err := decodeFile(renderer, f, stdin)
+		if err != nil {
+			return err
+		}
+		objs = append(objs, decoded...)
+	}
+
+	rendered, err := renderer.Render(objs)
+	if err != nil {
+		return err
+	}
+	return renderer.Encode(out, rendered)
+}
+
+func decodeFile(renderer *Renderer, path string, stdin io.Reader) ([]client.Object, error) {
+	if path == "-" {
+		return renderer.Decode(stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening %s: %w", path, err)
+	}
+	defer f.Close()
+	objs, err := renderer.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed decoding %s: %w", path, err)
+	}
+	return objs, nil
+}
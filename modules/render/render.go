@@ -0,0 +1,245 @@
+// Package render implements rendering of the Kubernetes resources that the
+// operator would create for the provided Gateways, DataPlanes and ControlPlanes
+// without connecting to a cluster.
+package render
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/controller/controlplane"
+	"github.com/kong/gateway-operator/controller/dataplane"
+	"github.com/kong/gateway-operator/controller/gateway"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/pkg/vars"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// Config holds the configuration of the renderer.
+type Config struct {
+	// ValidateImages enables validation of the ControlPlane and DataPlane images.
+	ValidateImages bool
+	// AnonymousReportsEnabled is used to configure ControlPlanes the same way
+	// the operator would do with its -anonymous-reports flag.
+	AnonymousReportsEnabled bool
+	// DefaultDataPlaneImage is the image used for DataPlanes that do not specify one.
+	DefaultDataPlaneImage string
+}
+
+// Renderer renders the resources that the operator would create for the
+// provided input objects.
+type Renderer struct {
+	cfg    Config
+	scheme *runtime.Scheme
+}
+
+// NewRenderer returns a new Renderer using the provided scheme to decode
+// the input and encode the output objects.
+func NewRenderer(cfg Config, scheme *runtime.Scheme) *Renderer {
+	return &Renderer{
+		cfg:    cfg,
+		scheme: scheme,
+	}
+}
+
+// Decode decodes the (possibly multi-document) YAML or JSON input into objects.
+func (r *Renderer) Decode(in io.Reader) ([]client.Object, error) {
+	var (
+		decoder = serializer.NewCodecFactory(r.scheme).UniversalDeserializer()
+		reader  = utilyaml.NewYAMLReader(bufio.NewReader(in))
+		objs    []client.Object
+	)
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed reading input: %w", err)
+		}
+		// Skip empty documents, e.g. the ones containing only comments.
+		if j, err := utilyaml.ToJSON(doc); err == nil && (len(j) == 0 || string(j) == "null") {
+			continue
+		}
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed decoding input: %w", err)
+		}
+		cObj, ok := obj.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("unsupported input object of type %T", obj)
+		}
+		objs = append(objs, cObj)
+	}
+}
+
+// Encode writes the provided objects as a multi-document YAML stream.
+func (r *Renderer) Encode(out io.Writer, objs []client.Object) error {
+	encoder := json.NewSerializerWithOptions(json.DefaultMetaFactory, r.scheme, r.scheme, json.SerializerOptions{Yaml: true})
+	for i, obj := range objs {
+		if i > 0 {
+			if _, err := io.WriteString(out, "---\n"); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Encode(obj, out); err != nil {
+			return fmt.Errorf("failed encoding %T %s: %w", obj, client.ObjectKeyFromObject(obj), err)
+		}
+	}
+	return nil
+}
+
+// Render renders the resources that the operator would create for the provided
+// objects. Gateways (using a GatewayClass managed by the operator), DataPlanes
+// and ControlPlanes are rendered, GatewayClasses and GatewayConfigurations are
+// used to resolve the Gateways' configuration, routes, Services, EndpointSlices,
+// Namespaces and ConfigMaps to resolve the egress rules of their NetworkPolicies
+// while all other objects are ignored.
+func (r *Renderer) Render(objs []client.Object) ([]client.Object, error) {
+	var (
+		gatewayClasses = make(map[string]*gatewayv1.GatewayClass)
+		gatewayConfigs = make(map[types.NamespacedName]*operatorv1beta1.GatewayConfiguration)
+		gateways       []*gwtypes.Gateway
+		dataplanes     []*operatorv1beta1.DataPlane
+		controlplanes  []*operatorv1beta1.ControlPlane
+	)
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *gatewayv1.GatewayClass:
+			gatewayClasses[o.Name] = o
+		case *operatorv1beta1.GatewayConfiguration:
+			gatewayConfigs[client.ObjectKeyFromObject(o)] = o
+		case *gwtypes.Gateway:
+			gateways = append(gateways, o)
+		case *operatorv1beta1.DataPlane:
+			dataplanes = append(dataplanes, o)
+		case *operatorv1beta1.ControlPlane:
+			controlplanes = append(controlplanes, o)
+		}
+	}
+
+	var out []client.Object
+	for _, gw := range gateways {
+		gatewayClass, ok := gatewayClasses[string(gw.Spec.GatewayClassName)]
+		if !ok {
+			return nil, fmt.Errorf("GatewayClass %s used by Gateway %s not found in the input", gw.Spec.GatewayClassName, client.ObjectKeyFromObject(gw))
+		}
+		if string(gatewayClass.Spec.ControllerName) != vars.ControllerName() {
+			continue
+		}
+		gatewayConfig, err := gatewayConfigForGatewayClass(gatewayClass, gatewayConfigs)
+		if err != nil {
+			return nil, err
+		}
+		rendered, err := r.renderGateway(gatewayClass, gw, gatewayConfig, objs)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rendered...)
+	}
+	for _, dp := range dataplanes {
+		_, _, rendered, err := dataplane.RenderOwnedResources(withGVK(r.scheme, dp), r.cfg.DefaultDataPlaneImage, r.cfg.ValidateImages)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rendered...)
+	}
+	for _, cp := range controlplanes {
+		rendered, err := controlplane.RenderOwnedResources(withGVK(r.scheme, cp), "", "", r.cfg.AnonymousReportsEnabled, r.cfg.ValidateImages)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rendered...)
+	}
+
+	for _, obj := range out {
+		withGVK(r.scheme, obj)
+	}
+	return out, nil
+}
+
+func (r *Renderer) renderGateway(
+	gatewayClass *gatewayv1.GatewayClass,
+	gw *gwtypes.Gateway,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	objs []client.Object,
+) ([]client.Object, error) {
+	params := gateway.RenderOwnedResourcesParams{
+		GatewayClass:            gatewayClass,
+		Gateway:                 withGVK(r.scheme, gw),
+		GatewayConfig:           gatewayConfig,
+		DefaultDataPlaneImage:   r.cfg.DefaultDataPlaneImage,
+		AnonymousReportsEnabled: r.cfg.AnonymousReportsEnabled,
+	}
+
+	dp, err := gateway.RenderDataPlane(params)
+	if err != nil {
+		return nil, err
+	}
+	withGVK(r.scheme, dp)
+	ingressServiceName, adminServiceName, dpObjs, err := dataplane.RenderOwnedResources(dp, r.cfg.DefaultDataPlaneImage, r.cfg.ValidateImages)
+	if err != nil {
+		return nil, err
+	}
+
+	params.DataPlaneIngressServiceName = ingressServiceName
+	params.DataPlaneAdminServiceName = adminServiceName
+	cp := withGVK(r.scheme, gateway.RenderControlPlane(params, dp))
+	cpObjs, err := controlplane.RenderOwnedResources(cp, ingressServiceName, adminServiceName, r.cfg.AnonymousReportsEnabled, r.cfg.ValidateImages)
+	if err != nil {
+		return nil, err
+	}
+
+	networkPolicy, err := gateway.RenderNetworkPolicy(gw, dp, cp, objs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []client.Object{dp, cp, networkPolicy}
+	out = append(out, dpObjs...)
+	return append(out, cpObjs...), nil
+}
+
+// withGVK sets the GroupVersionKind of the provided object based on the
+// renderer's scheme. Owner references are generated from the owner's TypeMeta
+// hence it has to be set before rendering the owned objects.
+func withGVK[T client.Object](scheme *runtime.Scheme, obj T) T {
+	if gvk, err := apiutil.GVKForObject(obj, scheme); err == nil {
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+	return obj
+}
+
+func gatewayConfigForGatewayClass(
+	gatewayClass *gatewayv1.GatewayClass,
+	gatewayConfigs map[types.NamespacedName]*operatorv1beta1.GatewayConfiguration,
+) (*operatorv1beta1.GatewayConfiguration, error) {
+	ref := gatewayClass.Spec.ParametersRef
+	if ref == nil {
+		return new(operatorv1beta1.GatewayConfiguration), nil
+	}
+	if string(ref.Group) != operatorv1beta1.SchemeGroupVersion.Group || string(ref.Kind) != "GatewayConfiguration" {
+		return nil, fmt.Errorf("GatewayClass %s parametersRef has to point to a %s GatewayConfiguration", gatewayClass.Name, operatorv1beta1.SchemeGroupVersion.Group)
+	}
+	if ref.Namespace == nil || *ref.Namespace == "" || ref.Name == "" {
+		return nil, fmt.Errorf("GatewayClass %s has invalid ParametersRef: both namespace and name must be provided", gatewayClass.Name)
+	}
+	nn := types.NamespacedName{Namespace: string(*ref.Namespace), Name: ref.Name}
+	gatewayConfig, ok := gatewayConfigs[nn]
+	if !ok {
+		return nil, fmt.Errorf("GatewayConfiguration %s used by GatewayClass %s not found in the input", nn, gatewayClass.Name)
+	}
+	return gatewayConfig, nil
+}
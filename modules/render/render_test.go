@@ -0,0 +1,216 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+const gatewayManifests = `
+apiVersion: gateway.networking.k8s.io/v1
+kind: GatewayClass
+metadata:
+  name: kong
+spec:
+  controllerName: konghq.com/gateway-operator
+  parametersRef:
+    group: gateway-operator.konghq.com
+    kind: GatewayConfiguration
+    name: kong
+    namespace: default
+---
+# Comment only document.
+---
+apiVersion: gateway-operator.konghq.com/v1beta1
+kind: GatewayConfiguration
+metadata:
+  name: kong
+  namespace: default
+spec:
+  dataPlaneOptions:
+    deployment:
+      replicas: 3
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: gw
+  namespace: default
+spec:
+  gatewayClassName: kong
+  listeners:
+  - name: http
+    protocol: HTTP
+    port: 80
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: other
+  namespace: default
+spec:
+  gatewayClassName: other
+  listeners:
+  - name: http
+    protocol: HTTP
+    port: 80
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: GatewayClass
+metadata:
+  name: other
+spec:
+  controllerName: example.com/other
+`
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		expectedKinds []string
+		expectedErr   string
+		assert        func(t *testing.T, objs []client.Object)
+	}{
+		{
+			name:  "Gateway with GatewayConfiguration",
+			input: gatewayManifests,
+			expectedKinds: []string{
+				"DataPlane", "ControlPlane", "NetworkPolicy",
+				"Service", "Service", "Secret", "Deployment",
+				"ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Secret", "Deployment",
+			},
+			assert: func(t *testing.T, objs []client.Object) {
+				var dpDeployment *appsv1.Deployment
+				for _, obj := range objs {
+					if d, ok := obj.(*appsv1.Deployment); ok && d.Labels[consts.GatewayOperatorManagedByLabel] == consts.DataPlaneManagedLabelValue {
+						dpDeployment = d
+					}
+				}
+				require.NotNil(t, dpDeployment)
+				require.NotNil(t, dpDeployment.Spec.Replicas)
+				require.Equal(t, int32(3), *dpDeployment.Spec.Replicas)
+				require.Len(t, dpDeployment.OwnerReferences, 1)
+				require.Equal(t, "DataPlane", dpDeployment.OwnerReferences[0].Kind)
+				require.Equal(t, "gw", dpDeployment.OwnerReferences[0].Name)
+			},
+		},
+		{
+			name: "Gateway with restricted egress",
+			input: strings.Replace(gatewayManifests, `  name: kong
+  namespace: default
+spec:
+  dataPlaneOptions:`, `  name: kong
+  namespace: default
+  annotations:
+    gateway-operator.konghq.com/network-policy-egress: restricted
+spec:
+  dataPlaneOptions:`, 1) + `
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: echo
+  namespace: default
+spec:
+  parentRefs:
+  - name: gw
+  rules:
+  - backendRefs:
+    - name: echo
+      port: 80
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: echo
+  namespace: default
+spec:
+  selector:
+    app: echo
+  ports:
+  - port: 80
+    targetPort: 8080
+`,
+			expectedKinds: []string{
+				"DataPlane", "ControlPlane", "NetworkPolicy",
+				"Service", "Service", "Secret", "Deployment",
+				"ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Secret", "Deployment",
+			},
+			assert: func(t *testing.T, objs []client.Object) {
+				var policy *networkingv1.NetworkPolicy
+				for _, obj := range objs {
+					if p, ok := obj.(*networkingv1.NetworkPolicy); ok {
+						policy = p
+					}
+				}
+				require.NotNil(t, policy)
+				require.True(t, lo.ContainsBy(policy.Spec.Egress, func(rule networkingv1.NetworkPolicyEgressRule) bool {
+					return len(rule.To) == 1 && rule.To[0].PodSelector != nil &&
+						rule.To[0].PodSelector.MatchLabels["app"] == "echo" &&
+						len(rule.Ports) == 1 && rule.Ports[0].Port.IntValue() == 8080
+				}), "egress rules should allow reaching the HTTPRoute's backend: %v", policy.Spec.Egress)
+			},
+		},
+		{
+			name: "standalone DataPlane",
+			input: `
+apiVersion: gateway-operator.konghq.com/v1beta1
+kind: DataPlane
+metadata:
+  name: dp
+  namespace: default
+`,
+			expectedKinds: []string{"Service", "Service", "Secret", "Deployment"},
+		},
+		{
+			name: "Gateway with missing GatewayClass",
+			input: `
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: gw
+  namespace: default
+spec:
+  gatewayClassName: kong
+  listeners:
+  - name: http
+    protocol: HTTP
+    port: 80
+`,
+			expectedErr: "GatewayClass kong used by Gateway default/gw not found in the input",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := Run([]string{"-validate-images=false"}, scheme.Get(), strings.NewReader(tc.input), out)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			objs, err := NewRenderer(Config{}, scheme.Get()).Decode(out)
+			require.NoError(t, err)
+			kinds := make([]string, 0, len(objs))
+			for _, obj := range objs {
+				kinds = append(kinds, obj.GetObjectKind().GroupVersionKind().Kind)
+				require.NotEmpty(t, obj.GetName())
+			}
+			require.Equal(t, tc.expectedKinds, kinds)
+			if tc.assert != nil {
+				tc.assert(t, objs)
+			}
+		})
+	}
+}
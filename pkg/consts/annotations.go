@@ -9,3 +9,217 @@ const (
 	// ControlPlane's ValidatingWebhookConfiguration.
 	AnnotationSpecHash = "gateway-operator.konghq.com/spec-hash"
 )
+
+const (
+	// AnnotationReconciliationPaused is the annotation which, when set to "true"
+	// on an object managed by the operator, pauses the reconciliation of that
+	// object and the objects it owns.
+	// Deletion of the object is still handled while reconciliation is paused.
+	AnnotationReconciliationPaused = "gateway-operator.konghq.com/reconciliation-paused"
+)
+
+const (
+	// AnnotationPropagatedLabels is the annotation set by the operator on DataPlanes
+	// and ControlPlanes holding the JSON encoded labels which are propagated to the
+	// objects generated for them, e.g. Deployments and Services.
+	// It's used to propagate Gateway's spec.infrastructure.labels.
+	AnnotationPropagatedLabels = "gateway-operator.konghq.com/propagated-labels"
+
+	// AnnotationPropagatedAnnotations is the annotation set by the operator on DataPlanes
+	// and ControlPlanes holding the JSON encoded annotations which are propagated to the
+	// objects generated for them, e.g. Deployments and Services.
+	// It's used to propagate Gateway's spec.infrastructure.annotations.
+	AnnotationPropagatedAnnotations = "gateway-operator.konghq.com/propagated-annotations"
+)
+
+const (
+	// AnnotationGatewayConfigurationSharedInfrastructure is the annotation which can
+	// be set to "true" on a GatewayConfiguration to make all the Gateways in its
+	// namespace which use it (with the same GatewayClass) share a single DataPlane
+	// and ControlPlane.
+	// The listeners of these Gateways are merged into the shared DataPlane's
+	// ingress Service. The shared ControlPlane reconciles all the Gateways of the
+	// GatewayClass, hence a dedicated GatewayClass is recommended.
+	AnnotationGatewayConfigurationSharedInfrastructure = "gateway-operator.konghq.com/shared-infrastructure"
+)
+
+const (
+	// AnnotationMaintenanceWindowSchedule is the annotation which can be set on
+	// a GatewayConfiguration, a DataPlane or a ControlPlane to only roll out
+	// changes affecting their Pods inside maintenance windows. Its value is a
+	// standard 5 fields cron expression defining when the windows open.
+	// Changes are held with the PendingMaintenance condition until a window opens.
+	// AnnotationMaintenanceWindowDuration has to be set together with it.
+	//
+	// Example:
+	// gateway-operator.konghq.com/maintenance-window-schedule: "0 2 * * 6"
+	AnnotationMaintenanceWindowSchedule = "gateway-operator.konghq.com/maintenance-window-schedule"
+
+	// AnnotationMaintenanceWindowDuration is the annotation defining how long the
+	// maintenance windows defined by AnnotationMaintenanceWindowSchedule stay open.
+	// The value is a Go duration.
+	//
+	// Example:
+	// gateway-operator.konghq.com/maintenance-window-duration: "2h"
+	AnnotationMaintenanceWindowDuration = "gateway-operator.konghq.com/maintenance-window-duration"
+
+	// AnnotationMaintenanceWindowTimezone is the annotation defining the IANA
+	// time zone in which AnnotationMaintenanceWindowSchedule is evaluated.
+	// It defaults to UTC.
+	//
+	// Example:
+	// gateway-operator.konghq.com/maintenance-window-timezone: "Europe/Berlin"
+	AnnotationMaintenanceWindowTimezone = "gateway-operator.konghq.com/maintenance-window-timezone"
+
+	// AnnotationMaintenanceOverride is the annotation which, when set to "true",
+	// rolls out the changes held until the next maintenance window right away.
+	// It's meant for emergencies and should be removed afterwards.
+	AnnotationMaintenanceOverride = "gateway-operator.konghq.com/maintenance-override"
+)
+
+const (
+	// AnnotationVersionChannel is the annotation which can be set on a DataPlane
+	// or a ControlPlane to automatically upgrade its image to the newest release
+	// of a version channel, resolved from the tags of the image repository.
+	// The image repository is the one of the configured image, or of the default
+	// image when none is configured. When set on a GatewayConfiguration, it
+	// applies to the DataPlanes of its Gateways.
+	//
+	// Example:
+	// gateway-operator.konghq.com/version-channel: "3.9.x"
+	AnnotationVersionChannel = "gateway-operator.konghq.com/version-channel"
+
+	// AnnotationControlPlaneVersionChannel is the annotation which can be set on
+	// a GatewayConfiguration to set the AnnotationVersionChannel annotation of
+	// the ControlPlanes of its Gateways, which run different versions than the
+	// DataPlanes.
+	//
+	// Example:
+	// gateway-operator.konghq.com/controlplane-version-channel: "3.4.x"
+	AnnotationControlPlaneVersionChannel = "gateway-operator.konghq.com/controlplane-version-channel"
+
+	// AnnotationVersionChannelResolvedImage is the annotation set by the operator
+	// on DataPlanes and ControlPlanes holding the image last resolved from their
+	// version channel. It's used when the image repository can't be reached.
+	AnnotationVersionChannelResolvedImage = "gateway-operator.konghq.com/version-channel-resolved-image"
+)
+
+const (
+	// AnnotationStagedRolloutWaves is the annotation which, when set on a
+	// GatewayConfiguration, rolls out changes to its spec to the Gateways using
+	// it in waves instead of all at once. It holds a semicolon separated list of
+	// waves, each being a label selector, a number of Gateways or a percentage
+	// of the Gateways. Gateways which are not part of any wave are rolled out in
+	// a final wave.
+	// Only the spec is rolled out in waves: changes to the GatewayConfiguration's
+	// annotations, e.g. AnnotationVersionChannel, the maintenance window or the
+	// NetworkPolicy annotations, apply to all the Gateways immediately.
+	//
+	// Example:
+	// gateway-operator.konghq.com/staged-rollout-waves: "env=canary;2;25%"
+	AnnotationStagedRolloutWaves = "gateway-operator.konghq.com/staged-rollout-waves"
+
+	// AnnotationStagedRolloutWaveTimeout is the annotation defining how long
+	// the Gateways of a wave have to become Programmed, and their DataPlanes
+	// Ready or RolledOut, before the staged rollout is paused. It defaults to 10m.
+	//
+	// Example:
+	// gateway-operator.konghq.com/staged-rollout-wave-timeout: "30m"
+	AnnotationStagedRolloutWaveTimeout = "gateway-operator.konghq.com/staged-rollout-wave-timeout"
+
+	// AnnotationStagedRolloutPaused is the annotation which, when set to "true"
+	// on a GatewayConfiguration, pauses its staged rollout. It's set by the
+	// operator when a wave fails and has to be removed to resume the rollout,
+	// which restarts the timeout of the wave in progress.
+	AnnotationStagedRolloutPaused = "gateway-operator.konghq.com/staged-rollout-paused"
+
+	// AnnotationGatewayConfigurationRevision is the annotation set by the operator
+	// on Gateways, and on their DataPlanes and ControlPlanes, holding the name of
+	// the ControllerRevision of the GatewayConfiguration spec they use during
+	// staged rollouts.
+	AnnotationGatewayConfigurationRevision = "gateway-operator.konghq.com/gatewayconfiguration-revision"
+
+	// AnnotationGatewayConfigurationRevisionTimestamp is the annotation set by
+	// the operator on Gateways holding the time at which they were moved to their
+	// AnnotationGatewayConfigurationRevision.
+	AnnotationGatewayConfigurationRevisionTimestamp = "gateway-operator.konghq.com/gatewayconfiguration-revision-timestamp"
+)
+
+const (
+	// AnnotationDeletionProtection is the annotation which, when set to "true"
+	// on a Gateway or a DataPlane, protects it from being deleted while routes
+	// are attached to the Gateway's listeners or while its DataPlanes serve
+	// traffic, according to the request rate scraped from them, which requires
+	// a DataPlaneMetricsExtension attached to their ControlPlane.
+	// DataPlanes managed by a Gateway are protected through their Gateway.
+	AnnotationDeletionProtection = "gateway-operator.konghq.com/deletion-protection"
+
+	// AnnotationConfirmDeletion is the annotation which, when set to "true" on
+	// a Gateway or a DataPlane protected with AnnotationDeletionProtection,
+	// confirms that it can be deleted despite being in use.
+	AnnotationConfirmDeletion = "gateway-operator.konghq.com/confirm-deletion"
+)
+
+const (
+	// AnnotationNetworkPolicyProxySourceCIDRs is the annotation which can be set
+	// on a GatewayConfiguration, which propagates it to the DataPlanes of its
+	// Gateways, to only allow traffic to the proxy ports of the DataPlanes from
+	// the comma separated list of CIDRs in the NetworkPolicies generated for
+	// them. When neither this annotation nor
+	// AnnotationNetworkPolicyProxySourceNamespaces is set, the proxy ports are
+	// reachable from anywhere.
+	//
+	// Example:
+	// gateway-operator.konghq.com/network-policy-proxy-source-cidrs: "10.0.0.0/8,192.168.0.0/16"
+	AnnotationNetworkPolicyProxySourceCIDRs = "gateway-operator.konghq.com/network-policy-proxy-source-cidrs"
+
+	// AnnotationNetworkPolicyProxySourceNamespaces is the annotation which can be
+	// set like AnnotationNetworkPolicyProxySourceCIDRs to only allow traffic to
+	// the proxy ports from the Pods of the comma separated list of namespaces.
+	//
+	// Example:
+	// gateway-operator.konghq.com/network-policy-proxy-source-namespaces: "frontend,partners"
+	AnnotationNetworkPolicyProxySourceNamespaces = "gateway-operator.konghq.com/network-policy-proxy-source-namespaces"
+
+	// AnnotationNetworkPolicyMetricsScrapers is the annotation which can be set
+	// like AnnotationNetworkPolicyProxySourceCIDRs to only allow traffic to the
+	// metrics port from the named scrapers. It holds a semicolon separated list
+	// of scrapers, each being a namespace optionally followed by a slash and a
+	// label selector of the scraper Pods in that namespace. When it isn't set,
+	// the metrics port is reachable from anywhere.
+	//
+	// Example:
+	// gateway-operator.konghq.com/network-policy-metrics-scrapers: "monitoring/app.kubernetes.io/name=prometheus;datadog"
+	AnnotationNetworkPolicyMetricsScrapers = "gateway-operator.konghq.com/network-policy-metrics-scrapers"
+
+	// AnnotationNetworkPolicyEgress is the annotation which, when set to
+	// "restricted" like AnnotationNetworkPolicyProxySourceCIDRs, denies all the
+	// egress traffic of the DataPlane Pods except DNS, the Services referenced
+	// as backends by the routes accepted by the listeners of the Gateways using
+	// the DataPlane, Konnect when the DataPlane uses a KonnectExtension, the
+	// self-hosted hybrid control plane endpoints configured in the ConfigMap
+	// named by AnnotationDataPlaneHybridControlPlane, and the CIDRs listed in
+	// the AnnotationNetworkPolicyEgressCIDRs annotation.
+	//
+	// Example:
+	// gateway-operator.konghq.com/network-policy-egress: "restricted"
+	AnnotationNetworkPolicyEgress = "gateway-operator.konghq.com/network-policy-egress"
+
+	// AnnotationNetworkPolicyEgressCIDRs is the annotation holding a comma
+	// separated list of CIDRs the DataPlane Pods can additionally reach when
+	// their egress traffic is restricted with AnnotationNetworkPolicyEgress,
+	// e.g. for plugins calling external services.
+	//
+	// Example:
+	// gateway-operator.konghq.com/network-policy-egress-cidrs: "203.0.113.0/24"
+	AnnotationNetworkPolicyEgressCIDRs = "gateway-operator.konghq.com/network-policy-egress-cidrs"
+)
+
+const (
+	// AnnotationBackendTLSPolicy is the annotation set by the operator on the
+	// Services targeted by a BackendTLSPolicy holding the name of the policy.
+	// The Kong Ingress Controller annotations configuring the upstream TLS of
+	// such Services are managed by the operator and removed when the policy
+	// doesn't apply to them anymore.
+	AnnotationBackendTLSPolicy = "gateway-operator.konghq.com/backend-tls-policy"
+)
@@ -55,6 +55,12 @@ const (
 	// the gateway controller.
 	GatewayManagedLabelValue = "gateway"
 
+	// GatewaySharedInfrastructureLabel is the label set on DataPlanes and ControlPlanes
+	// which are shared by multiple Gateways.
+	// The value set for this label is the UID of the GatewayConfiguration used
+	// by the Gateways sharing the object.
+	GatewaySharedInfrastructureLabel = OperatorLabelPrefix + "shared-infrastructure"
+
 	// ServiceSecretLabel is a label that is added to operator related Service
 	// Secrets to designate which Service this particular Secret it used by.
 	ServiceSecretLabel = OperatorLabelPrefix + "service-secret"
@@ -129,6 +135,14 @@ const (
 	// KongClusterCertVolumeMountPath holds the path where the Kong Cluster certificate
 	// volume will be mounted.
 	KongClusterCertVolumeMountPath = "/etc/secrets/kong-cluster-cert"
+
+	// KongClusterCACertVolume is the name of the volume that holds the CA certificate
+	// used to verify the certificate of a self-hosted Kong control plane.
+	KongClusterCACertVolume = "kong-cluster-ca-cert"
+
+	// KongClusterCACertVolumeMountPath holds the path where the Kong Cluster CA certificate
+	// volume will be mounted.
+	KongClusterCACertVolumeMountPath = "/etc/secrets/kong-cluster-ca-cert"
 )
 
 // -----------------------------------------------------------------------------
@@ -176,6 +190,22 @@ const (
 
 	// DefaultKonnectMaxConcurrentReconciles is the default max concurrent reconciles for Konnect entities.
 	DefaultKonnectMaxConcurrentReconciles = uint(8)
+
+	// DefaultKonnectOrphanedEntitiesGCPeriod is the default period of looking for
+	// orphaned Konnect entities.
+	DefaultKonnectOrphanedEntitiesGCPeriod = time.Hour
+
+	// DefaultKonnectBulkSyncBatchSize is the default maximum number of Konnect
+	// entities updated at once by a bulk sync of a Konnect ControlPlane.
+	DefaultKonnectBulkSyncBatchSize = uint(100)
+
+	// DefaultKongCredentialRotationOverlap is the default duration for which
+	// the previous credential is kept after a rotation.
+	DefaultKongCredentialRotationOverlap = time.Hour
+
+	// DefaultKonnectAPITokenExpiryWarningThreshold is the default duration before
+	// the expiry of a Konnect API token at which the expiry is reported.
+	DefaultKonnectAPITokenExpiryWarningThreshold = 7 * 24 * time.Hour
 )
 
 const (
@@ -186,6 +216,41 @@ const (
 	// Ref: https://docs.konghq.com/kubernetes-ingress-controller/latest/reference/custom-resources/#kongplugin
 	KongIngressControllerPluginsAnnotation = "konghq.com/plugins"
 
+	// KongIngressControllerProtocolAnnotation is the name of the annotation set on
+	// Services which indicates to ControlPlane the protocol used to proxy requests
+	// to the Service.
+	KongIngressControllerProtocolAnnotation = "konghq.com/protocol"
+
+	// KongIngressControllerTLSVerifyAnnotation is the name of the annotation set
+	// on Services which indicates to ControlPlane whether the certificate of the
+	// Service has to be verified.
+	KongIngressControllerTLSVerifyAnnotation = "konghq.com/tls-verify"
+
+	// KongIngressControllerCACertificatesAnnotation is the name of the annotation
+	// set on Services which indicates to ControlPlane the comma separated list of
+	// Secrets, labeled with KongIngressControllerCACertLabel, holding the CA
+	// certificates used to verify the certificate of the Service.
+	KongIngressControllerCACertificatesAnnotation = "konghq.com/ca-certificates"
+
+	// KongIngressControllerHostHeaderAnnotation is the name of the annotation set
+	// on Services which indicates to ControlPlane the host used in the Host header
+	// and as SNI when proxying requests to the Service.
+	KongIngressControllerHostHeaderAnnotation = "konghq.com/host-header"
+
+	// KongIngressControllerCACertLabel is the label set on Secrets holding a CA
+	// certificate under the "cert" key and its ID under the "id" key, which are
+	// loaded by ControlPlane as Kong CA certificates.
+	KongIngressControllerCACertLabel = "konghq.com/ca-cert"
+
+	// KongIngressControllerIngressClassAnnotation is the name of the annotation
+	// set on the Secrets labeled with KongIngressControllerCACertLabel which
+	// indicates which ControlPlane ingress class loads them.
+	KongIngressControllerIngressClassAnnotation = "kubernetes.io/ingress.class"
+
+	// KongIngressControllerDefaultIngressClass is the default ingress class of
+	// ControlPlanes.
+	KongIngressControllerDefaultIngressClass = "kong"
+
 	// KongPluginNamePrometheus is the name of the KongPlugin for the Prometheus plugin.
 	KongPluginNamePrometheus = "prometheus"
 )
@@ -54,6 +54,114 @@ const (
 	// - the "live" Deployment wraps the "live" DataPlane Pods.
 	DataPlaneStateLabelValueLive = "live"
 
+	// DataPlaneStateLabelValueDraining indicates that a DataPlane Deployment is
+	// no longer "live" and its Pods are being drained of active connections
+	// before the Deployment gets deleted.
+	DataPlaneStateLabelValueDraining = "draining"
+
+	// AnnotationDataPlaneConnectionDrainingTimeout is the annotation which can be
+	// set on a DataPlane to enable connection draining of its Pods and Deployments.
+	// Its value is the maximum duration (e.g. "5m") to wait for the active
+	// connections to be closed before the Pods are terminated or Deployments are deleted.
+	AnnotationDataPlaneConnectionDrainingTimeout = "gateway-operator.konghq.com/connection-draining-timeout"
+
+	// AnnotationDataPlaneDrainingStartedAt is the annotation set on a draining
+	// DataPlane Deployment which holds the time (in RFC3339 format) when draining started.
+	AnnotationDataPlaneDrainingStartedAt = "gateway-operator.konghq.com/draining-started-at"
+
+	// AnnotationDataPlaneTopologySpread is the annotation which can be set on
+	// a DataPlane to spread its Pods across topology domains. Its value is
+	// a comma-separated list of domains: "zone" and/or "node".
+	//
+	// Example:
+	// gateway-operator.konghq.com/topology-spread: "zone,node"
+	AnnotationDataPlaneTopologySpread = "gateway-operator.konghq.com/topology-spread"
+
+	// AnnotationDataPlaneTopologySpreadWhenUnsatisfiable is the annotation which
+	// can be set on a DataPlane to configure how the topology spread constraints
+	// generated for AnnotationDataPlaneTopologySpread are enforced.
+	// Its value is either "ScheduleAnyway" (default) or "DoNotSchedule".
+	AnnotationDataPlaneTopologySpreadWhenUnsatisfiable = "gateway-operator.konghq.com/topology-spread-when-unsatisfiable"
+
+	// AnnotationDataPlaneZoneAwarePodDisruptionBudget is the annotation which can
+	// be set to "true" on a DataPlane without a PodDisruptionBudget defined in its
+	// spec to make the operator manage a PodDisruptionBudget which allows to
+	// disrupt at most the share of replicas of a single zone at a time.
+	AnnotationDataPlaneZoneAwarePodDisruptionBudget = "gateway-operator.konghq.com/zone-aware-pod-disruption-budget"
+
+	// AnnotationDataPlanePerZoneIngressServices is the annotation which can be set
+	// to "true" on a DataPlane to make the operator create an additional ingress
+	// Service for each zone of the cluster, targeting only the DataPlane Pods
+	// running in that zone.
+	AnnotationDataPlanePerZoneIngressServices = "gateway-operator.konghq.com/per-zone-ingress-services"
+
+	// AnnotationDataPlaneIngressServiceTrafficDistribution is the annotation which
+	// can be set on a DataPlane to set the traffic distribution (e.g. "PreferClose")
+	// of its ingress Services.
+	// ref: https://kubernetes.io/docs/concepts/services-networking/service/#traffic-distribution
+	AnnotationDataPlaneIngressServiceTrafficDistribution = "gateway-operator.konghq.com/ingress-service-traffic-distribution"
+
+	// AnnotationDataPlaneHybridControlPlane is the annotation which can be set on
+	// a DataPlane to make it join a self-hosted (non-Konnect) Kong control plane
+	// running in hybrid mode. Its value is the name of a ConfigMap, in the DataPlane's
+	// namespace, holding the control plane's cluster configuration.
+	//
+	// Example:
+	// gateway-operator.konghq.com/hybrid-control-plane: "kong-cp"
+	AnnotationDataPlaneHybridControlPlane = "gateway-operator.konghq.com/hybrid-control-plane"
+
+	// DataPlaneHybridConfigKeyControlPlane is the key of the ConfigMap referenced
+	// through AnnotationDataPlaneHybridControlPlane holding the host:port of the
+	// control plane's cluster listener.
+	DataPlaneHybridConfigKeyControlPlane = "cluster_control_plane"
+
+	// DataPlaneHybridConfigKeyTelemetryEndpoint is the key of the ConfigMap
+	// referenced through AnnotationDataPlaneHybridControlPlane holding the
+	// host:port of the control plane's telemetry listener.
+	DataPlaneHybridConfigKeyTelemetryEndpoint = "cluster_telemetry_endpoint"
+
+	// AnnotationDataPlaneIngressServiceStaticIPs is the annotation which can be set
+	// on a DataPlane to request static IP addresses for its ingress Service.
+	// Its value is a comma separated list of IP addresses. For LoadBalancer Services
+	// the address is set as the Service's loadBalancerIP (only a single address is
+	// supported), for other Service types the addresses are set as externalIPs.
+	// The Gateway controller sets it from the Gateway's spec.addresses.
+	//
+	// Example:
+	// gateway-operator.konghq.com/ingress-service-static-ips: "203.0.113.10"
+	AnnotationDataPlaneIngressServiceStaticIPs = "gateway-operator.konghq.com/ingress-service-static-ips"
+
+	// AnnotationDataPlaneWorkloadKind is the annotation which can be set on a
+	// DataPlane to choose the kind of workload running its Pods: "Deployment"
+	// (default) or "DaemonSet". A DaemonSet runs one Pod per eligible Node,
+	// typically with spec.hostNetwork set in the DataPlane's PodTemplateSpec.
+	// The DataPlane's replicas and horizontal scaling settings are ignored for
+	// DaemonSets.
+	//
+	// Example:
+	// gateway-operator.konghq.com/workload-kind: "DaemonSet"
+	AnnotationDataPlaneWorkloadKind = "gateway-operator.konghq.com/workload-kind"
+
+	// DataPlaneWorkloadKindDeployment is the AnnotationDataPlaneWorkloadKind value
+	// which makes the DataPlane Pods run in a Deployment.
+	DataPlaneWorkloadKindDeployment = "Deployment"
+
+	// DataPlaneWorkloadKindDaemonSet is the AnnotationDataPlaneWorkloadKind value
+	// which makes the DataPlane Pods run in a DaemonSet.
+	DataPlaneWorkloadKindDaemonSet = "DaemonSet"
+
+	// ExternalDNSHostnameAnnotation is the external-dns annotation which makes
+	// external-dns create DNS records for the annotated Service. The Gateway
+	// controller sets it on DataPlane ingress Services from the Hostname addresses
+	// in the Gateway's spec.addresses.
+	// ref: https://kubernetes-sigs.github.io/external-dns/latest/docs/annotations/annotations/
+	ExternalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
+	// DataPlaneZoneLabel is the label set on DataPlane Pods with the zone of the
+	// Node they are running on and on the per-zone ingress Services with the zone
+	// they target.
+	DataPlaneZoneLabel = "gateway-operator.konghq.com/zone"
+
 	// DataPlaneAdminServiceLabelValue indicates that the service is intended to expose the
 	// DataPlane admin API.
 	DataPlaneAdminServiceLabelValue ServiceType = "admin"
@@ -61,6 +169,10 @@ const (
 	// DataPlaneIngressServiceLabelValue indicates that the service is intended to expose the
 	// DataPlane proxy.
 	DataPlaneIngressServiceLabelValue ServiceType = "ingress"
+
+	// DataPlaneZoneIngressServiceLabelValue indicates that the service is intended to expose the
+	// DataPlane proxy Pods running in a single zone.
+	DataPlaneZoneIngressServiceLabelValue ServiceType = "ingress-zone"
 )
 
 // -----------------------------------------------------------------------------
@@ -28,6 +28,10 @@ const (
 	// referenced by KonnectExtension to ensure that the secret is not deleted
 	// when in use by an active KonnectExtension.
 	KonnectExtensionSecretInUseFinalizer = "gateway.konghq.com/secret-in-use"
+	// KongCredentialMTLSCleanupFinalizer is the finalizer added to mtls-auth
+	// credential Secrets to ensure that the mtls-auth credentials created in Konnect
+	// out of them are deleted when the Secret is deleted.
+	KongCredentialMTLSCleanupFinalizer = "gateway.konghq.com/mtls-auth-credential-cleanup"
 )
 
 // -----------------------------------------------------------------------------
@@ -57,4 +61,118 @@ const (
 	// of all the certificates created out of the secret, separated by commas.
 	// Example: konnect.konghq.com/certificate-ids: "xxxxxx,yyyyyy,zzzzzz"
 	DataPlaneCertificateIDAnnotationKey = "konnect.konghq.com/certificate-ids"
+
+	// KonnectDeletionPolicyAnnotationKey is the annotation key which can be set
+	// on Konnect entities' objects or on their Namespaces to configure what
+	// happens with the Konnect entity when the object is deleted.
+	// Valid values are "Delete" (default) and "Orphan". The object's annotation
+	// takes precedence over the Namespace's one.
+	// Example: konnect.konghq.com/deletion-policy: "Orphan"
+	KonnectDeletionPolicyAnnotationKey = "konnect.konghq.com/deletion-policy"
+
+	// KonnectOrphanedEntitiesGCAnnotationKey is the annotation key which can be set
+	// to "true" on KonnectGatewayControlPlanes to periodically look for Konnect
+	// entities in the ControlPlane which were created for Kubernetes objects that
+	// do not exist anymore.
+	// Example: konnect.konghq.com/orphaned-entities-gc: "true"
+	KonnectOrphanedEntitiesGCAnnotationKey = "konnect.konghq.com/orphaned-entities-gc"
+
+	// KonnectMirrorEntitiesAnnotationKey is the annotation key which can be set
+	// to "true" on KonnectGatewayControlPlanes to mirror the entities configured
+	// directly in the Konnect ControlPlane (e.g. through the Konnect UI) into the
+	// ControlPlane's namespace as read-only objects, so that they can be referenced
+	// by other objects.
+	// Example: konnect.konghq.com/mirror-entities: "true"
+	KonnectMirrorEntitiesAnnotationKey = "konnect.konghq.com/mirror-entities"
+
+	// KonnectMirroredFromControlPlaneLabelKey is the label key set on read-only
+	// objects mirroring Konnect entities. Its value is the name of the
+	// KonnectGatewayControlPlane the entities were mirrored from.
+	// Such objects are not reconciled against Konnect.
+	KonnectMirroredFromControlPlaneLabelKey = "konnect.konghq.com/mirrored-from-control-plane"
+
+	// KonnectSyncModeAnnotationKey is the annotation key which can be set on
+	// KonnectGatewayControlPlanes to configure how the KongServices, KongRoutes
+	// and KongConsumers referencing them are periodically synced with Konnect.
+	// Valid values are "PerEntity" (default), where each object is synced on its own,
+	// and "Bulk", where the entities of all the objects are read from Konnect at once
+	// and only the ones differing from the objects are updated, in batches.
+	// Example: konnect.konghq.com/sync-mode: "Bulk"
+	KonnectSyncModeAnnotationKey = "konnect.konghq.com/sync-mode"
+	// KonnectSyncModeBulk is the KonnectSyncModeAnnotationKey annotation's value
+	// enabling the bulk sync mode.
+	KonnectSyncModeBulk = "Bulk"
+
+	// KonnectControlPlaneGroupAnnotationKey is the annotation key which can be set
+	// on KonnectGatewayControlPlanes to make them members of the control plane group
+	// with the given name, in the same namespace, in addition to the members listed
+	// in the group's spec.members.
+	// Example: konnect.konghq.com/control-plane-group: "shared-group"
+	KonnectControlPlaneGroupAnnotationKey = "konnect.konghq.com/control-plane-group"
+
+	// KongCredentialGenerateAnnotationKey is the annotation key which can be set
+	// to "true" on credential Secrets (key-auth, basic-auth and hmac) to make the
+	// operator generate the missing credential values (keys, passwords, secrets and usernames).
+	// Example: konnect.konghq.com/credential-generate: "true"
+	KongCredentialGenerateAnnotationKey = "konnect.konghq.com/credential-generate"
+	// KongCredentialGeneratedKeysAnnotationKey is the annotation key set by the operator
+	// on credential Secrets holding a comma separated list of the Secret's keys
+	// whose values were generated.
+	KongCredentialGeneratedKeysAnnotationKey = "konnect.konghq.com/credential-generated-keys"
+	// KongCredentialRotatedAtAnnotationKey is the annotation key set by the operator
+	// on credential Secrets holding the time (RFC 3339) at which the credential was
+	// last generated or rotated.
+	KongCredentialRotatedAtAnnotationKey = "konnect.konghq.com/credential-rotated-at"
+	// KongCredentialRotationIntervalAnnotationKey is the annotation key which can be set
+	// on credential Secrets with generated credentials to rotate them periodically.
+	// Example: konnect.konghq.com/credential-rotation-interval: "720h"
+	KongCredentialRotationIntervalAnnotationKey = "konnect.konghq.com/credential-rotation-interval"
+	// KongCredentialRotateAnnotationKey is the annotation key which can be set on
+	// credential Secrets with generated credentials to request their rotation.
+	// The credential is rotated whenever the annotation's value changes.
+	// Example: konnect.konghq.com/credential-rotate: "2025-01-01"
+	KongCredentialRotateAnnotationKey = "konnect.konghq.com/credential-rotate"
+	// KongCredentialRotateHandledAnnotationKey is the annotation key set by the operator
+	// on credential Secrets holding the value of the KongCredentialRotateAnnotationKey
+	// annotation for which the credential was last rotated.
+	KongCredentialRotateHandledAnnotationKey = "konnect.konghq.com/credential-rotate-handled"
+	// KongCredentialRotationOverlapAnnotationKey is the annotation key which can be set
+	// on credential Secrets to configure for how long the previous credential is kept
+	// in Konnect after a rotation.
+	// Example: konnect.konghq.com/credential-rotation-overlap: "24h"
+	KongCredentialRotationOverlapAnnotationKey = "konnect.konghq.com/credential-rotation-overlap"
+	// KongCredentialRetiredAtAnnotationKey is the annotation key set by the operator
+	// on managed credentials replaced by a rotation, holding the time (RFC 3339) of
+	// the rotation. Retired credentials are deleted after the rotation overlap.
+	KongCredentialRetiredAtAnnotationKey = "konnect.konghq.com/credential-retired-at"
+	// KongCredentialMTLSConsumersAnnotationKey is the annotation key set by the operator
+	// on mtls-auth credential Secrets holding a comma separated list of the consumers
+	// (<control plane namespace>/<control plane name>/<consumer Konnect ID>) which
+	// the mtls-auth credentials were created for in Konnect.
+	KongCredentialMTLSConsumersAnnotationKey = "konnect.konghq.com/mtls-auth-consumers"
+
+	// KonnectAPITokenExpiresAtAnnotationKey is the annotation key which can be set
+	// on KonnectAPIAuthConfigurations (for inline tokens) or on the Secrets they
+	// reference to declare the time (RFC 3339) at which the Konnect API token expires.
+	// It's used when the expiry can't be read from Konnect, i.e. for personal
+	// access tokens or when KonnectAPISystemAccountTokenAnnotationKey is not set.
+	// Example: konnect.konghq.com/token-expires-at: "2025-06-30T00:00:00Z"
+	KonnectAPITokenExpiresAtAnnotationKey = "konnect.konghq.com/token-expires-at"
+	// KonnectAPISecondaryTokenExpiresAtAnnotationKey is the annotation key which can be
+	// set on Secrets referenced by KonnectAPIAuthConfigurations to declare the time
+	// (RFC 3339) at which the secondary Konnect API token expires.
+	KonnectAPISecondaryTokenExpiresAtAnnotationKey = "konnect.konghq.com/secondary-token-expires-at"
+
+	// KonnectAPISystemAccountTokenAnnotationKey is the annotation key which can be
+	// set on KonnectAPIAuthConfigurations (for inline tokens) or on the Secrets they
+	// reference to identify the system account access token in use with the IDs of
+	// its system account and of the token, separated by a slash. The expiry of the
+	// token is then read from Konnect, taking precedence over the one declared with
+	// KonnectAPITokenExpiresAtAnnotationKey.
+	// Example: konnect.konghq.com/system-account-token: "<system account ID>/<token ID>"
+	KonnectAPISystemAccountTokenAnnotationKey = "konnect.konghq.com/system-account-token"
+	// KonnectAPISecondarySystemAccountTokenAnnotationKey is the annotation key which
+	// can be set on Secrets referenced by KonnectAPIAuthConfigurations to identify
+	// the secondary system account access token like KonnectAPISystemAccountTokenAnnotationKey.
+	KonnectAPISecondarySystemAccountTokenAnnotationKey = "konnect.konghq.com/secondary-system-account-token"
 )
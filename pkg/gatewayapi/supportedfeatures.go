@@ -9,6 +9,10 @@ import (
 	"github.com/kong/gateway-operator/pkg/consts"
 )
 
+// SupportBackendTLSPolicy is the name of the BackendTLSPolicy feature.
+// It's not defined in the Gateway API version the operator depends on yet.
+const SupportBackendTLSPolicy features.FeatureName = "BackendTLSPolicy"
+
 var (
 	traditionalCompatibleRouterSupportedFeatures = commonSupportedFeatures.Clone().Insert(
 	// add here the traditional compatible router specific features
@@ -30,11 +34,15 @@ var (
 
 		// Gateway extended
 		features.SupportGatewayPort8080,
+		features.SupportGatewayStaticAddresses,
 
 		// HTTPRoute extended
 		features.SupportHTTPRouteResponseHeaderModification,
 		features.SupportHTTPRoutePathRewrite,
 		features.SupportHTTPRouteHostRewrite,
+
+		// BackendTLSPolicy extended
+		SupportBackendTLSPolicy,
 	)
 )
 
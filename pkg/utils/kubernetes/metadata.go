@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	"encoding/json"
 	"fmt"
 	"maps"
 	"slices"
@@ -8,6 +9,8 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/pkg/consts"
 )
 
 // -----------------------------------------------------------------------------
@@ -30,6 +33,12 @@ func EnsureObjectMetaIsUpdated(
 ) (toUpdate bool, updatedMeta metav1.ObjectMeta) {
 	var metaToUpdate bool
 
+	// Remove the labels and annotations which were propagated from the owner
+	// but are not propagated anymore.
+	if prunePropagatedMetadata(&existingMeta, generatedMeta) {
+		metaToUpdate = true
+	}
+
 	// Compare and enforce annotations. Take into account the fact that we don't
 	// want to compare all annotations as some might be added by other controllers.
 	// We only want to compare the annotations that are added by the operator.
@@ -80,6 +89,43 @@ func EnsureObjectMetaIsUpdated(
 	return metaToUpdate, existingMeta
 }
 
+// prunePropagatedMetadata removes from the existing object metadata the labels
+// and annotations recorded as propagated from the owner (see resources.PropagateMetadata)
+// which are not present in the generated object metadata anymore.
+// It returns true when the existing object metadata was changed.
+func prunePropagatedMetadata(existingMeta *metav1.ObjectMeta, generatedMeta metav1.ObjectMeta) bool {
+	var changed bool
+	for _, record := range []struct {
+		annotation string
+		existing   map[string]string
+		generated  map[string]string
+	}{
+		{consts.AnnotationPropagatedLabels, existingMeta.Labels, generatedMeta.Labels},
+		{consts.AnnotationPropagatedAnnotations, existingMeta.Annotations, generatedMeta.Annotations},
+	} {
+		v, ok := existingMeta.Annotations[record.annotation]
+		if !ok {
+			continue
+		}
+		var propagated map[string]string
+		_ = json.Unmarshal([]byte(v), &propagated)
+		for k := range propagated {
+			if _, ok := record.generated[k]; ok {
+				continue
+			}
+			if _, ok := record.existing[k]; ok {
+				delete(record.existing, k)
+				changed = true
+			}
+		}
+		if _, ok := generatedMeta.Annotations[record.annotation]; !ok {
+			delete(existingMeta.Annotations, record.annotation)
+			changed = true
+		}
+	}
+	return changed
+}
+
 // TrimGenerateName cut the string to 63 chars, in case it is longer,
 // to be compliant with the GenerateName length maximum size of 63 chars.
 func TrimGenerateName(name string) string {
@@ -91,3 +137,14 @@ func TrimGenerateName(name string) string {
 	}
 	return name
 }
+
+// SetNameFromGenerateName sets the name of the provided object to its
+// GenerateName (without the trailing dash) if the name is not set yet.
+// This is useful when objects are generated without an API server which would
+// otherwise generate the name, e.g. when rendering manifests offline.
+func SetNameFromGenerateName(obj metav1.Object) {
+	if obj.GetName() != "" || obj.GetGenerateName() == "" {
+		return
+	}
+	obj.SetName(strings.TrimSuffix(obj.GetGenerateName(), "-"))
+}
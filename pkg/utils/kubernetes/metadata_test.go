@@ -6,6 +6,8 @@ import (
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/gateway-operator/pkg/consts"
 )
 
 func TestEnsureObjectMetaIsUpdated(t *testing.T) {
@@ -205,6 +207,42 @@ func TestEnsureObjectMetaIsUpdated(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "meta to update because of annotations not propagated anymore",
+			existingObjMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"foo":  "bar",
+					"team": "a",
+				},
+				Annotations: map[string]string{
+					"cost-center":                          "42",
+					"owner":                                "team-a",
+					"external":                             "value",
+					consts.AnnotationPropagatedLabels:      `{"team":"a"}`,
+					consts.AnnotationPropagatedAnnotations: `{"cost-center":"42","owner":"team-a"}`,
+				},
+			},
+			generatedObjMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"foo": "bar",
+				},
+				Annotations: map[string]string{
+					"owner":                                "team-a",
+					consts.AnnotationPropagatedAnnotations: `{"owner":"team-a"}`,
+				},
+			},
+			toUpdate: true,
+			resultingObjMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"foo": "bar",
+				},
+				Annotations: map[string]string{
+					"owner":                                "team-a",
+					"external":                             "value",
+					consts.AnnotationPropagatedAnnotations: `{"owner":"team-a"}`,
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
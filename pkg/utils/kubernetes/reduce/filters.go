@@ -225,6 +225,48 @@ func filterDeployments(deployments []appsv1.Deployment) []appsv1.Deployment {
 	return append(deployments[:toFilter], deployments[toFilter+1:]...)
 }
 
+// -----------------------------------------------------------------------------
+// Filter functions - DaemonSets
+// -----------------------------------------------------------------------------
+
+// filterDaemonSets filters out the DaemonSet to be kept and returns
+// all the DaemonSets to be deleted.
+//
+// The filtered-out DaemonSet is decided as follows:
+// 1. number of available Pods (higher is better)
+// 2. number of ready Pods (higher is better)
+// 3. creationTimestamp (older is better)
+func filterDaemonSets(daemonSets []appsv1.DaemonSet) []appsv1.DaemonSet {
+	if len(daemonSets) < 2 {
+		return []appsv1.DaemonSet{}
+	}
+
+	toFilter := 0
+	for i, daemonSet := range daemonSets {
+		// check which daemonset has more available Pods
+		if daemonSet.Status.NumberAvailable != daemonSets[toFilter].Status.NumberAvailable {
+			if daemonSet.Status.NumberAvailable > daemonSets[toFilter].Status.NumberAvailable {
+				toFilter = i
+			}
+			continue
+		}
+		// check which daemonset has more ready Pods
+		if daemonSet.Status.NumberReady != daemonSets[toFilter].Status.NumberReady {
+			if daemonSet.Status.NumberReady > daemonSets[toFilter].Status.NumberReady {
+				toFilter = i
+			}
+			continue
+		}
+		// check the older daemonset
+		if daemonSet.CreationTimestamp.Before(&daemonSets[toFilter].CreationTimestamp) {
+			toFilter = i
+			continue
+		}
+	}
+
+	return append(daemonSets[:toFilter], daemonSets[toFilter+1:]...)
+}
+
 // -----------------------------------------------------------------------------
 // Filter functions - Services
 // -----------------------------------------------------------------------------
@@ -227,6 +227,103 @@ func TestFilterDeployments(t *testing.T) {
 	}
 }
 
+func TestFilterDaemonSets(t *testing.T) {
+	testCases := []struct {
+		name               string
+		daemonSets         []appsv1.DaemonSet
+		filteredDaemonSets []appsv1.DaemonSet
+	}{
+		{
+			name: "the older daemonset must be filtered out",
+			daemonSets: []appsv1.DaemonSet{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "1/1/2000",
+						CreationTimestamp: metav1.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "12/31/1995",
+						CreationTimestamp: metav1.Date(1995, time.December, 31, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			filteredDaemonSets: []appsv1.DaemonSet{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "1/1/2000",
+						CreationTimestamp: metav1.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			name: "the daemonset with more available Pods must be filtered out",
+			daemonSets: []appsv1.DaemonSet{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "1-available",
+						CreationTimestamp: metav1.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+					},
+					Status: appsv1.DaemonSetStatus{
+						NumberAvailable: 1,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "0-available",
+						CreationTimestamp: metav1.Date(1995, time.December, 31, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			filteredDaemonSets: []appsv1.DaemonSet{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "0-available",
+						CreationTimestamp: metav1.Date(1995, time.December, 31, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			name: "the daemonset with more ready Pods must be filtered out",
+			daemonSets: []appsv1.DaemonSet{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "1-ready",
+						CreationTimestamp: metav1.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+					},
+					Status: appsv1.DaemonSetStatus{
+						NumberReady: 1,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "0-ready",
+						CreationTimestamp: metav1.Date(1995, time.December, 31, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			filteredDaemonSets: []appsv1.DaemonSet{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "0-ready",
+						CreationTimestamp: metav1.Date(1995, time.December, 31, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filteredDaemonSets := filterDaemonSets(tc.daemonSets)
+			require.Equal(t, tc.filteredDaemonSets, filteredDaemonSets)
+		})
+	}
+}
+
 func TestFilterServices(t *testing.T) {
 	testCases := []struct {
 		name             string
@@ -31,7 +31,18 @@ func AllowedByReferenceGrants(
 	if err != nil {
 		return false, err
 	}
-	for _, referenceGrant := range referenceGrantList.Items {
+	return ReferenceGrantsAllow(referenceGrantList.Items, from, to), nil
+}
+
+// ReferenceGrantsAllow checks if the reference from the input `from` to the object(s) with
+// group, kind, name given in the input `to` is allowed by any of the provided ReferenceGrants,
+// which are expected to be in the namespace of the referenced object(s).
+func ReferenceGrantsAllow(
+	referenceGrants []gatewayv1beta1.ReferenceGrant,
+	from gatewayv1beta1.ReferenceGrantFrom,
+	to gatewayv1beta1.ReferenceGrantTo,
+) bool {
+	for _, referenceGrant := range referenceGrants {
 		// If the `spec.from` does not contain the input `from`, we skip the ReferenceGrant
 		// because it is impossible to grant the reference to the input `from`.
 		if !lo.ContainsBy(referenceGrant.Spec.From, func(refGrantFrom gatewayv1beta1.ReferenceGrantFrom) bool {
@@ -49,11 +60,11 @@ func AllowedByReferenceGrants(
 				// check if the name matches: allow if `spec.to` has no name, or they both have name and equal.
 				(refGrantTo.Name == nil || (to.Name != nil && *refGrantTo.Name == *to.Name))
 		}) {
-			return true, nil
+			return true
 		}
 	}
 	// If we did not find one ReferenceGrant that allows the reference, return false.
-	return false, nil
+	return false
 }
 
 // isSameGroup returns true if the two `Group`s are the same. `core` and empty are equivalent.
@@ -1,9 +1,12 @@
 package resources
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/gateway-operator/pkg/consts"
@@ -42,3 +45,118 @@ func AnnotateObjWithHash[T any](
 
 	return nil
 }
+
+// SetPropagatedMetadata sets the provided labels and annotations on obj and records
+// them in the consts.AnnotationPropagatedLabels and consts.AnnotationPropagatedAnnotations
+// annotations, so that PropagateMetadata can propagate them to the objects generated for obj.
+// Labels and annotations recorded previously which are not provided anymore are
+// removed from obj. It returns true when obj's metadata was changed.
+func SetPropagatedMetadata(obj metav1.Object, labels, annotations map[string]string) bool {
+	prevLabels, prevAnnotations := propagatedMetadata(obj)
+
+	objLabels := maps.Clone(obj.GetLabels())
+	if objLabels == nil {
+		objLabels = make(map[string]string, len(labels))
+	}
+	objAnnotations := maps.Clone(obj.GetAnnotations())
+	if objAnnotations == nil {
+		objAnnotations = make(map[string]string, len(annotations)+2)
+	}
+
+	for k := range prevLabels {
+		if _, ok := labels[k]; !ok {
+			delete(objLabels, k)
+		}
+	}
+	for k := range prevAnnotations {
+		if _, ok := annotations[k]; !ok {
+			delete(objAnnotations, k)
+		}
+	}
+	maps.Copy(objLabels, labels)
+	maps.Copy(objAnnotations, annotations)
+	setEncodedAnnotation(objAnnotations, consts.AnnotationPropagatedLabels, labels)
+	setEncodedAnnotation(objAnnotations, consts.AnnotationPropagatedAnnotations, annotations)
+
+	changed := !maps.Equal(obj.GetLabels(), objLabels) || !maps.Equal(obj.GetAnnotations(), objAnnotations)
+	obj.SetLabels(objLabels)
+	obj.SetAnnotations(objAnnotations)
+	return changed
+}
+
+// PropagateMetadata adds the labels and annotations recorded on the owner with
+// SetPropagatedMetadata to the provided object. Labels and annotations already
+// set on the object are not overridden.
+// The propagated labels and annotations are recorded on the object as well, so
+// that they can be removed from the existing objects once they're not propagated
+// anymore (see k8sutils.EnsureObjectMetaIsUpdated).
+func PropagateMetadata(owner metav1.Object, obj metav1.Object) {
+	labels, annotations := propagatedMetadata(owner)
+	propagatedLabels := make(map[string]string, len(labels))
+	objLabels := obj.GetLabels()
+	if objLabels == nil && len(labels) > 0 {
+		objLabels = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		if _, ok := objLabels[k]; !ok {
+			objLabels[k] = v
+			propagatedLabels[k] = v
+		}
+	}
+	obj.SetLabels(objLabels)
+
+	propagatedAnnotations := make(map[string]string, len(annotations))
+	objAnnotations := obj.GetAnnotations()
+	if objAnnotations == nil && len(annotations)+len(propagatedLabels) > 0 {
+		objAnnotations = make(map[string]string, len(annotations)+2)
+	}
+	for k, v := range annotations {
+		if _, ok := objAnnotations[k]; !ok {
+			objAnnotations[k] = v
+			propagatedAnnotations[k] = v
+		}
+	}
+	if objAnnotations != nil {
+		setEncodedAnnotation(objAnnotations, consts.AnnotationPropagatedLabels, propagatedLabels)
+		setEncodedAnnotation(objAnnotations, consts.AnnotationPropagatedAnnotations, propagatedAnnotations)
+	}
+	obj.SetAnnotations(objAnnotations)
+}
+
+// PropagatedMetadataMatches returns true when the labels and annotations recorded
+// as propagated with PropagateMetadata on both of the provided objects are the same.
+func PropagatedMetadataMatches(existing, generated metav1.Object) bool {
+	for _, key := range []string{consts.AnnotationPropagatedLabels, consts.AnnotationPropagatedAnnotations} {
+		if existing.GetAnnotations()[key] != generated.GetAnnotations()[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// propagatedMetadata returns the labels and annotations recorded on the provided
+// object with SetPropagatedMetadata.
+func propagatedMetadata(obj metav1.Object) (labels, annotations map[string]string) {
+	anns := obj.GetAnnotations()
+	if v, ok := anns[consts.AnnotationPropagatedLabels]; ok {
+		_ = json.Unmarshal([]byte(v), &labels)
+	}
+	if v, ok := anns[consts.AnnotationPropagatedAnnotations]; ok {
+		_ = json.Unmarshal([]byte(v), &annotations)
+	}
+	return labels, annotations
+}
+
+// setEncodedAnnotation sets the JSON encoded value under the provided key or
+// removes the key when the value is empty.
+func setEncodedAnnotation(annotations map[string]string, key string, value map[string]string) {
+	if len(value) == 0 {
+		delete(annotations, key)
+		return
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	annotations[key] = string(b)
+}
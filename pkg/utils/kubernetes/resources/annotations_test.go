@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kong/gateway-operator/pkg/consts"
 
@@ -73,3 +74,65 @@ func TestSpecHash(t *testing.T) {
 		})
 	}
 }
+
+func TestSetPropagatedMetadata(t *testing.T) {
+	obj := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"app": "kong"},
+			Annotations: map[string]string{"existing": "value"},
+		},
+	}
+
+	require.True(t, SetPropagatedMetadata(obj,
+		map[string]string{"team": "a", "tier": "edge"},
+		map[string]string{"cost-center": "42"},
+	))
+	assert.Equal(t, map[string]string{"app": "kong", "team": "a", "tier": "edge"}, obj.Labels)
+	assert.Equal(t, "42", obj.Annotations["cost-center"])
+	assert.Equal(t, "value", obj.Annotations["existing"])
+
+	require.False(t, SetPropagatedMetadata(obj,
+		map[string]string{"team": "a", "tier": "edge"},
+		map[string]string{"cost-center": "42"},
+	), "setting the same metadata again should not report a change")
+
+	require.True(t, SetPropagatedMetadata(obj, map[string]string{"team": "b"}, nil))
+	assert.Equal(t, map[string]string{"app": "kong", "team": "b"}, obj.Labels)
+	assert.Equal(t, map[string]string{
+		"existing":                        "value",
+		consts.AnnotationPropagatedLabels: `{"team":"b"}`,
+	}, obj.Annotations)
+
+	require.True(t, SetPropagatedMetadata(obj, nil, nil))
+	assert.Equal(t, map[string]string{"app": "kong"}, obj.Labels)
+	assert.Equal(t, map[string]string{"existing": "value"}, obj.Annotations)
+}
+
+func TestPropagateMetadata(t *testing.T) {
+	owner := &operatorv1beta1.DataPlane{}
+	SetPropagatedMetadata(owner,
+		map[string]string{"team": "a", "app": "override-attempt"},
+		map[string]string{"cost-center": "42"},
+	)
+
+	obj := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "kong"},
+		},
+	}
+	PropagateMetadata(owner, obj)
+	assert.Equal(t, map[string]string{"app": "kong", "team": "a"}, obj.Labels)
+	assert.Equal(t, map[string]string{
+		"cost-center":                          "42",
+		consts.AnnotationPropagatedLabels:      `{"team":"a"}`,
+		consts.AnnotationPropagatedAnnotations: `{"cost-center":"42"}`,
+	}, obj.Annotations)
+
+	notPropagated := &corev1.Service{}
+	PropagateMetadata(&operatorv1beta1.DataPlane{}, notPropagated)
+	assert.Nil(t, notPropagated.Labels)
+	assert.Nil(t, notPropagated.Annotations)
+
+	assert.True(t, PropagatedMetadataMatches(obj, obj.DeepCopy()))
+	assert.False(t, PropagatedMetadataMatches(obj, notPropagated))
+}
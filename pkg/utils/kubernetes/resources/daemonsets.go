@@ -0,0 +1,46 @@
+package resources
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	pkgapisappsv1 "k8s.io/kubernetes/pkg/apis/apps/v1"
+)
+
+// GenerateDaemonSetFromDeployment generates a DaemonSet running the Pods of the
+// provided Deployment on every eligible Node. The Deployment's metadata (including
+// owner references and finalizers), selector and Pod template are carried over.
+//
+// Pods using the host network are updated by deleting the old Pod before
+// creating its replacement so that they don't compete for the same host ports.
+// They also get the ClusterFirstWithHostNet DNS policy so that cluster DNS names
+// keep resolving.
+func GenerateDaemonSetFromDeployment(deployment *appsv1.Deployment) *appsv1.DaemonSet {
+	deployment = deployment.DeepCopy()
+
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: deployment.ObjectMeta,
+		Spec: appsv1.DaemonSetSpec{
+			Selector:             deployment.Spec.Selector,
+			Template:             deployment.Spec.Template,
+			RevisionHistoryLimit: deployment.Spec.RevisionHistoryLimit,
+			MinReadySeconds:      deployment.Spec.MinReadySeconds,
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+					MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+					MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+				},
+			},
+		},
+	}
+
+	podSpec := &daemonSet.Spec.Template.Spec
+	if podSpec.HostNetwork &&
+		(podSpec.DNSPolicy == "" || podSpec.DNSPolicy == corev1.DNSClusterFirst) {
+		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	}
+
+	pkgapisappsv1.SetDefaults_DaemonSet(daemonSet)
+	return daemonSet
+}
@@ -0,0 +1,63 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestGenerateDaemonSetFromDeployment(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "gateway-operator.konghq.com/v1beta1",
+			Kind:       "DataPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "test-namespace",
+			UID:       "dp-uid",
+		},
+	}
+	deployment, err := GenerateNewDeploymentForDataPlane(dataplane, "kong:3.9")
+	require.NoError(t, err)
+
+	t.Run("keeps the Deployment metadata, selector and template", func(t *testing.T) {
+		daemonSet := GenerateDaemonSetFromDeployment(deployment.Unwrap())
+		require.Equal(t, deployment.GenerateName, daemonSet.GenerateName)
+		require.Equal(t, deployment.Labels, daemonSet.Labels)
+		require.Equal(t, deployment.OwnerReferences, daemonSet.OwnerReferences)
+		require.Contains(t, daemonSet.Finalizers, string(consts.DataPlaneOwnedWaitForOwnerFinalizer))
+		require.Equal(t, deployment.Spec.Selector, daemonSet.Spec.Selector)
+		require.Equal(t, deployment.Spec.Template, daemonSet.Spec.Template)
+		require.Equal(t, appsv1.RollingUpdateDaemonSetStrategyType, daemonSet.Spec.UpdateStrategy.Type)
+		require.Equal(t, intstr.FromInt32(0), *daemonSet.Spec.UpdateStrategy.RollingUpdate.MaxSurge)
+		require.Equal(t, intstr.FromInt32(1), *daemonSet.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable)
+	})
+
+	t.Run("uses the cluster DNS with host network", func(t *testing.T) {
+		hostNetworkDeployment := deployment.Unwrap().DeepCopy()
+		hostNetworkDeployment.Spec.Template.Spec.HostNetwork = true
+
+		daemonSet := GenerateDaemonSetFromDeployment(hostNetworkDeployment)
+		require.True(t, daemonSet.Spec.Template.Spec.HostNetwork)
+		require.Equal(t, corev1.DNSClusterFirstWithHostNet, daemonSet.Spec.Template.Spec.DNSPolicy)
+		require.Equal(t, corev1.DNSClusterFirst, hostNetworkDeployment.Spec.Template.Spec.DNSPolicy, "Deployment must not be modified")
+	})
+
+	t.Run("keeps a custom DNS policy with host network", func(t *testing.T) {
+		hostNetworkDeployment := deployment.Unwrap().DeepCopy()
+		hostNetworkDeployment.Spec.Template.Spec.HostNetwork = true
+		hostNetworkDeployment.Spec.Template.Spec.DNSPolicy = corev1.DNSDefault
+
+		daemonSet := GenerateDaemonSetFromDeployment(hostNetworkDeployment)
+		require.Equal(t, corev1.DNSDefault, daemonSet.Spec.Template.Spec.DNSPolicy)
+	})
+}
@@ -120,6 +120,7 @@ func GenerateNewDeploymentForControlPlane(params GenerateNewDeploymentForControl
 	}
 	SetDefaultsPodTemplateSpec(&deployment.Spec.Template)
 	LabelObjectAsControlPlaneManaged(deployment)
+	PropagateMetadata(params.ControlPlane, deployment)
 
 	if params.ControlPlane.Spec.Deployment.PodTemplateSpec != nil {
 		patchedPodTemplateSpec, err := StrategicMergePatchPodTemplateSpec(&deployment.Spec.Template, params.ControlPlane.Spec.Deployment.PodTemplateSpec)
@@ -307,6 +308,7 @@ func GenerateNewDeploymentForDataPlane(
 
 	SetDefaultsPodTemplateSpec(&deployment.Spec.Template)
 	LabelObjectAsDataPlaneManaged(deployment)
+	PropagateMetadata(dataplane, deployment)
 
 	for _, opt := range opts {
 		if opt != nil {
@@ -5,6 +5,7 @@ import (
 
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/gateway-operator/pkg/consts"
@@ -19,11 +20,37 @@ func GeneratePodDisruptionBudgetForDataPlane(dataplane *operatorv1beta1.DataPlan
 		return nil, fmt.Errorf("cannot generate PodDisruptionBudget for DataPlane which doesn't have PodDisruptionBudget defined")
 	}
 
+	pdbSpec := dataplane.Spec.Resources.PodDisruptionBudget.Spec
+	pdb := newPodDisruptionBudgetForDataPlane(dataplane)
+	// The rest of the fields is directly copied from the DP's PDB spec.
+	pdb.Spec.MinAvailable = pdbSpec.MinAvailable
+	pdb.Spec.MaxUnavailable = pdbSpec.MaxUnavailable
+	pdb.Spec.UnhealthyPodEvictionPolicy = pdbSpec.UnhealthyPodEvictionPolicy
+
+	return pdb, nil
+}
+
+// GenerateZoneAwarePodDisruptionBudgetForDataPlane generates a PodDisruptionBudget
+// for the given DataPlane which allows to disrupt at most the share of its replicas
+// which would run in a single zone when spread evenly across the provided number
+// of zones. When there are fewer than 2 zones, a single replica can be disrupted at a time.
+func GenerateZoneAwarePodDisruptionBudgetForDataPlane(dataplane *operatorv1beta1.DataPlane, zones int) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt32(1)
+	if zones > 1 {
+		// Percentage is rounded up to the number of replicas, e.g. 33% of 2 replicas is 1.
+		maxUnavailable = intstr.FromString(fmt.Sprintf("%d%%", 100/zones))
+	}
+
+	pdb := newPodDisruptionBudgetForDataPlane(dataplane)
+	pdb.Spec.MaxUnavailable = &maxUnavailable
+	return pdb
+}
+
+func newPodDisruptionBudgetForDataPlane(dataplane *operatorv1beta1.DataPlane) *policyv1.PodDisruptionBudget {
 	labels := GetManagedLabelForOwner(dataplane)
 	labels["app"] = dataplane.Name
 	labels[consts.OperatorLabelSelector] = dataplane.Status.Selector
 
-	pdbSpec := dataplane.Spec.Resources.PodDisruptionBudget.Spec
 	pdb := &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      dataplane.Name,
@@ -40,14 +67,10 @@ func GeneratePodDisruptionBudgetForDataPlane(dataplane *operatorv1beta1.DataPlan
 					consts.OperatorLabelSelector: dataplane.Status.Selector,
 				},
 			},
-			// The rest of the fields is directly copied from the DP's PDB spec.
-			MinAvailable:               pdbSpec.MinAvailable,
-			MaxUnavailable:             pdbSpec.MaxUnavailable,
-			UnhealthyPodEvictionPolicy: pdbSpec.UnhealthyPodEvictionPolicy,
 		},
 	}
 
 	k8sutils.SetOwnerForObject(pdb, dataplane)
 
-	return pdb, nil
+	return pdb
 }
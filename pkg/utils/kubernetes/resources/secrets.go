@@ -24,6 +24,9 @@ type ControlPlaneOrDataPlaneOrKonnectExtension interface {
 	*operatorv1beta1.ControlPlane | *operatorv1beta1.DataPlane | *konnectv1alpha1.KonnectExtension
 }
 
+// placeholderTLSData is the value used for certificate data in rendered Secrets.
+const placeholderTLSData = "<placeholder: signed by the cluster CA at runtime>"
+
 // SecretOpt is an option function for a Secret.
 type SecretOpt func(*corev1.Secret)
 
@@ -37,6 +40,19 @@ func SecretWithLabel(k, v string) func(s *corev1.Secret) {
 	}
 }
 
+// SecretWithPlaceholderTLSData fills a TLS Secret with placeholder certificate
+// data. It is used when Secrets are rendered offline and there is no cluster CA
+// to sign the certificate with.
+func SecretWithPlaceholderTLSData() func(s *corev1.Secret) {
+	return func(s *corev1.Secret) {
+		s.Data = map[string][]byte{
+			"ca.crt":  []byte(placeholderTLSData),
+			"tls.crt": []byte(placeholderTLSData),
+			"tls.key": []byte(placeholderTLSData),
+		}
+	}
+}
+
 // WithAnnotation adds an annotation to an object.
 func WithAnnotation[T client.Object](k, v string) func(d T) {
 	return func(obj T) {
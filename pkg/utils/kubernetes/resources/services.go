@@ -51,7 +51,10 @@ func GenerateNewIngressServiceForDataPlane(dataplane *operatorv1beta1.DataPlane,
 	}
 
 	setDataPlaneIngressServiceExternalTrafficPolicy(dataplane, svc)
+	setDataPlaneIngressServiceTrafficDistribution(dataplane, svc)
+	setDataPlaneIngressServiceStaticIPs(dataplane, svc)
 	LabelObjectAsDataPlaneManaged(svc)
+	PropagateMetadata(dataplane, svc)
 
 	for _, opt := range opts {
 		opt(svc)
@@ -71,6 +74,45 @@ func GenerateNewIngressServiceForDataPlane(dataplane *operatorv1beta1.DataPlane,
 	return svc, nil
 }
 
+// GenerateNewZoneIngressServiceForDataPlane is a helper to generate the dataplane
+// ingress service targeting only the dataplane Pods running in the provided zone.
+// The Pods are selected using the consts.DataPlaneZoneLabel label.
+func GenerateNewZoneIngressServiceForDataPlane(dataplane *operatorv1beta1.DataPlane, zone string, opts ...ServiceOpt) (*corev1.Service, error) {
+	svc, err := GenerateNewIngressServiceForDataPlane(dataplane, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The name of the DataPlane's ingress Service cannot be reused.
+	svc.Name = ""
+	svc.GenerateName = k8sutils.TrimGenerateName(
+		fmt.Sprintf("%s-ingress-%s-%s-", consts.DataPlanePrefix, dataplane.Name, sanitizeZoneForName(zone)),
+	)
+	// Static addresses can only be bound to a single Service.
+	svc.Spec.LoadBalancerIP = ""
+	svc.Spec.ExternalIPs = nil
+	svc.Labels[consts.DataPlaneServiceTypeLabel] = string(consts.DataPlaneZoneIngressServiceLabelValue)
+	svc.Labels[consts.DataPlaneZoneLabel] = zone
+	svc.Spec.Selector[consts.DataPlaneZoneLabel] = zone
+
+	return svc, nil
+}
+
+// sanitizeZoneForName returns the provided zone name with all the characters
+// which are not allowed in Service names replaced with "-".
+func sanitizeZoneForName(zone string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, zone)
+}
+
 // DefaultDataPlaneIngressServiceType is the default Service type for a DataPlane.
 const DefaultDataPlaneIngressServiceType = corev1.ServiceTypeLoadBalancer
 
@@ -112,6 +154,57 @@ func setDataPlaneIngressServiceExternalTrafficPolicy(
 	svc.Spec.ExternalTrafficPolicy = dataplane.Spec.Network.Services.Ingress.ExternalTrafficPolicy
 }
 
+func setDataPlaneIngressServiceTrafficDistribution(
+	dataplane *operatorv1beta1.DataPlane,
+	svc *corev1.Service,
+) {
+	if dataplane == nil {
+		return
+	}
+	trafficDistribution, ok := dataplane.Annotations[consts.AnnotationDataPlaneIngressServiceTrafficDistribution]
+	if !ok || trafficDistribution == "" {
+		return
+	}
+
+	svc.Spec.TrafficDistribution = &trafficDistribution
+}
+
+func setDataPlaneIngressServiceStaticIPs(
+	dataplane *operatorv1beta1.DataPlane,
+	svc *corev1.Service,
+) {
+	if dataplane == nil {
+		return
+	}
+	ips := GetDataPlaneIngressServiceStaticIPs(dataplane)
+	if len(ips) == 0 {
+		return
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		svc.Spec.LoadBalancerIP = ips[0]
+		return
+	}
+	svc.Spec.ExternalIPs = ips
+}
+
+// GetDataPlaneIngressServiceStaticIPs returns the static IP addresses requested
+// for the DataPlane's ingress Service through the
+// consts.AnnotationDataPlaneIngressServiceStaticIPs annotation.
+func GetDataPlaneIngressServiceStaticIPs(dataplane *operatorv1beta1.DataPlane) []string {
+	v, ok := dataplane.Annotations[consts.AnnotationDataPlaneIngressServiceStaticIPs]
+	if !ok || v == "" {
+		return nil
+	}
+	var ips []string
+	for _, ip := range strings.Split(v, ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
 // ServiceOpt is an option function for a Service.
 type ServiceOpt func(*corev1.Service)
 
@@ -203,6 +296,7 @@ func GenerateNewAdminServiceForDataPlane(dataplane *operatorv1beta1.DataPlane, o
 		},
 	}
 	LabelObjectAsDataPlaneManaged(adminService)
+	PropagateMetadata(dataplane, adminService)
 
 	for _, opt := range opts {
 		opt(adminService)
@@ -269,6 +363,7 @@ func GenerateNewAdmissionWebhookServiceForControlPlane(cp *operatorv1beta1.Contr
 	}
 	pkgapiscorev1.SetDefaults_Service(svc)
 	LabelObjectAsControlPlaneManaged(svc)
+	PropagateMetadata(cp, svc)
 	k8sutils.SetOwnerForObject(svc, cp)
 
 	return svc, nil
@@ -287,6 +287,68 @@ func TestGenerateNewIngressServiceForDataPlane(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "setting traffic distribution",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dp-1",
+					Namespace: "default",
+					UID:       types.UID("1234"),
+					Annotations: map[string]string{
+						"gateway-operator.konghq.com/ingress-service-traffic-distribution": "PreferClose",
+					},
+				},
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "gateway.konghq.com/v1beta1",
+					Kind:       "DataPlane",
+				},
+			},
+			expectedSvc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "dataplane-ingress-dp-1-",
+					Namespace:    "default",
+					Labels: map[string]string{
+						"app": "dp-1",
+						"gateway-operator.konghq.com/dataplane-service-type": "ingress",
+						"gateway-operator.konghq.com/managed-by":             "dataplane",
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "gateway.konghq.com/v1beta1",
+							Kind:       "DataPlane",
+							Name:       "dp-1",
+							UID:        "1234",
+							Controller: lo.ToPtr(true),
+						},
+					},
+					Finalizers: []string{
+						"gateway-operator.konghq.com/wait-for-owner",
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+					Ports: []corev1.ServicePort{
+						{
+							Name:       "http",
+							Protocol:   corev1.ProtocolTCP,
+							Port:       80,
+							TargetPort: intstr.FromInt(8000),
+						},
+						{
+							Name:       "https",
+							Protocol:   corev1.ProtocolTCP,
+							Port:       443,
+							TargetPort: intstr.FromInt(8443),
+						},
+					},
+					Selector: map[string]string{
+						"app": "dp-1",
+					},
+					TrafficDistribution: lo.ToPtr(corev1.ServiceTrafficDistributionPreferClose),
+				},
+			},
+			expectedErr: nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -297,3 +359,112 @@ func TestGenerateNewIngressServiceForDataPlane(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateNewZoneIngressServiceForDataPlane(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp-1",
+			Namespace: "default",
+			UID:       types.UID("1234"),
+		},
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "gateway.konghq.com/v1beta1",
+			Kind:       "DataPlane",
+		},
+		Spec: operatorv1beta1.DataPlaneSpec{
+			DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+				Network: operatorv1beta1.DataPlaneNetworkOptions{
+					Services: &operatorv1beta1.DataPlaneServices{
+						Ingress: &operatorv1beta1.DataPlaneServiceOptions{
+							ServiceOptions: operatorv1beta1.ServiceOptions{
+								Name: lo.ToPtr("custom-ingress"),
+								Type: corev1.ServiceTypeLoadBalancer,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	svc, err := GenerateNewZoneIngressServiceForDataPlane(dataplane, "Europe_West1.B")
+	require.NoError(t, err)
+	require.Empty(t, svc.Name, "ingress Service name set in DataPlane spec should not be used")
+	require.Equal(t, "dataplane-ingress-dp-1-europe-west1-b-", svc.GenerateName)
+	require.Equal(t, map[string]string{
+		"app": "dp-1",
+		"gateway-operator.konghq.com/dataplane-service-type": "ingress-zone",
+		"gateway-operator.konghq.com/managed-by":             "dataplane",
+		"gateway-operator.konghq.com/zone":                   "Europe_West1.B",
+	}, svc.Labels)
+	require.Equal(t, map[string]string{
+		"app":                              "dp-1",
+		"gateway-operator.konghq.com/zone": "Europe_West1.B",
+	}, svc.Spec.Selector)
+}
+
+func TestGenerateNewIngressServiceForDataPlaneStaticIPs(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		serviceType            corev1.ServiceType
+		staticIPs              string
+		expectedLoadBalancerIP string
+		expectedExternalIPs    []string
+	}{
+		{
+			name:        "no static IPs",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+		},
+		{
+			name:                   "LoadBalancer Service uses the first IP as loadBalancerIP",
+			serviceType:            corev1.ServiceTypeLoadBalancer,
+			staticIPs:              "203.0.113.10, 203.0.113.11",
+			expectedLoadBalancerIP: "203.0.113.10",
+		},
+		{
+			name:                "ClusterIP Service uses externalIPs",
+			serviceType:         corev1.ServiceTypeClusterIP,
+			staticIPs:           "203.0.113.10,203.0.113.11",
+			expectedExternalIPs: []string{"203.0.113.10", "203.0.113.11"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dataplane := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dp-1",
+					Namespace: "default",
+				},
+				Spec: operatorv1beta1.DataPlaneSpec{
+					DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+						Network: operatorv1beta1.DataPlaneNetworkOptions{
+							Services: &operatorv1beta1.DataPlaneServices{
+								Ingress: &operatorv1beta1.DataPlaneServiceOptions{
+									ServiceOptions: operatorv1beta1.ServiceOptions{
+										Type: tc.serviceType,
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			if tc.staticIPs != "" {
+				dataplane.Annotations = map[string]string{
+					"gateway-operator.konghq.com/ingress-service-static-ips": tc.staticIPs,
+				}
+			}
+
+			svc, err := GenerateNewIngressServiceForDataPlane(dataplane)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedLoadBalancerIP, svc.Spec.LoadBalancerIP)
+			require.Equal(t, tc.expectedExternalIPs, svc.Spec.ExternalIPs)
+
+			zoneSvc, err := GenerateNewZoneIngressServiceForDataPlane(dataplane, "zone-a")
+			require.NoError(t, err)
+			require.Empty(t, zoneSvc.Spec.LoadBalancerIP, "static IPs should not be set on per-zone Services")
+			require.Empty(t, zoneSvc.Spec.ExternalIPs, "static IPs should not be set on per-zone Services")
+		})
+	}
+}
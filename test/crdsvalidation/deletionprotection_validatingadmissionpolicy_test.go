@@ -0,0 +1,98 @@
+package crdsvalidation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/deletionprotection"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	"github.com/kong/gateway-operator/test/envtest"
+	"github.com/kong/gateway-operator/test/helpers/kustomize"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestDeletionProtectionValidatingAdmissionPolicy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = t.Context()
+		scheme  = scheme.Get()
+		cfg, ns = envtest.Setup(t, ctx, scheme)
+	)
+	cl, err := client.New(cfg, client.Options{Scheme: scheme})
+	require.NoError(t, err)
+
+	kustomize.Apply(ctx, t, cfg, KustomizePathValidatingPolicies)
+
+	createDataPlane := func(t *testing.T, annotations map[string]string, blocked bool) *operatorv1beta1.DataPlane {
+		dataplane := &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "dp-",
+				Namespace:    ns.Name,
+				Annotations:  annotations,
+			},
+			Spec: operatorv1beta1.DataPlaneSpec{
+				DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+					Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1beta1.DeploymentOptions{
+							PodTemplateSpec: &corev1.PodTemplateSpec{
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{{Name: "proxy", Image: "kong:3.9"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		require.NoError(t, cl.Create(ctx, dataplane))
+		if blocked {
+			dataplane.Status.Conditions = []metav1.Condition{{
+				Type:               string(deletionprotection.ConditionType),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(deletionprotection.ReasonInUse),
+				Message:            "DataPlane " + dataplane.Name + " serves traffic",
+				LastTransitionTime: metav1.Now(),
+			}}
+			require.NoError(t, cl.Status().Update(ctx, dataplane))
+		}
+		return dataplane
+	}
+
+	t.Run("deleting a protected DataPlane in use fails", func(t *testing.T) {
+		dataplane := createDataPlane(t, map[string]string{consts.AnnotationDeletionProtection: "true"}, true)
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			err := cl.Delete(ctx, dataplane)
+			if !assert.Error(c, err) {
+				return
+			}
+			assert.Contains(c, err.Error(), "DataPlane "+ns.Name+"/"+dataplane.Name+" is protected from deletion")
+			assert.Contains(c, err.Error(), "DataPlane "+dataplane.Name+" serves traffic")
+		}, sharedEventuallyConfig.Timeout, sharedEventuallyConfig.Period)
+	})
+
+	t.Run("deleting a protected DataPlane in use with confirmation succeeds", func(t *testing.T) {
+		dataplane := createDataPlane(t, map[string]string{
+			consts.AnnotationDeletionProtection: "true",
+			consts.AnnotationConfirmDeletion:    "true",
+		}, true)
+		require.NoError(t, cl.Delete(ctx, dataplane))
+	})
+
+	t.Run("deleting a protected DataPlane not in use succeeds", func(t *testing.T) {
+		dataplane := createDataPlane(t, map[string]string{consts.AnnotationDeletionProtection: "true"}, false)
+		require.NoError(t, cl.Delete(ctx, dataplane))
+	})
+
+	t.Run("deleting an unprotected DataPlane succeeds", func(t *testing.T) {
+		dataplane := createDataPlane(t, nil, true)
+		require.NoError(t, cl.Delete(ctx, dataplane))
+	})
+}
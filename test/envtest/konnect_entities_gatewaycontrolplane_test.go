@@ -203,6 +203,24 @@ var konnectGatewayControlPlaneTestCases = []konnectEntityReconcilerTestCase{
 					nil,
 				)
 
+			sdk.ControlPlaneGroupSDK.EXPECT().
+				GetControlPlanesIDGroupMemberships(
+					mock.Anything,
+					mock.MatchedBy(func(r sdkkonnectops.GetControlPlanesIDGroupMembershipsRequest) bool {
+						return r.ID == "12346"
+					}),
+				).
+				Return(
+					&sdkkonnectops.GetControlPlanesIDGroupMembershipsResponse{
+						ListGroupMemberships: &sdkkonnectcomp.ListGroupMemberships{
+							Data: []sdkkonnectcomp.ControlPlane{
+								{ID: "12345"},
+							},
+						},
+					},
+					nil,
+				).Maybe()
+
 			sdk.ControlPlaneSDK.EXPECT().
 				UpdateControlPlane(
 					mock.Anything,
@@ -348,6 +366,22 @@ var konnectGatewayControlPlaneTestCases = []konnectEntityReconcilerTestCase{
 					errors.New("some error"),
 				)
 
+			sdk.ControlPlaneGroupSDK.EXPECT().
+				GetControlPlanesIDGroupMemberships(
+					mock.Anything,
+					mock.MatchedBy(func(r sdkkonnectops.GetControlPlanesIDGroupMembershipsRequest) bool {
+						return r.ID == "123467"
+					}),
+				).
+				Return(
+					&sdkkonnectops.GetControlPlanesIDGroupMembershipsResponse{
+						ListGroupMemberships: &sdkkonnectcomp.ListGroupMemberships{
+							Data: []sdkkonnectcomp.ControlPlane{},
+						},
+					},
+					nil,
+				).Maybe()
+
 			sdk.ControlPlaneSDK.EXPECT().
 				ListControlPlanes(
 					mock.Anything,
@@ -676,6 +710,24 @@ var konnectGatewayControlPlaneTestCases = []konnectEntityReconcilerTestCase{
 				).
 				Return(&sdkkonnectops.PutControlPlanesIDGroupMembershipsResponse{}, nil)
 
+			sdk.ControlPlaneGroupSDK.EXPECT().
+				GetControlPlanesIDGroupMemberships(
+					mock.Anything,
+					mock.MatchedBy(func(r sdkkonnectops.GetControlPlanesIDGroupMembershipsRequest) bool {
+						return r.ID == "group-123456"
+					}),
+				).
+				Return(
+					&sdkkonnectops.GetControlPlanesIDGroupMembershipsResponse{
+						ListGroupMemberships: &sdkkonnectcomp.ListGroupMemberships{
+							Data: []sdkkonnectcomp.ControlPlane{
+								{ID: "123456"},
+							},
+						},
+					},
+					nil,
+				).Maybe()
+
 			sdk.ControlPlaneSDK.EXPECT().
 				UpdateControlPlane(
 					mock.Anything,
@@ -762,6 +814,22 @@ var konnectGatewayControlPlaneTestCases = []konnectEntityReconcilerTestCase{
 				).
 				Return(&sdkkonnectops.PutControlPlanesIDGroupMembershipsResponse{}, nil)
 
+			sdk.ControlPlaneGroupSDK.EXPECT().
+				GetControlPlanesIDGroupMemberships(
+					mock.Anything,
+					mock.MatchedBy(func(r sdkkonnectops.GetControlPlanesIDGroupMembershipsRequest) bool {
+						return r.ID == "cpg-id"
+					}),
+				).
+				Return(
+					&sdkkonnectops.GetControlPlanesIDGroupMembershipsResponse{
+						ListGroupMemberships: &sdkkonnectcomp.ListGroupMemberships{
+							Data: []sdkkonnectcomp.ControlPlane{},
+						},
+					},
+					nil,
+				).Maybe()
+
 			sdk.ControlPlaneSDK.EXPECT().
 				ListControlPlanes(
 					mock.Anything,
@@ -15,8 +15,10 @@ import (
 
 	"github.com/kong/gateway-operator/controller/konnect"
 	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+	"github.com/kong/gateway-operator/internal/metrics"
 	"github.com/kong/gateway-operator/modules/manager/logging"
 	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 	"github.com/kong/gateway-operator/test/helpers/deploy"
 
@@ -34,7 +36,9 @@ func TestKonnectAPIAuthConfiguration(t *testing.T) {
 	factory := sdkmocks.NewMockSDKFactory(t)
 	sdk := factory.SDK
 	StartReconcilers(ctx, t, mgr, logs,
-		konnect.NewKonnectAPIAuthConfigurationReconciler(factory, logging.DevelopmentMode, mgr.GetClient()),
+		konnect.NewKonnectAPIAuthConfigurationReconciler(
+			factory, logging.DevelopmentMode, mgr.GetClient(), consts.DefaultKonnectAPITokenExpiryWarningThreshold, &metrics.MockRecorder{},
+		),
 	)
 
 	t.Log("Setting up clients")
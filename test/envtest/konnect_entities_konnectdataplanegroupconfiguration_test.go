@@ -91,6 +91,20 @@ func TestKonnectDataPlaneGroupConfiguration(t *testing.T) {
 			},
 		}, nil)
 
+		t.Log("Setting up SDK expectations on polling the provisioning state")
+		sdk.CloudGatewaysSDK.EXPECT().GetConfiguration(mock.Anything, id).Return(&sdkkonnectops.GetConfigurationResponse{
+			ConfigurationManifest: &sdkkonnectcomp.ConfigurationManifest{
+				ID:             id,
+				ControlPlaneID: cp.GetKonnectID(),
+				DataplaneGroups: []sdkkonnectcomp.ConfigurationDataPlaneGroup{
+					{
+						ID:    "dpg-group-" + uuid.New().String(),
+						State: sdkkonnectcomp.StateReady,
+					},
+				},
+			},
+		}, nil)
+
 		sdk.CloudGatewaysSDK.EXPECT().GetNetwork(
 			mock.Anything,
 			networkID,
@@ -177,6 +191,20 @@ func TestKonnectDataPlaneGroupConfiguration(t *testing.T) {
 			},
 		}, nil)
 
+		t.Log("Setting up SDK expectations on polling the provisioning state")
+		sdk.CloudGatewaysSDK.EXPECT().GetConfiguration(mock.Anything, id).Return(&sdkkonnectops.GetConfigurationResponse{
+			ConfigurationManifest: &sdkkonnectcomp.ConfigurationManifest{
+				ID:             id,
+				ControlPlaneID: cp.GetKonnectID(),
+				DataplaneGroups: []sdkkonnectcomp.ConfigurationDataPlaneGroup{
+					{
+						ID:    "dpg-group-" + uuid.New().String(),
+						State: sdkkonnectcomp.StateReady,
+					},
+				},
+			},
+		}, nil)
+
 		n := deploy.KonnectCloudGatewayNetworkWithProgrammed(t, ctx, clientNamespaced, apiAuth,
 			func(obj client.Object) {
 				n := obj.(*konnectv1alpha1.KonnectCloudGatewayNetwork)
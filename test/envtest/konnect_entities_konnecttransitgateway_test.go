@@ -81,6 +81,20 @@ func TestKonnectCloudGatewayTransitGateway(t *testing.T) {
 			nil,
 		)
 
+		t.Log("Setting up SDK expectations on polling the provisioning state and updating")
+		sdk.CloudGatewaysSDK.EXPECT().GetTransitGateway(mock.Anything, networkID, id).Return(
+			&sdkkonnectops.GetTransitGatewayResponse{
+				TransitGatewayResponse: &sdkkonnectcomp.TransitGatewayResponse{
+					Type: sdkkonnectcomp.TransitGatewayResponseTypeAwsTransitGatewayResponse,
+					AwsTransitGatewayResponse: &sdkkonnectcomp.AwsTransitGatewayResponse{
+						Name:  transitGatewayName,
+						ID:    id,
+						State: sdkkonnectcomp.TransitGatewayStateReady,
+					},
+				},
+			}, nil,
+		)
+
 		t.Log("Creating KonnectAPIAuthConfiguration")
 		apiAuth := deploy.KonnectAPIAuthConfigurationWithProgrammed(t, ctx, clientNamespaced)
 
@@ -119,25 +133,13 @@ func TestKonnectCloudGatewayTransitGateway(t *testing.T) {
 		}
 		require.NoError(t, clientNamespaced.Create(ctx, tg))
 
-		t.Log("Waiting for KonnectCloudGatewayTransitGateway to be Programmed and get a Konnect ID")
+		t.Log("Waiting for KonnectCloudGatewayTransitGateway to be provisioned, Programmed and get a Konnect ID")
 		watchFor(t, ctx, w, apiwatch.Modified, func(tg *konnectv1alpha1.KonnectCloudGatewayTransitGateway) bool {
-			return tg.GetKonnectID() == id && conditionsContainProgrammed(tg.GetConditions(), metav1.ConditionTrue)
+			return tg.GetKonnectID() == id &&
+				tg.Status.State == sdkkonnectcomp.TransitGatewayStateReady &&
+				conditionsContainProgrammed(tg.GetConditions(), metav1.ConditionTrue)
 		}, "Did not see KonnectCloudGatewayTransitGateway get Programmed and Konnect ID set.")
 
-		t.Log("Setting up SDK expctations on updating/get")
-		sdk.CloudGatewaysSDK.EXPECT().GetTransitGateway(mock.Anything, networkID, id).Return(
-			&sdkkonnectops.GetTransitGatewayResponse{
-				TransitGatewayResponse: &sdkkonnectcomp.TransitGatewayResponse{
-					Type: sdkkonnectcomp.TransitGatewayResponseTypeAwsTransitGatewayResponse,
-					AwsTransitGatewayResponse: &sdkkonnectcomp.AwsTransitGatewayResponse{
-						Name:  transitGatewayName,
-						ID:    id,
-						State: sdkkonnectcomp.TransitGatewayStateReady,
-					},
-				},
-			}, nil,
-		)
-
 		t.Log("Updating KonnectCloudGatewayTransitGateway")
 		require.NoError(t, clientNamespaced.Get(ctx, client.ObjectKeyFromObject(tg), tg))
 		oldTg := tg.DeepCopy()